@@ -0,0 +1,31 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// SetupClientRoutes configures client routes. Clients are managed by the
+// registration service.
+func SetupClientRoutes(router *gin.RouterGroup) {
+	registrationServiceURL := getEnv(registrationServiceURLEnvKey, defaultRegistrationServiceURL)
+
+	// Client CRUD operations
+	router.POST("", proxyToService(registrationServiceURL, "/api/v1/clients"))
+	router.GET("", proxyToService(registrationServiceURL, "/api/v1/clients"))
+	router.GET("/search", proxyToService(registrationServiceURL, "/api/v1/clients/search"))
+	router.GET("/:id", proxyToService(registrationServiceURL, "/api/v1/clients/:id"))
+	router.PUT("/:id", proxyToService(registrationServiceURL, "/api/v1/clients/:id"))
+	router.DELETE("/:id", proxyToService(registrationServiceURL, "/api/v1/clients/:id"))
+
+	// User account link, duplicates and KYC
+	router.PUT("/:id/user", proxyToService(registrationServiceURL, "/api/v1/clients/:id/user"))
+	router.DELETE("/:id/user", proxyToService(registrationServiceURL, "/api/v1/clients/:id/user"))
+	router.GET("/:id/duplicates", proxyToService(registrationServiceURL, "/api/v1/clients/:id/duplicates"))
+	router.POST("/:id/merge", proxyToService(registrationServiceURL, "/api/v1/clients/:id/merge"))
+	router.GET("/:id/kyc", proxyToService(registrationServiceURL, "/api/v1/clients/:id/kyc"))
+
+	// Compliance obligations and calendar
+	router.GET("/:id/compliance", proxyToService(registrationServiceURL, "/api/v1/clients/:id/compliance"))
+	router.GET("/:id/compliance/calendar.ics", proxyToService(registrationServiceURL, "/api/v1/clients/:id/compliance/calendar.ics"))
+	router.GET("/:id/compliance/feed", proxyToService(registrationServiceURL, "/api/v1/clients/:id/compliance/feed"))
+}
@@ -59,4 +59,7 @@ func SetupCommissionRoutes(router *gin.RouterGroup) {
 	router.GET("/statistics", proxyToService(commissionServiceURL, "/api/v1/commissions/statistics"))
 	router.GET("/statistics/pending", proxyToService(commissionServiceURL, "/api/v1/commissions/statistics/pending"))
 	router.GET("/statistics/paid", proxyToService(commissionServiceURL, "/api/v1/commissions/statistics/paid"))
+
+	// Overrides earned on sub-agent tenants' commissions
+	router.GET("/overrides", proxyToService(commissionServiceURL, "/api/v1/commissions/overrides"))
 }
@@ -55,6 +55,20 @@ func SetupIntegrationRoutes(router *gin.RouterGroup) {
 		cipc.POST("/validate", proxyToService(integrationServiceURL, "/api/v1/integration/cipc/validate"))
 		cipc.GET("/company/:registration_number", proxyToService(integrationServiceURL, "/api/v1/integration/cipc/company/:registration_number"))
 		cipc.GET("/status/:registration_number", proxyToService(integrationServiceURL, "/api/v1/integration/cipc/status/:registration_number"))
+		cipc.POST("/name-reservations", proxyToService(integrationServiceURL, "/api/v1/integration/cipc/name-reservations"))
+		cipc.GET("/name-reservations/:reference", proxyToService(integrationServiceURL, "/api/v1/integration/cipc/name-reservations/:reference"))
+	}
+
+	// Companies registries by jurisdiction, such as CIPC (ZA) and DCIP (ZW)
+	registries := router.Group("/registries")
+	{
+		registries.GET("", proxyToService(integrationServiceURL, "/api/v1/integration/registries"))
+		registries.GET("/:jurisdiction/search", proxyToService(integrationServiceURL, "/api/v1/integration/registries/:jurisdiction/search"))
+		registries.POST("/:jurisdiction/validate", proxyToService(integrationServiceURL, "/api/v1/integration/registries/:jurisdiction/validate"))
+		registries.GET("/:jurisdiction/company/:registration_number", proxyToService(integrationServiceURL, "/api/v1/integration/registries/:jurisdiction/company/:registration_number"))
+		registries.GET("/:jurisdiction/status/:registration_number", proxyToService(integrationServiceURL, "/api/v1/integration/registries/:jurisdiction/status/:registration_number"))
+		registries.POST("/:jurisdiction/name-reservations", proxyToService(integrationServiceURL, "/api/v1/integration/registries/:jurisdiction/name-reservations"))
+		registries.GET("/:jurisdiction/name-reservations/:reference", proxyToService(integrationServiceURL, "/api/v1/integration/registries/:jurisdiction/name-reservations/:reference"))
 	}
 
 	// SARS (South African Revenue Service) integration
@@ -16,15 +16,24 @@ func SetupRegistrationRoutes(router *gin.RouterGroup) {
 	// Registration CRUD operations
 	router.POST("", proxyToService(registrationServiceURL, "/api/v1/registrations"))
 	router.GET("", proxyToService(registrationServiceURL, "/api/v1/registrations"))
+	router.GET("/search", proxyToService(registrationServiceURL, "/api/v1/registrations/search"))
 	router.GET("/:id", proxyToService(registrationServiceURL, "/api/v1/registrations/:id"))
 	router.PUT("/:id", proxyToService(registrationServiceURL, "/api/v1/registrations/:id"))
 	router.DELETE("/:id", proxyToService(registrationServiceURL, "/api/v1/registrations/:id"))
 
 	// Registration workflow actions
 	router.POST("/:id/submit", proxyToService(registrationServiceURL, "/api/v1/registrations/:id/submit"))
+	router.POST("/:id/review", proxyToService(registrationServiceURL, "/api/v1/registrations/:id/review"))
 	router.POST("/:id/approve", proxyToService(registrationServiceURL, "/api/v1/registrations/:id/approve"))
 	router.POST("/:id/reject", proxyToService(registrationServiceURL, "/api/v1/registrations/:id/reject"))
+	router.POST("/:id/complete", proxyToService(registrationServiceURL, "/api/v1/registrations/:id/complete"))
 	router.POST("/:id/cancel", proxyToService(registrationServiceURL, "/api/v1/registrations/:id/cancel"))
+	router.POST("/:id/invoice-paid", proxyToService(registrationServiceURL, "/api/v1/registrations/:id/invoice-paid"))
+	router.GET("/workflows/:type", proxyToService(registrationServiceURL, "/api/v1/registrations/workflows/:type"))
+
+	// Form schemas for registration form_data
+	router.GET("/form-schemas", proxyToService(registrationServiceURL, "/api/v1/registrations/form-schemas"))
+	router.GET("/form-schemas/:jurisdiction/:type", proxyToService(registrationServiceURL, "/api/v1/registrations/form-schemas/:jurisdiction/:type"))
 
 	// Document verification
 	router.POST("/:id/verify-documents", proxyToService(registrationServiceURL, "/api/v1/registrations/:id/verify-documents"))
@@ -33,11 +42,118 @@ func SetupRegistrationRoutes(router *gin.RouterGroup) {
 	router.POST("/:id/cipc-search", proxyToService(registrationServiceURL, "/api/v1/registrations/:id/cipc-search"))
 	router.POST("/:id/cipc-verify", proxyToService(registrationServiceURL, "/api/v1/registrations/:id/cipc-verify"))
 
+	// CIPC name reservation
+	router.GET("/:id/name-reservation", proxyToService(registrationServiceURL, "/api/v1/registrations/:id/name-reservation"))
+	router.PUT("/:id/name-reservation", proxyToService(registrationServiceURL, "/api/v1/registrations/:id/name-reservation"))
+	router.POST("/:id/name-reservation/submit", proxyToService(registrationServiceURL, "/api/v1/registrations/:id/name-reservation/submit"))
+	router.POST("/:id/name-reservation/refresh", proxyToService(registrationServiceURL, "/api/v1/registrations/:id/name-reservation/refresh"))
+	router.GET("/:id/name-reservations", proxyToService(registrationServiceURL, "/api/v1/registrations/:id/name-reservations"))
+
+	// Register of directors, shareholders and beneficial owners
+	router.GET("/:id/register", proxyToService(registrationServiceURL, "/api/v1/registrations/:id/register"))
+	router.GET("/:id/register/beneficial-ownership.csv", proxyToService(registrationServiceURL, "/api/v1/registrations/:id/register/beneficial-ownership.csv"))
+	router.POST("/:id/register/entries", proxyToService(registrationServiceURL, "/api/v1/registrations/:id/register/entries"))
+	router.GET("/:id/register/entries/:entry_id", proxyToService(registrationServiceURL, "/api/v1/registrations/:id/register/entries/:entry_id"))
+	router.PUT("/:id/register/entries/:entry_id", proxyToService(registrationServiceURL, "/api/v1/registrations/:id/register/entries/:entry_id"))
+	router.DELETE("/:id/register/entries/:entry_id", proxyToService(registrationServiceURL, "/api/v1/registrations/:id/register/entries/:entry_id"))
+	router.GET("/:id/register/entries/:entry_id/history", proxyToService(registrationServiceURL, "/api/v1/registrations/:id/register/entries/:entry_id/history"))
+	router.POST("/:id/register/entries/:entry_id/resign", proxyToService(registrationServiceURL, "/api/v1/registrations/:id/register/entries/:entry_id/resign"))
+
+	// Quote, locked at submission
+	router.GET("/:id/quote", proxyToService(registrationServiceURL, "/api/v1/registrations/:id/quote"))
+	router.POST("/:id/quote", proxyToService(registrationServiceURL, "/api/v1/registrations/:id/quote"))
+
 	// Registration history
 	router.GET("/:id/history", proxyToService(registrationServiceURL, "/api/v1/registrations/:id/history"))
 	router.GET("/:id/audit", proxyToService(registrationServiceURL, "/api/v1/registrations/:id/audit"))
 
+	// Requirements checklist and completeness
+	router.GET("/:id/checklist", proxyToService(registrationServiceURL, "/api/v1/registrations/:id/checklist"))
+
+	// Reviewer assignment and work queues
+	router.GET("/my-queue", proxyToService(registrationServiceURL, "/api/v1/registrations/my-queue"))
+	router.GET("/overdue", proxyToService(registrationServiceURL, "/api/v1/registrations/overdue"))
+	router.POST("/:id/assign", proxyToService(registrationServiceURL, "/api/v1/registrations/:id/assign"))
+	router.GET("/:id/assignments", proxyToService(registrationServiceURL, "/api/v1/registrations/:id/assignments"))
+
 	// Statistics and reports
 	router.GET("/statistics", proxyToService(registrationServiceURL, "/api/v1/registrations/statistics"))
 	router.GET("/export", proxyToService(registrationServiceURL, "/api/v1/registrations/export"))
 }
+
+// SetupReviewQueueRoutes configures review queue management routes
+func SetupReviewQueueRoutes(router *gin.RouterGroup) {
+	registrationServiceURL := getEnv(registrationServiceURLEnvKey, defaultRegistrationServiceURL)
+
+	router.POST("", proxyToService(registrationServiceURL, "/api/v1/review-queues"))
+	router.GET("", proxyToService(registrationServiceURL, "/api/v1/review-queues"))
+	router.GET("/:id", proxyToService(registrationServiceURL, "/api/v1/review-queues/:id"))
+	router.PUT("/:id", proxyToService(registrationServiceURL, "/api/v1/review-queues/:id"))
+	router.DELETE("/:id", proxyToService(registrationServiceURL, "/api/v1/review-queues/:id"))
+
+	// Queue membership
+	router.PUT("/:id/members/:user_id", proxyToService(registrationServiceURL, "/api/v1/review-queues/:id/members/:user_id"))
+	router.DELETE("/:id/members/:user_id", proxyToService(registrationServiceURL, "/api/v1/review-queues/:id/members/:user_id"))
+}
+
+// SetupSLATargetRoutes configures registration SLA target routes
+func SetupSLATargetRoutes(router *gin.RouterGroup) {
+	registrationServiceURL := getEnv(registrationServiceURLEnvKey, defaultRegistrationServiceURL)
+
+	router.GET("", proxyToService(registrationServiceURL, "/api/v1/sla-targets"))
+	router.PUT("", proxyToService(registrationServiceURL, "/api/v1/sla-targets"))
+	router.DELETE("/:id", proxyToService(registrationServiceURL, "/api/v1/sla-targets/:id"))
+}
+
+// SetupRegistrationImportRoutes configures bulk registration import routes
+func SetupRegistrationImportRoutes(router *gin.RouterGroup) {
+	registrationServiceURL := getEnv(registrationServiceURLEnvKey, defaultRegistrationServiceURL)
+
+	router.POST("", proxyToService(registrationServiceURL, "/api/v1/registration-imports"))
+	router.GET("", proxyToService(registrationServiceURL, "/api/v1/registration-imports"))
+	router.GET("/:id", proxyToService(registrationServiceURL, "/api/v1/registration-imports/:id"))
+	router.GET("/:id/errors", proxyToService(registrationServiceURL, "/api/v1/registration-imports/:id/errors"))
+}
+
+// SetupImportMappingRoutes configures saved import column mapping routes
+func SetupImportMappingRoutes(router *gin.RouterGroup) {
+	registrationServiceURL := getEnv(registrationServiceURLEnvKey, defaultRegistrationServiceURL)
+
+	router.POST("", proxyToService(registrationServiceURL, "/api/v1/registration-import-mappings"))
+	router.GET("", proxyToService(registrationServiceURL, "/api/v1/registration-import-mappings"))
+	router.GET("/:id", proxyToService(registrationServiceURL, "/api/v1/registration-import-mappings/:id"))
+	router.PUT("/:id", proxyToService(registrationServiceURL, "/api/v1/registration-import-mappings/:id"))
+	router.DELETE("/:id", proxyToService(registrationServiceURL, "/api/v1/registration-import-mappings/:id"))
+}
+
+// SetupComplianceRoutes configures compliance obligation and calendar routes
+func SetupComplianceRoutes(router *gin.RouterGroup) {
+	registrationServiceURL := getEnv(registrationServiceURLEnvKey, defaultRegistrationServiceURL)
+
+	router.GET("/obligations", proxyToService(registrationServiceURL, "/api/v1/compliance/obligations"))
+	router.GET("/obligations/:id", proxyToService(registrationServiceURL, "/api/v1/compliance/obligations/:id"))
+	router.POST("/obligations/:id/complete", proxyToService(registrationServiceURL, "/api/v1/compliance/obligations/:id/complete"))
+	router.POST("/obligations/:id/waive", proxyToService(registrationServiceURL, "/api/v1/compliance/obligations/:id/waive"))
+	router.POST("/obligations/:id/reopen", proxyToService(registrationServiceURL, "/api/v1/compliance/obligations/:id/reopen"))
+	router.GET("/calendar.ics", proxyToService(registrationServiceURL, "/api/v1/compliance/calendar.ics"))
+	router.GET("/feed", proxyToService(registrationServiceURL, "/api/v1/compliance/feed"))
+	router.POST("/feed/rotate", proxyToService(registrationServiceURL, "/api/v1/compliance/feed/rotate"))
+}
+
+// SetupFeeScheduleRoutes configures fee schedule routes
+func SetupFeeScheduleRoutes(router *gin.RouterGroup) {
+	registrationServiceURL := getEnv(registrationServiceURLEnvKey, defaultRegistrationServiceURL)
+
+	router.POST("", proxyToService(registrationServiceURL, "/api/v1/fee-schedules"))
+	router.GET("", proxyToService(registrationServiceURL, "/api/v1/fee-schedules"))
+	router.GET("/:id", proxyToService(registrationServiceURL, "/api/v1/fee-schedules/:id"))
+	router.DELETE("/:id", proxyToService(registrationServiceURL, "/api/v1/fee-schedules/:id"))
+}
+
+// SetupPublicComplianceRoutes configures the signed compliance calendar feeds
+// calendar apps subscribe to without signing in
+func SetupPublicComplianceRoutes(router *gin.RouterGroup) {
+	registrationServiceURL := getEnv(registrationServiceURLEnvKey, defaultRegistrationServiceURL)
+
+	router.GET("/compliance/feeds/:token", proxyToService(registrationServiceURL, "/public/compliance/feeds/:token"))
+}
@@ -23,12 +23,58 @@ func SetupTenantRoutes(router *gin.RouterGroup) {
 
 	// Tenant provisioning
 	router.POST("/:id/provision", proxyToService(tenantServiceURL, "/api/v1/tenants/:id/provision"))
+	router.GET("/:id/provisioning", proxyToService(tenantServiceURL, "/api/v1/tenants/:id/provisioning"))
+
+	// Custom domains
+	router.GET("/:id/domain", proxyToService(tenantServiceURL, "/api/v1/tenants/:id/domain"))
+	router.PUT("/:id/domain", proxyToService(tenantServiceURL, "/api/v1/tenants/:id/domain"))
+	router.DELETE("/:id/domain", proxyToService(tenantServiceURL, "/api/v1/tenants/:id/domain"))
+	router.POST("/:id/domain/verify", proxyToService(tenantServiceURL, "/api/v1/tenants/:id/domain/verify"))
+
+	// Tenant lifecycle
+	router.POST("/:id/suspend", proxyToService(tenantServiceURL, "/api/v1/tenants/:id/suspend"))
+	router.POST("/:id/reactivate", proxyToService(tenantServiceURL, "/api/v1/tenants/:id/reactivate"))
+	router.GET("/:id/deletion-certificate", proxyToService(tenantServiceURL, "/api/v1/tenants/:id/deletion-certificate"))
+
+	// Full data exports
+	router.POST("/:id/exports", proxyToService(tenantServiceURL, "/api/v1/tenants/:id/exports"))
+	router.GET("/:id/exports", proxyToService(tenantServiceURL, "/api/v1/tenants/:id/exports"))
+	router.GET("/:id/exports/:exportId", proxyToService(tenantServiceURL, "/api/v1/tenants/:id/exports/:exportId"))
+
+	// Imports and clones
+	router.POST("/:id/imports", proxyToService(tenantServiceURL, "/api/v1/tenants/:id/imports"))
+	router.GET("/:id/imports/:importId", proxyToService(tenantServiceURL, "/api/v1/tenants/:id/imports/:importId"))
+	router.POST("/:id/clone", proxyToService(tenantServiceURL, "/api/v1/tenants/:id/clone"))
 
 	// Tenant settings
 	router.GET("/:id/settings", proxyToService(tenantServiceURL, "/api/v1/tenants/:id/settings"))
 	router.PUT("/:id/settings", proxyToService(tenantServiceURL, "/api/v1/tenants/:id/settings"))
+	router.GET("/:id/settings/:key", proxyToService(tenantServiceURL, "/api/v1/tenants/:id/settings/:key"))
+	router.PUT("/:id/settings/:key", proxyToService(tenantServiceURL, "/api/v1/tenants/:id/settings/:key"))
+	router.DELETE("/:id/settings/:key", proxyToService(tenantServiceURL, "/api/v1/tenants/:id/settings/:key"))
+
+	// White-label branding
+	router.GET("/:id/branding", proxyToService(tenantServiceURL, "/api/v1/tenants/:id/branding"))
+	router.PUT("/:id/branding", proxyToService(tenantServiceURL, "/api/v1/tenants/:id/branding"))
+	router.PUT("/:id/branding/logo", proxyToService(tenantServiceURL, "/api/v1/tenants/:id/branding/logo"))
+	router.GET("/:id/branding/logo", proxyToService(tenantServiceURL, "/api/v1/tenants/:id/branding/logo"))
+	router.DELETE("/:id/branding/logo", proxyToService(tenantServiceURL, "/api/v1/tenants/:id/branding/logo"))
+
+	// Master agent / sub-agent hierarchy
+	router.PUT("/:id/parent", proxyToService(tenantServiceURL, "/api/v1/tenants/:id/parent"))
+	router.DELETE("/:id/parent", proxyToService(tenantServiceURL, "/api/v1/tenants/:id/parent"))
+	router.GET("/:id/children", proxyToService(tenantServiceURL, "/api/v1/tenants/:id/children"))
+	router.GET("/:id/rollup", proxyToService(tenantServiceURL, "/api/v1/tenants/:id/rollup"))
 
 	// Tenant users
 	router.GET("/:id/users", proxyToService(tenantServiceURL, "/api/v1/tenants/:id/users"))
 	router.GET("/:id/statistics", proxyToService(tenantServiceURL, "/api/v1/tenants/:id/statistics"))
 }
+
+// SetupPublicTenantRoutes configures tenant assets served without tenant context,
+// such as logos linked from email
+func SetupPublicTenantRoutes(router *gin.RouterGroup) {
+	tenantServiceURL := getEnv(tenantServiceURLEnvKey, defaultTenantServiceURL)
+
+	router.GET("/tenants/:id/logo", proxyToService(tenantServiceURL, "/api/v1/tenants/:id/branding/logo"))
+}
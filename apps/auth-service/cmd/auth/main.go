@@ -6,10 +6,13 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"time"
 
 	"github.com/comply360/auth-service/internal/handlers"
 	"github.com/comply360/auth-service/internal/repository"
 	"github.com/comply360/auth-service/internal/services"
+	"github.com/comply360/shared/branding"
+	"github.com/comply360/shared/settings"
 	"github.com/gin-gonic/gin"
 	_ "github.com/lib/pq"
 	"github.com/redis/go-redis/v9"
@@ -21,6 +24,11 @@ func main() {
 	redisURL := getEnv("REDIS_URL", "redis://localhost:6379/0")
 	port := getEnv("AUTH_SERVICE_PORT", "8081")
 	jwtSecret := getEnv("JWT_SECRET", "changeme-change-this-in-production")
+	publicAPIURL := getEnv("API_URL", "http://localhost:8080")
+	settingsCacheTTL, err := time.ParseDuration(getEnv("SETTINGS_CACHE_TTL", "1m"))
+	if err != nil {
+		log.Fatalf("Invalid SETTINGS_CACHE_TTL: %v", err)
+	}
 
 	// Connect to PostgreSQL
 	db, err := sql.Open("postgres", dbURL)
@@ -50,8 +58,12 @@ func main() {
 	// Initialize repository
 	userRepo := repository.NewUserRepository(db)
 
+	// Tenant branding is read from tenant settings; auth never needs the encrypted ones
+	settingsClient := settings.NewClient(db, nil, settingsCacheTTL)
+	brands := branding.NewResolver(settingsClient, branding.Defaults(), publicAPIURL)
+
 	// Initialize services
-	authService := services.NewAuthService(userRepo, redisClient, jwtSecret)
+	authService := services.NewAuthService(userRepo, redisClient, jwtSecret, brands)
 
 	// Initialize handlers
 	authHandler := handlers.NewAuthHandler(authService)
@@ -79,6 +79,9 @@ func (h *AuthHandler) Login(c *gin.Context) {
 
 	authResponse, err := h.authService.Login(tenantID, &req)
 	if err != nil {
+		if respondTenantAccessError(c, err) {
+			return
+		}
 		c.JSON(http.StatusUnauthorized, errors.NewAPIError(
 			errors.ErrInvalidCredentials,
 			err.Error(),
@@ -105,6 +108,9 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 
 	authResponse, err := h.authService.RefreshToken(req.RefreshToken)
 	if err != nil {
+		if respondTenantAccessError(c, err) {
+			return
+		}
 		c.JSON(http.StatusUnauthorized, errors.NewAPIError(
 			errors.ErrInvalidToken,
 			err.Error(),
@@ -347,3 +353,16 @@ func getUserID(c *gin.Context) (uuid.UUID, error) {
 
 	return uuid.Nil, errors.NewAPIError(errors.ErrUnauthorized, "User ID not found")
 }
+
+// respondTenantAccessError writes a 403 for tenants that may not authenticate, reporting whether it did
+func respondTenantAccessError(c *gin.Context, err error) bool {
+	switch err.Error() {
+	case "tenant suspended":
+		c.JSON(http.StatusForbidden, errors.NewAPIError(errors.ErrTenantSuspended, "Tenant account is suspended"))
+	case "tenant deleted":
+		c.JSON(http.StatusForbidden, errors.NewAPIError(errors.ErrTenantNotFound, "Tenant account has been deleted"))
+	default:
+		return false
+	}
+	return true
+}
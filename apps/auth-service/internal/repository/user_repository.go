@@ -422,3 +422,20 @@ func (r *UserRepository) Delete(tenantID, userID uuid.UUID) error {
 
 	return nil
 }
+
+// GetTenantStatus retrieves the lifecycle status of a tenant
+func (r *UserRepository) GetTenantStatus(tenantID uuid.UUID) (string, error) {
+	var status string
+
+	query := `SELECT status FROM public.tenants WHERE id = $1`
+
+	err := r.db.QueryRow(query, tenantID).Scan(&status)
+	if err == sql.ErrNoRows {
+		return "", fmt.Errorf("tenant not found")
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return status, nil
+}
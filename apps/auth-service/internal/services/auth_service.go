@@ -8,7 +8,9 @@ import (
 	"time"
 
 	"github.com/comply360/auth-service/internal/repository"
+	"github.com/comply360/shared/branding"
 	"github.com/comply360/shared/models"
+	"github.com/comply360/shared/tenancy"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/pquerna/otp/totp"
@@ -27,6 +29,7 @@ type AuthService struct {
 	userRepo   *repository.UserRepository
 	redis      *redis.Client
 	jwtSecret  string
+	brands     *branding.Resolver
 }
 
 // TokenClaims represents the claims in a JWT token
@@ -37,11 +40,14 @@ type TokenClaims struct {
 	Roles    []string  `json:"roles"`
 }
 
-func NewAuthService(userRepo *repository.UserRepository, redis *redis.Client, jwtSecret string) *AuthService {
+// NewAuthService creates the auth service. brands may be nil, in which case the
+// platform branding is used.
+func NewAuthService(userRepo *repository.UserRepository, redis *redis.Client, jwtSecret string, brands *branding.Resolver) *AuthService {
 	return &AuthService{
 		userRepo:  userRepo,
 		redis:     redis,
 		jwtSecret: jwtSecret,
+		brands:    brands,
 	}
 }
 
@@ -88,6 +94,10 @@ func (s *AuthService) Register(tenantID uuid.UUID, req *models.RegisterRequest)
 
 // Login authenticates a user and returns tokens
 func (s *AuthService) Login(tenantID uuid.UUID, req *models.LoginRequest) (*models.AuthResponse, error) {
+	if err := s.checkTenantAccess(tenantID); err != nil {
+		return nil, err
+	}
+
 	// Get user by email
 	user, err := s.userRepo.GetByEmail(tenantID, req.Email)
 	if err != nil {
@@ -142,9 +152,11 @@ func (s *AuthService) Login(tenantID uuid.UUID, req *models.LoginRequest) (*mode
 		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
 	}
 
-	// Store refresh token in Redis
+	// Store refresh token in Redis, indexed by tenant so the tenant's sessions can be revoked
 	ctx := context.Background()
-	s.redis.Set(ctx, fmt.Sprintf("refresh_token:%s", refreshToken), user.ID.String(), refreshTokenDuration)
+	s.redis.Set(ctx, tenancy.RefreshTokenKey(refreshToken), user.ID.String(), refreshTokenDuration)
+	s.redis.SAdd(ctx, tenancy.TenantSessionsKey(tenantID), refreshToken)
+	s.redis.Expire(ctx, tenancy.TenantSessionsKey(tenantID), refreshTokenDuration)
 
 	return &models.AuthResponse{
 		AccessToken:  accessToken,
@@ -160,7 +172,7 @@ func (s *AuthService) RefreshToken(refreshToken string) (*models.AuthResponse, e
 	ctx := context.Background()
 
 	// Check if refresh token exists in Redis
-	userIDStr, err := s.redis.Get(ctx, fmt.Sprintf("refresh_token:%s", refreshToken)).Result()
+	userIDStr, err := s.redis.Get(ctx, tenancy.RefreshTokenKey(refreshToken)).Result()
 	if err != nil {
 		return nil, fmt.Errorf("invalid refresh token")
 	}
@@ -190,6 +202,10 @@ func (s *AuthService) RefreshToken(refreshToken string) (*models.AuthResponse, e
 		return nil, fmt.Errorf("invalid tenant ID")
 	}
 
+	if err := s.checkTenantAccess(tenantID); err != nil {
+		return nil, err
+	}
+
 	// Get user
 	user, err := s.userRepo.GetByID(tenantID, userID)
 	if err != nil {
@@ -244,9 +260,9 @@ func (s *AuthService) SetupMFA(tenantID, userID uuid.UUID, method string) (strin
 		return "", fmt.Errorf("user not found")
 	}
 
-	// Generate TOTP secret
+	// Generate TOTP secret; the issuer is the name shown in the authenticator app
 	key, err := totp.Generate(totp.GenerateOpts{
-		Issuer:      "Comply360",
+		Issuer:      s.brands.For(context.Background(), tenantID).DisplayName,
 		AccountName: user.Email,
 	})
 	if err != nil {
@@ -292,6 +308,27 @@ func (s *AuthService) VerifyMFA(tenantID, userID uuid.UUID, code string) error {
 	return nil
 }
 
+// checkTenantAccess rejects authentication for suspended, pending-deletion and deleted tenants.
+// Tenants missing from public.tenants are left to TenantMiddleware to reject.
+func (s *AuthService) checkTenantAccess(tenantID uuid.UUID) error {
+	status, err := s.userRepo.GetTenantStatus(tenantID)
+	if err != nil {
+		if err.Error() == "tenant not found" {
+			return nil
+		}
+		return fmt.Errorf("failed to check tenant status: %w", err)
+	}
+
+	switch status {
+	case models.TenantStatusActive:
+		return nil
+	case models.TenantStatusDeleted:
+		return fmt.Errorf("tenant deleted")
+	default:
+		return fmt.Errorf("tenant suspended")
+	}
+}
+
 // ValidateToken validates a JWT token and returns the claims
 func (s *AuthService) ValidateToken(tokenString string) (*TokenClaims, error) {
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
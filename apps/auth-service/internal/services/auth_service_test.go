@@ -19,7 +19,7 @@ func TestAuthService_Register(t *testing.T) {
 	defer tredis.Cleanup(t)
 
 	userRepo := repository.NewUserRepository(tdb.DB)
-	authService := NewAuthService(userRepo, tredis.Client, "test_jwt_secret")
+	authService := NewAuthService(userRepo, tredis.Client, "test_jwt_secret", nil)
 
 	// Test: Successful registration
 	req := &models.RegisterRequest{
@@ -54,7 +54,7 @@ func TestAuthService_Login(t *testing.T) {
 	defer tredis.Cleanup(t)
 
 	userRepo := repository.NewUserRepository(tdb.DB)
-	authService := NewAuthService(userRepo, tredis.Client, "test_jwt_secret")
+	authService := NewAuthService(userRepo, tredis.Client, "test_jwt_secret", nil)
 
 	// Create a user first
 	password := "SecurePassword123!"
@@ -111,7 +111,7 @@ func TestAuthService_LoginAccountLocking(t *testing.T) {
 	defer tredis.Cleanup(t)
 
 	userRepo := repository.NewUserRepository(tdb.DB)
-	authService := NewAuthService(userRepo, tredis.Client, "test_jwt_secret")
+	authService := NewAuthService(userRepo, tredis.Client, "test_jwt_secret", nil)
 
 	// Create a user
 	password := "SecurePassword123!"
@@ -164,7 +164,7 @@ func TestAuthService_ValidateToken(t *testing.T) {
 	defer tredis.Cleanup(t)
 
 	userRepo := repository.NewUserRepository(tdb.DB)
-	authService := NewAuthService(userRepo, tredis.Client, "test_jwt_secret")
+	authService := NewAuthService(userRepo, tredis.Client, "test_jwt_secret", nil)
 
 	// Create a user and login
 	password := "SecurePassword123!"
@@ -204,7 +204,7 @@ func TestAuthService_ValidateToken(t *testing.T) {
 	testhelpers.AssertError(t, err, "Should fail to validate invalid token")
 
 	// Test: Token with wrong secret
-	wrongSecretService := NewAuthService(userRepo, tredis.Client, "wrong_secret")
+	wrongSecretService := NewAuthService(userRepo, tredis.Client, "wrong_secret", nil)
 	_, err = wrongSecretService.ValidateToken(authResp.AccessToken)
 	testhelpers.AssertError(t, err, "Should fail to validate token with wrong secret")
 }
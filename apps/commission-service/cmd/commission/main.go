@@ -10,6 +10,7 @@ import (
 	"github.com/comply360/commission-service/internal/repository"
 	"github.com/comply360/commission-service/internal/services"
 	sharedmiddleware "github.com/comply360/shared/middleware"
+	"github.com/comply360/shared/tenancy"
 	"github.com/gin-gonic/gin"
 	_ "github.com/lib/pq"
 	amqp "github.com/rabbitmq/amqp091-go"
@@ -45,8 +46,14 @@ func main() {
 	// Initialize repository
 	repo := repository.NewCommissionRepository(db)
 
+	// Tenant lookups for request context, delegated access and parent overrides
+	tenantResolver := tenancy.NewResolver(db, nil, tenancy.DefaultResolverOptions())
+	if err := tenantResolver.ListenForInvalidation(rabbitConn); err != nil {
+		log.Printf("Warning: Failed to subscribe to tenant events: %v", err)
+	}
+
 	// Initialize service
-	service, err := services.NewCommissionService(repo, rabbitConn)
+	service, err := services.NewCommissionService(repo, tenantResolver, rabbitConn)
 	if err != nil {
 		log.Fatalf("Failed to create commission service: %v", err)
 	}
@@ -56,7 +63,7 @@ func main() {
 	handler := handlers.NewCommissionHandler(service)
 
 	// Setup router
-	r := setupRouter(db, handler, jwtSecret)
+	r := setupRouter(tenantResolver, handler, jwtSecret)
 
 	// Start server
 	addr := fmt.Sprintf(":%s", port)
@@ -66,7 +73,7 @@ func main() {
 	}
 }
 
-func setupRouter(db *sql.DB, handler *handlers.CommissionHandler, jwtSecret string) *gin.Engine {
+func setupRouter(tenantResolver *tenancy.Resolver, handler *handlers.CommissionHandler, jwtSecret string) *gin.Engine {
 	// Set Gin mode
 	if os.Getenv("APP_ENV") == "production" {
 		gin.SetMode(gin.ReleaseMode)
@@ -87,11 +94,14 @@ func setupRouter(db *sql.DB, handler *handlers.CommissionHandler, jwtSecret stri
 	api := r.Group("/api/v1")
 	{
 		// Tenant middleware - extracts tenant context
-		api.Use(sharedmiddleware.TenantMiddleware(db))
+		api.Use(sharedmiddleware.TenantResolverMiddleware(tenantResolver))
 
 		// Auth middleware - validates JWT
 		api.Use(sharedmiddleware.AuthMiddleware(jwtSecret))
 
+		// Tenant access - a parent tenant_admin or global_admin may act on child tenants
+		api.Use(sharedmiddleware.TenantAccessMiddleware(tenantResolver))
+
 		// Commission routes
 		commissions := api.Group("/commissions")
 		{
@@ -3,12 +3,12 @@ package handlers
 import (
 	"fmt"
 	"net/http"
-	"strconv"
 
 	"github.com/comply360/commission-service/internal/services"
 	"github.com/comply360/shared/errors"
 	sharedmiddleware "github.com/comply360/shared/middleware"
 	"github.com/comply360/shared/models"
+	"github.com/comply360/shared/pagination"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
@@ -81,11 +81,20 @@ func (h *CommissionHandler) CreateCommission(c *gin.Context) {
 		req.Currency,
 	)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, errors.NewAPIErrorWithDetails(
-			errors.ErrInternalServer,
-			"Failed to create commission",
-			map[string]interface{}{"error": err.Error()},
-		))
+		switch err.Error() {
+		case "registration fee and currency are required for a registration without a locked quote",
+			"registration fee must be greater than 0", "commission rate must be between 0 and 100":
+			c.JSON(http.StatusBadRequest, errors.NewAPIError(errors.ErrInvalidInput, err.Error()))
+		case "registration fee differs from the registration's locked quote",
+			"currency differs from the registration's locked quote":
+			c.JSON(http.StatusConflict, errors.NewAPIError(errors.ErrConflict, err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, errors.NewAPIErrorWithDetails(
+				errors.ErrInternalServer,
+				"Failed to create commission",
+				map[string]interface{}{"error": err.Error()},
+			))
+		}
 		return
 	}
 
@@ -128,8 +137,11 @@ func (h *CommissionHandler) ListCommissions(c *gin.Context) {
 	tenantID, _ := sharedmiddleware.GetTenantID(c)
 
 	// Parse query parameters
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(errors.ErrInvalidInput, err.Error()))
+		return
+	}
 	status := c.Query("status")
 
 	var agentID *uuid.UUID
@@ -149,8 +161,12 @@ func (h *CommissionHandler) ListCommissions(c *gin.Context) {
 	}
 
 	// Get commissions
-	commissions, total, err := h.service.ListCommissions(schema.(string), tenantID, agentID, registrationID, offset, limit, status)
+	commissions, err := h.service.ListCommissions(schema.(string), tenantID, agentID, registrationID, status, page)
 	if err != nil {
+		if pagination.IsRequestError(err) {
+			c.JSON(http.StatusBadRequest, errors.NewAPIError(errors.ErrInvalidInput, err.Error()))
+			return
+		}
 		c.JSON(http.StatusInternalServerError, errors.NewAPIErrorWithDetails(
 			errors.ErrInternalServer,
 			"Failed to list commissions",
@@ -159,12 +175,7 @@ func (h *CommissionHandler) ListCommissions(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data":   commissions,
-		"total":  total,
-		"offset": offset,
-		"limit":  limit,
-	})
+	pagination.Respond(c, commissions, page)
 }
 
 // GetCommissionSummary handles GET /commissions/summary
@@ -316,11 +327,55 @@ func (h *CommissionHandler) CancelCommission(c *gin.Context) {
 	})
 }
 
+// ListOverrides handles GET /commissions/overrides: what the tenant earned on its
+// sub-agent tenants' commissions
+func (h *CommissionHandler) ListOverrides(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(errors.ErrInvalidInput, err.Error()))
+		return
+	}
+	status := c.Query("status")
+
+	var sourceTenantID *uuid.UUID
+	if sourceStr := c.Query("source_tenant_id"); sourceStr != "" {
+		id, err := uuid.Parse(sourceStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errors.NewAPIError(
+				errors.ErrInvalidInput,
+				"Invalid source tenant ID",
+			))
+			return
+		}
+		sourceTenantID = &id
+	}
+
+	overrides, err := h.service.ListOverrides(schema.(string), tenantID, sourceTenantID, status, page)
+	if err != nil {
+		if pagination.IsRequestError(err) {
+			c.JSON(http.StatusBadRequest, errors.NewAPIError(errors.ErrInvalidInput, err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errors.NewAPIErrorWithDetails(
+			errors.ErrInternalServer,
+			"Failed to list commission overrides",
+			map[string]interface{}{"error": err.Error()},
+		))
+		return
+	}
+
+	pagination.Respond(c, overrides, page)
+}
+
 // SetupRoutes sets up the commission routes
 func (h *CommissionHandler) SetupRoutes(r *gin.RouterGroup) {
 	r.POST("", h.CreateCommission)
 	r.GET("", h.ListCommissions)
 	r.GET("/summary", h.GetCommissionSummary)
+	r.GET("/overrides", h.ListOverrides)
 	r.GET("/:id", h.GetCommission)
 	r.POST("/:id/approve", h.ApproveCommission)
 	r.POST("/:id/pay", h.MarkCommissionPaid)
@@ -6,6 +6,8 @@ import (
 	"fmt"
 
 	"github.com/comply360/shared/models"
+	"github.com/comply360/shared/pagination"
+	"github.com/comply360/shared/tenancy"
 	"github.com/google/uuid"
 )
 
@@ -19,43 +21,6 @@ func NewCommissionRepository(db *sql.DB) *CommissionRepository {
 
 // Create creates a new commission record
 func (r *CommissionRepository) Create(schema string, commission *models.Commission) error {
-	fmt.Printf("DEBUG REPO: Schema parameter received: '%s' (len=%d)\n", schema, len(schema))
-
-	// Start transaction
-	tx, err := r.db.Begin()
-	if err != nil {
-		return fmt.Errorf("failed to start transaction: %w", err)
-	}
-	defer tx.Rollback()
-
-	// Set tenant context for RLS - CRITICAL for FK constraint checks!
-	tenantIDSetting := fmt.Sprintf("SET LOCAL app.current_tenant_id = '%s'", commission.TenantID.String())
-	fmt.Printf("DEBUG REPO: Executing: %s\n", tenantIDSetting)
-	_, err = tx.Exec(tenantIDSetting)
-	if err != nil {
-		fmt.Printf("DEBUG REPO: ERROR setting tenant context: %v\n", err)
-		return fmt.Errorf("failed to set tenant context: %w", err)
-	}
-	fmt.Printf("DEBUG REPO: ✓ Tenant context set\n")
-
-	// Set global admin flag to bypass RLS for FK checks
-	fmt.Printf("DEBUG REPO: Executing: SET LOCAL app.is_global_admin = 'true'\n")
-	_, err = tx.Exec("SET LOCAL app.is_global_admin = 'true'")
-	if err != nil {
-		fmt.Printf("DEBUG REPO: ERROR setting admin context: %v\n", err)
-		return fmt.Errorf("failed to set admin context: %w", err)
-	}
-	fmt.Printf("DEBUG REPO: ✓ Admin context set\n")
-
-	// Verify session variables are set
-	var tenantIDCheck, adminCheck string
-	err = tx.QueryRow("SELECT current_setting('app.current_tenant_id', true), current_setting('app.is_global_admin', true)").Scan(&tenantIDCheck, &adminCheck)
-	if err != nil {
-		fmt.Printf("DEBUG REPO: ERROR reading session vars: %v\n", err)
-	} else {
-		fmt.Printf("DEBUG REPO: Session vars verification - tenant_id=%s, is_global_admin=%s\n", tenantIDCheck, adminCheck)
-	}
-
 	query := fmt.Sprintf(`
 		INSERT INTO %s.commissions (
 			tenant_id, registration_id, agent_id,
@@ -70,34 +35,25 @@ func (r *CommissionRepository) Create(schema string, commission *models.Commissi
 		return fmt.Errorf("failed to marshal metadata: %w", err)
 	}
 
-	fmt.Printf("DEBUG REPO: Query length: %d bytes\n", len(query))
-	fmt.Printf("DEBUG REPO: Params: tenantID=%s, regID=%s, agentID=%s\n",
-		commission.TenantID, commission.RegistrationID, commission.AgentID)
-
-	err = tx.QueryRow(
-		query,
-		commission.TenantID,
-		commission.RegistrationID,
-		commission.AgentID,
-		commission.RegistrationFee,
-		commission.CommissionRate,
-		commission.CommissionAmount,
-		commission.Currency,
-		commission.Status,
-		metadataJSON,
-	).Scan(&commission.ID, &commission.CreatedAt, &commission.UpdatedAt)
-
+	// Foreign key checks bypass RLS, so the tenant context alone is enough here
+	err = tenancy.WithTenant(r.db, commission.TenantID, func(tx *sql.Tx) error {
+		return tx.QueryRow(
+			query,
+			commission.TenantID,
+			commission.RegistrationID,
+			commission.AgentID,
+			commission.RegistrationFee,
+			commission.CommissionRate,
+			commission.CommissionAmount,
+			commission.Currency,
+			commission.Status,
+			metadataJSON,
+		).Scan(&commission.ID, &commission.CreatedAt, &commission.UpdatedAt)
+	})
 	if err != nil {
-		fmt.Printf("DEBUG REPO ERROR: %v\n", err)
 		return fmt.Errorf("database insert failed: %w", err)
 	}
 
-	// Commit transaction
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
-	}
-
-	fmt.Printf("DEBUG REPO SUCCESS: Created commission ID=%s\n", commission.ID)
 	return nil
 }
 
@@ -116,25 +72,27 @@ func (r *CommissionRepository) GetByID(schema string, tenantID, commissionID uui
 	commission := &models.Commission{}
 	var metadataJSON []byte
 
-	err := r.db.QueryRow(query, commissionID, tenantID).Scan(
-		&commission.ID,
-		&commission.TenantID,
-		&commission.RegistrationID,
-		&commission.AgentID,
-		&commission.RegistrationFee,
-		&commission.CommissionRate,
-		&commission.CommissionAmount,
-		&commission.Currency,
-		&commission.Status,
-		&commission.ApprovedAt,
-		&commission.ApprovedBy,
-		&commission.PaidAt,
-		&commission.PaymentReference,
-		&commission.OdooCommissionID,
-		&commission.CreatedAt,
-		&commission.UpdatedAt,
-		&metadataJSON,
-	)
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		return tx.QueryRow(query, commissionID, tenantID).Scan(
+			&commission.ID,
+			&commission.TenantID,
+			&commission.RegistrationID,
+			&commission.AgentID,
+			&commission.RegistrationFee,
+			&commission.CommissionRate,
+			&commission.CommissionAmount,
+			&commission.Currency,
+			&commission.Status,
+			&commission.ApprovedAt,
+			&commission.ApprovedBy,
+			&commission.PaidAt,
+			&commission.PaymentReference,
+			&commission.OdooCommissionID,
+			&commission.CreatedAt,
+			&commission.UpdatedAt,
+			&metadataJSON,
+		)
+	})
 
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("commission not found")
@@ -153,8 +111,20 @@ func (r *CommissionRepository) GetByID(schema string, tenantID, commissionID uui
 	return commission, nil
 }
 
-// List retrieves commissions with pagination and filters
-func (r *CommissionRepository) List(schema string, tenantID uuid.UUID, agentID *uuid.UUID, registrationID *uuid.UUID, offset, limit int, status string) ([]*models.Commission, int, error) {
+// CommissionKeys are the fields commission lists sort by
+var CommissionKeys = &pagination.Keys[*models.Commission]{
+	ID: func(c *models.Commission) uuid.UUID { return c.ID },
+	Columns: map[string]pagination.Column[*models.Commission]{
+		"created_at":        {SQL: "created_at", Value: func(c *models.Commission) interface{} { return c.CreatedAt }},
+		"updated_at":        {SQL: "updated_at", Value: func(c *models.Commission) interface{} { return c.UpdatedAt }},
+		"commission_amount": {SQL: "commission_amount", Value: func(c *models.Commission) interface{} { return c.CommissionAmount }},
+		"status":            {SQL: "status", Value: func(c *models.Commission) interface{} { return c.Status }},
+	},
+	Default: "-created_at",
+}
+
+// List retrieves a page of commissions after the page's cursor, with optional filters
+func (r *CommissionRepository) List(schema string, tenantID uuid.UUID, agentID *uuid.UUID, registrationID *uuid.UUID, status string, page *pagination.Query[*models.Commission]) (*pagination.List[*models.Commission], error) {
 	// Build query with optional filters
 	whereClause := "WHERE tenant_id = $1"
 	args := []interface{}{tenantID}
@@ -178,23 +148,36 @@ func (r *CommissionRepository) List(schema string, tenantID uuid.UUID, agentID *
 		args = append(args, status)
 	}
 
-	// Count total
+	// Count total, only when asked for
 	countQuery := fmt.Sprintf(`
 		SELECT COUNT(*) FROM %s.commissions %s
 	`, schema, whereClause)
+	countArgs := args
 
-	var total int
-	if err := r.db.QueryRow(countQuery, args...).Scan(&total); err != nil {
-		return nil, 0, fmt.Errorf("failed to count commissions: %w", err)
+	// Continue after the cursor
+	if keyset, values := page.Keyset(argCount + 1); keyset != "" {
+		whereClause += " AND " + keyset
+		args = append(append([]interface{}{}, args...), values...)
+		argCount += len(values)
 	}
 
-	// Get commissions
-	argCount++
-	limitArg := argCount
-	argCount++
-	offsetArg := argCount
+	var total *int
+	var commissions []*models.Commission
 
-	query := fmt.Sprintf(`
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		if page.IncludeTotal() {
+			var count int
+			if err := tx.QueryRow(countQuery, countArgs...).Scan(&count); err != nil {
+				return fmt.Errorf("failed to count commissions: %w", err)
+			}
+			total = &count
+		}
+
+		// Get commissions
+		argCount++
+		limitArg := argCount
+
+		query := fmt.Sprintf(`
 		SELECT id, tenant_id, registration_id, agent_id,
 			registration_fee, commission_rate, commission_amount,
 			currency, status, approved_at, approved_by,
@@ -202,58 +185,63 @@ func (r *CommissionRepository) List(schema string, tenantID uuid.UUID, agentID *
 			created_at, updated_at, metadata
 		FROM %s.commissions
 		%s
-		ORDER BY created_at DESC
-		LIMIT $%d OFFSET $%d
-	`, schema, whereClause, limitArg, offsetArg)
-
-	args = append(args, limit, offset)
-
-	rows, err := r.db.Query(query, args...)
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to query commissions: %w", err)
-	}
-	defer rows.Close()
+		ORDER BY %s
+		LIMIT $%d
+	`, schema, whereClause, page.OrderBy(), limitArg)
 
-	var commissions []*models.Commission
-	for rows.Next() {
-		commission := &models.Commission{}
-		var metadataJSON []byte
-
-		err := rows.Scan(
-			&commission.ID,
-			&commission.TenantID,
-			&commission.RegistrationID,
-			&commission.AgentID,
-			&commission.RegistrationFee,
-			&commission.CommissionRate,
-			&commission.CommissionAmount,
-			&commission.Currency,
-			&commission.Status,
-			&commission.ApprovedAt,
-			&commission.ApprovedBy,
-			&commission.PaidAt,
-			&commission.PaymentReference,
-			&commission.OdooCommissionID,
-			&commission.CreatedAt,
-			&commission.UpdatedAt,
-			&metadataJSON,
-		)
+		args = append(args, page.Limit())
 
+		rows, err := tx.Query(query, args...)
 		if err != nil {
-			return nil, 0, fmt.Errorf("failed to scan commission: %w", err)
+			return fmt.Errorf("failed to query commissions: %w", err)
 		}
+		defer rows.Close()
+
+		for rows.Next() {
+			commission := &models.Commission{}
+			var metadataJSON []byte
+
+			err := rows.Scan(
+				&commission.ID,
+				&commission.TenantID,
+				&commission.RegistrationID,
+				&commission.AgentID,
+				&commission.RegistrationFee,
+				&commission.CommissionRate,
+				&commission.CommissionAmount,
+				&commission.Currency,
+				&commission.Status,
+				&commission.ApprovedAt,
+				&commission.ApprovedBy,
+				&commission.PaidAt,
+				&commission.PaymentReference,
+				&commission.OdooCommissionID,
+				&commission.CreatedAt,
+				&commission.UpdatedAt,
+				&metadataJSON,
+			)
+
+			if err != nil {
+				return fmt.Errorf("failed to scan commission: %w", err)
+			}
 
-		// Unmarshal metadata
-		if len(metadataJSON) > 0 {
-			if err := json.Unmarshal(metadataJSON, &commission.Metadata); err != nil {
-				return nil, 0, fmt.Errorf("failed to unmarshal metadata: %w", err)
+			// Unmarshal metadata
+			if len(metadataJSON) > 0 {
+				if err := json.Unmarshal(metadataJSON, &commission.Metadata); err != nil {
+					return fmt.Errorf("failed to unmarshal metadata: %w", err)
+				}
 			}
+
+			commissions = append(commissions, commission)
 		}
 
-		commissions = append(commissions, commission)
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return commissions, total, nil
+	return page.List(commissions, total), nil
 }
 
 // Update updates a commission
@@ -280,22 +268,27 @@ func (r *CommissionRepository) Update(schema string, commission *models.Commissi
 		return fmt.Errorf("failed to marshal metadata: %w", err)
 	}
 
-	result, err := r.db.Exec(
-		query,
-		commission.RegistrationFee,
-		commission.CommissionRate,
-		commission.CommissionAmount,
-		commission.Currency,
-		commission.Status,
-		commission.ApprovedAt,
-		commission.ApprovedBy,
-		commission.PaidAt,
-		commission.PaymentReference,
-		commission.OdooCommissionID,
-		metadataJSON,
-		commission.ID,
-		commission.TenantID,
-	)
+	var result sql.Result
+	err = tenancy.WithTenant(r.db, commission.TenantID, func(tx *sql.Tx) error {
+		var err error
+		result, err = tx.Exec(
+			query,
+			commission.RegistrationFee,
+			commission.CommissionRate,
+			commission.CommissionAmount,
+			commission.Currency,
+			commission.Status,
+			commission.ApprovedAt,
+			commission.ApprovedBy,
+			commission.PaidAt,
+			commission.PaymentReference,
+			commission.OdooCommissionID,
+			metadataJSON,
+			commission.ID,
+			commission.TenantID,
+		)
+		return err
+	})
 
 	if err != nil {
 		return fmt.Errorf("failed to update commission: %w", err)
@@ -328,13 +321,15 @@ func (r *CommissionRepository) GetSummary(schema string, tenantID, agentID uuid.
 
 	summary := &models.CommissionSummary{Currency: currency}
 
-	err := r.db.QueryRow(query, tenantID, agentID, currency).Scan(
-		&summary.TotalCommissions,
-		&summary.TotalAmount,
-		&summary.PendingAmount,
-		&summary.ApprovedAmount,
-		&summary.PaidAmount,
-	)
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		return tx.QueryRow(query, tenantID, agentID, currency).Scan(
+			&summary.TotalCommissions,
+			&summary.TotalAmount,
+			&summary.PendingAmount,
+			&summary.ApprovedAmount,
+			&summary.PaidAmount,
+		)
+	})
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to get commission summary: %w", err)
@@ -354,7 +349,12 @@ func (r *CommissionRepository) Approve(schema string, tenantID, commissionID, ap
 		WHERE id = $2 AND tenant_id = $3
 	`, schema)
 
-	result, err := r.db.Exec(query, approvedBy, commissionID, tenantID)
+	var result sql.Result
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		var err error
+		result, err = tx.Exec(query, approvedBy, commissionID, tenantID)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("failed to approve commission: %w", err)
 	}
@@ -382,7 +382,12 @@ func (r *CommissionRepository) Pay(schema string, tenantID, commissionID uuid.UU
 		WHERE id = $2 AND tenant_id = $3 AND status = 'approved'
 	`, schema)
 
-	result, err := r.db.Exec(query, paymentReference, commissionID, tenantID)
+	var result sql.Result
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		var err error
+		result, err = tx.Exec(query, paymentReference, commissionID, tenantID)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("failed to mark commission as paid: %w", err)
 	}
@@ -415,25 +420,27 @@ func (r *CommissionRepository) GetByRegistrationID(schema string, tenantID, regi
 	commission := &models.Commission{}
 	var metadataJSON []byte
 
-	err := r.db.QueryRow(query, registrationID, tenantID).Scan(
-		&commission.ID,
-		&commission.TenantID,
-		&commission.RegistrationID,
-		&commission.AgentID,
-		&commission.RegistrationFee,
-		&commission.CommissionRate,
-		&commission.CommissionAmount,
-		&commission.Currency,
-		&commission.Status,
-		&commission.ApprovedAt,
-		&commission.ApprovedBy,
-		&commission.PaidAt,
-		&commission.PaymentReference,
-		&commission.OdooCommissionID,
-		&commission.CreatedAt,
-		&commission.UpdatedAt,
-		&metadataJSON,
-	)
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		return tx.QueryRow(query, registrationID, tenantID).Scan(
+			&commission.ID,
+			&commission.TenantID,
+			&commission.RegistrationID,
+			&commission.AgentID,
+			&commission.RegistrationFee,
+			&commission.CommissionRate,
+			&commission.CommissionAmount,
+			&commission.Currency,
+			&commission.Status,
+			&commission.ApprovedAt,
+			&commission.ApprovedBy,
+			&commission.PaidAt,
+			&commission.PaymentReference,
+			&commission.OdooCommissionID,
+			&commission.CreatedAt,
+			&commission.UpdatedAt,
+			&metadataJSON,
+		)
+	})
 
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("commission not found")
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/comply360/shared/models"
+	"github.com/comply360/shared/pagination"
 	testhelpers "github.com/comply360/shared/testing"
 	"github.com/google/uuid"
 )
@@ -334,8 +335,10 @@ func TestCommissionRepository_List(t *testing.T) {
 	}
 
 	// Test: List commissions
-	commissions, total, err := repo.List(tdb.Schema, tdb.TenantID, nil, nil, 0, 10, "")
+	page, err := CommissionKeys.Query(pagination.Page{Limit: 10, IncludeTotal: true})
+	testhelpers.AssertNoError(t, err)
+	commissions, err := repo.List(tdb.Schema, tdb.TenantID, nil, nil, "", page)
 	testhelpers.AssertNoError(t, err, "Failed to list commissions")
-	testhelpers.AssertEqual(t, 5, len(commissions), "Should return 5 commissions")
-	testhelpers.AssertEqual(t, 5, total, "Total count should be 5")
+	testhelpers.AssertEqual(t, 5, len(commissions.Data), "Should return 5 commissions")
+	testhelpers.AssertEqual(t, 5, *commissions.Total, "Total count should be 5")
 }
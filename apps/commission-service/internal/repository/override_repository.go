@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/comply360/shared/models"
+	"github.com/comply360/shared/pagination"
+	"github.com/comply360/shared/tenancy"
+	"github.com/google/uuid"
+)
+
+// UpsertOverride records or updates the override a parent tenant earns on a
+// sub-agent's commission. schema is the parent's schema.
+func (r *CommissionRepository) UpsertOverride(schema string, override *models.CommissionOverride) error {
+	query := fmt.Sprintf(`
+		INSERT INTO %s.commission_overrides (
+			tenant_id, source_tenant_id, source_commission_id, source_registration_id,
+			registration_fee, override_rate, override_amount, currency, status
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (source_tenant_id, source_commission_id) DO UPDATE
+		SET registration_fee = EXCLUDED.registration_fee,
+			override_rate = EXCLUDED.override_rate,
+			override_amount = EXCLUDED.override_amount,
+			currency = EXCLUDED.currency,
+			status = EXCLUDED.status,
+			updated_at = CURRENT_TIMESTAMP
+		RETURNING id, created_at, updated_at
+	`, schema)
+
+	err := tenancy.WithTenant(r.db, override.TenantID, func(tx *sql.Tx) error {
+		return tx.QueryRow(
+			query,
+			override.TenantID,
+			override.SourceTenantID,
+			override.SourceCommissionID,
+			override.SourceRegistrationID,
+			override.RegistrationFee,
+			override.OverrideRate,
+			override.OverrideAmount,
+			override.Currency,
+			override.Status,
+		).Scan(&override.ID, &override.CreatedAt, &override.UpdatedAt)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store commission override: %w", err)
+	}
+
+	return nil
+}
+
+// OverrideKeys are the fields commission override lists sort by
+var OverrideKeys = &pagination.Keys[*models.CommissionOverride]{
+	ID: func(o *models.CommissionOverride) uuid.UUID { return o.ID },
+	Columns: map[string]pagination.Column[*models.CommissionOverride]{
+		"created_at":      {SQL: "created_at", Value: func(o *models.CommissionOverride) interface{} { return o.CreatedAt }},
+		"updated_at":      {SQL: "updated_at", Value: func(o *models.CommissionOverride) interface{} { return o.UpdatedAt }},
+		"override_amount": {SQL: "override_amount", Value: func(o *models.CommissionOverride) interface{} { return o.OverrideAmount }},
+		"status":          {SQL: "status", Value: func(o *models.CommissionOverride) interface{} { return o.Status }},
+	},
+	Default: "-created_at",
+}
+
+// ListOverrides retrieves a page of the overrides a tenant earned after the page's
+// cursor, optionally for one sub-agent tenant
+func (r *CommissionRepository) ListOverrides(schema string, tenantID uuid.UUID, sourceTenantID *uuid.UUID, status string, page *pagination.Query[*models.CommissionOverride]) (*pagination.List[*models.CommissionOverride], error) {
+	whereClause := "WHERE tenant_id = $1"
+	args := []interface{}{tenantID}
+	argCount := 1
+
+	if sourceTenantID != nil {
+		argCount++
+		whereClause += fmt.Sprintf(" AND source_tenant_id = $%d", argCount)
+		args = append(args, sourceTenantID)
+	}
+
+	if status != "" {
+		argCount++
+		whereClause += fmt.Sprintf(" AND status = $%d", argCount)
+		args = append(args, status)
+	}
+
+	// Count total, only when asked for
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM %s.commission_overrides %s`, schema, whereClause)
+	countArgs := args
+
+	// Continue after the cursor
+	if keyset, values := page.Keyset(argCount + 1); keyset != "" {
+		whereClause += " AND " + keyset
+		args = append(append([]interface{}{}, args...), values...)
+		argCount += len(values)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, tenant_id, source_tenant_id, source_commission_id, source_registration_id,
+			registration_fee, override_rate, override_amount, currency, status,
+			created_at, updated_at
+		FROM %s.commission_overrides
+		%s
+		ORDER BY %s
+		LIMIT $%d
+	`, schema, whereClause, page.OrderBy(), argCount+1)
+	args = append(args, page.Limit())
+
+	var total *int
+	var overrides []*models.CommissionOverride
+
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		if page.IncludeTotal() {
+			var count int
+			if err := tx.QueryRow(countQuery, countArgs...).Scan(&count); err != nil {
+				return fmt.Errorf("failed to count commission overrides: %w", err)
+			}
+			total = &count
+		}
+
+		rows, err := tx.Query(query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to query commission overrides: %w", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			override := &models.CommissionOverride{}
+			err := rows.Scan(
+				&override.ID,
+				&override.TenantID,
+				&override.SourceTenantID,
+				&override.SourceCommissionID,
+				&override.SourceRegistrationID,
+				&override.RegistrationFee,
+				&override.OverrideRate,
+				&override.OverrideAmount,
+				&override.Currency,
+				&override.Status,
+				&override.CreatedAt,
+				&override.UpdatedAt,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to scan commission override: %w", err)
+			}
+			overrides = append(overrides, override)
+		}
+
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return page.List(overrides, total), nil
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/comply360/shared/models"
+	"github.com/comply360/shared/tenancy"
+	"github.com/google/uuid"
+)
+
+// GetLockedQuote retrieves the quote the registration service locked when a
+// registration was submitted. Commissions are earned on its amounts.
+func (r *CommissionRepository) GetLockedQuote(schema string, tenantID, registrationID uuid.UUID) (*models.Quote, error) {
+	query := fmt.Sprintf(`
+		SELECT id, tenant_id, registration_id, currency, vat_rate, lines,
+			subtotal, vat, total, status, locked_at
+		FROM %s.registration_quotes
+		WHERE registration_id = $1 AND tenant_id = $2 AND status = $3
+	`, schema)
+
+	quote := &models.Quote{}
+	var linesJSON []byte
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		return tx.QueryRow(query, registrationID, tenantID, models.QuoteStatusLocked).Scan(
+			&quote.ID,
+			&quote.TenantID,
+			&quote.RegistrationID,
+			&quote.Currency,
+			&quote.VATRate,
+			&linesJSON,
+			&quote.Subtotal,
+			&quote.VAT,
+			&quote.Total,
+			&quote.Status,
+			&quote.LockedAt,
+		)
+	})
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("quote not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get locked quote: %w", err)
+	}
+
+	if err := json.Unmarshal(linesJSON, &quote.Lines); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal quote lines: %w", err)
+	}
+	return quote, nil
+}
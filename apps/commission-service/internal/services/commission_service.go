@@ -4,21 +4,27 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/comply360/commission-service/internal/repository"
 	"github.com/comply360/shared/models"
+	"github.com/comply360/shared/pagination"
+	"github.com/comply360/shared/tenancy"
 	"github.com/google/uuid"
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
 type CommissionService struct {
 	repo       *repository.CommissionRepository
+	tenants    *tenancy.Resolver
 	rabbitConn *amqp.Connection
 	rabbitCh   *amqp.Channel
 }
 
-func NewCommissionService(repo *repository.CommissionRepository, rabbitConn *amqp.Connection) (*CommissionService, error) {
+// NewCommissionService creates the commission service. tenants resolves the parent
+// of a sub-agent tenant, which earns an override on each of its commissions.
+func NewCommissionService(repo *repository.CommissionRepository, tenants *tenancy.Resolver, rabbitConn *amqp.Connection) (*CommissionService, error) {
 	ch, err := rabbitConn.Channel()
 	if err != nil {
 		return nil, fmt.Errorf("failed to open channel: %w", err)
@@ -40,15 +46,41 @@ func NewCommissionService(repo *repository.CommissionRepository, rabbitConn *amq
 
 	return &CommissionService{
 		repo:       repo,
+		tenants:    tenants,
 		rabbitConn: rabbitConn,
 		rabbitCh:   ch,
 	}, nil
 }
 
-// CreateCommission creates a new commission with automatic calculation
-func (s *CommissionService) CreateCommission(schema string, tenantID, registrationID, agentID uuid.UUID, registrationFee, commissionRate float64, currency string) (*models.Commission, error) {
+// CreateCommission creates a new commission with automatic calculation. The
+// commission is earned on the service amount of the quote locked when the
+// registration was submitted, in the quote's currency. A registration fee and
+// currency are only needed for a registration without a locked quote; given
+// for one with a quote, they must agree with it.
+func (s *CommissionService) CreateCommission(schema string, tenantID, registrationID, agentID uuid.UUID, registrationFee *float64, commissionRate float64, currency string) (*models.Commission, error) {
+	quote, err := s.repo.GetLockedQuote(schema, tenantID, registrationID)
+	if err != nil && err.Error() != "quote not found" {
+		return nil, err
+	}
+
+	var metadata map[string]interface{}
+	if quote != nil {
+		if registrationFee != nil && math.Abs(*registrationFee-quote.ServiceAmount()) >= 0.005 {
+			return nil, fmt.Errorf("registration fee differs from the registration's locked quote")
+		}
+		if currency != "" && currency != quote.Currency {
+			return nil, fmt.Errorf("currency differs from the registration's locked quote")
+		}
+		fee := quote.ServiceAmount()
+		registrationFee = &fee
+		currency = quote.Currency
+		metadata = map[string]interface{}{"quote_id": quote.ID.String()}
+	} else if registrationFee == nil || currency == "" {
+		return nil, fmt.Errorf("registration fee and currency are required for a registration without a locked quote")
+	}
+
 	// Validate inputs
-	if registrationFee <= 0 {
+	if *registrationFee <= 0 {
 		return nil, fmt.Errorf("registration fee must be greater than 0")
 	}
 	if commissionRate < 0 || commissionRate > 100 {
@@ -56,7 +88,7 @@ func (s *CommissionService) CreateCommission(schema string, tenantID, registrati
 	}
 
 	// Calculate commission amount
-	commissionAmount := s.calculateCommissionAmount(registrationFee, commissionRate)
+	commissionAmount := s.calculateCommissionAmount(*registrationFee, commissionRate)
 
 	// Create commission
 	commission := &models.Commission{
@@ -64,11 +96,12 @@ func (s *CommissionService) CreateCommission(schema string, tenantID, registrati
 		TenantID:         tenantID,
 		RegistrationID:   registrationID,
 		AgentID:          agentID,
-		RegistrationFee:  registrationFee,
+		RegistrationFee:  *registrationFee,
 		CommissionRate:   commissionRate,
 		CommissionAmount: commissionAmount,
 		Currency:         currency,
 		Status:           models.CommissionStatusPending,
+		Metadata:         metadata,
 	}
 
 	if err := s.repo.Create(schema, commission); err != nil {
@@ -80,6 +113,8 @@ func (s *CommissionService) CreateCommission(schema string, tenantID, registrati
 		fmt.Printf("Warning: Failed to publish event: %v\n", err)
 	}
 
+	s.flowOverride(commission)
+
 	return commission, nil
 }
 
@@ -93,20 +128,14 @@ func (s *CommissionService) GetCommissionByRegistration(schema string, tenantID,
 	return s.repo.GetByRegistrationID(schema, tenantID, registrationID)
 }
 
-// ListCommissions retrieves commissions with pagination and filters
-func (s *CommissionService) ListCommissions(schema string, tenantID uuid.UUID, agentID *uuid.UUID, registrationID *uuid.UUID, offset, limit int, status string) ([]*models.Commission, int, error) {
-	// Validate pagination parameters
-	if limit <= 0 {
-		limit = 20
-	}
-	if limit > 100 {
-		limit = 100
-	}
-	if offset < 0 {
-		offset = 0
+// ListCommissions retrieves a page of commissions with filters
+func (s *CommissionService) ListCommissions(schema string, tenantID uuid.UUID, agentID *uuid.UUID, registrationID *uuid.UUID, status string, page pagination.Page) (*pagination.List[*models.Commission], error) {
+	query, err := repository.CommissionKeys.Query(page)
+	if err != nil {
+		return nil, err
 	}
 
-	return s.repo.List(schema, tenantID, agentID, registrationID, offset, limit, status)
+	return s.repo.List(schema, tenantID, agentID, registrationID, status, query)
 }
 
 // GetCommissionSummary retrieves commission summary for an agent
@@ -143,6 +172,8 @@ func (s *CommissionService) ApproveCommission(schema string, tenantID, commissio
 		fmt.Printf("Warning: Failed to publish event: %v\n", err)
 	}
 
+	s.flowOverride(commission)
+
 	return nil
 }
 
@@ -171,6 +202,8 @@ func (s *CommissionService) MarkCommissionPaid(schema string, tenantID, commissi
 		fmt.Printf("Warning: Failed to publish event: %v\n", err)
 	}
 
+	s.flowOverride(commission)
+
 	return nil
 }
 
@@ -196,6 +229,8 @@ func (s *CommissionService) CancelCommission(schema string, tenantID, commission
 		fmt.Printf("Warning: Failed to publish event: %v\n", err)
 	}
 
+	s.flowOverride(commission)
+
 	return nil
 }
 
@@ -210,8 +245,14 @@ func (s *CommissionService) RecalculateCommission(schema string, tenantID, commi
 		return fmt.Errorf("can only recalculate pending commissions")
 	}
 
-	// Update fee and/or rate if provided
+	// Update fee and/or rate if provided. The fee of a registration with a
+	// locked quote is the quote's.
 	if newRegistrationFee != nil {
+		if _, err := s.repo.GetLockedQuote(schema, tenantID, commission.RegistrationID); err == nil {
+			return fmt.Errorf("registration fee is locked by the registration's quote")
+		} else if err.Error() != "quote not found" {
+			return err
+		}
 		if *newRegistrationFee <= 0 {
 			return fmt.Errorf("registration fee must be greater than 0")
 		}
@@ -237,9 +278,69 @@ func (s *CommissionService) RecalculateCommission(schema string, tenantID, commi
 		fmt.Printf("Warning: Failed to publish event: %v\n", err)
 	}
 
+	s.flowOverride(commission)
+
 	return nil
 }
 
+// ListOverrides retrieves a page of the overrides a tenant earned on its sub-agents' commissions
+func (s *CommissionService) ListOverrides(schema string, tenantID uuid.UUID, sourceTenantID *uuid.UUID, status string, page pagination.Page) (*pagination.List[*models.CommissionOverride], error) {
+	query, err := repository.OverrideKeys.Query(page)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.ListOverrides(schema, tenantID, sourceTenantID, status, query)
+}
+
+// flowOverride records or updates the override the parent of a sub-agent tenant
+// earns on a commission. Failures are logged: the child's commission stands on its
+// own and the override is brought up to date on the commission's next change.
+func (s *CommissionService) flowOverride(commission *models.Commission) {
+	if s.tenants == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tenant, err := s.tenants.ByID(ctx, commission.TenantID)
+	if err != nil {
+		fmt.Printf("Warning: Failed to resolve tenant %s for commission override: %v\n", commission.TenantID, err)
+		return
+	}
+	if !tenant.HasParent() || tenant.ParentOverrideRate == nil {
+		return
+	}
+
+	parent, err := s.tenants.ByID(ctx, *tenant.ParentTenantID)
+	if err != nil {
+		fmt.Printf("Warning: Failed to resolve parent tenant %s for commission override: %v\n", *tenant.ParentTenantID, err)
+		return
+	}
+
+	override := &models.CommissionOverride{
+		TenantID:             parent.ID,
+		SourceTenantID:       commission.TenantID,
+		SourceCommissionID:   commission.ID,
+		SourceRegistrationID: commission.RegistrationID,
+		RegistrationFee:      commission.RegistrationFee,
+		OverrideRate:         *tenant.ParentOverrideRate,
+		OverrideAmount:       s.calculateCommissionAmount(commission.RegistrationFee, *tenant.ParentOverrideRate),
+		Currency:             commission.Currency,
+		Status:               commission.Status,
+	}
+
+	if err := s.repo.UpsertOverride(parent.TenantSchema(), override); err != nil {
+		fmt.Printf("Warning: Failed to record commission override for tenant %s: %v\n", parent.ID, err)
+		return
+	}
+
+	if err := s.publishEvent("commission.override."+override.Status, override); err != nil {
+		fmt.Printf("Warning: Failed to publish event: %v\n", err)
+	}
+}
+
 // calculateCommissionAmount calculates commission amount from fee and rate
 func (s *CommissionService) calculateCommissionAmount(registrationFee, commissionRate float64) float64 {
 	// Calculate commission: fee * (rate / 100)
@@ -2,13 +2,13 @@ package handlers
 
 import (
 	"net/http"
-	"strconv"
 	"time"
 
 	"github.com/comply360/document-service/internal/services"
 	"github.com/comply360/shared/errors"
 	sharedmiddleware "github.com/comply360/shared/middleware"
 	"github.com/comply360/shared/models"
+	"github.com/comply360/shared/pagination"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
@@ -211,8 +211,11 @@ func (h *DocumentHandler) ListDocuments(c *gin.Context) {
 	tenantID, _ := sharedmiddleware.GetTenantID(c)
 
 	// Parse query parameters
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(errors.ErrInvalidInput, err.Error()))
+		return
+	}
 	status := c.Query("status")
 	documentType := c.Query("document_type")
 
@@ -225,8 +228,12 @@ func (h *DocumentHandler) ListDocuments(c *gin.Context) {
 	}
 
 	// Get documents
-	documents, total, err := h.service.ListDocuments(schema.(string), tenantID, registrationID, offset, limit, status, documentType)
+	documents, err := h.service.ListDocuments(schema.(string), tenantID, registrationID, status, documentType, page)
 	if err != nil {
+		if pagination.IsRequestError(err) {
+			c.JSON(http.StatusBadRequest, errors.NewAPIError(errors.ErrInvalidInput, err.Error()))
+			return
+		}
 		c.JSON(http.StatusInternalServerError, errors.NewAPIErrorWithDetails(
 			errors.ErrInternalServer,
 			"Failed to list documents",
@@ -235,12 +242,7 @@ func (h *DocumentHandler) ListDocuments(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data":   documents,
-		"total":  total,
-		"offset": offset,
-		"limit":  limit,
-	})
+	pagination.Respond(c, documents, page)
 }
 
 // UpdateDocument handles PUT /documents/:id
@@ -6,6 +6,8 @@ import (
 	"fmt"
 
 	"github.com/comply360/shared/models"
+	"github.com/comply360/shared/pagination"
+	"github.com/comply360/shared/tenancy"
 	"github.com/google/uuid"
 )
 
@@ -33,21 +35,23 @@ func (r *DocumentRepository) Create(schema string, document *models.Document) er
 		return fmt.Errorf("failed to marshal metadata: %w", err)
 	}
 
-	return r.db.QueryRow(
-		query,
-		document.TenantID,
-		document.RegistrationID,
-		document.ClientID,
-		document.UploadedBy,
-		document.DocumentType,
-		document.FileName,
-		document.FileSize,
-		document.MimeType,
-		document.StoragePath,
-		document.StorageProvider,
-		document.Status,
-		metadataJSON,
-	).Scan(&document.ID, &document.CreatedAt, &document.UpdatedAt)
+	return tenancy.WithTenant(r.db, document.TenantID, func(tx *sql.Tx) error {
+		return tx.QueryRow(
+			query,
+			document.TenantID,
+			document.RegistrationID,
+			document.ClientID,
+			document.UploadedBy,
+			document.DocumentType,
+			document.FileName,
+			document.FileSize,
+			document.MimeType,
+			document.StoragePath,
+			document.StorageProvider,
+			document.Status,
+			metadataJSON,
+		).Scan(&document.ID, &document.CreatedAt, &document.UpdatedAt)
+	})
 }
 
 // GetByID retrieves a document by ID
@@ -66,31 +70,33 @@ func (r *DocumentRepository) GetByID(schema string, tenantID, documentID uuid.UU
 	document := &models.Document{}
 	var metadataJSON []byte
 
-	err := r.db.QueryRow(query, documentID, tenantID).Scan(
-		&document.ID,
-		&document.TenantID,
-		&document.RegistrationID,
-		&document.ClientID,
-		&document.UploadedBy,
-		&document.DocumentType,
-		&document.FileName,
-		&document.FileSize,
-		&document.MimeType,
-		&document.StoragePath,
-		&document.StorageProvider,
-		&document.Status,
-		&document.VerifiedAt,
-		&document.VerifiedBy,
-		&document.OCRProcessed,
-		&document.OCRText,
-		&document.AIVerified,
-		&document.AIVerificationScore,
-		&document.AIVerificationNotes,
-		&document.CreatedAt,
-		&document.UpdatedAt,
-		&document.DeletedAt,
-		&metadataJSON,
-	)
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		return tx.QueryRow(query, documentID, tenantID).Scan(
+			&document.ID,
+			&document.TenantID,
+			&document.RegistrationID,
+			&document.ClientID,
+			&document.UploadedBy,
+			&document.DocumentType,
+			&document.FileName,
+			&document.FileSize,
+			&document.MimeType,
+			&document.StoragePath,
+			&document.StorageProvider,
+			&document.Status,
+			&document.VerifiedAt,
+			&document.VerifiedBy,
+			&document.OCRProcessed,
+			&document.OCRText,
+			&document.AIVerified,
+			&document.AIVerificationScore,
+			&document.AIVerificationNotes,
+			&document.CreatedAt,
+			&document.UpdatedAt,
+			&document.DeletedAt,
+			&metadataJSON,
+		)
+	})
 
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("document not found")
@@ -109,8 +115,21 @@ func (r *DocumentRepository) GetByID(schema string, tenantID, documentID uuid.UU
 	return document, nil
 }
 
-// List retrieves documents with pagination and filters
-func (r *DocumentRepository) List(schema string, tenantID uuid.UUID, registrationID *uuid.UUID, offset, limit int, status, documentType string) ([]*models.Document, int, error) {
+// DocumentKeys are the fields document lists sort by
+var DocumentKeys = &pagination.Keys[*models.Document]{
+	ID: func(d *models.Document) uuid.UUID { return d.ID },
+	Columns: map[string]pagination.Column[*models.Document]{
+		"created_at": {SQL: "created_at", Value: func(d *models.Document) interface{} { return d.CreatedAt }},
+		"updated_at": {SQL: "updated_at", Value: func(d *models.Document) interface{} { return d.UpdatedAt }},
+		"file_name":  {SQL: "file_name", Value: func(d *models.Document) interface{} { return d.FileName }},
+		"file_size":  {SQL: "file_size", Value: func(d *models.Document) interface{} { return d.FileSize }},
+		"status":     {SQL: "status", Value: func(d *models.Document) interface{} { return d.Status }},
+	},
+	Default: "-created_at",
+}
+
+// List retrieves a page of documents after the page's cursor, with optional filters
+func (r *DocumentRepository) List(schema string, tenantID uuid.UUID, registrationID *uuid.UUID, status, documentType string, page *pagination.Query[*models.Document]) (*pagination.List[*models.Document], error) {
 	// Build query with optional filters
 	whereClause := "WHERE tenant_id = $1 AND deleted_at IS NULL"
 	args := []interface{}{tenantID}
@@ -134,23 +153,36 @@ func (r *DocumentRepository) List(schema string, tenantID uuid.UUID, registratio
 		args = append(args, documentType)
 	}
 
-	// Count total
+	// Count total, only when asked for
 	countQuery := fmt.Sprintf(`
 		SELECT COUNT(*) FROM %s.documents %s
 	`, schema, whereClause)
+	countArgs := args
 
-	var total int
-	if err := r.db.QueryRow(countQuery, args...).Scan(&total); err != nil {
-		return nil, 0, fmt.Errorf("failed to count documents: %w", err)
+	// Continue after the cursor
+	if keyset, values := page.Keyset(argCount + 1); keyset != "" {
+		whereClause += " AND " + keyset
+		args = append(append([]interface{}{}, args...), values...)
+		argCount += len(values)
 	}
 
-	// Get documents
-	argCount++
-	limitArg := argCount
-	argCount++
-	offsetArg := argCount
+	var total *int
+	var documents []*models.Document
 
-	query := fmt.Sprintf(`
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		if page.IncludeTotal() {
+			var count int
+			if err := tx.QueryRow(countQuery, countArgs...).Scan(&count); err != nil {
+				return fmt.Errorf("failed to count documents: %w", err)
+			}
+			total = &count
+		}
+
+		// Get documents
+		argCount++
+		limitArg := argCount
+
+		query := fmt.Sprintf(`
 		SELECT id, tenant_id, registration_id, client_id, uploaded_by,
 			document_type, file_name, file_size, mime_type,
 			storage_path, storage_provider, status,
@@ -159,64 +191,69 @@ func (r *DocumentRepository) List(schema string, tenantID uuid.UUID, registratio
 			created_at, updated_at, deleted_at, metadata
 		FROM %s.documents
 		%s
-		ORDER BY created_at DESC
-		LIMIT $%d OFFSET $%d
-	`, schema, whereClause, limitArg, offsetArg)
+		ORDER BY %s
+		LIMIT $%d
+	`, schema, whereClause, page.OrderBy(), limitArg)
 
-	args = append(args, limit, offset)
-
-	rows, err := r.db.Query(query, args...)
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to query documents: %w", err)
-	}
-	defer rows.Close()
-
-	var documents []*models.Document
-	for rows.Next() {
-		document := &models.Document{}
-		var metadataJSON []byte
-
-		err := rows.Scan(
-			&document.ID,
-			&document.TenantID,
-			&document.RegistrationID,
-			&document.ClientID,
-			&document.UploadedBy,
-			&document.DocumentType,
-			&document.FileName,
-			&document.FileSize,
-			&document.MimeType,
-			&document.StoragePath,
-			&document.StorageProvider,
-			&document.Status,
-			&document.VerifiedAt,
-			&document.VerifiedBy,
-			&document.OCRProcessed,
-			&document.OCRText,
-			&document.AIVerified,
-			&document.AIVerificationScore,
-			&document.AIVerificationNotes,
-			&document.CreatedAt,
-			&document.UpdatedAt,
-			&document.DeletedAt,
-			&metadataJSON,
-		)
+		args = append(args, page.Limit())
 
+		rows, err := tx.Query(query, args...)
 		if err != nil {
-			return nil, 0, fmt.Errorf("failed to scan document: %w", err)
+			return fmt.Errorf("failed to query documents: %w", err)
 		}
+		defer rows.Close()
+
+		for rows.Next() {
+			document := &models.Document{}
+			var metadataJSON []byte
+
+			err := rows.Scan(
+				&document.ID,
+				&document.TenantID,
+				&document.RegistrationID,
+				&document.ClientID,
+				&document.UploadedBy,
+				&document.DocumentType,
+				&document.FileName,
+				&document.FileSize,
+				&document.MimeType,
+				&document.StoragePath,
+				&document.StorageProvider,
+				&document.Status,
+				&document.VerifiedAt,
+				&document.VerifiedBy,
+				&document.OCRProcessed,
+				&document.OCRText,
+				&document.AIVerified,
+				&document.AIVerificationScore,
+				&document.AIVerificationNotes,
+				&document.CreatedAt,
+				&document.UpdatedAt,
+				&document.DeletedAt,
+				&metadataJSON,
+			)
+
+			if err != nil {
+				return fmt.Errorf("failed to scan document: %w", err)
+			}
 
-		// Unmarshal metadata
-		if len(metadataJSON) > 0 {
-			if err := json.Unmarshal(metadataJSON, &document.Metadata); err != nil {
-				return nil, 0, fmt.Errorf("failed to unmarshal metadata: %w", err)
+			// Unmarshal metadata
+			if len(metadataJSON) > 0 {
+				if err := json.Unmarshal(metadataJSON, &document.Metadata); err != nil {
+					return fmt.Errorf("failed to unmarshal metadata: %w", err)
+				}
 			}
+
+			documents = append(documents, document)
 		}
 
-		documents = append(documents, document)
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return documents, total, nil
+	return page.List(documents, total), nil
 }
 
 // Update updates a document
@@ -243,22 +280,27 @@ func (r *DocumentRepository) Update(schema string, document *models.Document) er
 		return fmt.Errorf("failed to marshal metadata: %w", err)
 	}
 
-	result, err := r.db.Exec(
-		query,
-		document.DocumentType,
-		document.FileName,
-		document.Status,
-		document.VerifiedAt,
-		document.VerifiedBy,
-		document.OCRProcessed,
-		document.OCRText,
-		document.AIVerified,
-		document.AIVerificationScore,
-		document.AIVerificationNotes,
-		metadataJSON,
-		document.ID,
-		document.TenantID,
-	)
+	var result sql.Result
+	err = tenancy.WithTenant(r.db, document.TenantID, func(tx *sql.Tx) error {
+		var err error
+		result, err = tx.Exec(
+			query,
+			document.DocumentType,
+			document.FileName,
+			document.Status,
+			document.VerifiedAt,
+			document.VerifiedBy,
+			document.OCRProcessed,
+			document.OCRText,
+			document.AIVerified,
+			document.AIVerificationScore,
+			document.AIVerificationNotes,
+			metadataJSON,
+			document.ID,
+			document.TenantID,
+		)
+		return err
+	})
 
 	if err != nil {
 		return fmt.Errorf("failed to update document: %w", err)
@@ -284,7 +326,12 @@ func (r *DocumentRepository) Delete(schema string, tenantID, documentID uuid.UUI
 		WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL
 	`, schema)
 
-	result, err := r.db.Exec(query, documentID, tenantID)
+	var result sql.Result
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		var err error
+		result, err = tx.Exec(query, documentID, tenantID)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("failed to delete document: %w", err)
 	}
@@ -312,7 +359,12 @@ func (r *DocumentRepository) UpdateStatus(schema string, tenantID, documentID uu
 		WHERE id = $4 AND tenant_id = $5 AND deleted_at IS NULL
 	`, schema)
 
-	result, err := r.db.Exec(query, status, status, verifiedBy, documentID, tenantID)
+	var result sql.Result
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		var err error
+		result, err = tx.Exec(query, status, status, verifiedBy, documentID, tenantID)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("failed to update document status: %w", err)
 	}
@@ -331,6 +383,21 @@ func (r *DocumentRepository) UpdateStatus(schema string, tenantID, documentID uu
 
 // ListByRegistration retrieves all documents for a specific registration
 func (r *DocumentRepository) ListByRegistration(schema string, tenantID, registrationID uuid.UUID) ([]*models.Document, error) {
-	documents, _, err := r.List(schema, tenantID, &registrationID, 0, 1000, "", "")
-	return documents, err
+	documents := []*models.Document{}
+	request := pagination.Page{Limit: pagination.MaxLimit}
+	for {
+		page, err := DocumentKeys.Query(request)
+		if err != nil {
+			return nil, err
+		}
+		list, err := r.List(schema, tenantID, &registrationID, "", "", page)
+		if err != nil {
+			return nil, err
+		}
+		documents = append(documents, list.Data...)
+		if !list.HasMore {
+			return documents, nil
+		}
+		request.Cursor = list.NextCursor
+	}
 }
@@ -10,6 +10,7 @@ import (
 
 	"github.com/comply360/document-service/internal/repository"
 	"github.com/comply360/shared/models"
+	"github.com/comply360/shared/pagination"
 	"github.com/google/uuid"
 	"github.com/minio/minio-go/v7"
 	amqp "github.com/rabbitmq/amqp091-go"
@@ -155,20 +156,14 @@ func (s *DocumentService) GetDocumentDownloadURL(document *models.Document, expi
 	return presignedURL.String(), nil
 }
 
-// ListDocuments retrieves documents with pagination and filters
-func (s *DocumentService) ListDocuments(schema string, tenantID uuid.UUID, registrationID *uuid.UUID, offset, limit int, status, documentType string) ([]*models.Document, int, error) {
-	// Validate pagination parameters
-	if limit <= 0 {
-		limit = 20
-	}
-	if limit > 100 {
-		limit = 100
-	}
-	if offset < 0 {
-		offset = 0
+// ListDocuments retrieves a page of documents with filters
+func (s *DocumentService) ListDocuments(schema string, tenantID uuid.UUID, registrationID *uuid.UUID, status, documentType string, page pagination.Page) (*pagination.List[*models.Document], error) {
+	query, err := repository.DocumentKeys.Query(page)
+	if err != nil {
+		return nil, err
 	}
 
-	return s.repo.List(schema, tenantID, registrationID, offset, limit, status, documentType)
+	return s.repo.List(schema, tenantID, registrationID, status, documentType, query)
 }
 
 // UpdateDocument updates a document
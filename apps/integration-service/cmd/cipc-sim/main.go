@@ -0,0 +1,60 @@
+// Command cipc-sim serves a simulated CIPC API for local development and
+// integration tests. Point the integration service at it with CIPC_URL.
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/comply360/integration-service/internal/cipcsim"
+)
+
+func main() {
+	port := getEnv("CIPC_SIM_PORT", "8095")
+
+	fixtures := cipcsim.DefaultFixtures()
+	if path := os.Getenv("CIPC_SIM_FIXTURES"); path != "" {
+		loaded, err := cipcsim.LoadFixtures(path)
+		if err != nil {
+			log.Fatalf("Failed to load fixtures: %v", err)
+		}
+		fixtures = loaded
+		log.Printf("Loaded fixtures from %s", path)
+	}
+
+	sim := cipcsim.New(cipcsim.Config{
+		Username:    getEnv("CIPC_SIM_USERNAME", "sim"),
+		Password:    getEnv("CIPC_SIM_PASSWORD", "sim"),
+		APIKey:      os.Getenv("CIPC_SIM_API_KEY"),
+		TokenTTL:    time.Duration(getEnvInt("CIPC_SIM_TOKEN_TTL_SECONDS", 3600)) * time.Second,
+		RateLimit:   getEnvInt("CIPC_SIM_RATE_LIMIT", 0),
+		DecideAfter: getEnvInt("CIPC_SIM_DECIDE_AFTER", 1),
+	}, fixtures)
+
+	addr := fmt.Sprintf(":%s", port)
+	log.Printf("CIPC simulator starting on %s with %d companies and %d scenarios",
+		addr, len(fixtures.Companies), len(fixtures.Scenarios))
+	if err := http.ListenAndServe(addr, sim); err != nil {
+		log.Fatalf("Failed to start server: %v", err)
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		var intValue int
+		if _, err := fmt.Sscanf(value, "%d", &intValue); err == nil {
+			return intValue
+		}
+	}
+	return defaultValue
+}
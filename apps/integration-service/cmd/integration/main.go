@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/comply360/integration-service/internal/adapters"
 	"github.com/comply360/integration-service/internal/consumers"
@@ -34,6 +35,10 @@ func main() {
 		APIKey:   getEnv("CIPC_API_KEY", ""),
 		Username: getEnv("CIPC_USERNAME", ""),
 		Password: getEnv("CIPC_PASSWORD", ""),
+
+		MaxRetries:   getEnvInt("CIPC_MAX_RETRIES", 3),
+		RetryBackoff: time.Duration(getEnvInt("CIPC_RETRY_BACKOFF_MS", 500)) * time.Millisecond,
+		CacheTTL:     time.Duration(getEnvInt("CIPC_CACHE_TTL_SECONDS", 600)) * time.Second,
 	}
 
 	// Initialize Odoo client
@@ -46,11 +51,13 @@ func main() {
 		log.Println("Successfully connected to Odoo")
 	}
 
-	// Initialize CIPC client
+	// Initialize registries. DCIP has no API, so Zimbabwe always uses the fake.
+	registries := []adapters.Registry{adapters.NewDCIPFake(nil)}
 	cipcClient, err := adapters.NewCIPCClient(cipcConfig)
 	if err != nil {
 		log.Printf("Warning: Failed to initialize CIPC client: %v", err)
 	} else {
+		registries = append(registries, cipcClient)
 		log.Println("Successfully initialized CIPC client")
 	}
 
@@ -59,7 +66,7 @@ func main() {
 
 	// Initialize handlers
 	odooHandler := handlers.NewOdooHandler(odooService)
-	var cipcHandler *handlers.CIPCHandler
+	var registryHandler *handlers.RegistryHandler
 
 	// Connect to RabbitMQ
 	rabbitConn, err := amqp.Dial(rabbitURL)
@@ -69,16 +76,14 @@ func main() {
 	} else {
 		log.Println("Connected to RabbitMQ")
 
-		// Initialize CIPC service
-		if cipcClient != nil {
-			cipcService, err := services.NewCIPCService(cipcClient, rabbitConn)
-			if err != nil {
-				log.Printf("Warning: Failed to create CIPC service: %v", err)
-			} else {
-				cipcHandler = handlers.NewCIPCHandler(cipcService)
-				defer cipcService.Close()
-				log.Println("CIPC service initialized successfully")
-			}
+		// Initialize registry service
+		registryService, err := services.NewRegistryService(registries, rabbitConn)
+		if err != nil {
+			log.Printf("Warning: Failed to create registry service: %v", err)
+		} else {
+			registryHandler = handlers.NewRegistryHandler(registryService)
+			defer registryService.Close()
+			log.Println("Registry service initialized successfully")
 		}
 
 		// Initialize and start Odoo sync consumer
@@ -96,7 +101,7 @@ func main() {
 	}
 
 	// Setup router
-	r := setupRouter(odooHandler, cipcHandler)
+	r := setupRouter(odooHandler, registryHandler, cipcClient != nil)
 
 	// Start HTTP server in goroutine
 	go func() {
@@ -115,7 +120,7 @@ func main() {
 	log.Println("Shutting down Integration Service...")
 }
 
-func setupRouter(odooHandler *handlers.OdooHandler, cipcHandler *handlers.CIPCHandler) *gin.Engine {
+func setupRouter(odooHandler *handlers.OdooHandler, registryHandler *handlers.RegistryHandler, cipcConfigured bool) *gin.Engine {
 	// Set Gin mode
 	if os.Getenv("APP_ENV") == "production" {
 		gin.SetMode(gin.ReleaseMode)
@@ -165,16 +170,29 @@ func setupRouter(odooHandler *handlers.OdooHandler, cipcHandler *handlers.CIPCHa
 			odoo.POST("/test-connection", odooHandler.TestConnection)
 		}
 
-		// CIPC integration routes
+		// Companies registry routes, by jurisdiction
+		registries := api.Group("/registries")
+		{
+			if registryHandler != nil {
+				registryHandler.SetupRoutes(registries)
+			} else {
+				registries.GET("", placeholderHandler("Companies registries - not configured"))
+				registries.Any("/:jurisdiction/*path", placeholderHandler("Companies registry - not configured"))
+			}
+		}
+
+		// CIPC integration routes, the South African registry's
 		cipc := api.Group("/cipc")
 		{
-			if cipcHandler != nil {
-				cipcHandler.SetupRoutes(cipc)
+			if registryHandler != nil && cipcConfigured {
+				registryHandler.SetupJurisdictionRoutes(cipc, "ZA")
 			} else {
 				cipc.POST("/search", placeholderHandler("CIPC search - not configured"))
 				cipc.POST("/verify", placeholderHandler("CIPC verify - not configured"))
 				cipc.GET("/company/:registration_number", placeholderHandler("CIPC company lookup - not configured"))
 				cipc.GET("/status", placeholderHandler("CIPC status - not configured"))
+				cipc.POST("/name-reservations", placeholderHandler("CIPC name reservation - not configured"))
+				cipc.GET("/name-reservations/:reference", placeholderHandler("CIPC name reservation - not configured"))
 			}
 		}
 
@@ -228,15 +246,19 @@ func setupRouter(odooHandler *handlers.OdooHandler, cipcHandler *handlers.CIPCHa
 
 		// Overall integration status
 		api.GET("/status", func(c *gin.Context) {
-			cipcStatus := "not_configured"
-			if cipcHandler != nil {
-				cipcStatus = "connected"
+			cipcStatus, dcipStatus := "not_configured", "not_configured"
+			if registryHandler != nil {
+				dcipStatus = "simulated"
+				if cipcConfigured {
+					cipcStatus = "connected"
+				}
 			}
 			c.JSON(200, gin.H{
 				"service": "integration-service",
 				"integrations": gin.H{
 					"odoo":    "connected",
 					"cipc":    cipcStatus,
+					"dcip":    dcipStatus,
 					"sars":    "not_configured",
 					"stripe":  "not_configured",
 					"payfast": "not_configured",
@@ -262,3 +284,13 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		var intValue int
+		if _, err := fmt.Sscanf(value, "%d", &intValue); err == nil {
+			return intValue
+		}
+	}
+	return defaultValue
+}
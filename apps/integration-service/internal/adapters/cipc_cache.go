@@ -0,0 +1,68 @@
+package adapters
+
+import (
+	"sync"
+	"time"
+)
+
+// maxCachedResponses bounds the CIPC response cache
+const maxCachedResponses = 1000
+
+// responseCache holds CIPC responses for a fixed time
+type responseCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]cachedResponse
+}
+
+type cachedResponse struct {
+	body      []byte
+	expiresAt time.Time
+}
+
+func newResponseCache(ttl time.Duration) *responseCache {
+	return &responseCache{ttl: ttl, entries: map[string]cachedResponse{}}
+}
+
+// get returns a response that has not expired
+func (c *responseCache) get(key string) ([]byte, bool) {
+	if c.ttl <= 0 {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.body, true
+}
+
+// put caches a response. When the cache is full, expired responses are
+// dropped first and then whichever the map yields.
+func (c *responseCache) put(key string, body []byte) {
+	if c.ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.entries) >= maxCachedResponses {
+		now := time.Now()
+		for k, entry := range c.entries {
+			if now.After(entry.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+		for k := range c.entries {
+			if len(c.entries) < maxCachedResponses {
+				break
+			}
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = cachedResponse{body: body, expiresAt: time.Now().Add(c.ttl)}
+}
@@ -7,173 +7,329 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"net/url"
+	"strings"
+	"sync"
 	"time"
 )
 
+// maxRetryWait caps how long a retry waits, whatever CIPC asks for
+const maxRetryWait = 30 * time.Second
+
 // CIPCConfig holds CIPC API connection configuration
 type CIPCConfig struct {
 	BaseURL  string
 	APIKey   string
 	Username string
 	Password string
+
+	// Timeout bounds each HTTP request; defaults to 30 seconds
+	Timeout time.Duration
+	// MaxRetries is how many times a throttled or failed call is retried
+	MaxRetries int
+	// RetryBackoff is the wait before the first retry, doubling with each
+	// retry up to MaxRetryWait; defaults to 500 milliseconds
+	RetryBackoff time.Duration
+	// MaxRetryWait caps the wait between retries, including waits CIPC asks
+	// for with Retry-After; defaults to 30 seconds
+	MaxRetryWait time.Duration
+	// CacheTTL is how long company lookups are cached; zero disables caching
+	CacheTTL time.Duration
 }
 
-// CIPCClient handles REST API communication with CIPC
+// CIPCClient is the South African registry, handling REST API communication
+// with CIPC. It authenticates with a bearer token, retries throttled and
+// failed calls with exponential backoff, classifies failures as
+// RegistryErrors and caches company lookups.
 type CIPCClient struct {
 	config     *CIPCConfig
 	httpClient *http.Client
-	token      string
+	cache      *responseCache
+
+	mu          sync.Mutex
+	token       string
 	tokenExpiry time.Time
 }
 
-// CIPCCompanySearchResult represents a company search result from CIPC
-type CIPCCompanySearchResult struct {
-	RegistrationNumber string `json:"registration_number"`
-	CompanyName        string `json:"company_name"`
-	Status             string `json:"status"`
-	Type               string `json:"type"`
-	RegistrationDate   string `json:"registration_date"`
-}
-
-// CIPCCompanyDetails represents detailed company information from CIPC
-type CIPCCompanyDetails struct {
-	RegistrationNumber string   `json:"registration_number"`
-	CompanyName        string   `json:"company_name"`
-	Status             string   `json:"status"`
-	Type               string   `json:"type"`
-	RegistrationDate   string   `json:"registration_date"`
-	BusinessAddress    string   `json:"business_address"`
-	PostalAddress      string   `json:"postal_address"`
-	Directors          []string `json:"directors"`
-	EntityNumber       string   `json:"entity_number"`
-}
-
-// CIPCValidationResult represents the result of a company validation
-type CIPCValidationResult struct {
-	Valid              bool   `json:"valid"`
+// cipcToken is the response to a token request
+type cipcToken struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+// cipcSearchResponse is the response to a company search
+type cipcSearchResponse struct {
+	Results []CompanySearchResult `json:"results"`
+}
+
+// cipcStatusResponse is the response to a company status check
+type cipcStatusResponse struct {
 	RegistrationNumber string `json:"registration_number"`
-	CompanyName        string `json:"company_name"`
 	Status             string `json:"status"`
-	Message            string `json:"message"`
 }
 
-// NewCIPCClient creates a new CIPC API client
+// NewCIPCClient creates a new CIPC API client. It authenticates on its first
+// call, so the service can start while CIPC is unreachable.
 func NewCIPCClient(config *CIPCConfig) (*CIPCClient, error) {
+	if config.BaseURL == "" {
+		return nil, fmt.Errorf("CIPC base URL is required")
+	}
+	if _, err := url.Parse(config.BaseURL); err != nil {
+		return nil, fmt.Errorf("invalid CIPC base URL: %w", err)
+	}
+
+	settings := *config
+	settings.BaseURL = strings.TrimRight(config.BaseURL, "/")
+	if settings.Timeout <= 0 {
+		settings.Timeout = 30 * time.Second
+	}
+	if settings.RetryBackoff <= 0 {
+		settings.RetryBackoff = 500 * time.Millisecond
+	}
+	if settings.MaxRetryWait <= 0 {
+		settings.MaxRetryWait = maxRetryWait
+	}
+
 	client := &CIPCClient{
-		config: config,
+		config: &settings,
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout: settings.Timeout,
 		},
+		cache: newResponseCache(settings.CacheTTL),
 	}
 
-	// Note: In production, this would authenticate with CIPC API
-	// For development, we'll simulate the authentication
-	log.Printf("CIPC Client initialized for: %s", config.BaseURL)
+	log.Printf("CIPC Client initialized for: %s", settings.BaseURL)
 	return client, nil
 }
 
-// authenticate gets an authentication token from CIPC
+// authenticate gets an authentication token from CIPC. The token is renewed a
+// minute before it expires.
 func (c *CIPCClient) authenticate() error {
-	// In production, this would call CIPC's authentication endpoint
-	// For now, we'll simulate it
-	c.token = "simulated_cipc_token"
-	c.tokenExpiry = time.Now().Add(1 * time.Hour)
+	body, err := c.send(http.MethodPost, "/auth/token", map[string]string{
+		"username": c.config.Username,
+		"password": c.config.Password,
+	}, "")
+	if err != nil {
+		return err
+	}
+
+	var token cipcToken
+	if err := json.Unmarshal(body, &token); err != nil || token.AccessToken == "" {
+		return &RegistryError{Registry: cipcRegistry, Kind: RegistryErrorUnexpected, Err: fmt.Errorf("invalid token response")}
+	}
+
+	lifetime := time.Duration(token.ExpiresIn)*time.Second - time.Minute
+	if lifetime < 0 {
+		lifetime = time.Duration(token.ExpiresIn) * time.Second / 2
+	}
+	c.token = token.AccessToken
+	c.tokenExpiry = time.Now().Add(lifetime)
 	return nil
 }
 
 // ensureAuthenticated checks if token is valid and refreshes if needed
-func (c *CIPCClient) ensureAuthenticated() error {
+func (c *CIPCClient) ensureAuthenticated() (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if c.token == "" || time.Now().After(c.tokenExpiry) {
-		return c.authenticate()
+		if err := c.authenticate(); err != nil {
+			return "", err
+		}
 	}
-	return nil
+	return c.token, nil
 }
 
-// SearchCompanyByName searches for companies by name
-func (c *CIPCClient) SearchCompanyByName(companyName string) ([]CIPCCompanySearchResult, error) {
-	if err := c.ensureAuthenticated(); err != nil {
-		return nil, err
+// invalidateToken drops a token CIPC refused, unless another call has already
+// replaced it
+func (c *CIPCClient) invalidateToken(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token == token {
+		c.token = ""
 	}
+}
+
+// Jurisdiction returns ZA
+func (c *CIPCClient) Jurisdiction() string {
+	return "ZA"
+}
+
+// Name returns CIPC
+func (c *CIPCClient) Name() string {
+	return cipcRegistry
+}
 
-	// In production, this would call CIPC API
-	// For development, we'll return simulated results
+// SearchCompanyByName searches for companies by name
+func (c *CIPCClient) SearchCompanyByName(companyName string) ([]CompanySearchResult, error) {
 	log.Printf("CIPC: Searching for company: %s", companyName)
 
-	// Simulate API response
-	results := []CIPCCompanySearchResult{
-		{
-			RegistrationNumber: "2024/999999/07",
-			CompanyName:        companyName,
-			Status:             "Active",
-			Type:               "Private Company",
-			RegistrationDate:   "2024-01-15",
-		},
+	var response cipcSearchResponse
+	query := url.Values{"name": {companyName}}
+	if err := c.get("/companies?"+query.Encode(), true, &response); err != nil {
+		return nil, err
+	}
+	if response.Results == nil {
+		response.Results = []CompanySearchResult{}
 	}
 
-	return results, nil
+	return response.Results, nil
 }
 
 // GetCompanyDetails retrieves detailed information about a company
-func (c *CIPCClient) GetCompanyDetails(registrationNumber string) (*CIPCCompanyDetails, error) {
-	if err := c.ensureAuthenticated(); err != nil {
+func (c *CIPCClient) GetCompanyDetails(registrationNumber string) (*CompanyDetails, error) {
+	log.Printf("CIPC: Getting details for registration: %s", registrationNumber)
+
+	var details CompanyDetails
+	query := url.Values{"registration_number": {registrationNumber}}
+	if err := c.get("/companies/details?"+query.Encode(), true, &details); err != nil {
 		return nil, err
 	}
 
-	log.Printf("CIPC: Getting details for registration: %s", registrationNumber)
+	return &details, nil
+}
 
-	// In production, this would call CIPC API
-	// For development, we'll return simulated details
-	details := &CIPCCompanyDetails{
-		RegistrationNumber: registrationNumber,
-		CompanyName:        "Sample Company (Pty) Ltd",
-		Status:             "Active",
-		Type:               "Private Company",
-		RegistrationDate:   "2024-01-15",
-		BusinessAddress:    "123 Main Street, Johannesburg, 2000",
-		PostalAddress:      "PO Box 12345, Johannesburg, 2000",
-		Directors:          []string{"John Doe", "Jane Smith"},
-		EntityNumber:       "K2024999999",
+// ValidateCompany validates a company's registration status
+func (c *CIPCClient) ValidateCompany(registrationNumber string) (*ValidationResult, error) {
+	log.Printf("CIPC: Validating registration: %s", registrationNumber)
+
+	var result ValidationResult
+	query := url.Values{"registration_number": {registrationNumber}}
+	if err := c.get("/companies/validate?"+query.Encode(), true, &result); err != nil {
+		return nil, err
 	}
 
-	return details, nil
+	return &result, nil
 }
 
-// ValidateCompany validates a company's registration status
-func (c *CIPCClient) ValidateCompany(registrationNumber string) (*CIPCValidationResult, error) {
-	if err := c.ensureAuthenticated(); err != nil {
+// CheckCompanyStatus checks the current status of a company, such as Active,
+// Deregistered or In Liquidation
+func (c *CIPCClient) CheckCompanyStatus(registrationNumber string) (string, error) {
+	log.Printf("CIPC: Checking status for registration: %s", registrationNumber)
+
+	var response cipcStatusResponse
+	query := url.Values{"registration_number": {registrationNumber}}
+	if err := c.get("/companies/status?"+query.Encode(), true, &response); err != nil {
+		return "", err
+	}
+
+	return response.Status, nil
+}
+
+// ReserveNames lodges a name reservation with CIPC. CIPC examines the names
+// later; the reservation comes back pending. CIPC returns the existing
+// reservation when the customer reference has been used before, so a retried
+// request does not lodge twice.
+func (c *CIPCClient) ReserveNames(request *NameReservationRequest) (*NameReservation, error) {
+	log.Printf("CIPC: Reserving %d name(s) for: %s", len(request.Names), request.CustomerReference)
+
+	body, err := c.makeRequest(http.MethodPost, "/name-reservations", request)
+	if err != nil {
 		return nil, err
 	}
 
-	log.Printf("CIPC: Validating registration: %s", registrationNumber)
+	return decodeNameReservation(body)
+}
+
+// GetNameReservation retrieves a name reservation and CIPC's decision on it
+func (c *CIPCClient) GetNameReservation(reference string) (*NameReservation, error) {
+	log.Printf("CIPC: Getting name reservation: %s", reference)
 
-	// In production, this would call CIPC validation API
-	// For development, we'll return simulated validation
-	result := &CIPCValidationResult{
-		Valid:              true,
-		RegistrationNumber: registrationNumber,
-		CompanyName:        "Sample Company (Pty) Ltd",
-		Status:             "Active",
-		Message:            "Company registration is valid and active",
+	body, err := c.makeRequest(http.MethodGet, "/name-reservations/"+url.PathEscape(reference), nil)
+	if err != nil {
+		return nil, err
 	}
 
-	return result, nil
+	return decodeNameReservation(body)
 }
 
-// CheckCompanyStatus checks the current status of a company
-func (c *CIPCClient) CheckCompanyStatus(registrationNumber string) (string, error) {
-	if err := c.ensureAuthenticated(); err != nil {
-		return "", err
+func decodeNameReservation(body []byte) (*NameReservation, error) {
+	var reservation NameReservation
+	if err := json.Unmarshal(body, &reservation); err != nil {
+		return nil, &RegistryError{Registry: cipcRegistry, Kind: RegistryErrorUnexpected, Err: fmt.Errorf("failed to decode name reservation: %w", err)}
+	}
+	if reservation.Reference == "" {
+		return nil, &RegistryError{Registry: cipcRegistry, Kind: RegistryErrorUnexpected, Err: fmt.Errorf("name reservation has no reference")}
 	}
+	return &reservation, nil
+}
 
-	log.Printf("CIPC: Checking status for registration: %s", registrationNumber)
+// get fetches and decodes a GET endpoint, from the cache if allowed
+func (c *CIPCClient) get(endpoint string, cached bool, out interface{}) error {
+	body, ok := c.cache.get(endpoint)
+	if !cached || !ok {
+		var err error
+		if body, err = c.makeRequest(http.MethodGet, endpoint, nil); err != nil {
+			return err
+		}
+		if cached {
+			c.cache.put(endpoint, body)
+		}
+	}
 
-	// In production, this would call CIPC status API
-	// Possible statuses: Active, Deregistered, In Liquidation, etc.
-	return "Active", nil
+	if err := json.Unmarshal(body, out); err != nil {
+		return &RegistryError{Registry: cipcRegistry, Kind: RegistryErrorUnexpected, Err: fmt.Errorf("failed to decode response: %w", err)}
+	}
+	return nil
 }
 
-// makeRequest makes an HTTP request to CIPC API (helper method for production use)
+// makeRequest makes an authenticated request to the CIPC API. Throttled and
+// failed calls are retried with exponential backoff, honouring Retry-After;
+// a refused token is renewed once.
 func (c *CIPCClient) makeRequest(method, endpoint string, body interface{}) ([]byte, error) {
+	for attempt := 0; ; attempt++ {
+		respBody, err := c.authorizedRequest(method, endpoint, body)
+		if err == nil {
+			return respBody, nil
+		}
+
+		cipcErr, ok := err.(*RegistryError)
+		if !ok || !cipcErr.Retryable() || attempt >= c.config.MaxRetries {
+			return nil, err
+		}
+
+		wait := c.backoff(attempt, cipcErr)
+		log.Printf("CIPC: %s %s failed (%v), retrying in %v", method, endpoint, err, wait)
+		time.Sleep(wait)
+	}
+}
+
+// authorizedRequest sends one request with the current token, renewing the
+// token and resending once if CIPC refuses it
+func (c *CIPCClient) authorizedRequest(method, endpoint string, body interface{}) ([]byte, error) {
+	token, err := c.ensureAuthenticated()
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := c.send(method, endpoint, body, token)
+	if RegistryErrorKind(err) != RegistryErrorUnauthorized {
+		return respBody, err
+	}
+
+	c.invalidateToken(token)
+	if token, err = c.ensureAuthenticated(); err != nil {
+		return nil, err
+	}
+	return c.send(method, endpoint, body, token)
+}
+
+// backoff is how long to wait before retrying after a failed attempt
+func (c *CIPCClient) backoff(attempt int, err *RegistryError) time.Duration {
+	wait := err.RetryAfter
+	if wait <= 0 {
+		wait = c.config.RetryBackoff << attempt
+	}
+	if wait > c.config.MaxRetryWait || wait <= 0 {
+		wait = c.config.MaxRetryWait
+	}
+	return wait
+}
+
+// send makes a single HTTP request to the CIPC API
+func (c *CIPCClient) send(method, endpoint string, body interface{}, token string) ([]byte, error) {
 	var reqBody io.Reader
 	if body != nil {
 		jsonBody, err := json.Marshal(body)
@@ -183,17 +339,18 @@ func (c *CIPCClient) makeRequest(method, endpoint string, body interface{}) ([]b
 		reqBody = bytes.NewBuffer(jsonBody)
 	}
 
-	url := c.config.BaseURL + endpoint
-	req, err := http.NewRequest(method, url, reqBody)
+	req, err := http.NewRequest(method, c.config.BaseURL+endpoint, reqBody)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// Set headers
-	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
-	if c.token != "" {
-		req.Header.Set("Authorization", "Bearer "+c.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
 	}
 	if c.config.APIKey != "" {
 		req.Header.Set("X-API-Key", c.config.APIKey)
@@ -201,17 +358,17 @@ func (c *CIPCClient) makeRequest(method, endpoint string, body interface{}) ([]b
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		return nil, &RegistryError{Registry: cipcRegistry, Kind: RegistryErrorUnavailable, Err: fmt.Errorf("request failed: %w", err)}
 	}
 	defer resp.Body.Close()
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return nil, &RegistryError{Registry: cipcRegistry, Kind: RegistryErrorUnavailable, StatusCode: resp.StatusCode, Err: fmt.Errorf("failed to read response: %w", err)}
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(respBody))
+		return nil, classifyResponse(resp, respBody)
 	}
 
 	return respBody, nil
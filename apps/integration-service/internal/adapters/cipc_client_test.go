@@ -0,0 +1,226 @@
+package adapters
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/comply360/integration-service/internal/cipcsim"
+	testhelpers "github.com/comply360/shared/testing"
+)
+
+// simulatedCIPC runs the CIPC simulator and counts the calls made to it
+type simulatedCIPC struct {
+	sim    *cipcsim.Server
+	server *httptest.Server
+	calls  atomic.Int32
+}
+
+func newSimulatedCIPC(t *testing.T, config cipcsim.Config) *simulatedCIPC {
+	if config.Username == "" {
+		config.Username, config.Password = "sim", "sim"
+	}
+
+	s := &simulatedCIPC{sim: cipcsim.New(config, cipcsim.DefaultFixtures())}
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.calls.Add(1)
+		s.sim.ServeHTTP(w, r)
+	}))
+	t.Cleanup(s.server.Close)
+	return s
+}
+
+func (s *simulatedCIPC) client(t *testing.T, config CIPCConfig) *CIPCClient {
+	config.BaseURL = s.server.URL
+	if config.Username == "" {
+		config.Username = "sim"
+	}
+	if config.Password == "" {
+		config.Password = "sim"
+	}
+	if config.RetryBackoff == 0 {
+		config.RetryBackoff = time.Millisecond
+	}
+
+	client, err := NewCIPCClient(&config)
+	testhelpers.AssertNoError(t, err)
+	return client
+}
+
+func TestNewCIPCClientRequiresURL(t *testing.T) {
+	_, err := NewCIPCClient(&CIPCConfig{})
+	testhelpers.AssertError(t, err)
+}
+
+func TestCIPCCompanyLookups(t *testing.T) {
+	cipc := newSimulatedCIPC(t, cipcsim.Config{APIKey: "key"})
+	client := cipc.client(t, CIPCConfig{APIKey: "key"})
+
+	results, err := client.SearchCompanyByName("nkosi")
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 1, len(results))
+	testhelpers.AssertEqual(t, "2015/123456/07", results[0].RegistrationNumber)
+
+	results, err = client.SearchCompanyByName("Nobody Trading")
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertNotNil(t, results, "No matches is an empty list")
+	testhelpers.AssertEqual(t, 0, len(results))
+
+	details, err := client.GetCompanyDetails("2015/123456/07")
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "Nkosi Holdings (Pty) Ltd", details.CompanyName)
+	testhelpers.AssertTrue(t, len(details.Directors) > 0)
+
+	status, err := client.CheckCompanyStatus("2008/011111/23")
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "Deregistered", status)
+
+	validation, err := client.ValidateCompany("2015/123456/07")
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertTrue(t, validation.Valid)
+
+	validation, err = client.ValidateCompany("2012/222222/07")
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertFalse(t, validation.Valid, "A company in liquidation is not valid")
+
+	validation, err = client.ValidateCompany("2020/999999/07")
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertFalse(t, validation.Valid, "An unknown number is invalid, not an error")
+
+	_, err = client.GetCompanyDetails("2020/999999/07")
+	testhelpers.AssertError(t, err)
+	testhelpers.AssertEqual(t, RegistryErrorNotFound, RegistryErrorKind(err))
+}
+
+func TestCIPCClientCachesLookups(t *testing.T) {
+	cipc := newSimulatedCIPC(t, cipcsim.Config{})
+	client := cipc.client(t, CIPCConfig{CacheTTL: time.Minute})
+
+	_, err := client.GetCompanyDetails("2015/123456/07")
+	testhelpers.AssertNoError(t, err)
+	calls := cipc.calls.Load()
+	testhelpers.AssertEqual(t, int32(2), calls, "One token request and one lookup")
+
+	_, err = client.GetCompanyDetails("2015/123456/07")
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, calls, cipc.calls.Load(), "Second lookup is served from the cache")
+
+	_, err = client.CheckCompanyStatus("2015/123456/07")
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, calls+1, cipc.calls.Load(), "Other endpoints are cached separately")
+
+	_, err = client.GetCompanyDetails("2020/999999/07")
+	testhelpers.AssertError(t, err)
+	_, err = client.GetCompanyDetails("2020/999999/07")
+	testhelpers.AssertError(t, err)
+	testhelpers.AssertEqual(t, calls+3, cipc.calls.Load(), "Failures are not cached")
+}
+
+func TestCIPCClientAuthentication(t *testing.T) {
+	cipc := newSimulatedCIPC(t, cipcsim.Config{})
+	client := cipc.client(t, CIPCConfig{})
+
+	_, err := client.CheckCompanyStatus("2015/123456/07")
+	testhelpers.AssertNoError(t, err)
+	_, err = client.CheckCompanyStatus("2019/654321/07")
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, int32(3), cipc.calls.Load(), "The token is reused")
+
+	cipc.sim.RevokeTokens()
+	_, err = client.CheckCompanyStatus("2015/123456/07")
+	testhelpers.AssertNoError(t, err, "A refused token is renewed")
+	testhelpers.AssertEqual(t, int32(6), cipc.calls.Load())
+
+	wrong := cipc.client(t, CIPCConfig{Password: "wrong", MaxRetries: 3})
+	_, err = wrong.CheckCompanyStatus("2015/123456/07")
+	testhelpers.AssertError(t, err)
+	testhelpers.AssertEqual(t, RegistryErrorUnauthorized, RegistryErrorKind(err))
+	testhelpers.AssertEqual(t, int32(7), cipc.calls.Load(), "Bad credentials are not retried")
+}
+
+func TestCIPCClientRetries(t *testing.T) {
+	cipc := newSimulatedCIPC(t, cipcsim.Config{})
+	client := cipc.client(t, CIPCConfig{MaxRetries: 3, MaxRetryWait: 5 * time.Millisecond})
+
+	details, err := client.GetCompanyDetails("2099/000503/07")
+	testhelpers.AssertNoError(t, err, "Recovers once CIPC is available again")
+	testhelpers.AssertEqual(t, "Flaky Holdings (Pty) Ltd", details.CompanyName)
+
+	_, err = client.GetCompanyDetails("2099/000429/07")
+	testhelpers.AssertError(t, err)
+	testhelpers.AssertEqual(t, RegistryErrorThrottled, RegistryErrorKind(err))
+	cipcErr := err.(*RegistryError)
+	testhelpers.AssertEqual(t, time.Second, cipcErr.RetryAfter)
+
+	calls := cipc.calls.Load()
+	_, err = client.GetCompanyDetails("2099/000400/07")
+	testhelpers.AssertEqual(t, RegistryErrorInvalid, RegistryErrorKind(err))
+	testhelpers.AssertEqual(t, calls+1, cipc.calls.Load(), "Invalid requests are not retried")
+
+	_, err = client.GetCompanyDetails("2099/000500/07")
+	testhelpers.AssertEqual(t, RegistryErrorUnavailable, RegistryErrorKind(err))
+	testhelpers.AssertEqual(t, calls+5, cipc.calls.Load(), "Server errors are retried three times")
+}
+
+func TestCIPCClientRateLimit(t *testing.T) {
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	cipc := newSimulatedCIPC(t, cipcsim.Config{RateLimit: 2, Now: func() time.Time { return now }})
+	client := cipc.client(t, CIPCConfig{})
+
+	for _, number := range []string{"2015/123456/07", "2019/654321/07"} {
+		_, err := client.CheckCompanyStatus(number)
+		testhelpers.AssertNoError(t, err)
+	}
+
+	_, err := client.CheckCompanyStatus("2008/011111/23")
+	testhelpers.AssertEqual(t, RegistryErrorThrottled, RegistryErrorKind(err))
+}
+
+func TestCIPCNameReservation(t *testing.T) {
+	cipc := newSimulatedCIPC(t, cipcsim.Config{DecideAfter: 1})
+	client := cipc.client(t, CIPCConfig{})
+
+	request := &NameReservationRequest{
+		Names:             []string{"National Holdings", "Nkosi Holdings", "Nkosi Ventures", "Nkosi Capital"},
+		EntityType:        "pty_ltd",
+		CustomerReference: "R1",
+	}
+	reservation, err := client.ReserveNames(request)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertNotEqual(t, "", reservation.Reference)
+	testhelpers.AssertEqual(t, NameStatusPending, reservation.Status)
+	testhelpers.AssertEqual(t, 4, len(reservation.Names))
+
+	again, err := client.ReserveNames(request)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, reservation.Reference, again.Reference, "A repeated customer reference is not lodged twice")
+
+	reservation, err = client.GetNameReservation(reservation.Reference)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, NameStatusPending, reservation.Status, "Pending until examined")
+
+	reservation, err = client.GetNameReservation(reservation.Reference)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, NameStatusApproved, reservation.Status)
+	testhelpers.AssertEqual(t, "Nkosi Ventures", reservation.ReservedName)
+	testhelpers.AssertEqual(t, NameStatusRejected, reservation.Names[0].Status, "Restricted word")
+	testhelpers.AssertEqual(t, NameStatusRejected, reservation.Names[1].Status, "Registered company")
+	testhelpers.AssertEqual(t, NameStatusApproved, reservation.Names[2].Status)
+	testhelpers.AssertEqual(t, NameStatusRejected, reservation.Names[3].Status, "A preferred name was approved")
+	testhelpers.AssertTrue(t, reservation.ExpiresAt.Equal(reservation.DecidedAt.AddDate(0, 6, 0)))
+}
+
+func TestCIPCNameReservationErrors(t *testing.T) {
+	cipc := newSimulatedCIPC(t, cipcsim.Config{})
+	client := cipc.client(t, CIPCConfig{})
+
+	_, err := client.ReserveNames(&NameReservationRequest{EntityType: "pty_ltd", CustomerReference: "R2"})
+	testhelpers.AssertError(t, err, "CIPC refuses a reservation without names")
+	testhelpers.AssertEqual(t, RegistryErrorInvalid, RegistryErrorKind(err))
+
+	_, err = client.GetNameReservation("missing")
+	testhelpers.AssertError(t, err)
+	testhelpers.AssertEqual(t, RegistryErrorNotFound, RegistryErrorKind(err))
+}
@@ -0,0 +1,57 @@
+package adapters
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// cipcRegistry names CIPC in its errors
+const cipcRegistry = "CIPC"
+
+// cipcErrorBody is the error body CIPC sends with a failed call
+type cipcErrorBody struct {
+	Error struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// classifyResponse turns a failed CIPC response into a RegistryError
+func classifyResponse(resp *http.Response, body []byte) *RegistryError {
+	cipcErr := &RegistryError{Registry: cipcRegistry, StatusCode: resp.StatusCode, Message: http.StatusText(resp.StatusCode)}
+
+	var errorBody cipcErrorBody
+	if json.Unmarshal(body, &errorBody) == nil && errorBody.Error.Message != "" {
+		cipcErr.Code = errorBody.Error.Code
+		cipcErr.Message = errorBody.Error.Message
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusUnprocessableEntity:
+		cipcErr.Kind = RegistryErrorInvalid
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		cipcErr.Kind = RegistryErrorUnauthorized
+	case resp.StatusCode == http.StatusNotFound:
+		cipcErr.Kind = RegistryErrorNotFound
+	case resp.StatusCode == http.StatusTooManyRequests:
+		cipcErr.Kind = RegistryErrorThrottled
+		cipcErr.RetryAfter = retryAfter(resp.Header.Get("Retry-After"))
+	case resp.StatusCode >= 500:
+		cipcErr.Kind = RegistryErrorUnavailable
+		cipcErr.RetryAfter = retryAfter(resp.Header.Get("Retry-After"))
+	default:
+		cipcErr.Kind = RegistryErrorUnexpected
+	}
+	return cipcErr
+}
+
+// retryAfter reads a Retry-After header given in seconds
+func retryAfter(value string) time.Duration {
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
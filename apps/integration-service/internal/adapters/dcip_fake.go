@@ -0,0 +1,269 @@
+package adapters
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// dcipRegistry names DCIP in its errors
+const dcipRegistry = "DCIP"
+
+// dcipNameSearchDays is how long DCIP holds an approved name
+const dcipNameSearchDays = 30
+
+var (
+	dcipNonAlphanumeric = regexp.MustCompile(`[^a-z0-9]+`)
+	dcipLegalSuffixes   = regexp.MustCompile(`( (private|pvt|limited|ltd|p l))+$`)
+)
+
+// dcipCompanies are the companies the fake knows
+var dcipCompanies = []CompanyDetails{
+	{
+		RegistrationNumber: "1234/2019",
+		CompanyName:        "Mbare Fresh Produce (Private) Limited",
+		Status:             "Registered",
+		Type:               "Private Limited Company",
+		RegistrationDate:   "2019-03-14",
+		BusinessAddress:    "12 Remembrance Drive, Mbare, Harare",
+		PostalAddress:      "PO Box 1203, Harare",
+		Directors:          []string{"Tendai Moyo", "Rudo Chikore"},
+		EntityNumber:       "1234/2019",
+	},
+	{
+		RegistrationNumber: "987/2015",
+		CompanyName:        "Bulawayo Engineering Works (Private) Limited",
+		Status:             "Registered",
+		Type:               "Private Limited Company",
+		RegistrationDate:   "2015-08-02",
+		BusinessAddress:    "44 Fife Street, Bulawayo",
+		PostalAddress:      "PO Box 987, Bulawayo",
+		Directors:          []string{"Sipho Ndlovu"},
+		EntityNumber:       "987/2015",
+	},
+	{
+		RegistrationNumber: "56/2008",
+		CompanyName:        "Kariba Lakeside Tours (Private) Limited",
+		Status:             "Deregistered",
+		Type:               "Private Limited Company",
+		RegistrationDate:   "2008-11-20",
+		BusinessAddress:    "3 Lake Drive, Kariba",
+		Directors:          []string{"Farai Mutasa"},
+		EntityNumber:       "56/2008",
+	},
+}
+
+// dcipRestrictedWords are words DCIP refuses in a name without consent
+var dcipRestrictedWords = []string{"bank", "building society", "insurance", "government", "state", "national", "reserve bank", "zimra", "dcip"}
+
+// DCIPFake is the Zimbabwean registry. DCIP offers no API the platform can
+// call, so the fake answers from companies held in memory: a name reservation
+// (DCIP's name search) is pending when lodged and decided the first time it is
+// looked up, approving the first name that is neither restricted nor taken.
+type DCIPFake struct {
+	now func() time.Time
+
+	mu           sync.Mutex
+	lodged       int
+	reservations map[string]*NameReservation
+	byCustomer   map[string]string
+}
+
+// NewDCIPFake creates a fake DCIP. now is its clock and defaults to time.Now.
+func NewDCIPFake(now func() time.Time) *DCIPFake {
+	if now == nil {
+		now = time.Now
+	}
+	log.Println("DCIP fake registry initialized")
+	return &DCIPFake{
+		now:          now,
+		reservations: map[string]*NameReservation{},
+		byCustomer:   map[string]string{},
+	}
+}
+
+// Jurisdiction returns ZW
+func (d *DCIPFake) Jurisdiction() string {
+	return "ZW"
+}
+
+// Name returns DCIP
+func (d *DCIPFake) Name() string {
+	return dcipRegistry
+}
+
+// SearchCompanyByName finds the companies whose name contains the query,
+// ignoring case
+func (d *DCIPFake) SearchCompanyByName(companyName string) ([]CompanySearchResult, error) {
+	query := strings.ToLower(strings.TrimSpace(companyName))
+	results := []CompanySearchResult{}
+	for _, company := range dcipCompanies {
+		if query != "" && strings.Contains(strings.ToLower(company.CompanyName), query) {
+			results = append(results, CompanySearchResult{
+				RegistrationNumber: company.RegistrationNumber,
+				CompanyName:        company.CompanyName,
+				Status:             company.Status,
+				Type:               company.Type,
+				RegistrationDate:   company.RegistrationDate,
+			})
+		}
+	}
+	return results, nil
+}
+
+// GetCompanyDetails retrieves a company by its registration number
+func (d *DCIPFake) GetCompanyDetails(registrationNumber string) (*CompanyDetails, error) {
+	company, err := d.company(registrationNumber)
+	if err != nil {
+		return nil, err
+	}
+	details := *company
+	return &details, nil
+}
+
+// ValidateCompany reports whether a company is registered
+func (d *DCIPFake) ValidateCompany(registrationNumber string) (*ValidationResult, error) {
+	company, err := d.company(registrationNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ValidationResult{
+		Valid:              company.Status == "Registered",
+		RegistrationNumber: company.RegistrationNumber,
+		CompanyName:        company.CompanyName,
+		Status:             company.Status,
+		Message:            "Company is registered",
+	}
+	if !result.Valid {
+		result.Message = fmt.Sprintf("Company is %s", strings.ToLower(company.Status))
+	}
+	return result, nil
+}
+
+// CheckCompanyStatus returns a company's status, such as Registered or
+// Deregistered
+func (d *DCIPFake) CheckCompanyStatus(registrationNumber string) (string, error) {
+	company, err := d.company(registrationNumber)
+	if err != nil {
+		return "", err
+	}
+	return company.Status, nil
+}
+
+// ReserveNames lodges a name search. The existing reservation is returned
+// when the customer reference has been used before.
+func (d *DCIPFake) ReserveNames(request *NameReservationRequest) (*NameReservation, error) {
+	if len(request.Names) == 0 || len(request.Names) > 4 {
+		return nil, &RegistryError{Registry: dcipRegistry, Kind: RegistryErrorInvalid, Message: "between one and four names must be proposed"}
+	}
+	if request.CustomerReference == "" {
+		return nil, &RegistryError{Registry: dcipRegistry, Kind: RegistryErrorInvalid, Message: "customer_reference is required"}
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if reference, ok := d.byCustomer[request.CustomerReference]; ok {
+		return copyReservation(d.reservations[reference]), nil
+	}
+
+	d.lodged++
+	reservation := &NameReservation{
+		Reference: fmt.Sprintf("DCIP-NS-%06d", d.lodged),
+		Status:    NameStatusPending,
+		Names:     make([]ProposedName, len(request.Names)),
+	}
+	for i, name := range request.Names {
+		reservation.Names[i] = ProposedName{Name: name, Status: NameStatusPending}
+	}
+	d.reservations[reservation.Reference] = reservation
+	d.byCustomer[request.CustomerReference] = reservation.Reference
+
+	return copyReservation(reservation), nil
+}
+
+// GetNameReservation retrieves a name search, deciding it if it is pending
+func (d *DCIPFake) GetNameReservation(reference string) (*NameReservation, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	reservation, ok := d.reservations[reference]
+	if !ok {
+		return nil, &RegistryError{Registry: dcipRegistry, Kind: RegistryErrorNotFound, Message: fmt.Sprintf("name search %s not found", reference)}
+	}
+	if reservation.Status == NameStatusPending {
+		d.decide(reservation)
+	}
+	return copyReservation(reservation), nil
+}
+
+// decide approves the first available name and rejects the rest
+func (d *DCIPFake) decide(reservation *NameReservation) {
+	now := d.now().UTC().Truncate(time.Second)
+	reservation.DecidedAt = &now
+	reservation.Status = NameStatusRejected
+
+	for i := range reservation.Names {
+		name := &reservation.Names[i]
+		if reservation.Status == NameStatusApproved {
+			name.Status = NameStatusRejected
+			name.Reason = "A preferred name was approved"
+			continue
+		}
+		if reason := dcipUnavailable(name.Name); reason != "" {
+			name.Status = NameStatusRejected
+			name.Reason = reason
+			continue
+		}
+
+		name.Status = NameStatusApproved
+		expiresAt := now.AddDate(0, 0, dcipNameSearchDays)
+		reservation.Status = NameStatusApproved
+		reservation.ReservedName = name.Name
+		reservation.ExpiresAt = &expiresAt
+	}
+}
+
+func (d *DCIPFake) company(registrationNumber string) (*CompanyDetails, error) {
+	for i := range dcipCompanies {
+		if dcipCompanies[i].RegistrationNumber == registrationNumber {
+			return &dcipCompanies[i], nil
+		}
+	}
+	return nil, &RegistryError{Registry: dcipRegistry, Kind: RegistryErrorNotFound, Message: fmt.Sprintf("company %s not found", registrationNumber)}
+}
+
+// dcipUnavailable returns why DCIP would refuse a name, or ""
+func dcipUnavailable(name string) string {
+	key := dcipNameKey(name)
+	padded := " " + key + " "
+	for _, word := range dcipRestrictedWords {
+		if strings.Contains(padded, " "+word+" ") {
+			return fmt.Sprintf("Name contains the restricted word %q", word)
+		}
+	}
+	for _, company := range dcipCompanies {
+		if dcipNameKey(company.CompanyName) == key {
+			return fmt.Sprintf("Name is the same as registered company %s", company.RegistrationNumber)
+		}
+	}
+	return ""
+}
+
+// dcipNameKey reduces a company name to lower case words without legal
+// suffixes
+func dcipNameKey(name string) string {
+	key := strings.TrimSpace(dcipNonAlphanumeric.ReplaceAllString(strings.ToLower(name), " "))
+	return dcipLegalSuffixes.ReplaceAllString(key, "")
+}
+
+// copyReservation copies a reservation so callers cannot change the fake's
+func copyReservation(reservation *NameReservation) *NameReservation {
+	copied := *reservation
+	copied.Names = append([]ProposedName(nil), reservation.Names...)
+	return &copied
+}
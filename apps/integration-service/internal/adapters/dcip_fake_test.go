@@ -0,0 +1,64 @@
+package adapters
+
+import (
+	"testing"
+	"time"
+
+	testhelpers "github.com/comply360/shared/testing"
+)
+
+var (
+	_ Registry = (*CIPCClient)(nil)
+	_ Registry = (*DCIPFake)(nil)
+)
+
+func TestDCIPFakeCompanyLookups(t *testing.T) {
+	dcip := NewDCIPFake(nil)
+
+	results, err := dcip.SearchCompanyByName("mbare")
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 1, len(results))
+	testhelpers.AssertEqual(t, "1234/2019", results[0].RegistrationNumber)
+
+	result, err := dcip.ValidateCompany("56/2008")
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertFalse(t, result.Valid, "Deregistered companies are not valid")
+
+	_, err = dcip.GetCompanyDetails("2020/123456/07")
+	testhelpers.AssertEqual(t, RegistryErrorNotFound, RegistryErrorKind(err))
+	testhelpers.AssertEqual(t, "DCIP not_found (status 0): company 2020/123456/07 not found", err.Error())
+}
+
+func TestDCIPFakeNameReservation(t *testing.T) {
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	dcip := NewDCIPFake(func() time.Time { return now })
+
+	request := &NameReservationRequest{
+		Names:             []string{"National Traders", "Mbare Fresh Produce", "Chitungwiza Ventures", "Chitungwiza Holdings"},
+		EntityType:        "pty_ltd",
+		CustomerReference: "R1",
+	}
+	reservation, err := dcip.ReserveNames(request)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "DCIP-NS-000001", reservation.Reference)
+	testhelpers.AssertEqual(t, NameStatusPending, reservation.Status)
+
+	again, err := dcip.ReserveNames(request)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, reservation.Reference, again.Reference, "A repeated customer reference is not lodged twice")
+
+	reservation, err = dcip.GetNameReservation(reservation.Reference)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, NameStatusApproved, reservation.Status)
+	testhelpers.AssertEqual(t, "Chitungwiza Ventures", reservation.ReservedName)
+	testhelpers.AssertEqual(t, NameStatusRejected, reservation.Names[0].Status, "Restricted word")
+	testhelpers.AssertEqual(t, NameStatusRejected, reservation.Names[1].Status, "Registered company")
+	testhelpers.AssertEqual(t, NameStatusRejected, reservation.Names[3].Status, "A preferred name was approved")
+	testhelpers.AssertTrue(t, reservation.ExpiresAt.Equal(now.AddDate(0, 0, 30)))
+
+	_, err = dcip.ReserveNames(&NameReservationRequest{CustomerReference: "R2"})
+	testhelpers.AssertEqual(t, RegistryErrorInvalid, RegistryErrorKind(err))
+
+	_, err = dcip.GetNameReservation("missing")
+	testhelpers.AssertEqual(t, RegistryErrorNotFound, RegistryErrorKind(err))
+}
@@ -0,0 +1,144 @@
+package adapters
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Registry is a companies registry, such as CIPC in South Africa or DCIP in
+// Zimbabwe. Each jurisdiction the platform supports has one adapter, and
+// failures are returned as RegistryErrors.
+type Registry interface {
+	// Jurisdiction is the country code of the registry's jurisdiction
+	Jurisdiction() string
+	// Name is the registry's short name, such as CIPC
+	Name() string
+
+	SearchCompanyByName(companyName string) ([]CompanySearchResult, error)
+	GetCompanyDetails(registrationNumber string) (*CompanyDetails, error)
+	ValidateCompany(registrationNumber string) (*ValidationResult, error)
+	CheckCompanyStatus(registrationNumber string) (string, error)
+
+	// ReserveNames lodges a name reservation. Lodging again with a customer
+	// reference that has been used returns the existing reservation.
+	ReserveNames(request *NameReservationRequest) (*NameReservation, error)
+	GetNameReservation(reference string) (*NameReservation, error)
+}
+
+// CompanySearchResult represents a company search result from a registry
+type CompanySearchResult struct {
+	RegistrationNumber string `json:"registration_number"`
+	CompanyName        string `json:"company_name"`
+	Status             string `json:"status"`
+	Type               string `json:"type"`
+	RegistrationDate   string `json:"registration_date"`
+}
+
+// CompanyDetails represents detailed company information from a registry
+type CompanyDetails struct {
+	RegistrationNumber string   `json:"registration_number"`
+	CompanyName        string   `json:"company_name"`
+	Status             string   `json:"status"`
+	Type               string   `json:"type"`
+	RegistrationDate   string   `json:"registration_date"`
+	BusinessAddress    string   `json:"business_address"`
+	PostalAddress      string   `json:"postal_address"`
+	Directors          []string `json:"directors"`
+	EntityNumber       string   `json:"entity_number"`
+}
+
+// ValidationResult represents the result of a company validation
+type ValidationResult struct {
+	Valid              bool   `json:"valid"`
+	RegistrationNumber string `json:"registration_number"`
+	CompanyName        string `json:"company_name"`
+	Status             string `json:"status"`
+	Message            string `json:"message"`
+}
+
+// Name reservation and proposed name statuses
+const (
+	NameStatusPending  = "pending"
+	NameStatusApproved = "approved"
+	NameStatusRejected = "rejected"
+)
+
+// NameReservationRequest asks a registry to reserve one of up to four
+// proposed names, in order of preference
+type NameReservationRequest struct {
+	Names             []string `json:"names"`
+	EntityType        string   `json:"entity_type"`
+	CustomerReference string   `json:"customer_reference"`
+}
+
+// ProposedName is the registry's decision on one proposed name
+type ProposedName struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// NameReservation represents a name reservation lodged with a registry. Once
+// approved, ReservedName holds the name the registry granted until ExpiresAt.
+type NameReservation struct {
+	Reference    string         `json:"reference"`
+	Status       string         `json:"status"`
+	Names        []ProposedName `json:"names"`
+	ReservedName string         `json:"reserved_name,omitempty"`
+	DecidedAt    *time.Time     `json:"decided_at,omitempty"`
+	ExpiresAt    *time.Time     `json:"expires_at,omitempty"`
+}
+
+// Registry error kinds. Throttled and unavailable requests are worth retrying;
+// the others fail the same way every time.
+const (
+	RegistryErrorInvalid      = "invalid"
+	RegistryErrorUnauthorized = "unauthorized"
+	RegistryErrorNotFound     = "not_found"
+	RegistryErrorThrottled    = "throttled"
+	RegistryErrorUnavailable  = "unavailable"
+	RegistryErrorUnexpected   = "unexpected"
+)
+
+// RegistryError is a failed registry call, classified by what went wrong.
+// Code and Message are the registry's own when it sent an error body.
+type RegistryError struct {
+	Registry   string
+	Kind       string
+	StatusCode int
+	Code       string
+	Message    string
+	RetryAfter time.Duration
+	Err        error
+}
+
+func (e *RegistryError) Error() string {
+	switch {
+	case e.Err != nil:
+		return fmt.Sprintf("%s %s: %v", e.Registry, e.Kind, e.Err)
+	case e.Code != "":
+		return fmt.Sprintf("%s %s (status %d, %s): %s", e.Registry, e.Kind, e.StatusCode, e.Code, e.Message)
+	default:
+		return fmt.Sprintf("%s %s (status %d): %s", e.Registry, e.Kind, e.StatusCode, e.Message)
+	}
+}
+
+func (e *RegistryError) Unwrap() error {
+	return e.Err
+}
+
+// Retryable reports whether the call may succeed if tried again
+func (e *RegistryError) Retryable() bool {
+	return e.Kind == RegistryErrorThrottled || e.Kind == RegistryErrorUnavailable
+}
+
+// RegistryErrorKind returns the kind of a registry error, or "" for any other
+// error
+func RegistryErrorKind(err error) string {
+	var registryErr *RegistryError
+	if errors.As(err, &registryErr) {
+		return registryErr.Kind
+	}
+	return ""
+}
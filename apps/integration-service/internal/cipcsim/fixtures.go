@@ -0,0 +1,99 @@
+package cipcsim
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+//go:embed fixtures.json
+var defaultFixtures []byte
+
+// Fixtures are the companies the simulator knows, the words it refuses in
+// proposed names, and the scenarios that make it fail
+type Fixtures struct {
+	Companies       []Company  `json:"companies"`
+	RestrictedWords []string   `json:"restricted_words"`
+	Scenarios       []Scenario `json:"scenarios"`
+}
+
+// Company is a registered company
+type Company struct {
+	RegistrationNumber string   `json:"registration_number"`
+	CompanyName        string   `json:"company_name"`
+	Status             string   `json:"status"`
+	Type               string   `json:"type"`
+	RegistrationDate   string   `json:"registration_date"`
+	BusinessAddress    string   `json:"business_address"`
+	PostalAddress      string   `json:"postal_address"`
+	Directors          []string `json:"directors"`
+	EntityNumber       string   `json:"entity_number"`
+}
+
+// Scenario makes calls about a registration number or company name fail.
+// Match is compared without case against the registration_number or name
+// query parameter. Times is how many calls fail before the simulator answers
+// normally; zero fails every call. RetryAfter is sent in seconds with a 429
+// or 503.
+type Scenario struct {
+	Match      string `json:"match"`
+	Status     int    `json:"status"`
+	Times      int    `json:"times,omitempty"`
+	RetryAfter int    `json:"retry_after,omitempty"`
+	Message    string `json:"message"`
+}
+
+// DefaultFixtures returns the fixtures built into the simulator
+func DefaultFixtures() *Fixtures {
+	fixtures, err := parseFixtures(defaultFixtures)
+	if err != nil {
+		panic(fmt.Sprintf("invalid built-in CIPC fixtures: %v", err))
+	}
+	return fixtures
+}
+
+// LoadFixtures reads fixtures from a JSON file
+func LoadFixtures(path string) (*Fixtures, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixtures: %w", err)
+	}
+	return parseFixtures(data)
+}
+
+func parseFixtures(data []byte) (*Fixtures, error) {
+	var fixtures Fixtures
+	if err := json.Unmarshal(data, &fixtures); err != nil {
+		return nil, fmt.Errorf("failed to parse fixtures: %w", err)
+	}
+	for _, scenario := range fixtures.Scenarios {
+		if scenario.Match == "" || scenario.Status < 400 {
+			return nil, fmt.Errorf("scenario %q must match something and fail with a 4xx or 5xx status", scenario.Match)
+		}
+	}
+	return &fixtures, nil
+}
+
+// company finds a company by registration number
+func (f *Fixtures) company(registrationNumber string) (Company, bool) {
+	for _, company := range f.Companies {
+		if company.RegistrationNumber == registrationNumber {
+			return company, true
+		}
+	}
+	return Company{}, false
+}
+
+// search finds the companies whose name contains the query, ignoring case
+func (f *Fixtures) search(name string) []Company {
+	query := strings.ToLower(strings.TrimSpace(name))
+	found := []Company{}
+	for _, company := range f.Companies {
+		if query != "" && strings.Contains(strings.ToLower(company.CompanyName), query) {
+			found = append(found, company)
+		}
+	}
+	return found
+}
@@ -0,0 +1,459 @@
+// Package cipcsim simulates the CIPC API the integration service's CIPC client
+// is written against, for local development and integration tests. Answers
+// come from fixtures, so the same calls always get the same answers; fixture
+// scenarios make chosen calls fail or be throttled. The contract is described
+// in docs/cipc-api.md.
+package cipcsim
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	nonAlphanumeric = regexp.MustCompile(`[^a-z0-9]+`)
+	legalSuffixes   = regexp.MustCompile(`( (proprietary|pty|limited|ltd|cc|inc|npc|rf|soc))+$`)
+)
+
+// Config controls the simulator's credentials, limits and pace
+type Config struct {
+	Username string
+	Password string
+	// APIKey, when set, must accompany every call as X-API-Key
+	APIKey string
+	// TokenTTL is how long an access token lasts; defaults to an hour
+	TokenTTL time.Duration
+	// RateLimit is how many calls a token may make each second; zero is
+	// unlimited
+	RateLimit int
+	// DecideAfter is how many times a pending name reservation is fetched
+	// before CIPC decides on it; zero decides when it is lodged
+	DecideAfter int
+	// Now is the simulator's clock; defaults to time.Now
+	Now func() time.Time
+}
+
+// Server is a simulated CIPC API
+type Server struct {
+	config   Config
+	fixtures *Fixtures
+	mux      *http.ServeMux
+
+	mu           sync.Mutex
+	tokens       map[string]time.Time
+	issued       int
+	windows      map[string]rateWindow
+	failures     map[string]int
+	reservations map[string]*reservation
+	byCustomer   map[string]string
+}
+
+type rateWindow struct {
+	start time.Time
+	calls int
+}
+
+type reservation struct {
+	Reference    string         `json:"reference"`
+	Status       string         `json:"status"`
+	Names        []proposedName `json:"names"`
+	ReservedName string         `json:"reserved_name,omitempty"`
+	DecidedAt    *time.Time     `json:"decided_at,omitempty"`
+	ExpiresAt    *time.Time     `json:"expires_at,omitempty"`
+	EntityType   string         `json:"entity_type"`
+	Customer     string         `json:"customer_reference"`
+	fetches      int
+}
+
+type proposedName struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// New creates a simulator serving the fixtures
+func New(config Config, fixtures *Fixtures) *Server {
+	if config.TokenTTL <= 0 {
+		config.TokenTTL = time.Hour
+	}
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+
+	s := &Server{
+		config:       config,
+		fixtures:     fixtures,
+		mux:          http.NewServeMux(),
+		tokens:       map[string]time.Time{},
+		windows:      map[string]rateWindow{},
+		failures:     map[string]int{},
+		reservations: map[string]*reservation{},
+		byCustomer:   map[string]string{},
+	}
+
+	s.mux.HandleFunc("/health", s.health)
+	s.mux.HandleFunc("/auth/token", s.issueToken)
+	s.mux.HandleFunc("/companies", s.authorized(s.searchCompanies))
+	s.mux.HandleFunc("/companies/details", s.authorized(s.companyDetails))
+	s.mux.HandleFunc("/companies/status", s.authorized(s.companyStatus))
+	s.mux.HandleFunc("/companies/validate", s.authorized(s.validateCompany))
+	s.mux.HandleFunc("/name-reservations", s.authorized(s.reserveNames))
+	s.mux.HandleFunc("/name-reservations/", s.authorized(s.getNameReservation))
+	return s
+}
+
+// ServeHTTP serves the simulated API
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// RevokeTokens invalidates every access token issued, as if they had expired
+func (s *Server) RevokeTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens = map[string]time.Time{}
+}
+
+func (s *Server) health(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "healthy", "service": "cipc-sim"})
+}
+
+// issueToken handles POST /auth/token
+func (s *Server) issueToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Use POST")
+		return
+	}
+	if s.config.APIKey != "" && r.Header.Get("X-API-Key") != s.config.APIKey {
+		writeError(w, http.StatusUnauthorized, "invalid_api_key", "API key is missing or invalid")
+		return
+	}
+
+	var credentials struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&credentials); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "Request body must be JSON")
+		return
+	}
+	if credentials.Username != s.config.Username || credentials.Password != s.config.Password {
+		writeError(w, http.StatusUnauthorized, "invalid_credentials", "Username or password is incorrect")
+		return
+	}
+
+	s.mu.Lock()
+	s.issued++
+	token := fmt.Sprintf("sim-token-%d", s.issued)
+	s.tokens[token] = s.config.Now().Add(s.config.TokenTTL)
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": token,
+		"token_type":   "Bearer",
+		"expires_in":   int(s.config.TokenTTL / time.Second),
+	})
+}
+
+// authorized checks the caller's API key, token and rate limit, and plays any
+// scenario matching the call, before the handler runs
+func (s *Server) authorized(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.config.APIKey != "" && r.Header.Get("X-API-Key") != s.config.APIKey {
+			writeError(w, http.StatusUnauthorized, "invalid_api_key", "API key is missing or invalid")
+			return
+		}
+
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		now := s.config.Now()
+
+		s.mu.Lock()
+		expiry, ok := s.tokens[token]
+		if !ok || !now.Before(expiry) {
+			s.mu.Unlock()
+			writeError(w, http.StatusUnauthorized, "invalid_token", "Access token is missing, invalid or expired")
+			return
+		}
+
+		if s.config.RateLimit > 0 {
+			window := s.windows[token]
+			if second := now.Truncate(time.Second); !window.start.Equal(second) {
+				window = rateWindow{start: second}
+			}
+			window.calls++
+			s.windows[token] = window
+			if window.calls > s.config.RateLimit {
+				s.mu.Unlock()
+				w.Header().Set("Retry-After", "1")
+				writeError(w, http.StatusTooManyRequests, "rate_limited", "Rate limit exceeded")
+				return
+			}
+		}
+
+		scenario, fail := s.scenario(r)
+		s.mu.Unlock()
+
+		if fail {
+			if scenario.RetryAfter > 0 {
+				w.Header().Set("Retry-After", strconv.Itoa(scenario.RetryAfter))
+			}
+			writeError(w, scenario.Status, "scenario", scenario.Message)
+			return
+		}
+		handler(w, r)
+	}
+}
+
+// scenario finds a scenario matching the call and reports whether it still
+// fails. The caller holds the lock.
+func (s *Server) scenario(r *http.Request) (Scenario, bool) {
+	query := r.URL.Query()
+	for _, value := range []string{query.Get("registration_number"), query.Get("name")} {
+		if value == "" {
+			continue
+		}
+		for _, scenario := range s.fixtures.Scenarios {
+			if !strings.EqualFold(scenario.Match, value) {
+				continue
+			}
+			if scenario.Times > 0 && s.failures[scenario.Match] >= scenario.Times {
+				return scenario, false
+			}
+			s.failures[scenario.Match]++
+			return scenario, true
+		}
+	}
+	return Scenario{}, false
+}
+
+// searchCompanies handles GET /companies?name=
+func (s *Server) searchCompanies(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", "name is required")
+		return
+	}
+
+	results := []map[string]string{}
+	for _, company := range s.fixtures.search(name) {
+		results = append(results, map[string]string{
+			"registration_number": company.RegistrationNumber,
+			"company_name":        company.CompanyName,
+			"status":              company.Status,
+			"type":                company.Type,
+			"registration_date":   company.RegistrationDate,
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"results": results})
+}
+
+// companyDetails handles GET /companies/details?registration_number=
+func (s *Server) companyDetails(w http.ResponseWriter, r *http.Request) {
+	company, ok := s.lookup(w, r)
+	if ok {
+		writeJSON(w, http.StatusOK, company)
+	}
+}
+
+// companyStatus handles GET /companies/status?registration_number=
+func (s *Server) companyStatus(w http.ResponseWriter, r *http.Request) {
+	company, ok := s.lookup(w, r)
+	if ok {
+		writeJSON(w, http.StatusOK, map[string]string{
+			"registration_number": company.RegistrationNumber,
+			"status":              company.Status,
+		})
+	}
+}
+
+// validateCompany handles GET /companies/validate?registration_number=. An
+// unknown number is invalid rather than not found.
+func (s *Server) validateCompany(w http.ResponseWriter, r *http.Request) {
+	registrationNumber := r.URL.Query().Get("registration_number")
+	if registrationNumber == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", "registration_number is required")
+		return
+	}
+
+	company, ok := s.fixtures.company(registrationNumber)
+	result := map[string]interface{}{
+		"valid":               ok && company.Status == "Active",
+		"registration_number": registrationNumber,
+		"company_name":        company.CompanyName,
+		"status":              company.Status,
+	}
+	switch {
+	case !ok:
+		result["message"] = "No company is registered under this number"
+	case company.Status == "Active":
+		result["message"] = "Company registration is valid and active"
+	default:
+		result["message"] = fmt.Sprintf("Company is %s", strings.ToLower(company.Status))
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) lookup(w http.ResponseWriter, r *http.Request) (Company, bool) {
+	registrationNumber := r.URL.Query().Get("registration_number")
+	if registrationNumber == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", "registration_number is required")
+		return Company{}, false
+	}
+	company, ok := s.fixtures.company(registrationNumber)
+	if !ok {
+		writeError(w, http.StatusNotFound, "company_not_found", "No company is registered under this number")
+	}
+	return company, ok
+}
+
+// reserveNames handles POST /name-reservations. A customer reference that was
+// used before returns the reservation lodged with it.
+func (s *Server) reserveNames(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Use POST")
+		return
+	}
+
+	var request struct {
+		Names             []string `json:"names"`
+		EntityType        string   `json:"entity_type"`
+		CustomerReference string   `json:"customer_reference"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "Request body must be JSON")
+		return
+	}
+	if len(request.Names) == 0 || len(request.Names) > 4 {
+		writeError(w, http.StatusUnprocessableEntity, "invalid_names", "Propose between one and four names")
+		return
+	}
+	for _, name := range request.Names {
+		if strings.TrimSpace(name) == "" {
+			writeError(w, http.StatusUnprocessableEntity, "invalid_names", "Proposed names cannot be empty")
+			return
+		}
+	}
+	if request.EntityType == "" || request.CustomerReference == "" {
+		writeError(w, http.StatusUnprocessableEntity, "invalid_request", "entity_type and customer_reference are required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if reference, ok := s.byCustomer[request.CustomerReference]; ok {
+		writeJSON(w, http.StatusOK, s.reservations[reference])
+		return
+	}
+
+	lodged := &reservation{
+		Reference:  fmt.Sprintf("NR%08d", len(s.reservations)+1),
+		Status:     "pending",
+		EntityType: request.EntityType,
+		Customer:   request.CustomerReference,
+	}
+	for _, name := range request.Names {
+		lodged.Names = append(lodged.Names, proposedName{Name: strings.TrimSpace(name), Status: "pending"})
+	}
+	if s.config.DecideAfter <= 0 {
+		s.decide(lodged)
+	}
+	s.reservations[lodged.Reference] = lodged
+	s.byCustomer[request.CustomerReference] = lodged.Reference
+
+	writeJSON(w, http.StatusCreated, lodged)
+}
+
+// getNameReservation handles GET /name-reservations/{reference}
+func (s *Server) getNameReservation(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Use GET")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lodged, ok := s.reservations[strings.TrimPrefix(r.URL.Path, "/name-reservations/")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "reservation_not_found", "No name reservation has this reference")
+		return
+	}
+	if lodged.Status == "pending" {
+		lodged.fetches++
+		if lodged.fetches > s.config.DecideAfter {
+			s.decide(lodged)
+		}
+	}
+	writeJSON(w, http.StatusOK, lodged)
+}
+
+// decide examines a reservation's names in order of preference, approving the
+// first that neither uses a restricted word nor matches a registered company.
+// The caller holds the lock.
+func (s *Server) decide(lodged *reservation) {
+	now := s.config.Now().UTC().Truncate(time.Second)
+	lodged.DecidedAt = &now
+	lodged.Status = "rejected"
+
+	for i := range lodged.Names {
+		name := &lodged.Names[i]
+		if lodged.Status == "approved" {
+			name.Status = "rejected"
+			name.Reason = "A preferred name was approved"
+			continue
+		}
+		if reason := s.unavailable(name.Name); reason != "" {
+			name.Status = "rejected"
+			name.Reason = reason
+			continue
+		}
+
+		name.Status = "approved"
+		expiresAt := now.AddDate(0, 6, 0)
+		lodged.Status = "approved"
+		lodged.ReservedName = name.Name
+		lodged.ExpiresAt = &expiresAt
+	}
+}
+
+// unavailable returns why a name cannot be reserved, or ""
+func (s *Server) unavailable(name string) string {
+	key := nameKey(name)
+	padded := " " + key + " "
+	for _, word := range s.fixtures.RestrictedWords {
+		if strings.Contains(padded, " "+strings.ToLower(word)+" ") {
+			return fmt.Sprintf("Name contains the restricted word %q", word)
+		}
+	}
+	for _, company := range s.fixtures.Companies {
+		if nameKey(company.CompanyName) == key {
+			return fmt.Sprintf("Name is the same as registered company %s", company.RegistrationNumber)
+		}
+	}
+	return ""
+}
+
+// nameKey reduces a company name to lower case words without legal suffixes
+func nameKey(name string) string {
+	key := strings.TrimSpace(nonAlphanumeric.ReplaceAllString(strings.ToLower(name), " "))
+	return legalSuffixes.ReplaceAllString(key, "")
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, map[string]interface{}{
+		"error": map[string]string{"code": code, "message": message},
+	})
+}
@@ -72,8 +72,9 @@ func (c *OdooSyncConsumer) setupQueues() error {
 
 	// Bind registration events for Odoo sync
 	registrationBindings := []string{
-		"registration.status.submitted", // Create lead when submitted
-		"registration.status.approved",  // Update lead to won
+		"registration.status.submitted",  // Create lead when submitted
+		"registration.status.approved",   // Update lead to won
+		"registration.invoice.requested", // Invoice the locked quote
 	}
 
 	for _, routingKey := range registrationBindings {
@@ -193,6 +194,23 @@ func (c *OdooSyncConsumer) handleRegistrationEvent(msg amqp.Delivery) {
 
 		log.Printf("[ODOO SYNC] Would update Odoo lead status to 'won' for: %s", registration.CompanyName)
 		log.Printf("[ODOO SYNC] ✓ [DEMO] Would convert Odoo lead to customer")
+
+	case "registration.invoice.requested":
+		// Invoice the quote locked when the registration was submitted
+		if registration.Quote == nil {
+			log.Printf("[ODOO SYNC] No locked quote for %s; invoice must be raised manually", registration.CompanyName)
+			break
+		}
+
+		// In production, you would:
+		// 1. Find the client's Odoo partner from the registration's lead
+		// 2. Call odooService.CreateInvoice() with the invoice below
+		// 3. Update registration with returned Odoo invoice ID via API call back to registration service
+		invoice := services.InvoiceFromQuote(registration.Quote, 0, registration.ID.String())
+
+		log.Printf("[ODOO SYNC] Would create Odoo invoice for: %s (%d lines, %s %.2f)",
+			registration.CompanyName, len(invoice.Lines), registration.Quote.Currency, registration.Quote.Total)
+		log.Printf("[ODOO SYNC] ✓ [DEMO] Would create Odoo invoice from locked quote")
 	}
 
 	msg.Ack(false)
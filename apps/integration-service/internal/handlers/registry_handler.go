@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/comply360/integration-service/internal/adapters"
+	"github.com/comply360/integration-service/internal/services"
+	"github.com/comply360/shared/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// RegistryHandler handles companies registry HTTP requests, such as those for
+// CIPC in South Africa or DCIP in Zimbabwe
+type RegistryHandler struct {
+	service *services.RegistryService
+}
+
+// NewRegistryHandler creates a new registry handler
+func NewRegistryHandler(service *services.RegistryService) *RegistryHandler {
+	return &RegistryHandler{
+		service: service,
+	}
+}
+
+// SetupRoutes sets up the registry routes under /registries, with the
+// jurisdiction in the path
+func (h *RegistryHandler) SetupRoutes(router *gin.RouterGroup) {
+	router.GET("", h.ListRegistries)
+	h.setupRegistryRoutes(router.Group("/:jurisdiction"))
+}
+
+// SetupJurisdictionRoutes sets up the routes of one jurisdiction's registry,
+// such as /cipc for South Africa
+func (h *RegistryHandler) SetupJurisdictionRoutes(router *gin.RouterGroup, jurisdiction string) {
+	router.Use(func(c *gin.Context) {
+		c.Set("jurisdiction", jurisdiction)
+		c.Next()
+	})
+	h.setupRegistryRoutes(router)
+}
+
+func (h *RegistryHandler) setupRegistryRoutes(router *gin.RouterGroup) {
+	router.GET("/search", h.SearchCompany)
+	router.GET("/company/:registration_number", h.GetCompanyDetails)
+	router.POST("/validate", h.ValidateCompany)
+	router.GET("/status/:registration_number", h.CheckStatus)
+	router.POST("/name-reservations", h.ReserveNames)
+	router.GET("/name-reservations/:reference", h.GetNameReservation)
+}
+
+// ListRegistries handles GET /integration/registries
+func (h *RegistryHandler) ListRegistries(c *gin.Context) {
+	registries := []gin.H{}
+	for _, code := range h.service.Jurisdictions() {
+		registry, _ := h.service.Registry(code)
+		registries = append(registries, gin.H{"jurisdiction": code, "registry": registry.Name()})
+	}
+	c.JSON(http.StatusOK, gin.H{"data": registries})
+}
+
+// SearchCompany handles GET /integration/registries/:jurisdiction/search?company_name=xxx
+func (h *RegistryHandler) SearchCompany(c *gin.Context) {
+	companyName := c.Query("company_name")
+	if companyName == "" {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(
+			errors.ErrInvalidInput,
+			"company_name query parameter is required",
+		))
+		return
+	}
+
+	results, err := h.service.SearchCompany(jurisdiction(c), companyName)
+	if err != nil {
+		respondRegistryError(c, "Failed to search registry", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  results,
+		"total": len(results),
+	})
+}
+
+// GetCompanyDetails handles GET /integration/registries/:jurisdiction/company/:registration_number
+func (h *RegistryHandler) GetCompanyDetails(c *gin.Context) {
+	registrationNumber := c.Param("registration_number")
+	if registrationNumber == "" {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(
+			errors.ErrInvalidInput,
+			"registration_number is required",
+		))
+		return
+	}
+
+	details, err := h.service.GetCompanyDetails(jurisdiction(c), registrationNumber)
+	if err != nil {
+		respondRegistryError(c, "Failed to get company details", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, details)
+}
+
+// ValidateCompany handles POST /integration/registries/:jurisdiction/validate
+func (h *RegistryHandler) ValidateCompany(c *gin.Context) {
+	var req struct {
+		RegistrationNumber string `json:"registration_number" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(
+			errors.ErrInvalidInput,
+			"Invalid request body",
+		))
+		return
+	}
+
+	result, err := h.service.ValidateCompany(jurisdiction(c), req.RegistrationNumber)
+	if err != nil {
+		respondRegistryError(c, "Failed to validate company", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// CheckStatus handles GET /integration/registries/:jurisdiction/status/:registration_number
+func (h *RegistryHandler) CheckStatus(c *gin.Context) {
+	registrationNumber := c.Param("registration_number")
+	if registrationNumber == "" {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(
+			errors.ErrInvalidInput,
+			"registration_number is required",
+		))
+		return
+	}
+
+	status, err := h.service.CheckStatus(jurisdiction(c), registrationNumber)
+	if err != nil {
+		respondRegistryError(c, "Failed to check company status", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"registration_number": registrationNumber,
+		"status":              status,
+	})
+}
+
+// ReserveNames handles POST /integration/registries/:jurisdiction/name-reservations
+func (h *RegistryHandler) ReserveNames(c *gin.Context) {
+	var req struct {
+		Names             []string `json:"names" binding:"required,min=1,max=4,dive,required"`
+		EntityType        string   `json:"entity_type" binding:"required"`
+		CustomerReference string   `json:"customer_reference" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(
+			errors.ErrInvalidInput,
+			"Invalid request body",
+		))
+		return
+	}
+
+	reservation, err := h.service.ReserveNames(jurisdiction(c), &adapters.NameReservationRequest{
+		Names:             req.Names,
+		EntityType:        req.EntityType,
+		CustomerReference: req.CustomerReference,
+	})
+	if err != nil {
+		respondRegistryError(c, "Failed to reserve names with registry", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, reservation)
+}
+
+// GetNameReservation handles GET /integration/registries/:jurisdiction/name-reservations/:reference
+func (h *RegistryHandler) GetNameReservation(c *gin.Context) {
+	reservation, err := h.service.GetNameReservation(jurisdiction(c), c.Param("reference"))
+	if err != nil {
+		respondRegistryError(c, "Failed to get name reservation from registry", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reservation)
+}
+
+// jurisdiction returns the jurisdiction whose registry a request is for, from
+// the path or fixed by the route group
+func jurisdiction(c *gin.Context) string {
+	if code := c.Param("jurisdiction"); code != "" {
+		return strings.ToUpper(code)
+	}
+	return c.GetString("jurisdiction")
+}
+
+// respondRegistryError maps a failed registry call to a response. Unknown
+// jurisdictions and lookups of unknown companies are not found, requests the
+// registry refused are bad requests, and throttling is passed on with the
+// registry's Retry-After; anything else is a bad gateway.
+func respondRegistryError(c *gin.Context, message string, err error) {
+	details := map[string]interface{}{"error": err.Error()}
+
+	if err.Error() == "unsupported jurisdiction" {
+		c.JSON(http.StatusNotFound, errors.NewAPIError(errors.ErrNotFound, "No registry for jurisdiction "+jurisdiction(c)))
+		return
+	}
+
+	switch adapters.RegistryErrorKind(err) {
+	case adapters.RegistryErrorNotFound:
+		c.JSON(http.StatusNotFound, errors.NewAPIErrorWithDetails(errors.ErrNotFound, message, details))
+	case adapters.RegistryErrorInvalid:
+		c.JSON(http.StatusBadRequest, errors.NewAPIErrorWithDetails(errors.ErrInvalidInput, message, details))
+	case adapters.RegistryErrorThrottled:
+		var registryErr *adapters.RegistryError
+		if stderrors.As(err, &registryErr) && registryErr.RetryAfter > 0 {
+			c.Header("Retry-After", strconv.Itoa(int(registryErr.RetryAfter.Seconds())))
+		}
+		c.JSON(http.StatusTooManyRequests, errors.NewAPIErrorWithDetails(errors.ErrRateLimitExceeded, message, details))
+	default:
+		c.JSON(http.StatusBadGateway, errors.NewAPIErrorWithDetails(errors.ErrInternalServer, message, details))
+	}
+}
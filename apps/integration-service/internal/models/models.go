@@ -46,6 +46,7 @@ type Commission struct {
 type Invoice struct {
 	PartnerID  int           `json:"partner_id"`
 	Reference  string        `json:"reference"`
+	Currency   string        `json:"currency,omitempty"`
 	Lines      []InvoiceLine `json:"lines"`
 }
 
@@ -7,6 +7,7 @@ import (
 
 	"github.com/comply360/integration-service/internal/adapters"
 	"github.com/comply360/integration-service/internal/models"
+	sharedmodels "github.com/comply360/shared/models"
 	"github.com/google/uuid"
 )
 
@@ -180,6 +181,9 @@ func (s *OdooService) CreateInvoice(invoice *models.Invoice) (int, error) {
 		"invoice_date": time.Now().Format("2006-01-02"),
 		"payment_reference": invoice.Reference,
 	}
+	if invoice.Currency != "" {
+		invoiceValues["currency_id"] = s.getCurrencyID(invoice.Currency)
+	}
 
 	// Add invoice lines
 	if len(invoice.Lines) > 0 {
@@ -205,6 +209,34 @@ func (s *OdooService) CreateInvoice(invoice *models.Invoice) (int, error) {
 	return invoiceID, nil
 }
 
+// InvoiceFromQuote builds a registration's invoice from the quote locked when
+// it was submitted. Each fee is a line at its amount excluding VAT, and the VAT
+// is a line of its own, so the invoice comes to the quote's total whatever
+// taxes are set up in Odoo.
+func InvoiceFromQuote(quote *sharedmodels.Quote, partnerID int, reference string) *models.Invoice {
+	invoice := &models.Invoice{
+		PartnerID: partnerID,
+		Reference: reference,
+		Currency:  quote.Currency,
+		Lines:     []models.InvoiceLine{},
+	}
+	for _, line := range quote.Lines {
+		invoice.Lines = append(invoice.Lines, models.InvoiceLine{
+			Description: line.Description,
+			Quantity:    1,
+			UnitPrice:   line.Amount,
+		})
+	}
+	if quote.VAT > 0 {
+		invoice.Lines = append(invoice.Lines, models.InvoiceLine{
+			Description: fmt.Sprintf("VAT at %g%%", quote.VATRate),
+			Quantity:    1,
+			UnitPrice:   quote.VAT,
+		})
+	}
+	return invoice
+}
+
 // CreateCommission creates a commission record in Odoo
 func (s *OdooService) CreateCommission(commission *models.Commission) (int, error) {
 	log.Printf("Creating Odoo commission for agent %s", commission.AgentID)
@@ -308,6 +340,20 @@ func (s *OdooService) getCountryID(countryCode string) interface{} {
 	return countryIDs[0]
 }
 
+func (s *OdooService) getCurrencyID(currencyCode string) interface{} {
+	// Search for currency by ISO code
+	domain := []interface{}{
+		[]interface{}{"name", "=", currencyCode},
+	}
+
+	currencyIDs, err := s.client.Search("res.currency", domain, map[string]interface{}{"limit": 1})
+	if err != nil || len(currencyIDs) == 0 {
+		return false
+	}
+
+	return currencyIDs[0]
+}
+
 func (s *OdooService) getOrCreateAgent(agentID uuid.UUID, agentName string) (int, error) {
 	// Search for existing agent by Comply360 ID
 	domain := []interface{}{
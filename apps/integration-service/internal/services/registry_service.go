@@ -0,0 +1,243 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/comply360/integration-service/internal/adapters"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// RegistryService handles companies registry operations, such as CIPC in
+// South Africa or DCIP in Zimbabwe, choosing the registry by jurisdiction
+type RegistryService struct {
+	registries map[string]adapters.Registry
+	channel    *amqp.Channel
+}
+
+// NewRegistryService creates a registry service over each jurisdiction's
+// registry
+func NewRegistryService(registries []adapters.Registry, rabbitConn *amqp.Connection) (*RegistryService, error) {
+	channel, err := rabbitConn.Channel()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open RabbitMQ channel: %w", err)
+	}
+
+	// Declare registry event queue
+	_, err = channel.QueueDeclare(
+		"registry_events", // queue name
+		true,              // durable
+		false,             // delete when unused
+		false,             // exclusive
+		false,             // no-wait
+		nil,               // arguments
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to declare registry_events queue: %w", err)
+	}
+
+	byJurisdiction := map[string]adapters.Registry{}
+	for _, registry := range registries {
+		byJurisdiction[registry.Jurisdiction()] = registry
+	}
+
+	log.Println("Registry Service initialized")
+	return &RegistryService{
+		registries: byJurisdiction,
+		channel:    channel,
+	}, nil
+}
+
+// Jurisdictions lists the jurisdictions with a registry, ordered by code
+func (s *RegistryService) Jurisdictions() []string {
+	codes := make([]string, 0, len(s.registries))
+	for code := range s.registries {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// Registry returns the registry of a jurisdiction, given in either case
+func (s *RegistryService) Registry(jurisdiction string) (adapters.Registry, error) {
+	registry, ok := s.registries[strings.ToUpper(jurisdiction)]
+	if !ok {
+		return nil, fmt.Errorf("unsupported jurisdiction")
+	}
+	return registry, nil
+}
+
+// SearchCompany searches a jurisdiction's registry for companies by name
+func (s *RegistryService) SearchCompany(jurisdiction, companyName string) ([]adapters.CompanySearchResult, error) {
+	registry, err := s.Registry(jurisdiction)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Service: Searching %s for company: %s", registry.Name(), companyName)
+
+	results, err := registry.SearchCompanyByName(companyName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search %s: %w", registry.Name(), err)
+	}
+
+	// Publish event
+	s.publishEvent(registry, "company_searched", map[string]interface{}{
+		"company_name":  companyName,
+		"results_count": len(results),
+	})
+
+	return results, nil
+}
+
+// GetCompanyDetails retrieves detailed company information
+func (s *RegistryService) GetCompanyDetails(jurisdiction, registrationNumber string) (*adapters.CompanyDetails, error) {
+	registry, err := s.Registry(jurisdiction)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Service: Getting %s company details for: %s", registry.Name(), registrationNumber)
+
+	details, err := registry.GetCompanyDetails(registrationNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get company details: %w", err)
+	}
+
+	// Publish event
+	s.publishEvent(registry, "company_details_retrieved", map[string]interface{}{
+		"registration_number": registrationNumber,
+		"company_name":        details.CompanyName,
+		"status":              details.Status,
+	})
+
+	return details, nil
+}
+
+// ValidateCompany validates a company's registration
+func (s *RegistryService) ValidateCompany(jurisdiction, registrationNumber string) (*adapters.ValidationResult, error) {
+	registry, err := s.Registry(jurisdiction)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Service: Validating %s registration: %s", registry.Name(), registrationNumber)
+
+	result, err := registry.ValidateCompany(registrationNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate company: %w", err)
+	}
+
+	// Publish event
+	s.publishEvent(registry, "company_validated", map[string]interface{}{
+		"registration_number": registrationNumber,
+		"valid":               result.Valid,
+		"status":              result.Status,
+	})
+
+	return result, nil
+}
+
+// CheckStatus checks a company's current status
+func (s *RegistryService) CheckStatus(jurisdiction, registrationNumber string) (string, error) {
+	registry, err := s.Registry(jurisdiction)
+	if err != nil {
+		return "", err
+	}
+	log.Printf("Service: Checking %s status for: %s", registry.Name(), registrationNumber)
+
+	status, err := registry.CheckCompanyStatus(registrationNumber)
+	if err != nil {
+		return "", fmt.Errorf("failed to check company status: %w", err)
+	}
+
+	// Publish event
+	s.publishEvent(registry, "company_status_checked", map[string]interface{}{
+		"registration_number": registrationNumber,
+		"status":              status,
+	})
+
+	return status, nil
+}
+
+// ReserveNames lodges a name reservation with a jurisdiction's registry
+func (s *RegistryService) ReserveNames(jurisdiction string, request *adapters.NameReservationRequest) (*adapters.NameReservation, error) {
+	registry, err := s.Registry(jurisdiction)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Service: Reserving %s names for: %s", registry.Name(), request.CustomerReference)
+
+	reservation, err := registry.ReserveNames(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reserve names: %w", err)
+	}
+
+	// Publish event
+	s.publishEvent(registry, "name_reservation_submitted", map[string]interface{}{
+		"reference":          reservation.Reference,
+		"customer_reference": request.CustomerReference,
+		"names_count":        len(request.Names),
+	})
+
+	return reservation, nil
+}
+
+// GetNameReservation retrieves a name reservation and the registry's decision
+// on it
+func (s *RegistryService) GetNameReservation(jurisdiction, reference string) (*adapters.NameReservation, error) {
+	registry, err := s.Registry(jurisdiction)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Service: Getting %s name reservation: %s", registry.Name(), reference)
+
+	reservation, err := registry.GetNameReservation(reference)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get name reservation: %w", err)
+	}
+
+	return reservation, nil
+}
+
+// publishEvent publishes an event about a registry call to RabbitMQ
+func (s *RegistryService) publishEvent(registry adapters.Registry, eventType string, data map[string]interface{}) {
+	event := map[string]interface{}{
+		"event_type":   eventType,
+		"jurisdiction": registry.Jurisdiction(),
+		"registry":     registry.Name(),
+		"timestamp":    fmt.Sprintf("%d", amqp.Publishing{}.Timestamp.Unix()),
+		"data":         data,
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Failed to marshal event: %v", err)
+		return
+	}
+
+	err = s.channel.Publish(
+		"",                // exchange
+		"registry_events", // routing key (queue name)
+		false,             // mandatory
+		false,             // immediate
+		amqp.Publishing{
+			ContentType: "application/json",
+			Body:        body,
+		},
+	)
+
+	if err != nil {
+		log.Printf("Failed to publish event: %v", err)
+	} else {
+		log.Printf("Published %s event: %s", registry.Name(), eventType)
+	}
+}
+
+// Close closes the RabbitMQ channel
+func (s *RegistryService) Close() error {
+	if s.channel != nil {
+		return s.channel.Close()
+	}
+	return nil
+}
@@ -13,7 +13,9 @@ import (
 	"github.com/comply360/notification-service/internal/handlers"
 	"github.com/comply360/notification-service/internal/repository"
 	"github.com/comply360/notification-service/internal/services"
+	"github.com/comply360/shared/branding"
 	sharedmiddleware "github.com/comply360/shared/middleware"
+	"github.com/comply360/shared/settings"
 	"github.com/gin-gonic/gin"
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
@@ -36,6 +38,7 @@ func main() {
 	smtpPassword := getEnv("SMTP_PASSWORD", "")
 	fromEmail := getEnv("FROM_EMAIL", "noreply@comply360.africa")
 	fromName := getEnv("FROM_NAME", "Comply360")
+	publicAPIURL := getEnv("API_URL", "http://localhost:8080")
 
 	// SMS configuration
 	smsAPIKey := getEnv("SMS_API_KEY", "")
@@ -75,8 +78,17 @@ func main() {
 	// Initialize repositories
 	notificationRepo := repository.NewNotificationRepository(dbx)
 
+	// Tenant branding comes from tenant settings; changes are picked up from tenant events
+	settingsClient := settings.NewClient(db, nil, time.Minute)
+	if err := settingsClient.ListenForChanges(rabbitConn); err != nil {
+		log.Printf("Warning: Failed to subscribe to tenant settings changes: %v", err)
+	}
+	brandDefaults := branding.Defaults()
+	brandDefaults.SenderName = fromName
+	brands := branding.NewResolver(settingsClient, brandDefaults, publicAPIURL)
+
 	// Initialize email service
-	emailService := services.NewEmailService(smtpHost, smtpPort, smtpUsername, smtpPassword, fromEmail, fromName)
+	emailService := services.NewEmailService(smtpHost, smtpPort, smtpUsername, smtpPassword, fromEmail, brands)
 
 	// Initialize SMS service
 	smsService := services.NewSMSService(smsAPIKey, smsAPISecret, smsFromNumber, smsProvider)
@@ -49,6 +49,9 @@ func (c *EventConsumer) Start() error {
 	// Start consuming commission events
 	go c.consumeCommissionEvents()
 
+	// Start consuming compliance reminders
+	go c.consumeComplianceEvents()
+
 	log.Println("Event consumer started successfully")
 	return nil
 }
@@ -60,6 +63,7 @@ func (c *EventConsumer) setupQueues() error {
 		"comply360.notifications.registration",
 		"comply360.notifications.document",
 		"comply360.notifications.commission",
+		"comply360.notifications.compliance",
 	}
 
 	for _, queueName := range queues {
@@ -135,6 +139,25 @@ func (c *EventConsumer) setupQueues() error {
 		}
 	}
 
+	// Bind compliance reminders, published by the registration service
+	complianceBindings := []string{
+		"compliance.reminder",
+		"compliance.overdue",
+	}
+
+	for _, routingKey := range complianceBindings {
+		err := c.channel.QueueBind(
+			"comply360.notifications.compliance", // queue name
+			routingKey,                            // routing key
+			"comply360.registrations",             // exchange
+			false,
+			nil,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to bind queue for %s: %w", routingKey, err)
+		}
+	}
+
 	log.Println("RabbitMQ queues and bindings configured successfully")
 	return nil
 }
@@ -202,6 +225,27 @@ func (c *EventConsumer) consumeCommissionEvents() {
 	}
 }
 
+// consumeComplianceEvents consumes compliance reminders
+func (c *EventConsumer) consumeComplianceEvents() {
+	msgs, err := c.channel.Consume(
+		"comply360.notifications.compliance", // queue
+		"",                                    // consumer
+		false,                                 // auto-ack
+		false,                                 // exclusive
+		false,                                 // no-local
+		false,                                 // no-wait
+		nil,                                   // args
+	)
+	if err != nil {
+		log.Printf("Failed to consume compliance events: %v", err)
+		return
+	}
+
+	for msg := range msgs {
+		c.handleComplianceEvent(msg)
+	}
+}
+
 // handleRegistrationEvent handles registration events
 func (c *EventConsumer) handleRegistrationEvent(msg amqp.Delivery) {
 	routingKey := msg.RoutingKey
@@ -222,9 +266,9 @@ func (c *EventConsumer) handleRegistrationEvent(msg amqp.Delivery) {
 	var err error
 	switch routingKey {
 	case "registration.created":
-		err = c.emailService.SendRegistrationCreatedEmail(clientEmail, clientName, registration.CompanyName)
+		err = c.emailService.SendRegistrationCreatedEmail(registration.TenantID, clientEmail, clientName, registration.CompanyName)
 	case "registration.status.submitted":
-		err = c.emailService.SendRegistrationSubmittedEmail(clientEmail, clientName, registration.CompanyName, registration.ID.String())
+		err = c.emailService.SendRegistrationSubmittedEmail(registration.TenantID, clientEmail, clientName, registration.CompanyName, registration.ID.String())
 		// Also send SMS
 		c.smsService.SendRegistrationStatusSMS("+1234567890", clientName, registration.CompanyName, "submitted")
 	case "registration.status.approved":
@@ -232,14 +276,14 @@ func (c *EventConsumer) handleRegistrationEvent(msg amqp.Delivery) {
 		if registration.RegistrationNumber != nil {
 			regNumber = *registration.RegistrationNumber
 		}
-		err = c.emailService.SendRegistrationApprovedEmail(clientEmail, clientName, registration.CompanyName, regNumber)
+		err = c.emailService.SendRegistrationApprovedEmail(registration.TenantID, clientEmail, clientName, registration.CompanyName, regNumber)
 		c.smsService.SendRegistrationStatusSMS("+1234567890", clientName, registration.CompanyName, "approved")
 	case "registration.status.rejected":
 		reason := "Please review and resubmit"
 		if registration.RejectionReason != nil {
 			reason = *registration.RejectionReason
 		}
-		err = c.emailService.SendRegistrationRejectedEmail(clientEmail, clientName, registration.CompanyName, reason)
+		err = c.emailService.SendRegistrationRejectedEmail(registration.TenantID, clientEmail, clientName, registration.CompanyName, reason)
 		c.smsService.SendRegistrationStatusSMS("+1234567890", clientName, registration.CompanyName, "rejected")
 	}
 
@@ -272,9 +316,9 @@ func (c *EventConsumer) handleDocumentEvent(msg amqp.Delivery) {
 	var err error
 	switch routingKey {
 	case "document.uploaded":
-		err = c.emailService.SendDocumentUploadedEmail(clientEmail, clientName, document.DocumentType, document.FileName)
+		err = c.emailService.SendDocumentUploadedEmail(document.TenantID, clientEmail, clientName, document.DocumentType, document.FileName)
 	case "document.verified":
-		err = c.emailService.SendDocumentVerifiedEmail(clientEmail, clientName, document.DocumentType)
+		err = c.emailService.SendDocumentVerifiedEmail(document.TenantID, clientEmail, clientName, document.DocumentType)
 		c.smsService.SendDocumentVerifiedSMS("+1234567890", clientName, document.DocumentType)
 	}
 
@@ -307,13 +351,13 @@ func (c *EventConsumer) handleCommissionEvent(msg amqp.Delivery) {
 	var err error
 	switch routingKey {
 	case "commission.approved":
-		err = c.emailService.SendCommissionApprovedEmail(agentEmail, agentName, commission.CommissionAmount, commission.Currency)
+		err = c.emailService.SendCommissionApprovedEmail(commission.TenantID, agentEmail, agentName, commission.CommissionAmount, commission.Currency)
 	case "commission.paid":
 		paymentRef := "N/A"
 		if commission.PaymentReference != nil {
 			paymentRef = *commission.PaymentReference
 		}
-		err = c.emailService.SendCommissionPaidEmail(agentEmail, agentName, commission.CommissionAmount, commission.Currency, paymentRef)
+		err = c.emailService.SendCommissionPaidEmail(commission.TenantID, agentEmail, agentName, commission.CommissionAmount, commission.Currency, paymentRef)
 		c.smsService.SendCommissionPaidSMS("+1234567890", agentName, commission.CommissionAmount, commission.Currency)
 	}
 
@@ -327,6 +371,41 @@ func (c *EventConsumer) handleCommissionEvent(msg amqp.Delivery) {
 	log.Printf("Successfully processed commission event: %s", routingKey)
 }
 
+// handleComplianceEvent handles compliance reminders. Reminders carry the
+// client's contact details.
+func (c *EventConsumer) handleComplianceEvent(msg amqp.Delivery) {
+	routingKey := msg.RoutingKey
+	log.Printf("Received compliance event: %s", routingKey)
+
+	var reminder models.ComplianceReminder
+	if err := json.Unmarshal(msg.Body, &reminder); err != nil {
+		log.Printf("Failed to unmarshal compliance event: %v", err)
+		msg.Nack(false, false)
+		return
+	}
+	if reminder.ClientEmail == "" {
+		log.Printf("Skipping compliance reminder %s: client has no email address", reminder.ObligationID)
+		msg.Ack(false)
+		return
+	}
+
+	var err error
+	switch routingKey {
+	case "compliance.reminder", "compliance.overdue":
+		err = c.emailService.SendComplianceReminderEmail(reminder.TenantID, reminder.ClientEmail, reminder.ClientName,
+			reminder.CompanyName, reminder.Title, reminder.Authority, reminder.DueDate, reminder.DaysRemaining)
+	}
+
+	if err != nil {
+		log.Printf("Failed to send notification: %v", err)
+		msg.Nack(false, true)
+		return
+	}
+
+	msg.Ack(false)
+	log.Printf("Successfully processed compliance event: %s", routingKey)
+}
+
 // Close closes the consumer
 func (c *EventConsumer) Close() error {
 	if c.channel != nil {
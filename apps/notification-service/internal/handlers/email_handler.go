@@ -5,7 +5,9 @@ import (
 
 	"github.com/comply360/notification-service/internal/services"
 	"github.com/comply360/shared/errors"
+	sharedmiddleware "github.com/comply360/shared/middleware"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 // EmailHandler handles email-related HTTP requests
@@ -51,10 +53,11 @@ func (h *EmailHandler) SendEmail(c *gin.Context) {
 	}
 
 	msg := services.EmailMessage{
-		To:      req.To,
-		Subject: req.Subject,
-		Body:    req.Body,
-		IsHTML:  req.IsHTML,
+		TenantID: requestTenantID(c),
+		To:       req.To,
+		Subject:  req.Subject,
+		Body:     req.Body,
+		IsHTML:   req.IsHTML,
 	}
 
 	if err := h.emailService.SendEmail(msg); err != nil {
@@ -87,7 +90,7 @@ func (h *EmailHandler) SendRegistrationCreated(c *gin.Context) {
 		return
 	}
 
-	if err := h.emailService.SendRegistrationCreatedEmail(req.Email, req.ClientName, req.CompanyName); err != nil {
+	if err := h.emailService.SendRegistrationCreatedEmail(requestTenantID(c), req.Email, req.ClientName, req.CompanyName); err != nil {
 		c.JSON(http.StatusInternalServerError, errors.NewAPIErrorWithDetails(
 			errors.ErrInternalServer,
 			"Failed to send email",
@@ -118,7 +121,7 @@ func (h *EmailHandler) SendRegistrationSubmitted(c *gin.Context) {
 		return
 	}
 
-	if err := h.emailService.SendRegistrationSubmittedEmail(req.Email, req.ClientName, req.CompanyName, req.RegistrationNumber); err != nil {
+	if err := h.emailService.SendRegistrationSubmittedEmail(requestTenantID(c), req.Email, req.ClientName, req.CompanyName, req.RegistrationNumber); err != nil {
 		c.JSON(http.StatusInternalServerError, errors.NewAPIErrorWithDetails(
 			errors.ErrInternalServer,
 			"Failed to send email",
@@ -149,7 +152,7 @@ func (h *EmailHandler) SendRegistrationApproved(c *gin.Context) {
 		return
 	}
 
-	if err := h.emailService.SendRegistrationApprovedEmail(req.Email, req.ClientName, req.CompanyName, req.RegistrationNumber); err != nil {
+	if err := h.emailService.SendRegistrationApprovedEmail(requestTenantID(c), req.Email, req.ClientName, req.CompanyName, req.RegistrationNumber); err != nil {
 		c.JSON(http.StatusInternalServerError, errors.NewAPIErrorWithDetails(
 			errors.ErrInternalServer,
 			"Failed to send email",
@@ -180,7 +183,7 @@ func (h *EmailHandler) SendRegistrationRejected(c *gin.Context) {
 		return
 	}
 
-	if err := h.emailService.SendRegistrationRejectedEmail(req.Email, req.ClientName, req.CompanyName, req.Reason); err != nil {
+	if err := h.emailService.SendRegistrationRejectedEmail(requestTenantID(c), req.Email, req.ClientName, req.CompanyName, req.Reason); err != nil {
 		c.JSON(http.StatusInternalServerError, errors.NewAPIErrorWithDetails(
 			errors.ErrInternalServer,
 			"Failed to send email",
@@ -211,7 +214,7 @@ func (h *EmailHandler) SendDocumentUploaded(c *gin.Context) {
 		return
 	}
 
-	if err := h.emailService.SendDocumentUploadedEmail(req.Email, req.ClientName, req.DocumentType, req.FileName); err != nil {
+	if err := h.emailService.SendDocumentUploadedEmail(requestTenantID(c), req.Email, req.ClientName, req.DocumentType, req.FileName); err != nil {
 		c.JSON(http.StatusInternalServerError, errors.NewAPIErrorWithDetails(
 			errors.ErrInternalServer,
 			"Failed to send email",
@@ -241,7 +244,7 @@ func (h *EmailHandler) SendDocumentVerified(c *gin.Context) {
 		return
 	}
 
-	if err := h.emailService.SendDocumentVerifiedEmail(req.Email, req.ClientName, req.DocumentType); err != nil {
+	if err := h.emailService.SendDocumentVerifiedEmail(requestTenantID(c), req.Email, req.ClientName, req.DocumentType); err != nil {
 		c.JSON(http.StatusInternalServerError, errors.NewAPIErrorWithDetails(
 			errors.ErrInternalServer,
 			"Failed to send email",
@@ -272,7 +275,7 @@ func (h *EmailHandler) SendCommissionApproved(c *gin.Context) {
 		return
 	}
 
-	if err := h.emailService.SendCommissionApprovedEmail(req.Email, req.AgentName, req.Amount, req.Currency); err != nil {
+	if err := h.emailService.SendCommissionApprovedEmail(requestTenantID(c), req.Email, req.AgentName, req.Amount, req.Currency); err != nil {
 		c.JSON(http.StatusInternalServerError, errors.NewAPIErrorWithDetails(
 			errors.ErrInternalServer,
 			"Failed to send email",
@@ -304,7 +307,7 @@ func (h *EmailHandler) SendCommissionPaid(c *gin.Context) {
 		return
 	}
 
-	if err := h.emailService.SendCommissionPaidEmail(req.Email, req.AgentName, req.Amount, req.Currency, req.PaymentReference); err != nil {
+	if err := h.emailService.SendCommissionPaidEmail(requestTenantID(c), req.Email, req.AgentName, req.Amount, req.Currency, req.PaymentReference); err != nil {
 		c.JSON(http.StatusInternalServerError, errors.NewAPIErrorWithDetails(
 			errors.ErrInternalServer,
 			"Failed to send email",
@@ -317,3 +320,16 @@ func (h *EmailHandler) SendCommissionPaid(c *gin.Context) {
 		"message": "Commission paid email sent successfully",
 	})
 }
+
+// requestTenantID returns the tenant the gateway resolved for the request, or
+// uuid.Nil for platform email
+func requestTenantID(c *gin.Context) uuid.UUID {
+	if tenantID, err := sharedmiddleware.GetTenantID(c); err == nil {
+		return tenantID
+	}
+	tenantID, err := uuid.Parse(c.GetHeader("X-Tenant-ID"))
+	if err != nil {
+		return uuid.Nil
+	}
+	return tenantID
+}
@@ -1,12 +1,19 @@
 package services
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
+	"html"
 	"log"
+	"mime"
 	"net"
 	"net/smtp"
 	"strings"
+	"time"
+
+	"github.com/comply360/shared/branding"
+	"github.com/google/uuid"
 )
 
 // EmailService handles sending emails
@@ -16,31 +23,42 @@ type EmailService struct {
 	smtpUsername string
 	smtpPassword string
 	fromEmail    string
-	fromName     string
+	brands       *branding.Resolver
 }
 
 // EmailMessage represents an email to be sent
 type EmailMessage struct {
-	To      []string
-	Subject string
-	Body    string
-	IsHTML  bool
+	// TenantID selects the branding applied to the message; uuid.Nil uses the platform branding
+	TenantID uuid.UUID
+	To       []string
+	Subject  string
+	Body     string
+	IsHTML   bool
 }
 
-// NewEmailService creates a new email service
-func NewEmailService(smtpHost string, smtpPort int, smtpUsername, smtpPassword, fromEmail, fromName string) *EmailService {
+// NewEmailService creates a new email service. Sender names, reply-to addresses,
+// sign-offs and footers come from the tenant's branding.
+func NewEmailService(smtpHost string, smtpPort int, smtpUsername, smtpPassword, fromEmail string, brands *branding.Resolver) *EmailService {
 	return &EmailService{
 		smtpHost:     smtpHost,
 		smtpPort:     smtpPort,
 		smtpUsername: smtpUsername,
 		smtpPassword: smtpPassword,
 		fromEmail:    fromEmail,
-		fromName:     fromName,
+		brands:       brands,
 	}
 }
 
+// branding returns the branding for a tenant's email
+func (s *EmailService) branding(tenantID uuid.UUID) branding.Branding {
+	return s.brands.For(context.Background(), tenantID)
+}
+
 // SendEmail sends an email via SMTP
 func (s *EmailService) SendEmail(msg EmailMessage) error {
+	brand := s.branding(msg.TenantID)
+	msg.Body = withFooter(msg.Body, brand, msg.IsHTML)
+
 	// Development mode: Just log if SMTP not configured
 	if s.smtpHost == "" || s.smtpHost == "localhost" {
 		log.Printf("[EMAIL - DEV MODE] To: %v | Subject: %s | Body: %s\n", msg.To, msg.Subject, msg.Body)
@@ -51,9 +69,12 @@ func (s *EmailService) SendEmail(msg EmailMessage) error {
 	log.Printf("[EMAIL] Sending to: %v | Subject: %s", msg.To, msg.Subject)
 
 	// Build email headers
-	from := fmt.Sprintf("%s <%s>", s.fromName, s.fromEmail)
+	from := fmt.Sprintf("%s <%s>", mime.QEncoding.Encode("utf-8", brand.SenderName), s.fromEmail)
 	headers := make(map[string]string)
 	headers["From"] = from
+	if brand.ReplyTo != nil {
+		headers["Reply-To"] = *brand.ReplyTo
+	}
 	headers["To"] = strings.Join(msg.To, ", ")
 	headers["Subject"] = msg.Subject
 	headers["MIME-Version"] = "1.0"
@@ -207,11 +228,23 @@ func (s *EmailService) sendViaSTARTTLS(addr string, to []string, message []byte)
 	return nil
 }
 
+// withFooter appends the tenant's legal footer to a message body
+func withFooter(body string, brand branding.Branding, isHTML bool) string {
+	if brand.LegalFooter == "" {
+		return body
+	}
+	if isHTML {
+		return body + "<hr><p style=\"font-size:12px;color:#666\">" + html.EscapeString(brand.LegalFooter) + "</p>"
+	}
+	return body + "\n\n--\n" + brand.LegalFooter
+}
+
 // SendRegistrationCreatedEmail sends notification when registration is created
-func (s *EmailService) SendRegistrationCreatedEmail(toEmail, clientName, companyName string) error {
+func (s *EmailService) SendRegistrationCreatedEmail(tenantID uuid.UUID, toEmail, clientName, companyName string) error {
 	msg := EmailMessage{
-		To:      []string{toEmail},
-		Subject: "Registration Created - " + companyName,
+		TenantID: tenantID,
+		To:       []string{toEmail},
+		Subject:  "Registration Created - " + companyName,
 		Body: fmt.Sprintf(`Dear %s,
 
 Your company registration for %s has been successfully created and is now in draft status.
@@ -224,7 +257,7 @@ Next steps:
 You can track the progress of your registration in your dashboard.
 
 Best regards,
-Comply360 Team`, clientName, companyName),
+%s`, clientName, companyName, s.branding(tenantID).Signature()),
 		IsHTML: false,
 	}
 
@@ -232,10 +265,11 @@ Comply360 Team`, clientName, companyName),
 }
 
 // SendRegistrationSubmittedEmail sends notification when registration is submitted
-func (s *EmailService) SendRegistrationSubmittedEmail(toEmail, clientName, companyName, registrationNumber string) error {
+func (s *EmailService) SendRegistrationSubmittedEmail(tenantID uuid.UUID, toEmail, clientName, companyName, registrationNumber string) error {
 	msg := EmailMessage{
-		To:      []string{toEmail},
-		Subject: "Registration Submitted - " + companyName,
+		TenantID: tenantID,
+		To:       []string{toEmail},
+		Subject:  "Registration Submitted - " + companyName,
 		Body: fmt.Sprintf(`Dear %s,
 
 Your company registration for %s has been successfully submitted for review.
@@ -247,7 +281,7 @@ Our team will review your application and supporting documents. You will receive
 Typical review time: 2-5 business days
 
 Best regards,
-Comply360 Team`, clientName, companyName, registrationNumber),
+%s`, clientName, companyName, registrationNumber, s.branding(tenantID).Signature()),
 		IsHTML: false,
 	}
 
@@ -255,10 +289,11 @@ Comply360 Team`, clientName, companyName, registrationNumber),
 }
 
 // SendRegistrationApprovedEmail sends notification when registration is approved
-func (s *EmailService) SendRegistrationApprovedEmail(toEmail, clientName, companyName, registrationNumber string) error {
+func (s *EmailService) SendRegistrationApprovedEmail(tenantID uuid.UUID, toEmail, clientName, companyName, registrationNumber string) error {
 	msg := EmailMessage{
-		To:      []string{toEmail},
-		Subject: "Registration Approved - " + companyName,
+		TenantID: tenantID,
+		To:       []string{toEmail},
+		Subject:  "Registration Approved - " + companyName,
 		Body: fmt.Sprintf(`Dear %s,
 
 Congratulations! Your company registration for %s has been approved.
@@ -268,7 +303,7 @@ Registration Number: %s
 The registration documents will be processed and you will receive the official registration certificate shortly.
 
 Best regards,
-Comply360 Team`, clientName, companyName, registrationNumber),
+%s`, clientName, companyName, registrationNumber, s.branding(tenantID).Signature()),
 		IsHTML: false,
 	}
 
@@ -276,10 +311,11 @@ Comply360 Team`, clientName, companyName, registrationNumber),
 }
 
 // SendRegistrationRejectedEmail sends notification when registration is rejected
-func (s *EmailService) SendRegistrationRejectedEmail(toEmail, clientName, companyName, reason string) error {
+func (s *EmailService) SendRegistrationRejectedEmail(tenantID uuid.UUID, toEmail, clientName, companyName, reason string) error {
 	msg := EmailMessage{
-		To:      []string{toEmail},
-		Subject: "Registration Rejected - " + companyName,
+		TenantID: tenantID,
+		To:       []string{toEmail},
+		Subject:  "Registration Rejected - " + companyName,
 		Body: fmt.Sprintf(`Dear %s,
 
 Unfortunately, your company registration for %s has been rejected.
@@ -291,7 +327,7 @@ You can review the feedback and resubmit your application after addressing the i
 If you have any questions, please contact our support team.
 
 Best regards,
-Comply360 Team`, clientName, companyName, reason),
+%s`, clientName, companyName, reason, s.branding(tenantID).Signature()),
 		IsHTML: false,
 	}
 
@@ -299,10 +335,11 @@ Comply360 Team`, clientName, companyName, reason),
 }
 
 // SendDocumentUploadedEmail sends notification when document is uploaded
-func (s *EmailService) SendDocumentUploadedEmail(toEmail, clientName, documentType, fileName string) error {
+func (s *EmailService) SendDocumentUploadedEmail(tenantID uuid.UUID, toEmail, clientName, documentType, fileName string) error {
 	msg := EmailMessage{
-		To:      []string{toEmail},
-		Subject: "Document Uploaded - " + documentType,
+		TenantID: tenantID,
+		To:       []string{toEmail},
+		Subject:  "Document Uploaded - " + documentType,
 		Body: fmt.Sprintf(`Dear %s,
 
 Your document has been successfully uploaded.
@@ -313,7 +350,7 @@ File Name: %s
 The document will be reviewed by our team shortly.
 
 Best regards,
-Comply360 Team`, clientName, documentType, fileName),
+%s`, clientName, documentType, fileName, s.branding(tenantID).Signature()),
 		IsHTML: false,
 	}
 
@@ -321,10 +358,11 @@ Comply360 Team`, clientName, documentType, fileName),
 }
 
 // SendDocumentVerifiedEmail sends notification when document is verified
-func (s *EmailService) SendDocumentVerifiedEmail(toEmail, clientName, documentType string) error {
+func (s *EmailService) SendDocumentVerifiedEmail(tenantID uuid.UUID, toEmail, clientName, documentType string) error {
 	msg := EmailMessage{
-		To:      []string{toEmail},
-		Subject: "Document Verified - " + documentType,
+		TenantID: tenantID,
+		To:       []string{toEmail},
+		Subject:  "Document Verified - " + documentType,
 		Body: fmt.Sprintf(`Dear %s,
 
 Your document has been successfully verified.
@@ -334,7 +372,7 @@ Document Type: %s
 Status: Verified ✓
 
 Best regards,
-Comply360 Team`, clientName, documentType),
+%s`, clientName, documentType, s.branding(tenantID).Signature()),
 		IsHTML: false,
 	}
 
@@ -342,10 +380,11 @@ Comply360 Team`, clientName, documentType),
 }
 
 // SendCommissionApprovedEmail sends notification when commission is approved
-func (s *EmailService) SendCommissionApprovedEmail(toEmail, agentName string, amount float64, currency string) error {
+func (s *EmailService) SendCommissionApprovedEmail(tenantID uuid.UUID, toEmail, agentName string, amount float64, currency string) error {
 	msg := EmailMessage{
-		To:      []string{toEmail},
-		Subject: "Commission Approved",
+		TenantID: tenantID,
+		To:       []string{toEmail},
+		Subject:  "Commission Approved",
 		Body: fmt.Sprintf(`Dear %s,
 
 Your commission has been approved.
@@ -355,7 +394,7 @@ Amount: %s %.2f
 The payment will be processed according to the payment schedule.
 
 Best regards,
-Comply360 Team`, agentName, currency, amount),
+%s`, agentName, currency, amount, s.branding(tenantID).Signature()),
 		IsHTML: false,
 	}
 
@@ -363,10 +402,11 @@ Comply360 Team`, agentName, currency, amount),
 }
 
 // SendCommissionPaidEmail sends notification when commission is paid
-func (s *EmailService) SendCommissionPaidEmail(toEmail, agentName string, amount float64, currency, paymentReference string) error {
+func (s *EmailService) SendCommissionPaidEmail(tenantID uuid.UUID, toEmail, agentName string, amount float64, currency, paymentReference string) error {
 	msg := EmailMessage{
-		To:      []string{toEmail},
-		Subject: "Commission Paid",
+		TenantID: tenantID,
+		To:       []string{toEmail},
+		Subject:  "Commission Paid",
 		Body: fmt.Sprintf(`Dear %s,
 
 Your commission has been paid.
@@ -377,7 +417,44 @@ Payment Reference: %s
 Please check your account for the payment.
 
 Best regards,
-Comply360 Team`, agentName, currency, amount, paymentReference),
+%s`, agentName, currency, amount, paymentReference, s.branding(tenantID).Signature()),
+		IsHTML: false,
+	}
+
+	return s.SendEmail(msg)
+}
+
+// SendComplianceReminderEmail reminds a client of a compliance filing falling
+// due, or that it is overdue
+func (s *EmailService) SendComplianceReminderEmail(tenantID uuid.UUID, toEmail, clientName, companyName, title, authority string, dueDate time.Time, daysRemaining int) error {
+	subject := "Compliance Reminder - " + title
+	var when string
+	switch {
+	case daysRemaining < 0:
+		subject = "Compliance Overdue - " + title
+		when = "was due"
+	case daysRemaining == 0:
+		when = "is due today,"
+	case daysRemaining == 1:
+		when = "is due tomorrow,"
+	default:
+		when = fmt.Sprintf("is due in %d days,", daysRemaining)
+	}
+
+	msg := EmailMessage{
+		TenantID: tenantID,
+		To:       []string{toEmail},
+		Subject:  subject + " (" + companyName + ")",
+		Body: fmt.Sprintf(`Dear %s,
+
+The %s for %s %s on %s.
+
+Filed with: %s
+
+Please let us know if the filing has already been made, or contact us if you need help with it.
+
+Best regards,
+%s`, clientName, title, companyName, when, dueDate.Format("2 January 2006"), authority, s.branding(tenantID).Signature()),
 		IsHTML: false,
 	}
 
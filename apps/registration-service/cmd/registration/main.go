@@ -11,13 +11,17 @@ import (
 	"strconv"
 	"syscall"
 	"time"
+	_ "time/tzdata"
 
 	"github.com/comply360/registration-service/internal/events"
 	"github.com/comply360/registration-service/internal/handlers"
+	"github.com/comply360/registration-service/internal/integration"
 	"github.com/comply360/registration-service/internal/repository"
 	"github.com/comply360/registration-service/internal/services"
 	sharedmiddleware "github.com/comply360/shared/middleware"
+	"github.com/comply360/shared/models"
 	sharedsentry "github.com/comply360/shared/sentry"
+	"github.com/comply360/shared/settings"
 	"github.com/comply360/shared/websocket"
 	"github.com/gin-gonic/gin"
 	_ "github.com/lib/pq"
@@ -95,6 +99,13 @@ func main() {
 	// Initialize repositories
 	registrationRepo := repository.NewRegistrationRepository(db)
 	clientRepo := repository.NewClientRepository(db)
+	queueRepo := repository.NewQueueRepository(db)
+	searchRepo := repository.NewSearchRepository(db)
+	importRepo := repository.NewImportRepository(db)
+	nameReservationRepo := repository.NewNameReservationRepository(db)
+	complianceRepo := repository.NewComplianceRepository(db)
+	registerRepo := repository.NewRegisterRepository(db)
+	feeRepo := repository.NewFeeRepository(db)
 
 	// Initialize WebSocket client for real-time notifications
 	wsClient := websocket.NewWebSocketClient(websocketServiceURL)
@@ -107,11 +118,59 @@ func main() {
 	}
 	defer registrationService.Close()
 
+	// Review queues assign submitted registrations to reviewers and track SLAs
+	// in each tenant's timezone
+	settingsClient := settings.NewClient(db, nil, 5*time.Minute)
+	queueService, err := services.NewQueueService(queueRepo, registrationRepo, rabbitConn, settingsClient)
+	if err != nil {
+		log.Fatalf("Failed to create queue service: %v", err)
+	}
+	defer queueService.Close()
+	registrationService.UseQueues(queueService)
+
 	clientService := services.NewClientService(clientRepo)
+	searchService := services.NewSearchService(searchRepo)
+
+	// Bulk imports run in the background; fail any cut short by a restart
+	importService := services.NewImportService(importRepo, registrationService)
+	importService.RecoverInterrupted()
+
+	// Company names are reserved with CIPC through the integration service
+	nameReservationService := services.NewNameReservationService(nameReservationRepo, registrationRepo, integration.NewClient(integrationServiceURL))
+
+	// Completed registrations are tracked for their recurring filings. Calendar
+	// feed URLs are signed so calendar apps can subscribe without signing in.
+	// SECURITY: Feeds are signed with their own secret, never the JWT secret
+	complianceFeedSecret := os.Getenv("COMPLIANCE_FEED_SECRET")
+	if len(complianceFeedSecret) < 32 {
+		log.Fatalf("FATAL: COMPLIANCE_FEED_SECRET must be set to at least 32 characters (current length: %d)", len(complianceFeedSecret))
+	}
+	if complianceFeedSecret == jwtSecret {
+		log.Fatal("FATAL: COMPLIANCE_FEED_SECRET must differ from JWT_SECRET")
+	}
+	complianceFeedBaseURL := getEnv("COMPLIANCE_FEED_BASE_URL", "http://localhost:8080")
+	complianceService, err := services.NewComplianceService(complianceRepo, rabbitConn, settingsClient, []byte(complianceFeedSecret), complianceFeedBaseURL)
+	if err != nil {
+		log.Fatalf("Failed to create compliance service: %v", err)
+	}
+	defer complianceService.Close()
+	registrationService.UseCompliance(complianceService)
+
+	registerService := services.NewRegisterService(registerRepo, registrationRepo)
+
+	feeService := services.NewFeeService(feeRepo, registrationRepo)
+	registrationService.UseFees(feeService)
 
 	// Initialize handlers
 	registrationHandler := handlers.NewRegistrationHandler(registrationService)
 	clientHandler := handlers.NewClientHandler(clientService)
+	queueHandler := handlers.NewQueueHandler(queueService)
+	searchHandler := handlers.NewSearchHandler(searchService)
+	importHandler := handlers.NewImportHandler(importService)
+	nameReservationHandler := handlers.NewNameReservationHandler(nameReservationService)
+	complianceHandler := handlers.NewComplianceHandler(complianceService)
+	registerHandler := handlers.NewRegisterHandler(registerService)
+	feeHandler := handlers.NewFeeHandler(feeService)
 
 	// Initialize and start Odoo sync event consumer
 	odooSyncConsumer, err := events.NewOdooSyncConsumer(rabbitConn, integrationServiceURL)
@@ -126,8 +185,21 @@ func main() {
 	}
 	log.Println("✅ Odoo auto-sync enabled - listening for registration.approved events")
 
+	// Flag registrations that outstay their SLA target
+	slaInterval := time.Duration(getFloatEnv("SLA_CHECK_INTERVAL_MINUTES", 5)) * time.Minute
+	go runSLAMonitor(queueService, slaInterval)
+
+	// Record CIPC's decisions on name reservations and release expired names
+	nameReservationInterval := time.Duration(getFloatEnv("NAME_RESERVATION_CHECK_INTERVAL_MINUTES", 15)) * time.Minute
+	go runNameReservationMonitor(nameReservationService, nameReservationInterval)
+
+	// Roll compliance schedules forward, mark missed filings overdue and remind
+	// clients of filings falling due
+	complianceInterval := time.Duration(getFloatEnv("COMPLIANCE_CHECK_INTERVAL_MINUTES", 60)) * time.Minute
+	go runComplianceMonitor(complianceService, complianceInterval)
+
 	// Setup router
-	r := setupRouter(db, registrationHandler, clientHandler, jwtSecret)
+	r := setupRouter(db, registrationHandler, clientHandler, queueHandler, searchHandler, importHandler, nameReservationHandler, complianceHandler, registerHandler, feeHandler, jwtSecret)
 
 	// PRODUCTION: Configure HTTP server with timeouts for security and reliability
 	addr := fmt.Sprintf(":%s", port)
@@ -170,7 +242,7 @@ func main() {
 	log.Println("Registration Service stopped gracefully")
 }
 
-func setupRouter(db *sql.DB, registrationHandler *handlers.RegistrationHandler, clientHandler *handlers.ClientHandler, jwtSecret string) *gin.Engine {
+func setupRouter(db *sql.DB, registrationHandler *handlers.RegistrationHandler, clientHandler *handlers.ClientHandler, queueHandler *handlers.QueueHandler, searchHandler *handlers.SearchHandler, importHandler *handlers.ImportHandler, nameReservationHandler *handlers.NameReservationHandler, complianceHandler *handlers.ComplianceHandler, registerHandler *handlers.RegisterHandler, feeHandler *handlers.FeeHandler, jwtSecret string) *gin.Engine {
 	// Set Gin mode
 	if os.Getenv("APP_ENV") == "production" {
 		gin.SetMode(gin.ReleaseMode)
@@ -189,6 +261,7 @@ func setupRouter(db *sql.DB, registrationHandler *handlers.RegistrationHandler,
 		AllowedMimeTypes: []string{
 			"application/json",
 			"application/x-www-form-urlencoded",
+			"multipart/form-data", // Registration imports
 		},
 		BlockPatterns: []string{
 			`[<>]`, // No HTML brackets in company names/addresses
@@ -208,6 +281,10 @@ func setupRouter(db *sql.DB, registrationHandler *handlers.RegistrationHandler,
 	// PRODUCTION: Prometheus metrics endpoint for monitoring and alerting
 	r.GET("/metrics", gin.WrapH(promhttp.Handler()))
 
+	// Compliance calendar feeds, signed rather than authenticated, for calendar
+	// apps to subscribe to
+	complianceHandler.SetupPublicRoutes(r.Group("/public/compliance"))
+
 	// API routes
 	api := r.Group("/api/v1")
 	{
@@ -219,23 +296,105 @@ func setupRouter(db *sql.DB, registrationHandler *handlers.RegistrationHandler,
 		// Registration routes
 		registrations := api.Group("/registrations")
 		{
+			registrations.GET("/search", searchHandler.SearchRegistrations)
 			registrationHandler.SetupRoutes(registrations)
+			queueHandler.SetupRegistrationRoutes(registrations)
+			nameReservationHandler.SetupRoutes(registrations)
+			registerHandler.SetupRoutes(registrations)
+			feeHandler.SetupRegistrationRoutes(registrations)
 		}
 
+		// Review queue and SLA target routes
+		queueHandler.SetupRoutes(api.Group("/review-queues"), api.Group("/sla-targets"))
+
+		// Bulk registration import routes
+		importHandler.SetupRoutes(api.Group("/registration-imports"), api.Group("/registration-import-mappings"))
+
 		// Client routes
 		clients := api.Group("/clients")
 		{
 			clients.POST("", clientHandler.CreateClient)
 			clients.GET("", clientHandler.ListClients)
+			clients.GET("/search", searchHandler.SearchClients)
 			clients.GET("/:id", clientHandler.GetClient)
 			clients.PUT("/:id", clientHandler.UpdateClient)
 			clients.DELETE("/:id", clientHandler.DeleteClient)
+
+			// User account link, duplicates and KYC
+			clients.PUT("/:id/user", clientHandler.LinkUser)
+			clients.DELETE("/:id/user", clientHandler.UnlinkUser)
+			clients.GET("/:id/duplicates", clientHandler.FindDuplicates)
+			clients.POST("/:id/merge", sharedmiddleware.RequireRole(models.RoleTenantAdmin, models.RoleTenantManager, models.RoleGlobalAdmin), clientHandler.MergeClient)
+			clients.GET("/:id/kyc", clientHandler.GetKYC)
+			complianceHandler.SetupClientRoutes(clients)
 		}
+
+		// Compliance obligations and calendars
+		complianceHandler.SetupRoutes(api.Group("/compliance"))
+
+		// Fee schedules
+		feeHandler.SetupRoutes(api.Group("/fee-schedules"))
 	}
 
 	return r
 }
 
+// runSLAMonitor periodically flags registrations past their SLA due time
+func runSLAMonitor(queueService *services.QueueService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		breaches, err := queueService.CheckBreaches()
+		if err != nil {
+			log.Printf("SLA breach check failed: %v", err)
+			continue
+		}
+		if breaches > 0 {
+			log.Printf("Flagged %d SLA breach(es)", breaches)
+		}
+	}
+}
+
+// runNameReservationMonitor periodically records CIPC's decisions on submitted
+// name reservations and expires approved names CIPC no longer holds
+func runNameReservationMonitor(nameReservationService *services.NameReservationService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		decided, err := nameReservationService.PollSubmitted()
+		if err != nil {
+			log.Printf("Name reservation check failed: %v", err)
+		} else if decided > 0 {
+			log.Printf("Recorded CIPC decisions on %d name reservation(s)", decided)
+		}
+
+		expired, err := nameReservationService.ExpireReservations()
+		if err != nil {
+			log.Printf("Name reservation expiry failed: %v", err)
+		} else if expired > 0 {
+			log.Printf("Expired %d name reservation(s)", expired)
+		}
+	}
+}
+
+// runComplianceMonitor periodically runs the compliance check. Reminders are
+// sent once each, however often it runs.
+func runComplianceMonitor(complianceService *services.ComplianceService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		sent, err := complianceService.CheckObligations()
+		if err != nil {
+			log.Printf("Compliance check failed: %v", err)
+		} else if sent > 0 {
+			log.Printf("Sent %d compliance reminder(s)", sent)
+		}
+	}
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
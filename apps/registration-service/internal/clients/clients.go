@@ -0,0 +1,104 @@
+// Package clients holds the rules for client profiles: how addresses are
+// checked, when two clients are duplicates, what a merge keeps and how KYC
+// status follows from a client's documents.
+package clients
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/comply360/shared/models"
+)
+
+var (
+	nonAlphanumeric = regexp.MustCompile(`[^a-z0-9]+`)
+	legalSuffixes   = regexp.MustCompile(`( (proprietary|pty|limited|ltd|cc|inc|npc|rf|soc))+$`)
+	saPostalCode    = regexp.MustCompile(`^[0-9]{4}$`)
+)
+
+// CompanyKey reduces a company name to the key duplicates are matched on: lower
+// case, without punctuation or trailing legal suffixes such as "(Pty) Ltd". It
+// must agree with the clients.company_name_key column.
+func CompanyKey(name string) string {
+	key := strings.TrimSpace(nonAlphanumeric.ReplaceAllString(strings.ToLower(name), " "))
+	return legalSuffixes.ReplaceAllString(key, "")
+}
+
+// NormalizeIDNumber drops spaces and separators from an identity number
+func NormalizeIDNumber(number string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(number) {
+		if (r >= '0' && r <= '9') || (r >= 'A' && r <= 'Z') {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// Reasons returns the fields that make two clients possible duplicates
+func Reasons(a, b *models.Client) []string {
+	reasons := []string{}
+	if id := value(a.IDNumber); id != "" && NormalizeIDNumber(id) == NormalizeIDNumber(value(b.IDNumber)) {
+		reasons = append(reasons, models.DuplicateReasonIDNumber)
+	}
+	if a.Email != "" && strings.EqualFold(a.Email, b.Email) {
+		reasons = append(reasons, models.DuplicateReasonEmail)
+	}
+	if key := CompanyKey(value(a.CompanyName)); key != "" && key == CompanyKey(value(b.CompanyName)) {
+		reasons = append(reasons, models.DuplicateReasonCompanyName)
+	}
+	return reasons
+}
+
+// CheckAddress checks that a street address comes with a city, and that South
+// African postal codes have four digits
+func CheckAddress(client *models.Client) error {
+	if value(client.StreetAddress) != "" && value(client.City) == "" {
+		return fmt.Errorf("city is required with a street address")
+	}
+	if postalCode := value(client.PostalCode); postalCode != "" && value(client.Country) == "ZA" && !saPostalCode.MatchString(postalCode) {
+		return fmt.Errorf("postal_code must be 4 digits for South African addresses")
+	}
+	return nil
+}
+
+// Merge fills the target's empty details from the source client. The target's
+// own details, status and type are kept.
+func Merge(target, source *models.Client) {
+	fill := func(dst **string, src *string) {
+		if value(*dst) == "" && value(src) != "" {
+			*dst = src
+		}
+	}
+	fill(&target.FullName, source.FullName)
+	fill(&target.CompanyName, source.CompanyName)
+	fill(&target.IDNumber, source.IDNumber)
+	fill(&target.PassportNumber, source.PassportNumber)
+	fill(&target.TaxNumber, source.TaxNumber)
+	fill(&target.VATNumber, source.VATNumber)
+	fill(&target.Phone, source.Phone)
+	fill(&target.Mobile, source.Mobile)
+
+	// An address is taken whole so parts of two addresses are never mixed
+	if value(target.StreetAddress) == "" && value(target.City) == "" && value(target.PostalCode) == "" {
+		target.StreetAddress = source.StreetAddress
+		target.City = source.City
+		target.StateProvince = source.StateProvince
+		target.PostalCode = source.PostalCode
+		if source.Country != nil {
+			target.Country = source.Country
+		}
+	}
+
+	if target.UserID == nil {
+		target.UserID = source.UserID
+	}
+}
+
+func value(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return strings.TrimSpace(*s)
+}
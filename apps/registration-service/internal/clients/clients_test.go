@@ -0,0 +1,92 @@
+package clients
+
+import (
+	"testing"
+	"time"
+
+	"github.com/comply360/shared/models"
+	testhelpers "github.com/comply360/shared/testing"
+	"github.com/google/uuid"
+)
+
+func str(s string) *string {
+	return &s
+}
+
+func TestCompanyKey(t *testing.T) {
+	testhelpers.AssertEqual(t, "nkosi holdings", CompanyKey("Nkosi Holdings (Pty) Ltd."))
+	testhelpers.AssertEqual(t, "nkosi holdings", CompanyKey("  NKOSI   HOLDINGS  "))
+	testhelpers.AssertEqual(t, "dlamini sons", CompanyKey("Dlamini & Sons Proprietary Limited"))
+	testhelpers.AssertEqual(t, "cc motors", CompanyKey("CC Motors CC"), "Only trailing suffixes are dropped")
+	testhelpers.AssertEqual(t, "", CompanyKey(""))
+}
+
+func TestReasons(t *testing.T) {
+	a := &models.Client{Email: "Thandi@Example.com", IDNumber: str("800101 5009 087"), CompanyName: str("Nkosi Holdings (Pty) Ltd")}
+	b := &models.Client{Email: "thandi@example.com", IDNumber: str("8001015009087"), CompanyName: str("Nkosi Holdings")}
+
+	reasons := Reasons(a, b)
+	testhelpers.AssertEqual(t, 3, len(reasons))
+	testhelpers.AssertEqual(t, models.DuplicateReasonIDNumber, reasons[0])
+	testhelpers.AssertEqual(t, models.DuplicateReasonEmail, reasons[1])
+	testhelpers.AssertEqual(t, models.DuplicateReasonCompanyName, reasons[2])
+
+	c := &models.Client{Email: "sipho@example.com"}
+	testhelpers.AssertEqual(t, 0, len(Reasons(a, c)), "Missing details never match")
+}
+
+func TestCheckAddress(t *testing.T) {
+	testhelpers.AssertNoError(t, CheckAddress(&models.Client{}))
+	testhelpers.AssertError(t, CheckAddress(&models.Client{StreetAddress: str("1 Main Road")}))
+	testhelpers.AssertError(t, CheckAddress(&models.Client{City: str("Durban"), PostalCode: str("40001"), Country: str("ZA")}))
+	testhelpers.AssertNoError(t, CheckAddress(&models.Client{City: str("Harare"), PostalCode: str("00263"), Country: str("ZW")}))
+}
+
+func TestMerge(t *testing.T) {
+	userID := uuid.New()
+	target := &models.Client{Email: "thandi@example.com", FullName: str("Thandi Nkosi"), City: str("Durban")}
+	source := &models.Client{
+		Email:         "t.nkosi@example.com",
+		FullName:      str("T Nkosi"),
+		IDNumber:      str("8001015009087"),
+		StreetAddress: str("1 Main Road"),
+		City:          str("Pretoria"),
+		UserID:        &userID,
+	}
+
+	Merge(target, source)
+	testhelpers.AssertEqual(t, "thandi@example.com", target.Email)
+	testhelpers.AssertEqual(t, "Thandi Nkosi", *target.FullName, "The target's details are kept")
+	testhelpers.AssertEqual(t, "8001015009087", *target.IDNumber, "Missing details are filled")
+	testhelpers.AssertTrue(t, target.StreetAddress == nil, "Addresses are not mixed")
+	testhelpers.AssertEqual(t, userID, *target.UserID)
+}
+
+func TestAssessKYC(t *testing.T) {
+	kyc := AssessKYC(models.ClientTypeIndividual, nil)
+	testhelpers.AssertEqual(t, models.KYCStatusNotStarted, kyc.Status)
+	testhelpers.AssertEqual(t, 2, len(kyc.Requirements))
+	testhelpers.AssertEqual(t, models.KYCRequirementMissing, kyc.Requirements[0].Status)
+
+	now := time.Now()
+	earlier := now.Add(-time.Hour)
+	id := &models.Document{ID: uuid.New(), DocumentType: models.DocumentTypeID, Status: models.DocumentStatusRejected, CreatedAt: earlier}
+	kyc = AssessKYC(models.ClientTypeIndividual, []*models.Document{id})
+	testhelpers.AssertEqual(t, models.KYCStatusRejected, kyc.Status)
+
+	retry := &models.Document{ID: uuid.New(), DocumentType: models.DocumentTypeID, Status: models.DocumentStatusVerified, VerifiedAt: &earlier, CreatedAt: now}
+	address := &models.Document{ID: uuid.New(), DocumentType: models.DocumentTypeProofOfAddress, Status: models.DocumentStatusPending, CreatedAt: now}
+	kyc = AssessKYC(models.ClientTypeIndividual, []*models.Document{id, retry, address})
+	testhelpers.AssertEqual(t, models.KYCStatusPending, kyc.Status, "A verified document replaces a rejected one")
+	testhelpers.AssertEqual(t, retry.ID, *kyc.Requirements[0].DocumentID)
+
+	address.Status = models.DocumentStatusVerified
+	address.VerifiedAt = &now
+	kyc = AssessKYC(models.ClientTypeIndividual, []*models.Document{id, retry, address})
+	testhelpers.AssertEqual(t, models.KYCStatusVerified, kyc.Status)
+	testhelpers.AssertEqual(t, now, *kyc.VerifiedAt, "Verified when the last requirement was")
+
+	kyc = AssessKYC(models.ClientTypeCompany, []*models.Document{id, retry, address})
+	testhelpers.AssertEqual(t, models.KYCStatusPending, kyc.Status, "Companies need company documents")
+	testhelpers.AssertEqual(t, models.DocumentTypeCompanyDocs, kyc.Requirements[0].DocumentType)
+}
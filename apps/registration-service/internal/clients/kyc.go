@@ -0,0 +1,96 @@
+package clients
+
+import (
+	"time"
+
+	"github.com/comply360/shared/models"
+)
+
+// RequiredDocuments returns the document types KYC requires of a client type
+func RequiredDocuments(clientType string) []string {
+	if clientType == models.ClientTypeCompany {
+		return []string{models.DocumentTypeCompanyDocs, models.DocumentTypeProofOfAddress}
+	}
+	return []string{models.DocumentTypeID, models.DocumentTypeProofOfAddress}
+}
+
+// documentRank orders document statuses from most to least useful for KYC
+var documentRank = map[string]int{
+	models.DocumentStatusVerified: 0,
+	models.DocumentStatusPending:  1,
+	models.DocumentStatusRejected: 2,
+	models.DocumentStatusExpired:  3,
+}
+
+// AssessKYC aggregates a client's documents into its KYC profile. Each required
+// type is met by its best document: a verified one, else one awaiting review,
+// else the newest rejected or expired one. The client is verified once every
+// requirement is; rejected if a requirement's best document was rejected;
+// expired if one's has expired; and pending while documents are outstanding.
+func AssessKYC(clientType string, documents []*models.Document) *models.ClientKYC {
+	kyc := &models.ClientKYC{Status: models.KYCStatusVerified}
+
+	uploaded := false
+	rejected, expired := false, false
+	var verifiedAt *time.Time
+	for _, documentType := range RequiredDocuments(clientType) {
+		requirement := models.ClientKYCRequirement{DocumentType: documentType, Status: models.KYCRequirementMissing}
+
+		var best *models.Document
+		for _, document := range documents {
+			if document.DocumentType != documentType || !document.IsActive() {
+				continue
+			}
+			if _, ok := documentRank[document.Status]; !ok {
+				continue
+			}
+			if best == nil || better(document, best) {
+				best = document
+			}
+		}
+
+		if best != nil {
+			uploaded = true
+			id := best.ID
+			requirement.Status = best.Status
+			requirement.DocumentID = &id
+			requirement.VerifiedAt = best.VerifiedAt
+		}
+
+		switch requirement.Status {
+		case models.DocumentStatusVerified:
+			if verifiedAt == nil || (requirement.VerifiedAt != nil && requirement.VerifiedAt.After(*verifiedAt)) {
+				verifiedAt = requirement.VerifiedAt
+			}
+		case models.DocumentStatusRejected:
+			rejected = true
+		case models.DocumentStatusExpired:
+			expired = true
+		}
+		if requirement.Status != models.DocumentStatusVerified {
+			kyc.Status = models.KYCStatusPending
+		}
+		kyc.Requirements = append(kyc.Requirements, requirement)
+	}
+
+	switch {
+	case kyc.Status == models.KYCStatusVerified:
+		kyc.VerifiedAt = verifiedAt
+	case !uploaded:
+		kyc.Status = models.KYCStatusNotStarted
+	case rejected:
+		kyc.Status = models.KYCStatusRejected
+	case expired:
+		kyc.Status = models.KYCStatusExpired
+	}
+
+	return kyc
+}
+
+// better reports whether a document meets a requirement better than another
+func better(a, b *models.Document) bool {
+	if documentRank[a.Status] != documentRank[b.Status] {
+		return documentRank[a.Status] < documentRank[b.Status]
+	}
+	return a.CreatedAt.After(b.CreatedAt)
+}
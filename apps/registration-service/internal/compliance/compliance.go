@@ -0,0 +1,316 @@
+// Package compliance works out the recurring filings a registered company owes
+// from its registration type, registration date and jurisdiction, and when to
+// remind its client of them.
+package compliance
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/comply360/shared/models"
+)
+
+// HorizonMonths is how far ahead obligations are scheduled
+const HorizonMonths = 12
+
+// Company is a registered company obligations are worked out for
+type Company struct {
+	RegistrationType string
+	Jurisdiction     string
+	// RegisteredOn is the date the registry registered the company
+	RegisteredOn time.Time
+	// VATCategory is the VAT vendor's tax period category, A to F
+	VATCategory string
+}
+
+// CompanyOf returns the company a completed registration registered. The
+// registration date is the date the registration was approved.
+func CompanyOf(registration *models.Registration) Company {
+	registered := registration.CreatedAt
+	if registration.ApprovedAt != nil {
+		registered = *registration.ApprovedAt
+	}
+
+	category, _ := registration.FormData["vat_category"].(string)
+	return Company{
+		RegistrationType: registration.RegistrationType,
+		Jurisdiction:     registration.Jurisdiction,
+		RegisteredOn:     Date(registered),
+		VATCategory:      category,
+	}
+}
+
+// Date returns the calendar date of a time, at midnight UTC
+func Date(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// occurrence is one period of an obligation and when its filing is due
+type occurrence struct {
+	start, end, due time.Time
+	label           string
+}
+
+// rule schedules one type of obligation
+type rule struct {
+	obligationType string
+	authority      string
+	title          string
+	occurrences    func(company Company, from, to time.Time) []occurrence
+}
+
+// rules are the obligations of each jurisdiction and registration type.
+// Business names and types not listed owe nothing recurring.
+var rules = map[string]map[string][]rule{
+	models.JurisdictionSouthAfrica: {
+		models.RegistrationTypePtyLtd:           {zaAnnualReturn, zaBeneficialOwnership, zaTaxClearance},
+		models.RegistrationTypeCloseCorporation: {zaAnnualReturn, zaBeneficialOwnership, zaTaxClearance},
+		models.RegistrationTypeVATRegistration:  {zaVATReturn},
+	},
+	models.JurisdictionZimbabwe: {
+		models.RegistrationTypePtyLtd:          {zwAnnualReturn, zwBeneficialOwnership, zwTaxClearance},
+		models.RegistrationTypeVATRegistration: {zwVATReturn},
+	},
+}
+
+var (
+	// zaAnnualReturn is due within 30 business days of each anniversary of
+	// incorporation
+	zaAnnualReturn = rule{
+		obligationType: models.ObligationAnnualReturn,
+		authority:      "CIPC",
+		title:          "CIPC annual return %s",
+		occurrences: annually(func(anniversary time.Time) time.Time {
+			return AddBusinessDays(models.JurisdictionSouthAfrica, anniversary, 30)
+		}),
+	}
+	// zaBeneficialOwnership is filed with the annual return
+	zaBeneficialOwnership = rule{
+		obligationType: models.ObligationBeneficialOwnership,
+		authority:      "CIPC",
+		title:          "Beneficial ownership declaration %s",
+		occurrences:    zaAnnualReturn.occurrences,
+	}
+	// zaTaxClearance renews the SARS tax compliance status, which lasts a year
+	zaTaxClearance = rule{
+		obligationType: models.ObligationTaxClearance,
+		authority:      "SARS",
+		title:          "Tax compliance status renewal %s",
+		occurrences:    annually(func(anniversary time.Time) time.Time { return anniversary }),
+	}
+	zaVATReturn = rule{
+		obligationType: models.ObligationVATReturn,
+		authority:      "SARS",
+		title:          "VAT return %s",
+		occurrences:    vatPeriods(models.JurisdictionSouthAfrica),
+	}
+
+	// zwAnnualReturn is due within a month of each anniversary of incorporation
+	zwAnnualReturn = rule{
+		obligationType: models.ObligationAnnualReturn,
+		authority:      "Registrar of Companies",
+		title:          "Annual return %s",
+		occurrences: annually(func(anniversary time.Time) time.Time {
+			return addMonths(anniversary, 1)
+		}),
+	}
+	zwBeneficialOwnership = rule{
+		obligationType: models.ObligationBeneficialOwnership,
+		authority:      "Registrar of Companies",
+		title:          "Beneficial ownership register %s",
+		occurrences:    zwAnnualReturn.occurrences,
+	}
+	// zwTaxClearance renews the ZIMRA tax clearance (ITF263), which lasts to
+	// the end of the calendar year, by the end of the year before
+	zwTaxClearance = rule{
+		obligationType: models.ObligationTaxClearance,
+		authority:      "ZIMRA",
+		title:          "Tax clearance (ITF263) %s",
+		occurrences:    calendarYears,
+	}
+	zwVATReturn = rule{
+		obligationType: models.ObligationVATReturn,
+		authority:      "ZIMRA",
+		title:          "VAT return %s",
+		occurrences:    vatPeriods(models.JurisdictionZimbabwe),
+	}
+)
+
+// Obligations returns the obligations a company owes that fall due from one
+// date to another, inclusive, for no period before it was registered
+func Obligations(company Company, from, to time.Time) []*models.ComplianceObligation {
+	from, to = Date(from), Date(to)
+
+	var obligations []*models.ComplianceObligation
+	for _, rule := range rules[company.Jurisdiction][company.RegistrationType] {
+		for _, o := range rule.occurrences(company, from, to) {
+			if o.due.Before(from) || o.due.After(to) || o.end.Before(company.RegisteredOn) {
+				continue
+			}
+			obligations = append(obligations, &models.ComplianceObligation{
+				ObligationType: rule.obligationType,
+				Jurisdiction:   company.Jurisdiction,
+				Authority:      rule.authority,
+				Title:          fmt.Sprintf(rule.title, o.label),
+				PeriodStart:    o.start,
+				PeriodEnd:      o.end,
+				DueDate:        o.due,
+				Status:         models.ObligationStatusUpcoming,
+			})
+		}
+	}
+
+	sort.SliceStable(obligations, func(i, j int) bool {
+		return obligations[i].DueDate.Before(obligations[j].DueDate)
+	})
+	return obligations
+}
+
+// annually schedules an obligation for each year since incorporation, due a
+// time after each anniversary. The period is the year the anniversary ends.
+func annually(due func(anniversary time.Time) time.Time) func(Company, time.Time, time.Time) []occurrence {
+	return func(company Company, from, to time.Time) []occurrence {
+		var found []occurrence
+		for years := 1; ; years++ {
+			anniversary := addYears(company.RegisteredOn, years)
+			if anniversary.After(to) {
+				break
+			}
+			found = append(found, occurrence{
+				start: addYears(company.RegisteredOn, years-1),
+				end:   anniversary.AddDate(0, 0, -1),
+				due:   due(anniversary),
+				label: fmt.Sprint(anniversary.Year()),
+			})
+		}
+		return found
+	}
+}
+
+// calendarYears schedules an obligation for each calendar year, due on the
+// last day of the year before
+func calendarYears(company Company, from, to time.Time) []occurrence {
+	var found []occurrence
+	for year := company.RegisteredOn.Year(); year <= to.Year()+1; year++ {
+		start := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+		found = append(found, occurrence{
+			start: start,
+			end:   time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC),
+			due:   start.AddDate(0, 0, -1),
+			label: fmt.Sprint(year),
+		})
+	}
+	return found
+}
+
+// vatCategories are the VAT tax periods: how many months each lasts, and a
+// month one of them ends in
+var vatCategories = map[string]struct {
+	months int
+	endsIn time.Month
+}{
+	"A": {2, time.January},
+	"B": {2, time.February},
+	"C": {1, time.January},
+	"D": {6, time.February},
+	"E": {12, time.February},
+	"F": {4, time.February},
+}
+
+// vatPeriods schedules a VAT return for each tax period of the vendor's
+// category, due on the 25th of the month after the period ends, or the last
+// business day before when the 25th is not one. A vendor without a known
+// category files every two months (category A).
+func vatPeriods(jurisdiction string) func(Company, time.Time, time.Time) []occurrence {
+	return func(company Company, from, to time.Time) []occurrence {
+		category, ok := vatCategories[company.VATCategory]
+		if !ok {
+			category = vatCategories["A"]
+		}
+
+		// The first period ends in the first month on or after registration
+		// that ends a period
+		first := time.Date(company.RegisteredOn.Year(), company.RegisteredOn.Month(), 1, 0, 0, 0, 0, time.UTC)
+		for (int(first.Month())-int(category.endsIn)+12)%category.months != 0 {
+			first = first.AddDate(0, 1, 0)
+		}
+
+		var found []occurrence
+		for periodMonth := first; ; periodMonth = periodMonth.AddDate(0, category.months, 0) {
+			end := periodMonth.AddDate(0, 1, -1)
+			due := time.Date(end.Year(), end.Month()+1, 25, 0, 0, 0, 0, time.UTC)
+			for !IsBusinessDay(jurisdiction, due) {
+				due = due.AddDate(0, 0, -1)
+			}
+			if due.After(to) {
+				break
+			}
+
+			start := periodMonth.AddDate(0, 1-category.months, 0)
+			found = append(found, occurrence{start: start, end: end, due: due, label: periodLabel(start, end)})
+		}
+		return found
+	}
+}
+
+// periodLabel names a tax period, such as "Mar 2027" or "Jan-Feb 2027"
+func periodLabel(start, end time.Time) string {
+	switch {
+	case start.Year() == end.Year() && start.Month() == end.Month():
+		return end.Format("Jan 2006")
+	case start.Year() == end.Year():
+		return fmt.Sprintf("%s-%s", start.Format("Jan"), end.Format("Jan 2006"))
+	default:
+		return fmt.Sprintf("%s-%s", start.Format("Jan 2006"), end.Format("Jan 2006"))
+	}
+}
+
+// addYears moves a date on by whole years. An anniversary of 29 February
+// falls on 28 February in other years.
+func addYears(date time.Time, years int) time.Time {
+	return addMonths(date, 12*years)
+}
+
+// addMonths moves a date on by whole months, keeping to the last day of
+// shorter months
+func addMonths(date time.Time, months int) time.Time {
+	first := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, months, 0)
+	last := first.AddDate(0, 1, -1).Day()
+	return time.Date(first.Year(), first.Month(), min(date.Day(), last), 0, 0, 0, 0, time.UTC)
+}
+
+// NextReminder returns which reminder, in days before the due date, is due for
+// an obligation today, given the reminders already sent. It is the latest
+// reminder whose day has come, if it is sooner to the due date than the last
+// one sent, so a reminder is never repeated and stale ones are skipped.
+func NextReminder(reminderDays []int, due, today time.Time, lastSent *int) (int, bool) {
+	remaining := DaysUntil(due, today)
+	if remaining < 0 {
+		return 0, false
+	}
+
+	next, found := 0, false
+	for _, days := range reminderDays {
+		if days < remaining || (lastSent != nil && days >= *lastSent) {
+			continue
+		}
+		if !found || days < next {
+			next, found = days, true
+		}
+	}
+	return next, found
+}
+
+// DaysUntil returns how many days from today a date is, negative once past
+func DaysUntil(date, today time.Time) int {
+	return int(Date(date).Sub(Date(today)).Hours() / 24)
+}
+
+// StatusOn returns the status an open obligation has on a day
+func StatusOn(due, today time.Time) string {
+	if Date(due).Before(Date(today)) {
+		return models.ObligationStatusOverdue
+	}
+	return models.ObligationStatusUpcoming
+}
@@ -0,0 +1,245 @@
+package compliance
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/comply360/shared/models"
+	testhelpers "github.com/comply360/shared/testing"
+	"github.com/google/uuid"
+)
+
+func date(value string) time.Time {
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestObligations_CompanyInSouthAfrica(t *testing.T) {
+	company := Company{
+		RegistrationType: models.RegistrationTypePtyLtd,
+		Jurisdiction:     models.JurisdictionSouthAfrica,
+		RegisteredOn:     date("2026-03-10"),
+	}
+
+	obligations := Obligations(company, company.RegisteredOn, date("2027-06-30"))
+	testhelpers.AssertEqual(t, 3, len(obligations))
+
+	taxClearance := obligations[0]
+	testhelpers.AssertEqual(t, models.ObligationTaxClearance, taxClearance.ObligationType)
+	testhelpers.AssertEqual(t, date("2027-03-10"), taxClearance.DueDate)
+
+	annualReturn := obligations[1]
+	testhelpers.AssertEqual(t, models.ObligationAnnualReturn, annualReturn.ObligationType)
+	testhelpers.AssertEqual(t, "CIPC", annualReturn.Authority)
+	testhelpers.AssertEqual(t, "CIPC annual return 2027", annualReturn.Title)
+	testhelpers.AssertEqual(t, date("2026-03-10"), annualReturn.PeriodStart)
+	testhelpers.AssertEqual(t, date("2027-03-09"), annualReturn.PeriodEnd)
+	// 30 business days after the anniversary, skipping Human Rights Day
+	// observed on Monday 22 March, Good Friday and Family Day
+	testhelpers.AssertEqual(t, date("2027-04-26"), annualReturn.DueDate)
+	testhelpers.AssertEqual(t, models.ObligationStatusUpcoming, annualReturn.Status)
+
+	testhelpers.AssertEqual(t, models.ObligationBeneficialOwnership, obligations[2].ObligationType)
+	testhelpers.AssertEqual(t, annualReturn.DueDate, obligations[2].DueDate, "Filed with the annual return")
+}
+
+func TestObligations_OnlyWithinRange(t *testing.T) {
+	company := Company{
+		RegistrationType: models.RegistrationTypeCloseCorporation,
+		Jurisdiction:     models.JurisdictionSouthAfrica,
+		RegisteredOn:     date("2020-06-01"),
+	}
+
+	obligations := Obligations(company, date("2026-10-18"), date("2027-10-18"))
+	testhelpers.AssertEqual(t, 3, len(obligations))
+	for _, o := range obligations {
+		testhelpers.AssertEqual(t, 2027, o.PeriodEnd.Year())
+	}
+
+	testhelpers.AssertEqual(t, 0, len(Obligations(Company{
+		RegistrationType: models.RegistrationTypeBusinessName,
+		Jurisdiction:     models.JurisdictionSouthAfrica,
+		RegisteredOn:     date("2026-01-05"),
+	}, date("2026-01-05"), date("2027-01-05"))), "Business names owe no recurring filings")
+}
+
+func TestObligations_VATPeriods(t *testing.T) {
+	company := Company{
+		RegistrationType: models.RegistrationTypeVATRegistration,
+		Jurisdiction:     models.JurisdictionSouthAfrica,
+		RegisteredOn:     date("2026-03-10"),
+		VATCategory:      "B",
+	}
+
+	obligations := Obligations(company, company.RegisteredOn, date("2026-09-30"))
+	testhelpers.AssertEqual(t, 3, len(obligations))
+	testhelpers.AssertEqual(t, "VAT return Mar-Apr 2026", obligations[0].Title)
+	testhelpers.AssertEqual(t, date("2026-03-01"), obligations[0].PeriodStart)
+	testhelpers.AssertEqual(t, date("2026-04-30"), obligations[0].PeriodEnd)
+	testhelpers.AssertEqual(t, date("2026-05-25"), obligations[0].DueDate)
+	testhelpers.AssertEqual(t, date("2026-07-24"), obligations[1].DueDate, "The 25th is a Saturday")
+
+	company.VATCategory = "C"
+	obligations = Obligations(company, company.RegisteredOn, date("2026-12-31"))
+	testhelpers.AssertEqual(t, "VAT return Mar 2026", obligations[0].Title)
+	testhelpers.AssertEqual(t, date("2026-04-24"), obligations[0].DueDate)
+	testhelpers.AssertEqual(t, date("2026-12-24"), obligations[len(obligations)-1].DueDate, "Christmas Day is a holiday")
+
+	company.VATCategory = ""
+	obligations = Obligations(company, company.RegisteredOn, date("2026-06-30"))
+	testhelpers.AssertEqual(t, "VAT return Feb-Mar 2026", obligations[0].Title, "Category A by default")
+}
+
+func TestObligations_CompanyInZimbabwe(t *testing.T) {
+	company := Company{
+		RegistrationType: models.RegistrationTypePtyLtd,
+		Jurisdiction:     models.JurisdictionZimbabwe,
+		RegisteredOn:     date("2024-02-29"),
+	}
+
+	obligations := Obligations(company, company.RegisteredOn, date("2025-06-30"))
+	testhelpers.AssertEqual(t, 3, len(obligations))
+
+	testhelpers.AssertEqual(t, models.ObligationTaxClearance, obligations[0].ObligationType)
+	testhelpers.AssertEqual(t, "Tax clearance (ITF263) 2025", obligations[0].Title)
+	testhelpers.AssertEqual(t, date("2024-12-31"), obligations[0].DueDate)
+
+	annualReturn := obligations[1]
+	testhelpers.AssertEqual(t, "Registrar of Companies", annualReturn.Authority)
+	testhelpers.AssertEqual(t, date("2025-02-27"), annualReturn.PeriodEnd, "The anniversary of 29 February is 28 February")
+	testhelpers.AssertEqual(t, date("2025-03-28"), annualReturn.DueDate)
+}
+
+func TestCompanyOf_UsesApprovalDate(t *testing.T) {
+	approved := time.Date(2026, 5, 4, 22, 30, 0, 0, time.UTC)
+	company := CompanyOf(&models.Registration{
+		RegistrationType: models.RegistrationTypeVATRegistration,
+		Jurisdiction:     models.JurisdictionSouthAfrica,
+		ApprovedAt:       &approved,
+		CreatedAt:        date("2026-04-01"),
+		FormData:         map[string]interface{}{"vat_category": "C"},
+	})
+
+	testhelpers.AssertEqual(t, date("2026-05-04"), company.RegisteredOn)
+	testhelpers.AssertEqual(t, "C", company.VATCategory)
+}
+
+func TestBusinessDays(t *testing.T) {
+	testhelpers.AssertFalse(t, IsBusinessDay(models.JurisdictionSouthAfrica, date("2026-12-16")), "Day of Reconciliation")
+	testhelpers.AssertFalse(t, IsBusinessDay(models.JurisdictionSouthAfrica, date("2026-04-03")), "Good Friday")
+	testhelpers.AssertTrue(t, IsBusinessDay(models.JurisdictionZimbabwe, date("2026-12-16")))
+	testhelpers.AssertFalse(t, IsBusinessDay(models.JurisdictionZimbabwe, date("2026-08-10")), "Heroes' Day")
+	testhelpers.AssertFalse(t, IsBusinessDay(models.JurisdictionZimbabwe, date("2026-08-11")), "Defence Forces Day")
+	testhelpers.AssertFalse(t, IsBusinessDay(models.JurisdictionSouthAfrica, date("2027-03-22")), "Human Rights Day falls on a Sunday")
+
+	testhelpers.AssertEqual(t, date("2026-12-28"), AddBusinessDays(models.JurisdictionSouthAfrica, date("2026-12-23"), 2))
+	testhelpers.AssertEqual(t, date("2027-12-28"), AddBusinessDays(models.JurisdictionSouthAfrica, date("2027-12-24"), 1), "Day of Goodwill falls on a Sunday")
+	testhelpers.AssertEqual(t, date("2026-04-05"), easterSunday(2026))
+}
+
+func TestNextReminder(t *testing.T) {
+	days := []int{30, 7, 1}
+	due := date("2026-11-30")
+	sent := func(days int) *int { return &days }
+
+	_, ok := NextReminder(days, due, date("2026-10-01"), nil)
+	testhelpers.AssertFalse(t, ok, "Too early")
+
+	next, ok := NextReminder(days, due, date("2026-10-31"), nil)
+	testhelpers.AssertTrue(t, ok)
+	testhelpers.AssertEqual(t, 30, next)
+
+	_, ok = NextReminder(days, due, date("2026-11-10"), sent(30))
+	testhelpers.AssertFalse(t, ok, "Already reminded")
+
+	next, ok = NextReminder(days, due, date("2026-11-27"), nil)
+	testhelpers.AssertTrue(t, ok)
+	testhelpers.AssertEqual(t, 7, next, "Stale reminders are skipped")
+
+	next, ok = NextReminder(days, due, date("2026-11-30"), sent(7))
+	testhelpers.AssertTrue(t, ok)
+	testhelpers.AssertEqual(t, 1, next)
+
+	_, ok = NextReminder(days, due, date("2026-12-01"), sent(7))
+	testhelpers.AssertFalse(t, ok, "Overdue obligations are not reminded")
+
+	testhelpers.AssertEqual(t, models.ObligationStatusUpcoming, StatusOn(due, date("2026-11-30")))
+	testhelpers.AssertEqual(t, models.ObligationStatusOverdue, StatusOn(due, date("2026-12-01")))
+}
+
+func TestICal(t *testing.T) {
+	reference := "AR-2027-001"
+	obligations := []*models.ComplianceObligation{
+		{
+			ID:              uuid.New(),
+			CompanyName:     "Nkosi Holdings, Durban; KZN",
+			ObligationType:  models.ObligationAnnualReturn,
+			Authority:       "CIPC",
+			Title:           "CIPC annual return 2027",
+			PeriodStart:     date("2026-03-10"),
+			PeriodEnd:       date("2027-03-09"),
+			DueDate:         date("2027-04-26"),
+			Status:          models.ObligationStatusCompleted,
+			FilingReference: &reference,
+		},
+		{
+			ID:             uuid.New(),
+			CompanyName:    strings.Repeat("Ubuntu Engineering ", 5),
+			ObligationType: models.ObligationVATReturn,
+			Title:          "VAT return Mar 2026",
+			DueDate:        date("2026-04-24"),
+			Status:         models.ObligationStatusUpcoming,
+		},
+	}
+
+	feed := ICal("Compliance calendar", obligations)
+	testhelpers.AssertTrue(t, strings.HasPrefix(feed, "BEGIN:VCALENDAR\r\n"))
+	testhelpers.AssertTrue(t, strings.HasSuffix(feed, "END:VCALENDAR\r\n"))
+	testhelpers.AssertTrue(t, strings.Contains(feed, "UID:"+obligations[0].ID.String()+"@comply360\r\n"))
+	testhelpers.AssertTrue(t, strings.Contains(feed, "DTSTART;VALUE=DATE:20270426\r\nDTEND;VALUE=DATE:20270427\r\n"))
+	testhelpers.AssertTrue(t, strings.Contains(feed, `SUMMARY:Filed: CIPC annual return 2027 - Nkosi Holdings\, Durban\; KZN`))
+	testhelpers.AssertEqual(t, 1, strings.Count(feed, "BEGIN:VALARM"), "Only open obligations have alarms")
+
+	for _, line := range strings.Split(feed, "\r\n") {
+		testhelpers.AssertTrue(t, len(line) <= 75, "Lines are folded at 75 octets")
+	}
+	unfolded := strings.ReplaceAll(feed, "\r\n ", "")
+	testhelpers.AssertTrue(t, strings.Contains(unfolded, "SUMMARY:VAT return Mar 2026 - "+strings.Repeat("Ubuntu Engineering ", 5)))
+}
+
+func TestFeedToken(t *testing.T) {
+	secret := []byte("feed-secret")
+	tenantID, clientID := uuid.New(), uuid.New()
+
+	parsedTenant, parsedClient, version, err := ParseFeedToken(secret, FeedToken(secret, tenantID, nil, 1))
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, tenantID, parsedTenant)
+	testhelpers.AssertNil(t, parsedClient)
+	testhelpers.AssertEqual(t, 1, version)
+
+	token := FeedToken(secret, tenantID, &clientID, 3)
+	parsedTenant, parsedClient, version, err = ParseFeedToken(secret, token)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, tenantID, parsedTenant)
+	testhelpers.AssertEqual(t, clientID, *parsedClient)
+	testhelpers.AssertEqual(t, 3, version)
+
+	_, _, _, err = ParseFeedToken([]byte("other-secret"), token)
+	testhelpers.AssertError(t, err, "Signed with another secret")
+
+	payload, signature, _ := strings.Cut(token, ".")
+	tampered := FeedToken(secret, uuid.New(), nil, 3)
+	otherPayload, _, _ := strings.Cut(tampered, ".")
+	_, _, _, err = ParseFeedToken(secret, otherPayload+"."+signature)
+	testhelpers.AssertError(t, err, "Payload swapped")
+	_, _, _, err = ParseFeedToken(secret, payload)
+	testhelpers.AssertError(t, err, "No signature")
+
+	rotated, _, _ := strings.Cut(FeedToken(secret, tenantID, &clientID, 4), ".")
+	_, _, _, err = ParseFeedToken(secret, rotated+"."+signature)
+	testhelpers.AssertError(t, err, "Version swapped")
+}
@@ -0,0 +1,59 @@
+package compliance
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// FeedToken signs a calendar feed of a tenant's obligations, or of one
+// client's, so calendar apps can fetch it without signing in. The token names
+// the tenant, the client and the tenant's feed version, and cannot be altered
+// without the secret. Rotating the tenant's feed version revokes the token.
+func FeedToken(secret []byte, tenantID uuid.UUID, clientID *uuid.UUID, version int) string {
+	payload := binary.BigEndian.AppendUint32(nil, uint32(version))
+	payload = append(payload, tenantID[:]...)
+	if clientID != nil {
+		payload = append(payload, clientID[:]...)
+	}
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(feedSignature(secret, payload))
+}
+
+// ParseFeedToken checks a feed token's signature and returns the tenant and
+// client it names and the feed version it was issued under
+func ParseFeedToken(secret []byte, token string) (uuid.UUID, *uuid.UUID, int, error) {
+	invalid := fmt.Errorf("invalid calendar feed token")
+
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return uuid.Nil, nil, 0, invalid
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil || (len(payload) != 20 && len(payload) != 36) {
+		return uuid.Nil, nil, 0, invalid
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, feedSignature(secret, payload)) {
+		return uuid.Nil, nil, 0, invalid
+	}
+
+	version := int(binary.BigEndian.Uint32(payload[:4]))
+	tenantID, _ := uuid.FromBytes(payload[4:20])
+	if len(payload) == 20 {
+		return tenantID, nil, version, nil
+	}
+	clientID, _ := uuid.FromBytes(payload[20:])
+	return tenantID, &clientID, version, nil
+}
+
+func feedSignature(secret, payload []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte("compliance-feed:"))
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,112 @@
+package compliance
+
+import (
+	"time"
+
+	"github.com/comply360/shared/models"
+)
+
+// IsBusinessDay reports whether a date is a weekday that is not a public
+// holiday in a jurisdiction
+func IsBusinessDay(jurisdiction string, date time.Time) bool {
+	date = Date(date)
+	if date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
+		return false
+	}
+	return !publicHolidays(jurisdiction, date.Year())[date]
+}
+
+// AddBusinessDays returns the date a number of business days after another
+func AddBusinessDays(jurisdiction string, date time.Time, days int) time.Time {
+	date = Date(date)
+	for days > 0 {
+		date = date.AddDate(0, 0, 1)
+		if IsBusinessDay(jurisdiction, date) {
+			days--
+		}
+	}
+	return date
+}
+
+// publicHolidays returns a jurisdiction's public holidays in a year. In both
+// South Africa and Zimbabwe a holiday falling on a Sunday is also observed on
+// the Monday after.
+func publicHolidays(jurisdiction string, year int) map[time.Time]bool {
+	day := func(month time.Month, d int) time.Time {
+		return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+	}
+	easter := easterSunday(year)
+
+	var holidays []time.Time
+	switch jurisdiction {
+	case models.JurisdictionSouthAfrica:
+		holidays = []time.Time{
+			day(time.January, 1),     // New Year's Day
+			day(time.March, 21),      // Human Rights Day
+			easter.AddDate(0, 0, -2), // Good Friday
+			easter.AddDate(0, 0, 1),  // Family Day
+			day(time.April, 27),      // Freedom Day
+			day(time.May, 1),         // Workers' Day
+			day(time.June, 16),       // Youth Day
+			day(time.August, 9),      // National Women's Day
+			day(time.September, 24),  // Heritage Day
+			day(time.December, 16),   // Day of Reconciliation
+			day(time.December, 25),   // Christmas Day
+			day(time.December, 26),   // Day of Goodwill
+		}
+	case models.JurisdictionZimbabwe:
+		heroes := day(time.August, 1)
+		for heroes.Weekday() != time.Monday {
+			heroes = heroes.AddDate(0, 0, 1)
+		}
+		heroes = heroes.AddDate(0, 0, 7)
+
+		holidays = []time.Time{
+			day(time.January, 1),     // New Year's Day
+			day(time.February, 21),   // National Youth Day
+			easter.AddDate(0, 0, -2), // Good Friday
+			easter.AddDate(0, 0, -1), // Holy Saturday
+			easter.AddDate(0, 0, 1),  // Easter Monday
+			day(time.April, 18),      // Independence Day
+			day(time.May, 1),         // Workers' Day
+			day(time.May, 25),        // Africa Day
+			heroes,                   // Heroes' Day, the second Monday of August
+			heroes.AddDate(0, 0, 1),  // Defence Forces Day
+			day(time.December, 22),   // Unity Day
+			day(time.December, 25),   // Christmas Day
+			day(time.December, 26),   // Boxing Day
+		}
+	}
+
+	observed := map[time.Time]bool{}
+	for _, holiday := range holidays {
+		observed[holiday] = true
+	}
+	for _, holiday := range holidays {
+		if holiday.Weekday() == time.Sunday {
+			monday := holiday.AddDate(0, 0, 1)
+			for observed[monday] {
+				monday = monday.AddDate(0, 0, 1)
+			}
+			observed[monday] = true
+		}
+	}
+	return observed
+}
+
+// easterSunday returns the date of Easter Sunday in a year of the Gregorian
+// calendar
+func easterSunday(year int) time.Time {
+	a := year % 19
+	b, c := year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,107 @@
+package compliance
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/comply360/shared/models"
+)
+
+// reminderAlarm is when calendar apps alert for an open obligation
+const reminderAlarm = "-P7D"
+
+// ICal renders obligations as an iCalendar (RFC 5545) feed of all-day events
+// on their due dates. Waived obligations are cancelled events; open ones carry
+// an alarm a week before they fall due.
+func ICal(name string, obligations []*models.ComplianceObligation) string {
+	var b strings.Builder
+	line := func(format string, args ...interface{}) {
+		b.WriteString(fold(fmt.Sprintf(format, args...)))
+		b.WriteString("\r\n")
+	}
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//Comply360//Compliance Calendar//EN")
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:PUBLISH")
+	line("X-WR-CALNAME:%s", escape(name))
+
+	for _, o := range obligations {
+		summary := o.Title
+		if o.CompanyName != "" {
+			summary = fmt.Sprintf("%s - %s", o.Title, o.CompanyName)
+		}
+		if o.Status == models.ObligationStatusCompleted {
+			summary = "Filed: " + summary
+		}
+		status := "CONFIRMED"
+		if o.Status == models.ObligationStatusWaived {
+			status = "CANCELLED"
+		}
+		description := fmt.Sprintf("%s\nAuthority: %s\nPeriod: %s to %s\nStatus: %s",
+			o.Title, o.Authority, o.PeriodStart.Format("2006-01-02"), o.PeriodEnd.Format("2006-01-02"), o.Status)
+		if o.FilingReference != nil {
+			description += "\nFiling reference: " + *o.FilingReference
+		}
+
+		line("BEGIN:VEVENT")
+		line("UID:%s@comply360", o.ID)
+		line("DTSTAMP:%s", o.UpdatedAt.UTC().Format("20060102T150405Z"))
+		line("DTSTART;VALUE=DATE:%s", o.DueDate.Format("20060102"))
+		line("DTEND;VALUE=DATE:%s", o.DueDate.AddDate(0, 0, 1).Format("20060102"))
+		line("SUMMARY:%s", escape(summary))
+		line("DESCRIPTION:%s", escape(description))
+		line("CATEGORIES:%s", escape(o.ObligationType))
+		line("STATUS:%s", status)
+		line("TRANSP:TRANSPARENT")
+		if o.IsOpen() {
+			line("BEGIN:VALARM")
+			line("ACTION:DISPLAY")
+			line("TRIGGER:%s", reminderAlarm)
+			line("DESCRIPTION:%s", escape(fmt.Sprintf("%s is due on %s", summary, o.DueDate.Format("2 January 2006"))))
+			line("END:VALARM")
+		}
+		line("END:VEVENT")
+	}
+
+	line("END:VCALENDAR")
+	return b.String()
+}
+
+// escape escapes text for an iCalendar property value
+func escape(text string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(text)
+}
+
+// fold splits a content line longer than 75 octets into continuation lines,
+// without splitting a character
+func fold(line string) string {
+	const limit = 75
+	if len(line) <= limit {
+		return line
+	}
+
+	var b strings.Builder
+	width := limit
+	for len(line) > width {
+		cut := width
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space, which counts
+		width = limit - 1
+	}
+	b.WriteString(line)
+	return b.String()
+}
@@ -0,0 +1,165 @@
+// Package fees prices registrations: which fee schedule is in effect on a
+// date, what a schedule must hold and how a quote adds up government fees,
+// service fees, add-ons and VAT.
+package fees
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/comply360/shared/jurisdictions"
+	"github.com/comply360/shared/models"
+)
+
+// RuleError is a schedule or quote the fee rules refuse
+type RuleError struct {
+	Message string
+}
+
+func (e *RuleError) Error() string {
+	return e.Message
+}
+
+func refuse(format string, args ...interface{}) error {
+	return &RuleError{Message: fmt.Sprintf(format, args...)}
+}
+
+// ParseDate parses a YYYY-MM-DD date
+func ParseDate(value string) (time.Time, error) {
+	return time.Parse("2006-01-02", value)
+}
+
+// Date returns the calendar date of a time, at midnight UTC
+func Date(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Effective returns the schedule in effect on a date: of the schedules that
+// took effect on or before it, the latest. It returns nil when none has.
+func Effective(schedules []*models.FeeSchedule, on time.Time) *models.FeeSchedule {
+	day := Date(on)
+	var effective *models.FeeSchedule
+	for _, schedule := range schedules {
+		if schedule.EffectiveFrom.After(day) {
+			continue
+		}
+		if effective == nil || schedule.EffectiveFrom.After(effective.EffectiveFrom) {
+			effective = schedule
+		}
+	}
+	return effective
+}
+
+// Validate checks a schedule is for a registration type its jurisdiction
+// offers and that its item codes are unique. A schedule in another currency
+// than the jurisdiction's must list its government fees, since the
+// jurisdiction's own are in the jurisdiction's currency.
+func Validate(j jurisdictions.Jurisdiction, schedule *models.FeeSchedule) error {
+	if !jurisdictions.Offers(j, schedule.RegistrationType) {
+		return refuse("%s does not offer %s registrations", j.Name(), schedule.RegistrationType)
+	}
+
+	codes := map[string]bool{}
+	government := false
+	for _, item := range schedule.Items {
+		if codes[item.Code] {
+			return refuse("fee code %q is used twice", item.Code)
+		}
+		codes[item.Code] = true
+		government = government || item.Kind == models.FeeKindGovernment
+	}
+
+	if !government && schedule.Currency != j.Currency() && len(j.Fees(schedule.RegistrationType)) > 0 {
+		return refuse("a schedule in %s must list the government fees, which %s charges in %s", schedule.Currency, j.Registry(), j.Currency())
+	}
+	return nil
+}
+
+// Quote prices a registration type from a schedule, with the add-ons asked
+// for by code. Without a schedule only the jurisdiction's government fees are
+// quoted; a schedule listing no government fees is quoted with the
+// jurisdiction's. Government fees carry no VAT, and service fees and add-ons
+// carry the schedule's VAT rate.
+func Quote(j jurisdictions.Jurisdiction, registrationType string, schedule *models.FeeSchedule, addOns []string) (*models.Quote, error) {
+	quote := &models.Quote{
+		Currency: j.Currency(),
+		VATRate:  j.VATRate(),
+		AddOns:   []string{},
+		Lines:    []models.QuoteLine{},
+		Status:   models.QuoteStatusDraft,
+	}
+	items := []models.FeeItem{}
+	if schedule != nil {
+		scheduleID := schedule.ID
+		quote.FeeScheduleID = &scheduleID
+		quote.Currency = schedule.Currency
+		quote.VATRate = schedule.VATRate
+		items = schedule.Items
+	}
+
+	if !hasGovernmentFees(items) {
+		government := []models.FeeItem{}
+		for _, fee := range j.Fees(registrationType) {
+			government = append(government, models.FeeItem{
+				Code:        fee.Code,
+				Description: fee.Description,
+				Kind:        models.FeeKindGovernment,
+				Amount:      fee.Amount,
+			})
+		}
+		items = append(government, items...)
+	}
+
+	wanted := map[string]bool{}
+	for _, code := range addOns {
+		wanted[code] = true
+	}
+	for _, item := range items {
+		if item.Kind == models.FeeKindAddOn {
+			if !wanted[item.Code] {
+				continue
+			}
+			quote.AddOns = append(quote.AddOns, item.Code)
+			delete(wanted, item.Code)
+		}
+
+		line := models.QuoteLine{
+			Code:        item.Code,
+			Description: item.Description,
+			Kind:        item.Kind,
+			Amount:      round(item.Amount),
+		}
+		if item.Kind != models.FeeKindGovernment {
+			line.VATRate = quote.VATRate
+			line.VAT = round(line.Amount * quote.VATRate / 100)
+		}
+		quote.Lines = append(quote.Lines, line)
+		quote.Subtotal += line.Amount
+		quote.VAT += line.VAT
+	}
+	for _, code := range addOns {
+		if wanted[code] {
+			return nil, refuse("add-on %q is not on the fee schedule", code)
+		}
+	}
+
+	quote.Subtotal = round(quote.Subtotal)
+	quote.VAT = round(quote.VAT)
+	quote.Total = round(quote.Subtotal + quote.VAT)
+	return quote, nil
+}
+
+func hasGovernmentFees(items []models.FeeItem) bool {
+	for _, item := range items {
+		if item.Kind == models.FeeKindGovernment {
+			return true
+		}
+	}
+	return false
+}
+
+// round rounds an amount to cents
+func round(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,131 @@
+package fees
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/comply360/shared/jurisdictions"
+	"github.com/comply360/shared/models"
+	testhelpers "github.com/comply360/shared/testing"
+	"github.com/google/uuid"
+)
+
+func date(value string) time.Time {
+	d, err := ParseDate(value)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func schedule(effectiveFrom string, items ...models.FeeItem) *models.FeeSchedule {
+	return &models.FeeSchedule{
+		ID:               uuid.New(),
+		Jurisdiction:     models.JurisdictionSouthAfrica,
+		RegistrationType: models.RegistrationTypePtyLtd,
+		Currency:         models.CurrencyZAR,
+		VATRate:          15,
+		EffectiveFrom:    date(effectiveFrom),
+		Items:            items,
+	}
+}
+
+var (
+	incorporation = models.FeeItem{Code: "incorporation_service", Description: "Company incorporation", Kind: models.FeeKindService, Amount: 1000}
+	bankAccount   = models.FeeItem{Code: "bank_account", Description: "Business bank account", Kind: models.FeeKindAddOn, Amount: 500}
+	bbbee         = models.FeeItem{Code: "bbbee_affidavit", Description: "B-BBEE affidavit", Kind: models.FeeKindAddOn, Amount: 99.99}
+)
+
+func TestEffective(t *testing.T) {
+	january := schedule("2026-01-01", incorporation)
+	july := schedule("2026-07-01", incorporation)
+	next := schedule("2027-01-01", incorporation)
+	schedules := []*models.FeeSchedule{next, january, july}
+
+	testhelpers.AssertNil(t, Effective(schedules, date("2025-12-31")), "No schedule had taken effect")
+	testhelpers.AssertEqual(t, january.ID, Effective(schedules, date("2026-06-30")).ID)
+	testhelpers.AssertEqual(t, july.ID, Effective(schedules, time.Date(2026, 7, 1, 15, 30, 0, 0, time.UTC)).ID, "Takes effect on its first day")
+	testhelpers.AssertEqual(t, july.ID, Effective(schedules, date("2026-10-18")).ID)
+	testhelpers.AssertEqual(t, next.ID, Effective(schedules, date("2027-03-01")).ID)
+}
+
+func TestValidate(t *testing.T) {
+	za := jurisdictions.SouthAfrica{}
+	testhelpers.AssertNoError(t, Validate(za, schedule("2026-01-01", incorporation, bankAccount)))
+
+	twice := schedule("2026-01-01", incorporation, incorporation)
+	_, ok := Validate(za, twice).(*RuleError)
+	testhelpers.AssertTrue(t, ok, "Codes must be unique")
+
+	inDollars := schedule("2026-01-01", incorporation)
+	inDollars.Currency = models.CurrencyUSD
+	testhelpers.AssertError(t, Validate(za, inDollars), "CIPC's fees are in rand")
+	inDollars.Items = append(inDollars.Items, models.FeeItem{Code: "cipc", Description: "CIPC fees", Kind: models.FeeKindGovernment, Amount: 13})
+	testhelpers.AssertNoError(t, Validate(za, inDollars))
+
+	cc := schedule("2026-01-01", incorporation)
+	cc.RegistrationType = models.RegistrationTypeCloseCorporation
+	testhelpers.AssertError(t, Validate(jurisdictions.Zimbabwe{}, cc), "Zimbabwe has no close corporations")
+}
+
+func TestQuote_GovernmentFeesOnly(t *testing.T) {
+	quote, err := Quote(jurisdictions.SouthAfrica{}, models.RegistrationTypePtyLtd, nil, nil)
+	testhelpers.AssertNoError(t, err)
+
+	testhelpers.AssertNil(t, quote.FeeScheduleID)
+	testhelpers.AssertEqual(t, models.CurrencyZAR, quote.Currency)
+	testhelpers.AssertEqual(t, 2, len(quote.Lines))
+	testhelpers.AssertEqual(t, models.FeeKindGovernment, quote.Lines[0].Kind)
+	testhelpers.AssertEqual(t, 225.0, quote.Subtotal)
+	testhelpers.AssertEqual(t, 0.0, quote.VAT, "Government fees carry no VAT")
+	testhelpers.AssertEqual(t, 225.0, quote.Total)
+	testhelpers.AssertEqual(t, 0.0, quote.ServiceAmount())
+	testhelpers.AssertEqual(t, models.QuoteStatusDraft, quote.Status)
+
+	_, err = Quote(jurisdictions.SouthAfrica{}, models.RegistrationTypePtyLtd, nil, []string{"bank_account"})
+	testhelpers.AssertError(t, err, "There are no add-ons without a schedule")
+}
+
+func TestQuote_Schedule(t *testing.T) {
+	s := schedule("2026-01-01", incorporation, bankAccount, bbbee)
+
+	quote, err := Quote(jurisdictions.SouthAfrica{}, models.RegistrationTypePtyLtd, s, nil)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, s.ID, *quote.FeeScheduleID)
+	testhelpers.AssertEqual(t, 3, len(quote.Lines), "CIPC's fees and the service fee, without add-ons")
+	testhelpers.AssertEqual(t, "incorporation_service", quote.Lines[2].Code)
+	testhelpers.AssertEqual(t, 1225.0, quote.Subtotal)
+	testhelpers.AssertEqual(t, 150.0, quote.VAT)
+	testhelpers.AssertEqual(t, 1375.0, quote.Total)
+	testhelpers.AssertEqual(t, 1000.0, quote.ServiceAmount())
+
+	quote, err = Quote(jurisdictions.SouthAfrica{}, models.RegistrationTypePtyLtd, s, []string{"bbbee_affidavit", "bank_account", "bank_account"})
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "bank_account,bbbee_affidavit", strings.Join(quote.AddOns, ","), "Add-ons follow the schedule's order")
+	testhelpers.AssertEqual(t, 5, len(quote.Lines))
+	testhelpers.AssertEqual(t, 15.0, quote.Lines[4].VAT, "VAT is rounded to cents per line")
+	testhelpers.AssertEqual(t, 1824.99, quote.Subtotal)
+	testhelpers.AssertEqual(t, 240.0, quote.VAT)
+	testhelpers.AssertEqual(t, 2064.99, quote.Total)
+	testhelpers.AssertEqual(t, 1599.99, quote.ServiceAmount())
+
+	_, err = Quote(jurisdictions.SouthAfrica{}, models.RegistrationTypePtyLtd, s, []string{"shelf_company"})
+	_, ok := err.(*RuleError)
+	testhelpers.AssertTrue(t, ok, "Unknown add-ons are refused")
+}
+
+func TestQuote_ScheduleGovernmentFees(t *testing.T) {
+	s := schedule("2026-01-01",
+		models.FeeItem{Code: "cipc", Description: "CIPC fees", Kind: models.FeeKindGovernment, Amount: 250},
+		incorporation,
+	)
+	s.VATRate = 0
+
+	quote, err := Quote(jurisdictions.SouthAfrica{}, models.RegistrationTypePtyLtd, s, nil)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 2, len(quote.Lines), "The schedule's government fees replace the jurisdiction's")
+	testhelpers.AssertEqual(t, 1250.0, quote.Subtotal)
+	testhelpers.AssertEqual(t, 0.0, quote.VAT, "The tenant is not registered for VAT")
+	testhelpers.AssertEqual(t, 1250.0, quote.Total)
+}
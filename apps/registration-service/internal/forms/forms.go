@@ -0,0 +1,249 @@
+package forms
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/comply360/shared/validator"
+)
+
+//go:embed schemas
+var builtin embed.FS
+
+// schemaFileName is <registration_type>.v<version>.json under a jurisdiction directory
+var schemaFileName = regexp.MustCompile(`^([a-z_]+)\.v([0-9]+)\.json$`)
+
+// FormSchema is one version of the form_data schema of a registration type in a
+// jurisdiction
+type FormSchema struct {
+	Jurisdiction     string `json:"jurisdiction"`
+	RegistrationType string `json:"registration_type"`
+	Version          int    `json:"version"`
+	Title            string `json:"title"`
+	// Raw is the schema as written, served to frontends unchanged so keywords
+	// used only for rendering survive
+	Raw json.RawMessage `json:"-"`
+
+	schema *validator.Schema
+}
+
+// ValidationError lists the form_data fields that failed validation
+type ValidationError struct {
+	Fields []validator.ValidationError
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid form data"
+}
+
+// Registry holds every version of every form schema
+type Registry struct {
+	schemas   map[string]map[int]*FormSchema
+	validator *validator.Validator
+}
+
+// DefaultRegistry loads the built-in schemas
+func DefaultRegistry() (*Registry, error) {
+	sub, err := fs.Sub(builtin, "schemas")
+	if err != nil {
+		return nil, err
+	}
+	return LoadRegistry(sub)
+}
+
+// LoadRegistry loads schemas laid out as <jurisdiction>/<registration_type>.v<version>.json
+func LoadRegistry(fsys fs.FS) (*Registry, error) {
+	r := &Registry{
+		schemas:   map[string]map[int]*FormSchema{},
+		validator: validator.New(),
+	}
+
+	err := fs.WalkDir(fsys, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+
+		match := schemaFileName.FindStringSubmatch(path.Base(name))
+		if match == nil || path.Dir(name) == "." {
+			return fmt.Errorf("unexpected form schema file %s", name)
+		}
+		version, _ := strconv.Atoi(match[2])
+
+		raw, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		schema, err := validator.ParseSchema(raw)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+
+		form := &FormSchema{
+			Jurisdiction:     strings.ToUpper(path.Dir(name)),
+			RegistrationType: match[1],
+			Version:          version,
+			Title:            schema.Title,
+			Raw:              raw,
+			schema:           schema,
+		}
+
+		k := key(form.Jurisdiction, form.RegistrationType)
+		if r.schemas[k] == nil {
+			r.schemas[k] = map[int]*FormSchema{}
+		}
+		r.schemas[k][version] = form
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load form schemas: %w", err)
+	}
+
+	return r, nil
+}
+
+// Latest returns the newest schema of a registration type in a jurisdiction
+func (r *Registry) Latest(jurisdiction, registrationType string) (*FormSchema, error) {
+	versions := r.schemas[key(jurisdiction, registrationType)]
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("form schema not found")
+	}
+
+	latest := 0
+	for version := range versions {
+		if version > latest {
+			latest = version
+		}
+	}
+	return versions[latest], nil
+}
+
+// Get returns a specific schema version
+func (r *Registry) Get(jurisdiction, registrationType string, version int) (*FormSchema, error) {
+	form, ok := r.schemas[key(jurisdiction, registrationType)][version]
+	if !ok {
+		return nil, fmt.Errorf("form schema not found")
+	}
+	return form, nil
+}
+
+// List returns every schema version, ordered by jurisdiction, type and version
+func (r *Registry) List() []*FormSchema {
+	var list []*FormSchema
+	for _, versions := range r.schemas {
+		for _, form := range versions {
+			list = append(list, form)
+		}
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		a, b := list[i], list[j]
+		if a.Jurisdiction != b.Jurisdiction {
+			return a.Jurisdiction < b.Jurisdiction
+		}
+		if a.RegistrationType != b.RegistrationType {
+			return a.RegistrationType < b.RegistrationType
+		}
+		return a.Version < b.Version
+	})
+	return list
+}
+
+// Validate checks form data against a schema. With complete unset, missing
+// required fields are allowed so drafts can be saved as they are filled in.
+func (r *Registry) Validate(form *FormSchema, data map[string]interface{}, complete bool) error {
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+
+	var fields []validator.ValidationError
+	if complete {
+		fields = r.validator.ValidateSchema(form.schema, data)
+	} else {
+		fields = r.validator.ValidatePartialSchema(form.schema, data)
+	}
+	if len(fields) > 0 {
+		for i := range fields {
+			fields[i].Field = "form_data." + fields[i].Field
+		}
+		return &ValidationError{Fields: fields}
+	}
+	return nil
+}
+
+// Decode builds form data from text values, such as spreadsheet cells, keyed by
+// dotted paths like "registered_address.city". Values are converted to the type
+// the schema declares for their path; arrays and objects are read as JSON. A
+// value that does not convert is kept as text so validation reports it.
+func (f *FormSchema) Decode(fields map[string]string) map[string]interface{} {
+	data := map[string]interface{}{}
+
+	paths := make([]string, 0, len(fields))
+	for path := range fields {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		text := strings.TrimSpace(fields[path])
+		if text == "" {
+			continue
+		}
+
+		names := strings.Split(path, ".")
+		object, schema := data, f.schema
+		for _, name := range names[:len(names)-1] {
+			child, ok := object[name].(map[string]interface{})
+			if !ok {
+				child = map[string]interface{}{}
+				object[name] = child
+			}
+			object = child
+			if schema != nil {
+				schema = schema.Properties[name]
+			}
+		}
+
+		last := names[len(names)-1]
+		if schema != nil {
+			schema = schema.Properties[last]
+		}
+		object[last] = decodeValue(schema, text)
+	}
+
+	return data
+}
+
+// decodeValue converts text to the type a schema declares
+func decodeValue(schema *validator.Schema, text string) interface{} {
+	if schema == nil {
+		return text
+	}
+
+	switch schema.Type {
+	case "integer", "number":
+		if number, err := strconv.ParseFloat(text, 64); err == nil {
+			return number
+		}
+	case "boolean":
+		if value, err := strconv.ParseBool(text); err == nil {
+			return value
+		}
+	case "array", "object":
+		var value interface{}
+		if err := json.Unmarshal([]byte(text), &value); err == nil {
+			return value
+		}
+	}
+	return text
+}
+
+func key(jurisdiction, registrationType string) string {
+	return strings.ToUpper(jurisdiction) + "/" + registrationType
+}
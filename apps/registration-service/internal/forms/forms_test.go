@@ -0,0 +1,96 @@
+package forms
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/comply360/shared/models"
+	testhelpers "github.com/comply360/shared/testing"
+)
+
+func TestDefaultRegistry_LoadsBuiltinSchemas(t *testing.T) {
+	registry, err := DefaultRegistry()
+	testhelpers.AssertNoError(t, err)
+
+	for _, registrationType := range []string{
+		models.RegistrationTypePtyLtd,
+		models.RegistrationTypeCloseCorporation,
+		models.RegistrationTypeBusinessName,
+		models.RegistrationTypeVATRegistration,
+	} {
+		form, err := registry.Latest(models.JurisdictionSouthAfrica, registrationType)
+		testhelpers.AssertNoError(t, err, registrationType)
+		testhelpers.AssertEqual(t, 1, form.Version)
+		testhelpers.AssertNotEqual(t, "", form.Title)
+	}
+}
+
+func TestRegistry_ValidateDraftAndComplete(t *testing.T) {
+	registry, err := DefaultRegistry()
+	testhelpers.AssertNoError(t, err)
+	form, err := registry.Latest("za", models.RegistrationTypePtyLtd)
+	testhelpers.AssertNoError(t, err)
+
+	draft := map[string]interface{}{
+		"directors": []interface{}{
+			map[string]interface{}{"full_name": "Thandi Nkosi", "id_number": "8001015009087"},
+		},
+	}
+	testhelpers.AssertNoError(t, registry.Validate(form, draft, false), "An incomplete draft should be accepted")
+
+	err = registry.Validate(form, draft, true)
+	testhelpers.AssertError(t, err, "A submission must be complete")
+	invalid := err.(*ValidationError)
+	testhelpers.AssertEqual(t, "form_data.financial_year_end", invalid.Fields[0].Field)
+
+	draft["directors"] = []interface{}{map[string]interface{}{"id_number": "123"}}
+	err = registry.Validate(form, draft, false)
+	testhelpers.AssertError(t, err, "Invalid values are rejected even in drafts")
+	testhelpers.AssertEqual(t, "form_data.directors[0].id_number", err.(*ValidationError).Fields[0].Field)
+}
+
+func TestLoadRegistry_KeepsVersions(t *testing.T) {
+	registry, err := LoadRegistry(fstest.MapFS{
+		"za/business_name.v1.json": {Data: []byte(`{"title": "v1", "type": "object"}`)},
+		"za/business_name.v2.json": {Data: []byte(`{"title": "v2", "type": "object"}`)},
+	})
+	testhelpers.AssertNoError(t, err)
+
+	latest, err := registry.Latest("ZA", models.RegistrationTypeBusinessName)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 2, latest.Version)
+
+	first, err := registry.Get("ZA", models.RegistrationTypeBusinessName, 1)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "v1", first.Title)
+
+	_, err = registry.Latest("ZW", models.RegistrationTypeBusinessName)
+	testhelpers.AssertError(t, err)
+}
+
+func TestFormSchema_DecodeConvertsDeclaredTypes(t *testing.T) {
+	registry, err := DefaultRegistry()
+	testhelpers.AssertNoError(t, err)
+	form, err := registry.Latest("za", models.RegistrationTypePtyLtd)
+	testhelpers.AssertNoError(t, err)
+
+	data := form.Decode(map[string]string{
+		"financial_year_end":             "2",
+		"registered_address.city":        "Johannesburg",
+		"registered_address.postal_code": "2196",
+		"directors":                      `[{"full_name": "Thandi Nkosi", "id_number": "8001015009087"}]`,
+		"authorised_shares":              "many",
+		"business_activity":              " ",
+	})
+
+	testhelpers.AssertEqual(t, float64(2), data["financial_year_end"])
+	address := data["registered_address"].(map[string]interface{})
+	testhelpers.AssertEqual(t, "2196", address["postal_code"], "Text fields stay text")
+	testhelpers.AssertEqual(t, 1, len(data["directors"].([]interface{})))
+	testhelpers.AssertEqual(t, "many", data["authorised_shares"], "Values that do not convert are left for validation")
+	_, blank := data["business_activity"]
+	testhelpers.AssertFalse(t, blank, "Blank values are left out")
+
+	err = registry.Validate(form, data, false)
+	testhelpers.AssertTrue(t, err != nil, "authorised_shares is not an integer")
+}
@@ -0,0 +1,304 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/comply360/registration-service/internal/services"
+	"github.com/comply360/shared/errors"
+	sharedmiddleware "github.com/comply360/shared/middleware"
+	"github.com/comply360/shared/models"
+	"github.com/comply360/shared/pagination"
+	"github.com/gin-gonic/gin"
+)
+
+type ClientHandler struct {
+	service *services.ClientService
+}
+
+func NewClientHandler(service *services.ClientService) *ClientHandler {
+	return &ClientHandler{
+		service: service,
+	}
+}
+
+// CreateClient handles POST /clients. A possible duplicate of an existing
+// client is refused unless allow_duplicates=true.
+func (h *ClientHandler) CreateClient(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	var req models.CreateClientRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIErrorWithDetails(
+			errors.ErrInvalidInput,
+			"Invalid request body",
+			map[string]interface{}{"error": err.Error()},
+		))
+		return
+	}
+
+	allowDuplicates := false
+	if value := c.Query("allow_duplicates"); value != "" {
+		var err error
+		if allowDuplicates, err = strconv.ParseBool(value); err != nil {
+			c.JSON(http.StatusBadRequest, errors.NewAPIError(errors.ErrInvalidInput, "allow_duplicates must be true or false"))
+			return
+		}
+	}
+
+	client, err := h.service.CreateClient(schema.(string), tenantID, &req, allowDuplicates)
+	if err != nil {
+		respondClientError(c, err, "Failed to create client")
+		return
+	}
+
+	c.JSON(http.StatusCreated, client)
+}
+
+// ListClients handles GET /clients
+func (h *ClientHandler) ListClients(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(errors.ErrInvalidInput, err.Error()))
+		return
+	}
+
+	list, err := h.service.ListClients(schema.(string), tenantID, c.Query("status"), c.Query("client_type"), page)
+	if err != nil {
+		if pagination.IsRequestError(err) {
+			c.JSON(http.StatusBadRequest, errors.NewAPIError(errors.ErrInvalidInput, err.Error()))
+			return
+		}
+		respondClientError(c, err, "Failed to list clients")
+		return
+	}
+
+	pagination.Respond(c, list, page)
+}
+
+// GetClient handles GET /clients/:id
+func (h *ClientHandler) GetClient(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	clientID, ok := parseID(c, "id", "Invalid client ID")
+	if !ok {
+		return
+	}
+
+	client, err := h.service.GetClient(schema.(string), tenantID, clientID)
+	if err != nil {
+		respondClientError(c, err, "Failed to get client")
+		return
+	}
+
+	c.JSON(http.StatusOK, client)
+}
+
+// UpdateClient handles PUT /clients/:id
+func (h *ClientHandler) UpdateClient(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	clientID, ok := parseID(c, "id", "Invalid client ID")
+	if !ok {
+		return
+	}
+
+	var req models.UpdateClientRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIErrorWithDetails(
+			errors.ErrInvalidInput,
+			"Invalid request body",
+			map[string]interface{}{"error": err.Error()},
+		))
+		return
+	}
+
+	client, err := h.service.UpdateClient(schema.(string), tenantID, clientID, &req)
+	if err != nil {
+		respondClientError(c, err, "Failed to update client")
+		return
+	}
+
+	c.JSON(http.StatusOK, client)
+}
+
+// DeleteClient handles DELETE /clients/:id
+func (h *ClientHandler) DeleteClient(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	clientID, ok := parseID(c, "id", "Invalid client ID")
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteClient(schema.(string), tenantID, clientID); err != nil {
+		respondClientError(c, err, "Failed to delete client")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Client deleted successfully",
+	})
+}
+
+// LinkUser handles PUT /clients/:id/user
+func (h *ClientHandler) LinkUser(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	clientID, ok := parseID(c, "id", "Invalid client ID")
+	if !ok {
+		return
+	}
+
+	var req models.LinkClientUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIErrorWithDetails(
+			errors.ErrInvalidInput,
+			"Invalid request body",
+			map[string]interface{}{"error": err.Error()},
+		))
+		return
+	}
+
+	client, err := h.service.LinkUser(schema.(string), tenantID, clientID, &req)
+	if err != nil {
+		respondClientError(c, err, "Failed to link user")
+		return
+	}
+
+	c.JSON(http.StatusOK, client)
+}
+
+// UnlinkUser handles DELETE /clients/:id/user
+func (h *ClientHandler) UnlinkUser(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	clientID, ok := parseID(c, "id", "Invalid client ID")
+	if !ok {
+		return
+	}
+
+	client, err := h.service.UnlinkUser(schema.(string), tenantID, clientID)
+	if err != nil {
+		respondClientError(c, err, "Failed to unlink user")
+		return
+	}
+
+	c.JSON(http.StatusOK, client)
+}
+
+// FindDuplicates handles GET /clients/:id/duplicates
+func (h *ClientHandler) FindDuplicates(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	clientID, ok := parseID(c, "id", "Invalid client ID")
+	if !ok {
+		return
+	}
+
+	duplicates, err := h.service.FindDuplicates(schema.(string), tenantID, clientID)
+	if err != nil {
+		respondClientError(c, err, "Failed to find duplicate clients")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": duplicates,
+	})
+}
+
+// MergeClient handles POST /clients/:id/merge, merging the source client in the
+// body into the client in the path
+func (h *ClientHandler) MergeClient(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	clientID, ok := parseID(c, "id", "Invalid client ID")
+	if !ok {
+		return
+	}
+
+	var req models.MergeClientRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIErrorWithDetails(
+			errors.ErrInvalidInput,
+			"Invalid request body",
+			map[string]interface{}{"error": err.Error()},
+		))
+		return
+	}
+
+	client, merge, err := h.service.MergeClients(schema.(string), tenantID, clientID, &req, actor(c).ID())
+	if err != nil {
+		respondClientError(c, err, "Failed to merge clients")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"client": client,
+		"merge":  merge,
+	})
+}
+
+// GetKYC handles GET /clients/:id/kyc
+func (h *ClientHandler) GetKYC(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	clientID, ok := parseID(c, "id", "Invalid client ID")
+	if !ok {
+		return
+	}
+
+	kyc, err := h.service.GetKYC(schema.(string), tenantID, clientID)
+	if err != nil {
+		respondClientError(c, err, "Failed to get client KYC")
+		return
+	}
+
+	c.JSON(http.StatusOK, kyc)
+}
+
+// respondClientError maps client service errors to HTTP responses
+func respondClientError(c *gin.Context, err error, fallback string) {
+	if invalid, ok := err.(*errors.APIError); ok {
+		c.JSON(http.StatusBadRequest, invalid)
+		return
+	}
+	if duplicate, ok := err.(*services.DuplicateClientError); ok {
+		c.JSON(http.StatusConflict, errors.NewAPIErrorWithDetails(
+			errors.ErrConflict,
+			"Possible duplicate client; merge it or retry with allow_duplicates=true",
+			map[string]interface{}{"duplicates": duplicate.Duplicates},
+		))
+		return
+	}
+
+	switch err.Error() {
+	case "client not found":
+		c.JSON(http.StatusNotFound, errors.NewAPIError(errors.ErrNotFound, "Client not found"))
+	case "user is already linked to a client", "clients are linked to different users":
+		c.JSON(http.StatusConflict, errors.NewAPIError(errors.ErrConflict, err.Error()))
+	case "user not found", "email is required", "full_name is required for individual clients",
+		"company_name is required for company clients", "city is required with a street address",
+		"postal_code must be 4 digits for South African addresses", "cannot merge a client into itself",
+		"cannot merge clients of different types":
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(errors.ErrInvalidInput, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, errors.NewAPIErrorWithDetails(
+			errors.ErrInternalServer,
+			fallback,
+			map[string]interface{}{"error": err.Error()},
+		))
+	}
+}
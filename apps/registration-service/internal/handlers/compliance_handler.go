@@ -0,0 +1,348 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/comply360/registration-service/internal/repository"
+	"github.com/comply360/registration-service/internal/services"
+	"github.com/comply360/shared/errors"
+	sharedmiddleware "github.com/comply360/shared/middleware"
+	"github.com/comply360/shared/models"
+	"github.com/comply360/shared/pagination"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ComplianceHandler struct {
+	service *services.ComplianceService
+}
+
+func NewComplianceHandler(service *services.ComplianceService) *ComplianceHandler {
+	return &ComplianceHandler{
+		service: service,
+	}
+}
+
+// SetupRoutes sets up the compliance routes under /compliance
+func (h *ComplianceHandler) SetupRoutes(r *gin.RouterGroup) {
+	managers := sharedmiddleware.RequireRole(models.RoleTenantAdmin, models.RoleTenantManager, models.RoleGlobalAdmin)
+
+	r.GET("/obligations", h.ListObligations)
+	r.GET("/obligations/:id", h.GetObligation)
+	r.POST("/obligations/:id/complete", h.CompleteObligation)
+	r.POST("/obligations/:id/waive", managers, h.WaiveObligation)
+	r.POST("/obligations/:id/reopen", managers, h.ReopenObligation)
+	r.GET("/calendar.ics", h.Calendar)
+	r.GET("/feed", h.FeedURL)
+	r.POST("/feed/rotate", managers, h.RotateFeed)
+}
+
+// SetupClientRoutes sets up a client's compliance routes under /clients
+func (h *ComplianceHandler) SetupClientRoutes(r *gin.RouterGroup) {
+	r.GET("/:id/compliance", h.ListClientObligations)
+	r.GET("/:id/compliance/calendar.ics", h.ClientCalendar)
+	r.GET("/:id/compliance/feed", h.ClientFeedURL)
+}
+
+// SetupPublicRoutes sets up the calendar feeds calendar apps subscribe to,
+// which are signed rather than authenticated
+func (h *ComplianceHandler) SetupPublicRoutes(r *gin.RouterGroup) {
+	r.GET("/feeds/:token", h.Feed)
+}
+
+// ListObligations handles GET /compliance/obligations
+func (h *ComplianceHandler) ListObligations(c *gin.Context) {
+	filter, ok := complianceFilter(c)
+	if !ok {
+		return
+	}
+	if v := c.Query("client_id"); v != "" {
+		clientID, err := uuid.Parse(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errors.NewAPIError(errors.ErrInvalidInput, "Invalid client_id"))
+			return
+		}
+		filter.ClientID = &clientID
+	}
+	if v := c.Query("registration_id"); v != "" {
+		registrationID, err := uuid.Parse(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errors.NewAPIError(errors.ErrInvalidInput, "Invalid registration_id"))
+			return
+		}
+		filter.RegistrationID = &registrationID
+	}
+
+	h.listObligations(c, filter)
+}
+
+// ListClientObligations handles GET /clients/:id/compliance
+func (h *ComplianceHandler) ListClientObligations(c *gin.Context) {
+	clientID, ok := parseID(c, "id", "Invalid client ID")
+	if !ok {
+		return
+	}
+	filter, ok := complianceFilter(c)
+	if !ok {
+		return
+	}
+	filter.ClientID = &clientID
+
+	h.listObligations(c, filter)
+}
+
+func (h *ComplianceHandler) listObligations(c *gin.Context, filter repository.ComplianceFilter) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(errors.ErrInvalidInput, err.Error()))
+		return
+	}
+
+	list, err := h.service.ListObligations(schema.(string), tenantID, filter, page)
+	if err != nil {
+		if pagination.IsRequestError(err) {
+			c.JSON(http.StatusBadRequest, errors.NewAPIError(errors.ErrInvalidInput, err.Error()))
+			return
+		}
+		respondComplianceError(c, err, "Failed to list compliance obligations")
+		return
+	}
+
+	pagination.Respond(c, list, page)
+}
+
+// complianceFilter reads the status, obligation_type, due_from and due_to
+// filters. Due dates are calendar dates, both inclusive.
+func complianceFilter(c *gin.Context) (repository.ComplianceFilter, bool) {
+	filter := repository.ComplianceFilter{
+		Status:         c.Query("status"),
+		ObligationType: c.Query("obligation_type"),
+	}
+	for param, value := range map[string]**time.Time{"due_from": &filter.DueFrom, "due_to": &filter.DueTo} {
+		v := c.Query(param)
+		if v == "" {
+			continue
+		}
+		date, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errors.NewAPIError(errors.ErrInvalidInput, "Invalid "+param+"; use YYYY-MM-DD"))
+			return filter, false
+		}
+		*value = &date
+	}
+	return filter, true
+}
+
+// GetObligation handles GET /compliance/obligations/:id
+func (h *ComplianceHandler) GetObligation(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	obligationID, ok := parseID(c, "id", "Invalid obligation ID")
+	if !ok {
+		return
+	}
+
+	obligation, err := h.service.GetObligation(schema.(string), tenantID, obligationID)
+	if err != nil {
+		respondComplianceError(c, err, "Failed to get compliance obligation")
+		return
+	}
+
+	c.JSON(http.StatusOK, obligation)
+}
+
+// CompleteObligation handles POST /compliance/obligations/:id/complete
+func (h *ComplianceHandler) CompleteObligation(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	obligationID, ok := parseID(c, "id", "Invalid obligation ID")
+	if !ok {
+		return
+	}
+
+	var req models.CompleteObligationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIErrorWithDetails(
+			errors.ErrInvalidInput,
+			"Invalid request body",
+			map[string]interface{}{"error": err.Error()},
+		))
+		return
+	}
+
+	obligation, err := h.service.CompleteObligation(schema.(string), tenantID, obligationID, &req, actor(c).ID())
+	if err != nil {
+		respondComplianceError(c, err, "Failed to complete compliance obligation")
+		return
+	}
+
+	c.JSON(http.StatusOK, obligation)
+}
+
+// WaiveObligation handles POST /compliance/obligations/:id/waive
+func (h *ComplianceHandler) WaiveObligation(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	obligationID, ok := parseID(c, "id", "Invalid obligation ID")
+	if !ok {
+		return
+	}
+
+	var req models.WaiveObligationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIErrorWithDetails(
+			errors.ErrInvalidInput,
+			"Invalid request body",
+			map[string]interface{}{"error": err.Error()},
+		))
+		return
+	}
+
+	obligation, err := h.service.WaiveObligation(schema.(string), tenantID, obligationID, &req, actor(c).ID())
+	if err != nil {
+		respondComplianceError(c, err, "Failed to waive compliance obligation")
+		return
+	}
+
+	c.JSON(http.StatusOK, obligation)
+}
+
+// ReopenObligation handles POST /compliance/obligations/:id/reopen
+func (h *ComplianceHandler) ReopenObligation(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	obligationID, ok := parseID(c, "id", "Invalid obligation ID")
+	if !ok {
+		return
+	}
+
+	obligation, err := h.service.ReopenObligation(schema.(string), tenantID, obligationID)
+	if err != nil {
+		respondComplianceError(c, err, "Failed to reopen compliance obligation")
+		return
+	}
+
+	c.JSON(http.StatusOK, obligation)
+}
+
+// Calendar handles GET /compliance/calendar.ics
+func (h *ComplianceHandler) Calendar(c *gin.Context) {
+	h.calendar(c, nil)
+}
+
+// ClientCalendar handles GET /clients/:id/compliance/calendar.ics
+func (h *ComplianceHandler) ClientCalendar(c *gin.Context) {
+	clientID, ok := parseID(c, "id", "Invalid client ID")
+	if !ok {
+		return
+	}
+	h.calendar(c, &clientID)
+}
+
+func (h *ComplianceHandler) calendar(c *gin.Context, clientID *uuid.UUID) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	calendar, err := h.service.Calendar(schema.(string), tenantID, clientID)
+	if err != nil {
+		respondComplianceError(c, err, "Failed to render compliance calendar")
+		return
+	}
+
+	respondCalendar(c, calendar)
+}
+
+// FeedURL handles GET /compliance/feed
+func (h *ComplianceHandler) FeedURL(c *gin.Context) {
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	feed, err := h.service.FeedURL(tenantID, nil)
+	if err != nil {
+		respondComplianceError(c, err, "Failed to get calendar feed")
+		return
+	}
+
+	c.JSON(http.StatusOK, feed)
+}
+
+// RotateFeed handles POST /compliance/feed/rotate
+func (h *ComplianceHandler) RotateFeed(c *gin.Context) {
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	feed, err := h.service.RotateFeed(tenantID)
+	if err != nil {
+		respondComplianceError(c, err, "Failed to rotate calendar feed")
+		return
+	}
+
+	c.JSON(http.StatusOK, feed)
+}
+
+// ClientFeedURL handles GET /clients/:id/compliance/feed
+func (h *ComplianceHandler) ClientFeedURL(c *gin.Context) {
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	clientID, ok := parseID(c, "id", "Invalid client ID")
+	if !ok {
+		return
+	}
+
+	feed, err := h.service.FeedURL(tenantID, &clientID)
+	if err != nil {
+		respondComplianceError(c, err, "Failed to get calendar feed")
+		return
+	}
+
+	c.JSON(http.StatusOK, feed)
+}
+
+// Feed handles GET /public/compliance/feeds/:token
+func (h *ComplianceHandler) Feed(c *gin.Context) {
+	calendar, err := h.service.FeedCalendar(c.Param("token"))
+	if err != nil {
+		respondComplianceError(c, err, "Failed to render compliance calendar")
+		return
+	}
+
+	respondCalendar(c, calendar)
+}
+
+// respondCalendar writes an iCalendar feed
+func respondCalendar(c *gin.Context, calendar string) {
+	c.Header("Cache-Control", "private, max-age=900")
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", []byte(calendar))
+}
+
+func respondComplianceError(c *gin.Context, err error, fallback string) {
+	if invalid, ok := err.(*errors.APIError); ok {
+		c.JSON(http.StatusBadRequest, invalid)
+		return
+	}
+
+	switch err.Error() {
+	case "compliance obligation not found":
+		c.JSON(http.StatusNotFound, errors.NewAPIError(errors.ErrNotFound, "Compliance obligation not found"))
+	case "tenant not found":
+		c.JSON(http.StatusNotFound, errors.NewAPIError(errors.ErrNotFound, "Tenant not found"))
+	case "invalid calendar feed token":
+		c.JSON(http.StatusNotFound, errors.NewAPIError(errors.ErrNotFound, "Calendar feed not found"))
+	case "compliance obligation is already closed", "compliance obligation is already open":
+		c.JSON(http.StatusConflict, errors.NewAPIError(errors.ErrConflict, err.Error()))
+	case "completion date cannot be in the future":
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(errors.ErrInvalidInput, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, errors.NewAPIErrorWithDetails(
+			errors.ErrInternalServer,
+			fallback,
+			map[string]interface{}{"error": err.Error()},
+		))
+	}
+}
@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/comply360/registration-service/internal/fees"
+	"github.com/comply360/registration-service/internal/services"
+	"github.com/comply360/shared/errors"
+	sharedmiddleware "github.com/comply360/shared/middleware"
+	"github.com/comply360/shared/models"
+	"github.com/gin-gonic/gin"
+)
+
+type FeeHandler struct {
+	service *services.FeeService
+}
+
+func NewFeeHandler(service *services.FeeService) *FeeHandler {
+	return &FeeHandler{
+		service: service,
+	}
+}
+
+// SetupRoutes sets up the fee schedule routes. Anyone can read the schedules;
+// managers change them.
+func (h *FeeHandler) SetupRoutes(schedules *gin.RouterGroup) {
+	managers := sharedmiddleware.RequireRole(models.RoleTenantAdmin, models.RoleTenantManager, models.RoleGlobalAdmin)
+
+	schedules.GET("", h.ListSchedules)
+	schedules.POST("", managers, h.CreateSchedule)
+	schedules.GET("/:id", h.GetSchedule)
+	schedules.DELETE("/:id", managers, h.DeleteSchedule)
+}
+
+// SetupRegistrationRoutes sets up the quote routes under /registrations
+func (h *FeeHandler) SetupRegistrationRoutes(r *gin.RouterGroup) {
+	r.GET("/:id/quote", h.GetQuote)
+	r.POST("/:id/quote", h.QuoteRegistration)
+}
+
+// CreateSchedule handles POST /fee-schedules
+func (h *FeeHandler) CreateSchedule(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	var req models.CreateFeeScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIErrorWithDetails(
+			errors.ErrInvalidInput,
+			"Invalid request body",
+			map[string]interface{}{"error": err.Error()},
+		))
+		return
+	}
+
+	schedule, err := h.service.CreateSchedule(schema.(string), tenantID, &req, actor(c).ID())
+	if err != nil {
+		respondFeeError(c, err, "Failed to create fee schedule")
+		return
+	}
+
+	c.JSON(http.StatusCreated, schedule)
+}
+
+// ListSchedules handles GET /fee-schedules
+func (h *FeeHandler) ListSchedules(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	schedules, err := h.service.ListSchedules(schema.(string), tenantID, c.Query("jurisdiction"), c.Query("registration_type"))
+	if err != nil {
+		respondFeeError(c, err, "Failed to list fee schedules")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": schedules})
+}
+
+// GetSchedule handles GET /fee-schedules/:id
+func (h *FeeHandler) GetSchedule(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	scheduleID, ok := parseID(c, "id", "Invalid fee schedule ID")
+	if !ok {
+		return
+	}
+
+	schedule, err := h.service.GetSchedule(schema.(string), tenantID, scheduleID)
+	if err != nil {
+		respondFeeError(c, err, "Failed to get fee schedule")
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+// DeleteSchedule handles DELETE /fee-schedules/:id
+func (h *FeeHandler) DeleteSchedule(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	scheduleID, ok := parseID(c, "id", "Invalid fee schedule ID")
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteSchedule(schema.(string), tenantID, scheduleID); err != nil {
+		respondFeeError(c, err, "Failed to delete fee schedule")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetQuote handles GET /registrations/:id/quote
+func (h *FeeHandler) GetQuote(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	registrationID, ok := parseID(c, "id", "Invalid registration ID")
+	if !ok {
+		return
+	}
+
+	quote, err := h.service.GetQuote(schema.(string), tenantID, registrationID)
+	if err != nil {
+		respondFeeError(c, err, "Failed to get quote")
+		return
+	}
+
+	c.JSON(http.StatusOK, quote)
+}
+
+// QuoteRegistration handles POST /registrations/:id/quote
+func (h *FeeHandler) QuoteRegistration(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	registrationID, ok := parseID(c, "id", "Invalid registration ID")
+	if !ok {
+		return
+	}
+
+	var req models.QuoteRegistrationRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, errors.NewAPIErrorWithDetails(
+				errors.ErrInvalidInput,
+				"Invalid request body",
+				map[string]interface{}{"error": err.Error()},
+			))
+			return
+		}
+	}
+
+	quote, err := h.service.QuoteRegistration(schema.(string), tenantID, registrationID, &req)
+	if err != nil {
+		respondFeeError(c, err, "Failed to quote registration")
+		return
+	}
+
+	c.JSON(http.StatusOK, quote)
+}
+
+func respondFeeError(c *gin.Context, err error, fallback string) {
+	if invalid, ok := err.(*errors.APIError); ok {
+		c.JSON(http.StatusBadRequest, invalid)
+		return
+	}
+	if rule, ok := err.(*fees.RuleError); ok {
+		c.JSON(http.StatusUnprocessableEntity, errors.NewAPIError(errors.ErrInvalidInput, rule.Message))
+		return
+	}
+
+	switch err.Error() {
+	case "registration not found":
+		c.JSON(http.StatusNotFound, errors.NewAPIError(errors.ErrNotFound, "Registration not found"))
+	case "fee schedule not found":
+		c.JSON(http.StatusNotFound, errors.NewAPIError(errors.ErrNotFound, "Fee schedule not found"))
+	case "quote not found":
+		c.JSON(http.StatusNotFound, errors.NewAPIError(errors.ErrNotFound, "Quote not found"))
+	case "a fee schedule already takes effect on that date", "fee schedule has already taken effect",
+		"only draft registrations can be quoted", "registration quote is locked":
+		c.JSON(http.StatusConflict, errors.NewAPIError(errors.ErrConflict, err.Error()))
+	case "unsupported jurisdiction", "invalid effective_from":
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(errors.ErrInvalidInput, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, errors.NewAPIErrorWithDetails(
+			errors.ErrInternalServer,
+			fallback,
+			map[string]interface{}{"error": err.Error()},
+		))
+	}
+}
@@ -0,0 +1,351 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/comply360/registration-service/internal/imports"
+	"github.com/comply360/registration-service/internal/services"
+	"github.com/comply360/shared/errors"
+	sharedmiddleware "github.com/comply360/shared/middleware"
+	"github.com/comply360/shared/models"
+	"github.com/comply360/shared/pagination"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// maxImportSize is the largest import file accepted
+const maxImportSize = 10 << 20
+
+type ImportHandler struct {
+	service *services.ImportService
+}
+
+func NewImportHandler(service *services.ImportService) *ImportHandler {
+	return &ImportHandler{
+		service: service,
+	}
+}
+
+// CreateImport handles POST /registration-imports. The multipart form holds the
+// CSV or XLSX file, either mapping_id or an inline mapping as JSON
+// ({"columns": {...}, "defaults": {...}}), and optionally dry_run.
+func (h *ImportHandler) CreateImport(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	// Leave room for the other form fields
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize+(1<<20))
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(
+			errors.ErrInvalidInput,
+			"A CSV or XLSX file is required in the 'file' field",
+		))
+		return
+	}
+	if fileHeader.Size > maxImportSize {
+		c.JSON(http.StatusRequestEntityTooLarge, errors.NewAPIError(
+			errors.ErrInvalidInput,
+			fmt.Sprintf("File exceeds maximum size of %d MB", maxImportSize>>20),
+		))
+		return
+	}
+
+	req := &services.ImportRequest{FileName: fileHeader.Filename}
+	if value := c.PostForm("dry_run"); value != "" {
+		if req.DryRun, err = strconv.ParseBool(value); err != nil {
+			c.JSON(http.StatusBadRequest, errors.NewAPIError(errors.ErrInvalidInput, "dry_run must be true or false"))
+			return
+		}
+	}
+
+	mappingID, inline := c.PostForm("mapping_id"), c.PostForm("mapping")
+	switch {
+	case mappingID != "" && inline != "":
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(errors.ErrInvalidInput, "Give either mapping_id or mapping, not both"))
+		return
+	case mappingID != "":
+		id, err := uuid.Parse(mappingID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errors.NewAPIError(errors.ErrInvalidInput, "Invalid mapping_id"))
+			return
+		}
+		req.MappingID = &id
+	case inline != "":
+		var mapping struct {
+			Columns  map[string]string `json:"columns"`
+			Defaults map[string]string `json:"defaults"`
+		}
+		if err := json.Unmarshal([]byte(inline), &mapping); err != nil {
+			c.JSON(http.StatusBadRequest, errors.NewAPIErrorWithDetails(
+				errors.ErrInvalidInput,
+				"Invalid mapping",
+				map[string]interface{}{"error": err.Error()},
+			))
+			return
+		}
+		req.Columns, req.Defaults = mapping.Columns, mapping.Defaults
+	default:
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(errors.ErrInvalidInput, "mapping_id or mapping is required"))
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(errors.ErrInvalidInput, "Failed to read uploaded file"))
+		return
+	}
+	defer file.Close()
+
+	var data bytes.Buffer
+	if _, err := io.Copy(&data, file); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(errors.ErrInvalidInput, "Failed to read uploaded file"))
+		return
+	}
+	req.Data = data.Bytes()
+
+	record, err := h.service.StartImport(schema.(string), tenantID, req, actor(c))
+	if err != nil {
+		respondImportError(c, err, "Failed to start import")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"import":  record,
+		"message": "Import started. Poll /registration-imports/" + record.ID.String() + " for progress.",
+	})
+}
+
+// ListImports handles GET /registration-imports
+func (h *ImportHandler) ListImports(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(errors.ErrInvalidInput, err.Error()))
+		return
+	}
+
+	list, err := h.service.ListImports(schema.(string), tenantID, page)
+	if err != nil {
+		if pagination.IsRequestError(err) {
+			c.JSON(http.StatusBadRequest, errors.NewAPIError(errors.ErrInvalidInput, err.Error()))
+			return
+		}
+		respondImportError(c, err, "Failed to list imports")
+		return
+	}
+
+	pagination.Respond(c, list, page)
+}
+
+// GetImport handles GET /registration-imports/:id
+func (h *ImportHandler) GetImport(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	importID, ok := parseID(c, "id", "Invalid import ID")
+	if !ok {
+		return
+	}
+
+	record, err := h.service.GetImport(schema.(string), tenantID, importID)
+	if err != nil {
+		respondImportError(c, err, "Failed to get import")
+		return
+	}
+
+	c.JSON(http.StatusOK, record)
+}
+
+// GetImportErrors handles GET /registration-imports/:id/errors. The row errors
+// are downloaded as CSV, or returned as JSON with format=json.
+func (h *ImportHandler) GetImportErrors(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	importID, ok := parseID(c, "id", "Invalid import ID")
+	if !ok {
+		return
+	}
+
+	problems, err := h.service.ListImportErrors(schema.(string), tenantID, importID)
+	if err != nil {
+		respondImportError(c, err, "Failed to get import errors")
+		return
+	}
+
+	if c.Query("format") == "json" {
+		c.JSON(http.StatusOK, gin.H{
+			"data": problems,
+		})
+		return
+	}
+
+	var report bytes.Buffer
+	if err := imports.WriteReport(&report, problems); err != nil {
+		respondImportError(c, err, "Failed to write import errors")
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "import-"+importID.String()+"-errors.csv"))
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", report.Bytes())
+}
+
+// CreateMapping handles POST /registration-import-mappings
+func (h *ImportHandler) CreateMapping(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	var req models.CreateImportMappingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIErrorWithDetails(
+			errors.ErrInvalidInput,
+			"Invalid request body",
+			map[string]interface{}{"error": err.Error()},
+		))
+		return
+	}
+
+	mapping, err := h.service.CreateMapping(schema.(string), tenantID, &req, actor(c).ID())
+	if err != nil {
+		respondImportError(c, err, "Failed to create import mapping")
+		return
+	}
+
+	c.JSON(http.StatusCreated, mapping)
+}
+
+// ListMappings handles GET /registration-import-mappings
+func (h *ImportHandler) ListMappings(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	mappings, err := h.service.ListMappings(schema.(string), tenantID)
+	if err != nil {
+		respondImportError(c, err, "Failed to list import mappings")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":   mappings,
+		"fields": imports.Fields,
+	})
+}
+
+// GetMapping handles GET /registration-import-mappings/:id
+func (h *ImportHandler) GetMapping(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	mappingID, ok := parseID(c, "id", "Invalid import mapping ID")
+	if !ok {
+		return
+	}
+
+	mapping, err := h.service.GetMapping(schema.(string), tenantID, mappingID)
+	if err != nil {
+		respondImportError(c, err, "Failed to get import mapping")
+		return
+	}
+
+	c.JSON(http.StatusOK, mapping)
+}
+
+// UpdateMapping handles PUT /registration-import-mappings/:id
+func (h *ImportHandler) UpdateMapping(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	mappingID, ok := parseID(c, "id", "Invalid import mapping ID")
+	if !ok {
+		return
+	}
+
+	var req models.UpdateImportMappingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIErrorWithDetails(
+			errors.ErrInvalidInput,
+			"Invalid request body",
+			map[string]interface{}{"error": err.Error()},
+		))
+		return
+	}
+
+	mapping, err := h.service.UpdateMapping(schema.(string), tenantID, mappingID, &req)
+	if err != nil {
+		respondImportError(c, err, "Failed to update import mapping")
+		return
+	}
+
+	c.JSON(http.StatusOK, mapping)
+}
+
+// DeleteMapping handles DELETE /registration-import-mappings/:id
+func (h *ImportHandler) DeleteMapping(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	mappingID, ok := parseID(c, "id", "Invalid import mapping ID")
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteMapping(schema.(string), tenantID, mappingID); err != nil {
+		respondImportError(c, err, "Failed to delete import mapping")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Import mapping deleted successfully",
+	})
+}
+
+// SetupRoutes sets up the import and mapping routes. Imports create clients and
+// registrations in bulk, so they are limited to managers.
+func (h *ImportHandler) SetupRoutes(jobs, mappings *gin.RouterGroup) {
+	managers := sharedmiddleware.RequireRole(models.RoleTenantAdmin, models.RoleTenantManager, models.RoleGlobalAdmin)
+
+	jobs.Use(managers)
+	jobs.POST("", h.CreateImport)
+	jobs.GET("", h.ListImports)
+	jobs.GET("/:id", h.GetImport)
+	jobs.GET("/:id/errors", h.GetImportErrors)
+
+	mappings.Use(managers)
+	mappings.POST("", h.CreateMapping)
+	mappings.GET("", h.ListMappings)
+	mappings.GET("/:id", h.GetMapping)
+	mappings.PUT("/:id", h.UpdateMapping)
+	mappings.DELETE("/:id", h.DeleteMapping)
+}
+
+// respondImportError maps import service errors to HTTP responses
+func respondImportError(c *gin.Context, err error, fallback string) {
+	if invalid, ok := err.(*imports.MappingError); ok {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(errors.ErrInvalidInput, invalid.Error()))
+		return
+	}
+
+	switch err.Error() {
+	case "import not found":
+		c.JSON(http.StatusNotFound, errors.NewAPIError(errors.ErrNotFound, "Import not found"))
+	case "import mapping not found":
+		c.JSON(http.StatusNotFound, errors.NewAPIError(errors.ErrNotFound, "Import mapping not found"))
+	case "import mapping name already exists":
+		c.JSON(http.StatusConflict, errors.NewAPIError(errors.ErrConflict, err.Error()))
+	case "unsupported file type":
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(errors.ErrInvalidInput, "Unsupported file type; upload a .csv or .xlsx file"))
+	default:
+		c.JSON(http.StatusInternalServerError, errors.NewAPIErrorWithDetails(
+			errors.ErrInternalServer,
+			fallback,
+			map[string]interface{}{"error": err.Error()},
+		))
+	}
+}
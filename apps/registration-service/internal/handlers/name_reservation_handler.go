@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/comply360/registration-service/internal/services"
+	"github.com/comply360/shared/errors"
+	sharedmiddleware "github.com/comply360/shared/middleware"
+	"github.com/comply360/shared/models"
+	"github.com/gin-gonic/gin"
+)
+
+type NameReservationHandler struct {
+	service *services.NameReservationService
+}
+
+func NewNameReservationHandler(service *services.NameReservationService) *NameReservationHandler {
+	return &NameReservationHandler{
+		service: service,
+	}
+}
+
+// SetupRoutes sets up the name reservation routes under /registrations
+func (h *NameReservationHandler) SetupRoutes(r *gin.RouterGroup) {
+	r.GET("/:id/name-reservation", h.GetReservation)
+	r.PUT("/:id/name-reservation", h.ProposeNames)
+	r.POST("/:id/name-reservation/submit", h.SubmitReservation)
+	r.POST("/:id/name-reservation/refresh", h.RefreshReservation)
+	r.GET("/:id/name-reservations", h.ListReservations)
+}
+
+// GetReservation handles GET /registrations/:id/name-reservation
+func (h *NameReservationHandler) GetReservation(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	registrationID, ok := parseID(c, "id", "Invalid registration ID")
+	if !ok {
+		return
+	}
+
+	reservation, err := h.service.GetReservation(schema.(string), tenantID, registrationID)
+	if err != nil {
+		respondNameReservationError(c, err, "Failed to get name reservation")
+		return
+	}
+
+	c.JSON(http.StatusOK, reservation)
+}
+
+// ListReservations handles GET /registrations/:id/name-reservations
+func (h *NameReservationHandler) ListReservations(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	registrationID, ok := parseID(c, "id", "Invalid registration ID")
+	if !ok {
+		return
+	}
+
+	reservations, err := h.service.ListReservations(schema.(string), tenantID, registrationID)
+	if err != nil {
+		respondNameReservationError(c, err, "Failed to list name reservations")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": reservations,
+	})
+}
+
+// ProposeNames handles PUT /registrations/:id/name-reservation. The response
+// carries the screening issues found for each name.
+func (h *NameReservationHandler) ProposeNames(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	registrationID, ok := parseID(c, "id", "Invalid registration ID")
+	if !ok {
+		return
+	}
+
+	var req models.ProposeNamesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIErrorWithDetails(
+			errors.ErrInvalidInput,
+			"Invalid request body",
+			map[string]interface{}{"error": err.Error()},
+		))
+		return
+	}
+
+	reservation, err := h.service.ProposeNames(schema.(string), tenantID, registrationID, &req, actor(c).ID())
+	if err != nil {
+		respondNameReservationError(c, err, "Failed to propose names")
+		return
+	}
+
+	c.JSON(http.StatusOK, reservation)
+}
+
+// SubmitReservation handles POST /registrations/:id/name-reservation/submit
+func (h *NameReservationHandler) SubmitReservation(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	registrationID, ok := parseID(c, "id", "Invalid registration ID")
+	if !ok {
+		return
+	}
+
+	reservation, err := h.service.SubmitReservation(schema.(string), tenantID, registrationID)
+	if err != nil {
+		respondNameReservationError(c, err, "Failed to submit name reservation")
+		return
+	}
+
+	c.JSON(http.StatusOK, reservation)
+}
+
+// RefreshReservation handles POST /registrations/:id/name-reservation/refresh
+func (h *NameReservationHandler) RefreshReservation(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	registrationID, ok := parseID(c, "id", "Invalid registration ID")
+	if !ok {
+		return
+	}
+
+	reservation, err := h.service.RefreshReservation(schema.(string), tenantID, registrationID)
+	if err != nil {
+		respondNameReservationError(c, err, "Failed to refresh name reservation")
+		return
+	}
+
+	c.JSON(http.StatusOK, reservation)
+}
+
+// respondNameReservationError maps name reservation service errors to HTTP responses
+func respondNameReservationError(c *gin.Context, err error, fallback string) {
+	if invalid, ok := err.(*errors.APIError); ok {
+		c.JSON(http.StatusBadRequest, invalid)
+		return
+	}
+	if screening, ok := err.(*services.NameScreeningError); ok {
+		c.JSON(http.StatusUnprocessableEntity, errors.NewAPIErrorWithDetails(
+			errors.ErrInvalidInput,
+			"Proposed names failed screening; change the names with errors",
+			map[string]interface{}{"names": screening.Reservation.Names},
+		))
+		return
+	}
+	if registry, ok := err.(*services.RegistryError); ok {
+		c.JSON(http.StatusBadGateway, errors.NewAPIErrorWithDetails(
+			errors.ErrInternalServer,
+			registry.Registry+" could not be reached",
+			map[string]interface{}{"error": registry.Error()},
+		))
+		return
+	}
+
+	switch err.Error() {
+	case "registration not found":
+		c.JSON(http.StatusNotFound, errors.NewAPIError(errors.ErrNotFound, "Registration not found"))
+	case "name reservation not found":
+		c.JSON(http.StatusNotFound, errors.NewAPIError(errors.ErrNotFound, "Name reservation not found"))
+	case "name reservation has already been submitted", "name reservation is not a draft",
+		"name reservation is not awaiting the registry", "registration already has an open name reservation",
+		"registration name can no longer be changed":
+		c.JSON(http.StatusConflict, errors.NewAPIError(errors.ErrConflict, err.Error()))
+	case "registration does not need a name reservation":
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(errors.ErrInvalidInput, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, errors.NewAPIErrorWithDetails(
+			errors.ErrInternalServer,
+			fallback,
+			map[string]interface{}{"error": err.Error()},
+		))
+	}
+}
@@ -0,0 +1,433 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/comply360/registration-service/internal/repository"
+	"github.com/comply360/registration-service/internal/services"
+	"github.com/comply360/shared/errors"
+	sharedmiddleware "github.com/comply360/shared/middleware"
+	"github.com/comply360/shared/models"
+	"github.com/comply360/shared/pagination"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type QueueHandler struct {
+	service *services.QueueService
+}
+
+func NewQueueHandler(service *services.QueueService) *QueueHandler {
+	return &QueueHandler{
+		service: service,
+	}
+}
+
+// CreateQueue handles POST /review-queues
+func (h *QueueHandler) CreateQueue(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	var req models.CreateReviewQueueRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIErrorWithDetails(
+			errors.ErrInvalidInput,
+			"Invalid request body",
+			map[string]interface{}{"error": err.Error()},
+		))
+		return
+	}
+
+	queue, err := h.service.CreateQueue(schema.(string), tenantID, &req)
+	if err != nil {
+		respondQueueError(c, err, "Failed to create review queue")
+		return
+	}
+
+	c.JSON(http.StatusCreated, queue)
+}
+
+// ListQueues handles GET /review-queues
+func (h *QueueHandler) ListQueues(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	queues, err := h.service.ListQueues(schema.(string), tenantID)
+	if err != nil {
+		respondQueueError(c, err, "Failed to list review queues")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": queues,
+	})
+}
+
+// GetQueue handles GET /review-queues/:id
+func (h *QueueHandler) GetQueue(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	queueID, ok := parseID(c, "id", "Invalid review queue ID")
+	if !ok {
+		return
+	}
+
+	queue, err := h.service.GetQueue(schema.(string), tenantID, queueID)
+	if err != nil {
+		respondQueueError(c, err, "Failed to get review queue")
+		return
+	}
+
+	c.JSON(http.StatusOK, queue)
+}
+
+// UpdateQueue handles PUT /review-queues/:id
+func (h *QueueHandler) UpdateQueue(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	queueID, ok := parseID(c, "id", "Invalid review queue ID")
+	if !ok {
+		return
+	}
+
+	var req models.UpdateReviewQueueRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIErrorWithDetails(
+			errors.ErrInvalidInput,
+			"Invalid request body",
+			map[string]interface{}{"error": err.Error()},
+		))
+		return
+	}
+
+	queue, err := h.service.UpdateQueue(schema.(string), tenantID, queueID, &req)
+	if err != nil {
+		respondQueueError(c, err, "Failed to update review queue")
+		return
+	}
+
+	c.JSON(http.StatusOK, queue)
+}
+
+// DeleteQueue handles DELETE /review-queues/:id
+func (h *QueueHandler) DeleteQueue(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	queueID, ok := parseID(c, "id", "Invalid review queue ID")
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteQueue(schema.(string), tenantID, queueID); err != nil {
+		respondQueueError(c, err, "Failed to delete review queue")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// SetMember handles PUT /review-queues/:id/members/:user_id
+func (h *QueueHandler) SetMember(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	queueID, ok := parseID(c, "id", "Invalid review queue ID")
+	if !ok {
+		return
+	}
+	userID, ok := parseID(c, "user_id", "Invalid user ID")
+	if !ok {
+		return
+	}
+
+	var req models.SetQueueMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIErrorWithDetails(
+			errors.ErrInvalidInput,
+			"Invalid request body",
+			map[string]interface{}{"error": err.Error()},
+		))
+		return
+	}
+
+	member, err := h.service.SetMember(schema.(string), tenantID, queueID, userID, &req)
+	if err != nil {
+		respondQueueError(c, err, "Failed to set review queue member")
+		return
+	}
+
+	c.JSON(http.StatusOK, member)
+}
+
+// RemoveMember handles DELETE /review-queues/:id/members/:user_id
+func (h *QueueHandler) RemoveMember(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	queueID, ok := parseID(c, "id", "Invalid review queue ID")
+	if !ok {
+		return
+	}
+	userID, ok := parseID(c, "user_id", "Invalid user ID")
+	if !ok {
+		return
+	}
+
+	if err := h.service.RemoveMember(schema.(string), tenantID, queueID, userID); err != nil {
+		respondQueueError(c, err, "Failed to remove review queue member")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListSLATargets handles GET /sla-targets
+func (h *QueueHandler) ListSLATargets(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	targets, err := h.service.ListSLATargets(schema.(string), tenantID)
+	if err != nil {
+		respondQueueError(c, err, "Failed to list SLA targets")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": targets,
+	})
+}
+
+// SetSLATarget handles PUT /sla-targets
+func (h *QueueHandler) SetSLATarget(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	var req models.SetSLATargetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIErrorWithDetails(
+			errors.ErrInvalidInput,
+			"Invalid request body",
+			map[string]interface{}{"error": err.Error()},
+		))
+		return
+	}
+
+	target, err := h.service.SetSLATarget(schema.(string), tenantID, &req)
+	if err != nil {
+		respondQueueError(c, err, "Failed to set SLA target")
+		return
+	}
+
+	c.JSON(http.StatusOK, target)
+}
+
+// DeleteSLATarget handles DELETE /sla-targets/:id
+func (h *QueueHandler) DeleteSLATarget(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	targetID, ok := parseID(c, "id", "Invalid SLA target ID")
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteSLATarget(schema.(string), tenantID, targetID); err != nil {
+		respondQueueError(c, err, "Failed to delete SLA target")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Assign handles POST /registrations/:id/assign
+func (h *QueueHandler) Assign(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	registrationID, ok := parseID(c, "id", "Invalid registration ID")
+	if !ok {
+		return
+	}
+
+	var req models.AssignRegistrationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIErrorWithDetails(
+			errors.ErrInvalidInput,
+			"Invalid request body",
+			map[string]interface{}{"error": err.Error()},
+		))
+		return
+	}
+
+	assignment, err := h.service.Reassign(schema.(string), tenantID, registrationID, &req, actor(c))
+	if err != nil {
+		respondQueueError(c, err, "Failed to assign registration")
+		return
+	}
+
+	c.JSON(http.StatusOK, assignment)
+}
+
+// ListAssignments handles GET /registrations/:id/assignments
+func (h *QueueHandler) ListAssignments(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	registrationID, ok := parseID(c, "id", "Invalid registration ID")
+	if !ok {
+		return
+	}
+
+	assignments, err := h.service.ListAssignments(schema.(string), tenantID, registrationID)
+	if err != nil {
+		respondQueueError(c, err, "Failed to list assignments")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": assignments,
+	})
+}
+
+// MyQueue handles GET /registrations/my-queue: the open registrations assigned
+// to the authenticated user, soonest due first
+func (h *QueueHandler) MyQueue(c *gin.Context) {
+	userID, err := sharedmiddleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, errors.NewAPIError(
+			errors.ErrUnauthorized,
+			"User context not found",
+		))
+		return
+	}
+
+	filter, ok := queueFilter(c)
+	if !ok {
+		return
+	}
+	filter.AssignedTo = &userID
+
+	h.listRegistrations(c, filter)
+}
+
+// Overdue handles GET /registrations/overdue: open registrations past their SLA
+// due time, optionally for one assignee
+func (h *QueueHandler) Overdue(c *gin.Context) {
+	filter, ok := queueFilter(c)
+	if !ok {
+		return
+	}
+	if v := c.Query("assigned_to"); v != "" {
+		assignee, err := uuid.Parse(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errors.NewAPIError(errors.ErrInvalidInput, "Invalid assigned_to"))
+			return
+		}
+		filter.AssignedTo = &assignee
+	}
+	filter.OverdueOnly = true
+
+	h.listRegistrations(c, filter)
+}
+
+func (h *QueueHandler) listRegistrations(c *gin.Context, filter repository.QueueFilter) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(errors.ErrInvalidInput, err.Error()))
+		return
+	}
+
+	registrations, err := h.service.ListRegistrations(schema.(string), tenantID, filter, page)
+	if err != nil {
+		respondQueueError(c, err, "Failed to list registrations")
+		return
+	}
+
+	pagination.Respond(c, registrations, page)
+}
+
+// SetupRoutes sets up the review queue and SLA target routes. Only tenant
+// managers configure them.
+func (h *QueueHandler) SetupRoutes(queues, targets *gin.RouterGroup) {
+	managers := sharedmiddleware.RequireRole(models.RoleTenantAdmin, models.RoleTenantManager, models.RoleGlobalAdmin)
+
+	queues.Use(managers)
+	queues.POST("", h.CreateQueue)
+	queues.GET("", h.ListQueues)
+	queues.GET("/:id", h.GetQueue)
+	queues.PUT("/:id", h.UpdateQueue)
+	queues.DELETE("/:id", h.DeleteQueue)
+	queues.PUT("/:id/members/:user_id", h.SetMember)
+	queues.DELETE("/:id/members/:user_id", h.RemoveMember)
+
+	targets.Use(managers)
+	targets.GET("", h.ListSLATargets)
+	targets.PUT("", h.SetSLATarget)
+	targets.DELETE("/:id", h.DeleteSLATarget)
+}
+
+// SetupRegistrationRoutes sets up the assignment routes under /registrations
+func (h *QueueHandler) SetupRegistrationRoutes(r *gin.RouterGroup) {
+	r.GET("/my-queue", h.MyQueue)
+	r.GET("/overdue", h.Overdue)
+	r.GET("/:id/assignments", h.ListAssignments)
+	r.POST("/:id/assign", sharedmiddleware.RequireRole(models.RoleTenantAdmin, models.RoleTenantManager, models.RoleGlobalAdmin), h.Assign)
+}
+
+// queueFilter reads the status, registration_type and queue_id filters
+func queueFilter(c *gin.Context) (repository.QueueFilter, bool) {
+	filter := repository.QueueFilter{
+		Status:           c.Query("status"),
+		RegistrationType: c.Query("registration_type"),
+	}
+	if v := c.Query("queue_id"); v != "" {
+		queueID, err := uuid.Parse(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errors.NewAPIError(errors.ErrInvalidInput, "Invalid queue_id"))
+			return filter, false
+		}
+		filter.QueueID = &queueID
+	}
+	return filter, true
+}
+
+// parseID parses a UUID path parameter, responding with message when invalid
+func parseID(c *gin.Context, param, message string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param(param))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(errors.ErrInvalidInput, message))
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// respondQueueError maps queue service errors to HTTP responses
+func respondQueueError(c *gin.Context, err error, fallback string) {
+	switch err.Error() {
+	case "registration not found":
+		c.JSON(http.StatusNotFound, errors.NewAPIError(errors.ErrNotFound, "Registration not found"))
+	case "review queue not found":
+		c.JSON(http.StatusNotFound, errors.NewAPIError(errors.ErrNotFound, "Review queue not found"))
+	case "review queue member not found":
+		c.JSON(http.StatusNotFound, errors.NewAPIError(errors.ErrNotFound, "Review queue member not found"))
+	case "SLA target not found":
+		c.JSON(http.StatusNotFound, errors.NewAPIError(errors.ErrNotFound, "SLA target not found"))
+	case "review queue name already exists", "registration is already assigned":
+		c.JSON(http.StatusConflict, errors.NewAPIError(errors.ErrConflict, err.Error()))
+	case "user not found", "registration is closed", "no review queue for registration type",
+		"assignee is not a member of the review queue":
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(errors.ErrInvalidInput, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, errors.NewAPIErrorWithDetails(
+			errors.ErrInternalServer,
+			fallback,
+			map[string]interface{}{"error": err.Error()},
+		))
+	}
+}
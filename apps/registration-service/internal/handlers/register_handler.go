@@ -0,0 +1,297 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/comply360/registration-service/internal/register"
+	"github.com/comply360/registration-service/internal/services"
+	"github.com/comply360/shared/errors"
+	sharedmiddleware "github.com/comply360/shared/middleware"
+	"github.com/comply360/shared/models"
+	"github.com/gin-gonic/gin"
+)
+
+type RegisterHandler struct {
+	service *services.RegisterService
+}
+
+func NewRegisterHandler(service *services.RegisterService) *RegisterHandler {
+	return &RegisterHandler{
+		service: service,
+	}
+}
+
+// SetupRoutes sets up the company register routes under /registrations
+func (h *RegisterHandler) SetupRoutes(r *gin.RouterGroup) {
+	managers := sharedmiddleware.RequireRole(models.RoleTenantAdmin, models.RoleTenantManager, models.RoleGlobalAdmin)
+
+	r.GET("/:id/register", h.GetRegister)
+	r.GET("/:id/register/beneficial-ownership.csv", h.ExportBeneficialOwnership)
+	r.POST("/:id/register/entries", h.CreateEntry)
+	r.GET("/:id/register/entries/:entry_id", h.GetEntry)
+	r.PUT("/:id/register/entries/:entry_id", h.UpdateEntry)
+	r.GET("/:id/register/entries/:entry_id/history", h.EntryHistory)
+	r.POST("/:id/register/entries/:entry_id/resign", h.ResignEntry)
+	r.DELETE("/:id/register/entries/:entry_id", managers, h.RemoveEntry)
+}
+
+// GetRegister handles GET /registrations/:id/register
+func (h *RegisterHandler) GetRegister(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	registrationID, ok := parseID(c, "id", "Invalid registration ID")
+	if !ok {
+		return
+	}
+	asAt, ok := asAtDate(c)
+	if !ok {
+		return
+	}
+
+	companyRegister, err := h.service.GetRegister(schema.(string), tenantID, registrationID, asAt)
+	if err != nil {
+		respondRegisterError(c, err, "Failed to get company register")
+		return
+	}
+
+	c.JSON(http.StatusOK, companyRegister)
+}
+
+// ExportBeneficialOwnership handles GET /registrations/:id/register/beneficial-ownership.csv
+func (h *RegisterHandler) ExportBeneficialOwnership(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	registrationID, ok := parseID(c, "id", "Invalid registration ID")
+	if !ok {
+		return
+	}
+	asAt, ok := asAtDate(c)
+	if !ok {
+		return
+	}
+
+	data, filename, err := h.service.BeneficialOwnershipExport(schema.(string), tenantID, registrationID, asAt)
+	if err != nil {
+		respondRegisterError(c, err, "Failed to export beneficial ownership")
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", data)
+}
+
+// CreateEntry handles POST /registrations/:id/register/entries
+func (h *RegisterHandler) CreateEntry(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	registrationID, ok := parseID(c, "id", "Invalid registration ID")
+	if !ok {
+		return
+	}
+
+	var req models.CreateRegisterEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIErrorWithDetails(
+			errors.ErrInvalidInput,
+			"Invalid request body",
+			map[string]interface{}{"error": err.Error()},
+		))
+		return
+	}
+
+	entry, err := h.service.CreateEntry(schema.(string), tenantID, registrationID, &req, actor(c).ID())
+	if err != nil {
+		respondRegisterError(c, err, "Failed to add register entry")
+		return
+	}
+
+	c.JSON(http.StatusCreated, entry)
+}
+
+// GetEntry handles GET /registrations/:id/register/entries/:entry_id
+func (h *RegisterHandler) GetEntry(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	registrationID, ok := parseID(c, "id", "Invalid registration ID")
+	if !ok {
+		return
+	}
+	entryID, ok := parseID(c, "entry_id", "Invalid register entry ID")
+	if !ok {
+		return
+	}
+	asAt, ok := asAtDate(c)
+	if !ok {
+		return
+	}
+
+	entry, err := h.service.GetEntry(schema.(string), tenantID, registrationID, entryID, asAt)
+	if err != nil {
+		respondRegisterError(c, err, "Failed to get register entry")
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+// EntryHistory handles GET /registrations/:id/register/entries/:entry_id/history
+func (h *RegisterHandler) EntryHistory(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	registrationID, ok := parseID(c, "id", "Invalid registration ID")
+	if !ok {
+		return
+	}
+	entryID, ok := parseID(c, "entry_id", "Invalid register entry ID")
+	if !ok {
+		return
+	}
+
+	history, err := h.service.EntryHistory(schema.(string), tenantID, registrationID, entryID)
+	if err != nil {
+		respondRegisterError(c, err, "Failed to get register entry history")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": history,
+	})
+}
+
+// UpdateEntry handles PUT /registrations/:id/register/entries/:entry_id
+func (h *RegisterHandler) UpdateEntry(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	registrationID, ok := parseID(c, "id", "Invalid registration ID")
+	if !ok {
+		return
+	}
+	entryID, ok := parseID(c, "entry_id", "Invalid register entry ID")
+	if !ok {
+		return
+	}
+
+	var req models.UpdateRegisterEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIErrorWithDetails(
+			errors.ErrInvalidInput,
+			"Invalid request body",
+			map[string]interface{}{"error": err.Error()},
+		))
+		return
+	}
+
+	entry, err := h.service.UpdateEntry(schema.(string), tenantID, registrationID, entryID, &req, actor(c).ID())
+	if err != nil {
+		respondRegisterError(c, err, "Failed to update register entry")
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+// ResignEntry handles POST /registrations/:id/register/entries/:entry_id/resign
+func (h *RegisterHandler) ResignEntry(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	registrationID, ok := parseID(c, "id", "Invalid registration ID")
+	if !ok {
+		return
+	}
+	entryID, ok := parseID(c, "entry_id", "Invalid register entry ID")
+	if !ok {
+		return
+	}
+
+	var req models.ResignRegisterEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIErrorWithDetails(
+			errors.ErrInvalidInput,
+			"Invalid request body",
+			map[string]interface{}{"error": err.Error()},
+		))
+		return
+	}
+
+	entry, err := h.service.ResignEntry(schema.(string), tenantID, registrationID, entryID, &req, actor(c).ID())
+	if err != nil {
+		respondRegisterError(c, err, "Failed to record resignation")
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+// RemoveEntry handles DELETE /registrations/:id/register/entries/:entry_id
+func (h *RegisterHandler) RemoveEntry(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	registrationID, ok := parseID(c, "id", "Invalid registration ID")
+	if !ok {
+		return
+	}
+	entryID, ok := parseID(c, "entry_id", "Invalid register entry ID")
+	if !ok {
+		return
+	}
+
+	if err := h.service.RemoveEntry(schema.(string), tenantID, registrationID, entryID, actor(c).ID()); err != nil {
+		respondRegisterError(c, err, "Failed to remove register entry")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// asAtDate reads the optional as_at date a register is read as at
+func asAtDate(c *gin.Context) (*time.Time, bool) {
+	v := c.Query("as_at")
+	if v == "" {
+		return nil, true
+	}
+	date, err := register.ParseDate(v)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(errors.ErrInvalidInput, "Invalid as_at; use YYYY-MM-DD"))
+		return nil, false
+	}
+	return &date, true
+}
+
+func respondRegisterError(c *gin.Context, err error, fallback string) {
+	if invalid, ok := err.(*errors.APIError); ok {
+		c.JSON(http.StatusBadRequest, invalid)
+		return
+	}
+	if rule, ok := err.(*register.RuleError); ok {
+		c.JSON(http.StatusUnprocessableEntity, errors.NewAPIError(errors.ErrInvalidInput, rule.Message))
+		return
+	}
+
+	switch err.Error() {
+	case "registration not found":
+		c.JSON(http.StatusNotFound, errors.NewAPIError(errors.ErrNotFound, "Registration not found"))
+	case "register entry not found":
+		c.JSON(http.StatusNotFound, errors.NewAPIError(errors.ErrNotFound, "Register entry not found"))
+	case "register entry has already resigned", "register entry was changed concurrently",
+		"cannot change the register of a cancelled registration":
+		c.JSON(http.StatusConflict, errors.NewAPIError(errors.ErrConflict, err.Error()))
+	case "invalid appointed_on", "invalid effective_from", "invalid resigned_on", "invalid date_of_birth":
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(errors.ErrInvalidInput, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, errors.NewAPIErrorWithDetails(
+			errors.ErrInternalServer,
+			fallback,
+			map[string]interface{}{"error": err.Error()},
+		))
+	}
+}
@@ -4,10 +4,13 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/comply360/registration-service/internal/forms"
 	"github.com/comply360/registration-service/internal/services"
+	"github.com/comply360/registration-service/internal/workflow"
 	"github.com/comply360/shared/errors"
 	sharedmiddleware "github.com/comply360/shared/middleware"
 	"github.com/comply360/shared/models"
+	"github.com/comply360/shared/pagination"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
@@ -58,20 +61,9 @@ func (h *RegistrationHandler) CreateRegistration(c *gin.Context) {
 	}
 	registration.TenantID = tenantID
 
-	// Set assigned_to from user ID if not provided
-	if registration.AssignedTo == nil {
-		userIDUUID, _ := sharedmiddleware.GetUserID(c)
-		userIDStr := userIDUUID.String()
-		registration.AssignedTo = &userIDStr
-	}
-
 	// Create registration
-	if err := h.service.CreateRegistration(schema.(string), &registration); err != nil {
-		c.JSON(http.StatusInternalServerError, errors.NewAPIErrorWithDetails(
-			errors.ErrInternalServer,
-			"Failed to create registration",
-			map[string]interface{}{"error": err.Error()},
-		))
+	if err := h.service.CreateRegistration(schema.(string), &registration, actor(c)); err != nil {
+		respondRegistrationError(c, err, "Failed to create registration")
 		return
 	}
 
@@ -114,13 +106,20 @@ func (h *RegistrationHandler) ListRegistrations(c *gin.Context) {
 	tenantID, _ := sharedmiddleware.GetTenantID(c)
 
 	// Parse query parameters
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(errors.ErrInvalidInput, err.Error()))
+		return
+	}
 	status := c.Query("status")
 
 	// Get registrations
-	registrations, total, err := h.service.ListRegistrations(schema.(string), tenantID, offset, limit, status)
+	registrations, err := h.service.ListRegistrations(schema.(string), tenantID, status, page)
 	if err != nil {
+		if pagination.IsRequestError(err) {
+			c.JSON(http.StatusBadRequest, errors.NewAPIError(errors.ErrInvalidInput, err.Error()))
+			return
+		}
 		c.JSON(http.StatusInternalServerError, errors.NewAPIErrorWithDetails(
 			errors.ErrInternalServer,
 			"Failed to list registrations",
@@ -129,12 +128,7 @@ func (h *RegistrationHandler) ListRegistrations(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data":   registrations,
-		"total":  total,
-		"offset": offset,
-		"limit":  limit,
-	})
+	pagination.Respond(c, registrations, page)
 }
 
 // UpdateRegistration handles PUT /registrations/:id
@@ -169,12 +163,8 @@ func (h *RegistrationHandler) UpdateRegistration(c *gin.Context) {
 	registration.TenantID = tenantID
 
 	// Update registration
-	if err := h.service.UpdateRegistration(schema.(string), &registration); err != nil {
-		c.JSON(http.StatusInternalServerError, errors.NewAPIErrorWithDetails(
-			errors.ErrInternalServer,
-			"Failed to update registration",
-			map[string]interface{}{"error": err.Error()},
-		))
+	if err := h.service.UpdateRegistration(schema.(string), &registration, actor(c)); err != nil {
+		respondRegistrationError(c, err, "Failed to update registration")
 		return
 	}
 
@@ -212,11 +202,251 @@ func (h *RegistrationHandler) DeleteRegistration(c *gin.Context) {
 	})
 }
 
+// Transition returns a handler for a workflow action such as POST
+// /registrations/:id/submit, which moves the registration to status
+func (h *RegistrationHandler) Transition(status string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get tenant context
+		schema, _ := c.Get("tenant_schema")
+		tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+		// Parse registration ID
+		registrationID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errors.NewAPIError(
+				errors.ErrInvalidInput,
+				"Invalid registration ID",
+			))
+			return
+		}
+
+		// The body is optional; it only carries a reason
+		var req models.TransitionRegistrationRequest
+		if c.Request.ContentLength > 0 {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, errors.NewAPIErrorWithDetails(
+					errors.ErrInvalidInput,
+					"Invalid request body",
+					map[string]interface{}{"error": err.Error()},
+				))
+				return
+			}
+		}
+
+		registration, err := h.service.TransitionRegistration(schema.(string), tenantID, registrationID, status, req.Reason, actor(c))
+		if err != nil {
+			respondRegistrationError(c, err, "Failed to update registration status")
+			return
+		}
+
+		c.JSON(http.StatusOK, registration)
+	}
+}
+
+// RecordInvoicePayment handles POST /registrations/:id/invoice-paid
+func (h *RegistrationHandler) RecordInvoicePayment(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	registrationID, ok := parseID(c, "id", "Invalid registration ID")
+	if !ok {
+		return
+	}
+
+	// The body is optional; it only carries when the invoice was paid
+	var req models.RecordInvoicePaymentRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, errors.NewAPIErrorWithDetails(
+				errors.ErrInvalidInput,
+				"Invalid request body",
+				map[string]interface{}{"error": err.Error()},
+			))
+			return
+		}
+	}
+
+	registration, err := h.service.RecordInvoicePayment(schema.(string), tenantID, registrationID, req.PaidAt)
+	if err != nil {
+		respondRegistrationError(c, err, "Failed to record invoice payment")
+		return
+	}
+
+	c.JSON(http.StatusOK, registration)
+}
+
+// GetHistory handles GET /registrations/:id/history
+func (h *RegistrationHandler) GetHistory(c *gin.Context) {
+	// Get tenant context
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	// Parse registration ID
+	registrationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(
+			errors.ErrInvalidInput,
+			"Invalid registration ID",
+		))
+		return
+	}
+
+	history, err := h.service.GetHistory(schema.(string), tenantID, registrationID)
+	if err != nil {
+		respondRegistrationError(c, err, "Failed to get registration history")
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+// GetChecklist handles GET /registrations/:id/checklist
+func (h *RegistrationHandler) GetChecklist(c *gin.Context) {
+	// Get tenant context
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	// Parse registration ID
+	registrationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(
+			errors.ErrInvalidInput,
+			"Invalid registration ID",
+		))
+		return
+	}
+
+	checklist, err := h.service.GetChecklist(schema.(string), tenantID, registrationID)
+	if err != nil {
+		respondRegistrationError(c, err, "Failed to get registration checklist")
+		return
+	}
+
+	c.JSON(http.StatusOK, checklist)
+}
+
+// GetWorkflow handles GET /registrations/workflows/:type
+func (h *RegistrationHandler) GetWorkflow(c *gin.Context) {
+	definition, err := h.service.GetWorkflow(c.Param("type"))
+	if err != nil {
+		respondRegistrationError(c, err, "Failed to get workflow")
+		return
+	}
+
+	c.JSON(http.StatusOK, definition)
+}
+
+// ListFormSchemas handles GET /registrations/form-schemas
+func (h *RegistrationHandler) ListFormSchemas(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"data": h.service.ListFormSchemas(),
+	})
+}
+
+// GetFormSchema handles GET /registrations/form-schemas/:jurisdiction/:type. The
+// latest version is returned unless ?version= is given.
+func (h *RegistrationHandler) GetFormSchema(c *gin.Context) {
+	version := 0
+	if v := c.Query("version"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 {
+			c.JSON(http.StatusBadRequest, errors.NewAPIError(
+				errors.ErrInvalidInput,
+				"Invalid form schema version",
+			))
+			return
+		}
+		version = parsed
+	}
+
+	form, err := h.service.GetFormSchema(c.Param("jurisdiction"), c.Param("type"), version)
+	if err != nil {
+		respondRegistrationError(c, err, "Failed to get form schema")
+		return
+	}
+
+	c.Header("X-Form-Schema-Version", strconv.Itoa(form.Version))
+	c.Data(http.StatusOK, "application/json", form.Raw)
+}
+
 // SetupRoutes sets up the registration routes
 func (h *RegistrationHandler) SetupRoutes(r *gin.RouterGroup) {
 	r.POST("", h.CreateRegistration)
 	r.GET("", h.ListRegistrations)
+	r.GET("/workflows/:type", h.GetWorkflow)
+	r.GET("/form-schemas", h.ListFormSchemas)
+	r.GET("/form-schemas/:jurisdiction/:type", h.GetFormSchema)
 	r.GET("/:id", h.GetRegistration)
 	r.PUT("/:id", h.UpdateRegistration)
 	r.DELETE("/:id", h.DeleteRegistration)
+	r.GET("/:id/history", h.GetHistory)
+	r.GET("/:id/checklist", h.GetChecklist)
+
+	// Workflow actions
+	r.POST("/:id/submit", h.Transition(models.RegistrationStatusSubmitted))
+	r.POST("/:id/review", h.Transition(models.RegistrationStatusInReview))
+	r.POST("/:id/approve", h.Transition(models.RegistrationStatusApproved))
+	r.POST("/:id/reject", h.Transition(models.RegistrationStatusRejected))
+	r.POST("/:id/complete", h.Transition(models.RegistrationStatusCompleted))
+	r.POST("/:id/cancel", h.Transition(models.RegistrationStatusCancelled))
+
+	// Billing records the invoice payment the completion guard checks
+	r.POST("/:id/invoice-paid", sharedmiddleware.RequireRole(models.RoleTenantAdmin, models.RoleTenantManager, models.RoleGlobalAdmin), h.RecordInvoicePayment)
+}
+
+// actor identifies the authenticated user for workflow checks
+func actor(c *gin.Context) workflow.Actor {
+	userID, _ := sharedmiddleware.GetUserID(c)
+	roles, _ := sharedmiddleware.GetUserRoles(c)
+
+	a := workflow.Actor{UserID: userID, Roles: roles}
+	for _, role := range roles {
+		if role == models.RoleGlobalAdmin {
+			a.IsGlobalAdmin = true
+		}
+	}
+	return a
+}
+
+// respondRegistrationError maps registration service errors to HTTP responses
+func respondRegistrationError(c *gin.Context, err error, fallback string) {
+	if refused, ok := err.(*workflow.TransitionError); ok {
+		switch refused.Reason {
+		case workflow.ReasonForbidden:
+			c.JSON(http.StatusForbidden, errors.NewAPIError(errors.ErrInsufficientPermissions, refused.Error()))
+		case workflow.ReasonGuards:
+			c.JSON(http.StatusConflict, errors.NewAPIErrorWithDetails(
+				errors.ErrConflict,
+				refused.Error(),
+				map[string]interface{}{"unmet": refused.Unmet},
+			))
+		default:
+			c.JSON(http.StatusConflict, errors.NewAPIError(errors.ErrConflict, refused.Error()))
+		}
+		return
+	}
+	if invalid, ok := err.(*forms.ValidationError); ok {
+		c.JSON(http.StatusBadRequest, errors.ValidationFailed("Invalid form data", invalid.Fields))
+		return
+	}
+
+	switch err.Error() {
+	case "registration not found":
+		c.JSON(http.StatusNotFound, errors.NewAPIError(errors.ErrNotFound, "Registration not found"))
+	case "form schema not found":
+		c.JSON(http.StatusNotFound, errors.NewAPIError(errors.ErrNotFound, "Form schema not found"))
+	case "registration has not been invoiced":
+		c.JSON(http.StatusConflict, errors.NewAPIError(errors.ErrConflict, err.Error()))
+	case "tenant_id is required", "client_id is required", "registration_type is required",
+		"company_name is required", "jurisdiction is required", "unsupported jurisdiction", "registration_type cannot be changed",
+		"unsupported registration_type", "unsupported registration_type for jurisdiction", "new registrations start as " + models.RegistrationStatusDraft,
+		"paid_at cannot be in the future":
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(errors.ErrInvalidInput, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, errors.NewAPIErrorWithDetails(
+			errors.ErrInternalServer,
+			fallback,
+			map[string]interface{}{"error": err.Error()},
+		))
+	}
 }
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/comply360/registration-service/internal/repository"
+	"github.com/comply360/registration-service/internal/search"
+	"github.com/comply360/registration-service/internal/services"
+	"github.com/comply360/shared/errors"
+	sharedmiddleware "github.com/comply360/shared/middleware"
+	"github.com/comply360/shared/pagination"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type SearchHandler struct {
+	service *services.SearchService
+}
+
+func NewSearchHandler(service *services.SearchService) *SearchHandler {
+	return &SearchHandler{
+		service: service,
+	}
+}
+
+// SearchRegistrations handles GET /registrations/search. q matches company
+// names, registration numbers, CIPC/DCIP references and client ID numbers.
+// status, registration_type, jurisdiction and assigned_to take comma-separated
+// values; created_from/created_to and submitted_from/submitted_to take dates.
+func (h *SearchHandler) SearchRegistrations(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	req := &repository.RegistrationSearch{
+		Query:            c.Query("q"),
+		Status:           queryList(c, "status"),
+		RegistrationType: queryList(c, "registration_type"),
+		Jurisdiction:     queryList(c, "jurisdiction"),
+		AssignedTo:       queryList(c, "assigned_to"),
+	}
+	for _, assignee := range req.AssignedTo {
+		if _, err := uuid.Parse(assignee); err != nil && assignee != repository.Unassigned {
+			c.JSON(http.StatusBadRequest, errors.NewAPIError(errors.ErrInvalidInput, "Invalid assigned_to"))
+			return
+		}
+	}
+	if v := c.Query("client_id"); v != "" {
+		clientID, err := uuid.Parse(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errors.NewAPIError(errors.ErrInvalidInput, "Invalid client_id"))
+			return
+		}
+		req.ClientID = &clientID
+	}
+
+	var err error
+	if req.Created, err = search.ParseRange(c.Query("created_from"), c.Query("created_to")); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(errors.ErrInvalidInput, "Invalid created_from/created_to: "+err.Error()))
+		return
+	}
+	if req.Submitted, err = search.ParseRange(c.Query("submitted_from"), c.Query("submitted_to")); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(errors.ErrInvalidInput, "Invalid submitted_from/submitted_to: "+err.Error()))
+		return
+	}
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(errors.ErrInvalidInput, err.Error()))
+		return
+	}
+
+	results, facets, err := h.service.SearchRegistrations(schema.(string), tenantID, req, page)
+	if err != nil {
+		respondSearchError(c, err, "Failed to search registrations")
+		return
+	}
+
+	pagination.RespondWith(c, results, page, gin.H{"facets": facets})
+}
+
+// SearchClients handles GET /clients/search. q matches client names, email
+// addresses, phone numbers and identity and tax numbers. status, client_type
+// and country take comma-separated values; created_from/created_to take dates.
+func (h *SearchHandler) SearchClients(c *gin.Context) {
+	schema, _ := c.Get("tenant_schema")
+	tenantID, _ := sharedmiddleware.GetTenantID(c)
+
+	req := &repository.ClientSearch{
+		Query:      c.Query("q"),
+		Status:     queryList(c, "status"),
+		ClientType: queryList(c, "client_type"),
+		Country:    queryList(c, "country"),
+	}
+
+	var err error
+	if req.Created, err = search.ParseRange(c.Query("created_from"), c.Query("created_to")); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(errors.ErrInvalidInput, "Invalid created_from/created_to: "+err.Error()))
+		return
+	}
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(errors.ErrInvalidInput, err.Error()))
+		return
+	}
+
+	results, facets, err := h.service.SearchClients(schema.(string), tenantID, req, page)
+	if err != nil {
+		respondSearchError(c, err, "Failed to search clients")
+		return
+	}
+
+	pagination.RespondWith(c, results, page, gin.H{"facets": facets})
+}
+
+// queryList reads a filter given as repeated or comma-separated values
+func queryList(c *gin.Context, key string) []string {
+	values := []string{}
+	for _, param := range c.QueryArray(key) {
+		for _, value := range strings.Split(param, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
+// respondSearchError maps search service errors to HTTP responses
+func respondSearchError(c *gin.Context, err error, fallback string) {
+	if pagination.IsRequestError(err) {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(errors.ErrInvalidInput, err.Error()))
+		return
+	}
+	c.JSON(http.StatusInternalServerError, errors.NewAPIErrorWithDetails(
+		errors.ErrInternalServer,
+		fallback,
+		map[string]interface{}{"error": err.Error()},
+	))
+}
@@ -0,0 +1,292 @@
+// Package imports turns spreadsheet rows into client and registration requests
+// through a column mapping, validates them and writes row-level error reports.
+package imports
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/comply360/shared/errors"
+	"github.com/comply360/shared/models"
+	"github.com/comply360/shared/validator"
+)
+
+const (
+	// FieldClientID names an existing client instead of creating one
+	FieldClientID = "registration.client_id"
+	// FieldClientEmail is the email of the client a row creates
+	FieldClientEmail = "client.email"
+	// FormDataPrefix starts fields mapped into registration form_data, such as
+	// "registration.form_data.registered_address.city"
+	FormDataPrefix = "registration.form_data."
+)
+
+// Fields are the fields a column can be mapped to, besides form_data fields
+var Fields = []string{
+	"client.client_type",
+	"client.full_name",
+	"client.company_name",
+	"client.id_number",
+	"client.tax_number",
+	"client.vat_number",
+	FieldClientEmail,
+	"client.phone",
+	"client.mobile",
+	"client.country_code",
+	FieldClientID,
+	"registration.registration_type",
+	"registration.company_name",
+	"registration.jurisdiction",
+}
+
+// MappingError is a column mapping that cannot be applied
+type MappingError struct {
+	Message string
+}
+
+func (e *MappingError) Error() string {
+	return e.Message
+}
+
+// Mapping is a checked column mapping
+type Mapping struct {
+	columns  map[string]string
+	defaults map[string]string
+}
+
+// NewMapping checks that a mapping only names known fields, maps each field
+// from one column and can tell which client every row belongs to
+func NewMapping(columns, defaults map[string]string) (*Mapping, error) {
+	if len(columns) == 0 {
+		return nil, &MappingError{"mapping has no columns"}
+	}
+
+	mapped := map[string]string{}
+	for column, field := range columns {
+		field = strings.TrimSpace(field)
+		if !known(field) {
+			return nil, &MappingError{"unknown import field: " + field}
+		}
+		if other, ok := mapped[field]; ok {
+			return nil, &MappingError{fmt.Sprintf("import field %s is mapped from both %q and %q", field, other, column)}
+		}
+		mapped[field] = column
+	}
+	for field := range defaults {
+		if !known(field) {
+			return nil, &MappingError{"unknown import field: " + field}
+		}
+	}
+
+	has := func(field string) bool {
+		_, isMapped := mapped[field]
+		return isMapped || strings.TrimSpace(defaults[field]) != ""
+	}
+	if !has(FieldClientID) && !has(FieldClientEmail) {
+		return nil, &MappingError{fmt.Sprintf("mapping must include %s or %s", FieldClientID, FieldClientEmail)}
+	}
+
+	return &Mapping{columns: columns, defaults: defaults}, nil
+}
+
+func known(field string) bool {
+	if strings.HasPrefix(field, FormDataPrefix) {
+		return len(field) > len(FormDataPrefix) && !strings.HasSuffix(field, ".") && !strings.Contains(field, "..")
+	}
+	for _, name := range Fields {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Row is a spreadsheet row mapped to the requests it imports
+type Row struct {
+	Number int
+	// Client is the client the row creates; nil when the row names an existing client
+	Client       *models.CreateClientRequest
+	Registration *models.CreateRegistrationRequest
+	// FormData holds form_data values as text, keyed by path within form_data
+	FormData map[string]string
+
+	values  map[string]string
+	columns map[string]string
+}
+
+// Rows maps every data row of a sheet. Column headers are matched ignoring case
+// and surrounding spaces.
+func (m *Mapping) Rows(sheet *Sheet) ([]*Row, error) {
+	index := map[string]int{}
+	for i, name := range sheet.Header {
+		key := strings.ToLower(strings.TrimSpace(name))
+		if _, ok := index[key]; !ok {
+			index[key] = i
+		}
+	}
+
+	// Visit columns in a fixed order so errors do not depend on map order
+	columns := make([]string, 0, len(m.columns))
+	for column := range m.columns {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	positions := map[string]int{}
+	for _, column := range columns {
+		i, ok := index[strings.ToLower(strings.TrimSpace(column))]
+		if !ok {
+			return nil, &MappingError{"column not found: " + column}
+		}
+		positions[column] = i
+	}
+
+	rows := make([]*Row, 0, len(sheet.Rows))
+	for _, record := range sheet.Rows {
+		row := &Row{
+			Number:  record.Number,
+			values:  map[string]string{},
+			columns: map[string]string{},
+		}
+		for field, value := range m.defaults {
+			row.values[strings.TrimSpace(field)] = strings.TrimSpace(value)
+		}
+		for _, column := range columns {
+			field := strings.TrimSpace(m.columns[column])
+			row.columns[field] = column
+			if value := strings.TrimSpace(record.Cells[positions[column]]); value != "" {
+				row.values[field] = value
+			}
+		}
+		row.build()
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// build fills the row's requests from its values
+func (r *Row) build() {
+	v := r.values
+	optional := func(field string) *string {
+		if value, ok := v[field]; ok && value != "" {
+			return &value
+		}
+		return nil
+	}
+
+	r.Registration = &models.CreateRegistrationRequest{
+		ClientID:         v[FieldClientID],
+		RegistrationType: strings.ToLower(v["registration.registration_type"]),
+		CompanyName:      v["registration.company_name"],
+		Jurisdiction:     strings.ToUpper(v["registration.jurisdiction"]),
+	}
+
+	r.FormData = map[string]string{}
+	for field, value := range v {
+		if strings.HasPrefix(field, FormDataPrefix) {
+			r.FormData[strings.TrimPrefix(field, FormDataPrefix)] = value
+		}
+	}
+
+	if r.Registration.ClientID != "" {
+		return
+	}
+
+	// Without a client type, a row naming only a company creates a company client
+	clientType := strings.ToLower(v["client.client_type"])
+	if clientType == "" {
+		clientType = models.ClientTypeIndividual
+		if optional("client.company_name") != nil && optional("client.full_name") == nil {
+			clientType = models.ClientTypeCompany
+		}
+	}
+	r.Client = &models.CreateClientRequest{
+		ClientType:  clientType,
+		FullName:    optional("client.full_name"),
+		CompanyName: optional("client.company_name"),
+		IDNumber:    optional("client.id_number"),
+		TaxNumber:   optional("client.tax_number"),
+		VATNumber:   optional("client.vat_number"),
+		Email:       strings.ToLower(v[FieldClientEmail]),
+		Phone:       optional("client.phone"),
+		Mobile:      optional("client.mobile"),
+		CountryCode: optional("client.country_code"),
+	}
+	if r.Client.CountryCode != nil {
+		code := strings.ToUpper(*r.Client.CountryCode)
+		r.Client.CountryCode = &code
+	}
+}
+
+// Validate checks the row's requests with the shared validators
+func (r *Row) Validate(v *validator.Validator) []models.RegistrationImportError {
+	var problems []models.RegistrationImportError
+
+	if r.Client != nil {
+		for _, field := range fieldErrors(v.Validate(r.Client)) {
+			problems = append(problems, r.Problem("client."+field.Field, field.Message))
+		}
+	}
+	for _, field := range fieldErrors(v.Validate(r.Registration)) {
+		// The client a row creates has no ID until it is saved
+		if field.Field == "client_id" && r.Client != nil {
+			continue
+		}
+		problems = append(problems, r.Problem("registration."+field.Field, field.Message))
+	}
+
+	return problems
+}
+
+// Problem describes a problem with a field of the row, naming the column the
+// field was read from. An empty field describes the whole row.
+func (r *Row) Problem(field, message string) models.RegistrationImportError {
+	return models.RegistrationImportError{
+		Row:     r.Number,
+		Column:  r.columns[field],
+		Field:   field,
+		Value:   r.values[field],
+		Message: message,
+	}
+}
+
+// fieldErrors returns the field errors of a failed validation
+func fieldErrors(err *errors.APIError) []validator.ValidationError {
+	if err == nil {
+		return nil
+	}
+	if fields, ok := err.Details.([]validator.ValidationError); ok {
+		return fields
+	}
+	return []validator.ValidationError{{Message: err.Message}}
+}
+
+// WriteReport writes row errors as CSV, one line per error
+func WriteReport(w io.Writer, problems []models.RegistrationImportError) error {
+	out := csv.NewWriter(w)
+	if err := out.Write([]string{"row", "column", "field", "value", "message"}); err != nil {
+		return err
+	}
+	for _, problem := range problems {
+		record := []string{strconv.Itoa(problem.Row), cell(problem.Column), problem.Field, cell(problem.Value), cell(problem.Message)}
+		if err := out.Write(record); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// cell keeps spreadsheet programs from reading a value taken from an uploaded
+// file as a formula
+func cell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
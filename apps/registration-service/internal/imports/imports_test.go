@@ -0,0 +1,133 @@
+package imports
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/comply360/shared/models"
+	testhelpers "github.com/comply360/shared/testing"
+	"github.com/comply360/shared/validator"
+)
+
+var testColumns = map[string]string{
+	"Client Email": "client.email",
+	"Client Name":  "client.full_name",
+	"ID Number":    "client.id_number",
+	"Company":      "registration.company_name",
+	"Type":         "registration.registration_type",
+	"Year End":     "registration.form_data.financial_year_end",
+	"Existing":     "registration.client_id",
+}
+
+func TestRead_CSV(t *testing.T) {
+	data := "\xef\xbb\xbfClient Email;Company\n\nthandi@example.com;Nkosi Holdings\n;\nsipho@example.com;\"Dlamini; Sons\"\n"
+
+	sheet, err := Read(models.ImportFormatCSV, []byte(data))
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "Client Email", sheet.Header[0], "The byte order mark is dropped")
+	testhelpers.AssertEqual(t, 2, len(sheet.Rows), "Blank rows are skipped")
+	testhelpers.AssertEqual(t, 3, sheet.Rows[0].Number)
+	testhelpers.AssertEqual(t, 5, sheet.Rows[1].Number, "Row numbers count skipped rows")
+	testhelpers.AssertEqual(t, "Dlamini; Sons", sheet.Rows[1].Cells[1])
+}
+
+func TestRead_XLSX(t *testing.T) {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	files := map[string]string{
+		"xl/workbook.xml":            `<workbook xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Clients" sheetId="1" r:id="rId1"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships><Relationship Id="rId1" Target="worksheets/clients.xml"/></Relationships>`,
+		"xl/sharedStrings.xml":       `<sst><si><t>Client Email</t></si><si><t>Company</t></si><si><r><t>Nkosi </t></r><r><t>Holdings</t></r></si></sst>`,
+		"xl/worksheets/clients.xml": `<worksheet><sheetData>
+			<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c><c r="C1" t="inlineStr"><is><t>Year End</t></is></c></row>
+			<row r="2"><c r="A2" t="inlineStr"><is><t>thandi@example.com</t></is></c><c r="C2"><v>2</v></c></row>
+			<row r="3"><c r="B3" t="s"><v>2</v></c></row>
+		</sheetData></worksheet>`,
+	}
+	for name, content := range files {
+		w, err := archive.Create(name)
+		testhelpers.AssertNoError(t, err)
+		w.Write([]byte(content))
+	}
+	testhelpers.AssertNoError(t, archive.Close())
+
+	sheet, err := Read(models.ImportFormatXLSX, buf.Bytes())
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 3, len(sheet.Header))
+	testhelpers.AssertEqual(t, "Year End", sheet.Header[2])
+	testhelpers.AssertEqual(t, 2, len(sheet.Rows))
+	testhelpers.AssertEqual(t, "", sheet.Rows[0].Cells[1], "Missing cells are empty")
+	testhelpers.AssertEqual(t, "2", sheet.Rows[0].Cells[2])
+	testhelpers.AssertEqual(t, "Nkosi Holdings", sheet.Rows[1].Cells[1], "Rich text runs are joined")
+}
+
+func TestNewMapping_ChecksFields(t *testing.T) {
+	_, err := NewMapping(map[string]string{"Email": "client.mail"}, nil)
+	testhelpers.AssertError(t, err, "Unknown fields are rejected")
+
+	_, err = NewMapping(map[string]string{"Email": "client.email", "Mail": "client.email"}, nil)
+	testhelpers.AssertError(t, err, "A field comes from one column")
+
+	_, err = NewMapping(map[string]string{"Company": "registration.company_name"}, nil)
+	testhelpers.AssertError(t, err, "Rows must name or create a client")
+
+	_, err = NewMapping(map[string]string{"Company": "registration.company_name"}, map[string]string{"registration.client_id": "0b8e7c0e-4f4e-4a43-9d0c-7c1f0c3f3a11"})
+	testhelpers.AssertNoError(t, err, "A default client may be given")
+}
+
+func TestMapping_RowsAndValidate(t *testing.T) {
+	mapping, err := NewMapping(testColumns, map[string]string{"registration.jurisdiction": "za"})
+	testhelpers.AssertNoError(t, err)
+
+	sheet := &Sheet{
+		Header: []string{"client email", "Client Name", "ID Number", "Company", "Type", "Year End", "Existing"},
+		Rows: []SheetRow{
+			{Number: 2, Cells: []string{"Thandi@Example.com", "Thandi Nkosi", "8001015009087", "Nkosi Holdings", "pty_ltd", "2", ""}},
+			{Number: 3, Cells: []string{"not-an-email", "Sipho Dlamini", "", "", "pty_ltd", "", ""}},
+			{Number: 4, Cells: []string{"", "", "", "Dlamini Traders", "business_name", "", "0b8e7c0e-4f4e-4a43-9d0c-7c1f0c3f3a11"}},
+		},
+	}
+
+	rows, err := mapping.Rows(sheet)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 3, len(rows))
+
+	v := validator.New()
+
+	first := rows[0]
+	testhelpers.AssertEqual(t, 0, len(first.Validate(v)), "A complete row is valid without a client ID")
+	testhelpers.AssertEqual(t, "thandi@example.com", first.Client.Email)
+	testhelpers.AssertEqual(t, "ZA", first.Registration.Jurisdiction, "Defaults fill unmapped fields")
+	testhelpers.AssertEqual(t, "2", first.FormData["financial_year_end"])
+
+	problems := rows[1].Validate(v)
+	fields := map[string]models.RegistrationImportError{}
+	for _, problem := range problems {
+		fields[problem.Field] = problem
+	}
+	testhelpers.AssertEqual(t, "Client Email", fields["client.email"].Column)
+	testhelpers.AssertEqual(t, "not-an-email", fields["client.email"].Value)
+	testhelpers.AssertEqual(t, 3, fields["registration.company_name"].Row)
+
+	third := rows[2]
+	testhelpers.AssertTrue(t, third.Client == nil, "A row naming a client does not create one")
+	testhelpers.AssertEqual(t, 0, len(third.Validate(v)))
+
+	_, err = mapping.Rows(&Sheet{Header: []string{"Client Email"}})
+	testhelpers.AssertError(t, err, "Mapped columns must be in the file")
+}
+
+func TestWriteReport_EscapesFormulas(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteReport(&buf, []models.RegistrationImportError{
+		{Row: 2, Column: "Company", Field: "registration.company_name", Value: "=HYPERLINK(\"x\")", Message: "company_name is required"},
+	})
+	testhelpers.AssertNoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	testhelpers.AssertEqual(t, 2, len(lines))
+	testhelpers.AssertEqual(t, "row,column,field,value,message", lines[0])
+	testhelpers.AssertTrue(t, strings.Contains(lines[1], `'=HYPERLINK`), "Values are not read as formulas")
+}
@@ -0,0 +1,315 @@
+package imports
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/comply360/shared/models"
+)
+
+// MaxRows is the largest number of data rows one import may hold
+const MaxRows = 5000
+
+// maxColumns is the number of columns in an Excel worksheet
+const maxColumns = 16384
+
+// Sheet is a spreadsheet's header row and data rows. Rows are padded to the
+// width of the header.
+type Sheet struct {
+	Header []string
+	Rows   []SheetRow
+}
+
+// SheetRow is a data row and its spreadsheet row number
+type SheetRow struct {
+	Number int
+	Cells  []string
+}
+
+// DetectFormat returns the import format of a file name
+func DetectFormat(fileName string) (string, error) {
+	switch strings.ToLower(path.Ext(fileName)) {
+	case ".csv":
+		return models.ImportFormatCSV, nil
+	case ".xlsx":
+		return models.ImportFormatXLSX, nil
+	default:
+		return "", fmt.Errorf("unsupported file type")
+	}
+}
+
+// Read reads a CSV or XLSX file. The first row is the header; blank rows are
+// skipped. Only the first worksheet of a workbook is read.
+func Read(format string, data []byte) (*Sheet, error) {
+	var records []record
+	var err error
+	switch format {
+	case models.ImportFormatCSV:
+		records, err = readCSV(data)
+	case models.ImportFormatXLSX:
+		records, err = readXLSX(data)
+	default:
+		return nil, fmt.Errorf("unsupported file type")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sheet := &Sheet{}
+	for _, record := range records {
+		if blank(record.cells) {
+			continue
+		}
+		if sheet.Header == nil {
+			sheet.Header = make([]string, len(record.cells))
+			for i, name := range record.cells {
+				sheet.Header[i] = strings.TrimSpace(name)
+			}
+			continue
+		}
+
+		cells := make([]string, len(sheet.Header))
+		copy(cells, record.cells)
+		sheet.Rows = append(sheet.Rows, SheetRow{Number: record.number, Cells: cells})
+		if len(sheet.Rows) > MaxRows {
+			return nil, fmt.Errorf("file has more than %d rows", MaxRows)
+		}
+	}
+	if sheet.Header == nil {
+		return nil, fmt.Errorf("file is empty")
+	}
+
+	return sheet, nil
+}
+
+// record is a row as read from a file, with its row number
+type record struct {
+	number int
+	cells  []string
+}
+
+func blank(record []string) bool {
+	for _, cell := range record {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// readCSV reads comma or semicolon separated values, skipping a UTF-8 byte order mark
+func readCSV(data []byte) ([]record, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	if firstLine, _, _ := bytes.Cut(data, []byte("\n")); bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		reader.Comma = ';'
+	}
+
+	var records []record
+	for {
+		cells, err := reader.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV file: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+		records = append(records, record{number: line, cells: cells})
+	}
+}
+
+// xlsxWorkbook is the sheet list of xl/workbook.xml
+type xlsxWorkbook struct {
+	Sheets []struct {
+		RelID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+// xlsxRelationships is xl/_rels/workbook.xml.rels
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+// xlsxText is a shared or inline string, either plain or split into rich text runs
+type xlsxText struct {
+	Text string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxText) String() string {
+	if len(t.Runs) == 0 {
+		return t.Text
+	}
+	var b strings.Builder
+	for _, run := range t.Runs {
+		b.WriteString(run.Text)
+	}
+	return b.String()
+}
+
+// xlsxWorksheet is the cell data of a worksheet
+type xlsxWorksheet struct {
+	Rows []struct {
+		Number int `xml:"r,attr"`
+		Cells  []struct {
+			Ref    string   `xml:"r,attr"`
+			Type   string   `xml:"t,attr"`
+			Value  string   `xml:"v"`
+			Inline xlsxText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// readXLSX reads the first worksheet of a workbook. Cells are read as stored:
+// dates formatted in the spreadsheet arrive as serial numbers, so date columns
+// should be formatted as text.
+func readXLSX(data []byte) ([]record, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid XLSX file: %w", err)
+	}
+	files := map[string]*zip.File{}
+	for _, file := range archive.File {
+		files[file.Name] = file
+	}
+
+	sheetPath, err := firstSheetPath(files)
+	if err != nil {
+		return nil, err
+	}
+
+	var shared []string
+	if file, ok := files["xl/sharedStrings.xml"]; ok {
+		var table struct {
+			Items []xlsxText `xml:"si"`
+		}
+		if err := decodeXML(file, &table); err != nil {
+			return nil, err
+		}
+		for _, item := range table.Items {
+			shared = append(shared, item.String())
+		}
+	}
+
+	file, ok := files[sheetPath]
+	if !ok {
+		return nil, fmt.Errorf("invalid XLSX file: worksheet %s is missing", sheetPath)
+	}
+	var sheet xlsxWorksheet
+	if err := decodeXML(file, &sheet); err != nil {
+		return nil, err
+	}
+
+	records := make([]record, 0, len(sheet.Rows))
+	for i, row := range sheet.Rows {
+		number := row.Number
+		if number <= 0 {
+			number = i + 1
+		}
+		var cells []string
+		for j, cell := range row.Cells {
+			column := columnIndex(cell.Ref)
+			if column < 0 {
+				column = j
+			}
+			if column >= maxColumns {
+				return nil, fmt.Errorf("invalid XLSX file: bad cell reference %s", cell.Ref)
+			}
+			for len(cells) <= column {
+				cells = append(cells, "")
+			}
+
+			switch cell.Type {
+			case "s":
+				var index int
+				if _, err := fmt.Sscan(cell.Value, &index); err != nil || index < 0 || index >= len(shared) {
+					return nil, fmt.Errorf("invalid XLSX file: bad shared string in %s", cell.Ref)
+				}
+				cells[column] = shared[index]
+			case "inlineStr":
+				cells[column] = cell.Inline.String()
+			case "b":
+				cells[column] = map[string]string{"1": "true", "0": "false"}[cell.Value]
+			default:
+				cells[column] = cell.Value
+			}
+		}
+		records = append(records, record{number: number, cells: cells})
+	}
+
+	return records, nil
+}
+
+// firstSheetPath finds the worksheet listed first in the workbook
+func firstSheetPath(files map[string]*zip.File) (string, error) {
+	const fallback = "xl/worksheets/sheet1.xml"
+
+	workbookFile, ok := files["xl/workbook.xml"]
+	relsFile, hasRels := files["xl/_rels/workbook.xml.rels"]
+	if !ok || !hasRels {
+		return fallback, nil
+	}
+
+	var workbook xlsxWorkbook
+	if err := decodeXML(workbookFile, &workbook); err != nil {
+		return "", err
+	}
+	var rels xlsxRelationships
+	if err := decodeXML(relsFile, &rels); err != nil {
+		return "", err
+	}
+	if len(workbook.Sheets) == 0 {
+		return "", fmt.Errorf("invalid XLSX file: workbook has no sheets")
+	}
+
+	for _, rel := range rels.Relationships {
+		if rel.ID != workbook.Sheets[0].RelID {
+			continue
+		}
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/"), nil
+		}
+		return path.Join("xl", rel.Target), nil
+	}
+	return fallback, nil
+}
+
+func decodeXML(file *zip.File, v interface{}) error {
+	rc, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("invalid XLSX file: %w", err)
+	}
+	defer rc.Close()
+
+	if err := xml.NewDecoder(io.LimitReader(rc, 64<<20)).Decode(v); err != nil {
+		return fmt.Errorf("invalid XLSX file: %s: %w", file.Name, err)
+	}
+	return nil
+}
+
+// columnIndex returns the zero-based column of a cell reference such as "AB12",
+// or -1 when the reference has no column
+func columnIndex(ref string) int {
+	index := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		index = index*26 + int(r-'A') + 1
+	}
+	return index - 1
+}
@@ -0,0 +1,123 @@
+// Package integration calls the integration service, which speaks to external
+// registries such as CIPC and DCIP on the registration service's behalf.
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Name reservation and proposed name statuses, as the integration service
+// reports them for every registry
+const (
+	NameStatusPending  = "pending"
+	NameStatusApproved = "approved"
+	NameStatusRejected = "rejected"
+)
+
+// NameReservation is a name reservation lodged with a registry
+type NameReservation struct {
+	Reference    string         `json:"reference"`
+	Status       string         `json:"status"`
+	Names        []ProposedName `json:"names"`
+	ReservedName string         `json:"reserved_name,omitempty"`
+	DecidedAt    *time.Time     `json:"decided_at,omitempty"`
+	ExpiresAt    *time.Time     `json:"expires_at,omitempty"`
+}
+
+// ProposedName is the registry's decision on one proposed name
+type ProposedName struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// Client calls the integration service's HTTP API
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient creates a client for the integration service at baseURL
+func NewClient(baseURL string) *Client {
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// ReserveNames lodges proposed names, in order of preference, with the registry
+// of a jurisdiction. customerReference identifies the reservation to the
+// registry.
+func (c *Client) ReserveNames(jurisdiction string, names []string, entityType, customerReference string) (*NameReservation, error) {
+	body := map[string]interface{}{
+		"names":              names,
+		"entity_type":        entityType,
+		"customer_reference": customerReference,
+	}
+
+	var reservation NameReservation
+	if err := c.do(http.MethodPost, nameReservationsPath(jurisdiction), body, &reservation); err != nil {
+		return nil, fmt.Errorf("failed to reserve names with the %s registry: %w", strings.ToUpper(jurisdiction), err)
+	}
+	return &reservation, nil
+}
+
+// GetNameReservation retrieves a name reservation and the decision of the
+// jurisdiction's registry on it
+func (c *Client) GetNameReservation(jurisdiction, reference string) (*NameReservation, error) {
+	var reservation NameReservation
+	if err := c.do(http.MethodGet, nameReservationsPath(jurisdiction)+"/"+url.PathEscape(reference), nil, &reservation); err != nil {
+		return nil, fmt.Errorf("failed to get name reservation from the %s registry: %w", strings.ToUpper(jurisdiction), err)
+	}
+	return &reservation, nil
+}
+
+func nameReservationsPath(jurisdiction string) string {
+	return "/api/v1/integration/registries/" + url.PathEscape(strings.ToLower(jurisdiction)) + "/name-reservations"
+}
+
+func (c *Client) do(method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request body: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, c.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("integration service returned status %d: %s", resp.StatusCode, string(data))
+	}
+
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
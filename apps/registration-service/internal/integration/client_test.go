@@ -0,0 +1,73 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	testhelpers "github.com/comply360/shared/testing"
+)
+
+// fakeRegistry stands in for the integration service's name reservation routes
+// of the CIPC registry, holding one reservation that CIPC approves on the
+// second look
+func fakeRegistry(t *testing.T) (*httptest.Server, *map[string]interface{}) {
+	var lodged map[string]interface{}
+	looks := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/integration/registries/za/name-reservations", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&lodged)
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(NameReservation{Reference: "NR1", Status: NameStatusPending})
+	})
+	mux.HandleFunc("/api/v1/integration/registries/za/name-reservations/NR1", func(w http.ResponseWriter, r *http.Request) {
+		looks++
+		reservation := NameReservation{Reference: "NR1", Status: NameStatusPending}
+		if looks > 1 {
+			reservation.Status = NameStatusApproved
+			reservation.ReservedName = "Umhlanga Ventures"
+			reservation.Names = []ProposedName{{Name: "Umhlanga Ventures", Status: NameStatusApproved}}
+		}
+		json.NewEncoder(w).Encode(reservation)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, &lodged
+}
+
+func TestNameReservation(t *testing.T) {
+	server, lodged := fakeRegistry(t)
+	client := NewClient(server.URL + "/")
+
+	reservation, err := client.ReserveNames("ZA", []string{"Umhlanga Ventures"}, "pty_ltd", "R1")
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "NR1", reservation.Reference)
+	testhelpers.AssertEqual(t, "R1", (*lodged)["customer_reference"])
+	testhelpers.AssertEqual(t, "pty_ltd", (*lodged)["entity_type"])
+
+	reservation, err = client.GetNameReservation("ZA", "NR1")
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, NameStatusPending, reservation.Status)
+
+	reservation, err = client.GetNameReservation("ZA", "NR1")
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, NameStatusApproved, reservation.Status)
+	testhelpers.AssertEqual(t, "Umhlanga Ventures", reservation.ReservedName)
+
+	_, err = client.GetNameReservation("ZA", "missing")
+	testhelpers.AssertError(t, err, "Errors from the integration service are returned")
+}
+
+func TestNameReservation_OtherRegistry(t *testing.T) {
+	server, _ := fakeRegistry(t)
+	client := NewClient(server.URL)
+
+	_, err := client.ReserveNames("ZW", []string{"Mbare Ventures"}, "pty_ltd", "R2")
+	testhelpers.AssertError(t, err, "Names are lodged with the registry of the jurisdiction")
+}
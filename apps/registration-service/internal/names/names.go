@@ -0,0 +1,261 @@
+// Package names holds the rules for company name reservations: which
+// registrations need one, how proposed names are screened before they go to
+// the registry (CIPC or DCIP), and how the registry's decision is recorded.
+package names
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/comply360/registration-service/internal/clients"
+	"github.com/comply360/registration-service/internal/integration"
+	"github.com/comply360/shared/jurisdictions"
+	"github.com/comply360/shared/models"
+	"github.com/google/uuid"
+)
+
+// SimilarityThreshold is how similar a proposed name must be to an existing
+// registration's name to be flagged
+const SimilarityThreshold = 0.8
+
+// reservationMonths is how long a registry holds an approved name when it does
+// not say
+const reservationMonths = 6
+
+// bannedWords are words a company name may not use without the consent of a
+// regulator, mapped to why. They are matched as whole words of the name's key.
+var bannedWords = map[string]string{
+	"bank":           "requires the consent of the Prudential Authority",
+	"banking":        "requires the consent of the Prudential Authority",
+	"reserve bank":   "suggests an association with the South African Reserve Bank",
+	"insurance":      "requires the consent of the Prudential Authority",
+	"assurance":      "requires the consent of the Prudential Authority",
+	"reinsurance":    "requires the consent of the Prudential Authority",
+	"government":     "suggests an association with the state",
+	"republic":       "suggests an association with the state",
+	"national":       "suggests an association with the state",
+	"parliament":     "suggests an association with the state",
+	"presidency":     "suggests an association with the state",
+	"presidential":   "suggests an association with the state",
+	"ministry":       "suggests an association with the state",
+	"municipal":      "suggests an association with the state",
+	"municipality":   "suggests an association with the state",
+	"police":         "suggests an association with the state",
+	"defence":        "suggests an association with the state",
+	"cipc":           "suggests an association with a state body",
+	"sars":           "suggests an association with a state body",
+	"university":     "requires registration under the Higher Education Act",
+	"red cross":      "is a protected emblem",
+	"olympic":        "is a protected name",
+	"stock exchange": "requires a licence under the Financial Markets Act",
+}
+
+// Existing is the company name of another registration of the tenant
+type Existing struct {
+	RegistrationID uuid.UUID
+	Name           string
+}
+
+// Required reports whether a registration needs its company name reserved with
+// its jurisdiction's registry before it can be approved
+func Required(registration *models.Registration) bool {
+	j, ok := jurisdictions.Get(registration.Jurisdiction)
+	return ok && j.RequiresNameReservation(registration.RegistrationType)
+}
+
+// Screen checks proposed names, in order of preference, against the banned
+// words, each other and the names of the tenant's other registrations
+func Screen(proposed []string, existing []Existing) []models.ProposedName {
+	screened := make([]models.ProposedName, len(proposed))
+	seen := map[string]int{}
+	for i, name := range proposed {
+		name = strings.Join(strings.Fields(name), " ")
+		key := clients.CompanyKey(name)
+		issues := bannedIssues(key)
+
+		if first, ok := seen[key]; ok {
+			issues = append(issues, models.NameIssue{
+				Code:     models.NameIssueRepeated,
+				Severity: models.NameIssueSeverityError,
+				Message:  fmt.Sprintf("name is the same as proposed name %d", first+1),
+			})
+		} else {
+			seen[key] = i
+		}
+
+		issues = append(issues, existingIssues(key, existing)...)
+		screened[i] = models.ProposedName{Name: name, Status: models.ProposedNameStatusProposed, Issues: issues}
+	}
+	return screened
+}
+
+func bannedIssues(key string) []models.NameIssue {
+	padded := " " + key + " "
+	words := []string{}
+	for word := range bannedWords {
+		if strings.Contains(padded, " "+word+" ") {
+			words = append(words, word)
+		}
+	}
+	sort.Strings(words)
+
+	issues := []models.NameIssue{}
+	for _, word := range words {
+		issues = append(issues, models.NameIssue{
+			Code:     models.NameIssueBannedWord,
+			Severity: models.NameIssueSeverityError,
+			Message:  fmt.Sprintf("%q %s", word, bannedWords[word]),
+			Word:     word,
+		})
+	}
+	return issues
+}
+
+// existingIssues flags an existing name identical to the proposed one, or else
+// the most similar existing name above the threshold
+func existingIssues(key string, existing []Existing) []models.NameIssue {
+	var best *Existing
+	bestScore := 0.0
+	for i := range existing {
+		other := clients.CompanyKey(existing[i].Name)
+		if other == "" {
+			continue
+		}
+		if other == key {
+			id := existing[i].RegistrationID
+			return []models.NameIssue{{
+				Code:           models.NameIssueIdentical,
+				Severity:       models.NameIssueSeverityError,
+				Message:        fmt.Sprintf("name is the same as registration %q", existing[i].Name),
+				RegistrationID: &id,
+				SimilarName:    existing[i].Name,
+				Similarity:     1,
+			}}
+		}
+		if score := Similarity(key, other); score >= SimilarityThreshold && score > bestScore {
+			best, bestScore = &existing[i], score
+		}
+	}
+
+	if best == nil {
+		return nil
+	}
+	id := best.RegistrationID
+	return []models.NameIssue{{
+		Code:           models.NameIssueSimilar,
+		Severity:       models.NameIssueSeverityWarn,
+		Message:        fmt.Sprintf("name is similar to registration %q", best.Name),
+		RegistrationID: &id,
+		SimilarName:    best.Name,
+		Similarity:     float64(int(bestScore*100)) / 100,
+	}}
+}
+
+// Similarity scores how alike two names are from 0 to 1, by the edit distance
+// between their keys
+func Similarity(a, b string) float64 {
+	a, b = clients.CompanyKey(a), clients.CompanyKey(b)
+	ra, rb := []rune(a), []rune(b)
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+	if longest == 0 {
+		return 0
+	}
+	return 1 - float64(distance(ra, rb))/float64(longest)
+}
+
+// distance is the Levenshtein distance between two strings
+func distance(a, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+// Blocked reports whether screening found an error that stops the names being
+// submitted
+func Blocked(proposed []models.ProposedName) bool {
+	for _, name := range proposed {
+		for _, issue := range name.Issues {
+			if issue.Severity == models.NameIssueSeverityError {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Apply records the registry's decision on a submitted reservation, and reports
+// whether it has decided. Without an expiry from the registry, an approved name
+// is held for six months from the decision.
+func Apply(reservation *models.NameReservation, result *integration.NameReservation, now time.Time) bool {
+	for i := range reservation.Names {
+		name := &reservation.Names[i]
+		for _, decided := range result.Names {
+			if strings.EqualFold(decided.Name, name.Name) {
+				name.Status = proposedStatus(decided.Status)
+				name.Reason = decided.Reason
+				break
+			}
+		}
+	}
+
+	switch result.Status {
+	case integration.NameStatusApproved:
+		reservation.Status = models.NameReservationStatusApproved
+	case integration.NameStatusRejected:
+		reservation.Status = models.NameReservationStatusRejected
+	default:
+		return false
+	}
+
+	decidedAt := now
+	if result.DecidedAt != nil {
+		decidedAt = *result.DecidedAt
+	}
+	reservation.DecidedAt = &decidedAt
+
+	if reservation.Status == models.NameReservationStatusApproved {
+		reserved := result.ReservedName
+		for _, name := range reservation.Names {
+			if reserved == "" && name.Status == models.ProposedNameStatusApproved {
+				reserved = name.Name
+			}
+		}
+		reservation.ReservedName = &reserved
+		expiresAt := decidedAt.AddDate(0, reservationMonths, 0)
+		if result.ExpiresAt != nil {
+			expiresAt = *result.ExpiresAt
+		}
+		reservation.ExpiresAt = &expiresAt
+	}
+	return true
+}
+
+func proposedStatus(status string) string {
+	switch status {
+	case integration.NameStatusApproved:
+		return models.ProposedNameStatusApproved
+	case integration.NameStatusRejected:
+		return models.ProposedNameStatusRejected
+	default:
+		return models.ProposedNameStatusProposed
+	}
+}
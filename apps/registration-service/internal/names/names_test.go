@@ -0,0 +1,89 @@
+package names
+
+import (
+	"testing"
+	"time"
+
+	"github.com/comply360/registration-service/internal/integration"
+	"github.com/comply360/shared/models"
+	testhelpers "github.com/comply360/shared/testing"
+	"github.com/google/uuid"
+)
+
+func TestRequired(t *testing.T) {
+	testhelpers.AssertTrue(t, Required(&models.Registration{RegistrationType: models.RegistrationTypePtyLtd, Jurisdiction: models.JurisdictionSouthAfrica}))
+	testhelpers.AssertTrue(t, Required(&models.Registration{RegistrationType: models.RegistrationTypePtyLtd, Jurisdiction: models.JurisdictionZimbabwe}), "DCIP reserves company names too")
+	testhelpers.AssertFalse(t, Required(&models.Registration{RegistrationType: models.RegistrationTypeBusinessName, Jurisdiction: models.JurisdictionZimbabwe}))
+	testhelpers.AssertFalse(t, Required(&models.Registration{RegistrationType: models.RegistrationTypeVATRegistration, Jurisdiction: models.JurisdictionSouthAfrica}))
+}
+
+func TestScreen(t *testing.T) {
+	existingID := uuid.New()
+	existing := []Existing{
+		{RegistrationID: existingID, Name: "Nkosi Holdings (Pty) Ltd"},
+		{RegistrationID: uuid.New(), Name: "Dlamini Logistics"},
+	}
+
+	screened := Screen([]string{
+		"  Umhlanga   Ventures ",
+		"National Bank of Umhlanga",
+		"NKOSI HOLDINGS",
+		"Dlamini Logistic",
+		"Umhlanga Ventures (Pty) Ltd",
+	}, existing)
+
+	testhelpers.AssertEqual(t, "Umhlanga Ventures", screened[0].Name, "Whitespace is tidied")
+	testhelpers.AssertEqual(t, models.ProposedNameStatusProposed, screened[0].Status)
+	testhelpers.AssertEqual(t, 0, len(screened[0].Issues))
+
+	testhelpers.AssertEqual(t, 2, len(screened[1].Issues))
+	testhelpers.AssertEqual(t, "bank", screened[1].Issues[0].Word)
+	testhelpers.AssertEqual(t, "national", screened[1].Issues[1].Word)
+
+	testhelpers.AssertEqual(t, models.NameIssueIdentical, screened[2].Issues[0].Code)
+	testhelpers.AssertEqual(t, existingID, *screened[2].Issues[0].RegistrationID)
+
+	testhelpers.AssertEqual(t, models.NameIssueSimilar, screened[3].Issues[0].Code)
+	testhelpers.AssertEqual(t, models.NameIssueSeverityWarn, screened[3].Issues[0].Severity)
+	testhelpers.AssertEqual(t, "Dlamini Logistics", screened[3].Issues[0].SimilarName)
+
+	testhelpers.AssertEqual(t, models.NameIssueRepeated, screened[4].Issues[0].Code, "Legal suffixes do not make a name different")
+
+	testhelpers.AssertTrue(t, Blocked(screened))
+	testhelpers.AssertFalse(t, Blocked(Screen([]string{"Umhlanga Ventures", "Dlamini Logistic"}, existing)), "Similar names only warn")
+	testhelpers.AssertFalse(t, Blocked(Screen([]string{"Bankside Traders"}, nil)), "Banned words match whole words")
+}
+
+func TestSimilarity(t *testing.T) {
+	testhelpers.AssertEqual(t, 1.0, Similarity("Nkosi Holdings (Pty) Ltd", "nkosi holdings"))
+	testhelpers.AssertTrue(t, Similarity("Nkosi Holdings", "Nkosi Holding") > SimilarityThreshold)
+	testhelpers.AssertTrue(t, Similarity("Nkosi Holdings", "Zulu Traders") < SimilarityThreshold)
+	testhelpers.AssertEqual(t, 0.0, Similarity("", ""))
+}
+
+func TestApply(t *testing.T) {
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	reservation := &models.NameReservation{
+		Status: models.NameReservationStatusSubmitted,
+		Names:  Screen([]string{"Umhlanga Ventures", "Umhlanga Traders"}, nil),
+	}
+
+	pending := &integration.NameReservation{Status: integration.NameStatusPending}
+	testhelpers.AssertFalse(t, Apply(reservation, pending, now))
+	testhelpers.AssertEqual(t, models.NameReservationStatusSubmitted, reservation.Status)
+
+	approved := &integration.NameReservation{
+		Status: integration.NameStatusApproved,
+		Names: []integration.ProposedName{
+			{Name: "UMHLANGA VENTURES", Status: integration.NameStatusRejected, Reason: "Name is not available"},
+			{Name: "Umhlanga Traders", Status: integration.NameStatusApproved},
+		},
+	}
+	testhelpers.AssertTrue(t, Apply(reservation, approved, now))
+	testhelpers.AssertEqual(t, models.NameReservationStatusApproved, reservation.Status)
+	testhelpers.AssertEqual(t, models.ProposedNameStatusRejected, reservation.Names[0].Status)
+	testhelpers.AssertEqual(t, "Name is not available", reservation.Names[0].Reason)
+	testhelpers.AssertEqual(t, "Umhlanga Traders", *reservation.ReservedName, "The approved name is reserved when CIPC does not say")
+	testhelpers.AssertEqual(t, now, *reservation.DecidedAt)
+	testhelpers.AssertEqual(t, now.AddDate(0, 6, 0), *reservation.ExpiresAt)
+}
@@ -0,0 +1,106 @@
+package queues
+
+import (
+	"github.com/comply360/shared/models"
+	"github.com/google/uuid"
+)
+
+// Route returns the queue a registration type is assigned through: the first
+// active queue listing the type, or else the first active queue listing no
+// types. Queues are expected oldest first. It returns nil when no queue takes
+// the type.
+func Route(queues []*models.ReviewQueue, registrationType string) *models.ReviewQueue {
+	var fallback *models.ReviewQueue
+	for _, queue := range queues {
+		if !queue.IsActive {
+			continue
+		}
+		if len(queue.RegistrationTypes) == 0 {
+			if fallback == nil {
+				fallback = queue
+			}
+			continue
+		}
+		if contains(queue.RegistrationTypes, registrationType) {
+			return queue
+		}
+	}
+	return fallback
+}
+
+// Pick chooses the member of a queue to assign a registration to. Members are
+// taken in turn starting after the last one assigned. Round-robin takes the next
+// eligible member; least-loaded takes the one with the fewest open
+// registrations; skill does the same among members with the registration type
+// as a skill. Inactive members and those at their maximum load are skipped.
+func Pick(queue *models.ReviewQueue, registrationType string) (uuid.UUID, bool) {
+	var chosen *models.ReviewQueueMember
+	for _, member := range rotation(queue) {
+		if !eligible(queue, member, registrationType) {
+			continue
+		}
+		if queue.Strategy == models.QueueStrategyRoundRobin || queue.Strategy == "" {
+			return member.UserID, true
+		}
+		if chosen == nil || member.Load < chosen.Load {
+			chosen = member
+		}
+	}
+
+	if chosen == nil {
+		return uuid.Nil, false
+	}
+	return chosen.UserID, true
+}
+
+// IsMember reports whether a user is an active member of a queue
+func IsMember(queue *models.ReviewQueue, userID uuid.UUID) bool {
+	for _, member := range queue.Members {
+		if member.UserID == userID && member.IsActive {
+			return true
+		}
+	}
+	return false
+}
+
+// rotation orders a queue's members starting after the last one assigned
+func rotation(queue *models.ReviewQueue) []*models.ReviewQueueMember {
+	start := 0
+	if queue.LastAssignedTo != nil {
+		for i, member := range queue.Members {
+			if member.UserID == *queue.LastAssignedTo {
+				start = i + 1
+				break
+			}
+		}
+	}
+
+	ordered := make([]*models.ReviewQueueMember, 0, len(queue.Members))
+	for i := range queue.Members {
+		ordered = append(ordered, queue.Members[(start+i)%len(queue.Members)])
+	}
+	return ordered
+}
+
+// eligible reports whether a member can take another registration of a type
+func eligible(queue *models.ReviewQueue, member *models.ReviewQueueMember, registrationType string) bool {
+	if !member.IsActive {
+		return false
+	}
+	if member.MaxLoad != nil && member.Load >= *member.MaxLoad {
+		return false
+	}
+	if queue.Strategy == models.QueueStrategySkill && !contains(member.Skills, registrationType) {
+		return false
+	}
+	return true
+}
+
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+package queues
+
+import (
+	"testing"
+
+	"github.com/comply360/shared/models"
+	testhelpers "github.com/comply360/shared/testing"
+	"github.com/google/uuid"
+)
+
+func member(load int, skills ...string) *models.ReviewQueueMember {
+	return &models.ReviewQueueMember{UserID: uuid.New(), Skills: skills, IsActive: true, Load: load}
+}
+
+func TestRoute_PrefersQueueListingType(t *testing.T) {
+	general := &models.ReviewQueue{Name: "General", IsActive: true}
+	companies := &models.ReviewQueue{Name: "Companies", IsActive: true, RegistrationTypes: []string{models.RegistrationTypePtyLtd}}
+	inactive := &models.ReviewQueue{Name: "VAT", RegistrationTypes: []string{models.RegistrationTypeVATRegistration}}
+	queues := []*models.ReviewQueue{general, companies, inactive}
+
+	testhelpers.AssertEqual(t, companies, Route(queues, models.RegistrationTypePtyLtd))
+	testhelpers.AssertEqual(t, general, Route(queues, models.RegistrationTypeVATRegistration), "Inactive queues take nothing")
+	testhelpers.AssertTrue(t, Route(queues[1:], models.RegistrationTypeBusinessName) == nil)
+}
+
+func TestPick_RoundRobinContinuesAfterLastAssigned(t *testing.T) {
+	a, b, c := member(0), member(5), member(0)
+	c.IsActive = false
+	queue := &models.ReviewQueue{Strategy: models.QueueStrategyRoundRobin, Members: []*models.ReviewQueueMember{a, b, c}}
+
+	picked, ok := Pick(queue, models.RegistrationTypePtyLtd)
+	testhelpers.AssertTrue(t, ok)
+	testhelpers.AssertEqual(t, a.UserID, picked)
+
+	queue.LastAssignedTo = &b.UserID
+	picked, _ = Pick(queue, models.RegistrationTypePtyLtd)
+	testhelpers.AssertEqual(t, a.UserID, picked, "Inactive members are skipped and the rotation wraps")
+}
+
+func TestPick_LeastLoadedSkipsFullMembers(t *testing.T) {
+	limit := 2
+	a, b, c := member(1), member(3), member(2)
+	a.MaxLoad = &limit
+	c.MaxLoad = &limit
+	queue := &models.ReviewQueue{Strategy: models.QueueStrategyLeastLoaded, Members: []*models.ReviewQueueMember{a, b, c}}
+
+	picked, ok := Pick(queue, models.RegistrationTypePtyLtd)
+	testhelpers.AssertTrue(t, ok)
+	testhelpers.AssertEqual(t, a.UserID, picked)
+
+	a.Load = 2
+	picked, _ = Pick(queue, models.RegistrationTypePtyLtd)
+	testhelpers.AssertEqual(t, b.UserID, picked, "Members at their maximum load are skipped")
+}
+
+func TestPick_SkillRequiresRegistrationType(t *testing.T) {
+	a := member(0, models.RegistrationTypeVATRegistration)
+	b := member(4, models.RegistrationTypePtyLtd)
+	c := member(1, models.RegistrationTypePtyLtd, models.RegistrationTypeCloseCorporation)
+	queue := &models.ReviewQueue{Strategy: models.QueueStrategySkill, Members: []*models.ReviewQueueMember{a, b, c}}
+
+	picked, ok := Pick(queue, models.RegistrationTypePtyLtd)
+	testhelpers.AssertTrue(t, ok)
+	testhelpers.AssertEqual(t, c.UserID, picked)
+
+	_, ok = Pick(queue, models.RegistrationTypeBusinessName)
+	testhelpers.AssertFalse(t, ok, "Nobody has the skill")
+}
@@ -0,0 +1,89 @@
+package register
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strconv"
+
+	"github.com/comply360/shared/models"
+)
+
+// beneficialOwnershipColumns are the columns of CIPC's beneficial ownership
+// register upload
+var beneficialOwnershipColumns = []string{
+	"Company Registration Number",
+	"Company Name",
+	"Surname",
+	"First Names",
+	"ID Number",
+	"Passport Number",
+	"Nationality",
+	"Date of Birth",
+	"Residential Address",
+	"Email",
+	"Nature of Beneficial Interest",
+	"Percentage",
+	"Date Became Beneficial Owner",
+}
+
+// natureOfInterest is how CIPC describes each way of holding an interest
+var natureOfInterest = map[string]string{
+	models.ControlDirectShareholding:   "Direct holding of shares",
+	models.ControlIndirectShareholding: "Indirect holding of shares",
+	models.ControlVotingRights:         "Voting rights",
+	models.ControlAppointDirectors:     "Right to appoint or remove directors",
+	models.ControlOther:                "Other means of control",
+}
+
+// BeneficialOwnershipCSV renders a company's beneficial owners in the format
+// CIPC takes for beneficial ownership filings. Only a registered company with
+// its shareholding fully accounted for and at least one beneficial owner can
+// be filed.
+func BeneficialOwnershipCSV(registration *models.Registration, register *models.CompanyRegister) ([]byte, error) {
+	registrationNumber := value(registration.RegistrationNumber)
+	if registrationNumber == "" {
+		return nil, refuse("the company has no registration number yet")
+	}
+	if !complete(register.ShareholdingTotal) {
+		return nil, refuse("shareholdings must total 100%% before filing, not %g%%", register.ShareholdingTotal)
+	}
+	if len(register.BeneficialOwners) == 0 {
+		return nil, refuse("the register has no beneficial owners to file")
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(beneficialOwnershipColumns); err != nil {
+		return nil, err
+	}
+	for _, owner := range register.BeneficialOwners {
+		percentage := ""
+		if owner.SharePercent != nil {
+			percentage = strconv.FormatFloat(*owner.SharePercent, 'f', 2, 64)
+		}
+		dateOfBirth := ""
+		if owner.DateOfBirth != nil {
+			dateOfBirth = owner.DateOfBirth.Format("2006-01-02")
+		}
+		record := []string{
+			registrationNumber,
+			registration.CompanyName,
+			value(owner.LastName),
+			value(owner.FirstNames),
+			value(owner.IDNumber),
+			value(owner.PassportNumber),
+			value(owner.Nationality),
+			dateOfBirth,
+			value(owner.ResidentialAddress),
+			value(owner.Email),
+			natureOfInterest[value(owner.ControlType)],
+			percentage,
+			owner.AppointedOn.Format("2006-01-02"),
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
@@ -0,0 +1,354 @@
+// Package register holds the rules for a company's register of directors,
+// shareholders and beneficial owners: what each entry must record, how the
+// versions of an entry are read as at a date, what keeps the register
+// consistent and how beneficial ownership is exported for filing with CIPC.
+package register
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/comply360/registration-service/internal/clients"
+	"github.com/comply360/shared/models"
+	"github.com/google/uuid"
+)
+
+// BeneficialOwnershipThreshold is the interest, in percent, from which a person
+// holding shares or voting rights is a beneficial owner
+const BeneficialOwnershipThreshold = 5.0
+
+// tolerance absorbs rounding in shareholdings recorded to four decimals
+const tolerance = 0.0001
+
+// RuleError is a change the register's rules refuse
+type RuleError struct {
+	Message string
+}
+
+func (e *RuleError) Error() string {
+	return e.Message
+}
+
+func refuse(format string, args ...interface{}) error {
+	return &RuleError{Message: fmt.Sprintf(format, args...)}
+}
+
+// ParseDate parses a YYYY-MM-DD date
+func ParseDate(value string) (time.Time, error) {
+	return time.Parse("2006-01-02", value)
+}
+
+// Date returns the calendar date of a time, at midnight UTC
+func Date(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// DateOfBirth reads the date of birth from the first six digits of a South
+// African identity number. The century is the latest that does not put the
+// birth after today.
+func DateOfBirth(idNumber string, today time.Time) (time.Time, bool) {
+	if len(idNumber) < 6 {
+		return time.Time{}, false
+	}
+	yy, err1 := strconv.Atoi(idNumber[0:2])
+	mm, err2 := strconv.Atoi(idNumber[2:4])
+	dd, err3 := strconv.Atoi(idNumber[4:6])
+	if err1 != nil || err2 != nil || err3 != nil {
+		return time.Time{}, false
+	}
+
+	year := today.Year()/100*100 + yy
+	if year > today.Year() {
+		year -= 100
+	}
+	born := time.Date(year, time.Month(mm), dd, 0, 0, 0, 0, time.UTC)
+	// time.Date normalises 31 February into March; a real date survives the
+	// round trip
+	if born.Year() != year || int(born.Month()) != mm || born.Day() != dd {
+		return time.Time{}, false
+	}
+	if born.After(Date(today)) {
+		return time.Time{}, false
+	}
+	return born, true
+}
+
+// Validate checks what an entry must record for its role, and fills a natural
+// person's date of birth from their identity number
+func Validate(entry *models.RegisterEntry, today time.Time) error {
+	switch entry.PersonType {
+	case models.RegisterPersonNatural:
+		if value(entry.FirstNames) == "" || value(entry.LastName) == "" {
+			return refuse("first_names and last_name are required for natural persons")
+		}
+		if entry.EntityName != nil || entry.RegistrationNumber != nil {
+			return refuse("entity_name and registration_number are only for legal entities")
+		}
+		if id := value(entry.IDNumber); id != "" {
+			born, ok := DateOfBirth(id, today)
+			if !ok {
+				return refuse("id_number does not contain a valid date of birth")
+			}
+			if entry.DateOfBirth != nil && !entry.DateOfBirth.Equal(born) {
+				return refuse("date_of_birth does not match id_number")
+			}
+			entry.DateOfBirth = &born
+		} else if value(entry.PassportNumber) == "" || value(entry.Nationality) == "" {
+			return refuse("id_number, or passport_number and nationality, are required for natural persons")
+		}
+	case models.RegisterPersonEntity:
+		if value(entry.EntityName) == "" || value(entry.RegistrationNumber) == "" {
+			return refuse("entity_name and registration_number are required for legal entities")
+		}
+		if entry.FirstNames != nil || entry.LastName != nil || entry.IDNumber != nil ||
+			entry.PassportNumber != nil || entry.DateOfBirth != nil {
+			return refuse("personal details are only for natural persons")
+		}
+	default:
+		return refuse("unknown person_type %q", entry.PersonType)
+	}
+
+	switch entry.Role {
+	case models.RegisterRoleDirector:
+		if entry.PersonType != models.RegisterPersonNatural {
+			return refuse("directors must be natural persons")
+		}
+		if entry.SharePercent != nil || entry.ControlType != nil {
+			return refuse("share_percent and control_type are not recorded for directors")
+		}
+	case models.RegisterRoleShareholder:
+		if entry.SharePercent == nil {
+			return refuse("share_percent is required for shareholders")
+		}
+		if entry.ControlType != nil {
+			return refuse("control_type is only recorded for beneficial owners")
+		}
+	case models.RegisterRoleBeneficialOwner:
+		if err := validateBeneficialOwner(entry); err != nil {
+			return err
+		}
+	default:
+		return refuse("unknown role %q", entry.Role)
+	}
+
+	if entry.SharePercent != nil && (*entry.SharePercent <= 0 || *entry.SharePercent > 100) {
+		return refuse("share_percent must be more than 0 and at most 100")
+	}
+	if entry.AppointedOn.After(Date(today)) {
+		return refuse("appointed_on cannot be in the future")
+	}
+	if entry.ResignedOn != nil && entry.ResignedOn.Before(entry.AppointedOn) {
+		return refuse("resigned_on cannot be before appointed_on")
+	}
+	return nil
+}
+
+// validateBeneficialOwner checks what CIPC asks of a beneficial owner: how
+// they hold their interest, how large it is when held through shares or
+// votes, where they live and, without a South African identity number, when
+// they were born
+func validateBeneficialOwner(entry *models.RegisterEntry) error {
+	if entry.PersonType != models.RegisterPersonNatural {
+		return refuse("beneficial owners must be natural persons")
+	}
+	switch value(entry.ControlType) {
+	case "":
+		return refuse("control_type is required for beneficial owners")
+	case models.ControlDirectShareholding, models.ControlIndirectShareholding, models.ControlVotingRights:
+		if entry.SharePercent == nil {
+			return refuse("share_percent is required for beneficial owners holding shares or voting rights")
+		}
+		if *entry.SharePercent < BeneficialOwnershipThreshold-tolerance {
+			return refuse("beneficial owners must hold at least %g%% of the shares or voting rights", BeneficialOwnershipThreshold)
+		}
+	}
+	if value(entry.ResidentialAddress) == "" {
+		return refuse("residential_address is required for beneficial owners")
+	}
+	if entry.DateOfBirth == nil {
+		return refuse("date_of_birth is required for beneficial owners without an id_number")
+	}
+	return nil
+}
+
+// Current returns the version of each entry in force on a date, with the
+// entry's appointment and resignation as last recorded, leaving out removed
+// entries and those not in office on the date. Versions may be of several
+// entries, in any order.
+func Current(versions []*models.RegisterEntry, date time.Time) []*models.RegisterEntry {
+	current := []*models.RegisterEntry{}
+	for _, history := range Entries(versions) {
+		entry := AsAt(history, date)
+		if entry != nil && entry.HoldsOfficeOn(date) {
+			current = append(current, entry)
+		}
+	}
+	return current
+}
+
+// Entries groups versions by entry, each entry's versions in order, and the
+// entries in the order they were first recorded
+func Entries(versions []*models.RegisterEntry) [][]*models.RegisterEntry {
+	byID := map[uuid.UUID][]*models.RegisterEntry{}
+	var order []uuid.UUID
+	for _, version := range versions {
+		if _, ok := byID[version.ID]; !ok {
+			order = append(order, version.ID)
+		}
+		byID[version.ID] = append(byID[version.ID], version)
+	}
+
+	entries := make([][]*models.RegisterEntry, 0, len(order))
+	for _, id := range order {
+		history := byID[id]
+		sort.Slice(history, func(i, j int) bool { return history[i].Version < history[j].Version })
+		entries = append(entries, history)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i][0].RecordedAt.Before(entries[j][0].RecordedAt)
+	})
+	return entries
+}
+
+// AsAt returns an entry's details as they stood on a date: the latest version
+// in effect by then, or the first version for dates before any took effect.
+// Appointment and resignation dates come from the latest version, which
+// corrects earlier ones. It returns nil for removed entries.
+func AsAt(history []*models.RegisterEntry, date time.Time) *models.RegisterEntry {
+	if len(history) == 0 {
+		return nil
+	}
+	latest := history[len(history)-1]
+	if latest.Removed {
+		return nil
+	}
+
+	inEffect := history[0]
+	for _, version := range history {
+		if !version.EffectiveFrom.After(date) {
+			inEffect = version
+		}
+	}
+	entry := *inEffect
+	entry.AppointedOn = latest.AppointedOn
+	entry.ResignedOn = latest.ResignedOn
+	return &entry
+}
+
+// Build returns the register as it stood on a date, with what keeps it from
+// being complete
+func Build(registrationID uuid.UUID, versions []*models.RegisterEntry, date time.Time) *models.CompanyRegister {
+	register := &models.CompanyRegister{
+		RegistrationID:   registrationID,
+		AsAt:             date,
+		Directors:        []*models.RegisterEntry{},
+		Shareholders:     []*models.RegisterEntry{},
+		BeneficialOwners: []*models.RegisterEntry{},
+		Issues:           []string{},
+	}
+	for _, entry := range Current(versions, date) {
+		switch entry.Role {
+		case models.RegisterRoleDirector:
+			register.Directors = append(register.Directors, entry)
+		case models.RegisterRoleShareholder:
+			register.Shareholders = append(register.Shareholders, entry)
+			register.ShareholdingTotal += *entry.SharePercent
+		case models.RegisterRoleBeneficialOwner:
+			register.BeneficialOwners = append(register.BeneficialOwners, entry)
+		}
+	}
+	register.ShareholdingTotal = math.Round(register.ShareholdingTotal*10000) / 10000
+
+	if len(register.Directors) == 0 {
+		register.Issues = append(register.Issues, "the register has no directors")
+	}
+	if len(register.Shareholders) == 0 {
+		register.Issues = append(register.Issues, "the register has no shareholders")
+	} else if !complete(register.ShareholdingTotal) {
+		register.Issues = append(register.Issues, fmt.Sprintf("shareholdings total %g%%, not 100%%", register.ShareholdingTotal))
+	}
+	if len(register.BeneficialOwners) == 0 {
+		register.Issues = append(register.Issues, "the register has no beneficial owners")
+	}
+	return register
+}
+
+// Check checks the register's versions for inconsistencies that no single
+// entry shows: shareholdings adding up to more than 100% on any date, and the
+// same person holding the same role twice at once
+func Check(versions []*models.RegisterEntry) error {
+	dates := map[time.Time]bool{}
+	for _, version := range versions {
+		dates[version.EffectiveFrom] = true
+		dates[version.AppointedOn] = true
+	}
+	for date := range dates {
+		total := 0.0
+		for _, entry := range Current(versions, date) {
+			if entry.Role == models.RegisterRoleShareholder {
+				total += *entry.SharePercent
+			}
+		}
+		if total > 100+tolerance {
+			return refuse("shareholdings would total %g%% on %s", math.Round(total*10000)/10000, date.Format("2006-01-02"))
+		}
+	}
+
+	var latest []*models.RegisterEntry
+	for _, history := range Entries(versions) {
+		if entry := history[len(history)-1]; !entry.Removed {
+			latest = append(latest, entry)
+		}
+	}
+	for i, a := range latest {
+		for _, b := range latest[i+1:] {
+			if a.Role == b.Role && samePerson(a, b) && overlap(a, b) {
+				return refuse("%s is already on the register as %s for that period", a.Name(), strings.ReplaceAll(a.Role, "_", " "))
+			}
+		}
+	}
+	return nil
+}
+
+// samePerson reports whether two entries identify the same person or entity
+func samePerson(a, b *models.RegisterEntry) bool {
+	if a.PersonType != b.PersonType {
+		return false
+	}
+	if a.PersonType == models.RegisterPersonEntity {
+		return sameNumber(a.RegistrationNumber, b.RegistrationNumber)
+	}
+	if sameNumber(a.IDNumber, b.IDNumber) {
+		return true
+	}
+	return sameNumber(a.PassportNumber, b.PassportNumber) && value(a.Nationality) == value(b.Nationality)
+}
+
+func sameNumber(a, b *string) bool {
+	number := clients.NormalizeIDNumber(value(a))
+	return number != "" && number == clients.NormalizeIDNumber(value(b))
+}
+
+// overlap reports whether two entries' terms of office overlap
+func overlap(a, b *models.RegisterEntry) bool {
+	endsAfter := func(entry *models.RegisterEntry, date time.Time) bool {
+		return entry.ResignedOn == nil || entry.ResignedOn.After(date)
+	}
+	return endsAfter(a, b.AppointedOn) && endsAfter(b, a.AppointedOn)
+}
+
+// complete reports whether shareholdings account for all of the shares
+func complete(total float64) bool {
+	return math.Abs(total-100) <= tolerance
+}
+
+func value(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return strings.TrimSpace(*s)
+}
@@ -0,0 +1,292 @@
+package register
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/comply360/shared/models"
+	testhelpers "github.com/comply360/shared/testing"
+	"github.com/google/uuid"
+)
+
+var today = time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+
+func str(s string) *string {
+	return &s
+}
+
+func percent(p float64) *float64 {
+	return &p
+}
+
+func date(value string) time.Time {
+	d, err := ParseDate(value)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func datePtr(value string) *time.Time {
+	d := date(value)
+	return &d
+}
+
+func person(role, idNumber string) *models.RegisterEntry {
+	return &models.RegisterEntry{
+		ID:          uuid.New(),
+		Version:     1,
+		Role:        role,
+		PersonType:  models.RegisterPersonNatural,
+		FirstNames:  str("Thandi"),
+		LastName:    str("Nkosi"),
+		IDNumber:    str(idNumber),
+		AppointedOn: date("2024-03-01"),
+	}
+}
+
+func shareholder(idNumber string, share float64, appointed string) *models.RegisterEntry {
+	entry := person(models.RegisterRoleShareholder, idNumber)
+	entry.SharePercent = percent(share)
+	entry.AppointedOn = date(appointed)
+	entry.EffectiveFrom = date(appointed)
+	return entry
+}
+
+// revise returns the next version of an entry taking effect from a date
+func revise(entry *models.RegisterEntry, effective string, change func(*models.RegisterEntry)) *models.RegisterEntry {
+	next := *entry
+	next.Version++
+	next.EffectiveFrom = date(effective)
+	change(&next)
+	return &next
+}
+
+func TestDateOfBirth(t *testing.T) {
+	born, ok := DateOfBirth("8001015009087", today)
+	testhelpers.AssertTrue(t, ok)
+	testhelpers.AssertEqual(t, date("1980-01-01"), born)
+
+	born, ok = DateOfBirth("0502295009083", today)
+	testhelpers.AssertFalse(t, ok, "2005 was not a leap year")
+
+	born, ok = DateOfBirth("2410185009083", today)
+	testhelpers.AssertTrue(t, ok)
+	testhelpers.AssertEqual(t, date("2024-10-18"), born)
+
+	born, ok = DateOfBirth("2701015009083", today)
+	testhelpers.AssertTrue(t, ok)
+	testhelpers.AssertEqual(t, 1927, born.Year(), "Births cannot be in the future")
+
+	_, ok = DateOfBirth("80AB01", today)
+	testhelpers.AssertFalse(t, ok)
+}
+
+func TestValidate_NaturalPerson(t *testing.T) {
+	director := person(models.RegisterRoleDirector, "8001015009087")
+	testhelpers.AssertNoError(t, Validate(director, today))
+	testhelpers.AssertNotNil(t, director.DateOfBirth, "Date of birth is read from the ID number")
+	testhelpers.AssertEqual(t, date("1980-01-01"), *director.DateOfBirth)
+
+	mismatched := person(models.RegisterRoleDirector, "8001015009087")
+	mismatched.DateOfBirth = datePtr("1980-01-02")
+	testhelpers.AssertError(t, Validate(mismatched, today))
+
+	foreign := person(models.RegisterRoleDirector, "")
+	foreign.IDNumber = nil
+	foreign.PassportNumber = str("FN123456")
+	testhelpers.AssertError(t, Validate(foreign, today), "Passport holders need a nationality")
+	foreign.Nationality = str("ZW")
+	testhelpers.AssertNoError(t, Validate(foreign, today))
+
+	unnamed := person(models.RegisterRoleDirector, "8001015009087")
+	unnamed.LastName = nil
+	testhelpers.AssertError(t, Validate(unnamed, today))
+}
+
+func TestValidate_Roles(t *testing.T) {
+	entity := &models.RegisterEntry{
+		Role:               models.RegisterRoleShareholder,
+		PersonType:         models.RegisterPersonEntity,
+		EntityName:         str("Nkosi Holdings (Pty) Ltd"),
+		RegistrationNumber: str("2019/123456/07"),
+		SharePercent:       percent(60),
+		AppointedOn:        date("2024-03-01"),
+	}
+	testhelpers.AssertNoError(t, Validate(entity, today))
+
+	entity.Role = models.RegisterRoleDirector
+	entity.SharePercent = nil
+	testhelpers.AssertError(t, Validate(entity, today), "Directors must be natural persons")
+
+	director := person(models.RegisterRoleDirector, "8001015009087")
+	director.SharePercent = percent(10)
+	testhelpers.AssertError(t, Validate(director, today))
+
+	holder := person(models.RegisterRoleShareholder, "8001015009087")
+	testhelpers.AssertError(t, Validate(holder, today), "Shareholders need a shareholding")
+	holder.SharePercent = percent(100.5)
+	testhelpers.AssertError(t, Validate(holder, today))
+	holder.SharePercent = percent(40)
+	testhelpers.AssertNoError(t, Validate(holder, today))
+}
+
+func TestValidate_BeneficialOwner(t *testing.T) {
+	owner := person(models.RegisterRoleBeneficialOwner, "8001015009087")
+	owner.ResidentialAddress = str("1 Main Road, Sandton")
+	testhelpers.AssertError(t, Validate(owner, today), "Beneficial owners need a control type")
+
+	owner.ControlType = str(models.ControlDirectShareholding)
+	testhelpers.AssertError(t, Validate(owner, today), "Shareholding interests need a percentage")
+
+	owner.SharePercent = percent(4.99)
+	testhelpers.AssertError(t, Validate(owner, today), "Interests under the threshold are not beneficial ownership")
+
+	owner.SharePercent = percent(5)
+	testhelpers.AssertNoError(t, Validate(owner, today))
+
+	control := person(models.RegisterRoleBeneficialOwner, "8001015009087")
+	control.ResidentialAddress = str("1 Main Road, Sandton")
+	control.ControlType = str(models.ControlAppointDirectors)
+	testhelpers.AssertNoError(t, Validate(control, today), "Control without shares needs no percentage")
+
+	control.ResidentialAddress = nil
+	testhelpers.AssertError(t, Validate(control, today))
+
+	foreign := person(models.RegisterRoleBeneficialOwner, "")
+	foreign.IDNumber = nil
+	foreign.PassportNumber = str("FN123456")
+	foreign.Nationality = str("ZW")
+	foreign.ResidentialAddress = str("4 Samora Machel Ave, Harare")
+	foreign.ControlType = str(models.ControlOther)
+	testhelpers.AssertError(t, Validate(foreign, today), "Without an SA ID the date of birth is required")
+	foreign.DateOfBirth = datePtr("1975-06-16")
+	testhelpers.AssertNoError(t, Validate(foreign, today))
+}
+
+func TestValidate_Dates(t *testing.T) {
+	director := person(models.RegisterRoleDirector, "8001015009087")
+	director.AppointedOn = date("2026-10-19")
+	testhelpers.AssertError(t, Validate(director, today))
+
+	director.AppointedOn = date("2024-03-01")
+	director.ResignedOn = datePtr("2024-02-29")
+	testhelpers.AssertError(t, Validate(director, today))
+
+	director.ResignedOn = datePtr("2024-03-01")
+	testhelpers.AssertNoError(t, Validate(director, today))
+}
+
+func TestAsAt(t *testing.T) {
+	v1 := shareholder("8001015009087", 100, "2024-03-01")
+	v2 := revise(v1, "2025-07-01", func(e *models.RegisterEntry) { e.SharePercent = percent(60) })
+	v3 := revise(v2, "2026-01-31", func(e *models.RegisterEntry) { e.ResignedOn = datePtr("2026-01-31") })
+	history := []*models.RegisterEntry{v1, v2, v3}
+
+	testhelpers.AssertEqual(t, 100.0, *AsAt(history, date("2025-06-30")).SharePercent)
+	testhelpers.AssertEqual(t, 60.0, *AsAt(history, date("2025-07-01")).SharePercent)
+	testhelpers.AssertEqual(t, 100.0, *AsAt(history, date("2020-01-01")).SharePercent, "Before any version, the first applies")
+
+	entry := AsAt(history, date("2025-07-01"))
+	testhelpers.AssertNotNil(t, entry.ResignedOn, "Resignation is taken from the latest version")
+	testhelpers.AssertTrue(t, entry.HoldsOfficeOn(date("2026-01-30")))
+	testhelpers.AssertFalse(t, entry.HoldsOfficeOn(date("2026-01-31")))
+	testhelpers.AssertFalse(t, entry.HoldsOfficeOn(date("2024-02-29")))
+
+	removed := revise(v3, "2026-10-18", func(e *models.RegisterEntry) { e.Removed = true })
+	testhelpers.AssertNil(t, AsAt(append(history, removed), date("2025-07-01")))
+}
+
+func TestBuild(t *testing.T) {
+	director := person(models.RegisterRoleDirector, "8001015009087")
+	director.EffectiveFrom = director.AppointedOn
+	a := shareholder("8001015009087", 100, "2024-03-01")
+	a2 := revise(a, "2025-07-01", func(e *models.RegisterEntry) { e.SharePercent = percent(60) })
+	b := shareholder("9203150123082", 40, "2025-07-01")
+	versions := []*models.RegisterEntry{director, a, a2, b}
+
+	registrationID := uuid.New()
+	register := Build(registrationID, versions, date("2025-01-01"))
+	testhelpers.AssertEqual(t, registrationID, register.RegistrationID)
+	testhelpers.AssertEqual(t, 1, len(register.Directors))
+	testhelpers.AssertEqual(t, 1, len(register.Shareholders))
+	testhelpers.AssertEqual(t, 100.0, register.ShareholdingTotal)
+	testhelpers.AssertEqual(t, 1, len(register.Issues), "Only the missing beneficial owners")
+
+	register = Build(registrationID, versions, date("2025-07-01"))
+	testhelpers.AssertEqual(t, 2, len(register.Shareholders))
+	testhelpers.AssertEqual(t, 100.0, register.ShareholdingTotal)
+
+	register = Build(registrationID, []*models.RegisterEntry{director, a2, b}, date("2025-07-01"))
+	testhelpers.AssertEqual(t, 100.0, register.ShareholdingTotal)
+
+	register = Build(registrationID, []*models.RegisterEntry{director, b}, date("2025-07-01"))
+	testhelpers.AssertEqual(t, 40.0, register.ShareholdingTotal)
+	testhelpers.AssertTrue(t, strings.Contains(strings.Join(register.Issues, "; "), "not 100%"))
+}
+
+func TestCheck_Shareholding(t *testing.T) {
+	a := shareholder("8001015009087", 100, "2024-03-01")
+	b := shareholder("9203150123082", 40, "2025-07-01")
+	testhelpers.AssertError(t, Check([]*models.RegisterEntry{a, b}), "140% from 2025-07-01")
+
+	a2 := revise(a, "2025-07-01", func(e *models.RegisterEntry) { e.SharePercent = percent(60) })
+	testhelpers.AssertNoError(t, Check([]*models.RegisterEntry{a, a2, b}))
+
+	late := revise(a, "2025-08-01", func(e *models.RegisterEntry) { e.SharePercent = percent(60) })
+	err := Check([]*models.RegisterEntry{a, late, b})
+	testhelpers.AssertError(t, err, "The transfer must take effect when the new shares are issued")
+	_, ok := err.(*RuleError)
+	testhelpers.AssertTrue(t, ok)
+
+	resigned := revise(a, "2025-07-01", func(e *models.RegisterEntry) { e.ResignedOn = datePtr("2025-07-01") })
+	testhelpers.AssertNoError(t, Check([]*models.RegisterEntry{a, resigned, b}))
+}
+
+func TestCheck_SamePerson(t *testing.T) {
+	first := person(models.RegisterRoleDirector, "8001015009087")
+	second := person(models.RegisterRoleDirector, "800101 5009 087")
+	second.AppointedOn = date("2025-01-01")
+	testhelpers.AssertError(t, Check([]*models.RegisterEntry{first, second}))
+
+	first.ResignedOn = datePtr("2025-01-01")
+	testhelpers.AssertNoError(t, Check([]*models.RegisterEntry{first, second}), "A director may be reappointed after resigning")
+
+	first.ResignedOn = nil
+	owner := person(models.RegisterRoleBeneficialOwner, "8001015009087")
+	testhelpers.AssertNoError(t, Check([]*models.RegisterEntry{first, owner}), "One person may hold several roles")
+
+	removed := revise(second, "2026-10-18", func(e *models.RegisterEntry) { e.Removed = true })
+	testhelpers.AssertNoError(t, Check([]*models.RegisterEntry{first, second, removed}))
+}
+
+func TestBeneficialOwnershipCSV(t *testing.T) {
+	owner := person(models.RegisterRoleBeneficialOwner, "8001015009087")
+	owner.ResidentialAddress = str("1 Main Road, Sandton")
+	owner.ControlType = str(models.ControlDirectShareholding)
+	owner.SharePercent = percent(60)
+	owner.DateOfBirth = datePtr("1980-01-01")
+
+	registration := &models.Registration{CompanyName: "Nkosi Holdings (Pty) Ltd"}
+	register := &models.CompanyRegister{ShareholdingTotal: 100, BeneficialOwners: []*models.RegisterEntry{owner}}
+
+	_, err := BeneficialOwnershipCSV(registration, register)
+	testhelpers.AssertError(t, err, "Unregistered companies cannot file")
+
+	registration.RegistrationNumber = str("2024/123456/07")
+	register.ShareholdingTotal = 60
+	_, err = BeneficialOwnershipCSV(registration, register)
+	testhelpers.AssertError(t, err, "Shareholding must be fully accounted for")
+
+	register.ShareholdingTotal = 100
+	data, err := BeneficialOwnershipCSV(registration, register)
+	testhelpers.AssertNoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	testhelpers.AssertEqual(t, 2, len(lines))
+	testhelpers.AssertTrue(t, strings.HasPrefix(lines[0], "Company Registration Number,Company Name,Surname"))
+	testhelpers.AssertEqual(t,
+		`2024/123456/07,Nkosi Holdings (Pty) Ltd,Nkosi,Thandi,8001015009087,,,1980-01-01,"1 Main Road, Sandton",,Direct holding of shares,60.00,2024-03-01`,
+		lines[1])
+}
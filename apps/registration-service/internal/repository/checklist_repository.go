@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/comply360/registration-service/internal/requirements"
+	"github.com/comply360/shared/tenancy"
+	"github.com/google/uuid"
+)
+
+// CountDocuments counts a registration's documents by type and status, as
+// uploaded and verified through the document service
+func (r *RegistrationRepository) CountDocuments(schema string, tenantID, registrationID uuid.UUID) (requirements.DocumentCounts, error) {
+	query := fmt.Sprintf(`
+		SELECT document_type, status, COUNT(*)
+		FROM %s.documents
+		WHERE registration_id = $1 AND tenant_id = $2 AND deleted_at IS NULL
+		GROUP BY document_type, status
+	`, schema)
+
+	counts := requirements.DocumentCounts{}
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		rows, err := tx.Query(query, registrationID, tenantID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var documentType, status string
+			var count int
+			if err := rows.Scan(&documentType, &status, &count); err != nil {
+				return err
+			}
+			if counts[documentType] == nil {
+				counts[documentType] = map[string]int{}
+			}
+			counts[documentType][status] = count
+		}
+
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count documents: %w", err)
+	}
+
+	return counts, nil
+}
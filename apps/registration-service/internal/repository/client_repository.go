@@ -0,0 +1,464 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/comply360/registration-service/internal/clients"
+	"github.com/comply360/shared/models"
+	"github.com/comply360/shared/pagination"
+	"github.com/comply360/shared/tenancy"
+	"github.com/google/uuid"
+)
+
+// maxDuplicates bounds how many possible duplicates are returned for a client
+const maxDuplicates = 20
+
+type ClientRepository struct {
+	db *sql.DB
+}
+
+func NewClientRepository(db *sql.DB) *ClientRepository {
+	return &ClientRepository{db: db}
+}
+
+// clientColumns are the client columns read by scanClient
+const clientColumns = `id, tenant_id, client_type, full_name, company_name, id_number,
+			passport_number, tax_number, vat_number, email, phone, mobile,
+			street_address, city, state_province, postal_code, country, status,
+			user_id, merged_into, created_at, updated_at, deleted_at`
+
+func scanClient(row interface{ Scan(...interface{}) error }) (*models.Client, error) {
+	client := &models.Client{}
+	err := row.Scan(
+		&client.ID,
+		&client.TenantID,
+		&client.ClientType,
+		&client.FullName,
+		&client.CompanyName,
+		&client.IDNumber,
+		&client.PassportNumber,
+		&client.TaxNumber,
+		&client.VATNumber,
+		&client.Email,
+		&client.Phone,
+		&client.Mobile,
+		&client.StreetAddress,
+		&client.City,
+		&client.StateProvince,
+		&client.PostalCode,
+		&client.Country,
+		&client.Status,
+		&client.UserID,
+		&client.MergedInto,
+		&client.CreatedAt,
+		&client.UpdatedAt,
+		&client.DeletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// ClientKeys are the fields client lists sort by
+var ClientKeys = &pagination.Keys[*models.Client]{
+	ID: func(c *models.Client) uuid.UUID { return c.ID },
+	Columns: map[string]pagination.Column[*models.Client]{
+		"created_at": {SQL: "created_at", Value: func(c *models.Client) interface{} { return c.CreatedAt }},
+		"updated_at": {SQL: "updated_at", Value: func(c *models.Client) interface{} { return c.UpdatedAt }},
+		"email":      {SQL: "email", Value: func(c *models.Client) interface{} { return c.Email }},
+		"status":     {SQL: "status", Value: func(c *models.Client) interface{} { return c.Status }},
+	},
+	Default: "-created_at",
+}
+
+// Create creates a new client
+func (r *ClientRepository) Create(schema string, client *models.Client) error {
+	query := fmt.Sprintf(`
+		INSERT INTO %s.clients (
+			tenant_id, client_type, full_name, company_name, id_number,
+			passport_number, tax_number, vat_number, email, phone, mobile,
+			street_address, city, state_province, postal_code, country, status
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+		RETURNING id, created_at, updated_at
+	`, schema)
+
+	err := tenancy.WithTenant(r.db, client.TenantID, func(tx *sql.Tx) error {
+		return tx.QueryRow(
+			query,
+			client.TenantID,
+			client.ClientType,
+			client.FullName,
+			client.CompanyName,
+			client.IDNumber,
+			client.PassportNumber,
+			client.TaxNumber,
+			client.VATNumber,
+			client.Email,
+			client.Phone,
+			client.Mobile,
+			client.StreetAddress,
+			client.City,
+			client.StateProvince,
+			client.PostalCode,
+			client.Country,
+			client.Status,
+		).Scan(&client.ID, &client.CreatedAt, &client.UpdatedAt)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a client by ID
+func (r *ClientRepository) GetByID(schema string, tenantID, clientID uuid.UUID) (*models.Client, error) {
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s.clients
+		WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL
+	`, clientColumns, schema)
+
+	var client *models.Client
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		var err error
+		client, err = scanClient(tx.QueryRow(query, clientID, tenantID))
+		return err
+	})
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("client not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client: %w", err)
+	}
+
+	return client, nil
+}
+
+// List retrieves a page of clients, with optional status and client type filters
+func (r *ClientRepository) List(schema string, tenantID uuid.UUID, status, clientType string, page *pagination.Query[*models.Client]) (*pagination.List[*models.Client], error) {
+	whereClause := "WHERE tenant_id = $1 AND deleted_at IS NULL"
+	args := []interface{}{tenantID}
+
+	if status != "" {
+		args = append(args, status)
+		whereClause += fmt.Sprintf(" AND status = $%d", len(args))
+	}
+	if clientType != "" {
+		args = append(args, clientType)
+		whereClause += fmt.Sprintf(" AND client_type = $%d", len(args))
+	}
+
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM %s.clients %s`, schema, whereClause)
+	countArgs := args
+
+	if keyset, values := page.Keyset(len(args) + 1); keyset != "" {
+		whereClause += " AND " + keyset
+		args = append(append([]interface{}{}, args...), values...)
+	}
+	args = append(args, page.Limit())
+
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s.clients
+		%s
+		ORDER BY %s
+		LIMIT $%d
+	`, clientColumns, schema, whereClause, page.OrderBy(), len(args))
+
+	var total *int
+	var list []*models.Client
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		if page.IncludeTotal() {
+			var count int
+			if err := tx.QueryRow(countQuery, countArgs...).Scan(&count); err != nil {
+				return err
+			}
+			total = &count
+		}
+
+		rows, err := tx.Query(query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			client, err := scanClient(rows)
+			if err != nil {
+				return err
+			}
+			list = append(list, client)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list clients: %w", err)
+	}
+
+	return page.List(list, total), nil
+}
+
+// Update saves a client's details, status and user link
+func (r *ClientRepository) Update(schema string, client *models.Client) error {
+	err := tenancy.WithTenant(r.db, client.TenantID, func(tx *sql.Tx) error {
+		return updateClient(tx, schema, client)
+	})
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("client not found")
+	}
+	if isUniqueViolation(err) {
+		return fmt.Errorf("user is already linked to a client")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update client: %w", err)
+	}
+
+	return nil
+}
+
+func updateClient(tx *sql.Tx, schema string, client *models.Client) error {
+	query := fmt.Sprintf(`
+		UPDATE %s.clients
+		SET full_name = $3, company_name = $4, id_number = $5, passport_number = $6,
+			tax_number = $7, vat_number = $8, email = $9, phone = $10, mobile = $11,
+			street_address = $12, city = $13, state_province = $14, postal_code = $15,
+			country = $16, status = $17, user_id = $18
+		WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL
+		RETURNING updated_at
+	`, schema)
+
+	return tx.QueryRow(
+		query,
+		client.ID,
+		client.TenantID,
+		client.FullName,
+		client.CompanyName,
+		client.IDNumber,
+		client.PassportNumber,
+		client.TaxNumber,
+		client.VATNumber,
+		client.Email,
+		client.Phone,
+		client.Mobile,
+		client.StreetAddress,
+		client.City,
+		client.StateProvince,
+		client.PostalCode,
+		client.Country,
+		client.Status,
+		client.UserID,
+	).Scan(&client.UpdatedAt)
+}
+
+// Delete soft deletes a client
+func (r *ClientRepository) Delete(schema string, tenantID, clientID uuid.UUID) error {
+	query := fmt.Sprintf(`
+		UPDATE %s.clients
+		SET deleted_at = NOW(), user_id = NULL
+		WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL
+	`, schema)
+
+	var deleted int64
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		result, err := tx.Exec(query, clientID, tenantID)
+		if err != nil {
+			return err
+		}
+		deleted, err = result.RowsAffected()
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete client: %w", err)
+	}
+	if deleted == 0 {
+		return fmt.Errorf("client not found")
+	}
+
+	return nil
+}
+
+// UserExists reports whether a user belongs to the tenant
+func (r *ClientRepository) UserExists(schema string, tenantID, userID uuid.UUID) (bool, error) {
+	query := fmt.Sprintf(`
+		SELECT EXISTS (SELECT 1 FROM %s.users WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL)
+	`, schema)
+
+	var exists bool
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		return tx.QueryRow(query, userID, tenantID).Scan(&exists)
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	return exists, nil
+}
+
+// FindDuplicates returns clients sharing an identity number, email address or
+// company name with the given client, oldest first. The client itself is left out.
+func (r *ClientRepository) FindDuplicates(schema string, tenantID uuid.UUID, client *models.Client) ([]*models.Client, error) {
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s.clients
+		WHERE tenant_id = $1 AND deleted_at IS NULL AND id <> $2
+			AND (
+				($3 <> '' AND upper(regexp_replace(id_number, '[^0-9A-Za-z]', '', 'g')) = $3)
+				OR lower(email) = lower($4)
+				OR ($5 <> '' AND company_name_key = $5)
+			)
+		ORDER BY created_at, id
+		LIMIT %d
+	`, clientColumns, schema, maxDuplicates)
+
+	idNumber := ""
+	if client.IDNumber != nil {
+		idNumber = clients.NormalizeIDNumber(*client.IDNumber)
+	}
+	companyKey := ""
+	if client.CompanyName != nil {
+		companyKey = clients.CompanyKey(*client.CompanyName)
+	}
+
+	var duplicates []*models.Client
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		rows, err := tx.Query(query, tenantID, client.ID, idNumber, client.Email, companyKey)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			duplicate, err := scanClient(rows)
+			if err != nil {
+				return err
+			}
+			duplicates = append(duplicates, duplicate)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find duplicate clients: %w", err)
+	}
+
+	return duplicates, nil
+}
+
+// ListDocuments returns the documents uploaded for a client or any of its
+// registrations
+func (r *ClientRepository) ListDocuments(schema string, tenantID, clientID uuid.UUID) ([]*models.Document, error) {
+	query := fmt.Sprintf(`
+		SELECT id, registration_id, client_id, document_type, status, verified_at, created_at
+		FROM %[1]s.documents
+		WHERE tenant_id = $1 AND deleted_at IS NULL
+			AND (client_id = $2 OR registration_id IN (
+				SELECT id FROM %[1]s.registrations WHERE tenant_id = $1 AND client_id = $2
+			))
+	`, schema)
+
+	var documents []*models.Document
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		rows, err := tx.Query(query, tenantID, clientID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			document := &models.Document{TenantID: tenantID}
+			err := rows.Scan(
+				&document.ID,
+				&document.RegistrationID,
+				&document.ClientID,
+				&document.DocumentType,
+				&document.Status,
+				&document.VerifiedAt,
+				&document.CreatedAt,
+			)
+			if err != nil {
+				return err
+			}
+			documents = append(documents, document)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list client documents: %w", err)
+	}
+
+	return documents, nil
+}
+
+// Merge merges a source client into the target in one transaction: the
+// source's registrations and documents move to the target, the target is saved
+// with the details it took from the source, and the source is deleted.
+func (r *ClientRepository) Merge(schema string, target *models.Client, sourceID uuid.UUID, mergedBy *uuid.UUID) (*models.ClientMerge, error) {
+	merge := &models.ClientMerge{
+		TenantID:       target.TenantID,
+		SourceClientID: sourceID,
+		TargetClientID: target.ID,
+		MergedBy:       mergedBy,
+	}
+
+	err := tenancy.WithTenant(r.db, target.TenantID, func(tx *sql.Tx) error {
+		// The source gives up its user link before the target takes it over
+		result, err := tx.Exec(fmt.Sprintf(`
+			UPDATE %s.clients
+			SET deleted_at = NOW(), merged_into = $3, status = 'inactive', user_id = NULL
+			WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL
+		`, schema), sourceID, target.TenantID, target.ID)
+		if err != nil {
+			return err
+		}
+		if count, err := result.RowsAffected(); err != nil {
+			return err
+		} else if count == 0 {
+			return sql.ErrNoRows
+		}
+
+		for table, moved := range map[string]*int{
+			"registrations": &merge.RegistrationsMoved,
+			"documents":     &merge.DocumentsMoved,
+		} {
+			result, err := tx.Exec(fmt.Sprintf(`
+				UPDATE %s.%s SET client_id = $1 WHERE client_id = $2 AND tenant_id = $3
+			`, schema, table), target.ID, sourceID, target.TenantID)
+			if err != nil {
+				return fmt.Errorf("failed to move %s: %w", table, err)
+			}
+			count, err := result.RowsAffected()
+			if err != nil {
+				return err
+			}
+			*moved = int(count)
+		}
+
+		if err := updateClient(tx, schema, target); err != nil {
+			return err
+		}
+
+		return tx.QueryRow(fmt.Sprintf(`
+			INSERT INTO %s.client_merges (
+				tenant_id, source_client_id, target_client_id, registrations_moved, documents_moved, merged_by
+			) VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING id, created_at
+		`, schema),
+			merge.TenantID,
+			merge.SourceClientID,
+			merge.TargetClientID,
+			merge.RegistrationsMoved,
+			merge.DocumentsMoved,
+			merge.MergedBy,
+		).Scan(&merge.ID, &merge.CreatedAt)
+	})
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("client not found")
+	}
+	if isUniqueViolation(err) {
+		return nil, fmt.Errorf("user is already linked to a client")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge clients: %w", err)
+	}
+
+	return merge, nil
+}
@@ -0,0 +1,484 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/comply360/shared/models"
+	"github.com/comply360/shared/pagination"
+	"github.com/comply360/shared/tenancy"
+	"github.com/google/uuid"
+)
+
+type ComplianceRepository struct {
+	db *sql.DB
+}
+
+func NewComplianceRepository(db *sql.DB) *ComplianceRepository {
+	return &ComplianceRepository{db: db}
+}
+
+// ComplianceFilter narrows a list of obligations. Zero values match
+// everything.
+type ComplianceFilter struct {
+	Status         string
+	ObligationType string
+	ClientID       *uuid.UUID
+	RegistrationID *uuid.UUID
+	DueFrom        *time.Time
+	DueTo          *time.Time
+}
+
+// DueReminder is an open obligation with the client to remind of it
+type DueReminder struct {
+	Obligation  *models.ComplianceObligation
+	ClientName  string
+	ClientEmail string
+}
+
+// ComplianceKeys are the fields obligation lists sort by
+var ComplianceKeys = &pagination.Keys[*models.ComplianceObligation]{
+	ID: func(o *models.ComplianceObligation) uuid.UUID { return o.ID },
+	Columns: map[string]pagination.Column[*models.ComplianceObligation]{
+		"due_date":   {SQL: "due_date", Value: func(o *models.ComplianceObligation) interface{} { return o.DueDate }},
+		"created_at": {SQL: "created_at", Value: func(o *models.ComplianceObligation) interface{} { return o.CreatedAt }},
+		"status":     {SQL: "status", Value: func(o *models.ComplianceObligation) interface{} { return o.Status }},
+	},
+	Default: "due_date",
+}
+
+// complianceColumns are the obligation columns read by scanObligation, from
+// complianceObligations
+const complianceColumns = `id, tenant_id, registration_id, client_id, company_name,
+			obligation_type, jurisdiction, authority, title, period_start, period_end,
+			due_date, status, completed_at, completed_by, filing_reference, notes,
+			last_reminder_days, reminded_at, created_at, updated_at`
+
+// complianceObligations selects obligations with their registration's client
+// and company name, as a table o, for the live registrations of the tenant
+// given as $1
+func complianceObligations(schema string) string {
+	return fmt.Sprintf(`(
+		SELECT c.*, r.client_id, r.company_name
+		FROM %[1]s.compliance_obligations c
+		JOIN %[1]s.registrations r ON r.id = c.registration_id
+		WHERE c.tenant_id = $1 AND r.deleted_at IS NULL
+	) o`, schema)
+}
+
+// scanObligation reads an obligation, and any further columns selected after
+// complianceColumns into extra
+func scanObligation(row interface{ Scan(...interface{}) error }, extra ...interface{}) (*models.ComplianceObligation, error) {
+	obligation := &models.ComplianceObligation{}
+	err := row.Scan(append([]interface{}{
+		&obligation.ID,
+		&obligation.TenantID,
+		&obligation.RegistrationID,
+		&obligation.ClientID,
+		&obligation.CompanyName,
+		&obligation.ObligationType,
+		&obligation.Jurisdiction,
+		&obligation.Authority,
+		&obligation.Title,
+		&obligation.PeriodStart,
+		&obligation.PeriodEnd,
+		&obligation.DueDate,
+		&obligation.Status,
+		&obligation.CompletedAt,
+		&obligation.CompletedBy,
+		&obligation.FilingReference,
+		&obligation.Notes,
+		&obligation.LastReminderDays,
+		&obligation.RemindedAt,
+		&obligation.CreatedAt,
+		&obligation.UpdatedAt,
+	}, extra...)...)
+	if err != nil {
+		return nil, err
+	}
+	return obligation, nil
+}
+
+// queryObligations runs a query of obligations within a tenant transaction
+func queryObligations(tx *sql.Tx, query string, args ...interface{}) ([]*models.ComplianceObligation, error) {
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	obligations := []*models.ComplianceObligation{}
+	for rows.Next() {
+		obligation, err := scanObligation(rows)
+		if err != nil {
+			return nil, err
+		}
+		obligations = append(obligations, obligation)
+	}
+	return obligations, rows.Err()
+}
+
+// Insert stores the obligations a registration's company owes, skipping any
+// already scheduled for the same period. It returns how many were new.
+func (r *ComplianceRepository) Insert(schema string, tenantID uuid.UUID, obligations []*models.ComplianceObligation) (int, error) {
+	query := fmt.Sprintf(`
+		INSERT INTO %s.compliance_obligations (
+			tenant_id, registration_id, obligation_type, jurisdiction, authority,
+			title, period_start, period_end, due_date, status
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (registration_id, obligation_type, period_start) DO NOTHING
+		RETURNING id, created_at, updated_at
+	`, schema)
+
+	inserted := 0
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		for _, obligation := range obligations {
+			err := tx.QueryRow(
+				query,
+				tenantID,
+				obligation.RegistrationID,
+				obligation.ObligationType,
+				obligation.Jurisdiction,
+				obligation.Authority,
+				obligation.Title,
+				obligation.PeriodStart,
+				obligation.PeriodEnd,
+				obligation.DueDate,
+				obligation.Status,
+			).Scan(&obligation.ID, &obligation.CreatedAt, &obligation.UpdatedAt)
+			if err == sql.ErrNoRows {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			obligation.TenantID = tenantID
+			inserted++
+		}
+		return nil
+	})
+	if err != nil {
+		return inserted, fmt.Errorf("failed to insert compliance obligations: %w", err)
+	}
+
+	return inserted, nil
+}
+
+// GetByID retrieves an obligation
+func (r *ComplianceRepository) GetByID(schema string, tenantID, obligationID uuid.UUID) (*models.ComplianceObligation, error) {
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE id = $2`, complianceColumns, complianceObligations(schema))
+
+	var obligation *models.ComplianceObligation
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		var err error
+		obligation, err = scanObligation(tx.QueryRow(query, tenantID, obligationID))
+		return err
+	})
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("compliance obligation not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get compliance obligation: %w", err)
+	}
+
+	return obligation, nil
+}
+
+// List retrieves a page of a tenant's obligations
+func (r *ComplianceRepository) List(schema string, tenantID uuid.UUID, filter ComplianceFilter, page *pagination.Query[*models.ComplianceObligation]) (*pagination.List[*models.ComplianceObligation], error) {
+	whereClause, args := complianceWhere(tenantID, filter)
+
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM %s %s`, complianceObligations(schema), whereClause)
+	countArgs := args
+
+	if keyset, values := page.Keyset(len(args) + 1); keyset != "" {
+		whereClause += " AND " + keyset
+		args = append(append([]interface{}{}, args...), values...)
+	}
+	args = append(args, page.Limit())
+
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s
+		%s
+		ORDER BY %s
+		LIMIT $%d
+	`, complianceColumns, complianceObligations(schema), whereClause, page.OrderBy(), len(args))
+
+	var total *int
+	var list []*models.ComplianceObligation
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		if page.IncludeTotal() {
+			var count int
+			if err := tx.QueryRow(countQuery, countArgs...).Scan(&count); err != nil {
+				return err
+			}
+			total = &count
+		}
+
+		var err error
+		list, err = queryObligations(tx, query, args...)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list compliance obligations: %w", err)
+	}
+
+	return page.List(list, total), nil
+}
+
+// ListAll retrieves all of a tenant's obligations matching a filter, by due
+// date, for calendars
+func (r *ComplianceRepository) ListAll(schema string, tenantID uuid.UUID, filter ComplianceFilter) ([]*models.ComplianceObligation, error) {
+	whereClause, args := complianceWhere(tenantID, filter)
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s
+		%s
+		ORDER BY due_date, id
+	`, complianceColumns, complianceObligations(schema), whereClause)
+
+	var obligations []*models.ComplianceObligation
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		var err error
+		obligations, err = queryObligations(tx, query, args...)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list compliance obligations: %w", err)
+	}
+
+	return obligations, nil
+}
+
+// complianceWhere builds the condition of a filtered obligation list. $1 is
+// the tenant.
+func complianceWhere(tenantID uuid.UUID, filter ComplianceFilter) (string, []interface{}) {
+	whereClause := "WHERE TRUE"
+	args := []interface{}{tenantID}
+
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		whereClause += fmt.Sprintf(" AND status = $%d", len(args))
+	}
+	if filter.ObligationType != "" {
+		args = append(args, filter.ObligationType)
+		whereClause += fmt.Sprintf(" AND obligation_type = $%d", len(args))
+	}
+	if filter.ClientID != nil {
+		args = append(args, *filter.ClientID)
+		whereClause += fmt.Sprintf(" AND client_id = $%d", len(args))
+	}
+	if filter.RegistrationID != nil {
+		args = append(args, *filter.RegistrationID)
+		whereClause += fmt.Sprintf(" AND registration_id = $%d", len(args))
+	}
+	if filter.DueFrom != nil {
+		args = append(args, *filter.DueFrom)
+		whereClause += fmt.Sprintf(" AND due_date >= $%d", len(args))
+	}
+	if filter.DueTo != nil {
+		args = append(args, *filter.DueTo)
+		whereClause += fmt.Sprintf(" AND due_date <= $%d", len(args))
+	}
+
+	return whereClause, args
+}
+
+// Save stores an obligation's status and filing details
+func (r *ComplianceRepository) Save(schema string, obligation *models.ComplianceObligation) error {
+	query := fmt.Sprintf(`
+		UPDATE %s.compliance_obligations
+		SET status = $1, completed_at = $2, completed_by = $3, filing_reference = $4, notes = $5
+		WHERE id = $6 AND tenant_id = $7
+		RETURNING updated_at
+	`, schema)
+
+	err := tenancy.WithTenant(r.db, obligation.TenantID, func(tx *sql.Tx) error {
+		return tx.QueryRow(
+			query,
+			obligation.Status,
+			obligation.CompletedAt,
+			obligation.CompletedBy,
+			obligation.FilingReference,
+			obligation.Notes,
+			obligation.ID,
+			obligation.TenantID,
+		).Scan(&obligation.UpdatedAt)
+	})
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("compliance obligation not found")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update compliance obligation: %w", err)
+	}
+
+	return nil
+}
+
+// ListCompletedRegistrations lists a tenant's live completed registrations,
+// whose companies owe obligations
+func (r *ComplianceRepository) ListCompletedRegistrations(schema string, tenantID uuid.UUID) ([]*models.Registration, error) {
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s.registrations
+		WHERE tenant_id = $1 AND status = 'completed' AND deleted_at IS NULL
+		ORDER BY created_at, id
+	`, registrationColumns, schema)
+
+	registrations := []*models.Registration{}
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		rows, err := tx.Query(query, tenantID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			registration, err := scanRegistration(rows)
+			if err != nil {
+				return err
+			}
+			registrations = append(registrations, registration)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list completed registrations: %w", err)
+	}
+
+	return registrations, nil
+}
+
+// MarkOverdue marks a tenant's upcoming obligations due before a date overdue
+// and returns how many it marked
+func (r *ComplianceRepository) MarkOverdue(schema string, tenantID uuid.UUID, today time.Time) (int, error) {
+	query := fmt.Sprintf(`
+		UPDATE %s.compliance_obligations SET status = 'overdue'
+		WHERE tenant_id = $1 AND status = 'upcoming' AND due_date < $2
+	`, schema)
+
+	marked := 0
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		result, err := tx.Exec(query, tenantID, today)
+		if err != nil {
+			return err
+		}
+		count, err := result.RowsAffected()
+		marked = int(count)
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark overdue compliance obligations: %w", err)
+	}
+
+	return marked, nil
+}
+
+// ListDueReminders lists a tenant's open obligations due on or before a date
+// that may still need a reminder, with the client to remind of each. Overdue
+// obligations whose overdue notice has been sent are left out.
+func (r *ComplianceRepository) ListDueReminders(schema string, tenantID uuid.UUID, until time.Time) ([]*DueReminder, error) {
+	query := fmt.Sprintf(`
+		SELECT %s, client_name, client_email FROM (
+			SELECT o.*, COALESCE(cl.company_name, cl.full_name, '') AS client_name, cl.email AS client_email
+			FROM %s
+			JOIN %s.clients cl ON cl.id = o.client_id
+		) o
+		WHERE status IN ('upcoming', 'overdue') AND due_date <= $2
+			AND (last_reminder_days IS NULL OR last_reminder_days >= 0)
+		ORDER BY due_date, id
+	`, complianceColumns, complianceObligations(schema), schema)
+
+	reminders := []*DueReminder{}
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		rows, err := tx.Query(query, tenantID, until)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			reminder := &DueReminder{}
+			reminder.Obligation, err = scanObligation(rows, &reminder.ClientName, &reminder.ClientEmail)
+			if err != nil {
+				return err
+			}
+			reminders = append(reminders, reminder)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list compliance reminders: %w", err)
+	}
+
+	return reminders, nil
+}
+
+// MarkReminded records the reminder last sent for an obligation, in days
+// before its due date, or -1 for the overdue notice
+func (r *ComplianceRepository) MarkReminded(schema string, tenantID, obligationID uuid.UUID, days int) error {
+	query := fmt.Sprintf(`
+		UPDATE %s.compliance_obligations
+		SET last_reminder_days = $1, reminded_at = NOW()
+		WHERE id = $2 AND tenant_id = $3
+	`, schema)
+
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		_, err := tx.Exec(query, days, obligationID, tenantID)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record compliance reminder: %w", err)
+	}
+
+	return nil
+}
+
+// ListActiveTenants lists the provisioned tenants whose obligations are tracked
+func (r *ComplianceRepository) ListActiveTenants() ([]uuid.UUID, error) {
+	return listActiveTenants(r.db)
+}
+
+// FeedVersion returns a tenant's current calendar feed version and whether the
+// tenant is active, as only active tenants serve their feeds
+func (r *ComplianceRepository) FeedVersion(tenantID uuid.UUID) (int, bool, error) {
+	query := `
+		SELECT compliance_feed_version,
+			status = 'active' AND provisioned_at IS NOT NULL AND deleted_at IS NULL
+		FROM public.tenants
+		WHERE id = $1
+	`
+
+	var version int
+	var active bool
+	err := r.db.QueryRow(query, tenantID).Scan(&version, &active)
+	if err == sql.ErrNoRows {
+		return 0, false, fmt.Errorf("tenant not found")
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to get calendar feed version: %w", err)
+	}
+
+	return version, active, nil
+}
+
+// RotateFeedVersion moves a tenant to a new calendar feed version, revoking
+// every feed token issued under the previous one, and returns the new version
+func (r *ComplianceRepository) RotateFeedVersion(tenantID uuid.UUID) (int, error) {
+	query := `
+		UPDATE public.tenants
+		SET compliance_feed_version = compliance_feed_version + 1
+		WHERE id = $1
+		RETURNING compliance_feed_version
+	`
+
+	var version int
+	err := r.db.QueryRow(query, tenantID).Scan(&version)
+	if err == sql.ErrNoRows {
+		return 0, fmt.Errorf("tenant not found")
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to rotate calendar feed version: %w", err)
+	}
+
+	return version, nil
+}
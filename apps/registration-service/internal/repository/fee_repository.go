@@ -0,0 +1,349 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/comply360/shared/models"
+	"github.com/comply360/shared/tenancy"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type FeeRepository struct {
+	db *sql.DB
+}
+
+func NewFeeRepository(db *sql.DB) *FeeRepository {
+	return &FeeRepository{db: db}
+}
+
+// feeScheduleColumns are the fee schedule columns read by scanFeeSchedule
+const feeScheduleColumns = `id, tenant_id, jurisdiction, registration_type, currency, vat_rate,
+			effective_from, items, created_by, created_at, updated_at`
+
+func scanFeeSchedule(row interface{ Scan(...interface{}) error }) (*models.FeeSchedule, error) {
+	schedule := &models.FeeSchedule{}
+	var itemsJSON []byte
+	err := row.Scan(
+		&schedule.ID,
+		&schedule.TenantID,
+		&schedule.Jurisdiction,
+		&schedule.RegistrationType,
+		&schedule.Currency,
+		&schedule.VATRate,
+		&schedule.EffectiveFrom,
+		&itemsJSON,
+		&schedule.CreatedBy,
+		&schedule.CreatedAt,
+		&schedule.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(itemsJSON, &schedule.Items); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal fee items: %w", err)
+	}
+	return schedule, nil
+}
+
+// quoteColumns are the quote columns read by scanQuote
+const quoteColumns = `id, tenant_id, registration_id, fee_schedule_id, currency, vat_rate,
+			add_ons, lines, subtotal, vat, total, status, locked_at, locked_by,
+			created_at, updated_at`
+
+func scanQuote(row interface{ Scan(...interface{}) error }) (*models.Quote, error) {
+	quote := &models.Quote{}
+	var linesJSON []byte
+	err := row.Scan(
+		&quote.ID,
+		&quote.TenantID,
+		&quote.RegistrationID,
+		&quote.FeeScheduleID,
+		&quote.Currency,
+		&quote.VATRate,
+		pq.Array(&quote.AddOns),
+		&linesJSON,
+		&quote.Subtotal,
+		&quote.VAT,
+		&quote.Total,
+		&quote.Status,
+		&quote.LockedAt,
+		&quote.LockedBy,
+		&quote.CreatedAt,
+		&quote.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(linesJSON, &quote.Lines); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal quote lines: %w", err)
+	}
+	return quote, nil
+}
+
+// CreateSchedule stores a fee schedule. Only one schedule for a jurisdiction
+// and registration type can take effect on a date.
+func (r *FeeRepository) CreateSchedule(schema string, schedule *models.FeeSchedule) error {
+	query := fmt.Sprintf(`
+		INSERT INTO %s.fee_schedules (
+			tenant_id, jurisdiction, registration_type, currency, vat_rate,
+			effective_from, items, created_by
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at, updated_at
+	`, schema)
+
+	itemsJSON, err := json.Marshal(schedule.Items)
+	if err != nil {
+		return fmt.Errorf("failed to marshal fee items: %w", err)
+	}
+
+	err = tenancy.WithTenant(r.db, schedule.TenantID, func(tx *sql.Tx) error {
+		return tx.QueryRow(
+			query,
+			schedule.TenantID,
+			schedule.Jurisdiction,
+			schedule.RegistrationType,
+			schedule.Currency,
+			schedule.VATRate,
+			schedule.EffectiveFrom,
+			itemsJSON,
+			schedule.CreatedBy,
+		).Scan(&schedule.ID, &schedule.CreatedAt, &schedule.UpdatedAt)
+	})
+	if isUniqueViolation(err) {
+		return fmt.Errorf("a fee schedule already takes effect on that date")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create fee schedule: %w", err)
+	}
+
+	return nil
+}
+
+// ListSchedules returns a tenant's fee schedules, latest first. An empty
+// jurisdiction or registration type matches any.
+func (r *FeeRepository) ListSchedules(schema string, tenantID uuid.UUID, jurisdiction, registrationType string) ([]*models.FeeSchedule, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s.fee_schedules
+		WHERE tenant_id = $1
+			AND ($2 = '' OR jurisdiction = $2)
+			AND ($3 = '' OR registration_type = $3)
+		ORDER BY jurisdiction, registration_type, effective_from DESC
+	`, feeScheduleColumns, schema)
+
+	schedules := []*models.FeeSchedule{}
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		rows, err := tx.Query(query, tenantID, jurisdiction, registrationType)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			schedule, err := scanFeeSchedule(rows)
+			if err != nil {
+				return err
+			}
+			schedules = append(schedules, schedule)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list fee schedules: %w", err)
+	}
+
+	return schedules, nil
+}
+
+// GetSchedule retrieves a fee schedule by ID
+func (r *FeeRepository) GetSchedule(schema string, tenantID, scheduleID uuid.UUID) (*models.FeeSchedule, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s.fee_schedules
+		WHERE id = $1 AND tenant_id = $2
+	`, feeScheduleColumns, schema)
+
+	var schedule *models.FeeSchedule
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		var err error
+		schedule, err = scanFeeSchedule(tx.QueryRow(query, scheduleID, tenantID))
+		return err
+	})
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("fee schedule not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fee schedule: %w", err)
+	}
+
+	return schedule, nil
+}
+
+// DeleteSchedule deletes a fee schedule. Quotes made from it keep their lines.
+func (r *FeeRepository) DeleteSchedule(schema string, tenantID, scheduleID uuid.UUID) error {
+	query := fmt.Sprintf(`
+		DELETE FROM %s.fee_schedules
+		WHERE id = $1 AND tenant_id = $2
+	`, schema)
+
+	var affected int64
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		result, err := tx.Exec(query, scheduleID, tenantID)
+		if err != nil {
+			return err
+		}
+		affected, err = result.RowsAffected()
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete fee schedule: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("fee schedule not found")
+	}
+
+	return nil
+}
+
+// GetQuote retrieves a registration's draft or locked quote
+func (r *FeeRepository) GetQuote(schema string, tenantID, registrationID uuid.UUID, status string) (*models.Quote, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s.registration_quotes
+		WHERE registration_id = $1 AND tenant_id = $2 AND status = $3
+	`, quoteColumns, schema)
+
+	var quote *models.Quote
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		var err error
+		quote, err = scanQuote(tx.QueryRow(query, registrationID, tenantID, status))
+		return err
+	})
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("quote not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quote: %w", err)
+	}
+
+	return quote, nil
+}
+
+// SaveDraftQuote stores a registration's draft quote, replacing any earlier
+// draft
+func (r *FeeRepository) SaveDraftQuote(schema string, quote *models.Quote) error {
+	query := fmt.Sprintf(`
+		INSERT INTO %s.registration_quotes (
+			tenant_id, registration_id, fee_schedule_id, currency, vat_rate,
+			add_ons, lines, subtotal, vat, total, status
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (registration_id, status) DO UPDATE SET
+			fee_schedule_id = EXCLUDED.fee_schedule_id,
+			currency = EXCLUDED.currency,
+			vat_rate = EXCLUDED.vat_rate,
+			add_ons = EXCLUDED.add_ons,
+			lines = EXCLUDED.lines,
+			subtotal = EXCLUDED.subtotal,
+			vat = EXCLUDED.vat,
+			total = EXCLUDED.total
+		RETURNING id, created_at, updated_at
+	`, schema)
+
+	linesJSON, err := json.Marshal(quote.Lines)
+	if err != nil {
+		return fmt.Errorf("failed to marshal quote lines: %w", err)
+	}
+
+	quote.Status = models.QuoteStatusDraft
+	err = tenancy.WithTenant(r.db, quote.TenantID, func(tx *sql.Tx) error {
+		return tx.QueryRow(
+			query,
+			quote.TenantID,
+			quote.RegistrationID,
+			quote.FeeScheduleID,
+			quote.Currency,
+			quote.VATRate,
+			pq.Array(quote.AddOns),
+			linesJSON,
+			quote.Subtotal,
+			quote.VAT,
+			quote.Total,
+			quote.Status,
+		).Scan(&quote.ID, &quote.CreatedAt, &quote.UpdatedAt)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save quote: %w", err)
+	}
+
+	return nil
+}
+
+// lockQuote locks a registration's quote as it is submitted: its draft quote
+// when it has one, or else a new quote stored locked. A registration has only
+// one locked quote, so a second submission racing the first fails.
+func lockQuote(tx *sql.Tx, schema string, quote *models.Quote) error {
+	lockedAt := time.Now().UTC()
+
+	if quote.ID != uuid.Nil {
+		query := fmt.Sprintf(`
+			UPDATE %s.registration_quotes SET
+				status = $1,
+				locked_at = $2,
+				locked_by = $3
+			WHERE id = $4 AND tenant_id = $5 AND status = $6
+			RETURNING updated_at
+		`, schema)
+
+		err := tx.QueryRow(query, models.QuoteStatusLocked, lockedAt, quote.LockedBy, quote.ID, quote.TenantID, models.QuoteStatusDraft).Scan(&quote.UpdatedAt)
+		if err == sql.ErrNoRows || isUniqueViolation(err) {
+			return fmt.Errorf("quote was changed concurrently")
+		}
+		if err != nil {
+			return fmt.Errorf("failed to lock quote: %w", err)
+		}
+	} else {
+		query := fmt.Sprintf(`
+			INSERT INTO %s.registration_quotes (
+				tenant_id, registration_id, fee_schedule_id, currency, vat_rate,
+				add_ons, lines, subtotal, vat, total, status, locked_at, locked_by
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+			RETURNING id, created_at, updated_at
+		`, schema)
+
+		linesJSON, err := json.Marshal(quote.Lines)
+		if err != nil {
+			return fmt.Errorf("failed to marshal quote lines: %w", err)
+		}
+
+		err = tx.QueryRow(
+			query,
+			quote.TenantID,
+			quote.RegistrationID,
+			quote.FeeScheduleID,
+			quote.Currency,
+			quote.VATRate,
+			pq.Array(quote.AddOns),
+			linesJSON,
+			quote.Subtotal,
+			quote.VAT,
+			quote.Total,
+			models.QuoteStatusLocked,
+			lockedAt,
+			quote.LockedBy,
+		).Scan(&quote.ID, &quote.CreatedAt, &quote.UpdatedAt)
+		if isUniqueViolation(err) {
+			return fmt.Errorf("quote was changed concurrently")
+		}
+		if err != nil {
+			return fmt.Errorf("failed to lock quote: %w", err)
+		}
+	}
+
+	quote.Status = models.QuoteStatusLocked
+	quote.LockedAt = &lockedAt
+	return nil
+}
@@ -0,0 +1,617 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/comply360/shared/models"
+	"github.com/comply360/shared/pagination"
+	"github.com/comply360/shared/tenancy"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// ImportRepository stores registration imports and their column mappings, and
+// creates the clients and registrations they import
+type ImportRepository struct {
+	db *sql.DB
+}
+
+func NewImportRepository(db *sql.DB) *ImportRepository {
+	return &ImportRepository{db: db}
+}
+
+// ImportClient is a client created by an import. ID is set once it is saved.
+type ImportClient struct {
+	Client *models.Client
+}
+
+// ImportRegistration is a registration created by an import with its initial
+// status change. A registration of a client created by the import names it in
+// Client; its client ID is filled in once the client is saved.
+type ImportRegistration struct {
+	Registration *models.Registration
+	Initial      *models.RegistrationStatusChange
+	Client       *ImportClient
+}
+
+// ImportKeys are the fields import lists sort by
+var ImportKeys = &pagination.Keys[*models.RegistrationImport]{
+	ID: func(i *models.RegistrationImport) uuid.UUID { return i.ID },
+	Columns: map[string]pagination.Column[*models.RegistrationImport]{
+		"created_at": {SQL: "created_at", Value: func(i *models.RegistrationImport) interface{} { return i.CreatedAt }},
+	},
+	Default: "-created_at",
+}
+
+// CreateMapping saves a column mapping
+func (r *ImportRepository) CreateMapping(schema string, mapping *models.RegistrationImportMapping) error {
+	query := fmt.Sprintf(`
+		INSERT INTO %s.registration_import_mappings (tenant_id, name, columns, defaults, created_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at
+	`, schema)
+
+	columns, defaults, err := marshalMapping(mapping)
+	if err != nil {
+		return err
+	}
+
+	err = tenancy.WithTenant(r.db, mapping.TenantID, func(tx *sql.Tx) error {
+		return tx.QueryRow(query, mapping.TenantID, mapping.Name, columns, defaults, mapping.CreatedBy).
+			Scan(&mapping.ID, &mapping.CreatedAt, &mapping.UpdatedAt)
+	})
+	if isUniqueViolation(err) {
+		return fmt.Errorf("import mapping name already exists")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create import mapping: %w", err)
+	}
+
+	return nil
+}
+
+// GetMapping retrieves a column mapping
+func (r *ImportRepository) GetMapping(schema string, tenantID, mappingID uuid.UUID) (*models.RegistrationImportMapping, error) {
+	var mapping *models.RegistrationImportMapping
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		mappings, err := listMappings(tx, schema, tenantID, &mappingID)
+		if err != nil {
+			return err
+		}
+		if len(mappings) == 0 {
+			return sql.ErrNoRows
+		}
+		mapping = mappings[0]
+		return nil
+	})
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("import mapping not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get import mapping: %w", err)
+	}
+
+	return mapping, nil
+}
+
+// ListMappings lists a tenant's column mappings by name
+func (r *ImportRepository) ListMappings(schema string, tenantID uuid.UUID) ([]*models.RegistrationImportMapping, error) {
+	var mappings []*models.RegistrationImportMapping
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		var err error
+		mappings, err = listMappings(tx, schema, tenantID, nil)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list import mappings: %w", err)
+	}
+
+	return mappings, nil
+}
+
+func listMappings(tx *sql.Tx, schema string, tenantID uuid.UUID, mappingID *uuid.UUID) ([]*models.RegistrationImportMapping, error) {
+	query := fmt.Sprintf(`
+		SELECT id, tenant_id, name, columns, defaults, created_by, created_at, updated_at
+		FROM %s.registration_import_mappings
+		WHERE tenant_id = $1 AND ($2::UUID IS NULL OR id = $2)
+		ORDER BY name, id
+	`, schema)
+
+	rows, err := tx.Query(query, tenantID, mappingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	mappings := []*models.RegistrationImportMapping{}
+	for rows.Next() {
+		mapping := &models.RegistrationImportMapping{}
+		var columns, defaults []byte
+		err := rows.Scan(
+			&mapping.ID,
+			&mapping.TenantID,
+			&mapping.Name,
+			&columns,
+			&defaults,
+			&mapping.CreatedBy,
+			&mapping.CreatedAt,
+			&mapping.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(columns, &mapping.Columns); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal columns: %w", err)
+		}
+		if err := json.Unmarshal(defaults, &mapping.Defaults); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal defaults: %w", err)
+		}
+		mappings = append(mappings, mapping)
+	}
+
+	return mappings, rows.Err()
+}
+
+// UpdateMapping saves a mapping's name, columns and defaults
+func (r *ImportRepository) UpdateMapping(schema string, mapping *models.RegistrationImportMapping) error {
+	query := fmt.Sprintf(`
+		UPDATE %s.registration_import_mappings
+		SET name = $3, columns = $4, defaults = $5
+		WHERE id = $1 AND tenant_id = $2
+		RETURNING updated_at
+	`, schema)
+
+	columns, defaults, err := marshalMapping(mapping)
+	if err != nil {
+		return err
+	}
+
+	err = tenancy.WithTenant(r.db, mapping.TenantID, func(tx *sql.Tx) error {
+		return tx.QueryRow(query, mapping.ID, mapping.TenantID, mapping.Name, columns, defaults).Scan(&mapping.UpdatedAt)
+	})
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("import mapping not found")
+	}
+	if isUniqueViolation(err) {
+		return fmt.Errorf("import mapping name already exists")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update import mapping: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteMapping deletes a column mapping. Imports that used it keep their results.
+func (r *ImportRepository) DeleteMapping(schema string, tenantID, mappingID uuid.UUID) error {
+	query := fmt.Sprintf(`
+		DELETE FROM %s.registration_import_mappings WHERE id = $1 AND tenant_id = $2
+	`, schema)
+
+	var deleted int64
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		result, err := tx.Exec(query, mappingID, tenantID)
+		if err != nil {
+			return err
+		}
+		deleted, err = result.RowsAffected()
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete import mapping: %w", err)
+	}
+	if deleted == 0 {
+		return fmt.Errorf("import mapping not found")
+	}
+
+	return nil
+}
+
+func marshalMapping(mapping *models.RegistrationImportMapping) ([]byte, []byte, error) {
+	if mapping.Defaults == nil {
+		mapping.Defaults = map[string]string{}
+	}
+	columns, err := json.Marshal(mapping.Columns)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal columns: %w", err)
+	}
+	defaults, err := json.Marshal(mapping.Defaults)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal defaults: %w", err)
+	}
+	return columns, defaults, nil
+}
+
+// importColumns are the import columns read by scanImport
+const importColumns = `id, tenant_id, mapping_id, file_name, format, dry_run, status,
+			requested_by, rows_total, rows_done, stats, error_count, last_error,
+			started_at, completed_at, created_at`
+
+func scanImport(row interface{ Scan(...interface{}) error }) (*models.RegistrationImport, error) {
+	record := &models.RegistrationImport{}
+	var stats []byte
+	err := row.Scan(
+		&record.ID,
+		&record.TenantID,
+		&record.MappingID,
+		&record.FileName,
+		&record.Format,
+		&record.DryRun,
+		&record.Status,
+		&record.RequestedBy,
+		&record.RowsTotal,
+		&record.RowsDone,
+		&stats,
+		&record.ErrorCount,
+		&record.LastError,
+		&record.StartedAt,
+		&record.CompletedAt,
+		&record.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(stats) > 0 {
+		if err := json.Unmarshal(stats, &record.Stats); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal stats: %w", err)
+		}
+	}
+	if record.RowsTotal > 0 {
+		record.Progress = record.RowsDone * 100 / record.RowsTotal
+	}
+	return record, nil
+}
+
+// CreateImport records a new import as pending
+func (r *ImportRepository) CreateImport(schema string, record *models.RegistrationImport) error {
+	query := fmt.Sprintf(`
+		INSERT INTO %s.registration_imports (tenant_id, mapping_id, file_name, format, dry_run, requested_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, status, created_at
+	`, schema)
+
+	err := tenancy.WithTenant(r.db, record.TenantID, func(tx *sql.Tx) error {
+		return tx.QueryRow(
+			query,
+			record.TenantID,
+			record.MappingID,
+			record.FileName,
+			record.Format,
+			record.DryRun,
+			record.RequestedBy,
+		).Scan(&record.ID, &record.Status, &record.CreatedAt)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create import: %w", err)
+	}
+
+	return nil
+}
+
+// GetImport retrieves an import's progress and result
+func (r *ImportRepository) GetImport(schema string, tenantID, importID uuid.UUID) (*models.RegistrationImport, error) {
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s.registration_imports WHERE id = $1 AND tenant_id = $2
+	`, importColumns, schema)
+
+	var record *models.RegistrationImport
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		var err error
+		record, err = scanImport(tx.QueryRow(query, importID, tenantID))
+		return err
+	})
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("import not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get import: %w", err)
+	}
+
+	return record, nil
+}
+
+// ListImports lists a page of a tenant's imports
+func (r *ImportRepository) ListImports(schema string, tenantID uuid.UUID, page *pagination.Query[*models.RegistrationImport]) (*pagination.List[*models.RegistrationImport], error) {
+	whereClause := "WHERE tenant_id = $1"
+	args := []interface{}{tenantID}
+	countArgs := args
+
+	if keyset, values := page.Keyset(2); keyset != "" {
+		whereClause += " AND " + keyset
+		args = append(args, values...)
+	}
+	args = append(args, page.Limit())
+
+	var total *int
+	var imports []*models.RegistrationImport
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		if page.IncludeTotal() {
+			var count int
+			countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM %s.registration_imports WHERE tenant_id = $1`, schema)
+			if err := tx.QueryRow(countQuery, countArgs...).Scan(&count); err != nil {
+				return err
+			}
+			total = &count
+		}
+
+		query := fmt.Sprintf(`
+			SELECT %s FROM %s.registration_imports
+			%s
+			ORDER BY %s
+			LIMIT $%d
+		`, importColumns, schema, whereClause, page.OrderBy(), len(args))
+
+		rows, err := tx.Query(query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			record, err := scanImport(rows)
+			if err != nil {
+				return err
+			}
+			imports = append(imports, record)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list imports: %w", err)
+	}
+
+	return page.List(imports, total), nil
+}
+
+// ListImportErrors returns the row errors of an import, in row order
+func (r *ImportRepository) ListImportErrors(schema string, tenantID, importID uuid.UUID) ([]models.RegistrationImportError, error) {
+	query := fmt.Sprintf(`
+		SELECT errors FROM %s.registration_imports WHERE id = $1 AND tenant_id = $2
+	`, schema)
+
+	var data []byte
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		return tx.QueryRow(query, importID, tenantID).Scan(&data)
+	})
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("import not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get import errors: %w", err)
+	}
+
+	problems := []models.RegistrationImportError{}
+	if err := json.Unmarshal(data, &problems); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal import errors: %w", err)
+	}
+	return problems, nil
+}
+
+// StartImport marks an import as running
+func (r *ImportRepository) StartImport(schema string, tenantID, importID uuid.UUID) error {
+	return r.updateImport(schema, tenantID, importID, `status = 'running', started_at = NOW()`)
+}
+
+// UpdateImportProgress records how many rows an import has read and finished
+func (r *ImportRepository) UpdateImportProgress(schema string, tenantID, importID uuid.UUID, rowsTotal, rowsDone int) error {
+	return r.updateImport(schema, tenantID, importID, `rows_total = $3, rows_done = $4`, rowsTotal, rowsDone)
+}
+
+// CompleteImport records the result of a finished import
+func (r *ImportRepository) CompleteImport(schema string, tenantID, importID uuid.UUID, stats *models.RegistrationImportStats, problems []models.RegistrationImportError) error {
+	statsJSON, problemsJSON, err := marshalResult(stats, problems)
+	if err != nil {
+		return err
+	}
+	return r.updateImport(schema, tenantID, importID,
+		`status = 'completed', rows_done = rows_total, stats = $3, errors = $4, error_count = $5, completed_at = NOW()`,
+		statsJSON, problemsJSON, len(problems))
+}
+
+// FailImport records why an import stopped, with the rows it got through
+func (r *ImportRepository) FailImport(schema string, tenantID, importID uuid.UUID, message string, stats *models.RegistrationImportStats, problems []models.RegistrationImportError) error {
+	statsJSON, problemsJSON, err := marshalResult(stats, problems)
+	if err != nil {
+		return err
+	}
+	return r.updateImport(schema, tenantID, importID,
+		`status = 'failed', last_error = $3, stats = $4, errors = $5, error_count = $6, completed_at = NOW()`,
+		message, statsJSON, problemsJSON, len(problems))
+}
+
+func (r *ImportRepository) updateImport(schema string, tenantID, importID uuid.UUID, set string, args ...interface{}) error {
+	query := fmt.Sprintf(`
+		UPDATE %s.registration_imports SET %s WHERE id = $1 AND tenant_id = $2
+	`, schema, set)
+
+	return tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		_, err := tx.Exec(query, append([]interface{}{importID, tenantID}, args...)...)
+		return err
+	})
+}
+
+func marshalResult(stats *models.RegistrationImportStats, problems []models.RegistrationImportError) ([]byte, []byte, error) {
+	if problems == nil {
+		problems = []models.RegistrationImportError{}
+	}
+	var statsJSON []byte
+	if stats != nil {
+		var err error
+		if statsJSON, err = json.Marshal(stats); err != nil {
+			return nil, nil, fmt.Errorf("failed to marshal stats: %w", err)
+		}
+	}
+	problemsJSON, err := json.Marshal(problems)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal import errors: %w", err)
+	}
+	return statsJSON, problemsJSON, nil
+}
+
+// FailInterruptedImports fails the imports of every active tenant that were in
+// flight when the service last stopped, and returns how many there were
+func (r *ImportRepository) FailInterruptedImports() (int, error) {
+	tenantIDs, err := listActiveTenants(r.db)
+	if err != nil {
+		return 0, err
+	}
+
+	failed := 0
+	for _, tenantID := range tenantIDs {
+		tenant := models.Tenant{ID: tenantID}
+		schema := tenant.TenantSchema()
+		query := fmt.Sprintf(`
+			UPDATE %s.registration_imports
+			SET status = 'failed', last_error = 'import was interrupted', completed_at = NOW()
+			WHERE tenant_id = $1 AND status IN ('pending', 'running')
+		`, schema)
+
+		err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+			result, err := tx.Exec(query, tenantID)
+			if err != nil {
+				return err
+			}
+			count, err := result.RowsAffected()
+			failed += int(count)
+			return err
+		})
+		if err != nil {
+			return failed, fmt.Errorf("failed to recover imports of tenant %s: %w", tenantID, err)
+		}
+	}
+
+	return failed, nil
+}
+
+// ClientsByEmail finds existing clients by email address, ignoring case. When
+// several clients share an address the oldest is returned.
+func (r *ImportRepository) ClientsByEmail(schema string, tenantID uuid.UUID, emails []string) (map[string]uuid.UUID, error) {
+	query := fmt.Sprintf(`
+		SELECT DISTINCT ON (lower(email)) lower(email), id
+		FROM %s.clients
+		WHERE tenant_id = $1 AND deleted_at IS NULL AND lower(email) = ANY($2)
+		ORDER BY lower(email), created_at, id
+	`, schema)
+
+	lowered := make([]string, len(emails))
+	for i, email := range emails {
+		lowered[i] = strings.ToLower(email)
+	}
+
+	found := map[string]uuid.UUID{}
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		rows, err := tx.Query(query, tenantID, pq.Array(lowered))
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var email string
+			var id uuid.UUID
+			if err := rows.Scan(&email, &id); err != nil {
+				return err
+			}
+			found[email] = id
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find clients: %w", err)
+	}
+
+	return found, nil
+}
+
+// ExistingClients returns which of the given client IDs exist
+func (r *ImportRepository) ExistingClients(schema string, tenantID uuid.UUID, ids []uuid.UUID) (map[uuid.UUID]bool, error) {
+	query := fmt.Sprintf(`
+		SELECT id FROM %s.clients
+		WHERE tenant_id = $1 AND deleted_at IS NULL AND id = ANY($2::UUID[])
+	`, schema)
+
+	values := make([]string, len(ids))
+	for i, id := range ids {
+		values[i] = id.String()
+	}
+
+	found := map[uuid.UUID]bool{}
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		rows, err := tx.Query(query, tenantID, pq.Array(values))
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var id uuid.UUID
+			if err := rows.Scan(&id); err != nil {
+				return err
+			}
+			found[id] = true
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find clients: %w", err)
+	}
+
+	return found, nil
+}
+
+// CreateBatch creates a batch of imported clients and registrations in one
+// transaction, clients first. Nothing in the batch is saved if any row fails,
+// and the batch's clients are left without IDs.
+func (r *ImportRepository) CreateBatch(schema string, tenantID uuid.UUID, clients []*ImportClient, registrations []*ImportRegistration) error {
+	clientQuery := fmt.Sprintf(`
+		INSERT INTO %s.clients (
+			tenant_id, client_type, full_name, company_name, id_number,
+			tax_number, vat_number, email, phone, mobile, country
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id, status, created_at, updated_at
+	`, schema)
+
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		for _, imported := range clients {
+			client := imported.Client
+			err := tx.QueryRow(
+				clientQuery,
+				tenantID,
+				client.ClientType,
+				client.FullName,
+				client.CompanyName,
+				client.IDNumber,
+				client.TaxNumber,
+				client.VATNumber,
+				client.Email,
+				client.Phone,
+				client.Mobile,
+				client.Country,
+			).Scan(&client.ID, &client.Status, &client.CreatedAt, &client.UpdatedAt)
+			if err != nil {
+				return fmt.Errorf("failed to create client %s: %w", client.Email, err)
+			}
+		}
+
+		for _, imported := range registrations {
+			if imported.Client != nil {
+				imported.Registration.ClientID = imported.Client.Client.ID
+			}
+			if err := insertRegistration(tx, schema, imported.Registration, imported.Initial); err != nil {
+				return fmt.Errorf("failed to create registration %s: %w", imported.Registration.CompanyName, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		for _, imported := range clients {
+			imported.Client.ID = uuid.Nil
+		}
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,352 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/comply360/registration-service/internal/names"
+	"github.com/comply360/shared/models"
+	"github.com/comply360/shared/tenancy"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// maxExistingNames bounds how many existing registration names a proposed name
+// is screened against
+const maxExistingNames = 50
+
+type NameReservationRepository struct {
+	db *sql.DB
+}
+
+func NewNameReservationRepository(db *sql.DB) *NameReservationRepository {
+	return &NameReservationRepository{db: db}
+}
+
+// nameReservationColumns are the name reservation columns read by scanNameReservation
+const nameReservationColumns = `id, tenant_id, registration_id, status, names, requested_by,
+			jurisdiction, cipc_reference, dcip_reference, reserved_name, last_error, submitted_at, last_checked_at,
+			decided_at, expires_at, created_at, updated_at`
+
+func scanNameReservation(row interface{ Scan(...interface{}) error }) (*models.NameReservation, error) {
+	reservation := &models.NameReservation{}
+	var namesJSON []byte
+	err := row.Scan(
+		&reservation.ID,
+		&reservation.TenantID,
+		&reservation.RegistrationID,
+		&reservation.Status,
+		&namesJSON,
+		&reservation.RequestedBy,
+		&reservation.Jurisdiction,
+		&reservation.CIPCReference,
+		&reservation.DCIPReference,
+		&reservation.ReservedName,
+		&reservation.LastError,
+		&reservation.SubmittedAt,
+		&reservation.LastCheckedAt,
+		&reservation.DecidedAt,
+		&reservation.ExpiresAt,
+		&reservation.CreatedAt,
+		&reservation.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(namesJSON, &reservation.Names); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal proposed names: %w", err)
+	}
+	return reservation, nil
+}
+
+// Create stores a new name reservation. A registration has at most one open
+// reservation.
+func (r *NameReservationRepository) Create(schema string, reservation *models.NameReservation) error {
+	namesJSON, err := json.Marshal(reservation.Names)
+	if err != nil {
+		return fmt.Errorf("failed to marshal proposed names: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s.name_reservations (tenant_id, registration_id, status, names, requested_by, jurisdiction)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at, updated_at
+	`, schema)
+
+	err = tenancy.WithTenant(r.db, reservation.TenantID, func(tx *sql.Tx) error {
+		return tx.QueryRow(
+			query,
+			reservation.TenantID,
+			reservation.RegistrationID,
+			reservation.Status,
+			namesJSON,
+			reservation.RequestedBy,
+			reservation.Jurisdiction,
+		).Scan(&reservation.ID, &reservation.CreatedAt, &reservation.UpdatedAt)
+	})
+	if isUniqueViolation(err) {
+		return fmt.Errorf("registration already has an open name reservation")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create name reservation: %w", err)
+	}
+
+	return nil
+}
+
+// Latest retrieves a registration's most recent name reservation
+func (r *NameReservationRepository) Latest(schema string, tenantID, registrationID uuid.UUID) (*models.NameReservation, error) {
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s.name_reservations
+		WHERE registration_id = $1 AND tenant_id = $2
+		ORDER BY created_at DESC, id DESC
+		LIMIT 1
+	`, nameReservationColumns, schema)
+
+	var reservation *models.NameReservation
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		var err error
+		reservation, err = scanNameReservation(tx.QueryRow(query, registrationID, tenantID))
+		return err
+	})
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("name reservation not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get name reservation: %w", err)
+	}
+
+	return reservation, nil
+}
+
+// List retrieves a registration's name reservations, newest first
+func (r *NameReservationRepository) List(schema string, tenantID, registrationID uuid.UUID) ([]*models.NameReservation, error) {
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s.name_reservations
+		WHERE registration_id = $1 AND tenant_id = $2
+		ORDER BY created_at DESC, id DESC
+	`, nameReservationColumns, schema)
+
+	reservations := []*models.NameReservation{}
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		rows, err := tx.Query(query, registrationID, tenantID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			reservation, err := scanNameReservation(rows)
+			if err != nil {
+				return err
+			}
+			reservations = append(reservations, reservation)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list name reservations: %w", err)
+	}
+
+	return reservations, nil
+}
+
+// Save stores a name reservation's names, registry progress and decision. A
+// DCIP reference is copied to the registration, and approving a reservation
+// gives the registration the reserved name and marks its name reserved, in the
+// same transaction.
+func (r *NameReservationRepository) Save(schema string, reservation *models.NameReservation) error {
+	namesJSON, err := json.Marshal(reservation.Names)
+	if err != nil {
+		return fmt.Errorf("failed to marshal proposed names: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE %s.name_reservations
+		SET status = $1, names = $2, requested_by = $3, cipc_reference = $4,
+			dcip_reference = $5, reserved_name = $6, last_error = $7, submitted_at = $8,
+			last_checked_at = $9, decided_at = $10, expires_at = $11
+		WHERE id = $12 AND tenant_id = $13
+		RETURNING updated_at
+	`, schema)
+
+	err = tenancy.WithTenant(r.db, reservation.TenantID, func(tx *sql.Tx) error {
+		err := tx.QueryRow(
+			query,
+			reservation.Status,
+			namesJSON,
+			reservation.RequestedBy,
+			reservation.CIPCReference,
+			reservation.DCIPReference,
+			reservation.ReservedName,
+			reservation.LastError,
+			reservation.SubmittedAt,
+			reservation.LastCheckedAt,
+			reservation.DecidedAt,
+			reservation.ExpiresAt,
+			reservation.ID,
+			reservation.TenantID,
+		).Scan(&reservation.UpdatedAt)
+		if err != nil {
+			return err
+		}
+
+		if reservation.DCIPReference != nil {
+			_, err = tx.Exec(fmt.Sprintf(`
+				UPDATE %s.registrations SET dcip_reference = $1
+				WHERE id = $2 AND tenant_id = $3
+			`, schema), reservation.DCIPReference, reservation.RegistrationID, reservation.TenantID)
+			if err != nil {
+				return err
+			}
+		}
+
+		if reservation.Status != models.NameReservationStatusApproved {
+			return nil
+		}
+		_, err = tx.Exec(fmt.Sprintf(`
+			UPDATE %s.registrations
+			SET company_name = $1, name_reserved_at = $2
+			WHERE id = $3 AND tenant_id = $4
+		`, schema), reservation.ReservedName, reservation.DecidedAt, reservation.RegistrationID, reservation.TenantID)
+		return err
+	})
+	if isUniqueViolation(err) {
+		return fmt.Errorf("registry reference is already used by another name reservation")
+	}
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("name reservation not found")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update name reservation: %w", err)
+	}
+
+	return nil
+}
+
+// ExistingNames returns the company names of the tenant's other live
+// registrations that resemble any of the proposed names, for screening
+func (r *NameReservationRepository) ExistingNames(schema string, tenantID, registrationID uuid.UUID, proposed []string) ([]names.Existing, error) {
+	query := fmt.Sprintf(`
+		SELECT r.id, r.company_name
+		FROM %s.registrations r
+		WHERE r.tenant_id = $1 AND r.id <> $2 AND r.deleted_at IS NULL
+			AND r.status NOT IN ('rejected', 'cancelled')
+			AND EXISTS (
+				SELECT 1 FROM unnest($3::text[]) AS p(name)
+				WHERE r.company_name %% p.name
+			)
+		ORDER BY r.created_at DESC
+		LIMIT %d
+	`, schema, maxExistingNames*len(proposed))
+
+	existing := []names.Existing{}
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		rows, err := tx.Query(query, tenantID, registrationID, pq.Array(proposed))
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var name names.Existing
+			if err := rows.Scan(&name.RegistrationID, &name.Name); err != nil {
+				return err
+			}
+			existing = append(existing, name)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list existing names: %w", err)
+	}
+
+	return existing, nil
+}
+
+// ListSubmitted returns the reservations of every active tenant that are
+// waiting on their registry's decision, least recently checked first
+func (r *NameReservationRepository) ListSubmitted() ([]*models.NameReservation, error) {
+	tenantIDs, err := listActiveTenants(r.db)
+	if err != nil {
+		return nil, err
+	}
+
+	reservations := []*models.NameReservation{}
+	for _, tenantID := range tenantIDs {
+		tenant := models.Tenant{ID: tenantID}
+		query := fmt.Sprintf(`
+			SELECT %s FROM %s.name_reservations
+			WHERE tenant_id = $1 AND status = 'submitted'
+			ORDER BY last_checked_at NULLS FIRST, submitted_at
+		`, nameReservationColumns, tenant.TenantSchema())
+
+		err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+			rows, err := tx.Query(query, tenantID)
+			if err != nil {
+				return err
+			}
+			defer rows.Close()
+
+			for rows.Next() {
+				reservation, err := scanNameReservation(rows)
+				if err != nil {
+					return err
+				}
+				reservations = append(reservations, reservation)
+			}
+			return rows.Err()
+		})
+		if err != nil {
+			return reservations, fmt.Errorf("failed to list name reservations of tenant %s: %w", tenantID, err)
+		}
+	}
+
+	return reservations, nil
+}
+
+// ExpireReservations expires the approved reservations of every active tenant
+// that their registry no longer holds, unless the company has since been registered,
+// and clears their registrations' reserved name. It returns how many expired.
+func (r *NameReservationRepository) ExpireReservations() (int, error) {
+	tenantIDs, err := listActiveTenants(r.db)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, tenantID := range tenantIDs {
+		tenant := models.Tenant{ID: tenantID}
+		schema := tenant.TenantSchema()
+		query := fmt.Sprintf(`
+			WITH expired AS (
+				UPDATE %s.name_reservations n
+				SET status = 'expired'
+				FROM %s.registrations r
+				WHERE n.registration_id = r.id AND n.tenant_id = $1
+					AND n.status = 'approved' AND n.expires_at <= NOW()
+					AND r.status <> 'completed'
+				RETURNING n.registration_id
+			)
+			UPDATE %s.registrations
+			SET name_reserved_at = NULL
+			WHERE id IN (SELECT registration_id FROM expired)
+		`, schema, schema, schema)
+
+		err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+			result, err := tx.Exec(query, tenantID)
+			if err != nil {
+				return err
+			}
+			count, err := result.RowsAffected()
+			expired += int(count)
+			return err
+		})
+		if err != nil {
+			return expired, fmt.Errorf("failed to expire name reservations of tenant %s: %w", tenantID, err)
+		}
+	}
+
+	return expired, nil
+}
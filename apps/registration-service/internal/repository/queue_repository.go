@@ -0,0 +1,695 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/comply360/shared/models"
+	"github.com/comply360/shared/pagination"
+	"github.com/comply360/shared/tenancy"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// openStatuses are the statuses that count towards a reviewer's load
+const openStatuses = `('submitted', 'in_review')`
+
+// QueueRepository stores review queues, assignments and SLA targets
+type QueueRepository struct {
+	db *sql.DB
+}
+
+func NewQueueRepository(db *sql.DB) *QueueRepository {
+	return &QueueRepository{db: db}
+}
+
+// QueueFilter narrows a list of registrations in review. Zero values match
+// everything.
+type QueueFilter struct {
+	AssignedTo       *uuid.UUID
+	QueueID          *uuid.UUID
+	Status           string
+	RegistrationType string
+	// OverdueOnly keeps registrations past their SLA due time
+	OverdueOnly bool
+}
+
+// CreateQueue creates a review queue
+func (r *QueueRepository) CreateQueue(schema string, queue *models.ReviewQueue) error {
+	query := fmt.Sprintf(`
+		INSERT INTO %s.review_queues (tenant_id, name, strategy, registration_types, is_active)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at
+	`, schema)
+
+	err := tenancy.WithTenant(r.db, queue.TenantID, func(tx *sql.Tx) error {
+		return tx.QueryRow(
+			query,
+			queue.TenantID,
+			queue.Name,
+			queue.Strategy,
+			pq.Array(queue.RegistrationTypes),
+			queue.IsActive,
+		).Scan(&queue.ID, &queue.CreatedAt, &queue.UpdatedAt)
+	})
+	if isUniqueViolation(err) {
+		return fmt.Errorf("review queue name already exists")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create review queue: %w", err)
+	}
+
+	return nil
+}
+
+// GetQueue retrieves a review queue with its members
+func (r *QueueRepository) GetQueue(schema string, tenantID, queueID uuid.UUID) (*models.ReviewQueue, error) {
+	var queue *models.ReviewQueue
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		queues, err := listQueues(tx, schema, tenantID, &queueID)
+		if err != nil {
+			return err
+		}
+		if len(queues) == 0 {
+			return sql.ErrNoRows
+		}
+		queue = queues[0]
+		return nil
+	})
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("review queue not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get review queue: %w", err)
+	}
+
+	return queue, nil
+}
+
+// ListQueues lists a tenant's review queues with their members, oldest first
+func (r *QueueRepository) ListQueues(schema string, tenantID uuid.UUID) ([]*models.ReviewQueue, error) {
+	var queues []*models.ReviewQueue
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		var err error
+		queues, err = listQueues(tx, schema, tenantID, nil)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list review queues: %w", err)
+	}
+
+	return queues, nil
+}
+
+// listQueues reads queues, or one queue, with their members in the order they
+// joined and each member's load
+func listQueues(tx *sql.Tx, schema string, tenantID uuid.UUID, queueID *uuid.UUID) ([]*models.ReviewQueue, error) {
+	query := fmt.Sprintf(`
+		SELECT id, tenant_id, name, strategy, registration_types, is_active,
+			last_assigned_to, created_at, updated_at
+		FROM %s.review_queues
+		WHERE tenant_id = $1 AND ($2::UUID IS NULL OR id = $2)
+		ORDER BY created_at, id
+	`, schema)
+
+	rows, err := tx.Query(query, tenantID, queueID)
+	if err != nil {
+		return nil, err
+	}
+
+	queues := []*models.ReviewQueue{}
+	byID := map[uuid.UUID]*models.ReviewQueue{}
+	for rows.Next() {
+		queue := &models.ReviewQueue{Members: []*models.ReviewQueueMember{}}
+		err := rows.Scan(
+			&queue.ID,
+			&queue.TenantID,
+			&queue.Name,
+			&queue.Strategy,
+			pq.Array(&queue.RegistrationTypes),
+			&queue.IsActive,
+			&queue.LastAssignedTo,
+			&queue.CreatedAt,
+			&queue.UpdatedAt,
+		)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		queues = append(queues, queue)
+		byID[queue.ID] = queue
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	membersQuery := fmt.Sprintf(`
+		SELECT m.queue_id, m.user_id, m.skills, m.max_load, m.is_active, m.created_at,
+			(SELECT COUNT(*) FROM %s.registrations r
+			 WHERE r.assigned_to = m.user_id AND r.status IN %s AND r.deleted_at IS NULL)
+		FROM %s.review_queue_members m
+		WHERE m.tenant_id = $1 AND ($2::UUID IS NULL OR m.queue_id = $2)
+		ORDER BY m.created_at, m.user_id
+	`, schema, openStatuses, schema)
+
+	rows, err = tx.Query(membersQuery, tenantID, queueID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		member := &models.ReviewQueueMember{}
+		err := rows.Scan(
+			&member.QueueID,
+			&member.UserID,
+			pq.Array(&member.Skills),
+			&member.MaxLoad,
+			&member.IsActive,
+			&member.CreatedAt,
+			&member.Load,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if queue, ok := byID[member.QueueID]; ok {
+			queue.Members = append(queue.Members, member)
+		}
+	}
+
+	return queues, rows.Err()
+}
+
+// UpdateQueue updates a review queue's settings
+func (r *QueueRepository) UpdateQueue(schema string, queue *models.ReviewQueue) error {
+	query := fmt.Sprintf(`
+		UPDATE %s.review_queues SET
+			name = $1,
+			strategy = $2,
+			registration_types = $3,
+			is_active = $4
+		WHERE id = $5 AND tenant_id = $6
+		RETURNING updated_at
+	`, schema)
+
+	err := tenancy.WithTenant(r.db, queue.TenantID, func(tx *sql.Tx) error {
+		return tx.QueryRow(
+			query,
+			queue.Name,
+			queue.Strategy,
+			pq.Array(queue.RegistrationTypes),
+			queue.IsActive,
+			queue.ID,
+			queue.TenantID,
+		).Scan(&queue.UpdatedAt)
+	})
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("review queue not found")
+	}
+	if isUniqueViolation(err) {
+		return fmt.Errorf("review queue name already exists")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update review queue: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteQueue deletes a review queue. Its registrations keep their reviewer and
+// leave the queue.
+func (r *QueueRepository) DeleteQueue(schema string, tenantID, queueID uuid.UUID) error {
+	query := fmt.Sprintf(`DELETE FROM %s.review_queues WHERE id = $1 AND tenant_id = $2`, schema)
+
+	var result sql.Result
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		var err error
+		result, err = tx.Exec(query, queueID, tenantID)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete review queue: %w", err)
+	}
+
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return fmt.Errorf("review queue not found")
+	}
+	return nil
+}
+
+// SetMember adds a reviewer to a queue or updates their membership
+func (r *QueueRepository) SetMember(schema string, tenantID uuid.UUID, member *models.ReviewQueueMember) error {
+	query := fmt.Sprintf(`
+		INSERT INTO %s.review_queue_members (queue_id, user_id, tenant_id, skills, max_load, is_active)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (queue_id, user_id) DO UPDATE SET
+			skills = EXCLUDED.skills,
+			max_load = EXCLUDED.max_load,
+			is_active = EXCLUDED.is_active
+		RETURNING created_at
+	`, schema)
+
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		return tx.QueryRow(
+			query,
+			member.QueueID,
+			member.UserID,
+			tenantID,
+			pq.Array(member.Skills),
+			member.MaxLoad,
+			member.IsActive,
+		).Scan(&member.CreatedAt)
+	})
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+		if strings.Contains(pqErr.Constraint, "user_id") {
+			return fmt.Errorf("user not found")
+		}
+		return fmt.Errorf("review queue not found")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set review queue member: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveMember removes a reviewer from a queue
+func (r *QueueRepository) RemoveMember(schema string, tenantID, queueID, userID uuid.UUID) error {
+	query := fmt.Sprintf(`
+		DELETE FROM %s.review_queue_members
+		WHERE queue_id = $1 AND user_id = $2 AND tenant_id = $3
+	`, schema)
+
+	var result sql.Result
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		var err error
+		result, err = tx.Exec(query, queueID, userID, tenantID)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to remove review queue member: %w", err)
+	}
+
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return fmt.Errorf("review queue member not found")
+	}
+	return nil
+}
+
+// Assign assigns a registration to a reviewer and records the assignment, in one
+// transaction. An automatic assignment only applies to an unassigned
+// registration and moves the queue's round-robin position.
+func (r *QueueRepository) Assign(schema string, tenantID uuid.UUID, assignment *models.RegistrationAssignment) error {
+	lockQuery := fmt.Sprintf(`
+		SELECT assigned_to FROM %s.registrations
+		WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL
+		FOR UPDATE
+	`, schema)
+	updateQuery := fmt.Sprintf(`
+		UPDATE %s.registrations SET
+			assigned_to = $1,
+			queue_id = $2,
+			assigned_at = NOW(),
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $3 AND tenant_id = $4
+	`, schema)
+	insertQuery := fmt.Sprintf(`
+		INSERT INTO %s.registration_assignments (
+			tenant_id, registration_id, queue_id, from_user, to_user,
+			method, strategy, assigned_by, reason
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, created_at
+	`, schema)
+	cursorQuery := fmt.Sprintf(`
+		UPDATE %s.review_queues SET last_assigned_to = $1 WHERE id = $2 AND tenant_id = $3
+	`, schema)
+
+	auto := assignment.Method == models.AssignmentMethodAuto
+	alreadyAssigned := false
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		if err := tx.QueryRow(lockQuery, assignment.RegistrationID, tenantID).Scan(&assignment.FromUser); err != nil {
+			return err
+		}
+		if auto && assignment.FromUser != nil {
+			alreadyAssigned = true
+			return nil
+		}
+
+		if _, err := tx.Exec(updateQuery, assignment.ToUser, assignment.QueueID, assignment.RegistrationID, tenantID); err != nil {
+			return err
+		}
+
+		err := tx.QueryRow(
+			insertQuery,
+			tenantID,
+			assignment.RegistrationID,
+			assignment.QueueID,
+			assignment.FromUser,
+			assignment.ToUser,
+			assignment.Method,
+			assignment.Strategy,
+			assignment.AssignedBy,
+			assignment.Reason,
+		).Scan(&assignment.ID, &assignment.CreatedAt)
+		if err != nil {
+			return err
+		}
+
+		if auto && assignment.QueueID != nil {
+			if _, err := tx.Exec(cursorQuery, assignment.ToUser, assignment.QueueID, tenantID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("registration not found")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to assign registration: %w", err)
+	}
+	if alreadyAssigned {
+		return fmt.Errorf("registration is already assigned")
+	}
+
+	return nil
+}
+
+// ListAssignments lists a registration's assignments, oldest first
+func (r *QueueRepository) ListAssignments(schema string, tenantID, registrationID uuid.UUID) ([]*models.RegistrationAssignment, error) {
+	query := fmt.Sprintf(`
+		SELECT id, registration_id, queue_id, from_user, to_user, method,
+			strategy, assigned_by, reason, created_at
+		FROM %s.registration_assignments
+		WHERE registration_id = $1 AND tenant_id = $2
+		ORDER BY created_at, id
+	`, schema)
+
+	assignments := []*models.RegistrationAssignment{}
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		rows, err := tx.Query(query, registrationID, tenantID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			assignment := &models.RegistrationAssignment{}
+			err := rows.Scan(
+				&assignment.ID,
+				&assignment.RegistrationID,
+				&assignment.QueueID,
+				&assignment.FromUser,
+				&assignment.ToUser,
+				&assignment.Method,
+				&assignment.Strategy,
+				&assignment.AssignedBy,
+				&assignment.Reason,
+				&assignment.CreatedAt,
+			)
+			if err != nil {
+				return err
+			}
+			assignments = append(assignments, assignment)
+		}
+
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list assignments: %w", err)
+	}
+
+	return assignments, nil
+}
+
+// QueueKeys are the fields lists of registrations in review sort by, soonest
+// due first by default
+var QueueKeys = &pagination.Keys[*models.Registration]{
+	ID: func(r *models.Registration) uuid.UUID { return r.ID },
+	Columns: map[string]pagination.Column[*models.Registration]{
+		"sla_due_at": nullableTime("sla_due_at", "infinity", func(r *models.Registration) *time.Time { return r.SLADueAt }),
+		"created_at": {SQL: "created_at", Value: func(r *models.Registration) interface{} { return r.CreatedAt }},
+		"updated_at": {SQL: "updated_at", Value: func(r *models.Registration) interface{} { return r.UpdatedAt }},
+		"status":     {SQL: "status", Value: func(r *models.Registration) interface{} { return r.Status }},
+	},
+	Default: "sla_due_at",
+}
+
+// ListRegistrations retrieves a page of registrations in review matching a
+// filter after the page's cursor
+func (r *QueueRepository) ListRegistrations(schema string, tenantID uuid.UUID, filter QueueFilter, page *pagination.Query[*models.Registration]) (*pagination.List[*models.Registration], error) {
+	whereClause := fmt.Sprintf("WHERE tenant_id = $1 AND deleted_at IS NULL AND status IN %s", openStatuses)
+	args := []interface{}{tenantID}
+
+	add := func(condition string, value interface{}) {
+		args = append(args, value)
+		whereClause += fmt.Sprintf(" AND "+condition, len(args))
+	}
+	if filter.AssignedTo != nil {
+		add("assigned_to = $%d", *filter.AssignedTo)
+	}
+	if filter.QueueID != nil {
+		add("queue_id = $%d", *filter.QueueID)
+	}
+	if filter.Status != "" {
+		add("status = $%d", filter.Status)
+	}
+	if filter.RegistrationType != "" {
+		add("registration_type = $%d", filter.RegistrationType)
+	}
+	if filter.OverdueOnly {
+		whereClause += " AND sla_due_at <= NOW()"
+	}
+
+	// Count total, only when asked for
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM %s.registrations %s`, schema, whereClause)
+	countArgs := args
+
+	// Continue after the cursor
+	if keyset, values := page.Keyset(len(args) + 1); keyset != "" {
+		whereClause += " AND " + keyset
+		args = append(append([]interface{}{}, args...), values...)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s.registrations
+		%s
+		ORDER BY %s
+		LIMIT $%d
+	`, registrationColumns, schema, whereClause, page.OrderBy(), len(args)+1)
+	args = append(args, page.Limit())
+
+	var total *int
+	var registrations []*models.Registration
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		if page.IncludeTotal() {
+			var count int
+			if err := tx.QueryRow(countQuery, countArgs...).Scan(&count); err != nil {
+				return err
+			}
+			total = &count
+		}
+
+		rows, err := tx.Query(query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			registration, err := scanRegistration(rows)
+			if err != nil {
+				return err
+			}
+			registrations = append(registrations, registration)
+		}
+
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list registrations in review: %w", err)
+	}
+
+	return page.List(registrations, total), nil
+}
+
+// ListSLATargets lists a tenant's SLA targets
+func (r *QueueRepository) ListSLATargets(schema string, tenantID uuid.UUID) ([]*models.SLATarget, error) {
+	query := fmt.Sprintf(`
+		SELECT id, tenant_id, status, registration_type, target_hours,
+			business_hours, created_at, updated_at
+		FROM %s.sla_targets
+		WHERE tenant_id = $1
+		ORDER BY status, registration_type NULLS FIRST
+	`, schema)
+
+	targets := []*models.SLATarget{}
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		rows, err := tx.Query(query, tenantID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			target := &models.SLATarget{}
+			err := rows.Scan(
+				&target.ID,
+				&target.TenantID,
+				&target.Status,
+				&target.RegistrationType,
+				&target.TargetHours,
+				&target.BusinessHours,
+				&target.CreatedAt,
+				&target.UpdatedAt,
+			)
+			if err != nil {
+				return err
+			}
+			targets = append(targets, target)
+		}
+
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list SLA targets: %w", err)
+	}
+
+	return targets, nil
+}
+
+// SetSLATarget creates or replaces the target of a status and registration type
+func (r *QueueRepository) SetSLATarget(schema string, target *models.SLATarget) error {
+	query := fmt.Sprintf(`
+		INSERT INTO %s.sla_targets (tenant_id, status, registration_type, target_hours, business_hours)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (tenant_id, status, COALESCE(registration_type, '')) DO UPDATE SET
+			target_hours = EXCLUDED.target_hours,
+			business_hours = EXCLUDED.business_hours
+		RETURNING id, created_at, updated_at
+	`, schema)
+
+	err := tenancy.WithTenant(r.db, target.TenantID, func(tx *sql.Tx) error {
+		return tx.QueryRow(
+			query,
+			target.TenantID,
+			target.Status,
+			target.RegistrationType,
+			target.TargetHours,
+			target.BusinessHours,
+		).Scan(&target.ID, &target.CreatedAt, &target.UpdatedAt)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set SLA target: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteSLATarget deletes an SLA target, restoring the default for its status
+func (r *QueueRepository) DeleteSLATarget(schema string, tenantID, targetID uuid.UUID) error {
+	query := fmt.Sprintf(`DELETE FROM %s.sla_targets WHERE id = $1 AND tenant_id = $2`, schema)
+
+	var result sql.Result
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		var err error
+		result, err = tx.Exec(query, targetID, tenantID)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete SLA target: %w", err)
+	}
+
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return fmt.Errorf("SLA target not found")
+	}
+	return nil
+}
+
+// MarkBreaches flags registrations that have outstayed their SLA target and
+// returns them. Each breach is flagged once per status.
+func (r *QueueRepository) MarkBreaches(schema string, tenantID uuid.UUID) ([]*models.SLABreach, error) {
+	query := fmt.Sprintf(`
+		UPDATE %s.registrations SET sla_breached_at = NOW()
+		WHERE tenant_id = $1 AND deleted_at IS NULL
+		  AND sla_due_at <= NOW() AND sla_breached_at IS NULL
+		RETURNING id, tenant_id, registration_type, company_name, status,
+			assigned_to, queue_id, sla_due_at, sla_breached_at
+	`, schema)
+
+	breaches := []*models.SLABreach{}
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		rows, err := tx.Query(query, tenantID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			breach := &models.SLABreach{}
+			err := rows.Scan(
+				&breach.RegistrationID,
+				&breach.TenantID,
+				&breach.RegistrationType,
+				&breach.CompanyName,
+				&breach.Status,
+				&breach.AssignedTo,
+				&breach.QueueID,
+				&breach.DueAt,
+				&breach.BreachedAt,
+			)
+			if err != nil {
+				return err
+			}
+			breaches = append(breaches, breach)
+		}
+
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark SLA breaches: %w", err)
+	}
+
+	return breaches, nil
+}
+
+// ListActiveTenants lists the provisioned tenants whose registrations are monitored
+func (r *QueueRepository) ListActiveTenants() ([]uuid.UUID, error) {
+	return listActiveTenants(r.db)
+}
+
+// listActiveTenants lists the active, provisioned tenants, oldest first
+func listActiveTenants(db *sql.DB) ([]uuid.UUID, error) {
+	query := `
+		SELECT id FROM public.tenants
+		WHERE status = 'active' AND provisioned_at IS NOT NULL AND deleted_at IS NULL
+		ORDER BY created_at
+	`
+
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tenants: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// isUniqueViolation reports whether an error is a unique constraint violation
+func isUniqueViolation(err error) bool {
+	pqErr, ok := err.(*pq.Error)
+	return ok && pqErr.Code == "23505"
+}
@@ -0,0 +1,231 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/comply360/shared/models"
+	"github.com/comply360/shared/tenancy"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type RegisterRepository struct {
+	db *sql.DB
+}
+
+func NewRegisterRepository(db *sql.DB) *RegisterRepository {
+	return &RegisterRepository{db: db}
+}
+
+// registerColumns are the register entry columns read by scanRegisterEntry
+const registerColumns = `id, entry_id, tenant_id, registration_id, version, effective_from,
+			role, person_type, first_names, last_name, id_number, passport_number,
+			nationality, date_of_birth, entity_name, registration_number, email,
+			residential_address, share_percent, control_type, appointed_on, resigned_on,
+			document_ids, removed, note, recorded_by, recorded_at`
+
+func scanRegisterEntry(row interface{ Scan(...interface{}) error }) (*models.RegisterEntry, error) {
+	entry := &models.RegisterEntry{}
+	err := row.Scan(
+		&entry.VersionID,
+		&entry.ID,
+		&entry.TenantID,
+		&entry.RegistrationID,
+		&entry.Version,
+		&entry.EffectiveFrom,
+		&entry.Role,
+		&entry.PersonType,
+		&entry.FirstNames,
+		&entry.LastName,
+		&entry.IDNumber,
+		&entry.PassportNumber,
+		&entry.Nationality,
+		&entry.DateOfBirth,
+		&entry.EntityName,
+		&entry.RegistrationNumber,
+		&entry.Email,
+		&entry.ResidentialAddress,
+		&entry.SharePercent,
+		&entry.ControlType,
+		&entry.AppointedOn,
+		&entry.ResignedOn,
+		pq.Array(&entry.DocumentIDs),
+		&entry.Removed,
+		&entry.Note,
+		&entry.RecordedBy,
+		&entry.RecordedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// listRegisterVersions returns every version of a registration's register
+// entries, in the order they were recorded
+func listRegisterVersions(tx *sql.Tx, schema string, tenantID, registrationID uuid.UUID) ([]*models.RegisterEntry, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s.company_register_entries
+		WHERE registration_id = $1 AND tenant_id = $2
+		ORDER BY recorded_at, version
+	`, registerColumns, schema)
+
+	rows, err := tx.Query(query, registrationID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := []*models.RegisterEntry{}
+	for rows.Next() {
+		entry, err := scanRegisterEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, entry)
+	}
+	return versions, rows.Err()
+}
+
+// ListVersions returns every version of a registration's register entries
+func (r *RegisterRepository) ListVersions(schema string, tenantID, registrationID uuid.UUID) ([]*models.RegisterEntry, error) {
+	var versions []*models.RegisterEntry
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		var err error
+		versions, err = listRegisterVersions(tx, schema, tenantID, registrationID)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list register entries: %w", err)
+	}
+
+	return versions, nil
+}
+
+// Record stores a new version of a register entry in one transaction with the
+// registration locked, so changes to one register are made one at a time.
+// change is given the register's versions and returns the version to store,
+// or an error to store nothing.
+func (r *RegisterRepository) Record(schema string, tenantID, registrationID uuid.UUID, change func(versions []*models.RegisterEntry) (*models.RegisterEntry, error)) (*models.RegisterEntry, error) {
+	lockQuery := fmt.Sprintf(`
+		SELECT id FROM %s.registrations
+		WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL
+		FOR UPDATE
+	`, schema)
+	insertQuery := fmt.Sprintf(`
+		INSERT INTO %s.company_register_entries (
+			entry_id, tenant_id, registration_id, version, effective_from,
+			role, person_type, first_names, last_name, id_number, passport_number,
+			nationality, date_of_birth, entity_name, registration_number, email,
+			residential_address, share_percent, control_type, appointed_on, resigned_on,
+			document_ids, removed, note, recorded_by
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25)
+		RETURNING id, recorded_at
+	`, schema)
+
+	var entry *models.RegisterEntry
+	var refused error
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		var id uuid.UUID
+		if err := tx.QueryRow(lockQuery, registrationID, tenantID).Scan(&id); err != nil {
+			return err
+		}
+
+		versions, err := listRegisterVersions(tx, schema, tenantID, registrationID)
+		if err != nil {
+			return err
+		}
+		entry, refused = change(versions)
+		if refused != nil {
+			return refused
+		}
+
+		return tx.QueryRow(
+			insertQuery,
+			entry.ID,
+			tenantID,
+			registrationID,
+			entry.Version,
+			entry.EffectiveFrom,
+			entry.Role,
+			entry.PersonType,
+			entry.FirstNames,
+			entry.LastName,
+			entry.IDNumber,
+			entry.PassportNumber,
+			entry.Nationality,
+			entry.DateOfBirth,
+			entry.EntityName,
+			entry.RegistrationNumber,
+			entry.Email,
+			entry.ResidentialAddress,
+			entry.SharePercent,
+			entry.ControlType,
+			entry.AppointedOn,
+			entry.ResignedOn,
+			pq.Array(entry.DocumentIDs),
+			entry.Removed,
+			entry.Note,
+			entry.RecordedBy,
+		).Scan(&entry.VersionID, &entry.RecordedAt)
+	})
+	if refused != nil {
+		return nil, refused
+	}
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("registration not found")
+	}
+	if isUniqueViolation(err) {
+		return nil, fmt.Errorf("register entry was changed concurrently")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to record register entry: %w", err)
+	}
+
+	entry.TenantID = tenantID
+	entry.RegistrationID = registrationID
+	return entry, nil
+}
+
+// MissingDocuments returns the documents, of those given, that were not
+// uploaded for the registration or its client
+func (r *RegisterRepository) MissingDocuments(schema string, tenantID, registrationID uuid.UUID, documentIDs []uuid.UUID) ([]uuid.UUID, error) {
+	query := fmt.Sprintf(`
+		SELECT d.id
+		FROM %[1]s.documents d
+		JOIN %[1]s.registrations r ON r.id = $2
+		WHERE d.tenant_id = $1 AND d.id = ANY($3) AND d.deleted_at IS NULL
+			AND (d.registration_id = r.id OR d.client_id = r.client_id)
+	`, schema)
+
+	found := map[uuid.UUID]bool{}
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		rows, err := tx.Query(query, tenantID, registrationID, pq.Array(documentIDs))
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var id uuid.UUID
+			if err := rows.Scan(&id); err != nil {
+				return err
+			}
+			found[id] = true
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to check register documents: %w", err)
+	}
+
+	missing := []uuid.UUID{}
+	for _, id := range documentIDs {
+		if !found[id] {
+			missing = append(missing, id)
+		}
+	}
+	return missing, nil
+}
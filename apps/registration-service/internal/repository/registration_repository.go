@@ -4,8 +4,11 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/comply360/shared/models"
+	"github.com/comply360/shared/pagination"
+	"github.com/comply360/shared/tenancy"
 	"github.com/google/uuid"
 )
 
@@ -19,11 +22,29 @@ func NewRegistrationRepository(db *sql.DB) *RegistrationRepository {
 
 // Create creates a new registration
 func (r *RegistrationRepository) Create(schema string, registration *models.Registration) error {
+	return r.create(schema, registration, nil)
+}
+
+// CreateWithHistory creates a new registration and records its initial status as
+// the first entry of its status history, in one transaction
+func (r *RegistrationRepository) CreateWithHistory(schema string, registration *models.Registration, initial *models.RegistrationStatusChange) error {
+	return r.create(schema, registration, initial)
+}
+
+func (r *RegistrationRepository) create(schema string, registration *models.Registration, initial *models.RegistrationStatusChange) error {
+	return tenancy.WithTenant(r.db, registration.TenantID, func(tx *sql.Tx) error {
+		return insertRegistration(tx, schema, registration, initial)
+	})
+}
+
+// insertRegistration inserts a registration and, when given, its initial status change
+func insertRegistration(tx *sql.Tx, schema string, registration *models.Registration, initial *models.RegistrationStatusChange) error {
 	query := fmt.Sprintf(`
 		INSERT INTO %s.registrations (
 			tenant_id, client_id, registration_type, company_name,
-			jurisdiction, status, assigned_to, form_data
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			jurisdiction, status, assigned_to, form_data, workflow_version,
+			form_schema_version, status_changed_at, sla_due_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, COALESCE($11, NOW()), $12)
 		RETURNING id, created_at, updated_at
 	`, schema)
 
@@ -32,7 +53,7 @@ func (r *RegistrationRepository) Create(schema string, registration *models.Regi
 		return fmt.Errorf("failed to marshal form data: %w", err)
 	}
 
-	return r.db.QueryRow(
+	err = tx.QueryRow(
 		query,
 		registration.TenantID,
 		registration.ClientID,
@@ -42,25 +63,37 @@ func (r *RegistrationRepository) Create(schema string, registration *models.Regi
 		registration.Status,
 		registration.AssignedTo,
 		formDataJSON,
+		registration.WorkflowVersion,
+		registration.FormSchemaVersion,
+		registration.StatusChangedAt,
+		registration.SLADueAt,
 	).Scan(&registration.ID, &registration.CreatedAt, &registration.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	if initial != nil {
+		initial.RegistrationID = registration.ID
+		return insertStatusChange(tx, schema, registration.TenantID, initial)
+	}
+	return nil
 }
 
-// GetByID retrieves a registration by ID
-func (r *RegistrationRepository) GetByID(schema string, tenantID, registrationID uuid.UUID) (*models.Registration, error) {
-	query := fmt.Sprintf(`
-		SELECT id, tenant_id, client_id, registration_type, company_name,
+// registrationColumns are the registration columns read by scanRegistration
+const registrationColumns = `id, tenant_id, client_id, registration_type, company_name,
 			registration_number, jurisdiction, status, submitted_at, approved_at,
 			rejected_at, rejection_reason, assigned_to, cipc_reference,
 			dcip_reference, odoo_lead_id, odoo_project_id, odoo_invoice_id,
-			created_at, updated_at, deleted_at, form_data
-		FROM %s.registrations
-		WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL
-	`, schema)
+			created_at, updated_at, deleted_at, form_data, workflow_version,
+			form_schema_version, queue_id, assigned_at, status_changed_at,
+			sla_due_at, sla_breached_at`
 
+// scanRegistration reads a row of registrationColumns
+func scanRegistration(row interface{ Scan(...interface{}) error }) (*models.Registration, error) {
 	registration := &models.Registration{}
 	var formDataJSON []byte
 
-	err := r.db.QueryRow(query, registrationID, tenantID).Scan(
+	err := row.Scan(
 		&registration.ID,
 		&registration.TenantID,
 		&registration.ClientID,
@@ -83,13 +116,16 @@ func (r *RegistrationRepository) GetByID(schema string, tenantID, registrationID
 		&registration.UpdatedAt,
 		&registration.DeletedAt,
 		&formDataJSON,
+		&registration.WorkflowVersion,
+		&registration.FormSchemaVersion,
+		&registration.QueueID,
+		&registration.AssignedAt,
+		&registration.StatusChangedAt,
+		&registration.SLADueAt,
+		&registration.SLABreachedAt,
 	)
-
-	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("registration not found")
-	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to get registration: %w", err)
+		return nil, err
 	}
 
 	// Unmarshal form data
@@ -102,8 +138,60 @@ func (r *RegistrationRepository) GetByID(schema string, tenantID, registrationID
 	return registration, nil
 }
 
-// List retrieves registrations with pagination
-func (r *RegistrationRepository) List(schema string, tenantID uuid.UUID, offset, limit int, status string) ([]*models.Registration, int, error) {
+// GetByID retrieves a registration by ID
+func (r *RegistrationRepository) GetByID(schema string, tenantID, registrationID uuid.UUID) (*models.Registration, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s.registrations
+		WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL
+	`, registrationColumns, schema)
+
+	var registration *models.Registration
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		var err error
+		registration, err = scanRegistration(tx.QueryRow(query, registrationID, tenantID))
+		return err
+	})
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("registration not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get registration: %w", err)
+	}
+
+	return registration, nil
+}
+
+// RegistrationKeys are the fields registration lists sort by
+var RegistrationKeys = &pagination.Keys[*models.Registration]{
+	ID: func(r *models.Registration) uuid.UUID { return r.ID },
+	Columns: map[string]pagination.Column[*models.Registration]{
+		"created_at":   {SQL: "created_at", Value: func(r *models.Registration) interface{} { return r.CreatedAt }},
+		"updated_at":   {SQL: "updated_at", Value: func(r *models.Registration) interface{} { return r.UpdatedAt }},
+		"company_name": {SQL: "company_name", Value: func(r *models.Registration) interface{} { return r.CompanyName }},
+		"status":       {SQL: "status", Value: func(r *models.Registration) interface{} { return r.Status }},
+	},
+	Default: "-created_at",
+}
+
+// nullableTime is a sort column of a nullable timestamp. Rows without one sort
+// as infinity, 'infinity' after every time or '-infinity' before, which is also
+// their cursor value.
+func nullableTime[T any](column, infinity string, value func(T) *time.Time) pagination.Column[T] {
+	return pagination.Column[T]{
+		SQL: fmt.Sprintf("COALESCE(%s, '%s')", column, infinity),
+		Value: func(item T) interface{} {
+			if t := value(item); t != nil {
+				return *t
+			}
+			return infinity
+		},
+	}
+}
+
+// List retrieves a page of registrations after the page's cursor, with optional status filter
+func (r *RegistrationRepository) List(schema string, tenantID uuid.UUID, status string, page *pagination.Query[*models.Registration]) (*pagination.List[*models.Registration], error) {
 	// Build query with optional status filter
 	whereClause := "WHERE tenant_id = $1 AND deleted_at IS NULL"
 	args := []interface{}{tenantID}
@@ -115,91 +203,117 @@ func (r *RegistrationRepository) List(schema string, tenantID uuid.UUID, offset,
 		args = append(args, status)
 	}
 
-	// Count total
+	// Count total, only when asked for
 	countQuery := fmt.Sprintf(`
 		SELECT COUNT(*) FROM %s.registrations %s
 	`, schema, whereClause)
+	countArgs := args
 
-	var total int
-	if err := r.db.QueryRow(countQuery, args...).Scan(&total); err != nil {
-		return nil, 0, fmt.Errorf("failed to count registrations: %w", err)
+	// Continue after the cursor
+	if keyset, values := page.Keyset(argCount + 1); keyset != "" {
+		whereClause += " AND " + keyset
+		args = append(append([]interface{}{}, args...), values...)
+		argCount += len(values)
 	}
 
-	// Get registrations
-	argCount++
-	limitArg := argCount
-	argCount++
-	offsetArg := argCount
+	var total *int
+	var registrations []*models.Registration
 
-	query := fmt.Sprintf(`
-		SELECT id, tenant_id, client_id, registration_type, company_name,
-			registration_number, jurisdiction, status, submitted_at, approved_at,
-			rejected_at, rejection_reason, assigned_to, cipc_reference,
-			dcip_reference, odoo_lead_id, odoo_project_id, odoo_invoice_id,
-			created_at, updated_at, deleted_at, form_data
-		FROM %s.registrations
-		%s
-		ORDER BY created_at DESC
-		LIMIT $%d OFFSET $%d
-	`, schema, whereClause, limitArg, offsetArg)
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		if page.IncludeTotal() {
+			var count int
+			if err := tx.QueryRow(countQuery, countArgs...).Scan(&count); err != nil {
+				return fmt.Errorf("failed to count registrations: %w", err)
+			}
+			total = &count
+		}
 
-	args = append(args, limit, offset)
+		// Get registrations
+		argCount++
+		limitArg := argCount
 
-	rows, err := r.db.Query(query, args...)
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to query registrations: %w", err)
-	}
-	defer rows.Close()
+		query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s.registrations
+		%s
+		ORDER BY %s
+		LIMIT $%d
+	`, registrationColumns, schema, whereClause, page.OrderBy(), limitArg)
 
-	var registrations []*models.Registration
-	for rows.Next() {
-		registration := &models.Registration{}
-		var formDataJSON []byte
-
-		err := rows.Scan(
-			&registration.ID,
-			&registration.TenantID,
-			&registration.ClientID,
-			&registration.RegistrationType,
-			&registration.CompanyName,
-			&registration.RegistrationNumber,
-			&registration.Jurisdiction,
-			&registration.Status,
-			&registration.SubmittedAt,
-			&registration.ApprovedAt,
-			&registration.RejectedAt,
-			&registration.RejectionReason,
-			&registration.AssignedTo,
-			&registration.CIPCReference,
-			&registration.DCIPReference,
-			&registration.OdooLeadID,
-			&registration.OdooProjectID,
-			&registration.OdooInvoiceID,
-			&registration.CreatedAt,
-			&registration.UpdatedAt,
-			&registration.DeletedAt,
-			&formDataJSON,
-		)
+		args = append(args, page.Limit())
 
+		rows, err := tx.Query(query, args...)
 		if err != nil {
-			return nil, 0, fmt.Errorf("failed to scan registration: %w", err)
+			return fmt.Errorf("failed to query registrations: %w", err)
 		}
+		defer rows.Close()
 
-		// Unmarshal form data
-		if len(formDataJSON) > 0 {
-			if err := json.Unmarshal(formDataJSON, &registration.FormData); err != nil {
-				return nil, 0, fmt.Errorf("failed to unmarshal form data: %w", err)
+		for rows.Next() {
+			registration, err := scanRegistration(rows)
+			if err != nil {
+				return fmt.Errorf("failed to scan registration: %w", err)
 			}
+
+			registrations = append(registrations, registration)
 		}
 
-		registrations = append(registrations, registration)
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return registrations, total, nil
+	return page.List(registrations, total), nil
 }
 
 // Update updates a registration
 func (r *RegistrationRepository) Update(schema string, registration *models.Registration) error {
+	return tenancy.WithTenant(r.db, registration.TenantID, func(tx *sql.Tx) error {
+		return updateRegistration(tx, schema, registration)
+	})
+}
+
+// Transition updates a registration whose status changed, restarts its SLA
+// clock, records the change in its status history and locks the quote of a
+// registration being submitted, in one transaction
+func (r *RegistrationRepository) Transition(schema string, registration *models.Registration, change *models.RegistrationStatusChange) error {
+	return tenancy.WithTenant(r.db, registration.TenantID, func(tx *sql.Tx) error {
+		if err := updateRegistration(tx, schema, registration); err != nil {
+			return err
+		}
+		if err := updateStatusClock(tx, schema, registration); err != nil {
+			return err
+		}
+		if err := insertStatusChange(tx, schema, registration.TenantID, change); err != nil {
+			return err
+		}
+		if registration.Quote != nil && !registration.Quote.IsLocked() {
+			return lockQuote(tx, schema, registration.Quote)
+		}
+		return nil
+	})
+}
+
+// updateStatusClock records when a registration entered its status and when the
+// status's SLA target runs out, and clears any earlier breach
+func updateStatusClock(tx *sql.Tx, schema string, registration *models.Registration) error {
+	query := fmt.Sprintf(`
+		UPDATE %s.registrations SET
+			status_changed_at = COALESCE($1, NOW()),
+			sla_due_at = $2,
+			sla_breached_at = NULL
+		WHERE id = $3 AND tenant_id = $4
+	`, schema)
+
+	if _, err := tx.Exec(query, registration.StatusChangedAt, registration.SLADueAt, registration.ID, registration.TenantID); err != nil {
+		return fmt.Errorf("failed to update status clock: %w", err)
+	}
+	registration.SLABreachedAt = nil
+	return nil
+}
+
+// updateRegistration writes a registration's editable columns
+func updateRegistration(tx *sql.Tx, schema string, registration *models.Registration) error {
 	query := fmt.Sprintf(`
 		UPDATE %s.registrations SET
 			registration_type = $1,
@@ -218,8 +332,9 @@ func (r *RegistrationRepository) Update(schema string, registration *models.Regi
 			odoo_project_id = $14,
 			odoo_invoice_id = $15,
 			form_data = $16,
+			form_schema_version = $17,
 			updated_at = CURRENT_TIMESTAMP
-		WHERE id = $17 AND tenant_id = $18 AND deleted_at IS NULL
+		WHERE id = $18 AND tenant_id = $19 AND deleted_at IS NULL
 	`, schema)
 
 	formDataJSON, err := json.Marshal(registration.FormData)
@@ -227,7 +342,7 @@ func (r *RegistrationRepository) Update(schema string, registration *models.Regi
 		return fmt.Errorf("failed to marshal form data: %w", err)
 	}
 
-	result, err := r.db.Exec(
+	result, err := tx.Exec(
 		query,
 		registration.RegistrationType,
 		registration.CompanyName,
@@ -245,10 +360,10 @@ func (r *RegistrationRepository) Update(schema string, registration *models.Regi
 		registration.OdooProjectID,
 		registration.OdooInvoiceID,
 		formDataJSON,
+		registration.FormSchemaVersion,
 		registration.ID,
 		registration.TenantID,
 	)
-
 	if err != nil {
 		return fmt.Errorf("failed to update registration: %w", err)
 	}
@@ -273,7 +388,12 @@ func (r *RegistrationRepository) Delete(schema string, tenantID, registrationID
 		WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL
 	`, schema)
 
-	result, err := r.db.Exec(query, registrationID, tenantID)
+	var result sql.Result
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		var err error
+		result, err = tx.Exec(query, registrationID, tenantID)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("failed to delete registration: %w", err)
 	}
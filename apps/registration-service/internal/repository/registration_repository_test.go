@@ -2,8 +2,11 @@ package repository
 
 import (
 	"testing"
+	"time"
 
+	"github.com/comply360/registration-service/internal/workflow"
 	"github.com/comply360/shared/models"
+	"github.com/comply360/shared/pagination"
 	testhelpers "github.com/comply360/shared/testing"
 	"github.com/google/uuid"
 )
@@ -25,6 +28,13 @@ func TestRegistrationRepository_Create(t *testing.T) {
 			status VARCHAR(50) NOT NULL DEFAULT 'draft',
 			assigned_to VARCHAR(255),
 			form_data JSONB DEFAULT '{}'::jsonb,
+			workflow_version INT NOT NULL DEFAULT 1,
+			form_schema_version INT,
+			queue_id UUID,
+			assigned_at TIMESTAMP,
+			status_changed_at TIMESTAMP,
+			sla_due_at TIMESTAMP,
+			sla_breached_at TIMESTAMP,
 			submitted_at TIMESTAMP,
 			approved_at TIMESTAMP,
 			deleted_at TIMESTAMP,
@@ -76,6 +86,13 @@ func TestRegistrationRepository_GetByID(t *testing.T) {
 			odoo_project_id INTEGER,
 			odoo_invoice_id INTEGER,
 			form_data JSONB DEFAULT '{}'::jsonb,
+			workflow_version INT NOT NULL DEFAULT 1,
+			form_schema_version INT,
+			queue_id UUID,
+			assigned_at TIMESTAMP,
+			status_changed_at TIMESTAMP,
+			sla_due_at TIMESTAMP,
+			sla_breached_at TIMESTAMP,
 			metadata JSONB DEFAULT '{}'::jsonb,
 			created_at TIMESTAMP NOT NULL DEFAULT NOW(),
 			updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
@@ -130,6 +147,13 @@ func TestRegistrationRepository_Update(t *testing.T) {
 			odoo_project_id INTEGER,
 			odoo_invoice_id INTEGER,
 			form_data JSONB DEFAULT '{}'::jsonb,
+			workflow_version INT NOT NULL DEFAULT 1,
+			form_schema_version INT,
+			queue_id UUID,
+			assigned_at TIMESTAMP,
+			status_changed_at TIMESTAMP,
+			sla_due_at TIMESTAMP,
+			sla_breached_at TIMESTAMP,
 			metadata JSONB DEFAULT '{}'::jsonb,
 			created_at TIMESTAMP NOT NULL DEFAULT NOW(),
 			updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
@@ -193,6 +217,13 @@ func TestRegistrationRepository_List(t *testing.T) {
 			odoo_project_id INTEGER,
 			odoo_invoice_id INTEGER,
 			form_data JSONB DEFAULT '{}'::jsonb,
+			workflow_version INT NOT NULL DEFAULT 1,
+			form_schema_version INT,
+			queue_id UUID,
+			assigned_at TIMESTAMP,
+			status_changed_at TIMESTAMP,
+			sla_due_at TIMESTAMP,
+			sla_breached_at TIMESTAMP,
 			metadata JSONB DEFAULT '{}'::jsonb,
 			created_at TIMESTAMP NOT NULL DEFAULT NOW(),
 			updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
@@ -218,10 +249,32 @@ func TestRegistrationRepository_List(t *testing.T) {
 	}
 
 	// Test: List registrations
-	registrations, total, err := repo.List(tdb.Schema, tdb.TenantID, 0, 10, "")
+	page, err := RegistrationKeys.Query(pagination.Page{Limit: 10, IncludeTotal: true})
+	testhelpers.AssertNoError(t, err)
+	registrations, err := repo.List(tdb.Schema, tdb.TenantID, "", page)
 	testhelpers.AssertNoError(t, err, "Failed to list registrations")
-	testhelpers.AssertEqual(t, 5, len(registrations), "Should return 5 registrations")
-	testhelpers.AssertEqual(t, 5, total, "Total count should be 5")
+	testhelpers.AssertEqual(t, 5, len(registrations.Data), "Should return 5 registrations")
+	testhelpers.AssertEqual(t, 5, *registrations.Total, "Total count should be 5")
+	testhelpers.AssertFalse(t, registrations.HasMore, "All registrations fit on one page")
+
+	// Test: Keyset pagination walks every registration once
+	seen := map[uuid.UUID]bool{}
+	cursor := ""
+	for {
+		page, err := RegistrationKeys.Query(pagination.Page{Limit: 2, Cursor: cursor})
+		testhelpers.AssertNoError(t, err)
+		registrations, err := repo.List(tdb.Schema, tdb.TenantID, "", page)
+		testhelpers.AssertNoError(t, err)
+		for _, registration := range registrations.Data {
+			testhelpers.AssertFalse(t, seen[registration.ID], "Registration listed twice")
+			seen[registration.ID] = true
+		}
+		if !registrations.HasMore {
+			break
+		}
+		cursor = registrations.NextCursor
+	}
+	testhelpers.AssertEqual(t, 5, len(seen), "Every registration should be listed")
 }
 
 func TestRegistrationRepository_Delete(t *testing.T) {
@@ -240,6 +293,13 @@ func TestRegistrationRepository_Delete(t *testing.T) {
 			status VARCHAR(50) NOT NULL DEFAULT 'draft',
 			assigned_to VARCHAR(255),
 			form_data JSONB DEFAULT '{}'::jsonb,
+			workflow_version INT NOT NULL DEFAULT 1,
+			form_schema_version INT,
+			queue_id UUID,
+			assigned_at TIMESTAMP,
+			status_changed_at TIMESTAMP,
+			sla_due_at TIMESTAMP,
+			sla_breached_at TIMESTAMP,
 			submitted_at TIMESTAMP,
 			approved_at TIMESTAMP,
 			deleted_at TIMESTAMP,
@@ -271,3 +331,98 @@ func TestRegistrationRepository_Delete(t *testing.T) {
 	_, err = repo.GetByID(tdb.Schema, tdb.TenantID, registration.ID)
 	testhelpers.AssertError(t, err, "Should not find deleted registration")
 }
+
+func TestRegistrationRepository_InvoicePaymentAllowsCompletion(t *testing.T) {
+	tdb := testhelpers.SetupTestDB(t)
+	defer tdb.Cleanup(t)
+
+	_, err := tdb.DB.Exec(`
+		CREATE TABLE IF NOT EXISTS registrations (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			tenant_id UUID NOT NULL,
+			client_id UUID NOT NULL,
+			registration_type VARCHAR(100) NOT NULL,
+			company_name VARCHAR(255) NOT NULL,
+			registration_number VARCHAR(100),
+			jurisdiction VARCHAR(2) NOT NULL,
+			status VARCHAR(50) NOT NULL DEFAULT 'draft',
+			assigned_to VARCHAR(255),
+			form_data JSONB DEFAULT '{}'::jsonb,
+			workflow_version INT NOT NULL DEFAULT 1,
+			form_schema_version INT,
+			queue_id UUID,
+			assigned_at TIMESTAMP,
+			status_changed_at TIMESTAMP,
+			sla_due_at TIMESTAMP,
+			sla_breached_at TIMESTAMP,
+			submitted_at TIMESTAMP,
+			approved_at TIMESTAMP,
+			name_reserved_at TIMESTAMP,
+			invoice_paid_at TIMESTAMP,
+			deleted_at TIMESTAMP,
+			created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+		);
+		CREATE TABLE IF NOT EXISTS documents (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			registration_id UUID,
+			status VARCHAR(50) NOT NULL DEFAULT 'pending',
+			deleted_at TIMESTAMP
+		)
+	`)
+	testhelpers.AssertNoError(t, err)
+
+	repo := NewRegistrationRepository(tdb.DB)
+
+	registration := &models.Registration{
+		TenantID:         tdb.TenantID,
+		ClientID:         uuid.New(),
+		RegistrationType: models.RegistrationTypePtyLtd,
+		CompanyName:      "Test Company",
+		Jurisdiction:     "ZA",
+		Status:           models.RegistrationStatusApproved,
+	}
+	err = repo.Create(tdb.Schema, registration)
+	testhelpers.AssertNoError(t, err)
+
+	definition, err := workflow.DefaultRegistry().Latest(registration.RegistrationType)
+	testhelpers.AssertNoError(t, err)
+	reviewer := workflow.Actor{Roles: []string{models.RoleTenantAdmin}}
+
+	facts, err := repo.LoadFacts(tdb.Schema, tdb.TenantID, registration.ID)
+	testhelpers.AssertNoError(t, err)
+	_, err = definition.Check(models.RegistrationStatusApproved, models.RegistrationStatusCompleted, reviewer, facts)
+	testhelpers.AssertError(t, err, "Unpaid registration should not complete")
+
+	err = repo.MarkInvoicePaid(tdb.Schema, tdb.TenantID, registration.ID, time.Now())
+	testhelpers.AssertNoError(t, err, "Failed to record invoice payment")
+
+	facts, err = repo.LoadFacts(tdb.Schema, tdb.TenantID, registration.ID)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertTrue(t, facts.InvoicePaid, "Invoice should be paid")
+	_, err = definition.Check(models.RegistrationStatusApproved, models.RegistrationStatusCompleted, reviewer, facts)
+	testhelpers.AssertNoError(t, err, "Paid registration should complete")
+
+	err = repo.MarkInvoicePaid(tdb.Schema, tdb.TenantID, uuid.New(), time.Now())
+	testhelpers.AssertError(t, err, "Unknown registration should not be paid")
+}
+
+func TestRegistrationSearchKeys_SortsMissingTimesAsInfinity(t *testing.T) {
+	_, err := RegistrationSearchKeys.Query(pagination.Page{Sort: "password_hash"})
+	testhelpers.AssertTrue(t, pagination.IsRequestError(err), "Only whitelisted fields sort")
+
+	page := pagination.Page{Limit: 1, Sort: "-submitted_at"}
+	query, err := RegistrationSearchKeys.Query(page)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "COALESCE(submitted_at, '-infinity') DESC, id DESC", query.OrderBy())
+
+	draft := &models.RegistrationSearchHit{Registration: &models.Registration{ID: uuid.New()}}
+	list := query.List([]*models.RegistrationSearchHit{draft, draft}, nil)
+
+	page.Cursor = list.NextCursor
+	query, err = RegistrationSearchKeys.Query(page)
+	testhelpers.AssertNoError(t, err)
+	keyset, values := query.Keyset(1)
+	testhelpers.AssertEqual(t, "(COALESCE(submitted_at, '-infinity'), id) < ($1, $2)", keyset)
+	testhelpers.AssertEqual(t, "-infinity", values[0], "Registrations never submitted sort last")
+}
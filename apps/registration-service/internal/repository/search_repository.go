@@ -0,0 +1,490 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/comply360/registration-service/internal/search"
+	"github.com/comply360/shared/models"
+	"github.com/comply360/shared/pagination"
+	"github.com/comply360/shared/tenancy"
+	"github.com/google/uuid"
+)
+
+// Unassigned is the assigned_to facet value and filter of registrations with no
+// reviewer
+const Unassigned = "unassigned"
+
+// RegistrationSearchKeys are the fields registration search results sort by
+var RegistrationSearchKeys = &pagination.Keys[*models.RegistrationSearchHit]{
+	ID: func(h *models.RegistrationSearchHit) uuid.UUID { return h.ID },
+	Columns: map[string]pagination.Column[*models.RegistrationSearchHit]{
+		search.Relevance: {SQL: "score", Value: func(h *models.RegistrationSearchHit) interface{} { return h.Score }},
+		"created_at":     {SQL: "created_at", Value: func(h *models.RegistrationSearchHit) interface{} { return h.CreatedAt }},
+		"updated_at":     {SQL: "updated_at", Value: func(h *models.RegistrationSearchHit) interface{} { return h.UpdatedAt }},
+		"submitted_at":   nullableTime("submitted_at", "-infinity", func(h *models.RegistrationSearchHit) *time.Time { return h.SubmittedAt }),
+		"sla_due_at":     nullableTime("sla_due_at", "infinity", func(h *models.RegistrationSearchHit) *time.Time { return h.SLADueAt }),
+		"company_name":   {SQL: "lower(company_name)", Value: func(h *models.RegistrationSearchHit) interface{} { return strings.ToLower(h.CompanyName) }},
+		"registration_number": {SQL: "COALESCE(registration_number, '')", Value: func(h *models.RegistrationSearchHit) interface{} {
+			return stringOrEmpty(h.RegistrationNumber)
+		}},
+		"status": {SQL: "status", Value: func(h *models.RegistrationSearchHit) interface{} { return h.Status }},
+	},
+	Default: "-created_at",
+}
+
+// ClientSearchKeys are the fields client search results sort by
+var ClientSearchKeys = &pagination.Keys[*models.ClientSearchHit]{
+	ID: func(h *models.ClientSearchHit) uuid.UUID { return h.ID },
+	Columns: map[string]pagination.Column[*models.ClientSearchHit]{
+		search.Relevance: {SQL: "score", Value: func(h *models.ClientSearchHit) interface{} { return h.Score }},
+		"created_at":     {SQL: "created_at", Value: func(h *models.ClientSearchHit) interface{} { return h.CreatedAt }},
+		"updated_at":     {SQL: "updated_at", Value: func(h *models.ClientSearchHit) interface{} { return h.UpdatedAt }},
+		"name": {SQL: "lower(COALESCE(company_name, full_name, ''))", Value: func(h *models.ClientSearchHit) interface{} {
+			if h.CompanyName != nil {
+				return strings.ToLower(*h.CompanyName)
+			}
+			return strings.ToLower(stringOrEmpty(h.FullName))
+		}},
+		"email": {SQL: "lower(email)", Value: func(h *models.ClientSearchHit) interface{} { return strings.ToLower(h.Email) }},
+	},
+	Default: "-created_at",
+}
+
+// stringOrEmpty returns a nullable string, or "" when it is nil
+func stringOrEmpty(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+// RegistrationSearch is a registration search. Query matches the company name,
+// registration number, CIPC and DCIP references, and the client's ID or passport
+// number. Filters with several values match any of them.
+type RegistrationSearch struct {
+	Query            string
+	Status           []string
+	RegistrationType []string
+	Jurisdiction     []string
+	// AssignedTo holds reviewer IDs, or Unassigned
+	AssignedTo []string
+	ClientID   *uuid.UUID
+	Created    search.Range
+	Submitted  search.Range
+}
+
+// ClientSearch is a client search. Query matches the client's names, email,
+// phone numbers and identity and tax numbers.
+type ClientSearch struct {
+	Query      string
+	Status     []string
+	ClientType []string
+	Country    []string
+	Created    search.Range
+}
+
+// SearchRepository searches registrations and clients in a tenant schema
+type SearchRepository struct {
+	db *sql.DB
+}
+
+func NewSearchRepository(db *sql.DB) *SearchRepository {
+	return &SearchRepository{db: db}
+}
+
+// condition is a WHERE clause condition. Facet conditions are left out when
+// counting their own facet.
+type condition struct {
+	facet string
+	build func(arg func(interface{}) string) string
+}
+
+// where joins the conditions, except those of the skipped facet, binding their
+// values as query arguments
+func where(conditions []condition, skip string, args *[]interface{}) string {
+	arg := func(value interface{}) string {
+		*args = append(*args, value)
+		return fmt.Sprintf("$%d", len(*args))
+	}
+
+	clauses := []string{}
+	for _, c := range conditions {
+		if skip != "" && c.facet == skip {
+			continue
+		}
+		clauses = append(clauses, c.build(arg))
+	}
+	return "WHERE " + strings.Join(clauses, " AND ")
+}
+
+// in matches a column against any of the values
+func in(facet, column string, values []string) condition {
+	return condition{facet: facet, build: func(arg func(interface{}) string) string {
+		placeholders := make([]string, len(values))
+		for i, value := range values {
+			placeholders[i] = arg(value)
+		}
+		return fmt.Sprintf("%s IN (%s)", column, strings.Join(placeholders, ", "))
+	}}
+}
+
+// between matches a column within a date range
+func between(column string, r search.Range) []condition {
+	conditions := []condition{}
+	if r.From != nil {
+		from := *r.From
+		conditions = append(conditions, condition{build: func(arg func(interface{}) string) string {
+			return fmt.Sprintf("%s >= %s", column, arg(from))
+		}})
+	}
+	if r.To != nil {
+		to := *r.To
+		conditions = append(conditions, condition{build: func(arg func(interface{}) string) string {
+			return fmt.Sprintf("%s < %s", column, arg(to))
+		}})
+	}
+	return conditions
+}
+
+// scoreScanner scans a result row's relevance score after the scanned columns
+type scoreScanner struct {
+	rows  *sql.Rows
+	score *float64
+}
+
+func (s scoreScanner) Scan(dest ...interface{}) error {
+	return s.rows.Scan(append(dest, s.score)...)
+}
+
+// SearchRegistrations returns a page of registrations matching a search after
+// the page's cursor, and the facet counts of every match
+func (r *SearchRepository) SearchRegistrations(schema string, tenantID uuid.UUID, s *RegistrationSearch, page *pagination.Query[*models.RegistrationSearchHit]) (*pagination.List[*models.RegistrationSearchHit], map[string][]models.FacetCount, error) {
+	conditions := []condition{{build: func(arg func(interface{}) string) string {
+		return fmt.Sprintf("tenant_id = %s AND deleted_at IS NULL", arg(tenantID))
+	}}}
+
+	// Text match, and the relevance score of the match
+	score := func(func(interface{}) string) string { return "0" }
+	if query := strings.TrimSpace(s.Query); query != "" {
+		terms := search.Terms(query)
+		pattern := search.LikePattern(query)
+		conditions = append(conditions, condition{build: func(arg func(interface{}) string) string {
+			q, like := arg(query), arg(pattern)
+			matches := []string{
+				fmt.Sprintf("company_name %% %s", q),
+				fmt.Sprintf("company_name ILIKE %s", like),
+				fmt.Sprintf("registration_number ILIKE %s", like),
+				fmt.Sprintf("cipc_reference ILIKE %s", like),
+				fmt.Sprintf("dcip_reference ILIKE %s", like),
+				fmt.Sprintf(`client_id IN (
+					SELECT id FROM %s.clients
+					WHERE deleted_at IS NULL AND (id_number ILIKE %s OR passport_number ILIKE %s))`, schema, like, like),
+			}
+			if len(terms) > 0 {
+				matches = append(matches, fmt.Sprintf("search_vector @@ to_tsquery('simple', %s)", arg(search.TSQuery(terms))))
+			}
+			return "(" + strings.Join(matches, " OR ") + ")"
+		}})
+		score = func(arg func(interface{}) string) string {
+			q := arg(query)
+			rank := "0"
+			if len(terms) > 0 {
+				rank = fmt.Sprintf("ts_rank(search_vector, to_tsquery('simple', %s))", arg(search.TSQuery(terms)))
+			}
+			return fmt.Sprintf(`%s + similarity(company_name, %s) +
+				CASE WHEN lower(%s::text) IN (lower(registration_number), lower(cipc_reference), lower(dcip_reference)) THEN 1 ELSE 0 END`,
+				rank, q, q)
+		}
+	}
+
+	// Facet filters
+	if len(s.Status) > 0 {
+		conditions = append(conditions, in("status", "status", s.Status))
+	}
+	if len(s.RegistrationType) > 0 {
+		conditions = append(conditions, in("registration_type", "registration_type", s.RegistrationType))
+	}
+	if len(s.Jurisdiction) > 0 {
+		conditions = append(conditions, in("jurisdiction", "jurisdiction", s.Jurisdiction))
+	}
+	if len(s.AssignedTo) > 0 {
+		assignees := []string{}
+		unassigned := false
+		for _, value := range s.AssignedTo {
+			if value == Unassigned {
+				unassigned = true
+				continue
+			}
+			assignees = append(assignees, value)
+		}
+		conditions = append(conditions, condition{facet: "assigned_to", build: func(arg func(interface{}) string) string {
+			matches := []string{}
+			if len(assignees) > 0 {
+				matches = append(matches, in("", "assigned_to::text", assignees).build(arg))
+			}
+			if unassigned {
+				matches = append(matches, "assigned_to IS NULL")
+			}
+			return "(" + strings.Join(matches, " OR ") + ")"
+		}})
+	}
+	if s.ClientID != nil {
+		clientID := *s.ClientID
+		conditions = append(conditions, condition{build: func(arg func(interface{}) string) string {
+			return fmt.Sprintf("client_id = %s", arg(clientID))
+		}})
+	}
+	conditions = append(conditions, between("created_at", s.Created)...)
+	conditions = append(conditions, between("submitted_at", s.Submitted)...)
+
+	var args []interface{}
+	whereClause := where(conditions, "", &args)
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM %s.registrations %s`, schema, whereClause)
+	countArgs := append([]interface{}{}, args...)
+
+	scoreColumn := score(func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	})
+	keyset := after(page, &args)
+	query := fmt.Sprintf(`
+		SELECT * FROM (
+			SELECT %s, %s AS score
+			FROM %s.registrations
+			%s
+		) matches
+		%s
+		ORDER BY %s
+		LIMIT $%d
+	`, registrationColumns, scoreColumn, schema, whereClause, keyset, page.OrderBy(), len(args)+1)
+	args = append(args, page.Limit())
+
+	var total *int
+	var hits []*models.RegistrationSearchHit
+	facets := map[string][]models.FacetCount{}
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		if page.IncludeTotal() {
+			var count int
+			if err := tx.QueryRow(countQuery, countArgs...).Scan(&count); err != nil {
+				return err
+			}
+			total = &count
+		}
+
+		rows, err := tx.Query(query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			hit := &models.RegistrationSearchHit{}
+			hit.Registration, err = scanRegistration(scoreScanner{rows: rows, score: &hit.Score})
+			if err != nil {
+				return err
+			}
+			hits = append(hits, hit)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		for facet, column := range map[string]string{
+			"status":            "status",
+			"registration_type": "registration_type",
+			"jurisdiction":      "jurisdiction",
+			"assigned_to":       fmt.Sprintf("COALESCE(assigned_to::text, '%s')", Unassigned),
+		} {
+			counts, err := countFacet(tx, schema+".registrations", column, conditions, facet)
+			if err != nil {
+				return err
+			}
+			facets[facet] = counts
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to search registrations: %w", err)
+	}
+
+	return page.List(hits, total), facets, nil
+}
+
+// SearchClients returns a page of clients matching a search after the page's
+// cursor, and the facet counts of every match
+func (r *SearchRepository) SearchClients(schema string, tenantID uuid.UUID, s *ClientSearch, page *pagination.Query[*models.ClientSearchHit]) (*pagination.List[*models.ClientSearchHit], map[string][]models.FacetCount, error) {
+	conditions := []condition{{build: func(arg func(interface{}) string) string {
+		return fmt.Sprintf("tenant_id = %s AND deleted_at IS NULL", arg(tenantID))
+	}}}
+
+	score := func(func(interface{}) string) string { return "0" }
+	if query := strings.TrimSpace(s.Query); query != "" {
+		terms := search.Terms(query)
+		pattern := search.LikePattern(query)
+		conditions = append(conditions, condition{build: func(arg func(interface{}) string) string {
+			q, like := arg(query), arg(pattern)
+			matches := []string{
+				fmt.Sprintf("full_name %% %s", q),
+				fmt.Sprintf("company_name %% %s", q),
+				fmt.Sprintf("full_name ILIKE %s", like),
+				fmt.Sprintf("company_name ILIKE %s", like),
+				fmt.Sprintf("email ILIKE %s", like),
+				fmt.Sprintf("id_number ILIKE %s", like),
+				fmt.Sprintf("passport_number ILIKE %s", like),
+				fmt.Sprintf("tax_number ILIKE %s", like),
+				fmt.Sprintf("phone ILIKE %s", like),
+				fmt.Sprintf("mobile ILIKE %s", like),
+			}
+			if len(terms) > 0 {
+				matches = append(matches, fmt.Sprintf("search_vector @@ to_tsquery('simple', %s)", arg(search.TSQuery(terms))))
+			}
+			return "(" + strings.Join(matches, " OR ") + ")"
+		}})
+		score = func(arg func(interface{}) string) string {
+			q := arg(query)
+			rank := "0"
+			if len(terms) > 0 {
+				rank = fmt.Sprintf("ts_rank(search_vector, to_tsquery('simple', %s))", arg(search.TSQuery(terms)))
+			}
+			return fmt.Sprintf(`%s + GREATEST(similarity(COALESCE(full_name, ''), %s), similarity(COALESCE(company_name, ''), %s)) +
+				CASE WHEN lower(%s::text) IN (lower(email), lower(id_number), lower(passport_number), lower(tax_number)) THEN 1 ELSE 0 END`,
+				rank, q, q, q)
+		}
+	}
+
+	if len(s.Status) > 0 {
+		conditions = append(conditions, in("status", "status", s.Status))
+	}
+	if len(s.ClientType) > 0 {
+		conditions = append(conditions, in("client_type", "client_type", s.ClientType))
+	}
+	if len(s.Country) > 0 {
+		conditions = append(conditions, in("country", "country", s.Country))
+	}
+	conditions = append(conditions, between("created_at", s.Created)...)
+
+	var args []interface{}
+	whereClause := where(conditions, "", &args)
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM %s.clients %s`, schema, whereClause)
+	countArgs := append([]interface{}{}, args...)
+
+	scoreColumn := score(func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	})
+	keyset := after(page, &args)
+	query := fmt.Sprintf(`
+		SELECT * FROM (
+			SELECT id, tenant_id, client_type, full_name, company_name, id_number,
+				passport_number, tax_number, email, phone, mobile, street_address,
+				city, state_province, postal_code, country, status, user_id,
+				created_at, updated_at, %s AS score
+			FROM %s.clients
+			%s
+		) matches
+		%s
+		ORDER BY %s
+		LIMIT $%d
+	`, scoreColumn, schema, whereClause, keyset, page.OrderBy(), len(args)+1)
+	args = append(args, page.Limit())
+
+	var total *int
+	var hits []*models.ClientSearchHit
+	facets := map[string][]models.FacetCount{}
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		if page.IncludeTotal() {
+			var count int
+			if err := tx.QueryRow(countQuery, countArgs...).Scan(&count); err != nil {
+				return err
+			}
+			total = &count
+		}
+
+		rows, err := tx.Query(query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			client := &models.Client{}
+			hit := &models.ClientSearchHit{Client: client}
+			err := rows.Scan(
+				&client.ID, &client.TenantID, &client.ClientType, &client.FullName,
+				&client.CompanyName, &client.IDNumber, &client.PassportNumber,
+				&client.TaxNumber, &client.Email, &client.Phone, &client.Mobile,
+				&client.StreetAddress, &client.City, &client.StateProvince,
+				&client.PostalCode, &client.Country, &client.Status, &client.UserID,
+				&client.CreatedAt, &client.UpdatedAt, &hit.Score,
+			)
+			if err != nil {
+				return err
+			}
+			hits = append(hits, hit)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		for facet, column := range map[string]string{
+			"status":      "status",
+			"client_type": "client_type",
+			"country":     "COALESCE(country, '')",
+		} {
+			counts, err := countFacet(tx, schema+".clients", column, conditions, facet)
+			if err != nil {
+				return err
+			}
+			facets[facet] = counts
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to search clients: %w", err)
+	}
+
+	return page.List(hits, total), facets, nil
+}
+
+// countFacet counts the matches of every value of a facet, with every condition
+// applied except the facet's own
+func countFacet(tx *sql.Tx, table, column string, conditions []condition, facet string) ([]models.FacetCount, error) {
+	var args []interface{}
+	query := fmt.Sprintf(`
+		SELECT %s AS value, COUNT(*)
+		FROM %s
+		%s
+		GROUP BY value
+		ORDER BY COUNT(*) DESC, value
+	`, column, table, where(conditions, facet, &args))
+
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := []models.FacetCount{}
+	for rows.Next() {
+		var count models.FacetCount
+		if err := rows.Scan(&count.Value, &count.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, count)
+	}
+	return counts, rows.Err()
+}
+
+// after returns the WHERE clause selecting matches after the page's cursor,
+// binding its values as query arguments, or "" on the first page
+func after[T any](page *pagination.Query[T], args *[]interface{}) string {
+	keyset, values := page.Keyset(len(*args) + 1)
+	if keyset == "" {
+		return ""
+	}
+	*args = append(*args, values...)
+	return "WHERE " + keyset
+}
@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/comply360/registration-service/internal/workflow"
+	"github.com/comply360/shared/models"
+	"github.com/comply360/shared/tenancy"
+	"github.com/google/uuid"
+)
+
+// insertStatusChange records a status change in a registration's history
+func insertStatusChange(tx *sql.Tx, schema string, tenantID uuid.UUID, change *models.RegistrationStatusChange) error {
+	query := fmt.Sprintf(`
+		INSERT INTO %s.registration_status_history (
+			tenant_id, registration_id, from_status, to_status,
+			workflow_version, changed_by, reason
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+	`, schema)
+
+	err := tx.QueryRow(
+		query,
+		tenantID,
+		change.RegistrationID,
+		change.FromStatus,
+		change.ToStatus,
+		change.WorkflowVersion,
+		change.ChangedBy,
+		change.Reason,
+	).Scan(&change.ID, &change.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record status change: %w", err)
+	}
+
+	return nil
+}
+
+// ListHistory lists a registration's status changes, oldest first
+func (r *RegistrationRepository) ListHistory(schema string, tenantID, registrationID uuid.UUID) ([]*models.RegistrationStatusChange, error) {
+	query := fmt.Sprintf(`
+		SELECT id, registration_id, from_status, to_status, workflow_version,
+			changed_by, reason, created_at
+		FROM %s.registration_status_history
+		WHERE registration_id = $1 AND tenant_id = $2
+		ORDER BY created_at, id
+	`, schema)
+
+	history := []*models.RegistrationStatusChange{}
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		rows, err := tx.Query(query, registrationID, tenantID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			change := &models.RegistrationStatusChange{}
+			err := rows.Scan(
+				&change.ID,
+				&change.RegistrationID,
+				&change.FromStatus,
+				&change.ToStatus,
+				&change.WorkflowVersion,
+				&change.ChangedBy,
+				&change.Reason,
+				&change.CreatedAt,
+			)
+			if err != nil {
+				return err
+			}
+			history = append(history, change)
+		}
+
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list status history: %w", err)
+	}
+
+	return history, nil
+}
+
+// LoadFacts loads what workflow guards are evaluated against: the registration's
+// documents and whether its name is reserved and its invoice paid
+func (r *RegistrationRepository) LoadFacts(schema string, tenantID, registrationID uuid.UUID) (workflow.Facts, error) {
+	query := fmt.Sprintf(`
+		SELECT r.name_reserved_at IS NOT NULL,
+			r.invoice_paid_at IS NOT NULL,
+			COUNT(d.id),
+			COUNT(d.id) FILTER (WHERE d.status = 'verified')
+		FROM %s.registrations r
+		LEFT JOIN %s.documents d ON d.registration_id = r.id AND d.deleted_at IS NULL
+		WHERE r.id = $1 AND r.tenant_id = $2 AND r.deleted_at IS NULL
+		GROUP BY r.id
+	`, schema, schema)
+
+	var facts workflow.Facts
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		return tx.QueryRow(query, registrationID, tenantID).Scan(
+			&facts.NameReserved,
+			&facts.InvoicePaid,
+			&facts.Documents,
+			&facts.VerifiedDocuments,
+		)
+	})
+	if err == sql.ErrNoRows {
+		return facts, fmt.Errorf("registration not found")
+	}
+	if err != nil {
+		return facts, fmt.Errorf("failed to load registration facts: %w", err)
+	}
+
+	return facts, nil
+}
+
+// MarkInvoicePaid records when a registration's invoice was paid. A payment
+// already recorded is kept.
+func (r *RegistrationRepository) MarkInvoicePaid(schema string, tenantID, registrationID uuid.UUID, paidAt time.Time) error {
+	query := fmt.Sprintf(`
+		UPDATE %s.registrations
+		SET invoice_paid_at = COALESCE(invoice_paid_at, $1), updated_at = NOW()
+		WHERE id = $2 AND tenant_id = $3 AND deleted_at IS NULL
+	`, schema)
+
+	var affected int64
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		result, err := tx.Exec(query, paidAt, registrationID, tenantID)
+		if err != nil {
+			return err
+		}
+		affected, err = result.RowsAffected()
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record invoice payment: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("registration not found")
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package requirements
+
+import (
+	"github.com/comply360/shared/jurisdictions"
+	"github.com/comply360/shared/models"
+)
+
+const (
+	submitted = models.RegistrationStatusSubmitted
+	approved  = models.RegistrationStatusApproved
+)
+
+// fields are the form fields each registration type needs, by jurisdiction.
+// The documents come from the jurisdiction itself.
+var fields = map[string]map[string][]Item{
+	models.JurisdictionSouthAfrica: {
+		models.RegistrationTypePtyLtd: {
+			{Label: "Financial year end", Field: "financial_year_end"},
+			{Label: "Registered address", Field: "registered_address"},
+			{Label: "Directors", Field: "directors"},
+			{Label: "Shareholders", Field: "shareholders"},
+			{Label: "Authorised shares", Field: "authorised_shares"},
+		},
+		models.RegistrationTypeCloseCorporation: {
+			{Label: "Financial year end", Field: "financial_year_end"},
+			{Label: "Registered address", Field: "registered_address"},
+			{Label: "Members", Field: "members"},
+		},
+		models.RegistrationTypeBusinessName: {
+			{Label: "Owner name", Field: "owner_name"},
+			{Label: "Owner ID number", Field: "owner_id_number"},
+			{Label: "Business address", Field: "business_address"},
+		},
+		models.RegistrationTypeVATRegistration: {
+			{Label: "Company registration number", Field: "company_registration_number"},
+			{Label: "Income tax number", Field: "income_tax_number"},
+			{Label: "Taxable turnover", Field: "taxable_turnover"},
+			{Label: "VAT category", Field: "vat_category"},
+			{Label: "Bank account", Field: "bank_account"},
+		},
+	},
+	models.JurisdictionZimbabwe: {
+		models.RegistrationTypePtyLtd: {
+			{Label: "Registered address", Field: "registered_address"},
+			{Label: "Directors", Field: "directors"},
+			{Label: "Shareholders", Field: "shareholders"},
+		},
+		models.RegistrationTypeBusinessName: {
+			{Label: "Owner name", Field: "owner_name"},
+			{Label: "Owner national ID", Field: "owner_national_id"},
+			{Label: "Business address", Field: "business_address"},
+		},
+		models.RegistrationTypeVATRegistration: {
+			{Label: "BP number", Field: "bp_number"},
+			{Label: "Taxable turnover", Field: "taxable_turnover"},
+			{Label: "Bank account", Field: "bank_account"},
+		},
+	},
+}
+
+// DefaultRegistry returns the built-in requirements of every registration type
+// each jurisdiction offers. Documents and form fields must be supplied before
+// submission; verifications happen in review and are needed for approval.
+func DefaultRegistry() *Registry {
+	sets := []*Set{}
+	for _, j := range jurisdictions.All() {
+		for _, registrationType := range j.RegistrationTypes() {
+			sets = append(sets, &Set{
+				Jurisdiction:     j.Code(),
+				RegistrationType: registrationType,
+				Items:            items(j, registrationType),
+			})
+		}
+	}
+	return NewRegistry(sets...)
+}
+
+// items lists a registration type's documents, then its fields, then the
+// verifications made in review
+func items(j jurisdictions.Jurisdiction, registrationType string) []Item {
+	items := []Item{}
+	for _, document := range j.RequiredDocuments(registrationType) {
+		items = append(items, Item{
+			Label:        document.Label,
+			RequiredFor:  submitted,
+			DocumentType: document.Type,
+			PerEntryOf:   document.PerEntryOf,
+		})
+	}
+	for _, field := range fields[j.Code()][registrationType] {
+		field.RequiredFor = submitted
+		items = append(items, field)
+	}
+
+	if j.RequiresNameReservation(registrationType) {
+		entity := "Company"
+		if registrationType == models.RegistrationTypeCloseCorporation {
+			entity = "Close corporation"
+		}
+		items = append(items, Item{Label: entity + " name reserved with " + j.Registry(), RequiredFor: approved, Verification: VerificationNameReserved})
+	}
+	return append(items, Item{Label: "All documents verified", RequiredFor: approved, Verification: VerificationDocumentsVerified})
+}
@@ -0,0 +1,276 @@
+package requirements
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/comply360/shared/models"
+)
+
+// Verification names. A verification is checked against the registration's
+// documents and filings rather than something the preparer supplies.
+const (
+	// VerificationDocumentsVerified requires every uploaded document to be verified
+	VerificationDocumentsVerified = "documents_verified"
+	// VerificationNameReserved requires the company name to be reserved with the
+	// jurisdiction's registry
+	VerificationNameReserved = "name_reserved"
+)
+
+// Item is one requirement of a registration type. Exactly one of DocumentType,
+// Field and Verification is set. RequiredFor is the status the registration
+// cannot move to while the item is unmet.
+type Item struct {
+	Label       string
+	RequiredFor string
+
+	// DocumentType requires an uploaded document of the type. With PerEntryOf
+	// set, one document is needed per entry of that form_data array, such as an
+	// ID document for each director.
+	DocumentType string
+	PerEntryOf   string
+
+	// Field is a dotted path into form_data that must hold a value
+	Field string
+
+	Verification string
+}
+
+// Key identifies the item in a checklist
+func (i Item) Key() string {
+	switch {
+	case i.DocumentType != "":
+		return models.ChecklistItemDocument + "." + i.DocumentType
+	case i.Field != "":
+		return models.ChecklistItemField + "." + i.Field
+	default:
+		return models.ChecklistItemVerification + "." + i.Verification
+	}
+}
+
+// Kind returns the checklist item kind
+func (i Item) Kind() string {
+	switch {
+	case i.DocumentType != "":
+		return models.ChecklistItemDocument
+	case i.Field != "":
+		return models.ChecklistItemField
+	default:
+		return models.ChecklistItemVerification
+	}
+}
+
+// Set is the requirements of a registration type in a jurisdiction
+type Set struct {
+	Jurisdiction     string
+	RegistrationType string
+	Items            []Item
+}
+
+// Registry holds the requirement sets by jurisdiction and registration type
+type Registry struct {
+	sets map[string]*Set
+}
+
+// NewRegistry creates a registry from requirement sets. It panics on a duplicate
+// set or a malformed item, since sets are compiled in.
+func NewRegistry(sets ...*Set) *Registry {
+	r := &Registry{sets: map[string]*Set{}}
+	for _, set := range sets {
+		k := key(set.Jurisdiction, set.RegistrationType)
+		if _, exists := r.sets[k]; exists {
+			panic(fmt.Sprintf("requirements: duplicate set %s", k))
+		}
+		for _, item := range set.Items {
+			kinds := 0
+			for _, v := range []string{item.DocumentType, item.Field, item.Verification} {
+				if v != "" {
+					kinds++
+				}
+			}
+			if kinds != 1 || item.RequiredFor == "" {
+				panic(fmt.Sprintf("requirements: %s has a malformed item %q", k, item.Label))
+			}
+		}
+		r.sets[k] = set
+	}
+	return r
+}
+
+// Get returns the requirements of a registration type in a jurisdiction. Types
+// without a set have no requirements.
+func (r *Registry) Get(jurisdiction, registrationType string) *Set {
+	if set, ok := r.sets[key(jurisdiction, registrationType)]; ok {
+		return set
+	}
+	return &Set{Jurisdiction: strings.ToUpper(jurisdiction), RegistrationType: registrationType}
+}
+
+// List returns every set, ordered by jurisdiction and type
+func (r *Registry) List() []*Set {
+	list := make([]*Set, 0, len(r.sets))
+	for _, set := range r.sets {
+		list = append(list, set)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return key(list[i].Jurisdiction, list[i].RegistrationType) < key(list[j].Jurisdiction, list[j].RegistrationType)
+	})
+	return list
+}
+
+// DocumentCounts counts a registration's documents by type and status
+type DocumentCounts map[string]map[string]int
+
+// Evidence is what a checklist is evaluated against
+type Evidence struct {
+	FormData     map[string]interface{}
+	Documents    DocumentCounts
+	NameReserved bool
+}
+
+// Evaluate computes the checklist of a set. Completeness counts met items, with
+// a document awaiting verification counting as half done.
+func (s *Set) Evaluate(evidence Evidence) (items []*models.ChecklistItem, completeness int) {
+	items = make([]*models.ChecklistItem, 0, len(s.Items))
+	if len(s.Items) == 0 {
+		return items, 100
+	}
+
+	score := 0
+	for _, requirement := range s.Items {
+		item := &models.ChecklistItem{
+			Key:         requirement.Key(),
+			Kind:        requirement.Kind(),
+			Label:       requirement.Label,
+			RequiredFor: requirement.RequiredFor,
+		}
+		switch item.Kind {
+		case models.ChecklistItemDocument:
+			evaluateDocument(requirement, evidence, item)
+		case models.ChecklistItemField:
+			item.Status = models.ChecklistStatusMissing
+			if hasValue(lookup(evidence.FormData, requirement.Field)) {
+				item.Status = models.ChecklistStatusComplete
+			}
+		default:
+			evaluateVerification(requirement, evidence, item)
+		}
+
+		switch item.Status {
+		case models.ChecklistStatusComplete:
+			score += 2
+		case models.ChecklistStatusPending:
+			score++
+		}
+		items = append(items, item)
+	}
+
+	return items, score * 100 / (2 * len(s.Items))
+}
+
+// Unmet lists the labels of items required for a status that are not met. A
+// document awaiting verification meets a requirement for submission, so it can
+// be verified in review.
+func Unmet(items []*models.ChecklistItem, status string) []string {
+	unmet := []string{}
+	for _, item := range items {
+		if item.RequiredFor != status || item.Status == models.ChecklistStatusComplete {
+			continue
+		}
+		if item.Status == models.ChecklistStatusPending && status == models.RegistrationStatusSubmitted {
+			continue
+		}
+		unmet = append(unmet, item.Label)
+	}
+	return unmet
+}
+
+// evaluateDocument compares the documents of a type with how many are needed
+func evaluateDocument(requirement Item, evidence Evidence, item *models.ChecklistItem) {
+	needed := 1
+	if requirement.PerEntryOf != "" {
+		if entries, ok := lookup(evidence.FormData, requirement.PerEntryOf).([]interface{}); ok && len(entries) > needed {
+			needed = len(entries)
+		}
+	}
+
+	counts := evidence.Documents[requirement.DocumentType]
+	verified := counts[models.DocumentStatusVerified]
+	uploaded := verified + counts[models.DocumentStatusPending]
+	refused := counts[models.DocumentStatusRejected] + counts[models.DocumentStatusExpired]
+
+	switch {
+	case verified >= needed:
+		item.Status = models.ChecklistStatusComplete
+	case uploaded >= needed:
+		item.Status = models.ChecklistStatusPending
+		item.Detail = fmt.Sprintf("%d of %d verified", verified, needed)
+	case refused > 0:
+		item.Status = models.ChecklistStatusRejected
+		item.Detail = fmt.Sprintf("%d of %d uploaded; %d rejected or expired", uploaded, needed, refused)
+	default:
+		item.Status = models.ChecklistStatusMissing
+		if needed > 1 {
+			item.Detail = fmt.Sprintf("%d of %d uploaded", uploaded, needed)
+		}
+	}
+}
+
+// evaluateVerification checks a verification against the evidence
+func evaluateVerification(requirement Item, evidence Evidence, item *models.ChecklistItem) {
+	met := false
+	switch requirement.Verification {
+	case VerificationDocumentsVerified:
+		total, verified := 0, 0
+		for _, counts := range evidence.Documents {
+			for status, n := range counts {
+				total += n
+				if status == models.DocumentStatusVerified {
+					verified += n
+				}
+			}
+		}
+		met = total > 0 && verified == total
+	case VerificationNameReserved:
+		met = evidence.NameReserved
+	}
+
+	item.Status = models.ChecklistStatusMissing
+	if met {
+		item.Status = models.ChecklistStatusComplete
+	}
+}
+
+// lookup follows a dotted path into form data
+func lookup(data map[string]interface{}, path string) interface{} {
+	var value interface{} = data
+	for _, name := range strings.Split(path, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = object[name]
+	}
+	return value
+}
+
+// hasValue reports whether a form value is filled in
+func hasValue(value interface{}) bool {
+	switch typed := value.(type) {
+	case nil:
+		return false
+	case string:
+		return strings.TrimSpace(typed) != ""
+	case []interface{}:
+		return len(typed) > 0
+	case map[string]interface{}:
+		return len(typed) > 0
+	default:
+		return true
+	}
+}
+
+func key(jurisdiction, registrationType string) string {
+	return strings.ToUpper(jurisdiction) + "/" + registrationType
+}
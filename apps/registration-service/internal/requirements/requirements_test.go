@@ -0,0 +1,100 @@
+package requirements
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/comply360/registration-service/internal/forms"
+	"github.com/comply360/shared/models"
+	testhelpers "github.com/comply360/shared/testing"
+)
+
+func byKey(items []*models.ChecklistItem) map[string]*models.ChecklistItem {
+	index := map[string]*models.ChecklistItem{}
+	for _, item := range items {
+		index[item.Key] = item
+	}
+	return index
+}
+
+func TestDefaultRegistry_FieldsExistInFormSchemas(t *testing.T) {
+	formSchemas, err := forms.DefaultRegistry()
+	testhelpers.AssertNoError(t, err)
+
+	for _, set := range DefaultRegistry().List() {
+		form, err := formSchemas.Latest(set.Jurisdiction, set.RegistrationType)
+		testhelpers.AssertNoError(t, err, set.Jurisdiction+"/"+set.RegistrationType)
+
+		for _, item := range set.Items {
+			for _, path := range []string{item.Field, item.PerEntryOf} {
+				if path == "" {
+					continue
+				}
+				name := strings.Split(path, ".")[0]
+				testhelpers.AssertTrue(t, strings.Contains(string(form.Raw), `"`+name+`"`), set.Jurisdiction+"/"+set.RegistrationType+" has no field "+name)
+			}
+		}
+	}
+}
+
+func TestEvaluate_EmptyDraft(t *testing.T) {
+	set := DefaultRegistry().Get("za", models.RegistrationTypeBusinessName)
+
+	items, completeness := set.Evaluate(Evidence{})
+	testhelpers.AssertEqual(t, len(set.Items), len(items))
+	testhelpers.AssertEqual(t, 0, completeness)
+	for _, item := range items {
+		testhelpers.AssertEqual(t, models.ChecklistStatusMissing, item.Status, item.Key)
+	}
+	testhelpers.AssertEqual(t, 5, len(Unmet(items, models.RegistrationStatusSubmitted)))
+}
+
+func TestEvaluate_DocumentsPerDirector(t *testing.T) {
+	set := DefaultRegistry().Get("ZA", models.RegistrationTypePtyLtd)
+	formData := map[string]interface{}{
+		"financial_year_end": float64(2),
+		"authorised_shares":  float64(1000),
+		"registered_address": map[string]interface{}{"line1": "1 Main Road", "city": "Cape Town"},
+		"directors": []interface{}{
+			map[string]interface{}{"full_name": "Thandi Nkosi"},
+			map[string]interface{}{"full_name": "Pieter Botha"},
+		},
+		"shareholders": []interface{}{map[string]interface{}{"full_name": "Thandi Nkosi"}},
+	}
+	documents := DocumentCounts{
+		models.DocumentTypeID:             {models.DocumentStatusVerified: 1, models.DocumentStatusRejected: 1},
+		models.DocumentTypeProofOfAddress: {models.DocumentStatusPending: 1},
+	}
+
+	items, _ := set.Evaluate(Evidence{FormData: formData, Documents: documents})
+	index := byKey(items)
+	testhelpers.AssertEqual(t, models.ChecklistStatusRejected, index["document.id_document"].Status, "One director has no usable ID document")
+	testhelpers.AssertEqual(t, models.ChecklistStatusPending, index["document.proof_of_address"].Status)
+	testhelpers.AssertEqual(t, models.ChecklistStatusComplete, index["field.registered_address"].Status)
+	testhelpers.AssertEqual(t, 1, len(Unmet(items, models.RegistrationStatusSubmitted)))
+
+	documents[models.DocumentTypeID][models.DocumentStatusPending] = 1
+	items, completeness := set.Evaluate(Evidence{FormData: formData, Documents: documents})
+	testhelpers.AssertEqual(t, 0, len(Unmet(items, models.RegistrationStatusSubmitted)), "Documents awaiting verification may be submitted")
+	testhelpers.AssertEqual(t, 2, len(Unmet(items, models.RegistrationStatusApproved)))
+	// 5 fields done, 2 documents half done, 2 verifications outstanding
+	testhelpers.AssertEqual(t, 66, completeness)
+}
+
+func TestGet_UnknownTypeHasNoRequirements(t *testing.T) {
+	set := DefaultRegistry().Get("ZW", models.RegistrationTypeCloseCorporation)
+
+	items, completeness := set.Evaluate(Evidence{})
+	testhelpers.AssertEqual(t, 0, len(items))
+	testhelpers.AssertEqual(t, 100, completeness)
+}
+
+func TestDefaultRegistry_NameReservedWithJurisdictionRegistry(t *testing.T) {
+	items, _ := DefaultRegistry().Get("ZW", models.RegistrationTypePtyLtd).Evaluate(Evidence{})
+	item := byKey(items)[models.ChecklistItemVerification+"."+VerificationNameReserved]
+	testhelpers.AssertNotNil(t, item)
+	testhelpers.AssertEqual(t, "Company name reserved with DCIP", item.Label)
+
+	items, _ = DefaultRegistry().Get("ZW", models.RegistrationTypeBusinessName).Evaluate(Evidence{})
+	testhelpers.AssertNil(t, byKey(items)[models.ChecklistItemVerification+"."+VerificationNameReserved])
+}
@@ -0,0 +1,146 @@
+package search
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// MaxTerms caps how many words of a query are searched for
+const MaxTerms = 8
+
+// Terms splits a search query into distinct lower-cased words. Punctuation
+// separates words, so "K2024/123456/07" searches for k2024, 123456 and 07.
+func Terms(query string) []string {
+	words := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	terms := []string{}
+	seen := map[string]bool{}
+	for _, word := range words {
+		if seen[word] {
+			continue
+		}
+		seen[word] = true
+		terms = append(terms, word)
+		if len(terms) == MaxTerms {
+			break
+		}
+	}
+	return terms
+}
+
+// TSQuery returns a tsquery matching documents containing a word starting with
+// every term, so results narrow as the user types. Terms hold only letters and
+// digits, so they need no quoting.
+func TSQuery(terms []string) string {
+	parts := make([]string, len(terms))
+	for i, term := range terms {
+		parts[i] = term + ":*"
+	}
+	return strings.Join(parts, " & ")
+}
+
+// LikePattern returns an ILIKE pattern matching values containing text, with
+// the wildcards in text escaped
+func LikePattern(text string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(strings.TrimSpace(text))
+	return "%" + escaped + "%"
+}
+
+// Relevance is the sort field ordering results by how well they match
+const Relevance = "relevance"
+
+// Range is a date range filter. To is exclusive.
+type Range struct {
+	From *time.Time
+	To   *time.Time
+}
+
+// ParseRange parses from/to filter values, each a date (2006-01-02) or an
+// RFC 3339 time. A date-only to includes the whole day.
+func ParseRange(from, to string) (Range, error) {
+	var r Range
+	if from != "" {
+		t, _, err := parseTime(from)
+		if err != nil {
+			return r, err
+		}
+		r.From = &t
+	}
+	if to != "" {
+		t, dateOnly, err := parseTime(to)
+		if err != nil {
+			return r, err
+		}
+		if dateOnly {
+			t = t.AddDate(0, 0, 1)
+		}
+		r.To = &t
+	}
+	if r.From != nil && r.To != nil && !r.From.Before(*r.To) {
+		return r, fmt.Errorf("invalid date range")
+	}
+	return r, nil
+}
+
+func parseTime(value string) (time.Time, bool, error) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, true, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid date")
+	}
+	return t.UTC(), false, nil
+}
+
+// Highlight returns text HTML-escaped with every occurrence of the terms wrapped
+// in <mark> tags, or "" when no term occurs in it
+func Highlight(text string, terms []string) string {
+	runes := []rune(text)
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	marked := make([]bool, len(runes))
+	found := false
+	for _, term := range terms {
+		needle := []rune(term)
+		if len(needle) == 0 {
+			continue
+		}
+		for i := 0; i+len(needle) <= len(lower); i++ {
+			if string(lower[i:i+len(needle)]) != term {
+				continue
+			}
+			for j := i; j < i+len(needle); j++ {
+				marked[j] = true
+			}
+			found = true
+		}
+	}
+	if !found {
+		return ""
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(runes); {
+		j := i
+		for j < len(runes) && marked[j] == marked[i] {
+			j++
+		}
+		segment := html.EscapeString(string(runes[i:j]))
+		if marked[i] {
+			b.WriteString("<mark>" + segment + "</mark>")
+		} else {
+			b.WriteString(segment)
+		}
+		i = j
+	}
+	return b.String()
+}
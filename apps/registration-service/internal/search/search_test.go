@@ -0,0 +1,41 @@
+package search
+
+import (
+	"testing"
+	"time"
+
+	testhelpers "github.com/comply360/shared/testing"
+)
+
+func TestTerms_SplitsOnPunctuation(t *testing.T) {
+	terms := Terms("  Acme K2024/123456/07 acme ")
+
+	testhelpers.AssertEqual(t, 4, len(terms))
+	testhelpers.AssertEqual(t, "acme", terms[0])
+	testhelpers.AssertEqual(t, "k2024", terms[1])
+	testhelpers.AssertEqual(t, "07", terms[3])
+	testhelpers.AssertEqual(t, "acme:* & k2024:* & 123456:* & 07:*", TSQuery(terms))
+	testhelpers.AssertEqual(t, 0, len(Terms("'; --")))
+}
+
+func TestLikePattern_EscapesWildcards(t *testing.T) {
+	testhelpers.AssertEqual(t, `%50\% off\_now%`, LikePattern(" 50% off_now "))
+}
+
+func TestParseRange_DateOnlyIncludesWholeDay(t *testing.T) {
+	r, err := ParseRange("2026-10-01", "2026-10-18")
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertTrue(t, r.From.Equal(time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)))
+	testhelpers.AssertTrue(t, r.To.Equal(time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)))
+
+	_, err = ParseRange("2026-10-18", "2026-10-01T00:00:00Z")
+	testhelpers.AssertError(t, err)
+	_, err = ParseRange("yesterday", "")
+	testhelpers.AssertError(t, err)
+}
+
+func TestHighlight_MarksTermsAndEscapes(t *testing.T) {
+	testhelpers.AssertEqual(t, "<mark>Acme</mark> &amp; Sons <mark>Acme</mark>", Highlight("Acme & Sons Acme", []string{"acme"}))
+	testhelpers.AssertEqual(t, "<mark>Ström</mark> Holdings", Highlight("Ström Holdings", []string{"str", "röm"}))
+	testhelpers.AssertEqual(t, "", Highlight("Acme", []string{"globex"}))
+}
@@ -0,0 +1,323 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/comply360/registration-service/internal/clients"
+	"github.com/comply360/registration-service/internal/repository"
+	"github.com/comply360/shared/models"
+	"github.com/comply360/shared/pagination"
+	"github.com/comply360/shared/validator"
+	"github.com/google/uuid"
+)
+
+// ClientService manages clients: their details and addresses, the user account
+// they sign in with, duplicates and KYC
+type ClientService struct {
+	repo      *repository.ClientRepository
+	validator *validator.Validator
+}
+
+func NewClientService(repo *repository.ClientRepository) *ClientService {
+	return &ClientService{
+		repo:      repo,
+		validator: validator.New(),
+	}
+}
+
+// DuplicateClientError refuses a new client that may already exist
+type DuplicateClientError struct {
+	Duplicates []*models.ClientDuplicate
+}
+
+func (e *DuplicateClientError) Error() string {
+	return "possible duplicate client"
+}
+
+// CreateClient creates a client. Unless allowDuplicates is set, a client
+// sharing an identity number, email address or company name with an existing
+// client is refused with a DuplicateClientError listing the matches.
+func (s *ClientService) CreateClient(schema string, tenantID uuid.UUID, req *models.CreateClientRequest, allowDuplicates bool) (*models.Client, error) {
+	if err := s.validator.Validate(req); err != nil {
+		return nil, err
+	}
+
+	client := &models.Client{
+		TenantID:       tenantID,
+		ClientType:     req.ClientType,
+		FullName:       trimmed(req.FullName),
+		CompanyName:    trimmed(req.CompanyName),
+		IDNumber:       trimmed(req.IDNumber),
+		PassportNumber: trimmed(req.PassportNumber),
+		TaxNumber:      trimmed(req.TaxNumber),
+		VATNumber:      trimmed(req.VATNumber),
+		Email:          strings.ToLower(strings.TrimSpace(req.Email)),
+		Phone:          trimmed(req.Phone),
+		Mobile:         trimmed(req.Mobile),
+		StreetAddress:  trimmed(req.StreetAddress),
+		City:           trimmed(req.City),
+		StateProvince:  trimmed(req.StateProvince),
+		PostalCode:     trimmed(req.PostalCode),
+		Country:        countryCode(req.CountryCode),
+		Status:         models.ClientStatusActive,
+	}
+	if err := clients.CheckAddress(client); err != nil {
+		return nil, err
+	}
+
+	if !allowDuplicates {
+		duplicates, err := s.duplicates(schema, client)
+		if err != nil {
+			return nil, err
+		}
+		if len(duplicates) > 0 {
+			return nil, &DuplicateClientError{Duplicates: duplicates}
+		}
+	}
+
+	if err := s.repo.Create(schema, client); err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// GetClient retrieves a client with its KYC profile
+func (s *ClientService) GetClient(schema string, tenantID, clientID uuid.UUID) (*models.Client, error) {
+	client, err := s.repo.GetByID(schema, tenantID, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	client.KYC, err = s.assess(schema, client)
+	if err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// ListClients retrieves a page of clients
+func (s *ClientService) ListClients(schema string, tenantID uuid.UUID, status, clientType string, page pagination.Page) (*pagination.List[*models.Client], error) {
+	query, err := repository.ClientKeys.Query(page)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.List(schema, tenantID, status, clientType, query)
+}
+
+// UpdateClient changes a client's details. Fields left out of the request are
+// kept; an empty string clears an optional field.
+func (s *ClientService) UpdateClient(schema string, tenantID, clientID uuid.UUID, req *models.UpdateClientRequest) (*models.Client, error) {
+	if err := s.validator.Validate(req); err != nil {
+		return nil, err
+	}
+
+	client, err := s.repo.GetByID(schema, tenantID, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	update := func(field **string, value *string) {
+		if value != nil {
+			*field = trimmed(value)
+		}
+	}
+	update(&client.FullName, req.FullName)
+	update(&client.CompanyName, req.CompanyName)
+	update(&client.IDNumber, req.IDNumber)
+	update(&client.PassportNumber, req.PassportNumber)
+	update(&client.TaxNumber, req.TaxNumber)
+	update(&client.VATNumber, req.VATNumber)
+	update(&client.Phone, req.Phone)
+	update(&client.Mobile, req.Mobile)
+	update(&client.StreetAddress, req.StreetAddress)
+	update(&client.City, req.City)
+	update(&client.StateProvince, req.StateProvince)
+	update(&client.PostalCode, req.PostalCode)
+	if req.CountryCode != nil {
+		client.Country = countryCode(req.CountryCode)
+	}
+	if req.Email != nil {
+		client.Email = strings.ToLower(strings.TrimSpace(*req.Email))
+	}
+	if req.Status != nil {
+		client.Status = *req.Status
+	}
+
+	if client.Email == "" {
+		return nil, fmt.Errorf("email is required")
+	}
+	if client.ClientType == models.ClientTypeIndividual && client.FullName == nil {
+		return nil, fmt.Errorf("full_name is required for individual clients")
+	}
+	if client.ClientType == models.ClientTypeCompany && client.CompanyName == nil {
+		return nil, fmt.Errorf("company_name is required for company clients")
+	}
+	if err := clients.CheckAddress(client); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(schema, client); err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// DeleteClient deletes a client
+func (s *ClientService) DeleteClient(schema string, tenantID, clientID uuid.UUID) error {
+	return s.repo.Delete(schema, tenantID, clientID)
+}
+
+// LinkUser links a client to the user account it signs in with. A user can be
+// linked to one client.
+func (s *ClientService) LinkUser(schema string, tenantID, clientID uuid.UUID, req *models.LinkClientUserRequest) (*models.Client, error) {
+	if err := s.validator.Validate(req); err != nil {
+		return nil, err
+	}
+	userID := uuid.MustParse(req.UserID)
+
+	client, err := s.repo.GetByID(schema, tenantID, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := s.repo.UserExists(schema, tenantID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	client.UserID = &userID
+	if err := s.repo.Update(schema, client); err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// UnlinkUser removes a client's user account link
+func (s *ClientService) UnlinkUser(schema string, tenantID, clientID uuid.UUID) (*models.Client, error) {
+	client, err := s.repo.GetByID(schema, tenantID, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	client.UserID = nil
+	if err := s.repo.Update(schema, client); err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// FindDuplicates returns the clients that may be the same as a client
+func (s *ClientService) FindDuplicates(schema string, tenantID, clientID uuid.UUID) ([]*models.ClientDuplicate, error) {
+	client, err := s.repo.GetByID(schema, tenantID, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.duplicates(schema, client)
+}
+
+func (s *ClientService) duplicates(schema string, client *models.Client) ([]*models.ClientDuplicate, error) {
+	found, err := s.repo.FindDuplicates(schema, client.TenantID, client)
+	if err != nil {
+		return nil, err
+	}
+
+	duplicates := []*models.ClientDuplicate{}
+	for _, other := range found {
+		if reasons := clients.Reasons(client, other); len(reasons) > 0 {
+			duplicates = append(duplicates, &models.ClientDuplicate{Client: other, Reasons: reasons})
+		}
+	}
+	return duplicates, nil
+}
+
+// MergeClients merges a duplicate source client into the target. The source's
+// registrations and documents move to the target, the target takes any details
+// it lacks from the source, and the source is deleted.
+func (s *ClientService) MergeClients(schema string, tenantID, targetID uuid.UUID, req *models.MergeClientRequest, mergedBy *uuid.UUID) (*models.Client, *models.ClientMerge, error) {
+	if err := s.validator.Validate(req); err != nil {
+		return nil, nil, err
+	}
+	sourceID := uuid.MustParse(req.SourceClientID)
+	if sourceID == targetID {
+		return nil, nil, fmt.Errorf("cannot merge a client into itself")
+	}
+
+	target, err := s.repo.GetByID(schema, tenantID, targetID)
+	if err != nil {
+		return nil, nil, err
+	}
+	source, err := s.repo.GetByID(schema, tenantID, sourceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if target.ClientType != source.ClientType {
+		return nil, nil, fmt.Errorf("cannot merge clients of different types")
+	}
+	if target.UserID != nil && source.UserID != nil && *target.UserID != *source.UserID {
+		return nil, nil, fmt.Errorf("clients are linked to different users")
+	}
+
+	clients.Merge(target, source)
+	merge, err := s.repo.Merge(schema, target, sourceID, mergedBy)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	target.KYC, err = s.assess(schema, target)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return target, merge, nil
+}
+
+// GetKYC returns a client's KYC profile
+func (s *ClientService) GetKYC(schema string, tenantID, clientID uuid.UUID) (*models.ClientKYC, error) {
+	client, err := s.repo.GetByID(schema, tenantID, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.assess(schema, client)
+}
+
+func (s *ClientService) assess(schema string, client *models.Client) (*models.ClientKYC, error) {
+	documents, err := s.repo.ListDocuments(schema, client.TenantID, client.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return clients.AssessKYC(client.ClientType, documents), nil
+}
+
+// trimmed trims an optional value, treating an empty one as absent
+func trimmed(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	v := strings.TrimSpace(*value)
+	if v == "" {
+		return nil
+	}
+	return &v
+}
+
+func countryCode(value *string) *string {
+	code := trimmed(value)
+	if code != nil {
+		upper := strings.ToUpper(*code)
+		code = &upper
+	}
+	return code
+}
@@ -0,0 +1,433 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/comply360/registration-service/internal/compliance"
+	"github.com/comply360/registration-service/internal/repository"
+	"github.com/comply360/shared/models"
+	"github.com/comply360/shared/pagination"
+	"github.com/comply360/shared/settings"
+	"github.com/comply360/shared/validator"
+	"github.com/google/uuid"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// overdueNotice is the last reminder recorded once an obligation's overdue
+// notice is sent
+const overdueNotice = -1
+
+// ComplianceService tracks the recurring filings companies registered through
+// the platform owe: it schedules them when a registration completes and rolls
+// the schedule forward, marks missed ones overdue and reminds clients as they
+// fall due. Calendars of a tenant's or a client's obligations are served as
+// iCalendar feeds.
+type ComplianceService struct {
+	repo        *repository.ComplianceRepository
+	settings    *settings.Client
+	validator   *validator.Validator
+	feedSecret  []byte
+	feedBaseURL string
+	rabbitConn  *amqp.Connection
+	rabbitCh    *amqp.Channel
+}
+
+// NewComplianceService creates the service. Calendar feed URLs are signed with
+// feedSecret and served under feedBaseURL.
+func NewComplianceService(repo *repository.ComplianceRepository, rabbitConn *amqp.Connection, settingsClient *settings.Client, feedSecret []byte, feedBaseURL string) (*ComplianceService, error) {
+	ch, err := rabbitConn.Channel()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open channel: %w", err)
+	}
+
+	// Declare exchange for registration events
+	err = ch.ExchangeDeclare(
+		"comply360.registrations", // name
+		"topic",                   // type
+		true,                      // durable
+		false,                     // auto-deleted
+		false,                     // internal
+		false,                     // no-wait
+		nil,                       // arguments
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to declare exchange: %w", err)
+	}
+
+	return &ComplianceService{
+		repo:        repo,
+		settings:    settingsClient,
+		validator:   validator.New(),
+		feedSecret:  feedSecret,
+		feedBaseURL: strings.TrimSuffix(feedBaseURL, "/"),
+		rabbitConn:  rabbitConn,
+		rabbitCh:    ch,
+	}, nil
+}
+
+// Track schedules the obligations a completed registration's company owes
+// over the next year
+func (s *ComplianceService) Track(schema string, registration *models.Registration) error {
+	_, err := s.schedule(schema, registration, s.today(registration.TenantID, time.Now()))
+	return err
+}
+
+// schedule stores the obligations a company owes from the day its
+// registration completed to a year after today. Obligations already scheduled
+// are kept as they are.
+func (s *ComplianceService) schedule(schema string, registration *models.Registration, today time.Time) (int, error) {
+	company := compliance.CompanyOf(registration)
+	since := company.RegisteredOn
+	if registration.StatusChangedAt != nil {
+		since = compliance.Date(*registration.StatusChangedAt)
+	}
+
+	obligations := compliance.Obligations(company, since, today.AddDate(0, compliance.HorizonMonths, 0))
+	if len(obligations) == 0 {
+		return 0, nil
+	}
+	for _, obligation := range obligations {
+		obligation.RegistrationID = registration.ID
+		obligation.Status = compliance.StatusOn(obligation.DueDate, today)
+	}
+
+	return s.repo.Insert(schema, registration.TenantID, obligations)
+}
+
+// ListObligations retrieves a page of a tenant's obligations
+func (s *ComplianceService) ListObligations(schema string, tenantID uuid.UUID, filter repository.ComplianceFilter, page pagination.Page) (*pagination.List[*models.ComplianceObligation], error) {
+	query, err := repository.ComplianceKeys.Query(page)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.List(schema, tenantID, filter, query)
+}
+
+// GetObligation retrieves an obligation
+func (s *ComplianceService) GetObligation(schema string, tenantID, obligationID uuid.UUID) (*models.ComplianceObligation, error) {
+	return s.repo.GetByID(schema, tenantID, obligationID)
+}
+
+// CompleteObligation records an open obligation as filed
+func (s *ComplianceService) CompleteObligation(schema string, tenantID, obligationID uuid.UUID, req *models.CompleteObligationRequest, completedBy *uuid.UUID) (*models.ComplianceObligation, error) {
+	if err := s.validator.Validate(req); err != nil {
+		return nil, err
+	}
+
+	obligation, err := s.open(schema, tenantID, obligationID)
+	if err != nil {
+		return nil, err
+	}
+
+	completedAt := time.Now()
+	if req.CompletedAt != nil {
+		if req.CompletedAt.After(completedAt) {
+			return nil, fmt.Errorf("completion date cannot be in the future")
+		}
+		completedAt = *req.CompletedAt
+	}
+
+	obligation.Status = models.ObligationStatusCompleted
+	obligation.CompletedAt = &completedAt
+	obligation.CompletedBy = completedBy
+	obligation.FilingReference = trimmed(req.FilingReference)
+	if req.Notes != nil {
+		obligation.Notes = trimmed(req.Notes)
+	}
+
+	if err := s.repo.Save(schema, obligation); err != nil {
+		return nil, err
+	}
+	return obligation, nil
+}
+
+// WaiveObligation records why an open obligation does not have to be met, such
+// as a company deregistered before it fell due
+func (s *ComplianceService) WaiveObligation(schema string, tenantID, obligationID uuid.UUID, req *models.WaiveObligationRequest, waivedBy *uuid.UUID) (*models.ComplianceObligation, error) {
+	if err := s.validator.Validate(req); err != nil {
+		return nil, err
+	}
+
+	obligation, err := s.open(schema, tenantID, obligationID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	obligation.Status = models.ObligationStatusWaived
+	obligation.CompletedAt = &now
+	obligation.CompletedBy = waivedBy
+	obligation.Notes = trimmed(&req.Reason)
+
+	if err := s.repo.Save(schema, obligation); err != nil {
+		return nil, err
+	}
+	return obligation, nil
+}
+
+// ReopenObligation reopens a completed or waived obligation, such as a filing
+// recorded against the wrong period
+func (s *ComplianceService) ReopenObligation(schema string, tenantID, obligationID uuid.UUID) (*models.ComplianceObligation, error) {
+	obligation, err := s.repo.GetByID(schema, tenantID, obligationID)
+	if err != nil {
+		return nil, err
+	}
+	if obligation.IsOpen() {
+		return nil, fmt.Errorf("compliance obligation is already open")
+	}
+
+	obligation.Status = compliance.StatusOn(obligation.DueDate, s.today(tenantID, time.Now()))
+	obligation.CompletedAt = nil
+	obligation.CompletedBy = nil
+	obligation.FilingReference = nil
+
+	if err := s.repo.Save(schema, obligation); err != nil {
+		return nil, err
+	}
+	return obligation, nil
+}
+
+// open retrieves an obligation that still has to be met
+func (s *ComplianceService) open(schema string, tenantID, obligationID uuid.UUID) (*models.ComplianceObligation, error) {
+	obligation, err := s.repo.GetByID(schema, tenantID, obligationID)
+	if err != nil {
+		return nil, err
+	}
+	if !obligation.IsOpen() {
+		return nil, fmt.Errorf("compliance obligation is already closed")
+	}
+	return obligation, nil
+}
+
+// Calendar renders the iCalendar feed of a tenant's obligations, or of one
+// client's, from a year back onwards
+func (s *ComplianceService) Calendar(schema string, tenantID uuid.UUID, clientID *uuid.UUID) (string, error) {
+	from := s.today(tenantID, time.Now()).AddDate(-1, 0, 0)
+	obligations, err := s.repo.ListAll(schema, tenantID, repository.ComplianceFilter{ClientID: clientID, DueFrom: &from})
+	if err != nil {
+		return "", err
+	}
+
+	name := "Compliance calendar"
+	if clientID != nil && len(obligations) > 0 {
+		name = fmt.Sprintf("Compliance calendar - %s", obligations[0].CompanyName)
+	}
+	return compliance.ICal(name, obligations), nil
+}
+
+// FeedURL returns the calendar feed URL of a tenant's obligations, or of one
+// client's, that calendar apps can subscribe to without signing in
+func (s *ComplianceService) FeedURL(tenantID uuid.UUID, clientID *uuid.UUID) (*models.ComplianceFeed, error) {
+	version, _, err := s.repo.FeedVersion(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return s.feedURL(tenantID, clientID, version), nil
+}
+
+// RotateFeed revokes every calendar feed URL issued for a tenant and its
+// clients, and returns the tenant's new feed URL
+func (s *ComplianceService) RotateFeed(tenantID uuid.UUID) (*models.ComplianceFeed, error) {
+	version, err := s.repo.RotateFeedVersion(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return s.feedURL(tenantID, nil, version), nil
+}
+
+func (s *ComplianceService) feedURL(tenantID uuid.UUID, clientID *uuid.UUID, version int) *models.ComplianceFeed {
+	token := compliance.FeedToken(s.feedSecret, tenantID, clientID, version)
+	return &models.ComplianceFeed{
+		URL:      fmt.Sprintf("%s/public/compliance/feeds/%s.ics", s.feedBaseURL, token),
+		ClientID: clientID,
+	}
+}
+
+// FeedCalendar renders the calendar a feed token was issued for. Tokens of a
+// rotated feed version and feeds of suspended or deleted tenants are refused
+// as if the token were invalid.
+func (s *ComplianceService) FeedCalendar(token string) (string, error) {
+	tenantID, clientID, version, err := compliance.ParseFeedToken(s.feedSecret, strings.TrimSuffix(token, ".ics"))
+	if err != nil {
+		return "", err
+	}
+
+	current, active, err := s.repo.FeedVersion(tenantID)
+	if err != nil && err.Error() != "tenant not found" {
+		return "", err
+	}
+	if err != nil || !active || version != current {
+		return "", fmt.Errorf("invalid calendar feed token")
+	}
+
+	tenant := models.Tenant{ID: tenantID}
+	return s.Calendar(tenant.TenantSchema(), tenantID, clientID)
+}
+
+// CheckObligations runs the daily compliance check for every active tenant:
+// it rolls each completed registration's schedule forward, marks obligations
+// past their due date overdue and sends the reminders that are due. It returns
+// how many reminders and overdue notices were sent.
+func (s *ComplianceService) CheckObligations() (int, error) {
+	tenantIDs, err := s.repo.ListActiveTenants()
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, tenantID := range tenantIDs {
+		count, err := s.checkTenant(tenantID, s.today(tenantID, time.Now()))
+		sent += count
+		if err != nil {
+			fmt.Printf("Warning: Compliance check failed for tenant %s: %v\n", tenantID, err)
+		}
+	}
+
+	return sent, nil
+}
+
+// checkTenant runs the daily compliance check for one tenant
+func (s *ComplianceService) checkTenant(tenantID uuid.UUID, today time.Time) (int, error) {
+	tenant := models.Tenant{ID: tenantID}
+	schema := tenant.TenantSchema()
+
+	registrations, err := s.repo.ListCompletedRegistrations(schema, tenantID)
+	if err != nil {
+		return 0, err
+	}
+	for _, registration := range registrations {
+		if _, err := s.schedule(schema, registration, today); err != nil {
+			fmt.Printf("Warning: Failed to schedule obligations of registration %s: %v\n", registration.ID, err)
+		}
+	}
+
+	if _, err := s.repo.MarkOverdue(schema, tenantID, today); err != nil {
+		return 0, err
+	}
+
+	reminderDays := s.reminderDays(tenantID)
+	longest := 0
+	for _, days := range reminderDays {
+		longest = max(longest, days)
+	}
+
+	due, err := s.repo.ListDueReminders(schema, tenantID, today.AddDate(0, 0, longest))
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, reminder := range due {
+		obligation := reminder.Obligation
+		eventType, days := "compliance.reminder", 0
+		if obligation.Status == models.ObligationStatusOverdue {
+			eventType, days = "compliance.overdue", overdueNotice
+		} else {
+			next, ok := compliance.NextReminder(reminderDays, obligation.DueDate, today, obligation.LastReminderDays)
+			if !ok {
+				continue
+			}
+			days = next
+		}
+
+		err := s.publishEvent(eventType, &models.ComplianceReminder{
+			ObligationID:   obligation.ID,
+			TenantID:       tenantID,
+			RegistrationID: obligation.RegistrationID,
+			ClientID:       obligation.ClientID,
+			ClientName:     reminder.ClientName,
+			ClientEmail:    reminder.ClientEmail,
+			CompanyName:    obligation.CompanyName,
+			ObligationType: obligation.ObligationType,
+			Authority:      obligation.Authority,
+			Title:          obligation.Title,
+			DueDate:        obligation.DueDate,
+			DaysRemaining:  compliance.DaysUntil(obligation.DueDate, today),
+			Overdue:        obligation.Status == models.ObligationStatusOverdue,
+		})
+		if err != nil {
+			fmt.Printf("Warning: Failed to publish event: %v\n", err)
+			continue
+		}
+
+		if err := s.repo.MarkReminded(schema, tenantID, obligation.ID, days); err != nil {
+			return sent, err
+		}
+		sent++
+	}
+
+	return sent, nil
+}
+
+// reminderDays returns how many days before an obligation falls due the
+// tenant's clients are reminded
+func (s *ComplianceService) reminderDays(tenantID uuid.UUID) []int {
+	days := []int{30, 7, 1}
+	if s.settings != nil {
+		var configured []int
+		err := s.settings.JSON(context.Background(), tenantID, settings.KeyComplianceReminderDays, &configured)
+		if err == nil {
+			days = configured
+		} else {
+			fmt.Printf("Warning: Failed to read compliance reminder days for tenant %s: %v\n", tenantID, err)
+		}
+	}
+	return days
+}
+
+// today returns the date in the tenant's timezone
+func (s *ComplianceService) today(tenantID uuid.UUID, now time.Time) time.Time {
+	if s.settings != nil {
+		name, err := s.settings.String(context.Background(), tenantID, settings.KeyTimezone)
+		if err == nil {
+			if location, err := time.LoadLocation(name); err == nil {
+				now = now.In(location)
+			} else {
+				fmt.Printf("Warning: Unknown timezone %s for tenant %s: %v\n", name, tenantID, err)
+			}
+		}
+	}
+	return compliance.Date(now)
+}
+
+// publishEvent publishes an event to RabbitMQ
+func (s *ComplianceService) publishEvent(eventType string, data interface{}) error {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event data: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err = s.rabbitCh.PublishWithContext(
+		ctx,
+		"comply360.registrations", // exchange
+		eventType,                 // routing key
+		false,                     // mandatory
+		false,                     // immediate
+		amqp.Publishing{
+			ContentType: "application/json",
+			Body:        body,
+			Timestamp:   time.Now(),
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to publish event: %w", err)
+	}
+
+	return nil
+}
+
+// Close closes the service connections
+func (s *ComplianceService) Close() error {
+	if s.rabbitCh != nil {
+		return s.rabbitCh.Close()
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/comply360/registration-service/internal/fees"
+	"github.com/comply360/registration-service/internal/repository"
+	"github.com/comply360/shared/jurisdictions"
+	"github.com/comply360/shared/models"
+	"github.com/comply360/shared/validator"
+	"github.com/google/uuid"
+)
+
+// FeeService keeps each tenant's fee schedules and quotes registrations from
+// them. A draft registration can be quoted as often as its client likes;
+// submitting it locks the quote, and the locked quote is what is invoiced and
+// earns commission.
+type FeeService struct {
+	repo             *repository.FeeRepository
+	registrationRepo *repository.RegistrationRepository
+	validator        *validator.Validator
+}
+
+func NewFeeService(repo *repository.FeeRepository, registrationRepo *repository.RegistrationRepository) *FeeService {
+	return &FeeService{
+		repo:             repo,
+		registrationRepo: registrationRepo,
+		validator:        validator.New(),
+	}
+}
+
+// CreateSchedule adds a fee schedule taking effect on a date. Its currency
+// and VAT rate default to the jurisdiction's.
+func (s *FeeService) CreateSchedule(schema string, tenantID uuid.UUID, req *models.CreateFeeScheduleRequest, createdBy *uuid.UUID) (*models.FeeSchedule, error) {
+	if err := s.validator.Validate(req); err != nil {
+		return nil, err
+	}
+
+	j, err := jurisdictions.Lookup(req.Jurisdiction)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported jurisdiction")
+	}
+	effectiveFrom, err := fees.ParseDate(req.EffectiveFrom)
+	if err != nil {
+		return nil, fmt.Errorf("invalid effective_from")
+	}
+
+	schedule := &models.FeeSchedule{
+		TenantID:         tenantID,
+		Jurisdiction:     j.Code(),
+		RegistrationType: req.RegistrationType,
+		Currency:         j.Currency(),
+		VATRate:          j.VATRate(),
+		EffectiveFrom:    effectiveFrom,
+		Items:            req.Items,
+		CreatedBy:        createdBy,
+	}
+	if req.Currency != nil {
+		schedule.Currency = strings.ToUpper(*req.Currency)
+	}
+	if req.VATRate != nil {
+		schedule.VATRate = *req.VATRate
+	}
+	if err := fees.Validate(j, schedule); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CreateSchedule(schema, schedule); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// ListSchedules returns a tenant's fee schedules, optionally for one
+// jurisdiction or registration type
+func (s *FeeService) ListSchedules(schema string, tenantID uuid.UUID, jurisdiction, registrationType string) ([]*models.FeeSchedule, error) {
+	return s.repo.ListSchedules(schema, tenantID, strings.ToUpper(jurisdiction), registrationType)
+}
+
+// GetSchedule retrieves a fee schedule
+func (s *FeeService) GetSchedule(schema string, tenantID, scheduleID uuid.UUID) (*models.FeeSchedule, error) {
+	return s.repo.GetSchedule(schema, tenantID, scheduleID)
+}
+
+// DeleteSchedule deletes a fee schedule that has not yet taken effect.
+// Schedules that have are kept, since quotes were priced from them; a new
+// schedule replaces them from its effective date.
+func (s *FeeService) DeleteSchedule(schema string, tenantID, scheduleID uuid.UUID) error {
+	schedule, err := s.repo.GetSchedule(schema, tenantID, scheduleID)
+	if err != nil {
+		return err
+	}
+	if !schedule.EffectiveFrom.After(fees.Date(time.Now())) {
+		return fmt.Errorf("fee schedule has already taken effect")
+	}
+	return s.repo.DeleteSchedule(schema, tenantID, scheduleID)
+}
+
+// QuoteRegistration quotes a draft registration from the fee schedule in
+// effect today, with the add-ons asked for, replacing its earlier quote
+func (s *FeeService) QuoteRegistration(schema string, tenantID, registrationID uuid.UUID, req *models.QuoteRegistrationRequest) (*models.Quote, error) {
+	if err := s.validator.Validate(req); err != nil {
+		return nil, err
+	}
+
+	registration, err := s.registrationRepo.GetByID(schema, tenantID, registrationID)
+	if err != nil {
+		return nil, err
+	}
+	if registration.Status != models.RegistrationStatusDraft {
+		return nil, fmt.Errorf("only draft registrations can be quoted")
+	}
+	if _, err := s.repo.GetQuote(schema, tenantID, registrationID, models.QuoteStatusLocked); err == nil {
+		return nil, fmt.Errorf("registration quote is locked")
+	} else if err.Error() != "quote not found" {
+		return nil, err
+	}
+
+	quote, err := s.price(schema, registration, req.AddOns)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SaveDraftQuote(schema, quote); err != nil {
+		return nil, err
+	}
+	return quote, nil
+}
+
+// GetQuote returns a registration's locked quote, or its draft quote until it
+// is submitted
+func (s *FeeService) GetQuote(schema string, tenantID, registrationID uuid.UUID) (*models.Quote, error) {
+	if _, err := s.registrationRepo.GetByID(schema, tenantID, registrationID); err != nil {
+		return nil, err
+	}
+
+	quote, err := s.repo.GetQuote(schema, tenantID, registrationID, models.QuoteStatusLocked)
+	if err != nil && err.Error() == "quote not found" {
+		return s.repo.GetQuote(schema, tenantID, registrationID, models.QuoteStatusDraft)
+	}
+	return quote, err
+}
+
+// SubmissionQuote returns the quote to lock as a registration is submitted:
+// its draft quote, at the amounts the client was quoted, or else a quote
+// priced now without add-ons. A registration submitted again keeps the quote
+// locked the first time, which is returned already locked.
+func (s *FeeService) SubmissionQuote(schema string, registration *models.Registration, lockedBy *uuid.UUID) (*models.Quote, error) {
+	for _, status := range []string{models.QuoteStatusLocked, models.QuoteStatusDraft} {
+		quote, err := s.repo.GetQuote(schema, registration.TenantID, registration.ID, status)
+		if err == nil {
+			if !quote.IsLocked() {
+				quote.LockedBy = lockedBy
+			}
+			return quote, nil
+		}
+		if err.Error() != "quote not found" {
+			return nil, err
+		}
+	}
+
+	quote, err := s.price(schema, registration, nil)
+	if err != nil {
+		return nil, err
+	}
+	quote.LockedBy = lockedBy
+	return quote, nil
+}
+
+// price quotes a registration from the fee schedule in effect today
+func (s *FeeService) price(schema string, registration *models.Registration, addOns []string) (*models.Quote, error) {
+	j, err := jurisdictions.Lookup(registration.Jurisdiction)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported jurisdiction")
+	}
+
+	schedules, err := s.repo.ListSchedules(schema, registration.TenantID, j.Code(), registration.RegistrationType)
+	if err != nil {
+		return nil, err
+	}
+
+	quote, err := fees.Quote(j, registration.RegistrationType, fees.Effective(schedules, time.Now()), addOns)
+	if err != nil {
+		return nil, err
+	}
+	quote.TenantID = registration.TenantID
+	quote.RegistrationID = registration.ID
+	return quote, nil
+}
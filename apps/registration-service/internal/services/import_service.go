@@ -0,0 +1,402 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/comply360/registration-service/internal/forms"
+	"github.com/comply360/registration-service/internal/imports"
+	"github.com/comply360/registration-service/internal/repository"
+	"github.com/comply360/registration-service/internal/workflow"
+	"github.com/comply360/shared/models"
+	"github.com/comply360/shared/pagination"
+	"github.com/comply360/shared/validator"
+	"github.com/google/uuid"
+)
+
+// importBatchSize is how many registrations an import creates per transaction
+const importBatchSize = 100
+
+// ImportService imports clients and registrations from CSV and XLSX files
+type ImportService struct {
+	repo          *repository.ImportRepository
+	registrations *RegistrationService
+	validator     *validator.Validator
+}
+
+func NewImportService(repo *repository.ImportRepository, registrations *RegistrationService) *ImportService {
+	return &ImportService{
+		repo:          repo,
+		registrations: registrations,
+		validator:     validator.New(),
+	}
+}
+
+// ImportRequest is an uploaded file and the mapping to read it with: the saved
+// mapping MappingID, or else Columns and Defaults
+type ImportRequest struct {
+	FileName  string
+	Data      []byte
+	MappingID *uuid.UUID
+	Columns   map[string]string
+	Defaults  map[string]string
+	DryRun    bool
+}
+
+// CreateMapping saves a column mapping
+func (s *ImportService) CreateMapping(schema string, tenantID uuid.UUID, req *models.CreateImportMappingRequest, createdBy *uuid.UUID) (*models.RegistrationImportMapping, error) {
+	if _, err := imports.NewMapping(req.Columns, req.Defaults); err != nil {
+		return nil, err
+	}
+
+	mapping := &models.RegistrationImportMapping{
+		TenantID:  tenantID,
+		Name:      strings.TrimSpace(req.Name),
+		Columns:   req.Columns,
+		Defaults:  req.Defaults,
+		CreatedBy: createdBy,
+	}
+	if err := s.repo.CreateMapping(schema, mapping); err != nil {
+		return nil, err
+	}
+
+	return mapping, nil
+}
+
+// GetMapping retrieves a column mapping
+func (s *ImportService) GetMapping(schema string, tenantID, mappingID uuid.UUID) (*models.RegistrationImportMapping, error) {
+	return s.repo.GetMapping(schema, tenantID, mappingID)
+}
+
+// ListMappings lists a tenant's column mappings
+func (s *ImportService) ListMappings(schema string, tenantID uuid.UUID) ([]*models.RegistrationImportMapping, error) {
+	return s.repo.ListMappings(schema, tenantID)
+}
+
+// UpdateMapping changes a column mapping. Imports already run keep their results.
+func (s *ImportService) UpdateMapping(schema string, tenantID, mappingID uuid.UUID, req *models.UpdateImportMappingRequest) (*models.RegistrationImportMapping, error) {
+	mapping, err := s.repo.GetMapping(schema, tenantID, mappingID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		mapping.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Columns != nil {
+		mapping.Columns = req.Columns
+	}
+	if req.Defaults != nil {
+		mapping.Defaults = req.Defaults
+	}
+	if _, err := imports.NewMapping(mapping.Columns, mapping.Defaults); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateMapping(schema, mapping); err != nil {
+		return nil, err
+	}
+
+	return mapping, nil
+}
+
+// DeleteMapping deletes a column mapping
+func (s *ImportService) DeleteMapping(schema string, tenantID, mappingID uuid.UUID) error {
+	return s.repo.DeleteMapping(schema, tenantID, mappingID)
+}
+
+// StartImport records an import and runs it in the background. The file type
+// and mapping are checked before the import is accepted; rows are read and
+// checked by the import itself.
+func (s *ImportService) StartImport(schema string, tenantID uuid.UUID, req *ImportRequest, actor workflow.Actor) (*models.RegistrationImport, error) {
+	format, err := imports.DetectFormat(req.FileName)
+	if err != nil {
+		return nil, err
+	}
+
+	columns, defaults := req.Columns, req.Defaults
+	if req.MappingID != nil {
+		saved, err := s.repo.GetMapping(schema, tenantID, *req.MappingID)
+		if err != nil {
+			return nil, err
+		}
+		columns, defaults = saved.Columns, saved.Defaults
+	}
+	mapping, err := imports.NewMapping(columns, defaults)
+	if err != nil {
+		return nil, err
+	}
+
+	record := &models.RegistrationImport{
+		TenantID:    tenantID,
+		MappingID:   req.MappingID,
+		FileName:    req.FileName,
+		Format:      format,
+		DryRun:      req.DryRun,
+		RequestedBy: actor.ID(),
+	}
+	if err := s.repo.CreateImport(schema, record); err != nil {
+		return nil, err
+	}
+
+	go s.run(schema, record, mapping, req.Data, actor)
+
+	return record, nil
+}
+
+// GetImport returns an import's progress and result
+func (s *ImportService) GetImport(schema string, tenantID, importID uuid.UUID) (*models.RegistrationImport, error) {
+	return s.repo.GetImport(schema, tenantID, importID)
+}
+
+// ListImports retrieves a page of a tenant's imports, newest first by default
+func (s *ImportService) ListImports(schema string, tenantID uuid.UUID, page pagination.Page) (*pagination.List[*models.RegistrationImport], error) {
+	query, err := repository.ImportKeys.Query(page)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.ListImports(schema, tenantID, query)
+}
+
+// ListImportErrors returns an import's row errors
+func (s *ImportService) ListImportErrors(schema string, tenantID, importID uuid.UUID) ([]models.RegistrationImportError, error) {
+	return s.repo.ListImportErrors(schema, tenantID, importID)
+}
+
+// RecoverInterrupted fails imports that were in flight when the service last stopped
+func (s *ImportService) RecoverInterrupted() {
+	count, err := s.repo.FailInterruptedImports()
+	if err != nil {
+		fmt.Printf("Warning: Failed to recover interrupted imports: %v\n", err)
+		return
+	}
+	if count > 0 {
+		fmt.Printf("Marked %d interrupted import(s) as failed\n", count)
+	}
+}
+
+// run imports a file and records the outcome. Invalid rows do not fail an
+// import: they are skipped and listed in its error report.
+func (s *ImportService) run(schema string, record *models.RegistrationImport, mapping *imports.Mapping, data []byte, actor workflow.Actor) {
+	if err := s.repo.StartImport(schema, record.TenantID, record.ID); err != nil {
+		fmt.Printf("Warning: Failed to start import %s: %v\n", record.ID, err)
+	}
+
+	stats := &models.RegistrationImportStats{}
+	problems, err := s.importRows(schema, record, mapping, data, actor, stats)
+	sort.SliceStable(problems, func(i, j int) bool { return problems[i].Row < problems[j].Row })
+
+	if err != nil {
+		fmt.Printf("Import %s for tenant %s failed: %v\n", record.ID, record.TenantID, err)
+		if err := s.repo.FailImport(schema, record.TenantID, record.ID, err.Error(), stats, problems); err != nil {
+			fmt.Printf("Warning: Failed to record import failure: %v\n", err)
+		}
+		return
+	}
+
+	if err := s.repo.CompleteImport(schema, record.TenantID, record.ID, stats, problems); err != nil {
+		fmt.Printf("Warning: Failed to record import result: %v\n", err)
+	}
+}
+
+// importRow is a row that passed every check, ready to be created
+type importRow struct {
+	row    *imports.Row
+	create *repository.ImportRegistration
+}
+
+// importRows reads, checks and, unless the import is a dry run, creates the
+// rows of a file
+func (s *ImportService) importRows(schema string, record *models.RegistrationImport, mapping *imports.Mapping, data []byte, actor workflow.Actor, stats *models.RegistrationImportStats) ([]models.RegistrationImportError, error) {
+	sheet, err := imports.Read(record.Format, data)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := mapping.Rows(sheet)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateImportProgress(schema, record.TenantID, record.ID, len(rows), 0); err != nil {
+		fmt.Printf("Warning: Failed to update import %s progress: %v\n", record.ID, err)
+	}
+
+	accepted, problems, err := s.check(schema, record.TenantID, rows, actor, stats)
+	if err != nil {
+		return problems, err
+	}
+	if record.DryRun {
+		return problems, nil
+	}
+
+	done := len(rows) - len(accepted)
+	stats.ClientsCreated = 0
+	stats.RegistrationsCreated = 0
+	for start := 0; start < len(accepted); start += importBatchSize {
+		end := start + importBatchSize
+		if end > len(accepted) {
+			end = len(accepted)
+		}
+		batch := accepted[start:end]
+
+		// A client is created with the first batch holding one of its rows, or
+		// again by a later batch if that one fails
+		var clients []*repository.ImportClient
+		included := map[*repository.ImportClient]bool{}
+		registrations := make([]*repository.ImportRegistration, len(batch))
+		for i, item := range batch {
+			registrations[i] = item.create
+			if client := item.create.Client; client != nil && client.Client.ID == uuid.Nil && !included[client] {
+				included[client] = true
+				clients = append(clients, client)
+			}
+		}
+
+		if err := s.repo.CreateBatch(schema, record.TenantID, clients, registrations); err != nil {
+			fmt.Printf("Warning: Import %s failed to create rows %d to %d: %v\n", record.ID, batch[0].row.Number, batch[len(batch)-1].row.Number, err)
+			for _, item := range batch {
+				problems = append(problems, item.row.Problem("", "row could not be saved"))
+			}
+		} else {
+			stats.ClientsCreated += len(clients)
+			stats.RegistrationsCreated += len(batch)
+			for _, item := range batch {
+				if err := s.registrations.publishEvent("registration.created", item.create.Registration); err != nil {
+					fmt.Printf("Warning: Failed to publish event: %v\n", err)
+				}
+			}
+		}
+
+		done += len(batch)
+		if err := s.repo.UpdateImportProgress(schema, record.TenantID, record.ID, len(rows), done); err != nil {
+			fmt.Printf("Warning: Failed to update import %s progress: %v\n", record.ID, err)
+		}
+	}
+
+	return problems, nil
+}
+
+// check validates rows with the shared validators and the registration's form
+// schema and workflow, and resolves the client of each row: an existing client
+// by ID, an existing client with the row's email, or a client created once for
+// all rows with that email. It fills stats with what the import would create.
+func (s *ImportService) check(schema string, tenantID uuid.UUID, rows []*imports.Row, actor workflow.Actor, stats *models.RegistrationImportStats) ([]*importRow, []models.RegistrationImportError, error) {
+	var problems []models.RegistrationImportError
+	var candidates []*imports.Row
+	var clientIDs []uuid.UUID
+	var emails []string
+	for _, row := range rows {
+		if rowProblems := row.Validate(s.validator); len(rowProblems) > 0 {
+			problems = append(problems, rowProblems...)
+			continue
+		}
+		candidates = append(candidates, row)
+		if row.Client == nil {
+			clientIDs = append(clientIDs, uuid.MustParse(row.Registration.ClientID))
+		} else {
+			emails = append(emails, row.Client.Email)
+		}
+	}
+
+	existing, err := s.repo.ExistingClients(schema, tenantID, clientIDs)
+	if err != nil {
+		return nil, problems, err
+	}
+	byEmail, err := s.repo.ClientsByEmail(schema, tenantID, emails)
+	if err != nil {
+		return nil, problems, err
+	}
+
+	var accepted []*importRow
+	matched := map[string]bool{}
+	created := map[string]*repository.ImportClient{}
+	for _, row := range candidates {
+		registration := &models.Registration{
+			TenantID:         tenantID,
+			RegistrationType: row.Registration.RegistrationType,
+			CompanyName:      row.Registration.CompanyName,
+			Jurisdiction:     row.Registration.Jurisdiction,
+		}
+
+		var client *repository.ImportClient
+		if row.Client == nil {
+			registration.ClientID = uuid.MustParse(row.Registration.ClientID)
+			if !existing[registration.ClientID] {
+				problems = append(problems, row.Problem(imports.FieldClientID, "client not found"))
+				continue
+			}
+		} else if id, ok := byEmail[row.Client.Email]; ok {
+			registration.ClientID = id
+		} else {
+			client = created[row.Client.Email]
+			if client == nil {
+				client = newImportClient(tenantID, row.Client)
+			}
+			// Stands in for the client's ID until the client is saved
+			registration.ClientID = uuid.New()
+		}
+
+		form, err := s.registrations.forms.Latest(registration.Jurisdiction, registration.RegistrationType)
+		if err != nil {
+			problems = append(problems, row.Problem("registration.registration_type", "unsupported registration_type for jurisdiction"))
+			continue
+		}
+		registration.FormData = form.Decode(row.FormData)
+
+		initial, err := s.registrations.prepare(schema, registration, actor)
+		if invalid, ok := err.(*forms.ValidationError); ok {
+			for _, field := range invalid.Fields {
+				problems = append(problems, row.Problem("registration."+field.Field, field.Message))
+			}
+			continue
+		}
+		if err != nil {
+			problems = append(problems, row.Problem("", err.Error()))
+			continue
+		}
+
+		if row.Client != nil {
+			if client == nil {
+				matched[row.Client.Email] = true
+			} else {
+				created[row.Client.Email] = client
+			}
+		}
+		accepted = append(accepted, &importRow{
+			row: row,
+			create: &repository.ImportRegistration{
+				Registration: registration,
+				Initial:      initial,
+				Client:       client,
+			},
+		})
+	}
+
+	stats.ValidRows = len(accepted)
+	stats.InvalidRows = len(rows) - len(accepted)
+	stats.ClientsMatched = len(matched)
+	stats.ClientsCreated = len(created)
+	stats.RegistrationsCreated = len(accepted)
+
+	return accepted, problems, nil
+}
+
+// newImportClient builds the client a row creates
+func newImportClient(tenantID uuid.UUID, req *models.CreateClientRequest) *repository.ImportClient {
+	return &repository.ImportClient{
+		Client: &models.Client{
+			TenantID:    tenantID,
+			ClientType:  req.ClientType,
+			FullName:    req.FullName,
+			CompanyName: req.CompanyName,
+			IDNumber:    req.IDNumber,
+			TaxNumber:   req.TaxNumber,
+			Email:       req.Email,
+			VATNumber:   req.VATNumber,
+			Phone:       req.Phone,
+			Mobile:      req.Mobile,
+			Country:     req.CountryCode,
+		},
+	}
+}
@@ -0,0 +1,283 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/comply360/registration-service/internal/integration"
+	"github.com/comply360/registration-service/internal/names"
+	"github.com/comply360/registration-service/internal/repository"
+	"github.com/comply360/shared/jurisdictions"
+	"github.com/comply360/shared/models"
+	"github.com/comply360/shared/validator"
+	"github.com/google/uuid"
+)
+
+// NameReservationService runs the name reservation a company registration
+// needs before approval: the preparer proposes up to four names, which are
+// screened locally and then lodged through the integration service with the
+// registry of the registration's jurisdiction, CIPC or DCIP. Once the registry
+// approves a name the registration takes it and its name counts as reserved
+// until the reservation expires.
+type NameReservationService struct {
+	repo          *repository.NameReservationRepository
+	registrations *repository.RegistrationRepository
+	registries    *integration.Client
+	validator     *validator.Validator
+}
+
+func NewNameReservationService(repo *repository.NameReservationRepository, registrations *repository.RegistrationRepository, registries *integration.Client) *NameReservationService {
+	return &NameReservationService{
+		repo:          repo,
+		registrations: registrations,
+		registries:    registries,
+		validator:     validator.New(),
+	}
+}
+
+// NameScreeningError refuses to submit proposed names that failed screening
+type NameScreeningError struct {
+	Reservation *models.NameReservation
+}
+
+func (e *NameScreeningError) Error() string {
+	return "proposed names failed screening"
+}
+
+// RegistryError is a failure to reach a registry or a refusal from it
+type RegistryError struct {
+	Registry string
+	Err      error
+}
+
+func (e *RegistryError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RegistryError) Unwrap() error {
+	return e.Err
+}
+
+// GetReservation retrieves a registration's current name reservation
+func (s *NameReservationService) GetReservation(schema string, tenantID, registrationID uuid.UUID) (*models.NameReservation, error) {
+	return s.repo.Latest(schema, tenantID, registrationID)
+}
+
+// ListReservations retrieves all of a registration's name reservations
+func (s *NameReservationService) ListReservations(schema string, tenantID, registrationID uuid.UUID) ([]*models.NameReservation, error) {
+	return s.repo.List(schema, tenantID, registrationID)
+}
+
+// ProposeNames sets the names proposed for a registration, in order of
+// preference, and screens them. The names of a draft reservation are replaced;
+// once a reservation has been rejected or has expired a new one is started.
+func (s *NameReservationService) ProposeNames(schema string, tenantID, registrationID uuid.UUID, req *models.ProposeNamesRequest, requestedBy *uuid.UUID) (*models.NameReservation, error) {
+	if err := s.validator.Validate(req); err != nil {
+		return nil, err
+	}
+
+	registration, err := s.reservable(schema, tenantID, registrationID)
+	if err != nil {
+		return nil, err
+	}
+
+	screened, err := s.screen(schema, registration, req.Names)
+	if err != nil {
+		return nil, err
+	}
+
+	reservation, err := s.repo.Latest(schema, tenantID, registrationID)
+	if err != nil && err.Error() != "name reservation not found" {
+		return nil, err
+	}
+	if reservation != nil {
+		switch reservation.Status {
+		case models.NameReservationStatusDraft:
+			reservation.Names = screened
+			reservation.RequestedBy = requestedBy
+			if err := s.repo.Save(schema, reservation); err != nil {
+				return nil, err
+			}
+			return reservation, nil
+		case models.NameReservationStatusSubmitted, models.NameReservationStatusApproved:
+			return nil, fmt.Errorf("name reservation has already been submitted")
+		}
+	}
+
+	reservation = &models.NameReservation{
+		TenantID:       tenantID,
+		RegistrationID: registrationID,
+		Jurisdiction:   registration.Jurisdiction,
+		Status:         models.NameReservationStatusDraft,
+		Names:          screened,
+		RequestedBy:    requestedBy,
+	}
+	if err := s.repo.Create(schema, reservation); err != nil {
+		return nil, err
+	}
+
+	return reservation, nil
+}
+
+// SubmitReservation lodges a draft reservation's names with the registry. The
+// names are screened again first, since other registrations may have taken
+// similar names since they were proposed; a NameScreeningError refuses names
+// with errors.
+func (s *NameReservationService) SubmitReservation(schema string, tenantID, registrationID uuid.UUID) (*models.NameReservation, error) {
+	registration, err := s.reservable(schema, tenantID, registrationID)
+	if err != nil {
+		return nil, err
+	}
+
+	reservation, err := s.repo.Latest(schema, tenantID, registrationID)
+	if err != nil {
+		return nil, err
+	}
+	if reservation.Status != models.NameReservationStatusDraft {
+		return nil, fmt.Errorf("name reservation is not a draft")
+	}
+
+	proposed := make([]string, len(reservation.Names))
+	for i, name := range reservation.Names {
+		proposed[i] = name.Name
+	}
+	reservation.Names, err = s.screen(schema, registration, proposed)
+	if err != nil {
+		return nil, err
+	}
+	if names.Blocked(reservation.Names) {
+		if err := s.repo.Save(schema, reservation); err != nil {
+			return nil, err
+		}
+		return nil, &NameScreeningError{Reservation: reservation}
+	}
+
+	now := time.Now()
+	result, err := s.registries.ReserveNames(reservation.Jurisdiction, proposed, registration.RegistrationType, reservation.ID.String())
+	if err != nil {
+		message := err.Error()
+		reservation.LastError = &message
+		if saveErr := s.repo.Save(schema, reservation); saveErr != nil {
+			fmt.Printf("Warning: Failed to record name reservation error: %v\n", saveErr)
+		}
+		return nil, registryError(reservation.Jurisdiction, err)
+	}
+
+	reservation.Status = models.NameReservationStatusSubmitted
+	reservation.SetReference(result.Reference)
+	reservation.SubmittedAt = &now
+	reservation.LastCheckedAt = &now
+	reservation.LastError = nil
+	names.Apply(reservation, result, now)
+	if err := s.repo.Save(schema, reservation); err != nil {
+		return nil, err
+	}
+
+	return reservation, nil
+}
+
+// RefreshReservation asks the registry whether it has decided on a submitted
+// reservation, rather than waiting for the next poll
+func (s *NameReservationService) RefreshReservation(schema string, tenantID, registrationID uuid.UUID) (*models.NameReservation, error) {
+	reservation, err := s.repo.Latest(schema, tenantID, registrationID)
+	if err != nil {
+		return nil, err
+	}
+	if reservation.Status != models.NameReservationStatusSubmitted {
+		return nil, fmt.Errorf("name reservation is not awaiting the registry")
+	}
+
+	if _, err := s.check(schema, reservation); err != nil {
+		return nil, err
+	}
+	return reservation, nil
+}
+
+// PollSubmitted checks every reservation waiting on a registry and records the
+// decisions made since the last poll. It returns how many were decided.
+func (s *NameReservationService) PollSubmitted() (int, error) {
+	reservations, err := s.repo.ListSubmitted()
+	if err != nil {
+		return 0, err
+	}
+
+	decided := 0
+	for _, reservation := range reservations {
+		tenant := models.Tenant{ID: reservation.TenantID}
+		done, err := s.check(tenant.TenantSchema(), reservation)
+		if err != nil {
+			fmt.Printf("Warning: Failed to check name reservation %s: %v\n", reservation.ID, err)
+			continue
+		}
+		if done {
+			decided++
+		}
+	}
+	return decided, nil
+}
+
+// ExpireReservations releases approved names their registry no longer holds
+func (s *NameReservationService) ExpireReservations() (int, error) {
+	return s.repo.ExpireReservations()
+}
+
+// check fetches the registry's decision on a submitted reservation and records
+// it, reporting whether the registry has decided
+func (s *NameReservationService) check(schema string, reservation *models.NameReservation) (bool, error) {
+	now := time.Now()
+	reservation.LastCheckedAt = &now
+
+	result, err := s.registries.GetNameReservation(reservation.Jurisdiction, *reservation.Reference())
+	if err != nil {
+		message := err.Error()
+		reservation.LastError = &message
+		if saveErr := s.repo.Save(schema, reservation); saveErr != nil {
+			fmt.Printf("Warning: Failed to record name reservation error: %v\n", saveErr)
+		}
+		return false, registryError(reservation.Jurisdiction, err)
+	}
+
+	reservation.LastError = nil
+	decided := names.Apply(reservation, result, now)
+	if err := s.repo.Save(schema, reservation); err != nil {
+		return false, err
+	}
+	return decided, nil
+}
+
+// reservable loads a registration that needs a name reservation and can still
+// change its name
+func (s *NameReservationService) reservable(schema string, tenantID, registrationID uuid.UUID) (*models.Registration, error) {
+	registration, err := s.registrations.GetByID(schema, tenantID, registrationID)
+	if err != nil {
+		return nil, err
+	}
+	if !names.Required(registration) {
+		return nil, fmt.Errorf("registration does not need a name reservation")
+	}
+
+	switch registration.Status {
+	case models.RegistrationStatusDraft, models.RegistrationStatusSubmitted, models.RegistrationStatusInReview:
+		return registration, nil
+	default:
+		return nil, fmt.Errorf("registration name can no longer be changed")
+	}
+}
+
+// registryError names the registry of a jurisdiction in a failure to reach it
+func registryError(jurisdiction string, err error) *RegistryError {
+	registry := strings.ToUpper(jurisdiction)
+	if j, ok := jurisdictions.Get(jurisdiction); ok {
+		registry = j.Registry()
+	}
+	return &RegistryError{Registry: registry, Err: err}
+}
+
+func (s *NameReservationService) screen(schema string, registration *models.Registration, proposed []string) ([]models.ProposedName, error) {
+	existing, err := s.repo.ExistingNames(schema, registration.TenantID, registration.ID, proposed)
+	if err != nil {
+		return nil, err
+	}
+	return names.Screen(proposed, existing), nil
+}
@@ -0,0 +1,403 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/comply360/registration-service/internal/queues"
+	"github.com/comply360/registration-service/internal/repository"
+	"github.com/comply360/registration-service/internal/sla"
+	"github.com/comply360/registration-service/internal/workflow"
+	"github.com/comply360/shared/models"
+	"github.com/comply360/shared/pagination"
+	"github.com/comply360/shared/settings"
+	"github.com/google/uuid"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// QueueService assigns registrations in review to reviewers through the
+// tenant's work queues and tracks how long they stay in each status against the
+// tenant's SLA targets
+type QueueService struct {
+	repo          *repository.QueueRepository
+	registrations *repository.RegistrationRepository
+	settings      *settings.Client
+	rabbitConn    *amqp.Connection
+	rabbitCh      *amqp.Channel
+}
+
+func NewQueueService(repo *repository.QueueRepository, registrations *repository.RegistrationRepository, rabbitConn *amqp.Connection, settingsClient *settings.Client) (*QueueService, error) {
+	ch, err := rabbitConn.Channel()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open channel: %w", err)
+	}
+
+	// Declare exchange for registration events
+	err = ch.ExchangeDeclare(
+		"comply360.registrations", // name
+		"topic",                    // type
+		true,                       // durable
+		false,                      // auto-deleted
+		false,                      // internal
+		false,                      // no-wait
+		nil,                        // arguments
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to declare exchange: %w", err)
+	}
+
+	return &QueueService{
+		repo:          repo,
+		registrations: registrations,
+		settings:      settingsClient,
+		rabbitConn:    rabbitConn,
+		rabbitCh:      ch,
+	}, nil
+}
+
+// CreateQueue creates a review queue
+func (s *QueueService) CreateQueue(schema string, tenantID uuid.UUID, req *models.CreateReviewQueueRequest) (*models.ReviewQueue, error) {
+	queue := &models.ReviewQueue{
+		TenantID:          tenantID,
+		Name:              req.Name,
+		Strategy:          req.Strategy,
+		RegistrationTypes: req.RegistrationTypes,
+		IsActive:          true,
+		Members:           []*models.ReviewQueueMember{},
+	}
+	if queue.Strategy == "" {
+		queue.Strategy = models.QueueStrategyRoundRobin
+	}
+	if queue.RegistrationTypes == nil {
+		queue.RegistrationTypes = []string{}
+	}
+
+	if err := s.repo.CreateQueue(schema, queue); err != nil {
+		return nil, err
+	}
+	return queue, nil
+}
+
+// GetQueue retrieves a review queue with its members and their load
+func (s *QueueService) GetQueue(schema string, tenantID, queueID uuid.UUID) (*models.ReviewQueue, error) {
+	return s.repo.GetQueue(schema, tenantID, queueID)
+}
+
+// ListQueues lists a tenant's review queues
+func (s *QueueService) ListQueues(schema string, tenantID uuid.UUID) ([]*models.ReviewQueue, error) {
+	return s.repo.ListQueues(schema, tenantID)
+}
+
+// UpdateQueue updates a review queue's settings
+func (s *QueueService) UpdateQueue(schema string, tenantID, queueID uuid.UUID, req *models.UpdateReviewQueueRequest) (*models.ReviewQueue, error) {
+	queue, err := s.repo.GetQueue(schema, tenantID, queueID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		queue.Name = *req.Name
+	}
+	if req.Strategy != nil {
+		queue.Strategy = *req.Strategy
+	}
+	if req.RegistrationTypes != nil {
+		queue.RegistrationTypes = req.RegistrationTypes
+	}
+	if req.IsActive != nil {
+		queue.IsActive = *req.IsActive
+	}
+
+	if err := s.repo.UpdateQueue(schema, queue); err != nil {
+		return nil, err
+	}
+	return queue, nil
+}
+
+// DeleteQueue deletes a review queue
+func (s *QueueService) DeleteQueue(schema string, tenantID, queueID uuid.UUID) error {
+	return s.repo.DeleteQueue(schema, tenantID, queueID)
+}
+
+// SetMember adds a reviewer to a queue or updates their membership
+func (s *QueueService) SetMember(schema string, tenantID, queueID, userID uuid.UUID, req *models.SetQueueMemberRequest) (*models.ReviewQueueMember, error) {
+	member := &models.ReviewQueueMember{
+		QueueID:  queueID,
+		UserID:   userID,
+		Skills:   req.Skills,
+		MaxLoad:  req.MaxLoad,
+		IsActive: true,
+	}
+	if member.Skills == nil {
+		member.Skills = []string{}
+	}
+	if req.IsActive != nil {
+		member.IsActive = *req.IsActive
+	}
+
+	if err := s.repo.SetMember(schema, tenantID, member); err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+// RemoveMember removes a reviewer from a queue. Registrations already assigned
+// to them stay assigned.
+func (s *QueueService) RemoveMember(schema string, tenantID, queueID, userID uuid.UUID) error {
+	return s.repo.RemoveMember(schema, tenantID, queueID, userID)
+}
+
+// AutoAssign assigns an unassigned registration to a reviewer of the queue its
+// type is routed to. It returns nil without assigning when no queue takes the
+// type or no member is available; the registration then waits in the overdue
+// list until someone is assigned manually.
+func (s *QueueService) AutoAssign(schema string, registration *models.Registration) (*models.RegistrationAssignment, error) {
+	if registration.AssignedTo != nil {
+		return nil, nil
+	}
+
+	all, err := s.repo.ListQueues(schema, registration.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	queue := queues.Route(all, registration.RegistrationType)
+	if queue == nil {
+		return nil, nil
+	}
+	reviewer, ok := queues.Pick(queue, registration.RegistrationType)
+	if !ok {
+		return nil, nil
+	}
+
+	assignment := &models.RegistrationAssignment{
+		RegistrationID: registration.ID,
+		QueueID:        &queue.ID,
+		ToUser:         &reviewer,
+		Method:         models.AssignmentMethodAuto,
+		Strategy:       &queue.Strategy,
+	}
+	if err := s.repo.Assign(schema, registration.TenantID, assignment); err != nil {
+		return nil, err
+	}
+
+	now := assignment.CreatedAt
+	registration.AssignedTo = &reviewer
+	registration.QueueID = &queue.ID
+	registration.AssignedAt = &now
+	s.publishAssigned(registration)
+
+	return assignment, nil
+}
+
+// Reassign assigns a registration to a reviewer by hand. The reviewer must be an
+// active member of the queue given, or else of the registration's queue.
+func (s *QueueService) Reassign(schema string, tenantID, registrationID uuid.UUID, req *models.AssignRegistrationRequest, actor workflow.Actor) (*models.RegistrationAssignment, error) {
+	registration, err := s.registrations.GetByID(schema, tenantID, registrationID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch registration.Status {
+	case models.RegistrationStatusCancelled, models.RegistrationStatusCompleted:
+		return nil, fmt.Errorf("registration is closed")
+	}
+
+	queueID := req.QueueID
+	if queueID == nil {
+		queueID = registration.QueueID
+	}
+
+	var queue *models.ReviewQueue
+	if queueID != nil {
+		queue, err = s.repo.GetQueue(schema, registration.TenantID, *queueID)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		all, err := s.repo.ListQueues(schema, registration.TenantID)
+		if err != nil {
+			return nil, err
+		}
+		if queue = queues.Route(all, registration.RegistrationType); queue == nil {
+			return nil, fmt.Errorf("no review queue for registration type")
+		}
+	}
+	if !queues.IsMember(queue, req.UserID) {
+		return nil, fmt.Errorf("assignee is not a member of the review queue")
+	}
+
+	assignment := &models.RegistrationAssignment{
+		RegistrationID: registration.ID,
+		QueueID:        &queue.ID,
+		ToUser:         &req.UserID,
+		Method:         models.AssignmentMethodManual,
+		AssignedBy:     actor.ID(),
+		Reason:         req.Reason,
+	}
+	if err := s.repo.Assign(schema, registration.TenantID, assignment); err != nil {
+		return nil, err
+	}
+
+	now := assignment.CreatedAt
+	registration.AssignedTo = &req.UserID
+	registration.QueueID = &queue.ID
+	registration.AssignedAt = &now
+	s.publishAssigned(registration)
+
+	return assignment, nil
+}
+
+// ListAssignments returns a registration's assignment history
+func (s *QueueService) ListAssignments(schema string, tenantID, registrationID uuid.UUID) ([]*models.RegistrationAssignment, error) {
+	return s.repo.ListAssignments(schema, tenantID, registrationID)
+}
+
+// ListRegistrations retrieves a page of registrations in review matching a
+// filter, soonest due first unless the page sorts otherwise
+func (s *QueueService) ListRegistrations(schema string, tenantID uuid.UUID, filter repository.QueueFilter, page pagination.Page) (*pagination.List[*models.Registration], error) {
+	query, err := repository.QueueKeys.Query(page)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.ListRegistrations(schema, tenantID, filter, query)
+}
+
+// DueAt returns when a registration that entered its status at a time breaches
+// the status's SLA target, or nil when the status has none. Business hours
+// follow the tenant's timezone.
+func (s *QueueService) DueAt(schema string, registration *models.Registration, since time.Time) (*time.Time, error) {
+	targets, err := s.repo.ListSLATargets(schema, registration.TenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	target := sla.Resolve(targets, registration.Status, registration.RegistrationType)
+	if target == nil {
+		return nil, nil
+	}
+
+	due := s.calendar(registration.TenantID).Due(target, since).UTC()
+	return &due, nil
+}
+
+// calendar returns the business calendar in the tenant's timezone
+func (s *QueueService) calendar(tenantID uuid.UUID) sla.Calendar {
+	location := time.UTC
+	if s.settings != nil {
+		name, err := s.settings.String(context.Background(), tenantID, settings.KeyTimezone)
+		if err == nil {
+			if loaded, err := time.LoadLocation(name); err == nil {
+				location = loaded
+			} else {
+				fmt.Printf("Warning: Unknown timezone %s for tenant %s: %v\n", name, tenantID, err)
+			}
+		}
+	}
+	return sla.BusinessCalendar(location)
+}
+
+// ListSLATargets returns a tenant's SLA targets
+func (s *QueueService) ListSLATargets(schema string, tenantID uuid.UUID) ([]*models.SLATarget, error) {
+	return s.repo.ListSLATargets(schema, tenantID)
+}
+
+// SetSLATarget sets the SLA target of a status, optionally for one registration
+// type. Targets apply to registrations entering the status from now on.
+func (s *QueueService) SetSLATarget(schema string, tenantID uuid.UUID, req *models.SetSLATargetRequest) (*models.SLATarget, error) {
+	target := &models.SLATarget{
+		TenantID:         tenantID,
+		Status:           req.Status,
+		RegistrationType: req.RegistrationType,
+		TargetHours:      req.TargetHours,
+		BusinessHours:    true,
+	}
+	if req.BusinessHours != nil {
+		target.BusinessHours = *req.BusinessHours
+	}
+
+	if err := s.repo.SetSLATarget(schema, target); err != nil {
+		return nil, err
+	}
+	return target, nil
+}
+
+// DeleteSLATarget deletes an SLA target
+func (s *QueueService) DeleteSLATarget(schema string, tenantID, targetID uuid.UUID) error {
+	return s.repo.DeleteSLATarget(schema, tenantID, targetID)
+}
+
+// CheckBreaches flags registrations of every active tenant that have outstayed
+// their SLA target and publishes a registration.sla.breached event for each. It
+// returns how many breaches were found.
+func (s *QueueService) CheckBreaches() (int, error) {
+	tenantIDs, err := s.repo.ListActiveTenants()
+	if err != nil {
+		return 0, err
+	}
+
+	found := 0
+	for _, tenantID := range tenantIDs {
+		tenant := models.Tenant{ID: tenantID}
+		breaches, err := s.repo.MarkBreaches(tenant.TenantSchema(), tenantID)
+		if err != nil {
+			fmt.Printf("Warning: SLA check failed for tenant %s: %v\n", tenantID, err)
+			continue
+		}
+
+		for _, breach := range breaches {
+			if err := s.publishEvent("registration.sla.breached", breach); err != nil {
+				fmt.Printf("Warning: Failed to publish event: %v\n", err)
+			}
+		}
+		found += len(breaches)
+	}
+
+	return found, nil
+}
+
+// publishAssigned announces a registration's new reviewer
+func (s *QueueService) publishAssigned(registration *models.Registration) {
+	if err := s.publishEvent("registration.assigned", registration); err != nil {
+		fmt.Printf("Warning: Failed to publish event: %v\n", err)
+	}
+}
+
+// publishEvent publishes an event to RabbitMQ
+func (s *QueueService) publishEvent(eventType string, data interface{}) error {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event data: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err = s.rabbitCh.PublishWithContext(
+		ctx,
+		"comply360.registrations", // exchange
+		eventType,                  // routing key
+		false,                      // mandatory
+		false,                      // immediate
+		amqp.Publishing{
+			ContentType: "application/json",
+			Body:        body,
+			Timestamp:   time.Now(),
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to publish event: %w", err)
+	}
+
+	return nil
+}
+
+// Close closes the service connections
+func (s *QueueService) Close() error {
+	if s.rabbitCh != nil {
+		return s.rabbitCh.Close()
+	}
+	return nil
+}
@@ -0,0 +1,333 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/comply360/registration-service/internal/register"
+	"github.com/comply360/registration-service/internal/repository"
+	"github.com/comply360/shared/models"
+	"github.com/comply360/shared/validator"
+	"github.com/google/uuid"
+)
+
+// RegisterService keeps each company's register of directors, shareholders and
+// beneficial owners. Every change records a new version of an entry, so the
+// register can be read, and beneficial ownership exported for CIPC, as at any
+// date.
+type RegisterService struct {
+	repo             *repository.RegisterRepository
+	registrationRepo *repository.RegistrationRepository
+	validator        *validator.Validator
+}
+
+func NewRegisterService(repo *repository.RegisterRepository, registrationRepo *repository.RegistrationRepository) *RegisterService {
+	return &RegisterService{
+		repo:             repo,
+		registrationRepo: registrationRepo,
+		validator:        validator.New(),
+	}
+}
+
+// GetRegister returns a registration's register as it stood on a date, or
+// today without one
+func (s *RegisterService) GetRegister(schema string, tenantID, registrationID uuid.UUID, asAt *time.Time) (*models.CompanyRegister, error) {
+	if _, err := s.registrationRepo.GetByID(schema, tenantID, registrationID); err != nil {
+		return nil, err
+	}
+
+	versions, err := s.repo.ListVersions(schema, tenantID, registrationID)
+	if err != nil {
+		return nil, err
+	}
+	return register.Build(registrationID, versions, s.asAt(asAt)), nil
+}
+
+// BeneficialOwnershipExport renders a registration's beneficial owners as at a
+// date in CIPC's filing format, with a file name for it
+func (s *RegisterService) BeneficialOwnershipExport(schema string, tenantID, registrationID uuid.UUID, asAt *time.Time) ([]byte, string, error) {
+	registration, err := s.registrationRepo.GetByID(schema, tenantID, registrationID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	versions, err := s.repo.ListVersions(schema, tenantID, registrationID)
+	if err != nil {
+		return nil, "", err
+	}
+	date := s.asAt(asAt)
+
+	data, err := register.BeneficialOwnershipCSV(registration, register.Build(registrationID, versions, date))
+	if err != nil {
+		return nil, "", err
+	}
+	number := strings.ReplaceAll(*registration.RegistrationNumber, "/", "-")
+	return data, fmt.Sprintf("beneficial-ownership-%s-%s.csv", number, date.Format("2006-01-02")), nil
+}
+
+// GetEntry returns a register entry as it stood on a date, or today without
+// one
+func (s *RegisterService) GetEntry(schema string, tenantID, registrationID, entryID uuid.UUID, asAt *time.Time) (*models.RegisterEntry, error) {
+	history, err := s.EntryHistory(schema, tenantID, registrationID, entryID)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := register.AsAt(history, s.asAt(asAt))
+	if entry == nil {
+		return nil, fmt.Errorf("register entry not found")
+	}
+	return entry, nil
+}
+
+// EntryHistory returns every version of a register entry, oldest first
+func (s *RegisterService) EntryHistory(schema string, tenantID, registrationID, entryID uuid.UUID) ([]*models.RegisterEntry, error) {
+	if _, err := s.registrationRepo.GetByID(schema, tenantID, registrationID); err != nil {
+		return nil, err
+	}
+
+	versions, err := s.repo.ListVersions(schema, tenantID, registrationID)
+	if err != nil {
+		return nil, err
+	}
+	history := entryHistory(versions, entryID)
+	if len(history) == 0 {
+		return nil, fmt.Errorf("register entry not found")
+	}
+	return history, nil
+}
+
+// CreateEntry adds a director, shareholder or beneficial owner to a
+// registration's register, in effect from the day they were appointed
+func (s *RegisterService) CreateEntry(schema string, tenantID, registrationID uuid.UUID, req *models.CreateRegisterEntryRequest, recordedBy *uuid.UUID) (*models.RegisterEntry, error) {
+	if err := s.validator.Validate(req); err != nil {
+		return nil, err
+	}
+	if err := s.editable(schema, tenantID, registrationID, req.DocumentIDs); err != nil {
+		return nil, err
+	}
+
+	entry, err := entryFrom(&req.RegisterEntryDetails)
+	if err != nil {
+		return nil, err
+	}
+	entry.ID = uuid.New()
+	entry.Version = 1
+	entry.Role = req.Role
+	entry.EffectiveFrom = entry.AppointedOn
+	entry.RecordedBy = recordedBy
+
+	return s.record(schema, tenantID, registrationID, uuid.Nil, func(history []*models.RegisterEntry) (*models.RegisterEntry, error) {
+		return entry, nil
+	})
+}
+
+// UpdateEntry records a change to a register entry taking effect from a date.
+// Changes are recorded in date order: a change cannot take effect before the
+// entry's last one.
+func (s *RegisterService) UpdateEntry(schema string, tenantID, registrationID, entryID uuid.UUID, req *models.UpdateRegisterEntryRequest, recordedBy *uuid.UUID) (*models.RegisterEntry, error) {
+	if err := s.validator.Validate(req); err != nil {
+		return nil, err
+	}
+	if err := s.editable(schema, tenantID, registrationID, req.DocumentIDs); err != nil {
+		return nil, err
+	}
+
+	next, err := entryFrom(&req.RegisterEntryDetails)
+	if err != nil {
+		return nil, err
+	}
+	effectiveFrom, err := register.ParseDate(req.EffectiveFrom)
+	if err != nil {
+		return nil, fmt.Errorf("invalid effective_from")
+	}
+
+	return s.record(schema, tenantID, registrationID, entryID, func(history []*models.RegisterEntry) (*models.RegisterEntry, error) {
+		latest := history[len(history)-1]
+		if effectiveFrom.Before(latest.EffectiveFrom) {
+			return nil, &register.RuleError{Message: fmt.Sprintf(
+				"effective_from cannot be before the entry's last change on %s", latest.EffectiveFrom.Format("2006-01-02"))}
+		}
+		next.ID = latest.ID
+		next.Version = latest.Version + 1
+		next.Role = latest.Role
+		next.EffectiveFrom = effectiveFrom
+		next.ResignedOn = latest.ResignedOn
+		next.RecordedBy = recordedBy
+		return next, nil
+	})
+}
+
+// ResignEntry records the day an entry left the register. The resignation may
+// be dated ahead, for a director serving notice.
+func (s *RegisterService) ResignEntry(schema string, tenantID, registrationID, entryID uuid.UUID, req *models.ResignRegisterEntryRequest, recordedBy *uuid.UUID) (*models.RegisterEntry, error) {
+	if err := s.validator.Validate(req); err != nil {
+		return nil, err
+	}
+	if err := s.editable(schema, tenantID, registrationID, nil); err != nil {
+		return nil, err
+	}
+
+	resignedOn, err := register.ParseDate(req.ResignedOn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid resigned_on")
+	}
+
+	return s.record(schema, tenantID, registrationID, entryID, func(history []*models.RegisterEntry) (*models.RegisterEntry, error) {
+		latest := history[len(history)-1]
+		if latest.ResignedOn != nil {
+			return nil, fmt.Errorf("register entry has already resigned")
+		}
+		next := revision(latest, resignedOn, recordedBy)
+		next.ResignedOn = &resignedOn
+		next.Note = trimmed(req.Note)
+		return next, nil
+	})
+}
+
+// RemoveEntry strikes an entry recorded in error from the register. Its
+// versions are kept for the record.
+func (s *RegisterService) RemoveEntry(schema string, tenantID, registrationID, entryID uuid.UUID, recordedBy *uuid.UUID) error {
+	if err := s.editable(schema, tenantID, registrationID, nil); err != nil {
+		return err
+	}
+
+	today := register.Date(time.Now())
+	_, err := s.record(schema, tenantID, registrationID, entryID, func(history []*models.RegisterEntry) (*models.RegisterEntry, error) {
+		next := revision(history[len(history)-1], today, recordedBy)
+		next.Removed = true
+		next.Note = nil
+		return next, nil
+	})
+	return err
+}
+
+// record stores the version change returns, once it is valid and leaves the
+// register consistent. For a change to an existing entry, given as entryID,
+// change is given the entry's versions; removed entries cannot be changed. A
+// new entry is recorded with uuid.Nil.
+func (s *RegisterService) record(schema string, tenantID, registrationID, entryID uuid.UUID, change func(history []*models.RegisterEntry) (*models.RegisterEntry, error)) (*models.RegisterEntry, error) {
+	today := register.Date(time.Now())
+	return s.repo.Record(schema, tenantID, registrationID, func(versions []*models.RegisterEntry) (*models.RegisterEntry, error) {
+		var history []*models.RegisterEntry
+		if entryID != uuid.Nil {
+			history = entryHistory(versions, entryID)
+			if len(history) == 0 || history[len(history)-1].Removed {
+				return nil, fmt.Errorf("register entry not found")
+			}
+		}
+
+		entry, err := change(history)
+		if err != nil {
+			return nil, err
+		}
+		if !entry.Removed {
+			if err := register.Validate(entry, today); err != nil {
+				return nil, err
+			}
+		}
+		if err := register.Check(append(versions, entry)); err != nil {
+			return nil, err
+		}
+		return entry, nil
+	})
+}
+
+// editable checks that a registration's register can be changed, and that
+// the supporting documents given were uploaded for the registration or its
+// client
+func (s *RegisterService) editable(schema string, tenantID, registrationID uuid.UUID, documentIDs []uuid.UUID) error {
+	registration, err := s.registrationRepo.GetByID(schema, tenantID, registrationID)
+	if err != nil {
+		return err
+	}
+	if registration.Status == models.RegistrationStatusCancelled {
+		return fmt.Errorf("cannot change the register of a cancelled registration")
+	}
+
+	if len(documentIDs) == 0 {
+		return nil
+	}
+	missing, err := s.repo.MissingDocuments(schema, tenantID, registrationID, documentIDs)
+	if err != nil {
+		return err
+	}
+	if len(missing) > 0 {
+		return &register.RuleError{Message: fmt.Sprintf("document %s was not uploaded for this registration or its client", missing[0])}
+	}
+	return nil
+}
+
+// asAt returns the date a register is read as at: the date given, or today
+func (s *RegisterService) asAt(date *time.Time) time.Time {
+	if date != nil {
+		return register.Date(*date)
+	}
+	return register.Date(time.Now())
+}
+
+// entryFrom builds a register entry from the details of a request
+func entryFrom(details *models.RegisterEntryDetails) (*models.RegisterEntry, error) {
+	appointedOn, err := register.ParseDate(details.AppointedOn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid appointed_on")
+	}
+
+	entry := &models.RegisterEntry{
+		PersonType:         details.PersonType,
+		FirstNames:         trimmed(details.FirstNames),
+		LastName:           trimmed(details.LastName),
+		IDNumber:           trimmed(details.IDNumber),
+		PassportNumber:     trimmed(details.PassportNumber),
+		Nationality:        countryCode(details.Nationality),
+		EntityName:         trimmed(details.EntityName),
+		RegistrationNumber: trimmed(details.RegistrationNumber),
+		Email:              trimmed(details.Email),
+		ResidentialAddress: trimmed(details.ResidentialAddress),
+		SharePercent:       details.SharePercent,
+		ControlType:        details.ControlType,
+		AppointedOn:        appointedOn,
+		DocumentIDs:        details.DocumentIDs,
+		Note:               trimmed(details.Note),
+	}
+	if entry.Email != nil {
+		email := strings.ToLower(*entry.Email)
+		entry.Email = &email
+	}
+	if details.DateOfBirth != nil {
+		born, err := register.ParseDate(*details.DateOfBirth)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date_of_birth")
+		}
+		entry.DateOfBirth = &born
+	}
+	if entry.DocumentIDs == nil {
+		entry.DocumentIDs = []uuid.UUID{}
+	}
+	return entry, nil
+}
+
+// revision returns the next version of an entry, unchanged, taking effect from
+// a date or from the entry's last change if that is later
+func revision(latest *models.RegisterEntry, effectiveFrom time.Time, recordedBy *uuid.UUID) *models.RegisterEntry {
+	next := *latest
+	next.Version = latest.Version + 1
+	next.EffectiveFrom = effectiveFrom
+	if latest.EffectiveFrom.After(effectiveFrom) {
+		next.EffectiveFrom = latest.EffectiveFrom
+	}
+	next.Removed = false
+	next.RecordedBy = recordedBy
+	return &next
+}
+
+// entryHistory returns the versions of one entry, oldest first
+func entryHistory(versions []*models.RegisterEntry, entryID uuid.UUID) []*models.RegisterEntry {
+	for _, history := range register.Entries(versions) {
+		if history[0].ID == entryID {
+			return history
+		}
+	}
+	return nil
+}
@@ -6,8 +6,13 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/comply360/registration-service/internal/forms"
 	"github.com/comply360/registration-service/internal/repository"
+	"github.com/comply360/registration-service/internal/requirements"
+	"github.com/comply360/registration-service/internal/workflow"
+	"github.com/comply360/shared/jurisdictions"
 	"github.com/comply360/shared/models"
+	"github.com/comply360/shared/pagination"
 	"github.com/google/uuid"
 	amqp "github.com/rabbitmq/amqp091-go"
 )
@@ -16,6 +21,13 @@ type RegistrationService struct {
 	repo       *repository.RegistrationRepository
 	rabbitConn *amqp.Connection
 	rabbitCh   *amqp.Channel
+	workflows  *workflow.Registry
+	forms      *forms.Registry
+	checklists *requirements.Registry
+	queues     *QueueService
+	compliance *ComplianceService
+	fees       *FeeService
+	hooks      map[string]func(schema string, registration *models.Registration) error
 }
 
 func NewRegistrationService(repo *repository.RegistrationRepository, rabbitConn *amqp.Connection) (*RegistrationService, error) {
@@ -38,52 +50,140 @@ func NewRegistrationService(repo *repository.RegistrationRepository, rabbitConn
 		return nil, fmt.Errorf("failed to declare exchange: %w", err)
 	}
 
-	return &RegistrationService{
+	formSchemas, err := forms.DefaultRegistry()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &RegistrationService{
 		repo:       repo,
 		rabbitConn: rabbitConn,
 		rabbitCh:   ch,
-	}, nil
+		workflows:  workflow.DefaultRegistry(),
+		forms:      formSchemas,
+		checklists: requirements.DefaultRegistry(),
+	}
+	s.hooks = map[string]func(string, *models.Registration) error{
+		workflow.HookRequestInvoice:  s.requestInvoice,
+		workflow.HookRequestFiling:   s.requestFiling,
+		workflow.HookAssignReviewer:  s.assignReviewer,
+		workflow.HookTrackCompliance: s.trackCompliance,
+	}
+
+	return s, nil
+}
+
+// UseQueues routes submitted registrations to reviewers through review queues
+// and tracks their SLA targets
+func (s *RegistrationService) UseQueues(queues *QueueService) {
+	s.queues = queues
+}
+
+// UseCompliance schedules the recurring filings of companies whose
+// registration completes
+func (s *RegistrationService) UseCompliance(compliance *ComplianceService) {
+	s.compliance = compliance
+}
+
+// UseFees locks the quote of each registration as it is submitted, so its
+// invoice and commission use the quoted amounts
+func (s *RegistrationService) UseFees(fees *FeeService) {
+	s.fees = fees
+}
+
+// CreateRegistration creates a new registration on the latest workflow and form
+// schema of its type and publishes event
+func (s *RegistrationService) CreateRegistration(schema string, registration *models.Registration, actor workflow.Actor) error {
+	initial, err := s.prepare(schema, registration, actor)
+	if err != nil {
+		return err
+	}
+
+	// Create in database
+	if err := s.repo.CreateWithHistory(schema, registration, initial); err != nil {
+		return fmt.Errorf("failed to create registration: %w", err)
+	}
+
+	// Publish event
+	if err := s.publishEvent("registration.created", registration); err != nil {
+		// Log error but don't fail the request
+		fmt.Printf("Warning: Failed to publish event: %v\n", err)
+	}
+
+	return nil
 }
 
-// CreateRegistration creates a new registration and publishes event
-func (s *RegistrationService) CreateRegistration(schema string, registration *models.Registration) error {
+// prepare validates a new registration, places it on the latest workflow and
+// form schema of its type and returns its initial status change
+func (s *RegistrationService) prepare(schema string, registration *models.Registration, actor workflow.Actor) (*models.RegistrationStatusChange, error) {
 	// Set default values
 	if registration.ID == uuid.Nil {
 		registration.ID = uuid.New()
 	}
-	if registration.Status == "" {
-		registration.Status = "draft"
-	}
 
 	// Validate required fields
 	if registration.TenantID == uuid.Nil {
-		return fmt.Errorf("tenant_id is required")
+		return nil, fmt.Errorf("tenant_id is required")
 	}
 	if registration.ClientID == uuid.Nil {
-		return fmt.Errorf("client_id is required")
+		return nil, fmt.Errorf("client_id is required")
 	}
 	if registration.RegistrationType == "" {
-		return fmt.Errorf("registration_type is required")
+		return nil, fmt.Errorf("registration_type is required")
 	}
 	if registration.CompanyName == "" {
-		return fmt.Errorf("company_name is required")
+		return nil, fmt.Errorf("company_name is required")
 	}
 	if registration.Jurisdiction == "" {
-		return fmt.Errorf("jurisdiction is required")
+		return nil, fmt.Errorf("jurisdiction is required")
 	}
 
-	// Create in database
-	if err := s.repo.Create(schema, registration); err != nil {
-		return fmt.Errorf("failed to create registration: %w", err)
+	definition, err := s.workflows.Latest(registration.RegistrationType)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported registration_type")
 	}
+	if registration.Status == "" {
+		registration.Status = definition.Initial
+	}
+	if registration.Status != definition.Initial {
+		return nil, fmt.Errorf("new registrations start as %s", definition.Initial)
+	}
+	registration.WorkflowVersion = definition.Version
 
-	// Publish event
-	if err := s.publishEvent("registration.created", registration); err != nil {
-		// Log error but don't fail the request
-		fmt.Printf("Warning: Failed to publish event: %v\n", err)
+	j, ok := jurisdictions.Get(registration.Jurisdiction)
+	if !ok {
+		return nil, fmt.Errorf("unsupported jurisdiction")
+	}
+	registration.Jurisdiction = j.Code()
+	if !jurisdictions.Offers(j, registration.RegistrationType) {
+		return nil, fmt.Errorf("unsupported registration_type for jurisdiction")
 	}
 
-	return nil
+	form, err := s.forms.Latest(registration.Jurisdiction, registration.RegistrationType)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported registration_type for jurisdiction")
+	}
+	if err := s.forms.Validate(form, registration.FormData, requiresCompleteForm(registration.Status)); err != nil {
+		return nil, err
+	}
+	registration.FormSchemaVersion = &form.Version
+
+	// Reviewers are assigned through review queues
+	registration.AssignedTo = nil
+	registration.QueueID = nil
+	registration.AssignedAt = nil
+
+	now := time.Now().UTC()
+	registration.StatusChangedAt = &now
+	if err := s.startClock(schema, registration, now); err != nil {
+		return nil, err
+	}
+
+	return &models.RegistrationStatusChange{
+		ToStatus:        registration.Status,
+		WorkflowVersion: registration.WorkflowVersion,
+		ChangedBy:       actor.ID(),
+	}, nil
 }
 
 // GetRegistration retrieves a registration by ID
@@ -91,24 +191,20 @@ func (s *RegistrationService) GetRegistration(schema string, tenantID, registrat
 	return s.repo.GetByID(schema, tenantID, registrationID)
 }
 
-// ListRegistrations retrieves registrations with pagination
-func (s *RegistrationService) ListRegistrations(schema string, tenantID uuid.UUID, offset, limit int, status string) ([]*models.Registration, int, error) {
-	// Validate pagination parameters
-	if limit <= 0 {
-		limit = 20
-	}
-	if limit > 100 {
-		limit = 100
-	}
-	if offset < 0 {
-		offset = 0
+// ListRegistrations retrieves a page of registrations
+func (s *RegistrationService) ListRegistrations(schema string, tenantID uuid.UUID, status string, page pagination.Page) (*pagination.List[*models.Registration], error) {
+	query, err := repository.RegistrationKeys.Query(page)
+	if err != nil {
+		return nil, err
 	}
 
-	return s.repo.List(schema, tenantID, offset, limit, status)
+	return s.repo.List(schema, tenantID, status, query)
 }
 
-// UpdateRegistration updates a registration and publishes event
-func (s *RegistrationService) UpdateRegistration(schema string, registration *models.Registration) error {
+// UpdateRegistration updates a registration and publishes event. A status change
+// goes through the registration's workflow: it must be a transition the actor's
+// roles allow and whose guards hold, and it is recorded in the status history.
+func (s *RegistrationService) UpdateRegistration(schema string, registration *models.Registration, actor workflow.Actor) error {
 	// Validate required fields
 	if registration.ID == uuid.Nil {
 		return fmt.Errorf("id is required")
@@ -120,47 +216,343 @@ func (s *RegistrationService) UpdateRegistration(schema string, registration *mo
 	// Get existing registration to check status transitions
 	existing, err := s.repo.GetByID(schema, registration.TenantID, registration.ID)
 	if err != nil {
-		return fmt.Errorf("failed to get existing registration: %w", err)
+		return err
+	}
+
+	// The workflow is chosen by type at creation, so the type is fixed
+	if registration.RegistrationType == "" {
+		registration.RegistrationType = existing.RegistrationType
+	}
+	if registration.RegistrationType != existing.RegistrationType {
+		return fmt.Errorf("registration_type cannot be changed")
+	}
+	registration.WorkflowVersion = existing.WorkflowVersion
+	if registration.Status == "" {
+		registration.Status = existing.Status
+	}
+	if registration.Jurisdiction == "" {
+		registration.Jurisdiction = existing.Jurisdiction
+	}
+
+	if err := s.checkForm(existing, registration); err != nil {
+		return err
+	}
+
+	// Keep the workflow's timestamps; only a transition sets them
+	registration.SubmittedAt = existing.SubmittedAt
+	registration.ApprovedAt = existing.ApprovedAt
+	registration.RejectedAt = existing.RejectedAt
+
+	// Reviewers change through assignment, which keeps its own audit trail
+	registration.AssignedTo = existing.AssignedTo
+	registration.QueueID = existing.QueueID
+	registration.AssignedAt = existing.AssignedAt
+
+	if existing.Status == registration.Status {
+		if err := s.repo.Update(schema, registration); err != nil {
+			return fmt.Errorf("failed to update registration: %w", err)
+		}
+		return nil
+	}
+
+	var reason *string
+	if registration.Status == models.RegistrationStatusRejected {
+		reason = registration.RejectionReason
+	}
+
+	return s.transition(schema, existing, registration, actor, reason)
+}
+
+// TransitionRegistration moves a registration to another status through its
+// workflow. reason is recorded in the status history, and as the rejection
+// reason when the registration is rejected.
+func (s *RegistrationService) TransitionRegistration(schema string, tenantID, registrationID uuid.UUID, to string, reason *string, actor workflow.Actor) (*models.Registration, error) {
+	existing, err := s.repo.GetByID(schema, tenantID, registrationID)
+	if err != nil {
+		return nil, err
+	}
+	if existing.Status == to {
+		return nil, &workflow.TransitionError{From: existing.Status, To: to, Reason: workflow.ReasonNotAllowed}
+	}
+
+	registration := *existing
+	registration.Status = to
+	if to == models.RegistrationStatusRejected {
+		registration.RejectionReason = reason
+	}
+
+	if err := s.checkForm(existing, &registration); err != nil {
+		return nil, err
+	}
+
+	if err := s.transition(schema, existing, &registration, actor, reason); err != nil {
+		return nil, err
+	}
+
+	return &registration, nil
+}
+
+// transition checks a status change against the registration's workflow, stores
+// it with its history entry, then publishes it and runs the transition's hooks
+func (s *RegistrationService) transition(schema string, existing, registration *models.Registration, actor workflow.Actor, reason *string) error {
+	definition, err := s.workflows.Get(existing.RegistrationType, existing.WorkflowVersion)
+	if err != nil {
+		return err
 	}
 
-	// Validate status transitions
-	if err := s.validateStatusTransition(existing.Status, registration.Status); err != nil {
+	facts, checklist, err := s.evaluate(schema, registration)
+	if err != nil {
+		return err
+	}
+	facts.MissingRequirements = requirements.Unmet(checklist.Items, models.RegistrationStatusSubmitted)
+
+	transition, err := definition.Check(existing.Status, registration.Status, actor, facts)
+	if err != nil {
 		return err
 	}
 
 	// Update timestamps based on status
 	now := time.Now()
 	switch registration.Status {
-	case "submitted":
-		if existing.Status != "submitted" {
-			registration.SubmittedAt = &now
-		}
-	case "approved":
-		if existing.Status != "approved" {
-			registration.ApprovedAt = &now
-		}
-	case "rejected":
-		if existing.Status != "rejected" {
-			registration.RejectedAt = &now
+	case models.RegistrationStatusSubmitted:
+		registration.SubmittedAt = &now
+	case models.RegistrationStatusApproved:
+		registration.ApprovedAt = &now
+	case models.RegistrationStatusRejected:
+		registration.RejectedAt = &now
+	}
+
+	// Restart the SLA clock for the new status
+	entered := now.UTC()
+	registration.StatusChangedAt = &entered
+	registration.SLABreachedAt = nil
+	if err := s.startClock(schema, registration, entered); err != nil {
+		return err
+	}
+
+	// Lock the quote with the submission; it travels with the published
+	// registration to invoicing
+	if registration.Status == models.RegistrationStatusSubmitted && s.fees != nil {
+		quote, err := s.fees.SubmissionQuote(schema, registration, actor.ID())
+		if err != nil {
+			return fmt.Errorf("failed to quote registration: %w", err)
 		}
+		registration.Quote = quote
 	}
 
-	// Update in database
-	if err := s.repo.Update(schema, registration); err != nil {
+	change := &models.RegistrationStatusChange{
+		RegistrationID:  registration.ID,
+		FromStatus:      &existing.Status,
+		ToStatus:        registration.Status,
+		WorkflowVersion: existing.WorkflowVersion,
+		ChangedBy:       actor.ID(),
+		Reason:          reason,
+	}
+
+	if err := s.repo.Transition(schema, registration, change); err != nil {
 		return fmt.Errorf("failed to update registration: %w", err)
 	}
 
-	// Publish event if status changed
-	if existing.Status != registration.Status {
-		eventType := fmt.Sprintf("registration.status.%s", registration.Status)
-		if err := s.publishEvent(eventType, registration); err != nil {
-			fmt.Printf("Warning: Failed to publish event: %v\n", err)
+	// Publish the status change, then run the transition's side effects
+	eventType := fmt.Sprintf("registration.status.%s", registration.Status)
+	if err := s.publishEvent(eventType, registration); err != nil {
+		fmt.Printf("Warning: Failed to publish event: %v\n", err)
+	}
+	s.runHooks(schema, transition, registration)
+
+	return nil
+}
+
+// startClock sets when the registration's current status runs out its SLA
+// target. Without review queues no status has a target.
+func (s *RegistrationService) startClock(schema string, registration *models.Registration, since time.Time) error {
+	registration.SLADueAt = nil
+	if s.queues == nil {
+		return nil
+	}
+
+	due, err := s.queues.DueAt(schema, registration, since)
+	if err != nil {
+		return fmt.Errorf("failed to compute SLA due date: %w", err)
+	}
+	registration.SLADueAt = due
+	return nil
+}
+
+// GetHistory returns a registration's status history and the statuses it can
+// move to next
+func (s *RegistrationService) GetHistory(schema string, tenantID, registrationID uuid.UUID) (*models.RegistrationHistoryResponse, error) {
+	registration, err := s.repo.GetByID(schema, tenantID, registrationID)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := s.repo.ListHistory(schema, tenantID, registrationID)
+	if err != nil {
+		return nil, err
+	}
+
+	next := []string{}
+	if definition, err := s.workflows.Get(registration.RegistrationType, registration.WorkflowVersion); err == nil {
+		for _, transition := range definition.Next(registration.Status) {
+			next = append(next, transition.To)
 		}
 	}
 
+	return &models.RegistrationHistoryResponse{
+		RegistrationID:   registration.ID,
+		RegistrationType: registration.RegistrationType,
+		WorkflowVersion:  registration.WorkflowVersion,
+		Status:           registration.Status,
+		NextStatuses:     next,
+		Data:             history,
+	}, nil
+}
+
+// GetChecklist returns a registration's requirements checklist with its
+// completeness
+func (s *RegistrationService) GetChecklist(schema string, tenantID, registrationID uuid.UUID) (*models.RegistrationChecklist, error) {
+	registration, err := s.repo.GetByID(schema, tenantID, registrationID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, checklist, err := s.evaluate(schema, registration)
+	if err != nil {
+		return nil, err
+	}
+	return checklist, nil
+}
+
+// RecordInvoicePayment records that a registration's invoice was paid, which
+// the workflow requires before the registration is completed. The invoice is
+// requested on submission, so drafts cannot be paid.
+func (s *RegistrationService) RecordInvoicePayment(schema string, tenantID, registrationID uuid.UUID, paidAt *time.Time) (*models.Registration, error) {
+	registration, err := s.repo.GetByID(schema, tenantID, registrationID)
+	if err != nil {
+		return nil, err
+	}
+	if registration.Status == models.RegistrationStatusDraft {
+		return nil, fmt.Errorf("registration has not been invoiced")
+	}
+
+	now := time.Now().UTC()
+	if paidAt == nil {
+		paidAt = &now
+	} else if paidAt.After(now) {
+		return nil, fmt.Errorf("paid_at cannot be in the future")
+	}
+
+	if err := s.repo.MarkInvoicePaid(schema, tenantID, registrationID, paidAt.UTC()); err != nil {
+		return nil, err
+	}
+
+	if err := s.publishEvent("registration.invoice.paid", registration); err != nil {
+		fmt.Printf("Warning: Failed to publish event: %v\n", err)
+	}
+
+	return registration, nil
+}
+
+// evaluate loads the registration's workflow facts and computes its checklist
+// from its documents and form data
+func (s *RegistrationService) evaluate(schema string, registration *models.Registration) (workflow.Facts, *models.RegistrationChecklist, error) {
+	facts, err := s.repo.LoadFacts(schema, registration.TenantID, registration.ID)
+	if err != nil {
+		return facts, nil, err
+	}
+
+	documents, err := s.repo.CountDocuments(schema, registration.TenantID, registration.ID)
+	if err != nil {
+		return facts, nil, err
+	}
+
+	set := s.checklists.Get(registration.Jurisdiction, registration.RegistrationType)
+	items, completeness := set.Evaluate(requirements.Evidence{
+		FormData:     registration.FormData,
+		Documents:    documents,
+		NameReserved: facts.NameReserved,
+	})
+
+	return facts, &models.RegistrationChecklist{
+		RegistrationID:   registration.ID,
+		RegistrationType: registration.RegistrationType,
+		Jurisdiction:     registration.Jurisdiction,
+		Status:           registration.Status,
+		Completeness:     completeness,
+		ReadyToSubmit:    len(requirements.Unmet(items, models.RegistrationStatusSubmitted)) == 0,
+		Items:            items,
+	}, nil
+}
+
+// checkForm validates form_data against the registration's form schema version.
+// Moving to another jurisdiction moves the registration onto that jurisdiction's
+// latest schema. Registrations from before form schemas are not validated.
+func (s *RegistrationService) checkForm(existing, registration *models.Registration) error {
+	registration.FormSchemaVersion = existing.FormSchemaVersion
+
+	var form *forms.FormSchema
+	var err error
+	switch {
+	case registration.Jurisdiction != existing.Jurisdiction:
+		j, ok := jurisdictions.Get(registration.Jurisdiction)
+		if !ok {
+			return fmt.Errorf("unsupported jurisdiction")
+		}
+		form, err = s.forms.Latest(registration.Jurisdiction, registration.RegistrationType)
+		if err != nil || !jurisdictions.Offers(j, registration.RegistrationType) {
+			return fmt.Errorf("unsupported registration_type for jurisdiction")
+		}
+	case existing.FormSchemaVersion == nil:
+		return nil
+	default:
+		form, err = s.forms.Get(existing.Jurisdiction, existing.RegistrationType, *existing.FormSchemaVersion)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := s.forms.Validate(form, registration.FormData, requiresCompleteForm(registration.Status)); err != nil {
+		return err
+	}
+	registration.FormSchemaVersion = &form.Version
 	return nil
 }
 
+// requiresCompleteForm reports whether a status needs every required form field.
+// Drafts are saved as they are filled in, and an incomplete form may still be
+// rejected or cancelled.
+func requiresCompleteForm(status string) bool {
+	switch status {
+	case models.RegistrationStatusDraft, models.RegistrationStatusRejected, models.RegistrationStatusCancelled:
+		return false
+	default:
+		return true
+	}
+}
+
+// ListFormSchemas lists every form schema version
+func (s *RegistrationService) ListFormSchemas() []*forms.FormSchema {
+	return s.forms.List()
+}
+
+// GetFormSchema returns a form schema version, or the latest when version is 0
+func (s *RegistrationService) GetFormSchema(jurisdiction, registrationType string, version int) (*forms.FormSchema, error) {
+	if version == 0 {
+		return s.forms.Latest(jurisdiction, registrationType)
+	}
+	return s.forms.Get(jurisdiction, registrationType, version)
+}
+
+// GetWorkflow returns the latest workflow definition of a registration type
+func (s *RegistrationService) GetWorkflow(registrationType string) (*workflow.Definition, error) {
+	definition, err := s.workflows.Latest(registrationType)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported registration_type")
+	}
+	return definition, nil
+}
+
 // DeleteRegistration soft deletes a registration
 func (s *RegistrationService) DeleteRegistration(schema string, tenantID, registrationID uuid.UUID) error {
 	if err := s.repo.Delete(schema, tenantID, registrationID); err != nil {
@@ -179,29 +571,56 @@ func (s *RegistrationService) DeleteRegistration(schema string, tenantID, regist
 	return nil
 }
 
-// validateStatusTransition validates if a status transition is allowed
-func (s *RegistrationService) validateStatusTransition(from, to string) error {
-	validTransitions := map[string][]string{
-		"draft":     {"submitted", "cancelled"},
-		"submitted": {"approved", "rejected", "cancelled"},
-		"approved":  {"completed", "cancelled"},
-		"rejected":  {"draft", "cancelled"},
-		"completed": {},
-		"cancelled": {},
+// runHooks runs a transition's side effects. The status change is already
+// stored, so a failing hook is logged rather than failing the request.
+func (s *RegistrationService) runHooks(schema string, transition workflow.Transition, registration *models.Registration) {
+	for _, name := range transition.Hooks {
+		hook, ok := s.hooks[name]
+		if !ok {
+			fmt.Printf("Warning: Unknown workflow hook %s\n", name)
+			continue
+		}
+		if err := hook(schema, registration); err != nil {
+			fmt.Printf("Warning: Workflow hook %s failed for registration %s: %v\n", name, registration.ID, err)
+		}
 	}
+}
 
-	allowedStates, exists := validTransitions[from]
-	if !exists {
-		return fmt.Errorf("invalid current status: %s", from)
+// requestInvoice asks billing to raise the registration invoice
+func (s *RegistrationService) requestInvoice(schema string, registration *models.Registration) error {
+	return s.publishEvent("registration.invoice.requested", registration)
+}
+
+// requestFiling asks the integration layer to file the registration with the
+// government registry of its jurisdiction
+func (s *RegistrationService) requestFiling(schema string, registration *models.Registration) error {
+	return s.publishEvent("registration.filing.requested", registration)
+}
+
+// assignReviewer assigns a newly submitted registration to a reviewer through
+// the review queue its type is routed to, unless someone already has it
+func (s *RegistrationService) assignReviewer(schema string, registration *models.Registration) error {
+	if s.queues == nil || registration.AssignedTo != nil {
+		return nil
 	}
 
-	for _, allowed := range allowedStates {
-		if allowed == to {
-			return nil
-		}
+	assignment, err := s.queues.AutoAssign(schema, registration)
+	if err != nil {
+		return err
+	}
+	if assignment == nil {
+		fmt.Printf("Warning: No reviewer available for registration %s\n", registration.ID)
 	}
+	return nil
+}
 
-	return fmt.Errorf("invalid status transition from %s to %s", from, to)
+// trackCompliance schedules the recurring filings the company a completed
+// registration registered owes
+func (s *RegistrationService) trackCompliance(schema string, registration *models.Registration) error {
+	if s.compliance == nil {
+		return nil
+	}
+	return s.compliance.Track(schema, registration)
 }
 
 // publishEvent publishes an event to RabbitMQ
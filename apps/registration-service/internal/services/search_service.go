@@ -0,0 +1,99 @@
+package services
+
+import (
+	"github.com/comply360/registration-service/internal/repository"
+	"github.com/comply360/registration-service/internal/search"
+	"github.com/comply360/shared/models"
+	"github.com/comply360/shared/pagination"
+	"github.com/google/uuid"
+)
+
+// SearchService searches a tenant's registrations and clients
+type SearchService struct {
+	repo *repository.SearchRepository
+}
+
+func NewSearchService(repo *repository.SearchRepository) *SearchService {
+	return &SearchService{repo: repo}
+}
+
+// SearchRegistrations searches registrations, returning a page of matches and
+// the facet counts of every match. Results are ordered by relevance when there
+// is a query and newest first otherwise, unless the page sorts otherwise.
+func (s *SearchService) SearchRegistrations(schema string, tenantID uuid.UUID, req *repository.RegistrationSearch, page pagination.Page) (*pagination.List[*models.RegistrationSearchHit], map[string][]models.FacetCount, error) {
+	query, err := repository.RegistrationSearchKeys.Query(relevanceFirst(page, req.Query))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	hits, facets, err := s.repo.SearchRegistrations(schema, tenantID, req, query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	terms := search.Terms(req.Query)
+	if len(terms) > 0 {
+		for _, hit := range hits.Data {
+			hit.Highlights = highlights(terms, map[string]*string{
+				"company_name":        &hit.CompanyName,
+				"registration_number": hit.RegistrationNumber,
+				"cipc_reference":      hit.CIPCReference,
+				"dcip_reference":      hit.DCIPReference,
+			})
+		}
+	}
+
+	return hits, facets, nil
+}
+
+// SearchClients searches clients, ordered like registration searches
+func (s *SearchService) SearchClients(schema string, tenantID uuid.UUID, req *repository.ClientSearch, page pagination.Page) (*pagination.List[*models.ClientSearchHit], map[string][]models.FacetCount, error) {
+	query, err := repository.ClientSearchKeys.Query(relevanceFirst(page, req.Query))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	hits, facets, err := s.repo.SearchClients(schema, tenantID, req, query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	terms := search.Terms(req.Query)
+	if len(terms) > 0 {
+		for _, hit := range hits.Data {
+			hit.Highlights = highlights(terms, map[string]*string{
+				"full_name":       hit.FullName,
+				"company_name":    hit.CompanyName,
+				"email":           &hit.Email,
+				"id_number":       hit.IDNumber,
+				"passport_number": hit.PassportNumber,
+				"tax_number":      hit.TaxNumber,
+			})
+		}
+	}
+
+	return hits, facets, nil
+}
+
+// relevanceFirst sorts a page without a sort by relevance when there is a text
+// query, leaving the keys' default otherwise
+func relevanceFirst(page pagination.Page, query string) pagination.Page {
+	if page.Sort == "" && len(search.Terms(query)) > 0 {
+		page.Sort = "-" + search.Relevance
+	}
+	return page
+}
+
+// highlights returns the fields containing a search term, highlighted
+func highlights(terms []string, fields map[string]*string) map[string]string {
+	marked := map[string]string{}
+	for name, value := range fields {
+		if value == nil {
+			continue
+		}
+		if highlighted := search.Highlight(*value, terms); highlighted != "" {
+			marked[name] = highlighted
+		}
+	}
+	return marked
+}
@@ -0,0 +1,107 @@
+package sla
+
+import (
+	"time"
+
+	"github.com/comply360/shared/models"
+)
+
+// Defaults apply to a status a tenant has set no target for: a submitted
+// registration is picked up for review within a working day and decided within
+// 48 business hours
+var Defaults = []*models.SLATarget{
+	{Status: models.RegistrationStatusSubmitted, TargetHours: 8, BusinessHours: true},
+	{Status: models.RegistrationStatusInReview, TargetHours: 48, BusinessHours: true},
+}
+
+// Resolve returns the target of a status for a registration type: the tenant's
+// target for the type, then the tenant's target for every type, then the
+// default. It returns nil when the status has no target.
+func Resolve(targets []*models.SLATarget, status, registrationType string) *models.SLATarget {
+	var general *models.SLATarget
+	for _, target := range targets {
+		if target.Status != status {
+			continue
+		}
+		if target.RegistrationType == nil {
+			general = target
+			continue
+		}
+		if *target.RegistrationType == registrationType {
+			return target
+		}
+	}
+	if general != nil {
+		return general
+	}
+
+	for _, target := range Defaults {
+		if target.Status == status {
+			return target
+		}
+	}
+	return nil
+}
+
+// Calendar is a tenant's working week
+type Calendar struct {
+	Location *time.Location
+	// Open and Close are the hours of the day business hours start and end
+	Open, Close int
+	Workdays    map[time.Weekday]bool
+}
+
+// BusinessCalendar returns the standard working week, 08:00 to 17:00 Monday to
+// Friday, in a location
+func BusinessCalendar(location *time.Location) Calendar {
+	return Calendar{
+		Location: location,
+		Open:     8,
+		Close:    17,
+		Workdays: map[time.Weekday]bool{
+			time.Monday:    true,
+			time.Tuesday:   true,
+			time.Wednesday: true,
+			time.Thursday:  true,
+			time.Friday:    true,
+		},
+	}
+}
+
+// Due returns when a target started at a time runs out
+func (c Calendar) Due(target *models.SLATarget, since time.Time) time.Time {
+	duration := time.Duration(target.TargetHours) * time.Hour
+	if !target.BusinessHours {
+		return since.Add(duration)
+	}
+	return c.AddBusinessTime(since, duration)
+}
+
+// AddBusinessTime adds a duration counted only within business hours
+func (c Calendar) AddBusinessTime(start time.Time, duration time.Duration) time.Time {
+	if len(c.Workdays) == 0 || c.Close <= c.Open {
+		return start.Add(duration)
+	}
+
+	t := start.In(c.Location)
+	for {
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, c.Location)
+		open := day.Add(time.Duration(c.Open) * time.Hour)
+		closing := day.Add(time.Duration(c.Close) * time.Hour)
+
+		if !c.Workdays[t.Weekday()] || !t.Before(closing) {
+			t = day.AddDate(0, 0, 1)
+			continue
+		}
+		if t.Before(open) {
+			t = open
+		}
+
+		available := closing.Sub(t)
+		if duration <= available {
+			return t.Add(duration)
+		}
+		duration -= available
+		t = day.AddDate(0, 0, 1)
+	}
+}
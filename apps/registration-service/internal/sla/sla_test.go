@@ -0,0 +1,47 @@
+package sla
+
+import (
+	"testing"
+	"time"
+
+	"github.com/comply360/shared/models"
+	testhelpers "github.com/comply360/shared/testing"
+)
+
+var johannesburg = time.FixedZone("SAST", 2*60*60)
+
+func TestAddBusinessTime_SkipsNightsAndWeekends(t *testing.T) {
+	calendar := BusinessCalendar(johannesburg)
+
+	// Friday 15:00 plus 4 business hours is Monday 10:00
+	friday := time.Date(2026, 10, 16, 15, 0, 0, 0, johannesburg)
+	testhelpers.AssertTrue(t, calendar.AddBusinessTime(friday, 4*time.Hour).Equal(time.Date(2026, 10, 19, 10, 0, 0, 0, johannesburg)))
+
+	// Saturday night counts from Monday's opening
+	saturday := time.Date(2026, 10, 17, 22, 0, 0, 0, johannesburg)
+	testhelpers.AssertTrue(t, calendar.AddBusinessTime(saturday, 9*time.Hour).Equal(time.Date(2026, 10, 19, 17, 0, 0, 0, johannesburg)))
+}
+
+func TestDue_FortyEightBusinessHours(t *testing.T) {
+	calendar := BusinessCalendar(johannesburg)
+	target := &models.SLATarget{Status: models.RegistrationStatusInReview, TargetHours: 48, BusinessHours: true}
+
+	// 48 hours is five working days and three hours
+	monday := time.Date(2026, 10, 19, 9, 0, 0, 0, johannesburg)
+	testhelpers.AssertTrue(t, calendar.Due(target, monday).Equal(time.Date(2026, 10, 26, 12, 0, 0, 0, johannesburg)))
+
+	target.BusinessHours = false
+	testhelpers.AssertTrue(t, calendar.Due(target, monday).Equal(monday.Add(48*time.Hour)))
+}
+
+func TestResolve_PrefersTypeThenTenantThenDefault(t *testing.T) {
+	ptyLtd := models.RegistrationTypePtyLtd
+	general := &models.SLATarget{Status: models.RegistrationStatusInReview, TargetHours: 24}
+	specific := &models.SLATarget{Status: models.RegistrationStatusInReview, RegistrationType: &ptyLtd, TargetHours: 16}
+	targets := []*models.SLATarget{specific, general}
+
+	testhelpers.AssertEqual(t, specific, Resolve(targets, models.RegistrationStatusInReview, models.RegistrationTypePtyLtd))
+	testhelpers.AssertEqual(t, general, Resolve(targets, models.RegistrationStatusInReview, models.RegistrationTypeBusinessName))
+	testhelpers.AssertEqual(t, 8, Resolve(targets, models.RegistrationStatusSubmitted, models.RegistrationTypePtyLtd).TargetHours)
+	testhelpers.AssertTrue(t, Resolve(targets, models.RegistrationStatusApproved, models.RegistrationTypePtyLtd) == nil)
+}
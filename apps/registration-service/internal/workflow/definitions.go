@@ -0,0 +1,107 @@
+package workflow
+
+import "github.com/comply360/shared/models"
+
+// Hook names. The registration service registers what each one does.
+const (
+	// HookRequestInvoice asks billing to raise the registration invoice
+	HookRequestInvoice = "request_invoice"
+	// HookRequestFiling asks the integration layer to file with the government registry
+	HookRequestFiling = "request_filing"
+	// HookAssignReviewer assigns the registration to a reviewer through its review queue
+	HookAssignReviewer = "assign_reviewer"
+	// HookTrackCompliance schedules the recurring filings the registered company owes
+	HookTrackCompliance = "track_compliance"
+)
+
+// Roles allowed to prepare and submit registrations, and to review them
+var (
+	preparers = []string{models.RoleTenantAdmin, models.RoleTenantManager, models.RoleAgent, models.RoleAgentAssistant}
+	reviewers = []string{models.RoleTenantAdmin, models.RoleTenantManager}
+)
+
+// DefaultRegistry returns the built-in workflows
+func DefaultRegistry() *Registry {
+	return NewRegistry(
+		standardV1(models.RegistrationTypePtyLtd, GuardNameReserved),
+		standardV1(models.RegistrationTypeCloseCorporation, GuardNameReserved),
+		standardV1(models.RegistrationTypeBusinessName),
+		standardV1(models.RegistrationTypeVATRegistration),
+		standardV2(models.RegistrationTypePtyLtd, GuardNameReserved),
+		standardV2(models.RegistrationTypeCloseCorporation, GuardNameReserved),
+		standardV2(models.RegistrationTypeBusinessName),
+		standardV2(models.RegistrationTypeVATRegistration),
+	)
+}
+
+// standardV1 is the first version of the workflow every registration type
+// follows: draft, submitted, in review, then approved or rejected, and completed
+// once paid. approvalGuards are checked on approval in addition to verified
+// documents.
+func standardV1(registrationType string, approvalGuards ...string) *Definition {
+	const (
+		draft     = models.RegistrationStatusDraft
+		submitted = models.RegistrationStatusSubmitted
+		inReview  = models.RegistrationStatusInReview
+		approved  = models.RegistrationStatusApproved
+		rejected  = models.RegistrationStatusRejected
+		cancelled = models.RegistrationStatusCancelled
+		completed = models.RegistrationStatusCompleted
+	)
+
+	return &Definition{
+		RegistrationType: registrationType,
+		Version:          1,
+		Initial:          draft,
+		Transitions: []Transition{
+			{From: draft, To: submitted, Roles: preparers, Hooks: []string{HookRequestInvoice}},
+			{From: draft, To: cancelled, Roles: preparers},
+			{From: submitted, To: draft, Roles: preparers},
+			{From: submitted, To: inReview, Roles: reviewers},
+			{From: submitted, To: cancelled, Roles: reviewers},
+			{From: inReview, To: approved, Roles: reviewers, Guards: append([]string{GuardDocumentsVerified}, approvalGuards...), Hooks: []string{HookRequestFiling}},
+			{From: inReview, To: rejected, Roles: reviewers},
+			{From: inReview, To: cancelled, Roles: reviewers},
+			{From: rejected, To: draft, Roles: preparers},
+			{From: rejected, To: cancelled, Roles: preparers},
+			{From: approved, To: completed, Roles: reviewers, Guards: []string{GuardInvoicePaid}},
+			{From: approved, To: cancelled, Roles: reviewers},
+		},
+	}
+}
+
+// standardV2 is standardV1 with submission refused until the registration's
+// requirements checklist is met, submitted registrations assigned to a reviewer
+// through their review queue, and the compliance obligations of completed
+// registrations tracked. Registrations started on version 1 finish on it.
+func standardV2(registrationType string, approvalGuards ...string) *Definition {
+	const (
+		draft     = models.RegistrationStatusDraft
+		submitted = models.RegistrationStatusSubmitted
+		inReview  = models.RegistrationStatusInReview
+		approved  = models.RegistrationStatusApproved
+		rejected  = models.RegistrationStatusRejected
+		cancelled = models.RegistrationStatusCancelled
+		completed = models.RegistrationStatusCompleted
+	)
+
+	return &Definition{
+		RegistrationType: registrationType,
+		Version:          2,
+		Initial:          draft,
+		Transitions: []Transition{
+			{From: draft, To: submitted, Roles: preparers, Guards: []string{GuardRequirementsMet}, Hooks: []string{HookRequestInvoice, HookAssignReviewer}},
+			{From: draft, To: cancelled, Roles: preparers},
+			{From: submitted, To: draft, Roles: preparers},
+			{From: submitted, To: inReview, Roles: reviewers},
+			{From: submitted, To: cancelled, Roles: reviewers},
+			{From: inReview, To: approved, Roles: reviewers, Guards: append([]string{GuardDocumentsVerified}, approvalGuards...), Hooks: []string{HookRequestFiling}},
+			{From: inReview, To: rejected, Roles: reviewers},
+			{From: inReview, To: cancelled, Roles: reviewers},
+			{From: rejected, To: draft, Roles: preparers},
+			{From: rejected, To: cancelled, Roles: preparers},
+			{From: approved, To: completed, Roles: reviewers, Guards: []string{GuardInvoicePaid}, Hooks: []string{HookTrackCompliance}},
+			{From: approved, To: cancelled, Roles: reviewers},
+		},
+	}
+}
@@ -0,0 +1,262 @@
+package workflow
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/comply360/shared/models"
+	"github.com/google/uuid"
+)
+
+// Guard names. Each guard is a precondition checked against the registration's
+// Facts before a transition is allowed.
+const (
+	// GuardRequirementsMet requires every item of the registration's checklist
+	// needed for submission
+	GuardRequirementsMet = "requirements_met"
+	// GuardDocumentsVerified requires at least one document and every document verified
+	GuardDocumentsVerified = "documents_verified"
+	// GuardNameReserved requires the company name to be reserved with CIPC
+	GuardNameReserved = "name_reserved"
+	// GuardInvoicePaid requires the registration invoice to be paid
+	GuardInvoicePaid = "invoice_paid"
+)
+
+// guardMessages explain unmet guards to API clients
+var guardMessages = map[string]string{
+	GuardRequirementsMet:   "required documents and form fields are missing",
+	GuardDocumentsVerified: "all documents must be uploaded and verified",
+	GuardNameReserved:      "the company name must be reserved with CIPC",
+	GuardInvoicePaid:       "the registration invoice must be paid",
+}
+
+// Transition is an allowed status change. An actor needs any one of Roles; no
+// roles means anyone with access to the registration may make it. Hooks name
+// side effects run once the change is stored.
+type Transition struct {
+	From   string   `json:"from"`
+	To     string   `json:"to"`
+	Roles  []string `json:"roles,omitempty"`
+	Guards []string `json:"guards,omitempty"`
+	Hooks  []string `json:"hooks,omitempty"`
+}
+
+// Definition is one version of the state machine of a registration type.
+// Registrations keep the version they were created with, so a new version only
+// applies to new registrations.
+type Definition struct {
+	RegistrationType string       `json:"registration_type"`
+	Version          int          `json:"version"`
+	Initial          string       `json:"initial"`
+	Transitions      []Transition `json:"transitions"`
+}
+
+// Find returns the transition between two statuses
+func (d *Definition) Find(from, to string) (Transition, bool) {
+	for _, t := range d.Transitions {
+		if t.From == from && t.To == to {
+			return t, true
+		}
+	}
+	return Transition{}, false
+}
+
+// Next lists the transitions out of a status
+func (d *Definition) Next(from string) []Transition {
+	var next []Transition
+	for _, t := range d.Transitions {
+		if t.From == from {
+			next = append(next, t)
+		}
+	}
+	return next
+}
+
+// Actor is the user making a transition
+type Actor struct {
+	UserID        uuid.UUID
+	Roles         []string
+	IsGlobalAdmin bool
+}
+
+// ID returns the actor's user ID, or nil for an anonymous actor
+func (a Actor) ID() *uuid.UUID {
+	if a.UserID == uuid.Nil {
+		return nil
+	}
+	id := a.UserID
+	return &id
+}
+
+// can reports whether the actor holds one of the transition's roles. Global
+// admins may make any transition.
+func (a Actor) can(t Transition) bool {
+	if len(t.Roles) == 0 || a.IsGlobalAdmin {
+		return true
+	}
+	for _, required := range t.Roles {
+		for _, role := range a.Roles {
+			if role == required || role == models.RoleGlobalAdmin {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Facts are what guards are evaluated against, loaded from the registration and
+// its documents
+type Facts struct {
+	Documents         int
+	VerifiedDocuments int
+	NameReserved      bool
+	InvoicePaid       bool
+	// MissingRequirements labels the checklist items still needed for submission
+	MissingRequirements []string
+}
+
+// satisfies reports whether a guard holds
+func (f Facts) satisfies(guard string) bool {
+	switch guard {
+	case GuardRequirementsMet:
+		return len(f.MissingRequirements) == 0
+	case GuardDocumentsVerified:
+		return f.Documents > 0 && f.VerifiedDocuments == f.Documents
+	case GuardNameReserved:
+		return f.NameReserved
+	case GuardInvoicePaid:
+		return f.InvoicePaid
+	default:
+		return false
+	}
+}
+
+// Reasons a transition is refused
+const (
+	ReasonNotAllowed = "not_allowed"
+	ReasonForbidden  = "forbidden"
+	ReasonGuards     = "guards"
+)
+
+// TransitionError explains why a transition was refused
+type TransitionError struct {
+	From   string
+	To     string
+	Reason string
+	// Unmet lists the guards that failed, with a message for each
+	Unmet map[string]string
+}
+
+func (e *TransitionError) Error() string {
+	switch e.Reason {
+	case ReasonForbidden:
+		return fmt.Sprintf("not permitted to move registration from %s to %s", e.From, e.To)
+	case ReasonGuards:
+		return fmt.Sprintf("requirements for moving registration from %s to %s are not met", e.From, e.To)
+	default:
+		return fmt.Sprintf("invalid status transition from %s to %s", e.From, e.To)
+	}
+}
+
+// Check validates a status change against a definition: the transition must
+// exist, the actor must hold a permitted role and every guard must hold.
+func (d *Definition) Check(from, to string, actor Actor, facts Facts) (Transition, error) {
+	t, ok := d.Find(from, to)
+	if !ok {
+		return Transition{}, &TransitionError{From: from, To: to, Reason: ReasonNotAllowed}
+	}
+	if !actor.can(t) {
+		return Transition{}, &TransitionError{From: from, To: to, Reason: ReasonForbidden}
+	}
+
+	unmet := map[string]string{}
+	for _, guard := range t.Guards {
+		if !facts.satisfies(guard) {
+			unmet[guard] = guardMessages[guard]
+			if guard == GuardRequirementsMet {
+				unmet[guard] += ": " + strings.Join(facts.MissingRequirements, ", ")
+			}
+		}
+	}
+	if len(unmet) > 0 {
+		return Transition{}, &TransitionError{From: from, To: to, Reason: ReasonGuards, Unmet: unmet}
+	}
+
+	return t, nil
+}
+
+// Registry holds every version of every registration type's state machine
+type Registry struct {
+	definitions map[string]map[int]*Definition
+}
+
+// NewRegistry creates a registry from definitions. It panics on a duplicate
+// version or a transition out of an unknown status, since definitions are
+// compiled in.
+func NewRegistry(defs ...*Definition) *Registry {
+	r := &Registry{definitions: map[string]map[int]*Definition{}}
+	for _, def := range defs {
+		versions, ok := r.definitions[def.RegistrationType]
+		if !ok {
+			versions = map[int]*Definition{}
+			r.definitions[def.RegistrationType] = versions
+		}
+		if _, exists := versions[def.Version]; exists {
+			panic(fmt.Sprintf("workflow: duplicate definition %s v%d", def.RegistrationType, def.Version))
+		}
+		for _, t := range def.Transitions {
+			if !knownStatuses[t.From] || !knownStatuses[t.To] {
+				panic(fmt.Sprintf("workflow: %s v%d has a transition %s -> %s with an unknown status", def.RegistrationType, def.Version, t.From, t.To))
+			}
+		}
+		versions[def.Version] = def
+	}
+	return r
+}
+
+// Latest returns the newest definition of a registration type
+func (r *Registry) Latest(registrationType string) (*Definition, error) {
+	versions, ok := r.definitions[registrationType]
+	if !ok || len(versions) == 0 {
+		return nil, fmt.Errorf("no workflow for registration type %s", registrationType)
+	}
+
+	latest := 0
+	for version := range versions {
+		if version > latest {
+			latest = version
+		}
+	}
+	return versions[latest], nil
+}
+
+// Get returns a specific version of a registration type's definition
+func (r *Registry) Get(registrationType string, version int) (*Definition, error) {
+	def, ok := r.definitions[registrationType][version]
+	if !ok {
+		return nil, fmt.Errorf("no workflow version %d for registration type %s", version, registrationType)
+	}
+	return def, nil
+}
+
+// Types lists the registration types with a workflow
+func (r *Registry) Types() []string {
+	types := make([]string, 0, len(r.definitions))
+	for registrationType := range r.definitions {
+		types = append(types, registrationType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// knownStatuses are the statuses a registration may hold
+var knownStatuses = map[string]bool{
+	models.RegistrationStatusDraft:     true,
+	models.RegistrationStatusSubmitted: true,
+	models.RegistrationStatusInReview:  true,
+	models.RegistrationStatusApproved:  true,
+	models.RegistrationStatusRejected:  true,
+	models.RegistrationStatusCancelled: true,
+	models.RegistrationStatusCompleted: true,
+}
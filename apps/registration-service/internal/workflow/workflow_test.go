@@ -0,0 +1,143 @@
+package workflow
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/comply360/shared/models"
+	testhelpers "github.com/comply360/shared/testing"
+)
+
+func TestDefaultRegistry_CoversEveryRegistrationType(t *testing.T) {
+	registry := DefaultRegistry()
+
+	for _, registrationType := range []string{
+		models.RegistrationTypePtyLtd,
+		models.RegistrationTypeCloseCorporation,
+		models.RegistrationTypeBusinessName,
+		models.RegistrationTypeVATRegistration,
+	} {
+		def, err := registry.Latest(registrationType)
+		testhelpers.AssertNoError(t, err, registrationType)
+		testhelpers.AssertEqual(t, models.RegistrationStatusDraft, def.Initial)
+
+		_, ok := def.Find(models.RegistrationStatusSubmitted, models.RegistrationStatusInReview)
+		testhelpers.AssertTrue(t, ok, "Submitted registrations should move into review")
+	}
+}
+
+func TestDefinition_CheckRejectsUnknownTransition(t *testing.T) {
+	def, _ := DefaultRegistry().Latest(models.RegistrationTypeBusinessName)
+
+	_, err := def.Check(models.RegistrationStatusDraft, models.RegistrationStatusApproved, Actor{Roles: []string{models.RoleTenantAdmin}}, Facts{})
+	testhelpers.AssertError(t, err)
+	testhelpers.AssertEqual(t, ReasonNotAllowed, err.(*TransitionError).Reason)
+}
+
+func TestDefinition_CheckRequiresRole(t *testing.T) {
+	def, _ := DefaultRegistry().Latest(models.RegistrationTypeBusinessName)
+
+	_, err := def.Check(models.RegistrationStatusSubmitted, models.RegistrationStatusInReview, Actor{Roles: []string{models.RoleAgent}}, Facts{})
+	testhelpers.AssertError(t, err)
+	testhelpers.AssertEqual(t, ReasonForbidden, err.(*TransitionError).Reason)
+
+	_, err = def.Check(models.RegistrationStatusSubmitted, models.RegistrationStatusInReview, Actor{IsGlobalAdmin: true}, Facts{})
+	testhelpers.AssertNoError(t, err, "Global admins may make any transition")
+}
+
+func TestDefinition_CheckReportsUnmetGuards(t *testing.T) {
+	def, _ := DefaultRegistry().Latest(models.RegistrationTypePtyLtd)
+	reviewer := Actor{Roles: []string{models.RoleTenantManager}}
+
+	_, err := def.Check(models.RegistrationStatusInReview, models.RegistrationStatusApproved, reviewer, Facts{Documents: 2, VerifiedDocuments: 1})
+	testhelpers.AssertError(t, err)
+	unmet := err.(*TransitionError).Unmet
+	testhelpers.AssertEqual(t, 2, len(unmet))
+	testhelpers.AssertNotEqual(t, "", unmet[GuardDocumentsVerified])
+	testhelpers.AssertNotEqual(t, "", unmet[GuardNameReserved])
+
+	transition, err := def.Check(models.RegistrationStatusInReview, models.RegistrationStatusApproved, reviewer, Facts{Documents: 2, VerifiedDocuments: 2, NameReserved: true})
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 1, len(transition.Hooks))
+	testhelpers.AssertEqual(t, HookRequestFiling, transition.Hooks[0])
+}
+
+func TestDefinition_CompletionTracksCompliance(t *testing.T) {
+	def, _ := DefaultRegistry().Latest(models.RegistrationTypeCloseCorporation)
+	reviewer := Actor{Roles: []string{models.RoleTenantAdmin}}
+
+	_, err := def.Check(models.RegistrationStatusApproved, models.RegistrationStatusCompleted, reviewer, Facts{})
+	testhelpers.AssertError(t, err)
+
+	transition, err := def.Check(models.RegistrationStatusApproved, models.RegistrationStatusCompleted, reviewer, Facts{InvoicePaid: true})
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 1, len(transition.Hooks))
+	testhelpers.AssertEqual(t, HookTrackCompliance, transition.Hooks[0])
+
+	v1, _ := DefaultRegistry().Get(models.RegistrationTypeCloseCorporation, 1)
+	transition, err = v1.Check(models.RegistrationStatusApproved, models.RegistrationStatusCompleted, reviewer, Facts{InvoicePaid: true})
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 0, len(transition.Hooks), "Version 1 completions are not tracked")
+}
+
+func TestDefinition_SubmissionRequiresChecklist(t *testing.T) {
+	def, _ := DefaultRegistry().Latest(models.RegistrationTypeBusinessName)
+	agent := Actor{Roles: []string{models.RoleAgent}}
+
+	_, err := def.Check(models.RegistrationStatusDraft, models.RegistrationStatusSubmitted, agent, Facts{MissingRequirements: []string{"Owner ID document"}})
+	testhelpers.AssertError(t, err)
+	testhelpers.AssertTrue(t, strings.HasSuffix(err.(*TransitionError).Unmet[GuardRequirementsMet], ": Owner ID document"))
+
+	_, err = def.Check(models.RegistrationStatusDraft, models.RegistrationStatusSubmitted, agent, Facts{})
+	testhelpers.AssertNoError(t, err)
+}
+
+func TestDefinition_V1SubmitsWithoutChecklist(t *testing.T) {
+	registry := DefaultRegistry()
+	agent := Actor{Roles: []string{models.RoleAgent}}
+	missing := Facts{MissingRequirements: []string{"Owner ID document"}}
+
+	latest, _ := registry.Latest(models.RegistrationTypeBusinessName)
+	testhelpers.AssertEqual(t, 2, latest.Version, "New registrations should start on version 2")
+
+	v1, err := registry.Get(models.RegistrationTypeBusinessName, 1)
+	testhelpers.AssertNoError(t, err)
+	_, err = v1.Check(models.RegistrationStatusDraft, models.RegistrationStatusSubmitted, agent, missing)
+	testhelpers.AssertNoError(t, err, "Registrations started on version 1 should keep its rules")
+}
+
+func TestDefinition_V2SubmissionAssignsReviewer(t *testing.T) {
+	registry := DefaultRegistry()
+	agent := Actor{Roles: []string{models.RoleAgent}}
+
+	latest, _ := registry.Latest(models.RegistrationTypePtyLtd)
+	transition, err := latest.Check(models.RegistrationStatusDraft, models.RegistrationStatusSubmitted, agent, Facts{})
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "request_invoice,assign_reviewer", strings.Join(transition.Hooks, ","))
+
+	v1, _ := registry.Get(models.RegistrationTypePtyLtd, 1)
+	transition, err = v1.Check(models.RegistrationStatusDraft, models.RegistrationStatusSubmitted, agent, Facts{})
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, HookRequestInvoice, strings.Join(transition.Hooks, ","), "Version 1 submissions are not auto-assigned")
+}
+
+func TestDefinition_NoDocumentsIsNotVerified(t *testing.T) {
+	testhelpers.AssertFalse(t, Facts{}.satisfies(GuardDocumentsVerified), "A registration without documents has nothing verified")
+}
+
+func TestRegistry_KeepsEarlierVersions(t *testing.T) {
+	v2 := standardV1(models.RegistrationTypeVATRegistration)
+	v2.Version = 2
+	registry := NewRegistry(standardV1(models.RegistrationTypeVATRegistration), v2)
+
+	latest, err := registry.Latest(models.RegistrationTypeVATRegistration)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 2, latest.Version)
+
+	v1, err := registry.Get(models.RegistrationTypeVATRegistration, 1)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 1, v1.Version)
+
+	_, err = registry.Get(models.RegistrationTypeVATRegistration, 3)
+	testhelpers.AssertError(t, err)
+}
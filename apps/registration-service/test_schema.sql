@@ -18,6 +18,13 @@ CREATE TABLE IF NOT EXISTS registrations (
 	odoo_project_id INTEGER,
 	odoo_invoice_id INTEGER,
 	form_data JSONB DEFAULT '{}'::jsonb,
+	workflow_version INT NOT NULL DEFAULT 1,
+	form_schema_version INT,
+	queue_id UUID,
+	assigned_at TIMESTAMP,
+	status_changed_at TIMESTAMP,
+	sla_due_at TIMESTAMP,
+	sla_breached_at TIMESTAMP,
 	metadata JSONB DEFAULT '{}'::jsonb,
 	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
 	updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"io"
+	"net/http"
+
+	"github.com/comply360/shared/errors"
+	"github.com/comply360/shared/models"
+	"github.com/comply360/tenant-service/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type BrandingHandler struct {
+	service *services.BrandingService
+}
+
+func NewBrandingHandler(service *services.BrandingService) *BrandingHandler {
+	return &BrandingHandler{service: service}
+}
+
+// GetBranding returns a tenant's effective branding
+func (h *BrandingHandler) GetBranding(c *gin.Context) {
+	id, ok := parseTenantID(c)
+	if !ok {
+		return
+	}
+
+	branding, err := h.service.GetBranding(id)
+	if err != nil {
+		respondBrandingError(c, err, "Failed to get branding")
+		return
+	}
+
+	c.JSON(http.StatusOK, branding)
+}
+
+// UpdateBranding changes a tenant's branding fields
+func (h *BrandingHandler) UpdateBranding(c *gin.Context) {
+	id, ok := parseTenantID(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateTenantBrandingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(
+			errors.ErrInvalidInput,
+			err.Error(),
+		))
+		return
+	}
+
+	branding, err := h.service.UpdateBranding(id, &req)
+	if err != nil {
+		respondBrandingError(c, err, "Failed to update branding")
+		return
+	}
+
+	c.JSON(http.StatusOK, branding)
+}
+
+// UploadLogo replaces a tenant's logo from the multipart "logo" field
+func (h *BrandingHandler) UploadLogo(c *gin.Context) {
+	id, ok := parseTenantID(c)
+	if !ok {
+		return
+	}
+
+	fileHeader, err := c.FormFile("logo")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(
+			errors.ErrInvalidInput,
+			"A logo image is required in the 'logo' field",
+		))
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(
+			errors.ErrInvalidInput,
+			"Failed to read uploaded logo",
+		))
+		return
+	}
+	defer file.Close()
+
+	branding, err := h.service.UploadLogo(id, file)
+	if err != nil {
+		respondBrandingError(c, err, "Failed to upload logo")
+		return
+	}
+
+	c.JSON(http.StatusOK, branding)
+}
+
+// GetLogo serves a tenant's logo image
+func (h *BrandingHandler) GetLogo(c *gin.Context) {
+	id, ok := parseTenantID(c)
+	if !ok {
+		return
+	}
+
+	logo, contentType, err := h.service.GetLogo(id)
+	if err != nil {
+		respondBrandingError(c, err, "Failed to get logo")
+		return
+	}
+	defer logo.Close()
+
+	c.Header("Content-Type", contentType)
+	c.Header("Cache-Control", "public, max-age=300")
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Status(http.StatusOK)
+	if _, err := io.Copy(c.Writer, logo); err != nil {
+		c.Error(err)
+	}
+}
+
+// DeleteLogo removes a tenant's logo
+func (h *BrandingHandler) DeleteLogo(c *gin.Context) {
+	id, ok := parseTenantID(c)
+	if !ok {
+		return
+	}
+
+	branding, err := h.service.DeleteLogo(id)
+	if err != nil {
+		respondBrandingError(c, err, "Failed to delete logo")
+		return
+	}
+
+	c.JSON(http.StatusOK, branding)
+}
+
+// respondBrandingError maps branding service errors to HTTP responses
+func respondBrandingError(c *gin.Context, err error, fallback string) {
+	var invalid *services.SettingsValidationError
+	if stderrors.As(err, &invalid) {
+		c.JSON(http.StatusBadRequest, errors.ValidationFailed("Invalid branding", invalid.Fields))
+		return
+	}
+
+	switch err.Error() {
+	case "tenant not found":
+		c.JSON(http.StatusNotFound, errors.NewAPIError(errors.ErrTenantNotFound, "Tenant not found"))
+	case "logo not found":
+		c.JSON(http.StatusNotFound, errors.NewAPIError(errors.ErrNotFound, err.Error()))
+	case "logo too large":
+		c.JSON(http.StatusRequestEntityTooLarge, errors.NewAPIError(errors.ErrInvalidInput, "Logo must be at most 512 KB"))
+	case "unsupported logo format":
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(errors.ErrInvalidInput, "Logo must be a PNG, JPEG or WebP image"))
+	case "tenant is not provisioned":
+		c.JSON(http.StatusConflict, errors.NewAPIError(errors.ErrConflict, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, errors.NewAPIError(errors.ErrInternal, fallback))
+	}
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/comply360/shared/errors"
+	"github.com/comply360/shared/models"
+	"github.com/comply360/tenant-service/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type DomainHandler struct {
+	service *services.DomainService
+}
+
+func NewDomainHandler(service *services.DomainService) *DomainHandler {
+	return &DomainHandler{service: service}
+}
+
+// SetCustomDomain registers a custom domain and returns the TXT record to publish
+func (h *DomainHandler) SetCustomDomain(c *gin.Context) {
+	id, ok := parseTenantID(c)
+	if !ok {
+		return
+	}
+
+	var req models.SetCustomDomainRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(
+			errors.ErrInvalidInput,
+			err.Error(),
+		))
+		return
+	}
+
+	verification, err := h.service.SetCustomDomain(id, req.Domain)
+	if err != nil {
+		respondDomainError(c, err, "Failed to set custom domain")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"domain":  verification,
+		"message": "Publish the TXT record, then call /domain/verify",
+	})
+}
+
+// GetCustomDomain returns the custom domain and its verification status
+func (h *DomainHandler) GetCustomDomain(c *gin.Context) {
+	id, ok := parseTenantID(c)
+	if !ok {
+		return
+	}
+
+	verification, err := h.service.GetCustomDomain(id)
+	if err != nil {
+		respondDomainError(c, err, "Failed to get custom domain")
+		return
+	}
+
+	c.JSON(http.StatusOK, verification)
+}
+
+// VerifyCustomDomain checks the DNS TXT record now
+func (h *DomainHandler) VerifyCustomDomain(c *gin.Context) {
+	id, ok := parseTenantID(c)
+	if !ok {
+		return
+	}
+
+	verification, err := h.service.VerifyCustomDomain(id)
+	if err != nil {
+		respondDomainError(c, err, "Failed to verify custom domain")
+		return
+	}
+
+	c.JSON(http.StatusOK, verification)
+}
+
+// RemoveCustomDomain removes the custom domain
+func (h *DomainHandler) RemoveCustomDomain(c *gin.Context) {
+	id, ok := parseTenantID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.RemoveCustomDomain(id); err != nil {
+		respondDomainError(c, err, "Failed to remove custom domain")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Custom domain removed successfully",
+	})
+}
+
+// parseTenantID parses the :id route parameter, writing a 400 on failure
+func parseTenantID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(
+			errors.ErrInvalidInput,
+			"Invalid tenant ID",
+		))
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// respondDomainError maps domain service errors to HTTP responses
+func respondDomainError(c *gin.Context, err error, fallback string) {
+	switch err.Error() {
+	case "tenant not found":
+		c.JSON(http.StatusNotFound, errors.NewAPIError(errors.ErrTenantNotFound, "Tenant not found"))
+	case "no custom domain configured":
+		c.JSON(http.StatusNotFound, errors.NewAPIError(errors.ErrNotFound, err.Error()))
+	case "invalid domain":
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(errors.ErrInvalidInput, err.Error()))
+	case "domain already in use":
+		c.JSON(http.StatusConflict, errors.NewAPIError(errors.ErrAlreadyExists, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, errors.NewAPIError(errors.ErrInternal, fallback))
+	}
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/comply360/shared/errors"
+	"github.com/comply360/shared/models"
+	"github.com/comply360/tenant-service/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ExportHandler struct {
+	service *services.ExportService
+}
+
+func NewExportHandler(service *services.ExportService) *ExportHandler {
+	return &ExportHandler{service: service}
+}
+
+// CreateExport starts an asynchronous full data export of a tenant
+func (h *ExportHandler) CreateExport(c *gin.Context) {
+	id, ok := parseTenantID(c)
+	if !ok {
+		return
+	}
+
+	// The body is optional; without one the export is JSONL
+	var req models.CreateTenantExportRequest
+	if err := c.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(
+			errors.ErrInvalidInput,
+			err.Error(),
+		))
+		return
+	}
+
+	export, err := h.service.RequestExport(id, req.Format, requestingUser(c))
+	if err != nil {
+		respondExportError(c, err, "Failed to start export")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"export":  export,
+		"message": "Export started. Poll /exports/" + export.ID.String() + " for progress.",
+	})
+}
+
+// ListExports lists a tenant's exports
+func (h *ExportHandler) ListExports(c *gin.Context) {
+	id, ok := parseTenantID(c)
+	if !ok {
+		return
+	}
+
+	exports, err := h.service.ListExports(id)
+	if err != nil {
+		respondExportError(c, err, "Failed to list exports")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"exports": exports,
+	})
+}
+
+// GetExport returns export progress, and the manifest and download link once completed
+func (h *ExportHandler) GetExport(c *gin.Context) {
+	id, ok := parseTenantID(c)
+	if !ok {
+		return
+	}
+
+	exportID, err := uuid.Parse(c.Param("exportId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(
+			errors.ErrInvalidInput,
+			"Invalid export ID",
+		))
+		return
+	}
+
+	export, err := h.service.GetExport(id, exportID)
+	if err != nil {
+		respondExportError(c, err, "Failed to get export")
+		return
+	}
+
+	c.JSON(http.StatusOK, export)
+}
+
+// respondExportError maps export service errors to HTTP responses
+func respondExportError(c *gin.Context, err error, fallback string) {
+	switch err.Error() {
+	case "tenant not found":
+		c.JSON(http.StatusNotFound, errors.NewAPIError(errors.ErrTenantNotFound, "Tenant not found"))
+	case "export not found":
+		c.JSON(http.StatusNotFound, errors.NewAPIError(errors.ErrNotFound, err.Error()))
+	case "invalid export format":
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(errors.ErrInvalidInput, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, errors.NewAPIError(errors.ErrInternal, fallback))
+	}
+}
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/comply360/shared/errors"
+	"github.com/comply360/shared/models"
+	"github.com/comply360/tenant-service/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type HierarchyHandler struct {
+	service *services.HierarchyService
+}
+
+func NewHierarchyHandler(service *services.HierarchyService) *HierarchyHandler {
+	return &HierarchyHandler{service: service}
+}
+
+// SetParent makes the tenant a sub-agent of another tenant
+func (h *HierarchyHandler) SetParent(c *gin.Context) {
+	id, ok := parseTenantID(c)
+	if !ok {
+		return
+	}
+
+	var req models.SetTenantParentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(
+			errors.ErrInvalidInput,
+			err.Error(),
+		))
+		return
+	}
+
+	parentID, err := uuid.Parse(req.ParentTenantID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(
+			errors.ErrInvalidInput,
+			"Invalid parent tenant ID",
+		))
+		return
+	}
+
+	tenant, err := h.service.SetParent(id, parentID, req.OverrideRate)
+	if err != nil {
+		respondHierarchyError(c, err, "Failed to set parent tenant")
+		return
+	}
+
+	c.JSON(http.StatusOK, tenant)
+}
+
+// RemoveParent makes the tenant top-level again
+func (h *HierarchyHandler) RemoveParent(c *gin.Context) {
+	id, ok := parseTenantID(c)
+	if !ok {
+		return
+	}
+
+	tenant, err := h.service.RemoveParent(id)
+	if err != nil {
+		respondHierarchyError(c, err, "Failed to remove parent tenant")
+		return
+	}
+
+	c.JSON(http.StatusOK, tenant)
+}
+
+// ListChildren lists the tenant's sub-agents; ?recursive=true includes all descendants
+func (h *HierarchyHandler) ListChildren(c *gin.Context) {
+	id, ok := parseTenantID(c)
+	if !ok {
+		return
+	}
+
+	children, err := h.service.ListChildren(id, c.Query("recursive") == "true")
+	if err != nil {
+		respondHierarchyError(c, err, "Failed to list child tenants")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tenants": children,
+		"total":   len(children),
+	})
+}
+
+// GetRollup reports the registrations and commissions of the tenant's descendants.
+// ?from= and ?to= limit it to records created in that period.
+func (h *HierarchyHandler) GetRollup(c *gin.Context) {
+	id, ok := parseTenantID(c)
+	if !ok {
+		return
+	}
+
+	from, ok := parseReportTime(c, "from")
+	if !ok {
+		return
+	}
+	to, ok := parseReportTime(c, "to")
+	if !ok {
+		return
+	}
+
+	rollup, err := h.service.Rollup(id, from, to)
+	if err != nil {
+		respondHierarchyError(c, err, "Failed to build roll-up report")
+		return
+	}
+
+	c.JSON(http.StatusOK, rollup)
+}
+
+// parseReportTime parses an optional RFC 3339 or YYYY-MM-DD query parameter,
+// writing a 400 on failure
+func parseReportTime(c *gin.Context, name string) (*time.Time, bool) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, true
+	}
+
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t, true
+		}
+	}
+
+	c.JSON(http.StatusBadRequest, errors.NewAPIError(
+		errors.ErrInvalidInput,
+		"Invalid "+name+" date; use YYYY-MM-DD or RFC 3339",
+	))
+	return nil, false
+}
+
+// respondHierarchyError maps hierarchy service errors to HTTP responses
+func respondHierarchyError(c *gin.Context, err error, fallback string) {
+	switch err.Error() {
+	case "tenant not found":
+		c.JSON(http.StatusNotFound, errors.NewAPIError(errors.ErrTenantNotFound, "Tenant not found"))
+	case "parent tenant not found":
+		c.JSON(http.StatusNotFound, errors.NewAPIError(errors.ErrTenantNotFound, err.Error()))
+	case "tenant hierarchy cycle", "tenant hierarchy too deep":
+		c.JSON(http.StatusConflict, errors.NewAPIError(errors.ErrConflict, err.Error()))
+	case "override rate must be between 0 and 100", "from must be before to":
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(errors.ErrInvalidInput, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, errors.NewAPIError(errors.ErrInternal, fallback))
+	}
+}
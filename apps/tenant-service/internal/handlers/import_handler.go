@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/comply360/shared/errors"
+	"github.com/comply360/shared/models"
+	"github.com/comply360/tenant-service/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ImportHandler struct {
+	service *services.ImportService
+}
+
+func NewImportHandler(service *services.ImportService) *ImportHandler {
+	return &ImportHandler{service: service}
+}
+
+// CreateImport uploads an export archive and imports it into a freshly provisioned tenant
+func (h *ImportHandler) CreateImport(c *gin.Context) {
+	id, ok := parseTenantID(c)
+	if !ok {
+		return
+	}
+
+	fileHeader, err := c.FormFile("archive")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(
+			errors.ErrInvalidInput,
+			"An export archive is required in the 'archive' field",
+		))
+		return
+	}
+
+	remapIDs := false
+	if value := c.PostForm("remap_ids"); value != "" {
+		if remapIDs, err = strconv.ParseBool(value); err != nil {
+			c.JSON(http.StatusBadRequest, errors.NewAPIError(
+				errors.ErrInvalidInput,
+				"remap_ids must be true or false",
+			))
+			return
+		}
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(
+			errors.ErrInvalidInput,
+			"Failed to read uploaded archive",
+		))
+		return
+	}
+	defer file.Close()
+
+	result, err := h.service.ImportArchive(id, file, fileHeader.Size, remapIDs, requestingUser(c))
+	if err != nil {
+		respondImportError(c, err, "Failed to start import")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"import":  result,
+		"message": "Import started. Poll /imports/" + result.ID.String() + " for progress.",
+	})
+}
+
+// GetImport returns import progress, and validation errors if the import was rejected
+func (h *ImportHandler) GetImport(c *gin.Context) {
+	id, ok := parseTenantID(c)
+	if !ok {
+		return
+	}
+
+	importID, err := uuid.Parse(c.Param("importId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(
+			errors.ErrInvalidInput,
+			"Invalid import ID",
+		))
+		return
+	}
+
+	result, err := h.service.GetImport(id, importID)
+	if err != nil {
+		respondImportError(c, err, "Failed to get import")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// CloneTenant copies a tenant into a new tenant, anonymising personal data by default
+func (h *ImportHandler) CloneTenant(c *gin.Context) {
+	id, ok := parseTenantID(c)
+	if !ok {
+		return
+	}
+
+	var req models.CloneTenantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(
+			errors.ErrInvalidInput,
+			err.Error(),
+		))
+		return
+	}
+
+	tenant, result, err := h.service.CloneTenant(id, &req, requestingUser(c))
+	if err != nil {
+		respondImportError(c, err, "Failed to clone tenant")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"tenant":  tenant,
+		"import":  result,
+		"message": "Clone started. Poll /tenants/" + tenant.ID.String() + "/imports/" + result.ID.String() + " for progress.",
+	})
+}
+
+// requestingUser reads the optional X-User-ID header
+func requestingUser(c *gin.Context) *uuid.UUID {
+	if userID, err := uuid.Parse(c.GetHeader("X-User-ID")); err == nil {
+		return &userID
+	}
+	return nil
+}
+
+// respondImportError maps import service errors to HTTP responses
+func respondImportError(c *gin.Context, err error, fallback string) {
+	switch err.Error() {
+	case "tenant not found":
+		c.JSON(http.StatusNotFound, errors.NewAPIError(errors.ErrTenantNotFound, "Tenant not found"))
+	case "import not found":
+		c.JSON(http.StatusNotFound, errors.NewAPIError(errors.ErrNotFound, err.Error()))
+	case "tenant is not provisioned":
+		c.JSON(http.StatusConflict, errors.NewAPIError(errors.ErrConflict, err.Error()))
+	case "subdomain already exists":
+		c.JSON(http.StatusConflict, errors.NewAPIError(errors.ErrAlreadyExists, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, errors.NewAPIError(errors.ErrInternal, fallback))
+	}
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/comply360/shared/errors"
+	"github.com/comply360/shared/models"
+	"github.com/comply360/tenant-service/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type LifecycleHandler struct {
+	service *services.LifecycleService
+}
+
+func NewLifecycleHandler(service *services.LifecycleService) *LifecycleHandler {
+	return &LifecycleHandler{service: service}
+}
+
+// SuspendTenant suspends a tenant with a reason
+func (h *LifecycleHandler) SuspendTenant(c *gin.Context) {
+	id, ok := parseTenantID(c)
+	if !ok {
+		return
+	}
+
+	var req models.SuspendTenantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(
+			errors.ErrInvalidInput,
+			err.Error(),
+		))
+		return
+	}
+
+	tenant, err := h.service.SuspendTenant(id, req.Reason)
+	if err != nil {
+		respondLifecycleError(c, err, "Failed to suspend tenant")
+		return
+	}
+
+	c.JSON(http.StatusOK, tenant)
+}
+
+// ReactivateTenant reactivates a suspended tenant or cancels a scheduled deletion
+func (h *LifecycleHandler) ReactivateTenant(c *gin.Context) {
+	id, ok := parseTenantID(c)
+	if !ok {
+		return
+	}
+
+	tenant, err := h.service.ReactivateTenant(id)
+	if err != nil {
+		respondLifecycleError(c, err, "Failed to reactivate tenant")
+		return
+	}
+
+	c.JSON(http.StatusOK, tenant)
+}
+
+// ScheduleDeletion schedules a tenant for purge after a grace period
+func (h *LifecycleHandler) ScheduleDeletion(c *gin.Context) {
+	id, ok := parseTenantID(c)
+	if !ok {
+		return
+	}
+
+	// The body is optional; without one the default grace period applies
+	var req models.ScheduleTenantDeletionRequest
+	if err := c.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(
+			errors.ErrInvalidInput,
+			err.Error(),
+		))
+		return
+	}
+
+	tenant, err := h.service.ScheduleDeletion(id, req.GraceDays)
+	if err != nil {
+		respondLifecycleError(c, err, "Failed to schedule tenant deletion")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"tenant":  tenant,
+		"message": "Tenant scheduled for deletion",
+	})
+}
+
+// GetDeletionCertificate returns the signed deletion certificate of a purged tenant
+func (h *LifecycleHandler) GetDeletionCertificate(c *gin.Context) {
+	id, ok := parseTenantID(c)
+	if !ok {
+		return
+	}
+
+	certificate, err := h.service.GetDeletionCertificate(id)
+	if err != nil {
+		respondLifecycleError(c, err, "Failed to get deletion certificate")
+		return
+	}
+
+	c.JSON(http.StatusOK, certificate)
+}
+
+// respondLifecycleError maps lifecycle service errors to HTTP responses
+func respondLifecycleError(c *gin.Context, err error, fallback string) {
+	switch err.Error() {
+	case "tenant not found":
+		c.JSON(http.StatusNotFound, errors.NewAPIError(errors.ErrTenantNotFound, "Tenant not found"))
+	case "deletion certificate not found":
+		c.JSON(http.StatusNotFound, errors.NewAPIError(errors.ErrNotFound, err.Error()))
+	case "invalid tenant status transition":
+		c.JSON(http.StatusConflict, errors.NewAPIError(errors.ErrConflict, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, errors.NewAPIError(errors.ErrInternal, fallback))
+	}
+}
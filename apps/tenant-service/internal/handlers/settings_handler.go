@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"net/http"
+
+	"github.com/comply360/shared/errors"
+	"github.com/comply360/shared/models"
+	"github.com/comply360/tenant-service/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type SettingsHandler struct {
+	service *services.SettingsService
+}
+
+func NewSettingsHandler(service *services.SettingsService) *SettingsHandler {
+	return &SettingsHandler{service: service}
+}
+
+// ListSettings lists every known setting of a tenant with defaults applied
+func (h *SettingsHandler) ListSettings(c *gin.Context) {
+	id, ok := parseTenantID(c)
+	if !ok {
+		return
+	}
+
+	settings, err := h.service.ListSettings(id)
+	if err != nil {
+		respondSettingsError(c, err, "Failed to list settings")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"settings": settings})
+}
+
+// UpdateSettings sets several settings at once
+func (h *SettingsHandler) UpdateSettings(c *gin.Context) {
+	id, ok := parseTenantID(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateTenantSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(
+			errors.ErrInvalidInput,
+			err.Error(),
+		))
+		return
+	}
+
+	settings, err := h.service.UpdateSettings(id, req.Settings)
+	if err != nil {
+		respondSettingsError(c, err, "Failed to update settings")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"settings": settings})
+}
+
+// GetSetting returns a single setting
+func (h *SettingsHandler) GetSetting(c *gin.Context) {
+	id, ok := parseTenantID(c)
+	if !ok {
+		return
+	}
+
+	setting, err := h.service.GetSetting(id, c.Param("key"))
+	if err != nil {
+		respondSettingsError(c, err, "Failed to get setting")
+		return
+	}
+
+	c.JSON(http.StatusOK, setting)
+}
+
+// UpdateSetting sets a single setting
+func (h *SettingsHandler) UpdateSetting(c *gin.Context) {
+	id, ok := parseTenantID(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateTenantSettingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(
+			errors.ErrInvalidInput,
+			err.Error(),
+		))
+		return
+	}
+
+	setting, err := h.service.UpdateSetting(id, c.Param("key"), req.Value)
+	if err != nil {
+		respondSettingsError(c, err, "Failed to update setting")
+		return
+	}
+
+	c.JSON(http.StatusOK, setting)
+}
+
+// ResetSetting removes a stored setting so its default applies again
+func (h *SettingsHandler) ResetSetting(c *gin.Context) {
+	id, ok := parseTenantID(c)
+	if !ok {
+		return
+	}
+
+	setting, err := h.service.ResetSetting(id, c.Param("key"))
+	if err != nil {
+		respondSettingsError(c, err, "Failed to reset setting")
+		return
+	}
+
+	c.JSON(http.StatusOK, setting)
+}
+
+// respondSettingsError maps settings service errors to HTTP responses
+func respondSettingsError(c *gin.Context, err error, fallback string) {
+	var invalid *services.SettingsValidationError
+	if stderrors.As(err, &invalid) {
+		c.JSON(http.StatusBadRequest, errors.ValidationFailed("Invalid settings", invalid.Fields))
+		return
+	}
+
+	switch err.Error() {
+	case "tenant not found":
+		c.JSON(http.StatusNotFound, errors.NewAPIError(errors.ErrTenantNotFound, "Tenant not found"))
+	case "unknown setting":
+		c.JSON(http.StatusNotFound, errors.NewAPIError(errors.ErrNotFound, err.Error()))
+	case "setting is read-only":
+		c.JSON(http.StatusForbidden, errors.NewAPIError(errors.ErrForbidden, err.Error()))
+	case "tenant is not provisioned":
+		c.JSON(http.StatusConflict, errors.NewAPIError(errors.ErrConflict, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, errors.NewAPIError(errors.ErrInternal, fallback))
+	}
+}
@@ -2,10 +2,10 @@ package handlers
 
 import (
 	"net/http"
-	"strconv"
 
 	"github.com/comply360/shared/errors"
 	"github.com/comply360/shared/models"
+	"github.com/comply360/shared/pagination"
 	"github.com/comply360/tenant-service/internal/services"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -66,10 +66,23 @@ func (h *TenantHandler) ProvisionTenant(c *gin.Context) {
 	}
 
 	if err := h.service.ProvisionTenant(id); err != nil {
-		c.JSON(http.StatusInternalServerError, errors.NewAPIError(
-			errors.ErrInternal,
-			err.Error(),
-		))
+		switch err.Error() {
+		case "tenant not found":
+			c.JSON(http.StatusNotFound, errors.NewAPIError(
+				errors.ErrTenantNotFound,
+				"Tenant not found",
+			))
+		case "provisioning already in progress":
+			c.JSON(http.StatusConflict, errors.NewAPIError(
+				errors.ErrConflict,
+				err.Error(),
+			))
+		default:
+			c.JSON(http.StatusInternalServerError, errors.NewAPIError(
+				errors.ErrInternal,
+				err.Error(),
+			))
+		}
 		return
 	}
 
@@ -78,6 +91,37 @@ func (h *TenantHandler) ProvisionTenant(c *gin.Context) {
 	})
 }
 
+// GetProvisioningStatus returns the provisioning progress of a tenant
+func (h *TenantHandler) GetProvisioningStatus(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(
+			errors.ErrInvalidInput,
+			"Invalid tenant ID",
+		))
+		return
+	}
+
+	status, err := h.service.GetProvisioningStatus(id)
+	if err != nil {
+		if err.Error() == "tenant not found" {
+			c.JSON(http.StatusNotFound, errors.NewAPIError(
+				errors.ErrTenantNotFound,
+				"Tenant not found",
+			))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errors.NewAPIError(
+			errors.ErrInternal,
+			"Failed to get provisioning status",
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
 // GetTenant retrieves a tenant by ID
 func (h *TenantHandler) GetTenant(c *gin.Context) {
 	idStr := c.Param("id")
@@ -102,20 +146,20 @@ func (h *TenantHandler) GetTenant(c *gin.Context) {
 	c.JSON(http.StatusOK, tenant)
 }
 
-// ListTenants lists all tenants with pagination
+// ListTenants lists a page of tenants
 func (h *TenantHandler) ListTenants(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
-
-	if page < 1 {
-		page = 1
-	}
-	if perPage < 1 || perPage > 100 {
-		perPage = 20
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(errors.ErrInvalidInput, err.Error()))
+		return
 	}
 
-	response, err := h.service.ListTenants(page, perPage)
+	tenants, err := h.service.ListTenants(page)
 	if err != nil {
+		if pagination.IsRequestError(err) {
+			c.JSON(http.StatusBadRequest, errors.NewAPIError(errors.ErrInvalidInput, err.Error()))
+			return
+		}
 		c.JSON(http.StatusInternalServerError, errors.NewAPIError(
 			errors.ErrInternal,
 			"Failed to list tenants",
@@ -123,7 +167,7 @@ func (h *TenantHandler) ListTenants(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, response)
+	pagination.Respond(c, tenants, page)
 }
 
 // UpdateTenant updates a tenant
@@ -159,27 +203,3 @@ func (h *TenantHandler) UpdateTenant(c *gin.Context) {
 	c.JSON(http.StatusOK, tenant)
 }
 
-// DeleteTenant soft deletes a tenant
-func (h *TenantHandler) DeleteTenant(c *gin.Context) {
-	idStr := c.Param("id")
-	id, err := uuid.Parse(idStr)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.NewAPIError(
-			errors.ErrInvalidInput,
-			"Invalid tenant ID",
-		))
-		return
-	}
-
-	if err := h.service.DeleteTenant(id); err != nil {
-		c.JSON(http.StatusInternalServerError, errors.NewAPIError(
-			errors.ErrInternal,
-			"Failed to delete tenant",
-		))
-		return
-	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Tenant deleted successfully",
-	})
-}
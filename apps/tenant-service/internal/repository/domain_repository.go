@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/comply360/shared/models"
+	"github.com/google/uuid"
+)
+
+// DomainRecord is a tenant's custom domain together with its verification token
+type DomainRecord struct {
+	TenantID      uuid.UUID
+	Domain        string
+	Status        string
+	Token         string
+	VerifiedAt    *time.Time
+	LastCheckedAt *time.Time
+	LastError     *string
+}
+
+// SetDomain assigns a custom domain to a tenant and resets its verification
+func (r *TenantRepository) SetDomain(tenantID uuid.UUID, domain, token string) error {
+	query := `
+		UPDATE public.tenants
+		SET domain = $2,
+		    domain_status = 'pending',
+		    domain_verification_token = $3,
+		    domain_verified_at = NULL,
+		    domain_last_checked_at = NULL,
+		    domain_last_error = NULL,
+		    updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	result, err := r.db.Exec(query, tenantID, domain, token)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("tenant not found")
+	}
+
+	return nil
+}
+
+// GetDomain retrieves the custom domain record of a tenant
+func (r *TenantRepository) GetDomain(tenantID uuid.UUID) (*DomainRecord, error) {
+	var domain, status, token sql.NullString
+	record := &DomainRecord{TenantID: tenantID}
+
+	query := `
+		SELECT domain, domain_status, domain_verification_token,
+		       domain_verified_at, domain_last_checked_at, domain_last_error
+		FROM public.tenants
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	err := r.db.QueryRow(query, tenantID).Scan(
+		&domain,
+		&status,
+		&token,
+		&record.VerifiedAt,
+		&record.LastCheckedAt,
+		&record.LastError,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("tenant not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !domain.Valid {
+		return nil, fmt.Errorf("no custom domain configured")
+	}
+
+	record.Domain = domain.String
+	record.Status = status.String
+	record.Token = token.String
+
+	return record, nil
+}
+
+// GetByDomain retrieves the live tenant that owns a custom domain
+func (r *TenantRepository) GetByDomain(domain string) (*models.Tenant, error) {
+	var tenantID uuid.UUID
+
+	query := `
+		SELECT id FROM public.tenants
+		WHERE LOWER(domain) = LOWER($1) AND deleted_at IS NULL
+	`
+
+	err := r.db.QueryRow(query, domain).Scan(&tenantID)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("tenant not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(tenantID)
+}
+
+// RecordDomainCheck stores the outcome of a DNS verification attempt
+func (r *TenantRepository) RecordDomainCheck(tenantID uuid.UUID, verified bool, checkErr *string) error {
+	status := models.DomainStatusFailed
+	if verified {
+		status = models.DomainStatusVerified
+	}
+
+	query := `
+		UPDATE public.tenants
+		SET domain_status = $2,
+		    domain_verified_at = CASE WHEN $3 THEN COALESCE(domain_verified_at, NOW()) ELSE NULL END,
+		    domain_last_checked_at = NOW(),
+		    domain_last_error = $4,
+		    updated_at = NOW()
+		WHERE id = $1 AND domain IS NOT NULL
+	`
+
+	_, err := r.db.Exec(query, tenantID, status, verified, checkErr)
+	return err
+}
+
+// ClearDomain removes a tenant's custom domain
+func (r *TenantRepository) ClearDomain(tenantID uuid.UUID) error {
+	query := `
+		UPDATE public.tenants
+		SET domain = NULL,
+		    domain_status = NULL,
+		    domain_verification_token = NULL,
+		    domain_verified_at = NULL,
+		    domain_last_checked_at = NULL,
+		    domain_last_error = NULL,
+		    updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	_, err := r.db.Exec(query, tenantID)
+	return err
+}
+
+// ListUnverifiedDomains lists tenants whose custom domain is still awaiting verification
+func (r *TenantRepository) ListUnverifiedDomains() ([]uuid.UUID, error) {
+	query := `
+		SELECT id FROM public.tenants
+		WHERE domain IS NOT NULL
+		  AND domain_status IN ('pending', 'failed')
+		  AND deleted_at IS NULL
+		ORDER BY domain_last_checked_at NULLS FIRST
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
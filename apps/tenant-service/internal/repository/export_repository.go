@@ -0,0 +1,327 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/comply360/shared/models"
+	"github.com/comply360/shared/tenancy"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// ExportRecord is an export job together with the object key of its archive
+type ExportRecord struct {
+	models.TenantExport
+	ObjectKey *string
+}
+
+// ExportTable is a tenant schema table and its columns in ordinal order.
+// Generated columns are computed by the database, so they are listed apart:
+// they are neither exported nor inserted on import.
+type ExportTable struct {
+	Name      string
+	Columns   []string
+	Generated []string
+}
+
+const exportColumns = `
+	id, tenant_id, format, status, requested_by, current_step, items_total, items_done,
+	object_key, checksum, size_bytes, manifest, last_error,
+	started_at, completed_at, expires_at, created_at
+`
+
+// CreateExport creates a pending export job
+func (r *TenantRepository) CreateExport(export *models.TenantExport) error {
+	query := `
+		INSERT INTO public.tenant_exports (tenant_id, format, status, requested_by)
+		VALUES ($1, $2, 'pending', $3)
+		RETURNING id, status, created_at
+	`
+
+	return r.db.QueryRow(query, export.TenantID, export.Format, export.RequestedBy).Scan(
+		&export.ID,
+		&export.Status,
+		&export.CreatedAt,
+	)
+}
+
+// GetExport retrieves an export job of a tenant
+func (r *TenantRepository) GetExport(tenantID, exportID uuid.UUID) (*ExportRecord, error) {
+	query := `SELECT ` + exportColumns + ` FROM public.tenant_exports WHERE id = $1 AND tenant_id = $2`
+
+	record, err := scanExport(r.db.QueryRow(query, exportID, tenantID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("export not found")
+	}
+	return record, err
+}
+
+// ListExports lists the export jobs of a tenant, newest first
+func (r *TenantRepository) ListExports(tenantID uuid.UUID) ([]*ExportRecord, error) {
+	query := `SELECT ` + exportColumns + ` FROM public.tenant_exports WHERE tenant_id = $1 ORDER BY created_at DESC`
+
+	rows, err := r.db.Query(query, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []*ExportRecord
+	for rows.Next() {
+		record, err := scanExport(rows)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+
+	return records, rows.Err()
+}
+
+// StartExport marks an export as running
+func (r *TenantRepository) StartExport(exportID uuid.UUID) error {
+	query := `UPDATE public.tenant_exports SET status = 'running', started_at = NOW() WHERE id = $1`
+
+	_, err := r.db.Exec(query, exportID)
+	return err
+}
+
+// UpdateExportProgress records the step an export is working on
+func (r *TenantRepository) UpdateExportProgress(exportID uuid.UUID, step string, itemsDone, itemsTotal int) error {
+	query := `UPDATE public.tenant_exports SET current_step = $2, items_done = $3, items_total = $4 WHERE id = $1`
+
+	_, err := r.db.Exec(query, exportID, step, itemsDone, itemsTotal)
+	return err
+}
+
+// CompleteExport stores the result of a finished export
+func (r *TenantRepository) CompleteExport(exportID uuid.UUID, objectKey, checksum string, sizeBytes int64, manifest *models.TenantExportManifest, expiresAt time.Time) error {
+	manifestJSON, err := json.Marshal(manifest)
+	if err != nil {
+		return fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+
+	query := `
+		UPDATE public.tenant_exports
+		SET status = 'completed',
+		    current_step = NULL,
+		    items_done = items_total,
+		    object_key = $2,
+		    checksum = $3,
+		    size_bytes = $4,
+		    manifest = $5,
+		    completed_at = NOW(),
+		    expires_at = $6
+		WHERE id = $1
+	`
+
+	_, err = r.db.Exec(query, exportID, objectKey, checksum, sizeBytes, manifestJSON, expiresAt)
+	return err
+}
+
+// FailExport marks an export as failed
+func (r *TenantRepository) FailExport(exportID uuid.UUID, message string) error {
+	query := `UPDATE public.tenant_exports SET status = 'failed', last_error = $2 WHERE id = $1`
+
+	_, err := r.db.Exec(query, exportID, message)
+	return err
+}
+
+// FailInterruptedExports fails exports left pending or running by a previous process
+func (r *TenantRepository) FailInterruptedExports() (int64, error) {
+	query := `
+		UPDATE public.tenant_exports
+		SET status = 'failed', last_error = 'export interrupted by service restart'
+		WHERE status IN ('pending', 'running')
+	`
+
+	result, err := r.db.Exec(query)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// ListExpiredExports lists completed exports whose download window has closed
+func (r *TenantRepository) ListExpiredExports() ([]*ExportRecord, error) {
+	query := `SELECT ` + exportColumns + ` FROM public.tenant_exports WHERE status = 'completed' AND expires_at <= NOW()`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []*ExportRecord
+	for rows.Next() {
+		record, err := scanExport(rows)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+
+	return records, rows.Err()
+}
+
+// ExpireExport marks an export's archive as removed
+func (r *TenantRepository) ExpireExport(exportID uuid.UUID) error {
+	query := `UPDATE public.tenant_exports SET status = 'expired', object_key = NULL WHERE id = $1`
+
+	_, err := r.db.Exec(query, exportID)
+	return err
+}
+
+// ListExportTables lists the base tables of a tenant schema with their columns
+func (r *TenantRepository) ListExportTables(schemaName string) ([]ExportTable, error) {
+	query := `
+		SELECT c.table_name, c.column_name, c.is_generated <> 'NEVER'
+		FROM information_schema.columns c
+		JOIN information_schema.tables t
+		  ON t.table_schema = c.table_schema AND t.table_name = c.table_name
+		WHERE c.table_schema = $1 AND t.table_type = 'BASE TABLE'
+		ORDER BY c.table_name, c.ordinal_position
+	`
+
+	rows, err := r.db.Query(query, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tables []ExportTable
+	for rows.Next() {
+		var table, column string
+		var generated bool
+		if err := rows.Scan(&table, &column, &generated); err != nil {
+			return nil, err
+		}
+		if len(tables) == 0 || tables[len(tables)-1].Name != table {
+			tables = append(tables, ExportTable{Name: table})
+		}
+		last := &tables[len(tables)-1]
+		if generated {
+			last.Generated = append(last.Generated, column)
+		} else {
+			last.Columns = append(last.Columns, column)
+		}
+	}
+
+	return tables, rows.Err()
+}
+
+// StreamTableRows reads every row of a tenant table under the tenant's RLS context.
+// In JSON mode each row arrives as a single JSON object value; otherwise as one text value per column.
+func (r *TenantRepository) StreamTableRows(tenantID uuid.UUID, schemaName, table string, columns []string, asJSON bool, fn func(values []sql.NullString) error) error {
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = pq.QuoteIdentifier(column)
+	}
+	source := pq.QuoteIdentifier(schemaName) + "." + pq.QuoteIdentifier(table)
+
+	var query string
+	width := len(columns)
+	if asJSON {
+		query = fmt.Sprintf(`SELECT row_to_json(t)::text FROM (SELECT %s FROM %s) t`, strings.Join(quoted, ", "), source)
+		width = 1
+	} else {
+		casts := make([]string, len(quoted))
+		for i, column := range quoted {
+			casts[i] = column + "::text"
+		}
+		query = fmt.Sprintf(`SELECT %s FROM %s`, strings.Join(casts, ", "), source)
+	}
+
+	return tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		rows, err := tx.Query(query)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		values := make([]sql.NullString, width)
+		dest := make([]interface{}, width)
+		for i := range values {
+			dest[i] = &values[i]
+		}
+
+		for rows.Next() {
+			if err := rows.Scan(dest...); err != nil {
+				return err
+			}
+			if err := fn(values); err != nil {
+				return err
+			}
+		}
+
+		return rows.Err()
+	})
+}
+
+// ListDocumentPaths lists the object storage paths of a tenant's documents
+func (r *TenantRepository) ListDocumentPaths(tenantID uuid.UUID, schemaName string) ([]string, error) {
+	query := fmt.Sprintf(`SELECT DISTINCT storage_path FROM %s.documents ORDER BY storage_path`, pq.QuoteIdentifier(schemaName))
+
+	var paths []string
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		rows, err := tx.Query(query)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var path string
+			if err := rows.Scan(&path); err != nil {
+				return err
+			}
+			paths = append(paths, path)
+		}
+
+		return rows.Err()
+	})
+
+	return paths, err
+}
+
+// scanExport scans a tenant_exports row selected with exportColumns
+func scanExport(row interface{ Scan(...interface{}) error }) (*ExportRecord, error) {
+	record := &ExportRecord{}
+	var manifestJSON []byte
+
+	err := row.Scan(
+		&record.ID,
+		&record.TenantID,
+		&record.Format,
+		&record.Status,
+		&record.RequestedBy,
+		&record.CurrentStep,
+		&record.ItemsTotal,
+		&record.ItemsDone,
+		&record.ObjectKey,
+		&record.Checksum,
+		&record.SizeBytes,
+		&manifestJSON,
+		&record.LastError,
+		&record.StartedAt,
+		&record.CompletedAt,
+		&record.ExpiresAt,
+		&record.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if manifestJSON != nil {
+		record.Manifest = &models.TenantExportManifest{}
+		if err := json.Unmarshal(manifestJSON, record.Manifest); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal manifest: %w", err)
+		}
+	}
+
+	return record, nil
+}
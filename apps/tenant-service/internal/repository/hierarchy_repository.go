@@ -0,0 +1,233 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/comply360/shared/models"
+	"github.com/comply360/shared/tenancy"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// Descendant is a live tenant below another in the hierarchy
+type Descendant struct {
+	Tenant *models.Tenant
+	Depth  int
+}
+
+// RegistrationCount is the number of registrations in a status
+type RegistrationCount struct {
+	Status string
+	Count  int
+}
+
+// CommissionTotal totals commissions of one currency and status
+type CommissionTotal struct {
+	Currency string
+	Status   string
+	Count    int
+	Amount   float64
+}
+
+// SetParent makes a tenant a sub-agent of parentID. The database trigger rejects
+// parents that would create a cycle.
+func (r *TenantRepository) SetParent(id, parentID uuid.UUID, overrideRate *float64) error {
+	query := `
+		UPDATE public.tenants
+		SET parent_tenant_id = $2, parent_override_rate = $3, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	result, err := r.db.Exec(query, id, parentID, overrideRate)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23514" {
+			return fmt.Errorf("tenant hierarchy cycle")
+		}
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("tenant not found")
+	}
+
+	return nil
+}
+
+// ClearParent makes a tenant top-level again
+func (r *TenantRepository) ClearParent(id uuid.UUID) error {
+	query := `
+		UPDATE public.tenants
+		SET parent_tenant_id = NULL, parent_override_rate = NULL, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	result, err := r.db.Exec(query, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("tenant not found")
+	}
+
+	return nil
+}
+
+// ListAncestorIDs lists a tenant's ancestors, nearest first
+func (r *TenantRepository) ListAncestorIDs(id uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := r.db.Query(`SELECT tenant_id FROM public.tenant_ancestors($1) ORDER BY depth`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var ancestorID uuid.UUID
+		if err := rows.Scan(&ancestorID); err != nil {
+			return nil, err
+		}
+		ids = append(ids, ancestorID)
+	}
+
+	return ids, rows.Err()
+}
+
+// ListDescendants lists a tenant's live descendants, nearest first. With
+// directOnly only its children are returned.
+func (r *TenantRepository) ListDescendants(id uuid.UUID, directOnly bool) ([]Descendant, error) {
+	query := `
+		SELECT d.tenant_id, d.depth
+		FROM public.tenant_descendants($1) d
+		JOIN public.tenants t ON t.id = d.tenant_id
+		WHERE NOT $2 OR d.depth = 1
+		ORDER BY d.depth, t.name
+	`
+
+	rows, err := r.db.Query(query, id, directOnly)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type entry struct {
+		id    uuid.UUID
+		depth int
+	}
+	var entries []entry
+	for rows.Next() {
+		var e entry
+		if err := rows.Scan(&e.id, &e.depth); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	descendants := make([]Descendant, 0, len(entries))
+	for _, e := range entries {
+		tenant, err := r.GetByID(e.id)
+		if err != nil {
+			return nil, err
+		}
+		descendants = append(descendants, Descendant{Tenant: tenant, Depth: e.depth})
+	}
+
+	return descendants, nil
+}
+
+// CountRegistrations counts a tenant's registrations by status. from and to
+// optionally limit them to those created in [from, to).
+func (r *TenantRepository) CountRegistrations(tc tenancy.Context, schemaName string, from, to *time.Time) ([]RegistrationCount, error) {
+	query := fmt.Sprintf(`
+		SELECT status, COUNT(*)
+		FROM %s.registrations
+		WHERE deleted_at IS NULL
+		  AND ($1::timestamp IS NULL OR created_at >= $1)
+		  AND ($2::timestamp IS NULL OR created_at < $2)
+		GROUP BY status
+	`, pq.QuoteIdentifier(schemaName))
+
+	var counts []RegistrationCount
+	err := tenancy.Run(context.Background(), r.db, tc, func(tx *sql.Tx) error {
+		rows, err := tx.Query(query, from, to)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var count RegistrationCount
+			if err := rows.Scan(&count.Status, &count.Count); err != nil {
+				return err
+			}
+			counts = append(counts, count)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count registrations: %w", err)
+	}
+
+	return counts, nil
+}
+
+// SumCommissions totals a tenant's commissions by currency and status
+func (r *TenantRepository) SumCommissions(tc tenancy.Context, schemaName string, from, to *time.Time) ([]CommissionTotal, error) {
+	return r.sumAmounts(tc, fmt.Sprintf(`
+		SELECT currency, status, COUNT(*), COALESCE(SUM(commission_amount), 0)
+		FROM %s.commissions
+		WHERE ($1::timestamp IS NULL OR created_at >= $1)
+		  AND ($2::timestamp IS NULL OR created_at < $2)
+		GROUP BY currency, status
+	`, pq.QuoteIdentifier(schemaName)), from, to)
+}
+
+// SumCommissionOverrides totals the overrides a tenant earned on its sub-agents' commissions
+func (r *TenantRepository) SumCommissionOverrides(tc tenancy.Context, schemaName string, from, to *time.Time) ([]CommissionTotal, error) {
+	return r.sumAmounts(tc, fmt.Sprintf(`
+		SELECT currency, status, COUNT(*), COALESCE(SUM(override_amount), 0)
+		FROM %s.commission_overrides
+		WHERE ($1::timestamp IS NULL OR created_at >= $1)
+		  AND ($2::timestamp IS NULL OR created_at < $2)
+		GROUP BY currency, status
+	`, pq.QuoteIdentifier(schemaName)), from, to)
+}
+
+func (r *TenantRepository) sumAmounts(tc tenancy.Context, query string, from, to *time.Time) ([]CommissionTotal, error) {
+	var totals []CommissionTotal
+	err := tenancy.Run(context.Background(), r.db, tc, func(tx *sql.Tx) error {
+		rows, err := tx.Query(query, from, to)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var total CommissionTotal
+			if err := rows.Scan(&total.Currency, &total.Status, &total.Count, &total.Amount); err != nil {
+				return err
+			}
+			totals = append(totals, total)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to total commissions: %w", err)
+	}
+
+	return totals, nil
+}
@@ -0,0 +1,293 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/comply360/shared/models"
+	"github.com/comply360/shared/tenancy"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// ImportRecord is an import job together with the object key of its uploaded archive
+type ImportRecord struct {
+	models.TenantImport
+	ObjectKey *string
+}
+
+// ForeignKey is a single-column foreign key between two tables of a tenant schema
+type ForeignKey struct {
+	Table     string
+	Column    string
+	RefTable  string
+	RefColumn string
+}
+
+// ImportTable is the data to restore into one table. Each row is a JSON object.
+type ImportTable struct {
+	Name    string
+	Columns []string
+	Rows    [][]byte
+}
+
+const importColumns = `
+	id, tenant_id, source, source_tenant_id, remap_ids, anonymize, status, requested_by,
+	current_step, items_total, items_done, object_key, stats, validation_errors, last_error,
+	started_at, completed_at, created_at
+`
+
+// CreateImport creates a pending import job
+func (r *TenantRepository) CreateImport(record *ImportRecord) error {
+	query := `
+		INSERT INTO public.tenant_imports
+			(tenant_id, source, source_tenant_id, remap_ids, anonymize, requested_by, object_key)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, status, created_at
+	`
+
+	return r.db.QueryRow(query,
+		record.TenantID,
+		record.Source,
+		record.SourceTenantID,
+		record.RemapIDs,
+		record.Anonymize,
+		record.RequestedBy,
+		record.ObjectKey,
+	).Scan(&record.ID, &record.Status, &record.CreatedAt)
+}
+
+// SetImportObjectKey records where an import's uploaded archive is stored
+func (r *TenantRepository) SetImportObjectKey(importID uuid.UUID, objectKey string) error {
+	_, err := r.db.Exec(`UPDATE public.tenant_imports SET object_key = $2 WHERE id = $1`, importID, objectKey)
+	return err
+}
+
+// GetImport retrieves an import job of a tenant
+func (r *TenantRepository) GetImport(tenantID, importID uuid.UUID) (*ImportRecord, error) {
+	query := `SELECT ` + importColumns + ` FROM public.tenant_imports WHERE id = $1 AND tenant_id = $2`
+
+	record, err := scanImport(r.db.QueryRow(query, importID, tenantID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("import not found")
+	}
+	return record, err
+}
+
+// StartImport marks an import as running
+func (r *TenantRepository) StartImport(importID uuid.UUID) error {
+	query := `UPDATE public.tenant_imports SET status = 'running', started_at = NOW() WHERE id = $1`
+
+	_, err := r.db.Exec(query, importID)
+	return err
+}
+
+// UpdateImportProgress records the step an import is working on
+func (r *TenantRepository) UpdateImportProgress(importID uuid.UUID, step string, itemsDone, itemsTotal int) error {
+	query := `UPDATE public.tenant_imports SET current_step = $2, items_done = $3, items_total = $4 WHERE id = $1`
+
+	_, err := r.db.Exec(query, importID, step, itemsDone, itemsTotal)
+	return err
+}
+
+// CompleteImport stores the result of a finished import
+func (r *TenantRepository) CompleteImport(importID uuid.UUID, stats *models.TenantImportStats) error {
+	statsJSON, err := json.Marshal(stats)
+	if err != nil {
+		return fmt.Errorf("failed to marshal stats: %w", err)
+	}
+
+	query := `
+		UPDATE public.tenant_imports
+		SET status = 'completed',
+		    current_step = NULL,
+		    items_done = items_total,
+		    object_key = NULL,
+		    stats = $2,
+		    completed_at = NOW()
+		WHERE id = $1
+	`
+
+	_, err = r.db.Exec(query, importID, statsJSON)
+	return err
+}
+
+// FailImport marks an import as failed, with any validation errors that stopped it
+func (r *TenantRepository) FailImport(importID uuid.UUID, message string, validationErrors []models.TenantImportError) error {
+	var errorsJSON []byte
+	if len(validationErrors) > 0 {
+		var err error
+		if errorsJSON, err = json.Marshal(validationErrors); err != nil {
+			return fmt.Errorf("failed to marshal validation errors: %w", err)
+		}
+	}
+
+	query := `
+		UPDATE public.tenant_imports
+		SET status = 'failed', last_error = $2, validation_errors = $3
+		WHERE id = $1
+	`
+
+	_, err := r.db.Exec(query, importID, message, errorsJSON)
+	return err
+}
+
+// FailInterruptedImports fails imports left pending or running by a previous process
+func (r *TenantRepository) FailInterruptedImports() (int64, error) {
+	query := `
+		UPDATE public.tenant_imports
+		SET status = 'failed', last_error = 'import interrupted by service restart'
+		WHERE status IN ('pending', 'running')
+	`
+
+	result, err := r.db.Exec(query)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// ListForeignKeys lists the single-column foreign keys between tables of a tenant schema
+func (r *TenantRepository) ListForeignKeys(schemaName string) ([]ForeignKey, error) {
+	query := `
+		SELECT cl.relname, att.attname, rcl.relname, ratt.attname
+		FROM pg_constraint con
+		JOIN pg_class cl ON cl.oid = con.conrelid
+		JOIN pg_namespace ns ON ns.oid = cl.relnamespace
+		JOIN pg_class rcl ON rcl.oid = con.confrelid
+		JOIN pg_attribute att ON att.attrelid = con.conrelid AND att.attnum = con.conkey[1]
+		JOIN pg_attribute ratt ON ratt.attrelid = con.confrelid AND ratt.attnum = con.confkey[1]
+		WHERE con.contype = 'f'
+		  AND ns.nspname = $1
+		  AND array_length(con.conkey, 1) = 1
+		ORDER BY cl.relname, att.attname
+	`
+
+	rows, err := r.db.Query(query, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []ForeignKey
+	for rows.Next() {
+		var key ForeignKey
+		if err := rows.Scan(&key.Table, &key.Column, &key.RefTable, &key.RefColumn); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+// RestoreTenantData loads data into a freshly provisioned tenant schema in one transaction.
+// Only the seeded tables may hold rows beforehand; every table is cleared in clearOrder
+// (children first) and the import tables are inserted in the given order (parents first).
+func (r *TenantRepository) RestoreTenantData(tenantID uuid.UUID, schemaName string, seeded map[string]bool, clearOrder []string, tables []ImportTable) error {
+	return tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		for _, table := range clearOrder {
+			source := pq.QuoteIdentifier(schemaName) + "." + pq.QuoteIdentifier(table)
+
+			if !seeded[table] {
+				var hasRows bool
+				if err := tx.QueryRow(fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s)`, source)).Scan(&hasRows); err != nil {
+					return err
+				}
+				if hasRows {
+					return fmt.Errorf("tenant is not empty")
+				}
+				continue
+			}
+
+			if _, err := tx.Exec(fmt.Sprintf(`DELETE FROM %s`, source)); err != nil {
+				return fmt.Errorf("failed to clear %s: %w", table, err)
+			}
+		}
+
+		for _, table := range tables {
+			if err := insertImportTable(tx, schemaName, table); err != nil {
+				return fmt.Errorf("failed to restore %s: %w", table.Name, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// insertImportTable inserts JSON rows, letting Postgres convert each value to its column type
+func insertImportTable(tx *sql.Tx, schemaName string, table ImportTable) error {
+	if len(table.Rows) == 0 {
+		return nil
+	}
+
+	quoted := make([]string, len(table.Columns))
+	for i, column := range table.Columns {
+		quoted[i] = pq.QuoteIdentifier(column)
+	}
+	columns := strings.Join(quoted, ", ")
+	target := pq.QuoteIdentifier(schemaName) + "." + pq.QuoteIdentifier(table.Name)
+
+	stmt, err := tx.Prepare(fmt.Sprintf(
+		`INSERT INTO %s (%s) SELECT %s FROM json_populate_record(NULL::%s, $1::json)`,
+		target, columns, columns, target,
+	))
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for i, row := range table.Rows {
+		if _, err := stmt.Exec(string(row)); err != nil {
+			return fmt.Errorf("row %d: %w", i+1, err)
+		}
+	}
+
+	return nil
+}
+
+// scanImport scans a tenant_imports row selected with importColumns
+func scanImport(row interface{ Scan(...interface{}) error }) (*ImportRecord, error) {
+	record := &ImportRecord{}
+	var statsJSON, errorsJSON []byte
+
+	err := row.Scan(
+		&record.ID,
+		&record.TenantID,
+		&record.Source,
+		&record.SourceTenantID,
+		&record.RemapIDs,
+		&record.Anonymize,
+		&record.Status,
+		&record.RequestedBy,
+		&record.CurrentStep,
+		&record.ItemsTotal,
+		&record.ItemsDone,
+		&record.ObjectKey,
+		&statsJSON,
+		&errorsJSON,
+		&record.LastError,
+		&record.StartedAt,
+		&record.CompletedAt,
+		&record.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if statsJSON != nil {
+		record.Stats = &models.TenantImportStats{}
+		if err := json.Unmarshal(statsJSON, record.Stats); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal stats: %w", err)
+		}
+	}
+	if errorsJSON != nil {
+		if err := json.Unmarshal(errorsJSON, &record.ValidationErrors); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal validation errors: %w", err)
+		}
+	}
+
+	return record, nil
+}
@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/comply360/shared/models"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// auditActionTenantPurged is the tenant_audit_log action holding a deletion certificate
+const auditActionTenantPurged = "tenant.purged"
+
+// Suspend suspends an active (or already suspended) tenant with a reason
+func (r *TenantRepository) Suspend(id uuid.UUID, reason string) error {
+	query := `
+		UPDATE public.tenants
+		SET status = 'suspended',
+		    suspension_reason = $2,
+		    suspended_at = COALESCE(suspended_at, NOW()),
+		    updated_at = NOW()
+		WHERE id = $1 AND status IN ('active', 'suspended') AND deleted_at IS NULL
+	`
+
+	return r.execLifecycle(query, id, reason)
+}
+
+// Reactivate returns a suspended or pending-deletion tenant to active
+func (r *TenantRepository) Reactivate(id uuid.UUID) error {
+	query := `
+		UPDATE public.tenants
+		SET status = 'active',
+		    suspension_reason = NULL,
+		    suspended_at = NULL,
+		    deletion_requested_at = NULL,
+		    purge_after = NULL,
+		    updated_at = NOW()
+		WHERE id = $1 AND status IN ('suspended', 'pending_deletion') AND deleted_at IS NULL
+	`
+
+	return r.execLifecycle(query, id)
+}
+
+// ScheduleDeletion marks a tenant for purge once the grace period ends
+func (r *TenantRepository) ScheduleDeletion(id uuid.UUID, purgeAfter time.Time) error {
+	query := `
+		UPDATE public.tenants
+		SET status = 'pending_deletion',
+		    deletion_requested_at = NOW(),
+		    purge_after = $2,
+		    suspended_at = COALESCE(suspended_at, NOW()),
+		    suspension_reason = COALESCE(suspension_reason, 'Scheduled for deletion'),
+		    updated_at = NOW()
+		WHERE id = $1 AND status IN ('active', 'suspended') AND deleted_at IS NULL
+	`
+
+	return r.execLifecycle(query, id, purgeAfter)
+}
+
+// execLifecycle runs a status transition, distinguishing a missing tenant from one in the wrong state
+func (r *TenantRepository) execLifecycle(query string, id uuid.UUID, args ...interface{}) error {
+	result, err := r.db.Exec(query, append([]interface{}{id}, args...)...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows > 0 {
+		return nil
+	}
+
+	if _, err := r.GetByID(id); err != nil {
+		return err
+	}
+	return fmt.Errorf("invalid tenant status transition")
+}
+
+// PurgeCandidate is a tenant whose deletion grace period has ended
+type PurgeCandidate struct {
+	Tenant              *models.Tenant
+	DeletionRequestedAt *time.Time
+}
+
+// ListDueForPurge lists tenants whose deletion grace period has ended
+func (r *TenantRepository) ListDueForPurge() ([]uuid.UUID, error) {
+	query := `
+		SELECT id FROM public.tenants
+		WHERE status = 'pending_deletion'
+		  AND purge_after <= NOW()
+		  AND purged_at IS NULL
+		ORDER BY purge_after
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// GetPurgeCandidate loads a tenant pending deletion together with its deletion request time
+func (r *TenantRepository) GetPurgeCandidate(id uuid.UUID) (*PurgeCandidate, error) {
+	tenant, err := r.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if tenant.Status != models.TenantStatusPendingDeletion {
+		return nil, fmt.Errorf("tenant is not pending deletion")
+	}
+
+	candidate := &PurgeCandidate{Tenant: tenant}
+	query := `SELECT deletion_requested_at FROM public.tenants WHERE id = $1`
+	if err := r.db.QueryRow(query, id).Scan(&candidate.DeletionRequestedAt); err != nil {
+		return nil, err
+	}
+
+	return candidate, nil
+}
+
+// DropTenantSchema drops a tenant's schema and its registry entry
+func (r *TenantRepository) DropTenantSchema(tenantID uuid.UUID, schemaName string) error {
+	if _, err := r.db.Exec(fmt.Sprintf(`DROP SCHEMA IF EXISTS %s CASCADE`, pq.QuoteIdentifier(schemaName))); err != nil {
+		return err
+	}
+
+	_, err := r.db.Exec(`DELETE FROM public.tenant_schemas WHERE tenant_id = $1`, tenantID)
+	return err
+}
+
+// CompletePurge marks a tenant deleted and stores its deletion certificate in the global audit log
+func (r *TenantRepository) CompletePurge(certificate *models.TenantDeletionCertificate) error {
+	certificateJSON, err := json.Marshal(certificate)
+	if err != nil {
+		return fmt.Errorf("failed to marshal certificate: %w", err)
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE public.tenants
+		SET status = 'deleted',
+		    purged_at = $2,
+		    deleted_at = $2,
+		    updated_at = NOW()
+		WHERE id = $1 AND status = 'pending_deletion' AND purged_at IS NULL
+	`, certificate.TenantID, certificate.PurgedAt)
+	if err != nil {
+		return err
+	}
+	if rows, err := result.RowsAffected(); err != nil {
+		return err
+	} else if rows == 0 {
+		return fmt.Errorf("tenant is not pending deletion")
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO public.tenant_audit_log (tenant_id, action, entity_type, entity_id, changes)
+		VALUES ($1, $2, 'tenant', $1, $3)
+	`, certificate.TenantID, auditActionTenantPurged, certificateJSON)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetDeletionCertificate retrieves the deletion certificate of a purged tenant
+func (r *TenantRepository) GetDeletionCertificate(tenantID uuid.UUID) (*models.TenantDeletionCertificate, error) {
+	var certificateJSON []byte
+
+	query := `
+		SELECT changes FROM public.tenant_audit_log
+		WHERE entity_id = $1 AND action = $2
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	err := r.db.QueryRow(query, tenantID, auditActionTenantPurged).Scan(&certificateJSON)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("deletion certificate not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var certificate models.TenantDeletionCertificate
+	if err := json.Unmarshal(certificateJSON, &certificate); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal certificate: %w", err)
+	}
+
+	return &certificate, nil
+}
@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/comply360/shared/models"
+	"github.com/google/uuid"
+)
+
+// InitProvisioningSteps creates pending step rows for a tenant, keeping any existing progress
+func (r *TenantRepository) InitProvisioningSteps(tenantID uuid.UUID, steps []string) error {
+	query := `
+		INSERT INTO public.tenant_provisioning_steps (tenant_id, step, position, status)
+		VALUES ($1, $2, $3, 'pending')
+		ON CONFLICT (tenant_id, step) DO NOTHING
+	`
+
+	for i, step := range steps {
+		if _, err := r.db.Exec(query, tenantID, step, i+1); err != nil {
+			return fmt.Errorf("failed to init step %s: %w", step, err)
+		}
+	}
+
+	return nil
+}
+
+// GetProvisioningSteps retrieves the provisioning steps for a tenant in execution order
+func (r *TenantRepository) GetProvisioningSteps(tenantID uuid.UUID) ([]models.TenantProvisioningStep, error) {
+	query := `
+		SELECT step, position, status, attempts, last_error, started_at, completed_at
+		FROM public.tenant_provisioning_steps
+		WHERE tenant_id = $1
+		ORDER BY position
+	`
+
+	rows, err := r.db.Query(query, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	steps := []models.TenantProvisioningStep{}
+	for rows.Next() {
+		var step models.TenantProvisioningStep
+		if err := rows.Scan(
+			&step.Step,
+			&step.Position,
+			&step.Status,
+			&step.Attempts,
+			&step.LastError,
+			&step.StartedAt,
+			&step.CompletedAt,
+		); err != nil {
+			return nil, err
+		}
+		steps = append(steps, step)
+	}
+
+	return steps, rows.Err()
+}
+
+// StartProvisioningStep marks a step as running and counts the attempt
+func (r *TenantRepository) StartProvisioningStep(tenantID uuid.UUID, step string) error {
+	query := `
+		UPDATE public.tenant_provisioning_steps
+		SET status = 'running', attempts = attempts + 1, last_error = NULL, started_at = NOW()
+		WHERE tenant_id = $1 AND step = $2
+	`
+
+	_, err := r.db.Exec(query, tenantID, step)
+	return err
+}
+
+// CompleteProvisioningStep marks a step as completed
+func (r *TenantRepository) CompleteProvisioningStep(tenantID uuid.UUID, step string) error {
+	query := `
+		UPDATE public.tenant_provisioning_steps
+		SET status = 'completed', last_error = NULL, completed_at = NOW()
+		WHERE tenant_id = $1 AND step = $2
+	`
+
+	_, err := r.db.Exec(query, tenantID, step)
+	return err
+}
+
+// FailProvisioningStep marks a step as failed and records the error
+func (r *TenantRepository) FailProvisioningStep(tenantID uuid.UUID, step string, message string) error {
+	query := `
+		UPDATE public.tenant_provisioning_steps
+		SET status = 'failed', last_error = $3
+		WHERE tenant_id = $1 AND step = $2
+	`
+
+	_, err := r.db.Exec(query, tenantID, step, message)
+	return err
+}
+
+// GetProvisionedAt returns when the tenant finished provisioning, or nil if it has not
+func (r *TenantRepository) GetProvisionedAt(tenantID uuid.UUID) (*time.Time, error) {
+	var provisionedAt *time.Time
+
+	query := `SELECT provisioned_at FROM public.tenants WHERE id = $1`
+	err := r.db.QueryRow(query, tenantID).Scan(&provisionedAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("tenant not found")
+	}
+
+	return provisionedAt, err
+}
+
+// EnsureSchemaRecord registers the tenant schema as 'creating' unless it is already registered
+func (r *TenantRepository) EnsureSchemaRecord(tenantID uuid.UUID, schemaName string) error {
+	query := `
+		INSERT INTO public.tenant_schemas (tenant_id, schema_name, status)
+		VALUES ($1, $2, 'creating')
+		ON CONFLICT (schema_name) DO NOTHING
+	`
+
+	_, err := r.db.Exec(query, tenantID, schemaName)
+	return err
+}
+
+// MarkProvisioned activates the tenant schema and stamps the tenant as provisioned
+func (r *TenantRepository) MarkProvisioned(tenantID uuid.UUID, schemaName string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`
+		UPDATE public.tenant_schemas
+		SET status = 'active', updated_at = NOW()
+		WHERE schema_name = $1
+	`, schemaName); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`
+		UPDATE public.tenants
+		SET provisioned_at = COALESCE(provisioned_at, NOW()), updated_at = NOW()
+		WHERE id = $1
+	`, tenantID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
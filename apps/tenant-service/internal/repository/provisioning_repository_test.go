@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/comply360/shared/models"
+	testhelpers "github.com/comply360/shared/testing"
+	"github.com/google/uuid"
+)
+
+func TestTenantRepository_ProvisioningSteps(t *testing.T) {
+	tdb := testhelpers.SetupTestDB(t)
+	defer tdb.Cleanup(t)
+
+	_, err := tdb.DB.Exec(`
+		CREATE TABLE IF NOT EXISTS tenants (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			name VARCHAR(255) NOT NULL,
+			subdomain VARCHAR(100) NOT NULL UNIQUE,
+			domain VARCHAR(255),
+			status VARCHAR(50) NOT NULL DEFAULT 'active',
+			subscription_tier VARCHAR(50) NOT NULL DEFAULT 'starter',
+			company_name VARCHAR(255),
+			contact_email VARCHAR(255),
+			contact_phone VARCHAR(50),
+			country VARCHAR(2),
+			max_users INTEGER NOT NULL DEFAULT 10,
+			provisioned_at TIMESTAMP,
+			created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+			deleted_at TIMESTAMP
+		)
+	`)
+	testhelpers.AssertNoError(t, err)
+
+	_, err = tdb.DB.Exec(`
+		CREATE TABLE IF NOT EXISTS tenant_provisioning_steps (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			tenant_id UUID NOT NULL,
+			step VARCHAR(50) NOT NULL,
+			position INT NOT NULL,
+			status VARCHAR(50) NOT NULL DEFAULT 'pending',
+			attempts INT NOT NULL DEFAULT 0,
+			last_error TEXT,
+			started_at TIMESTAMP,
+			completed_at TIMESTAMP,
+			created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+			UNIQUE(tenant_id, step)
+		)
+	`)
+	testhelpers.AssertNoError(t, err)
+
+	repo := NewTenantRepository(tdb.DB)
+
+	tenant := &models.Tenant{
+		Name:             "Provisioning Test Tenant",
+		Subdomain:        "provision-" + uuid.New().String()[:8],
+		Status:           models.TenantStatusActive,
+		SubscriptionTier: models.SubscriptionTierStarter,
+		MaxUsers:         10,
+	}
+
+	err = repo.Create(tenant)
+	testhelpers.AssertNoError(t, err)
+
+	// Test: Init steps
+	err = repo.InitProvisioningSteps(tenant.ID, models.ProvisioningSteps)
+	testhelpers.AssertNoError(t, err, "Failed to init provisioning steps")
+
+	steps, err := repo.GetProvisioningSteps(tenant.ID)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, len(models.ProvisioningSteps), len(steps))
+	testhelpers.AssertEqual(t, models.ProvisioningStepCreateSchema, steps[0].Step)
+	testhelpers.AssertEqual(t, models.ProvisioningStatusPending, steps[0].Status)
+
+	// Test: Fail then retry a step
+	err = repo.StartProvisioningStep(tenant.ID, models.ProvisioningStepCreateSchema)
+	testhelpers.AssertNoError(t, err)
+	err = repo.FailProvisioningStep(tenant.ID, models.ProvisioningStepCreateSchema, "boom")
+	testhelpers.AssertNoError(t, err)
+	err = repo.StartProvisioningStep(tenant.ID, models.ProvisioningStepCreateSchema)
+	testhelpers.AssertNoError(t, err)
+	err = repo.CompleteProvisioningStep(tenant.ID, models.ProvisioningStepCreateSchema)
+	testhelpers.AssertNoError(t, err)
+
+	// Test: Re-init keeps existing progress
+	err = repo.InitProvisioningSteps(tenant.ID, models.ProvisioningSteps)
+	testhelpers.AssertNoError(t, err)
+
+	steps, err = repo.GetProvisioningSteps(tenant.ID)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, len(models.ProvisioningSteps), len(steps))
+	testhelpers.AssertEqual(t, models.ProvisioningStatusCompleted, steps[0].Status)
+	testhelpers.AssertEqual(t, 2, steps[0].Attempts)
+	testhelpers.AssertNil(t, steps[0].LastError)
+	testhelpers.AssertNotNil(t, steps[0].CompletedAt)
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/comply360/shared/tenancy"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// StoredSetting is a row of a tenant's tenant_settings table. Value holds the
+// sealed envelope when IsEncrypted is set.
+type StoredSetting struct {
+	Key         string
+	Value       string
+	ValueType   string
+	IsEncrypted bool
+	UpdatedAt   time.Time
+}
+
+// ListSettings lists the settings stored in a tenant schema
+func (r *TenantRepository) ListSettings(tenantID uuid.UUID, schemaName string) ([]StoredSetting, error) {
+	query := fmt.Sprintf(`
+		SELECT key, COALESCE(value, ''), value_type, is_encrypted, updated_at
+		FROM %s.tenant_settings
+		ORDER BY key
+	`, pq.QuoteIdentifier(schemaName))
+
+	var stored []StoredSetting
+	err := tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		rows, err := tx.Query(query)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var setting StoredSetting
+			if err := rows.Scan(&setting.Key, &setting.Value, &setting.ValueType, &setting.IsEncrypted, &setting.UpdatedAt); err != nil {
+				return err
+			}
+			stored = append(stored, setting)
+		}
+
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list settings: %w", err)
+	}
+
+	return stored, nil
+}
+
+// UpsertSettings stores settings in a single transaction
+func (r *TenantRepository) UpsertSettings(tenantID uuid.UUID, schemaName string, settings []StoredSetting) error {
+	query := fmt.Sprintf(`
+		INSERT INTO %s.tenant_settings (tenant_id, key, value, value_type, is_encrypted)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (tenant_id, key) DO UPDATE
+		SET value = EXCLUDED.value,
+			value_type = EXCLUDED.value_type,
+			is_encrypted = EXCLUDED.is_encrypted,
+			updated_at = NOW()
+	`, pq.QuoteIdentifier(schemaName))
+
+	return tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		for _, setting := range settings {
+			if _, err := tx.Exec(query, tenantID, setting.Key, setting.Value, setting.ValueType, setting.IsEncrypted); err != nil {
+				return fmt.Errorf("failed to store setting %s: %w", setting.Key, err)
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteSetting removes a stored setting so its default applies again
+func (r *TenantRepository) DeleteSetting(tenantID uuid.UUID, schemaName string, key string) error {
+	query := fmt.Sprintf(`DELETE FROM %s.tenant_settings WHERE key = $1`, pq.QuoteIdentifier(schemaName))
+
+	return tenancy.WithTenant(r.db, tenantID, func(tx *sql.Tx) error {
+		if _, err := tx.Exec(query, key); err != nil {
+			return fmt.Errorf("failed to delete setting %s: %w", key, err)
+		}
+		return nil
+	})
+}
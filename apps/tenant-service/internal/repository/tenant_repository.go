@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/comply360/shared/models"
+	"github.com/comply360/shared/pagination"
 	"github.com/google/uuid"
 )
 
@@ -46,7 +47,8 @@ func (r *TenantRepository) GetByID(id uuid.UUID) (*models.Tenant, error) {
 	tenant := &models.Tenant{}
 
 	query := `
-		SELECT id, name, subdomain, domain, status, subscription_tier,
+		SELECT id, name, subdomain, domain, domain_status, domain_verified_at, domain_last_checked_at,
+		       status, suspended_at, suspension_reason, purge_after, parent_tenant_id, parent_override_rate, subscription_tier,
 		       company_name, contact_email, contact_phone, country,
 		       max_users, created_at, updated_at, deleted_at
 		FROM public.tenants
@@ -58,7 +60,15 @@ func (r *TenantRepository) GetByID(id uuid.UUID) (*models.Tenant, error) {
 		&tenant.Name,
 		&tenant.Subdomain,
 		&tenant.Domain,
+		&tenant.DomainStatus,
+		&tenant.DomainVerifiedAt,
+		&tenant.DomainCheckedAt,
 		&tenant.Status,
+		&tenant.SuspendedAt,
+		&tenant.SuspensionReason,
+		&tenant.PurgeAfter,
+		&tenant.ParentTenantID,
+		&tenant.ParentOverrideRate,
 		&tenant.SubscriptionTier,
 		&tenant.CompanyName,
 		&tenant.ContactEmail,
@@ -82,7 +92,8 @@ func (r *TenantRepository) GetBySubdomain(subdomain string) (*models.Tenant, err
 	tenant := &models.Tenant{}
 
 	query := `
-		SELECT id, name, subdomain, domain, status, subscription_tier,
+		SELECT id, name, subdomain, domain, domain_status, domain_verified_at, domain_last_checked_at,
+		       status, suspended_at, suspension_reason, purge_after, parent_tenant_id, parent_override_rate, subscription_tier,
 		       company_name, contact_email, contact_phone, country,
 		       max_users, created_at, updated_at, deleted_at
 		FROM public.tenants
@@ -94,7 +105,15 @@ func (r *TenantRepository) GetBySubdomain(subdomain string) (*models.Tenant, err
 		&tenant.Name,
 		&tenant.Subdomain,
 		&tenant.Domain,
+		&tenant.DomainStatus,
+		&tenant.DomainVerifiedAt,
+		&tenant.DomainCheckedAt,
 		&tenant.Status,
+		&tenant.SuspendedAt,
+		&tenant.SuspensionReason,
+		&tenant.PurgeAfter,
+		&tenant.ParentTenantID,
+		&tenant.ParentOverrideRate,
 		&tenant.SubscriptionTier,
 		&tenant.CompanyName,
 		&tenant.ContactEmail,
@@ -113,31 +132,56 @@ func (r *TenantRepository) GetBySubdomain(subdomain string) (*models.Tenant, err
 	return tenant, err
 }
 
-// List retrieves all tenants with pagination
-func (r *TenantRepository) List(page, perPage int) ([]models.Tenant, int, error) {
-	offset := (page - 1) * perPage
+// TenantKeys are the fields tenant lists sort by
+var TenantKeys = &pagination.Keys[models.Tenant]{
+	ID: func(t models.Tenant) uuid.UUID { return t.ID },
+	Columns: map[string]pagination.Column[models.Tenant]{
+		"created_at": {SQL: "created_at", Value: func(t models.Tenant) interface{} { return t.CreatedAt }},
+		"updated_at": {SQL: "updated_at", Value: func(t models.Tenant) interface{} { return t.UpdatedAt }},
+		"name":       {SQL: "name", Value: func(t models.Tenant) interface{} { return t.Name }},
+		"subdomain":  {SQL: "subdomain", Value: func(t models.Tenant) interface{} { return t.Subdomain }},
+		"status":     {SQL: "status", Value: func(t models.Tenant) interface{} { return t.Status }},
+	},
+	Default: "-created_at",
+}
+
+// List retrieves a page of tenants after the page's cursor
+func (r *TenantRepository) List(page *pagination.Query[models.Tenant]) (*pagination.List[models.Tenant], error) {
+	// Get total count, only when asked for
+	var total *int
+	if page.IncludeTotal() {
+		var count int
+		countQuery := `SELECT COUNT(*) FROM public.tenants WHERE deleted_at IS NULL`
+		if err := r.db.QueryRow(countQuery).Scan(&count); err != nil {
+			return nil, err
+		}
+		total = &count
+	}
 
-	// Get total count
-	var total int
-	countQuery := `SELECT COUNT(*) FROM public.tenants WHERE deleted_at IS NULL`
-	if err := r.db.QueryRow(countQuery).Scan(&total); err != nil {
-		return nil, 0, err
+	// Continue after the cursor
+	whereClause := "WHERE deleted_at IS NULL"
+	args := []interface{}{}
+	if keyset, values := page.Keyset(1); keyset != "" {
+		whereClause += " AND " + keyset
+		args = append(args, values...)
 	}
+	args = append(args, page.Limit())
 
 	// Get tenants
-	query := `
-		SELECT id, name, subdomain, domain, status, subscription_tier,
+	query := fmt.Sprintf(`
+		SELECT id, name, subdomain, domain, domain_status, domain_verified_at, domain_last_checked_at,
+		       status, suspended_at, suspension_reason, purge_after, parent_tenant_id, parent_override_rate, subscription_tier,
 		       company_name, contact_email, contact_phone, country,
 		       max_users, created_at, updated_at
 		FROM public.tenants
-		WHERE deleted_at IS NULL
-		ORDER BY created_at DESC
-		LIMIT $1 OFFSET $2
-	`
+		%s
+		ORDER BY %s
+		LIMIT $%d
+	`, whereClause, page.OrderBy(), len(args))
 
-	rows, err := r.db.Query(query, perPage, offset)
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 	defer rows.Close()
 
@@ -149,7 +193,15 @@ func (r *TenantRepository) List(page, perPage int) ([]models.Tenant, int, error)
 			&tenant.Name,
 			&tenant.Subdomain,
 			&tenant.Domain,
+			&tenant.DomainStatus,
+			&tenant.DomainVerifiedAt,
+			&tenant.DomainCheckedAt,
 			&tenant.Status,
+			&tenant.SuspendedAt,
+			&tenant.SuspensionReason,
+			&tenant.PurgeAfter,
+			&tenant.ParentTenantID,
+			&tenant.ParentOverrideRate,
 			&tenant.SubscriptionTier,
 			&tenant.CompanyName,
 			&tenant.ContactEmail,
@@ -160,12 +212,15 @@ func (r *TenantRepository) List(page, perPage int) ([]models.Tenant, int, error)
 			&tenant.UpdatedAt,
 		)
 		if err != nil {
-			return nil, 0, err
+			return nil, err
 		}
 		tenants = append(tenants, tenant)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
-	return tenants, total, nil
+	return page.List(tenants, total), nil
 }
 
 // Update updates a tenant
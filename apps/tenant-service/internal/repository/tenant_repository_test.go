@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/comply360/shared/models"
+	"github.com/comply360/shared/pagination"
 	testhelpers "github.com/comply360/shared/testing"
 	"github.com/google/uuid"
 )
@@ -195,10 +196,12 @@ func TestTenantRepository_List(t *testing.T) {
 	}
 
 	// Test: List tenants
-	tenants, total, err := repo.List(1, 100)
+	page, err := TenantKeys.Query(pagination.Page{Limit: 100, IncludeTotal: true})
+	testhelpers.AssertNoError(t, err)
+	tenants, err := repo.List(page)
 	testhelpers.AssertNoError(t, err, "Failed to list tenants")
-	testhelpers.AssertTrue(t, len(tenants) >= 3, "Should return at least 3 tenants")
-	testhelpers.AssertTrue(t, total >= 3, "Total count should be at least 3")
+	testhelpers.AssertTrue(t, len(tenants.Data) >= 3, "Should return at least 3 tenants")
+	testhelpers.AssertTrue(t, *tenants.Total >= 3, "Total count should be at least 3")
 }
 
 func TestTenantRepository_Update(t *testing.T) {
@@ -0,0 +1,346 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	mathrand "math/rand"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Kinds of personal data the anonymiser replaces
+const (
+	piiEmail     = "email"
+	piiFirstName = "first_name"
+	piiLastName  = "last_name"
+	piiFullName  = "full_name"
+	piiCompany   = "company"
+	piiSAID      = "sa_id"
+	piiVAT       = "vat"
+	piiPhone     = "phone"
+	piiAddress   = "address"
+	piiBirthDate = "birth_date"
+	piiShape     = "shape"
+	piiFileName  = "file_name"
+	piiDrop      = "drop"
+)
+
+// anonymizedFields maps column and JSON key names to the kind of data they hold.
+// Keys are matched anywhere in a row, including inside JSONB columns such as form_data.
+// A "parent.key" entry matches key only inside the parent object, or inside the
+// objects of the parent array, for keys too generic to match everywhere.
+var anonymizedFields = map[string]string{
+	"email":                 piiEmail,
+	"contact_email":         piiEmail,
+	"first_name":            piiFirstName,
+	"first_names":           piiFirstName,
+	"last_name":             piiLastName,
+	"surname":               piiLastName,
+	"full_name":             piiFullName,
+	"client_name":           piiFullName,
+	"contact_name":          piiFullName,
+	"director_name":         piiFullName,
+	"company_name":          piiCompany,
+	"trading_name":          piiCompany,
+	"proposed_name":         piiCompany,
+	"entity_name":           piiCompany,
+	"reserved_name":         piiCompany,
+	"similar_name":          piiCompany,
+	"names.name":            piiCompany,
+	"id_number":             piiSAID,
+	"sa_id_number":          piiSAID,
+	"vat_number":            piiVAT,
+	"phone":                 piiPhone,
+	"mobile":                piiPhone,
+	"contact_phone":         piiPhone,
+	"street_address":        piiAddress,
+	"address":               piiAddress,
+	"physical_address":      piiAddress,
+	"postal_address":        piiAddress,
+	"residential_address":   piiAddress,
+	"date_of_birth":         piiBirthDate,
+	"passport_number":       piiShape,
+	"tax_number":            piiShape,
+	"account_number":        piiShape,
+	"file_name":             piiFileName,
+	"ip_address":            piiDrop,
+	"last_login_ip":         piiDrop,
+	"user_agent":            piiDrop,
+	"ocr_text":              piiDrop,
+	"ai_verification_notes": piiDrop,
+}
+
+// mentionFields are free-text keys that may quote other fields of their object,
+// such as a name issue's message quoting the similar name
+var mentionFields = map[string]bool{
+	"message":     true,
+	"reason":      true,
+	"note":        true,
+	"notes":       true,
+	"description": true,
+}
+
+var (
+	fakeFirstNames  = []string{"Thabo", "Lerato", "Sipho", "Naledi", "Pieter", "Anika", "Tendai", "Rudo", "Kagiso", "Zanele", "Johan", "Ayesha"}
+	fakeLastNames   = []string{"Mokoena", "Naidoo", "van der Merwe", "Dlamini", "Botha", "Moyo", "Khumalo", "Pillay", "Nkosi", "Smit", "Chikwanha", "Adams"}
+	fakeCompanyA    = []string{"Blue", "Summit", "Karoo", "Baobab", "Protea", "Granite", "Silver", "Coastal", "Highveld", "Acacia"}
+	fakeCompanyB    = []string{"Holdings", "Traders", "Logistics", "Consulting", "Ventures", "Solutions", "Services", "Investments"}
+	fakeStreets     = []string{"Main", "Church", "Voortrekker", "Jan Smuts", "Oxford", "Long", "Market", "Nelson Mandela"}
+	companySuffixes = []string{"(Pty) Ltd", "(RF) (Pty) Ltd", "Pty Ltd", "NPC", "CC", "Inc", "Ltd"}
+)
+
+// anonymizer replaces personal data with fake values of the same format. Replacements
+// are deterministic within one anonymizer, so a value repeated across tables (a client's
+// email on their user account, say) stays consistent.
+type anonymizer struct {
+	salt []byte
+}
+
+func newAnonymizer() (*anonymizer, error) {
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	return &anonymizer{salt: salt}, nil
+}
+
+// anonymizeRow replaces personal data in a row in place
+func (a *anonymizer) anonymizeRow(row importRow) {
+	a.anonymizeObject("", row)
+}
+
+// anonymizeObject replaces personal data in a row or JSON object in place. Free-text
+// fields of the object that mention a replaced value have the mention replaced too.
+func (a *anonymizer) anonymizeObject(parent string, object map[string]interface{}) {
+	replaced := map[string]string{}
+	for key, value := range object {
+		object[key] = a.anonymizeValue(parent, key, value)
+		if original, ok := value.(string); ok && original != "" {
+			if fake, ok := object[key].(string); ok && fake != original {
+				replaced[original] = fake
+			}
+		}
+	}
+	matchBirthDate(object)
+	if len(replaced) == 0 {
+		return
+	}
+
+	for key, value := range object {
+		text, ok := value.(string)
+		if !ok || !mentionFields[strings.ToLower(key)] {
+			continue
+		}
+		for original, fake := range replaced {
+			text = strings.ReplaceAll(text, original, fake)
+		}
+		object[key] = text
+	}
+}
+
+// anonymizeValue anonymises a value by its key, descending into JSON objects and arrays.
+// parent is the key of the object holding the value.
+func (a *anonymizer) anonymizeValue(parent, key string, value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		a.anonymizeObject(key, v)
+		return v
+	case []interface{}:
+		for i, nested := range v {
+			v[i] = a.anonymizeValue(parent, key, nested)
+		}
+		return v
+	case string:
+		kind, ok := fieldKind(parent, key)
+		if !ok || v == "" {
+			return v
+		}
+		if kind == piiDrop {
+			return nil
+		}
+		return a.replace(kind, v)
+	default:
+		return value
+	}
+}
+
+// fieldKind returns the kind of data a key holds inside its parent
+func fieldKind(parent, key string) (string, bool) {
+	key = strings.ToLower(key)
+	if kind, ok := anonymizedFields[strings.ToLower(parent)+"."+key]; ok {
+		return kind, true
+	}
+	kind, ok := anonymizedFields[key]
+	return kind, ok
+}
+
+// replace returns the fake value for an original value of the given kind
+func (a *anonymizer) replace(kind, original string) string {
+	r := a.rand(kind, original)
+
+	switch kind {
+	case piiEmail:
+		first := strings.ToLower(pick(r, fakeFirstNames))
+		last := strings.ToLower(strings.ReplaceAll(pick(r, fakeLastNames), " ", ""))
+		return fmt.Sprintf("%s.%s.%s@example.com", first, last, a.tag(kind, original))
+	case piiFirstName:
+		return pick(r, fakeFirstNames)
+	case piiLastName:
+		return pick(r, fakeLastNames)
+	case piiFullName:
+		return pick(r, fakeFirstNames) + " " + pick(r, fakeLastNames)
+	case piiCompany:
+		name := pick(r, fakeCompanyA) + " " + pick(r, fakeCompanyB)
+		if suffix := companySuffix(original); suffix != "" {
+			name += " " + suffix
+		}
+		return name
+	case piiSAID:
+		if !isDigits(original) || len(original) != 13 {
+			return scrambleShape(r, original)
+		}
+		return fakeSAIDNumber(r)
+	case piiVAT:
+		return "4" + randomDigits(r, 9)
+	case piiPhone:
+		return "+27" + pick(r, []string{"6", "7", "8"}) + randomDigits(r, 8)
+	case piiAddress:
+		return fmt.Sprintf("%d %s Street", r.Intn(250)+1, pick(r, fakeStreets))
+	case piiBirthDate:
+		born := time.Date(1940+r.Intn(60), time.Month(r.Intn(12)+1), r.Intn(28)+1, 0, 0, 0, 0, time.UTC)
+		return born.Format("2006-01-02")
+	case piiFileName:
+		return "document-" + a.tag(kind, original) + strings.ToLower(filepath.Ext(original))
+	default:
+		return scrambleShape(r, original)
+	}
+}
+
+// rand returns a generator seeded by the value, so equal inputs give equal outputs
+func (a *anonymizer) rand(kind, value string) *mathrand.Rand {
+	mac := hmac.New(sha256.New, a.salt)
+	mac.Write([]byte(kind + ":" + value))
+	sum := mac.Sum(nil)
+	return mathrand.New(mathrand.NewSource(int64(binary.BigEndian.Uint64(sum[:8]))))
+}
+
+// tag returns a short stable token for a value, keeping unique values unique
+func (a *anonymizer) tag(kind, value string) string {
+	mac := hmac.New(sha256.New, a.salt)
+	mac.Write([]byte("tag:" + kind + ":" + value))
+	return hex.EncodeToString(mac.Sum(nil))[:8]
+}
+
+// fakeSAIDNumber generates a South African ID number with a valid date and Luhn check digit
+func fakeSAIDNumber(r *mathrand.Rand) string {
+	year := r.Intn(100)
+	month := r.Intn(12) + 1
+	day := r.Intn(28) + 1
+	digits := fmt.Sprintf("%02d%02d%02d%04d%d8", year, month, day, r.Intn(10000), r.Intn(2))
+
+	sum := 0
+	for i := 0; i < 12; i++ {
+		digit := int(digits[i] - '0')
+		if i%2 == 0 {
+			sum += digit
+		} else {
+			doubled := digit * 2
+			if doubled > 9 {
+				doubled -= 9
+			}
+			sum += doubled
+		}
+	}
+
+	return digits + fmt.Sprint((10-(sum%10))%10)
+}
+
+// matchBirthDate sets the date of birth of an object holding a South African ID
+// number to the date the number encodes, as register validation expects. Run after
+// anonymising, it makes a fake date of birth agree with the fake ID number.
+func matchBirthDate(object map[string]interface{}) {
+	if born, ok := object["date_of_birth"].(string); !ok || born == "" {
+		return
+	}
+	for _, key := range []string{"id_number", "sa_id_number"} {
+		id, _ := object[key].(string)
+		if born, ok := saIDBirthDate(id, time.Now()); ok {
+			object["date_of_birth"] = born
+			return
+		}
+	}
+}
+
+// saIDBirthDate returns the date of birth in the first six digits of a South African
+// ID number. The century is the latest that does not put the birth after today.
+func saIDBirthDate(id string, today time.Time) (string, bool) {
+	if len(id) != 13 || !isDigits(id) {
+		return "", false
+	}
+	yy, _ := strconv.Atoi(id[0:2])
+	mm, _ := strconv.Atoi(id[2:4])
+	dd, _ := strconv.Atoi(id[4:6])
+
+	year := today.Year()/100*100 + yy
+	if year > today.Year() {
+		year -= 100
+	}
+	born := time.Date(year, time.Month(mm), dd, 0, 0, 0, 0, time.UTC)
+	if born.Year() != year || int(born.Month()) != mm || born.Day() != dd || born.After(today) {
+		return "", false
+	}
+	return born.Format("2006-01-02"), true
+}
+
+// scrambleShape replaces digits with digits and letters with letters, keeping everything else
+func scrambleShape(r *mathrand.Rand, value string) string {
+	out := []rune(value)
+	for i, c := range out {
+		switch {
+		case c >= '0' && c <= '9':
+			out[i] = rune('0' + r.Intn(10))
+		case c >= 'a' && c <= 'z':
+			out[i] = rune('a' + r.Intn(26))
+		case c >= 'A' && c <= 'Z':
+			out[i] = rune('A' + r.Intn(26))
+		}
+	}
+	return string(out)
+}
+
+// companySuffix returns the legal-form suffix of a company name, if it has one
+func companySuffix(name string) string {
+	for _, suffix := range companySuffixes {
+		if strings.HasSuffix(strings.ToLower(name), strings.ToLower(suffix)) {
+			return suffix
+		}
+	}
+	return ""
+}
+
+func randomDigits(r *mathrand.Rand, n int) string {
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		b.WriteByte(byte('0' + r.Intn(10)))
+	}
+	return b.String()
+}
+
+func isDigits(value string) bool {
+	for _, c := range value {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return value != ""
+}
+
+func pick(r *mathrand.Rand, values []string) string {
+	return values[r.Intn(len(values))]
+}
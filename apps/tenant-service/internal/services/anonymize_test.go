@@ -0,0 +1,139 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	testhelpers "github.com/comply360/shared/testing"
+	"github.com/comply360/shared/validator"
+)
+
+func TestAnonymizer_KeepsFormatsValid(t *testing.T) {
+	anon, err := newAnonymizer()
+	testhelpers.AssertNoError(t, err)
+	v := validator.New()
+
+	for _, id := range []string{"9001015009087", "8502285800083", "7707075000080"} {
+		fake := anon.replace(piiSAID, id)
+		testhelpers.AssertNotEqual(t, id, fake)
+		testhelpers.AssertNoError(t, v.ValidateVar(fake, "sa_id_number"), "Fake ID "+fake+" should pass the SA ID validator")
+	}
+
+	vat := anon.replace(piiVAT, "4123456789")
+	testhelpers.AssertNoError(t, v.ValidateVar(vat, "vat_number"), "Fake VAT number should pass the VAT validator")
+
+	email := anon.replace(piiEmail, "jane.doe@agency.co.za")
+	testhelpers.AssertNoError(t, v.ValidateVar(email, "email"), "Fake email should be a valid address")
+
+	phone := anon.replace(piiPhone, "+27821234567")
+	testhelpers.AssertNoError(t, v.ValidateVar(phone, "phone"))
+
+	company := anon.replace(piiCompany, "Acme Trading (Pty) Ltd")
+	testhelpers.AssertTrue(t, strings.HasSuffix(company, "(Pty) Ltd"), "Company suffix should be kept")
+}
+
+func TestAnonymizer_IsConsistentWithinRun(t *testing.T) {
+	anon, err := newAnonymizer()
+	testhelpers.AssertNoError(t, err)
+
+	testhelpers.AssertEqual(t, anon.replace(piiEmail, "a@b.co.za"), anon.replace(piiEmail, "a@b.co.za"))
+	testhelpers.AssertNotEqual(t, anon.replace(piiEmail, "a@b.co.za"), anon.replace(piiEmail, "c@b.co.za"))
+}
+
+func TestAnonymizer_AnonymizeRow(t *testing.T) {
+	anon, err := newAnonymizer()
+	testhelpers.AssertNoError(t, err)
+
+	row := importRow{
+		"id":            "2d5a7c6e-3f57-4a55-9d6c-5a0cf1e7d3a1",
+		"full_name":     "Jane Doe",
+		"email":         "jane@agency.co.za",
+		"id_number":     "9001015009087",
+		"last_login_ip": "196.21.4.7",
+		"status":        "active",
+		"form_data": map[string]interface{}{
+			"directors": []interface{}{
+				map[string]interface{}{"director_name": "John Smith", "sa_id_number": "8502285800083"},
+			},
+		},
+	}
+	anon.anonymizeRow(row)
+
+	testhelpers.AssertEqual(t, "2d5a7c6e-3f57-4a55-9d6c-5a0cf1e7d3a1", row["id"], "Keys should be untouched")
+	testhelpers.AssertEqual(t, "active", row["status"])
+	testhelpers.AssertNotEqual(t, "Jane Doe", row["full_name"])
+	testhelpers.AssertNotEqual(t, "jane@agency.co.za", row["email"])
+	testhelpers.AssertNil(t, row["last_login_ip"], "Dropped fields should be cleared")
+
+	director := row["form_data"].(map[string]interface{})["directors"].([]interface{})[0].(map[string]interface{})
+	testhelpers.AssertNotEqual(t, "John Smith", director["director_name"], "Nested names should be anonymised")
+	testhelpers.AssertNotEqual(t, "8502285800083", director["sa_id_number"], "Nested ID numbers should be anonymised")
+}
+
+func TestAnonymizer_ReplacesCompanyNamesConsistently(t *testing.T) {
+	anon, err := newAnonymizer()
+	testhelpers.AssertNoError(t, err)
+
+	reservation := importRow{
+		"reserved_name": "Acme Trading (Pty) Ltd",
+		"names": []interface{}{
+			map[string]interface{}{
+				"name":   "Acme Trading (Pty) Ltd",
+				"status": "approved",
+				"issues": []interface{}{
+					map[string]interface{}{
+						"code":         "similar_name",
+						"message":      `name is similar to registration "Acme Traders CC"`,
+						"similar_name": "Acme Traders CC",
+					},
+				},
+			},
+		},
+	}
+	registration := importRow{"company_name": "Acme Trading (Pty) Ltd", "name": "Default queue"}
+	anon.anonymizeRow(reservation)
+	anon.anonymizeRow(registration)
+
+	proposed := reservation["names"].([]interface{})[0].(map[string]interface{})
+	issue := proposed["issues"].([]interface{})[0].(map[string]interface{})
+	testhelpers.AssertNotEqual(t, "Acme Trading (Pty) Ltd", reservation["reserved_name"])
+	testhelpers.AssertEqual(t, registration["company_name"], reservation["reserved_name"], "The reserved name should match the company name it became")
+	testhelpers.AssertEqual(t, registration["company_name"], proposed["name"])
+	testhelpers.AssertEqual(t, "approved", proposed["status"])
+	testhelpers.AssertNotEqual(t, "Acme Traders CC", issue["similar_name"])
+	testhelpers.AssertEqual(t, `name is similar to registration "`+issue["similar_name"].(string)+`"`, issue["message"], "Messages should quote the replaced name")
+	testhelpers.AssertEqual(t, "similar_name", issue["code"], "Values are not keys")
+	testhelpers.AssertEqual(t, "Default queue", registration["name"], "Names outside proposed names are kept")
+}
+
+func TestAnonymizer_RegisterEntryKeepsBirthDateMatchingID(t *testing.T) {
+	anon, err := newAnonymizer()
+	testhelpers.AssertNoError(t, err)
+
+	director := importRow{
+		"first_names":         "Jane Mary",
+		"last_name":           "Doe",
+		"id_number":           "9001015009087",
+		"date_of_birth":       "1990-01-01",
+		"residential_address": "12 Real Road, Sandton",
+	}
+	owner := importRow{
+		"entity_name":     "Doe Family Trust",
+		"passport_number": "A1234567",
+		"date_of_birth":   "1985-06-15",
+	}
+	anon.anonymizeRow(director)
+	anon.anonymizeRow(owner)
+
+	testhelpers.AssertNotEqual(t, "Jane Mary", director["first_names"])
+	testhelpers.AssertNotEqual(t, "12 Real Road, Sandton", director["residential_address"])
+	testhelpers.AssertNotEqual(t, "Doe Family Trust", owner["entity_name"])
+	testhelpers.AssertNotEqual(t, "1985-06-15", owner["date_of_birth"], "Birth dates without an ID number should be replaced")
+
+	id := director["id_number"].(string)
+	born, ok := saIDBirthDate(id, time.Now())
+	testhelpers.AssertTrue(t, ok)
+	testhelpers.AssertEqual(t, born, director["date_of_birth"], "The birth date should match the fake ID number")
+	testhelpers.AssertEqual(t, id[0:2]+id[2:4]+id[4:6], strings.ReplaceAll(born, "-", "")[2:], "The birth date should be the ID's first six digits")
+}
@@ -0,0 +1,219 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/comply360/shared/branding"
+	"github.com/comply360/shared/models"
+	"github.com/comply360/shared/settings"
+	"github.com/comply360/shared/validator"
+	"github.com/google/uuid"
+)
+
+// maxLogoSize bounds uploaded logos; they are embedded in every email
+const maxLogoSize = 512 * 1024
+
+// logoContentTypes are the accepted logo formats. SVG is excluded because it can
+// carry scripts and the logo is served from the API origin.
+var logoContentTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/webp": true,
+}
+
+// brandingFields maps branding API fields to the settings that store them
+var brandingFields = map[string]string{
+	"display_name":    settings.KeyBrandingDisplayName,
+	"primary_color":   settings.KeyBrandingPrimaryColor,
+	"secondary_color": settings.KeyBrandingSecondaryColor,
+	"support_email":   settings.KeyBrandingSupportEmail,
+	"sender_name":     settings.KeyBrandingSenderName,
+	"reply_to":        settings.KeyBrandingReplyTo,
+	"legal_footer":    settings.KeyBrandingLegalFooter,
+}
+
+// LogoStore stores tenant logos. MinIOObjectStore satisfies it.
+type LogoStore interface {
+	GetObject(ctx context.Context, key string) (io.ReadCloser, error)
+	PutObject(ctx context.Context, key string, reader io.Reader, size int64, contentType string) error
+	RemoveObject(ctx context.Context, key string) error
+}
+
+// BrandingService manages a tenant's white-label profile. The profile is kept in
+// branding_* tenant settings, so other services read it through the settings client;
+// the logo itself lives in object storage under the tenant's prefix.
+type BrandingService struct {
+	settings  *SettingsService
+	store     LogoStore
+	publicURL string
+}
+
+func NewBrandingService(settingsService *SettingsService, store LogoStore, publicURL string) *BrandingService {
+	return &BrandingService{
+		settings:  settingsService,
+		store:     store,
+		publicURL: publicURL,
+	}
+}
+
+// GetBranding returns a tenant's effective branding, with anything it has not set
+// inherited from its parent and platform fallbacks applied
+func (s *BrandingService) GetBranding(tenantID uuid.UUID) (*branding.Branding, error) {
+	list, err := s.settings.ListSettings(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]interface{}, len(list))
+	for _, setting := range list {
+		if (setting.IsSet || setting.InheritedFrom != nil) && !setting.Sensitive {
+			values[setting.Key] = setting.Value
+		}
+	}
+
+	b := branding.FromSettings(tenantID, values, branding.Defaults(), s.publicURL)
+	return &b, nil
+}
+
+// UpdateBranding sets the given branding fields; empty values clear a field
+func (s *BrandingService) UpdateBranding(tenantID uuid.UUID, req *models.UpdateTenantBrandingRequest) (*branding.Branding, error) {
+	fields := map[string]*string{
+		"display_name":    req.DisplayName,
+		"primary_color":   req.PrimaryColor,
+		"secondary_color": req.SecondaryColor,
+		"support_email":   req.SupportEmail,
+		"sender_name":     req.SenderName,
+		"reply_to":        req.ReplyTo,
+		"legal_footer":    req.LegalFooter,
+	}
+
+	values := map[string]interface{}{}
+	var cleared []string
+	for field, value := range fields {
+		if value == nil {
+			continue
+		}
+		if *value == "" {
+			cleared = append(cleared, brandingFields[field])
+			continue
+		}
+		values[brandingFields[field]] = *value
+	}
+
+	if len(values) > 0 {
+		if _, err := s.settings.UpdateSettings(tenantID, values); err != nil {
+			return nil, brandingValidationError(err)
+		}
+	}
+	for _, key := range cleared {
+		if _, err := s.settings.ResetSetting(tenantID, key); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.GetBranding(tenantID)
+}
+
+// UploadLogo stores a new logo. The format is sniffed from the content rather than
+// trusted from the upload.
+func (s *BrandingService) UploadLogo(tenantID uuid.UUID, reader io.Reader) (*branding.Branding, error) {
+	if _, err := s.settings.tenantSchema(tenantID); err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(io.LimitReader(reader, maxLogoSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read logo: %w", err)
+	}
+	if len(data) > maxLogoSize {
+		return nil, fmt.Errorf("logo too large")
+	}
+
+	contentType := http.DetectContentType(data)
+	if !logoContentTypes[contentType] {
+		return nil, fmt.Errorf("unsupported logo format")
+	}
+
+	err = s.store.PutObject(context.Background(), logoObjectKey(tenantID), bytes.NewReader(data), int64(len(data)), contentType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store logo: %w", err)
+	}
+
+	if _, err := s.settings.update(tenantID, map[string]interface{}{settings.KeyBrandingLogoType: contentType}, true); err != nil {
+		return nil, err
+	}
+
+	return s.GetBranding(tenantID)
+}
+
+// GetLogo opens a tenant's logo, or the one it inherits from its parent, and
+// returns its content type
+func (s *BrandingService) GetLogo(tenantID uuid.UUID) (io.ReadCloser, string, error) {
+	setting, err := s.settings.GetSetting(tenantID, settings.KeyBrandingLogoType)
+	if err != nil {
+		return nil, "", err
+	}
+	contentType, _ := setting.Value.(string)
+	if (!setting.IsSet && setting.InheritedFrom == nil) || contentType == "" {
+		return nil, "", fmt.Errorf("logo not found")
+	}
+
+	owner := tenantID
+	if setting.InheritedFrom != nil {
+		owner = *setting.InheritedFrom
+	}
+
+	object, err := s.store.GetObject(context.Background(), logoObjectKey(owner))
+	if err != nil {
+		return nil, "", fmt.Errorf("logo not found")
+	}
+
+	return object, contentType, nil
+}
+
+// DeleteLogo removes a tenant's logo so the platform logo is used again
+func (s *BrandingService) DeleteLogo(tenantID uuid.UUID) (*branding.Branding, error) {
+	if _, err := s.settings.reset(tenantID, settings.KeyBrandingLogoType, true); err != nil {
+		return nil, err
+	}
+
+	if err := s.store.RemoveObject(context.Background(), logoObjectKey(tenantID)); err != nil {
+		return nil, fmt.Errorf("failed to remove logo: %w", err)
+	}
+
+	return s.GetBranding(tenantID)
+}
+
+// logoObjectKey is where a tenant's logo is stored. It sits under the tenant prefix
+// so the logo is removed when the tenant is purged.
+func logoObjectKey(tenantID uuid.UUID) string {
+	return tenantObjectPrefix(tenantID) + "branding/logo"
+}
+
+// brandingValidationError reports settings validation failures under the branding
+// API field names
+func brandingValidationError(err error) error {
+	invalid, ok := err.(*SettingsValidationError)
+	if !ok {
+		return err
+	}
+
+	fieldsByKey := make(map[string]string, len(brandingFields))
+	for field, key := range brandingFields {
+		fieldsByKey[key] = field
+	}
+
+	fields := make([]validator.ValidationError, 0, len(invalid.Fields))
+	for _, fieldErr := range invalid.Fields {
+		if field, ok := fieldsByKey[fieldErr.Field]; ok {
+			fieldErr.Field = field
+		}
+		fields = append(fields, fieldErr)
+	}
+
+	return &SettingsValidationError{Fields: fields}
+}
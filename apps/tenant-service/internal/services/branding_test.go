@@ -0,0 +1,36 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/comply360/shared/settings"
+	testhelpers "github.com/comply360/shared/testing"
+	"github.com/comply360/shared/validator"
+	"github.com/google/uuid"
+)
+
+func TestBrandingValidationError_UsesBrandingFieldNames(t *testing.T) {
+	err := brandingValidationError(&SettingsValidationError{Fields: []validator.ValidationError{
+		{Field: settings.KeyBrandingPrimaryColor, Message: "failed hexcolor validation", Tag: "setting"},
+		{Field: settings.KeyBrandingReplyTo, Message: "failed email validation", Tag: "setting"},
+	}})
+
+	invalid, ok := err.(*SettingsValidationError)
+	testhelpers.AssertTrue(t, ok, "Validation errors should be preserved")
+	testhelpers.AssertEqual(t, 2, len(invalid.Fields))
+	testhelpers.AssertEqual(t, "primary_color", invalid.Fields[0].Field)
+	testhelpers.AssertEqual(t, "reply_to", invalid.Fields[1].Field)
+}
+
+func TestBrandingFields_AreRegisteredSettings(t *testing.T) {
+	for field, key := range brandingFields {
+		def, ok := settings.Lookup(key)
+		testhelpers.AssertTrue(t, ok, field+" should map to a registered setting")
+		testhelpers.AssertFalse(t, def.ReadOnly, field+" should be writable")
+	}
+}
+
+func TestLogoObjectKey_IsUnderTenantPrefix(t *testing.T) {
+	tenantID := uuid.New()
+	testhelpers.AssertEqual(t, "tenants/"+tenantID.String()+"/branding/logo", logoObjectKey(tenantID))
+}
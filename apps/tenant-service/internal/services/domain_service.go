@@ -0,0 +1,195 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/comply360/shared/models"
+	"github.com/comply360/tenant-service/internal/repository"
+	"github.com/google/uuid"
+)
+
+const (
+	// domainVerificationHost is prefixed to the custom domain to form the TXT record name
+	domainVerificationHost = "_comply360-verification."
+	// domainVerificationValue is prefixed to the token to form the TXT record value
+	domainVerificationValue = "comply360-verification="
+	// domainLookupTimeout bounds a single DNS lookup
+	domainLookupTimeout = 5 * time.Second
+)
+
+var domainPattern = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z]{2,63}$`)
+
+// TXTResolver looks up DNS TXT records. *net.Resolver satisfies it; tests inject a fake.
+type TXTResolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// DomainService handles custom domain onboarding and DNS verification
+type DomainService struct {
+	repo     *repository.TenantRepository
+	resolver TXTResolver
+	events   *EventPublisher
+}
+
+func NewDomainService(repo *repository.TenantRepository, resolver TXTResolver, events *EventPublisher) *DomainService {
+	return &DomainService{
+		repo:     repo,
+		resolver: resolver,
+		events:   events,
+	}
+}
+
+// SetCustomDomain registers a custom domain for a tenant and issues a verification token
+func (s *DomainService) SetCustomDomain(tenantID uuid.UUID, domain string) (*models.TenantDomainVerification, error) {
+	domain = normalizeDomain(domain)
+	if !domainPattern.MatchString(domain) || len(domain) > 255 {
+		return nil, fmt.Errorf("invalid domain")
+	}
+
+	// Check the domain is not claimed by another tenant
+	if owner, err := s.repo.GetByDomain(domain); err == nil && owner.ID != tenantID {
+		return nil, fmt.Errorf("domain already in use")
+	}
+
+	token, err := generateVerificationToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate verification token: %w", err)
+	}
+
+	if err := s.repo.SetDomain(tenantID, domain, token); err != nil {
+		return nil, err
+	}
+
+	s.events.PublishDomainChanged(tenantID, domain)
+
+	return s.GetCustomDomain(tenantID)
+}
+
+// GetCustomDomain returns the custom domain of a tenant and its verification instructions
+func (s *DomainService) GetCustomDomain(tenantID uuid.UUID) (*models.TenantDomainVerification, error) {
+	record, err := s.repo.GetDomain(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toDomainVerification(record), nil
+}
+
+// VerifyCustomDomain checks the DNS TXT record of a tenant's custom domain and records the result
+func (s *DomainService) VerifyCustomDomain(tenantID uuid.UUID) (*models.TenantDomainVerification, error) {
+	record, err := s.repo.GetDomain(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), domainLookupTimeout)
+	defer cancel()
+
+	var checkErr *string
+	verified := true
+	if err := s.checkTXT(ctx, record.Domain, record.Token); err != nil {
+		message := err.Error()
+		checkErr = &message
+		verified = false
+	}
+
+	if err := s.repo.RecordDomainCheck(tenantID, verified, checkErr); err != nil {
+		return nil, fmt.Errorf("failed to record domain check: %w", err)
+	}
+
+	if verified != (record.Status == models.DomainStatusVerified) {
+		s.events.PublishDomainChanged(tenantID, record.Domain)
+	}
+
+	return s.GetCustomDomain(tenantID)
+}
+
+// RemoveCustomDomain removes a tenant's custom domain
+func (s *DomainService) RemoveCustomDomain(tenantID uuid.UUID) error {
+	record, err := s.repo.GetDomain(tenantID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.ClearDomain(tenantID); err != nil {
+		return err
+	}
+
+	s.events.PublishDomainChanged(tenantID, record.Domain)
+
+	return nil
+}
+
+// VerifyPendingDomains re-checks every domain still awaiting verification
+func (s *DomainService) VerifyPendingDomains() (int, error) {
+	ids, err := s.repo.ListUnverifiedDomains()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list unverified domains: %w", err)
+	}
+
+	verified := 0
+	for _, id := range ids {
+		result, err := s.VerifyCustomDomain(id)
+		if err != nil {
+			log.Printf("Warning: Failed to verify domain for tenant %s: %v", id, err)
+			continue
+		}
+		if result.Status == models.DomainStatusVerified {
+			verified++
+		}
+	}
+
+	return verified, nil
+}
+
+// checkTXT looks for the expected verification value among the domain's TXT records
+func (s *DomainService) checkTXT(ctx context.Context, domain, token string) error {
+	records, err := s.resolver.LookupTXT(ctx, domainVerificationHost+domain)
+	if err != nil {
+		return fmt.Errorf("TXT lookup failed: %w", err)
+	}
+
+	expected := domainVerificationValue + token
+	for _, record := range records {
+		if strings.TrimSpace(record) == expected {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("verification TXT record not found")
+}
+
+// toDomainVerification builds the API view of a domain record
+func toDomainVerification(record *repository.DomainRecord) *models.TenantDomainVerification {
+	return &models.TenantDomainVerification{
+		TenantID:       record.TenantID,
+		Domain:         record.Domain,
+		Status:         record.Status,
+		TXTRecordName:  domainVerificationHost + record.Domain,
+		TXTRecordValue: domainVerificationValue + record.Token,
+		VerifiedAt:     record.VerifiedAt,
+		LastCheckedAt:  record.LastCheckedAt,
+		LastError:      record.LastError,
+	}
+}
+
+// normalizeDomain lowercases a domain and strips a trailing dot
+func normalizeDomain(domain string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+}
+
+// generateVerificationToken generates a random hex token
+func generateVerificationToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	testhelpers "github.com/comply360/shared/testing"
+)
+
+// fakeResolver serves TXT records from a map
+type fakeResolver struct {
+	records map[string][]string
+}
+
+func (f *fakeResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	records, ok := f.records[name]
+	if !ok {
+		return nil, fmt.Errorf("no such host")
+	}
+	return records, nil
+}
+
+func TestDomainService_CheckTXT(t *testing.T) {
+	resolver := &fakeResolver{records: map[string][]string{
+		"_comply360-verification.verified.example.com": {"v=spf1 -all", "comply360-verification=abc123"},
+		"_comply360-verification.wrong.example.com":    {"comply360-verification=other"},
+	}}
+	service := NewDomainService(nil, resolver, nil)
+	ctx := context.Background()
+
+	testhelpers.AssertNoError(t, service.checkTXT(ctx, "verified.example.com", "abc123"), "Matching TXT record should verify")
+	testhelpers.AssertError(t, service.checkTXT(ctx, "wrong.example.com", "abc123"), "Mismatched token should fail")
+	testhelpers.AssertError(t, service.checkTXT(ctx, "missing.example.com", "abc123"), "Missing record should fail")
+}
+
+func TestNormalizeDomain(t *testing.T) {
+	testhelpers.AssertEqual(t, "portal.example.co.za", normalizeDomain(" Portal.Example.co.za. "))
+	testhelpers.AssertEqual(t, "example.com", normalizeDomain("example.com"))
+}
+
+func TestDomainPattern(t *testing.T) {
+	valid := []string{"example.com", "portal.example.co.za", "a-b.example.org"}
+	invalid := []string{"localhost", "-bad.example.com", "bad-.example.com", "exa mple.com", "http://example.com", "example.c0m"}
+
+	for _, domain := range valid {
+		testhelpers.AssertTrue(t, domainPattern.MatchString(domain), domain+" should be valid")
+	}
+	for _, domain := range invalid {
+		testhelpers.AssertFalse(t, domainPattern.MatchString(domain), domain+" should be invalid")
+	}
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/comply360/shared/tenancy"
+	"github.com/google/uuid"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// EventPublisher publishes tenant change events so other services can drop cached tenants
+type EventPublisher struct {
+	ch *amqp.Channel
+}
+
+func NewEventPublisher(rabbitConn *amqp.Connection) (*EventPublisher, error) {
+	ch, err := rabbitConn.Channel()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open channel: %w", err)
+	}
+
+	if err := tenancy.DeclareExchange(ch); err != nil {
+		return nil, fmt.Errorf("failed to declare exchange: %w", err)
+	}
+
+	return &EventPublisher{ch: ch}, nil
+}
+
+// Publish publishes a tenant event. A nil publisher is a no-op, and failures are
+// logged rather than returned: caches still expire on their own TTL.
+func (p *EventPublisher) Publish(eventType string, tenantID uuid.UUID) {
+	p.publish(tenancy.Event{TenantID: tenantID, Type: eventType})
+}
+
+// PublishSettingsChanged announces changed tenant settings to settings clients
+func (p *EventPublisher) PublishSettingsChanged(tenantID uuid.UUID, keys []string) {
+	p.publish(tenancy.Event{TenantID: tenantID, Type: tenancy.EventTenantSettingsChanged, Keys: keys})
+}
+
+// PublishDomainChanged announces a change to a tenant's custom domain, so
+// resolvers stop treating a newly verified domain as unknown
+func (p *EventPublisher) PublishDomainChanged(tenantID uuid.UUID, domain string) {
+	p.publish(tenancy.Event{TenantID: tenantID, Type: tenancy.EventTenantDomainChanged, Domain: domain})
+}
+
+func (p *EventPublisher) publish(event tenancy.Event) {
+	if p == nil {
+		return
+	}
+
+	event.OccurredAt = time.Now()
+	body, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Warning: Failed to marshal %s event: %v", event.Type, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err = p.ch.PublishWithContext(
+		ctx,
+		tenancy.EventsExchange, // exchange
+		event.Type,             // routing key
+		false,                  // mandatory
+		false,                  // immediate
+		amqp.Publishing{
+			ContentType: "application/json",
+			Body:        body,
+			Timestamp:   time.Now(),
+		},
+	)
+	if err != nil {
+		log.Printf("Warning: Failed to publish %s event for tenant %s: %v", event.Type, event.TenantID, err)
+	}
+}
+
+// Close closes the publisher channel
+func (p *EventPublisher) Close() error {
+	if p == nil || p.ch == nil {
+		return nil
+	}
+	return p.ch.Close()
+}
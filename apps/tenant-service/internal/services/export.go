@@ -0,0 +1,482 @@
+package services
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/comply360/shared/models"
+	"github.com/comply360/tenant-service/internal/repository"
+	"github.com/google/uuid"
+)
+
+// exportTimeout bounds a single export run
+const exportTimeout = 2 * time.Hour
+
+// exportOmittedTables hold only credentials and one-time tokens, so they are never exported
+var exportOmittedTables = map[string]bool{
+	"password_reset_tokens":     true,
+	"email_verification_tokens": true,
+}
+
+// exportOmittedColumns are secret columns stripped from otherwise exported tables
+var exportOmittedColumns = map[string][]string{
+	"users":          {"password_hash", "mfa_secret"},
+	"oauth_accounts": {"access_token", "refresh_token"},
+}
+
+// ExportService runs asynchronous full data exports of tenants
+type ExportService struct {
+	repo      *repository.TenantRepository
+	store     ExportStore
+	retention time.Duration
+	linkTTL   time.Duration
+}
+
+func NewExportService(repo *repository.TenantRepository, store ExportStore, retention, linkTTL time.Duration) *ExportService {
+	return &ExportService{
+		repo:      repo,
+		store:     store,
+		retention: retention,
+		linkTTL:   linkTTL,
+	}
+}
+
+// RequestExport queues a full data export of a tenant and starts it in the background
+func (s *ExportService) RequestExport(tenantID uuid.UUID, format string, requestedBy *uuid.UUID) (*models.TenantExport, error) {
+	if format == "" {
+		format = models.ExportFormatJSONL
+	}
+	if format != models.ExportFormatJSONL && format != models.ExportFormatCSV {
+		return nil, fmt.Errorf("invalid export format")
+	}
+
+	tenant, err := s.repo.GetByID(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &models.TenantExport{
+		TenantID:    tenantID,
+		Format:      format,
+		RequestedBy: requestedBy,
+	}
+	if err := s.repo.CreateExport(export); err != nil {
+		return nil, fmt.Errorf("failed to create export: %w", err)
+	}
+
+	go s.runExport(tenant, export.ID, format)
+
+	return export, nil
+}
+
+// GetExport returns an export's progress, with a download link once it has completed
+func (s *ExportService) GetExport(tenantID, exportID uuid.UUID) (*models.TenantExport, error) {
+	record, err := s.repo.GetExport(tenantID, exportID)
+	if err != nil {
+		return nil, err
+	}
+
+	export := s.toExport(record)
+	if export.Status == models.ExportStatusCompleted && record.ObjectKey != nil {
+		link, err := s.downloadURL(record)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create download link: %w", err)
+		}
+		export.DownloadURL = link
+	}
+
+	return export, nil
+}
+
+// ListExports lists a tenant's exports without download links
+func (s *ExportService) ListExports(tenantID uuid.UUID) ([]*models.TenantExport, error) {
+	records, err := s.repo.ListExports(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	exports := make([]*models.TenantExport, 0, len(records))
+	for _, record := range records {
+		export := s.toExport(record)
+		// The manifest can be large; it is only returned for a single export
+		export.Manifest = nil
+		exports = append(exports, export)
+	}
+
+	return exports, nil
+}
+
+// RecoverInterrupted fails exports that were in flight when the service last stopped
+func (s *ExportService) RecoverInterrupted() {
+	count, err := s.repo.FailInterruptedExports()
+	if err != nil {
+		log.Printf("Warning: Failed to recover interrupted exports: %v", err)
+		return
+	}
+	if count > 0 {
+		log.Printf("Marked %d interrupted export(s) as failed", count)
+	}
+}
+
+// ExpireExports removes archives whose download window has closed
+func (s *ExportService) ExpireExports() (int, error) {
+	records, err := s.repo.ListExpiredExports()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list expired exports: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	expired := 0
+	for _, record := range records {
+		if record.ObjectKey != nil {
+			if err := s.store.RemoveObject(ctx, *record.ObjectKey); err != nil {
+				log.Printf("Warning: Failed to remove export %s: %v", record.ID, err)
+				continue
+			}
+		}
+		if err := s.repo.ExpireExport(record.ID); err != nil {
+			log.Printf("Warning: Failed to expire export %s: %v", record.ID, err)
+			continue
+		}
+		expired++
+	}
+
+	return expired, nil
+}
+
+// runExport builds the archive, uploads it and records the result
+func (s *ExportService) runExport(tenant *models.Tenant, exportID uuid.UUID, format string) {
+	ctx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+	defer cancel()
+
+	if err := s.export(ctx, tenant, exportID, format); err != nil {
+		log.Printf("Export %s of tenant %s failed: %v", exportID, tenant.ID, err)
+		if err := s.repo.FailExport(exportID, err.Error()); err != nil {
+			log.Printf("Warning: Failed to record export failure: %v", err)
+		}
+	}
+}
+
+func (s *ExportService) export(ctx context.Context, tenant *models.Tenant, exportID uuid.UUID, format string) error {
+	if err := s.repo.StartExport(exportID); err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp("", "tenant-export-*.zip")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	// One extra item covers the upload
+	itemsTotal := 0
+	checksum := sha256.New()
+	manifest, err := s.writeArchive(ctx, tenant, format, io.MultiWriter(file, checksum), func(step string, done, total int) error {
+		itemsTotal = total + 1
+		return s.repo.UpdateExportProgress(exportID, step, done, itemsTotal)
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.UpdateExportProgress(exportID, "upload", itemsTotal-1, itemsTotal); err != nil {
+		return err
+	}
+
+	size, err := file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	objectKey := exportObjectKey(tenant.ID, exportID)
+	if err := s.store.PutObject(ctx, objectKey, file, size, "application/zip"); err != nil {
+		return fmt.Errorf("failed to upload archive: %w", err)
+	}
+
+	expiresAt := time.Now().Add(s.retention)
+	return s.repo.CompleteExport(exportID, objectKey, hex.EncodeToString(checksum.Sum(nil)), size, manifest, expiresAt)
+}
+
+// archiveProgress is called before each table and document is written
+type archiveProgress func(step string, done, total int) error
+
+// writeArchive writes a tenant's tables, documents and manifest as a ZIP to w
+func (s *ExportService) writeArchive(ctx context.Context, tenant *models.Tenant, format string, w io.Writer, progress archiveProgress) (*models.TenantExportManifest, error) {
+	schemaName := tenant.TenantSchema()
+
+	tables, err := s.repo.ListExportTables(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+	documents, err := s.repo.ListDocumentPaths(tenant.ID, schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list documents: %w", err)
+	}
+
+	total := len(tables) + len(documents)
+	archive := newExportArchive(w)
+
+	manifest := &models.TenantExportManifest{
+		TenantID:       tenant.ID,
+		TenantName:     tenant.Name,
+		SchemaName:     schemaName,
+		Format:         format,
+		GeneratedAt:    time.Now().UTC(),
+		OmittedColumns: map[string][]string{},
+	}
+
+	done := 0
+	for _, table := range tables {
+		done++
+		if exportOmittedTables[table.Name] {
+			manifest.OmittedTables = append(manifest.OmittedTables, table.Name)
+			continue
+		}
+		if err := progress("table:"+table.Name, done-1, total); err != nil {
+			return nil, err
+		}
+
+		columns, omitted := exportableColumns(table.Name, table.Columns)
+		if len(omitted) > 0 {
+			manifest.OmittedColumns[table.Name] = omitted
+		}
+
+		entry, err := archive.addFile(exportTablePath(table.Name, format), table.Name, func(w io.Writer) (int, error) {
+			return s.writeTable(w, tenant.ID, schemaName, table.Name, columns, format)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to export table %s: %w", table.Name, err)
+		}
+		manifest.Tables = append(manifest.Tables, entry)
+	}
+
+	for _, path := range documents {
+		if err := progress("document:"+path, done, total); err != nil {
+			return nil, err
+		}
+		done++
+
+		object, err := s.store.GetObject(ctx, path)
+		if err != nil {
+			manifest.MissingDocuments = append(manifest.MissingDocuments, path)
+			continue
+		}
+		entry, err := archive.addFile(exportDocumentPath(path), path, func(w io.Writer) (int, error) {
+			_, err := io.Copy(w, object)
+			return 0, err
+		})
+		object.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to export document %s: %w", path, err)
+		}
+		manifest.Documents = append(manifest.Documents, entry)
+	}
+
+	if err := archive.addManifest(manifest); err != nil {
+		return nil, err
+	}
+	if err := archive.close(); err != nil {
+		return nil, fmt.Errorf("failed to finish archive: %w", err)
+	}
+
+	return manifest, nil
+}
+
+// writeTable writes one table in the export format and returns its row count
+func (s *ExportService) writeTable(w io.Writer, tenantID uuid.UUID, schemaName, table string, columns []string, format string) (int, error) {
+	rows := 0
+
+	if format == models.ExportFormatJSONL {
+		err := s.repo.StreamTableRows(tenantID, schemaName, table, columns, true, func(values []sql.NullString) error {
+			rows++
+			_, err := io.WriteString(w, values[0].String+"\n")
+			return err
+		})
+		return rows, err
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(columns); err != nil {
+		return 0, err
+	}
+
+	record := make([]string, len(columns))
+	err := s.repo.StreamTableRows(tenantID, schemaName, table, columns, false, func(values []sql.NullString) error {
+		rows++
+		for i, value := range values {
+			record[i] = value.String
+		}
+		return writer.Write(record)
+	})
+	if err != nil {
+		return rows, err
+	}
+
+	writer.Flush()
+	return rows, writer.Error()
+}
+
+// downloadURL presigns a link to an export archive, never outliving the archive itself
+func (s *ExportService) downloadURL(record *repository.ExportRecord) (*string, error) {
+	ttl := s.linkTTL
+	if record.ExpiresAt != nil {
+		if remaining := time.Until(*record.ExpiresAt); remaining < ttl {
+			ttl = remaining
+		}
+	}
+	if ttl < time.Second {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	fileName := fmt.Sprintf("comply360-export-%s.zip", record.ID)
+	link, err := s.store.PresignedGetURL(ctx, *record.ObjectKey, ttl, fileName)
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// toExport builds the API view of an export record
+func (s *ExportService) toExport(record *repository.ExportRecord) *models.TenantExport {
+	export := record.TenantExport
+	finished := export.Status == models.ExportStatusCompleted || export.Status == models.ExportStatusExpired
+	export.Progress = jobProgress(finished, export.ItemsDone, export.ItemsTotal)
+	return &export
+}
+
+// jobProgress returns completion of an export or import as a percentage
+func jobProgress(finished bool, done, total int) int {
+	if finished {
+		return 100
+	}
+	if total <= 0 {
+		return 0
+	}
+	if done >= total {
+		return 99
+	}
+	return done * 100 / total
+}
+
+// exportableColumns drops secret columns from a table, returning the kept and omitted columns
+func exportableColumns(table string, columns []string) ([]string, []string) {
+	secrets := exportOmittedColumns[table]
+	if len(secrets) == 0 {
+		return columns, nil
+	}
+
+	var kept, omitted []string
+	for _, column := range columns {
+		secret := false
+		for _, s := range secrets {
+			if column == s {
+				secret = true
+				break
+			}
+		}
+		if secret {
+			omitted = append(omitted, column)
+		} else {
+			kept = append(kept, column)
+		}
+	}
+	return kept, omitted
+}
+
+// exportTablePath is the archive path of a table's data file
+func exportTablePath(table, format string) string {
+	return "data/" + table + "." + format
+}
+
+// exportDocumentPath is the archive path of a stored document
+func exportDocumentPath(storagePath string) string {
+	return "documents/" + strings.TrimPrefix(storagePath, "/")
+}
+
+// exportManifestPath is the archive path of the manifest
+const exportManifestPath = "manifest.json"
+
+// exportObjectKey is where an export archive is stored. It sits under the tenant prefix
+// so the archive is removed along with the tenant's documents on purge.
+func exportObjectKey(tenantID, exportID uuid.UUID) string {
+	return fmt.Sprintf("tenants/%s/exports/%s.zip", tenantID, exportID)
+}
+
+// exportArchive writes files into a ZIP, checksumming each one for the manifest
+type exportArchive struct {
+	zip *zip.Writer
+}
+
+func newExportArchive(w io.Writer) *exportArchive {
+	return &exportArchive{zip: zip.NewWriter(w)}
+}
+
+// addFile writes a file produced by fn and returns its manifest entry.
+// fn returns the number of rows written, or 0 for non-tabular files.
+func (a *exportArchive) addFile(path, source string, fn func(w io.Writer) (int, error)) (models.TenantExportFile, error) {
+	entry := models.TenantExportFile{Path: path, Source: source}
+
+	w, err := a.zip.Create(path)
+	if err != nil {
+		return entry, err
+	}
+
+	counter := &hashingWriter{hash: sha256.New()}
+	rows, err := fn(io.MultiWriter(w, counter))
+	if err != nil {
+		return entry, err
+	}
+
+	entry.Rows = rows
+	entry.Bytes = counter.bytes
+	entry.SHA256 = hex.EncodeToString(counter.hash.Sum(nil))
+	return entry, nil
+}
+
+// addManifest writes manifest.json at the root of the archive
+func (a *exportArchive) addManifest(manifest *models.TenantExportManifest) error {
+	w, err := a.zip.Create(exportManifestPath)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(manifest)
+}
+
+func (a *exportArchive) close() error {
+	return a.zip.Close()
+}
+
+// hashingWriter hashes and counts the bytes written through it
+type hashingWriter struct {
+	hash  hash.Hash
+	bytes int64
+}
+
+func (h *hashingWriter) Write(p []byte) (int, error) {
+	h.bytes += int64(len(p))
+	return h.hash.Write(p)
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/comply360/shared/models"
+	testhelpers "github.com/comply360/shared/testing"
+	"github.com/google/uuid"
+)
+
+func TestExportableColumns(t *testing.T) {
+	kept, omitted := exportableColumns("users", []string{"id", "email", "password_hash", "mfa_enabled", "mfa_secret"})
+	testhelpers.AssertEqual(t, "id,email,mfa_enabled", strings.Join(kept, ","))
+	testhelpers.AssertEqual(t, "password_hash,mfa_secret", strings.Join(omitted, ","))
+
+	kept, omitted = exportableColumns("clients", []string{"id", "email"})
+	testhelpers.AssertEqual(t, "id,email", strings.Join(kept, ","))
+	testhelpers.AssertEqual(t, 0, len(omitted))
+}
+
+func TestJobProgress(t *testing.T) {
+	testhelpers.AssertEqual(t, 0, jobProgress(false, 0, 0))
+	testhelpers.AssertEqual(t, 50, jobProgress(false, 5, 10))
+	testhelpers.AssertEqual(t, 99, jobProgress(false, 10, 10), "Unfinished job should not report 100")
+	testhelpers.AssertEqual(t, 100, jobProgress(true, 10, 10))
+}
+
+func TestExportObjectKey_UnderTenantPrefix(t *testing.T) {
+	tenantID := uuid.New()
+	key := exportObjectKey(tenantID, uuid.New())
+
+	// Archives must be removed together with the tenant's documents on purge
+	testhelpers.AssertTrue(t, strings.HasPrefix(key, tenantObjectPrefix(tenantID)), "Export key should sit under the tenant prefix")
+}
+
+func TestExportArchive_ChecksumsMatchContents(t *testing.T) {
+	var buf bytes.Buffer
+	archive := newExportArchive(&buf)
+
+	entry, err := archive.addFile("data/clients.jsonl", "clients", func(w io.Writer) (int, error) {
+		_, err := io.WriteString(w, "{\"id\":1}\n{\"id\":2}\n")
+		return 2, err
+	})
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 2, entry.Rows)
+	testhelpers.AssertEqual(t, int64(18), entry.Bytes)
+
+	manifest := &models.TenantExportManifest{Tables: []models.TenantExportFile{entry}}
+	testhelpers.AssertNoError(t, archive.addManifest(manifest))
+	testhelpers.AssertNoError(t, archive.close())
+
+	reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	testhelpers.AssertNoError(t, err)
+
+	files := map[string][]byte{}
+	for _, file := range reader.File {
+		rc, err := file.Open()
+		testhelpers.AssertNoError(t, err)
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		testhelpers.AssertNoError(t, err)
+		files[file.Name] = content
+	}
+
+	var stored models.TenantExportManifest
+	testhelpers.AssertNoError(t, json.Unmarshal(files["manifest.json"], &stored))
+	testhelpers.AssertEqual(t, 1, len(stored.Tables))
+
+	sum := sha256.Sum256(files["data/clients.jsonl"])
+	testhelpers.AssertEqual(t, hex.EncodeToString(sum[:]), stored.Tables[0].SHA256)
+}
@@ -0,0 +1,223 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/comply360/shared/models"
+	"github.com/comply360/shared/tenancy"
+	"github.com/comply360/tenant-service/internal/repository"
+	"github.com/google/uuid"
+)
+
+// HierarchyService manages master agent and sub-agent tenant relationships, and
+// the roll-up reports a parent sees of its descendants
+type HierarchyService struct {
+	repo   *repository.TenantRepository
+	events *EventPublisher
+}
+
+func NewHierarchyService(repo *repository.TenantRepository, events *EventPublisher) *HierarchyService {
+	return &HierarchyService{
+		repo:   repo,
+		events: events,
+	}
+}
+
+// SetParent makes a tenant a sub-agent of another. overrideRate is the percentage
+// of each registration fee the parent earns on the child's commissions.
+func (s *HierarchyService) SetParent(tenantID, parentID uuid.UUID, overrideRate *float64) (*models.Tenant, error) {
+	if overrideRate != nil && (*overrideRate < 0 || *overrideRate > 100) {
+		return nil, fmt.Errorf("override rate must be between 0 and 100")
+	}
+
+	if _, err := s.repo.GetByID(tenantID); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetByID(parentID); err != nil {
+		return nil, fmt.Errorf("parent tenant not found")
+	}
+
+	parentAncestors, err := s.repo.ListAncestorIDs(parentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tenant hierarchy: %w", err)
+	}
+	if createsCycle(tenantID, parentID, parentAncestors) {
+		return nil, fmt.Errorf("tenant hierarchy cycle")
+	}
+	if len(parentAncestors)+1 >= tenancy.MaxHierarchyDepth {
+		return nil, fmt.Errorf("tenant hierarchy too deep")
+	}
+
+	if err := s.repo.SetParent(tenantID, parentID, overrideRate); err != nil {
+		return nil, err
+	}
+
+	s.hierarchyChanged(tenantID)
+
+	return s.repo.GetByID(tenantID)
+}
+
+// RemoveParent makes a sub-agent tenant top-level again
+func (s *HierarchyService) RemoveParent(tenantID uuid.UUID) (*models.Tenant, error) {
+	if err := s.repo.ClearParent(tenantID); err != nil {
+		return nil, err
+	}
+
+	s.hierarchyChanged(tenantID)
+
+	return s.repo.GetByID(tenantID)
+}
+
+// ListChildren lists a tenant's sub-agents; with recursive, all of its descendants
+func (s *HierarchyService) ListChildren(tenantID uuid.UUID, recursive bool) ([]models.Tenant, error) {
+	if _, err := s.repo.GetByID(tenantID); err != nil {
+		return nil, err
+	}
+
+	descendants, err := s.repo.ListDescendants(tenantID, !recursive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list child tenants: %w", err)
+	}
+
+	children := make([]models.Tenant, 0, len(descendants))
+	for _, descendant := range descendants {
+		children = append(children, *descendant.Tenant)
+	}
+	return children, nil
+}
+
+// Rollup aggregates the registrations and commissions of every descendant of a
+// tenant, together with the overrides the tenant earned on them. Each descendant
+// is read with its own tenant context, with the parent as the acting tenant.
+func (s *HierarchyService) Rollup(tenantID uuid.UUID, from, to *time.Time) (*models.TenantHierarchyRollup, error) {
+	if from != nil && to != nil && !from.Before(*to) {
+		return nil, fmt.Errorf("from must be before to")
+	}
+
+	tenant, err := s.repo.GetByID(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	descendants, err := s.repo.ListDescendants(tenantID, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list child tenants: %w", err)
+	}
+
+	rollup := &models.TenantHierarchyRollup{
+		TenantID:      tenantID,
+		From:          from,
+		To:            to,
+		Children:      make([]models.TenantRollupEntry, 0, len(descendants)),
+		Registrations: map[string]int{},
+		Commissions:   map[string]models.RollupAmounts{},
+		Overrides:     map[string]models.RollupAmounts{},
+		GeneratedAt:   time.Now(),
+	}
+
+	for _, descendant := range descendants {
+		entry := s.rollupEntry(tenantID, descendant, from, to)
+		for status, count := range entry.Registrations {
+			rollup.Registrations[status] += count
+		}
+		for currency, amounts := range entry.Commissions {
+			total := rollup.Commissions[currency]
+			total.Merge(amounts)
+			rollup.Commissions[currency] = total
+		}
+		rollup.Children = append(rollup.Children, entry)
+	}
+
+	if provisioned, _ := s.repo.GetProvisionedAt(tenantID); provisioned != nil {
+		overrides, err := s.repo.SumCommissionOverrides(tenancy.Context{TenantID: tenantID}, tenant.TenantSchema(), from, to)
+		if err != nil {
+			log.Printf("Warning: Failed to total commission overrides for tenant %s: %v", tenantID, err)
+		}
+		rollup.Overrides = commissionAmounts(overrides)
+	}
+
+	return rollup, nil
+}
+
+// rollupEntry totals a single descendant. A descendant that cannot be read is
+// reported as unavailable rather than failing the whole report.
+func (s *HierarchyService) rollupEntry(parentID uuid.UUID, descendant repository.Descendant, from, to *time.Time) models.TenantRollupEntry {
+	child := descendant.Tenant
+	entry := models.TenantRollupEntry{
+		TenantID:      child.ID,
+		Name:          child.Name,
+		Depth:         descendant.Depth,
+		Registrations: map[string]int{},
+		Commissions:   map[string]models.RollupAmounts{},
+	}
+	if child.ParentTenantID != nil {
+		entry.ParentTenantID = *child.ParentTenantID
+	}
+
+	unavailable := func(reason string) models.TenantRollupEntry {
+		entry.Unavailable = &reason
+		return entry
+	}
+
+	provisionedAt, err := s.repo.GetProvisionedAt(child.ID)
+	if err != nil {
+		return unavailable("failed to read tenant")
+	}
+	if provisionedAt == nil {
+		return unavailable("tenant is not provisioned")
+	}
+
+	tc := tenancy.Context{TenantID: child.ID, ActingTenantID: &parentID}
+
+	counts, err := s.repo.CountRegistrations(tc, child.TenantSchema(), from, to)
+	if err != nil {
+		log.Printf("Warning: Roll-up of tenant %s failed: %v", child.ID, err)
+		return unavailable("failed to read registrations")
+	}
+	for _, count := range counts {
+		entry.Registrations[count.Status] = count.Count
+	}
+
+	totals, err := s.repo.SumCommissions(tc, child.TenantSchema(), from, to)
+	if err != nil {
+		log.Printf("Warning: Roll-up of tenant %s failed: %v", child.ID, err)
+		return unavailable("failed to read commissions")
+	}
+	entry.Commissions = commissionAmounts(totals)
+
+	return entry
+}
+
+// hierarchyChanged tells other services a tenant moved in the hierarchy. Settings
+// clients drop the tenant and its descendants, whose inherited values changed.
+func (s *HierarchyService) hierarchyChanged(tenantID uuid.UUID) {
+	s.events.Publish(tenancy.EventTenantHierarchyChanged, tenantID)
+	s.events.PublishSettingsChanged(tenantID, nil)
+}
+
+// createsCycle reports whether making parentID the parent of tenantID would make
+// tenantID its own ancestor. parentAncestors are the ancestors of parentID.
+func createsCycle(tenantID, parentID uuid.UUID, parentAncestors []uuid.UUID) bool {
+	if tenantID == parentID {
+		return true
+	}
+	for _, ancestor := range parentAncestors {
+		if ancestor == tenantID {
+			return true
+		}
+	}
+	return false
+}
+
+// commissionAmounts groups commission totals by currency
+func commissionAmounts(totals []repository.CommissionTotal) map[string]models.RollupAmounts {
+	amounts := map[string]models.RollupAmounts{}
+	for _, total := range totals {
+		a := amounts[total.Currency]
+		a.Add(total.Status, total.Count, total.Amount)
+		amounts[total.Currency] = a
+	}
+	return amounts
+}
@@ -0,0 +1,38 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/comply360/shared/models"
+	testhelpers "github.com/comply360/shared/testing"
+	"github.com/comply360/tenant-service/internal/repository"
+	"github.com/google/uuid"
+)
+
+func TestCreatesCycle(t *testing.T) {
+	tenant, parent, grandparent := uuid.New(), uuid.New(), uuid.New()
+
+	testhelpers.AssertFalse(t, createsCycle(tenant, parent, []uuid.UUID{grandparent}), "A new parent outside the subtree is allowed")
+	testhelpers.AssertTrue(t, createsCycle(tenant, tenant, nil), "A tenant cannot be its own parent")
+	testhelpers.AssertTrue(t, createsCycle(tenant, parent, []uuid.UUID{grandparent, tenant}), "A tenant cannot move below its own descendant")
+}
+
+func TestCommissionAmounts_GroupsByCurrencyAndStatus(t *testing.T) {
+	amounts := commissionAmounts([]repository.CommissionTotal{
+		{Currency: models.CurrencyZAR, Status: models.CommissionStatusPending, Count: 2, Amount: 150},
+		{Currency: models.CurrencyZAR, Status: models.CommissionStatusPaid, Count: 1, Amount: 100},
+		{Currency: models.CurrencyZAR, Status: models.CommissionStatusCancelled, Count: 1, Amount: 40},
+		{Currency: models.CurrencyUSD, Status: models.CommissionStatusApproved, Count: 1, Amount: 25},
+	})
+
+	zar := amounts[models.CurrencyZAR]
+	testhelpers.AssertEqual(t, 4, zar.Count)
+	testhelpers.AssertEqual(t, 250.0, zar.Total, "Cancelled commissions should not count towards the total")
+	testhelpers.AssertEqual(t, 150.0, zar.Pending)
+	testhelpers.AssertEqual(t, 100.0, zar.Paid)
+	testhelpers.AssertEqual(t, 40.0, zar.Cancelled)
+
+	usd := amounts[models.CurrencyUSD]
+	testhelpers.AssertEqual(t, 25.0, usd.Approved)
+	testhelpers.AssertEqual(t, 25.0, usd.Total)
+}
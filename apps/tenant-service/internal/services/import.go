@@ -0,0 +1,445 @@
+package services
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/comply360/shared/models"
+	"github.com/comply360/shared/settings"
+	"github.com/comply360/tenant-service/internal/repository"
+	"github.com/google/uuid"
+)
+
+// importTimeout bounds a single import or clone run
+const importTimeout = 2 * time.Hour
+
+// importSeededTables are populated by provisioning and replaced wholesale by an import
+var importSeededTables = map[string]bool{
+	"tenant_settings": true,
+	"user_roles":      true,
+	"users":           true,
+}
+
+// importValidationFailed is the job error when validation errors stopped an import
+const importValidationFailed = "import validation failed"
+
+// ImportService restores export archives into freshly provisioned tenants and clones tenants
+type ImportService struct {
+	repo    *repository.TenantRepository
+	tenants *TenantService
+	exports *ExportService
+	store   ExportStore
+	keyring *settings.Keyring
+}
+
+func NewImportService(repo *repository.TenantRepository, tenants *TenantService, exports *ExportService, store ExportStore, keyring *settings.Keyring) *ImportService {
+	return &ImportService{
+		repo:    repo,
+		tenants: tenants,
+		exports: exports,
+		store:   store,
+		keyring: keyring,
+	}
+}
+
+// ImportArchive stores an uploaded export archive and imports it into a provisioned tenant in the background
+func (s *ImportService) ImportArchive(tenantID uuid.UUID, archive io.Reader, size int64, remapIDs bool, requestedBy *uuid.UUID) (*models.TenantImport, error) {
+	tenant, err := s.repo.GetByID(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.requireProvisioned(tenantID); err != nil {
+		return nil, err
+	}
+
+	record := &repository.ImportRecord{TenantImport: models.TenantImport{
+		TenantID:    tenantID,
+		Source:      models.ImportSourceArchive,
+		RemapIDs:    remapIDs,
+		RequestedBy: requestedBy,
+	}}
+	if err := s.repo.CreateImport(record); err != nil {
+		return nil, fmt.Errorf("failed to create import: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	objectKey := importObjectKey(tenantID, record.ID)
+	if err := s.store.PutObject(ctx, objectKey, archive, size, "application/zip"); err != nil {
+		s.repo.FailImport(record.ID, "failed to store archive", nil)
+		return nil, fmt.Errorf("failed to store archive: %w", err)
+	}
+	if err := s.repo.SetImportObjectKey(record.ID, objectKey); err != nil {
+		return nil, err
+	}
+	record.ObjectKey = &objectKey
+
+	go s.runImport(tenant, record)
+
+	return s.toImport(record), nil
+}
+
+// CloneTenant creates and provisions a new tenant holding a copy of another tenant's data.
+// Personal data is anonymised unless the request turns it off.
+func (s *ImportService) CloneTenant(sourceID uuid.UUID, req *models.CloneTenantRequest, requestedBy *uuid.UUID) (*models.Tenant, *models.TenantImport, error) {
+	source, err := s.repo.GetByID(sourceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := s.requireProvisioned(sourceID); err != nil {
+		return nil, nil, err
+	}
+
+	anonymize := true
+	if req.Anonymize != nil {
+		anonymize = *req.Anonymize
+	}
+
+	create, err := cloneTenantRequest(source, req, anonymize)
+	if err != nil {
+		return nil, nil, err
+	}
+	clone, err := s.tenants.CreateTenant(create)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Clones always get fresh IDs so rows can never be confused with the source tenant's
+	record := &repository.ImportRecord{TenantImport: models.TenantImport{
+		TenantID:       clone.ID,
+		Source:         models.ImportSourceClone,
+		SourceTenantID: &sourceID,
+		RemapIDs:       true,
+		Anonymize:      anonymize,
+		RequestedBy:    requestedBy,
+	}}
+	if err := s.repo.CreateImport(record); err != nil {
+		return nil, nil, fmt.Errorf("failed to create import: %w", err)
+	}
+
+	go s.runClone(source, clone, record)
+
+	return clone, s.toImport(record), nil
+}
+
+// cloneTenantRequest builds the request creating a clone. Company and contact details
+// not given in the clone request are the source tenant's, anonymised when the copy is,
+// so that the clone's admin user and mail never reach the real agency.
+func cloneTenantRequest(source *models.Tenant, req *models.CloneTenantRequest, anonymize bool) (*models.CreateTenantRequest, error) {
+	anon, err := newAnonymizer()
+	if err != nil {
+		return nil, err
+	}
+	detail := func(given string, sourceValue *string, kind string) string {
+		switch {
+		case given != "":
+			return given
+		case sourceValue == nil || *sourceValue == "":
+			return ""
+		case anonymize:
+			return anon.replace(kind, *sourceValue)
+		default:
+			return *sourceValue
+		}
+	}
+
+	create := &models.CreateTenantRequest{
+		Name:             req.Name,
+		Subdomain:        req.Subdomain,
+		CompanyName:      detail(req.CompanyName, source.CompanyName, piiCompany),
+		ContactEmail:     detail(req.ContactEmail, source.ContactEmail, piiEmail),
+		ContactPhone:     detail(req.ContactPhone, source.ContactPhone, piiPhone),
+		SubscriptionTier: source.SubscriptionTier,
+	}
+	if source.Country != nil {
+		create.Country = *source.Country
+	}
+	return create, nil
+}
+
+// GetImport returns an import's progress and result
+func (s *ImportService) GetImport(tenantID, importID uuid.UUID) (*models.TenantImport, error) {
+	record, err := s.repo.GetImport(tenantID, importID)
+	if err != nil {
+		return nil, err
+	}
+	return s.toImport(record), nil
+}
+
+// RecoverInterrupted fails imports that were in flight when the service last stopped
+func (s *ImportService) RecoverInterrupted() {
+	count, err := s.repo.FailInterruptedImports()
+	if err != nil {
+		log.Printf("Warning: Failed to recover interrupted imports: %v", err)
+		return
+	}
+	if count > 0 {
+		log.Printf("Marked %d interrupted import(s) as failed", count)
+	}
+}
+
+// runImport restores an uploaded archive and removes it afterwards
+func (s *ImportService) runImport(tenant *models.Tenant, record *repository.ImportRecord) {
+	ctx, cancel := context.WithTimeout(context.Background(), importTimeout)
+	defer cancel()
+
+	defer func() {
+		if err := s.store.RemoveObject(ctx, *record.ObjectKey); err != nil {
+			log.Printf("Warning: Failed to remove import archive %s: %v", *record.ObjectKey, err)
+		}
+	}()
+
+	s.finish(record, func(progress archiveProgress) (*models.TenantImportStats, []models.TenantImportError, error) {
+		if err := progress("download", 0, importStages); err != nil {
+			return nil, nil, err
+		}
+
+		object, err := s.store.GetObject(ctx, *record.ObjectKey)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read archive: %w", err)
+		}
+		defer object.Close()
+
+		file, err := os.CreateTemp("", "tenant-import-*.zip")
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create temp file: %w", err)
+		}
+		defer os.Remove(file.Name())
+		defer file.Close()
+
+		size, err := io.Copy(file, object)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to download archive: %w", err)
+		}
+
+		reader, err := zip.NewReader(file, size)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid archive: %w", err)
+		}
+
+		return s.restore(ctx, tenant, record, reader, progress, 1)
+	})
+}
+
+// runClone provisions the clone, exports the source into a temp archive and restores it
+func (s *ImportService) runClone(source, clone *models.Tenant, record *repository.ImportRecord) {
+	ctx, cancel := context.WithTimeout(context.Background(), importTimeout)
+	defer cancel()
+
+	s.finish(record, func(progress archiveProgress) (*models.TenantImportStats, []models.TenantImportError, error) {
+		if err := progress("provision", 0, importStages); err != nil {
+			return nil, nil, err
+		}
+		if err := s.tenants.ProvisionTenant(clone.ID); err != nil {
+			return nil, nil, fmt.Errorf("failed to provision clone: %w", err)
+		}
+
+		file, err := os.CreateTemp("", "tenant-clone-*.zip")
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create temp file: %w", err)
+		}
+		defer os.Remove(file.Name())
+		defer file.Close()
+
+		// Export step progress is reported as a single stage
+		_, err = s.exports.writeArchive(ctx, source, models.ExportFormatJSONL, file, func(step string, done, total int) error {
+			return progress("export:"+step, 0, importStages)
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to export source tenant: %w", err)
+		}
+
+		size, err := file.Seek(0, io.SeekEnd)
+		if err != nil {
+			return nil, nil, err
+		}
+		reader, err := zip.NewReader(file, size)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return s.restore(ctx, clone, record, reader, progress, 1)
+	})
+}
+
+// importStages is the number of progress stages: fetch (provision or download), read, validate, documents, restore
+const importStages = 5
+
+// finish runs an import and records its outcome
+func (s *ImportService) finish(record *repository.ImportRecord, run func(progress archiveProgress) (*models.TenantImportStats, []models.TenantImportError, error)) {
+	if err := s.repo.StartImport(record.ID); err != nil {
+		log.Printf("Warning: Failed to start import %s: %v", record.ID, err)
+	}
+
+	stats, problems, err := run(func(step string, done, total int) error {
+		return s.repo.UpdateImportProgress(record.ID, step, done, total)
+	})
+	if err == nil && len(problems) > 0 {
+		err = fmt.Errorf(importValidationFailed)
+	}
+	if err != nil {
+		log.Printf("Import %s into tenant %s failed: %v", record.ID, record.TenantID, err)
+		if err := s.repo.FailImport(record.ID, err.Error(), problems); err != nil {
+			log.Printf("Warning: Failed to record import failure: %v", err)
+		}
+		return
+	}
+
+	if err := s.repo.CompleteImport(record.ID, stats); err != nil {
+		log.Printf("Warning: Failed to record import result: %v", err)
+	}
+}
+
+// restore validates an archive and loads it into the target tenant. Nothing is written
+// to the tenant schema unless every table and reference checks out.
+func (s *ImportService) restore(ctx context.Context, tenant *models.Tenant, record *repository.ImportRecord, reader *zip.Reader, progress archiveProgress, stage int) (*models.TenantImportStats, []models.TenantImportError, error) {
+	schemaName := tenant.TenantSchema()
+
+	if err := progress("read_archive", stage, importStages); err != nil {
+		return nil, nil, err
+	}
+	data, problems, err := readImportArchive(reader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := progress("validate", stage+1, importStages); err != nil {
+		return nil, nil, err
+	}
+	target, err := s.repo.ListExportTables(schemaName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list target tables: %w", err)
+	}
+	keys, err := s.repo.ListForeignKeys(schemaName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list foreign keys: %w", err)
+	}
+
+	problems = append(problems, validateImportColumns(data, target)...)
+	if len(problems) > 0 {
+		return nil, problems, nil
+	}
+
+	if record.Anonymize {
+		anon, err := newAnonymizer()
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, name := range data.names {
+			for _, row := range data.tables[name] {
+				anon.anonymizeRow(row)
+			}
+		}
+	}
+	// Secrets such as SMTP and Odoo credentials would let an anonymised copy act as the
+	// source tenant, so they are left for the copy to configure
+	skippedSettings, err := resealImportSettings(data, s.keyring, data.manifest.TenantID, tenant.ID, record.Anonymize)
+	if err != nil {
+		return nil, nil, err
+	}
+	if record.RemapIDs {
+		remapImportIDs(data)
+	}
+	rewriteImportTenant(data, data.manifest.TenantID, tenant.ID)
+
+	if problems := validateImportReferences(data, keys); len(problems) > 0 {
+		return nil, problems, nil
+	}
+
+	order, err := importOrder(data.names, keys)
+	if err != nil {
+		return nil, nil, err
+	}
+	targetNames := make([]string, len(target))
+	for i, table := range target {
+		targetNames[i] = table.Name
+	}
+	clearOrder, err := importOrder(targetNames, keys)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i, j := 0, len(clearOrder)-1; i < j; i, j = i+1, j-1 {
+		clearOrder[i], clearOrder[j] = clearOrder[j], clearOrder[i]
+	}
+
+	tables, err := buildImportTables(data, order, target)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stats := &models.TenantImportStats{Rows: map[string]int{}, SettingsSkipped: skippedSettings}
+	for _, table := range tables {
+		stats.Rows[table.Name] = len(table.Rows)
+	}
+
+	// Scanned documents are personal data that cannot be anonymised, so anonymised copies leave them out
+	if err := progress("documents", stage+2, importStages); err != nil {
+		return nil, nil, err
+	}
+	if record.Anonymize {
+		stats.DocumentsSkipped = len(data.manifest.Documents)
+	} else {
+		for _, entry := range data.manifest.Documents {
+			if err := s.copyDocument(ctx, data, entry, data.manifest.TenantID, tenant.ID); err != nil {
+				return nil, nil, err
+			}
+			stats.DocumentsImported++
+		}
+	}
+
+	if err := progress("restore", stage+3, importStages); err != nil {
+		return nil, nil, err
+	}
+	if err := s.repo.RestoreTenantData(tenant.ID, schemaName, importSeededTables, clearOrder, tables); err != nil {
+		return nil, nil, err
+	}
+
+	return stats, nil, nil
+}
+
+// copyDocument uploads an archived document to its path under the target tenant
+func (s *ImportService) copyDocument(ctx context.Context, data *importData, entry models.TenantExportFile, sourceID, targetID uuid.UUID) error {
+	file := data.files[entry.Path]
+
+	rc, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("failed to read document %s: %w", entry.Source, err)
+	}
+	defer rc.Close()
+
+	path := rewriteStoragePath(entry.Source, sourceID, targetID)
+	if err := s.store.PutObject(ctx, path, rc, int64(file.UncompressedSize64), "application/octet-stream"); err != nil {
+		return fmt.Errorf("failed to upload document %s: %w", path, err)
+	}
+	return nil
+}
+
+// requireProvisioned rejects tenants whose schema is not ready
+func (s *ImportService) requireProvisioned(tenantID uuid.UUID) error {
+	provisionedAt, err := s.repo.GetProvisionedAt(tenantID)
+	if err != nil {
+		return err
+	}
+	if provisionedAt == nil {
+		return fmt.Errorf("tenant is not provisioned")
+	}
+	return nil
+}
+
+// toImport builds the API view of an import record
+func (s *ImportService) toImport(record *repository.ImportRecord) *models.TenantImport {
+	result := record.TenantImport
+	result.Progress = jobProgress(result.Status == models.ImportStatusCompleted, result.ItemsDone, result.ItemsTotal)
+	return &result
+}
+
+// importObjectKey is where an uploaded import archive waits to be processed
+func importObjectKey(tenantID, importID uuid.UUID) string {
+	return fmt.Sprintf("tenants/%s/imports/%s.zip", tenantID, importID)
+}
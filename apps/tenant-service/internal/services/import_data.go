@@ -0,0 +1,419 @@
+package services
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/comply360/shared/models"
+	"github.com/comply360/shared/settings"
+	"github.com/comply360/tenant-service/internal/repository"
+	"github.com/google/uuid"
+)
+
+// importRow is one row of an exported table, keyed by column
+type importRow = map[string]interface{}
+
+// importData is the verified contents of an export archive
+type importData struct {
+	manifest models.TenantExportManifest
+	// tables holds rows per table; names lists the tables in manifest order
+	tables map[string][]importRow
+	names  []string
+	files  map[string]*zip.File
+}
+
+// readImportArchive reads and checksums every file listed in an archive's manifest.
+// Problems with individual files are returned as validation errors; err is reserved
+// for archives that cannot be read at all.
+func readImportArchive(reader *zip.Reader) (*importData, []models.TenantImportError, error) {
+	data := &importData{
+		tables: map[string][]importRow{},
+		files:  map[string]*zip.File{},
+	}
+	for _, file := range reader.File {
+		data.files[file.Name] = file
+	}
+
+	manifestFile, ok := data.files[exportManifestPath]
+	if !ok {
+		return nil, nil, fmt.Errorf("archive has no manifest")
+	}
+	content, err := readZipFile(manifestFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	if err := json.Unmarshal(content, &data.manifest); err != nil {
+		return nil, nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+	if data.manifest.Format != models.ExportFormatJSONL {
+		return nil, nil, fmt.Errorf("only JSONL exports can be imported")
+	}
+
+	var problems []models.TenantImportError
+	for _, entry := range data.manifest.Tables {
+		file, ok := data.files[entry.Path]
+		if !ok {
+			problems = append(problems, models.TenantImportError{Table: entry.Source, Message: "file missing from archive: " + entry.Path})
+			continue
+		}
+
+		content, err := readZipFile(file)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read %s: %w", entry.Path, err)
+		}
+		if !checksumMatches(content, entry.SHA256) {
+			problems = append(problems, models.TenantImportError{Table: entry.Source, Message: "checksum mismatch for " + entry.Path})
+			continue
+		}
+
+		rows, rowProblems := parseJSONLines(entry.Source, content)
+		problems = append(problems, rowProblems...)
+		data.tables[entry.Source] = rows
+		data.names = append(data.names, entry.Source)
+	}
+
+	for _, entry := range data.manifest.Documents {
+		file, ok := data.files[entry.Path]
+		if !ok {
+			problems = append(problems, models.TenantImportError{Table: "documents", Value: entry.Source, Message: "document missing from archive"})
+			continue
+		}
+
+		sum, err := hashZipFile(file)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read %s: %w", entry.Path, err)
+		}
+		if sum != entry.SHA256 {
+			problems = append(problems, models.TenantImportError{Table: "documents", Value: entry.Source, Message: "document checksum mismatch"})
+		}
+	}
+
+	return data, problems, nil
+}
+
+// parseJSONLines decodes one JSON object per line, keeping numbers exact
+func parseJSONLines(table string, content []byte) ([]importRow, []models.TenantImportError) {
+	var rows []importRow
+	var problems []models.TenantImportError
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(scanner.Bytes()))
+		decoder.UseNumber()
+
+		var row importRow
+		if err := decoder.Decode(&row); err != nil {
+			problems = append(problems, models.TenantImportError{Table: table, Row: line, Message: "invalid JSON: " + err.Error()})
+			continue
+		}
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		problems = append(problems, models.TenantImportError{Table: table, Row: line + 1, Message: err.Error()})
+	}
+
+	return rows, problems
+}
+
+// validateImportColumns checks that every imported table and column exists in the target schema.
+// Generated columns, which archives made before they were left out of exports still hold, are
+// accepted and left for the database to compute.
+func validateImportColumns(data *importData, target []repository.ExportTable) []models.TenantImportError {
+	targetColumns := map[string]map[string]bool{}
+	for _, table := range target {
+		targetColumns[table.Name] = map[string]bool{}
+		for _, column := range append(append([]string{}, table.Columns...), table.Generated...) {
+			targetColumns[table.Name][column] = true
+		}
+	}
+
+	var problems []models.TenantImportError
+	for _, name := range data.names {
+		columns, ok := targetColumns[name]
+		if !ok {
+			problems = append(problems, models.TenantImportError{Table: name, Message: "table does not exist in the target schema"})
+			continue
+		}
+
+		unknown := map[string]bool{}
+		for _, row := range data.tables[name] {
+			for column := range row {
+				if !columns[column] && !unknown[column] {
+					unknown[column] = true
+					problems = append(problems, models.TenantImportError{Table: name, Column: column, Message: "column does not exist in the target schema"})
+				}
+			}
+		}
+	}
+
+	return problems
+}
+
+// remapImportIDs gives every row a new primary key and rewrites every value that
+// held an old key. Keys are UUIDs, so a value equal to an old key is a reference to it.
+func remapImportIDs(data *importData) {
+	mapping := map[string]string{}
+	for _, name := range data.names {
+		for _, row := range data.tables[name] {
+			if id, ok := row["id"].(string); ok {
+				if _, err := uuid.Parse(id); err == nil {
+					mapping[id] = uuid.New().String()
+				}
+			}
+		}
+	}
+
+	for _, name := range data.names {
+		for _, row := range data.tables[name] {
+			for column, value := range row {
+				if s, ok := value.(string); ok {
+					if mapped, ok := mapping[s]; ok {
+						row[column] = mapped
+					}
+				}
+			}
+		}
+	}
+}
+
+// rewriteImportTenant moves rows and document paths from the source tenant to the target tenant
+func rewriteImportTenant(data *importData, sourceID, targetID uuid.UUID) {
+	for _, name := range data.names {
+		for _, row := range data.tables[name] {
+			if _, ok := row["tenant_id"]; ok {
+				row["tenant_id"] = targetID.String()
+			}
+			if path, ok := row["storage_path"].(string); ok {
+				row["storage_path"] = rewriteStoragePath(path, sourceID, targetID)
+			}
+		}
+	}
+}
+
+// rewriteStoragePath moves an object path from one tenant prefix to another
+func rewriteStoragePath(path string, sourceID, targetID uuid.UUID) string {
+	if strings.HasPrefix(path, tenantObjectPrefix(sourceID)) {
+		return tenantObjectPrefix(targetID) + strings.TrimPrefix(path, tenantObjectPrefix(sourceID))
+	}
+	return path
+}
+
+// resealImportSettings re-encrypts encrypted settings for the target tenant. Sealed
+// values are bound to their tenant, so copied as they are they would not open. Values
+// that cannot be opened, such as those sealed with another deployment's keys, are left
+// out, as is every encrypted value when drop is set; the keys left out are returned.
+func resealImportSettings(data *importData, keyring *settings.Keyring, sourceID, targetID uuid.UUID, drop bool) ([]string, error) {
+	rows, ok := data.tables["tenant_settings"]
+	if !ok {
+		return nil, nil
+	}
+
+	var kept []importRow
+	var skipped []string
+	for _, row := range rows {
+		key, _ := row["key"].(string)
+		sealed, isString := row["value"].(string)
+		if encrypted, _ := row["is_encrypted"].(bool); !encrypted || !isString {
+			kept = append(kept, row)
+			continue
+		}
+		if drop {
+			skipped = append(skipped, key)
+			continue
+		}
+
+		plaintext, err := keyring.Open(sourceID, key, sealed)
+		if err != nil {
+			skipped = append(skipped, key)
+			continue
+		}
+		if row["value"], err = keyring.Seal(targetID, key, plaintext); err != nil {
+			return nil, fmt.Errorf("failed to encrypt setting %s: %w", key, err)
+		}
+		kept = append(kept, row)
+	}
+	data.tables["tenant_settings"] = kept
+
+	sort.Strings(skipped)
+	return skipped, nil
+}
+
+// validateImportReferences checks every foreign key value points at an imported row
+func validateImportReferences(data *importData, keys []repository.ForeignKey) []models.TenantImportError {
+	present := map[string]map[string]bool{}
+	targets := func(table, column string) map[string]bool {
+		key := table + "." + column
+		if set, ok := present[key]; ok {
+			return set
+		}
+		set := map[string]bool{}
+		for _, row := range data.tables[table] {
+			if value := row[column]; value != nil {
+				set[fmt.Sprint(value)] = true
+			}
+		}
+		present[key] = set
+		return set
+	}
+
+	var problems []models.TenantImportError
+	for _, key := range keys {
+		rows, ok := data.tables[key.Table]
+		if !ok {
+			continue
+		}
+
+		set := targets(key.RefTable, key.RefColumn)
+		for i, row := range rows {
+			value := row[key.Column]
+			if value == nil {
+				continue
+			}
+			if !set[fmt.Sprint(value)] {
+				problems = append(problems, models.TenantImportError{
+					Table:   key.Table,
+					Row:     i + 1,
+					Column:  key.Column,
+					Value:   fmt.Sprint(value),
+					Message: fmt.Sprintf("references missing %s.%s", key.RefTable, key.RefColumn),
+				})
+			}
+		}
+	}
+
+	return problems
+}
+
+// importOrder sorts tables so that referenced tables come before the tables referencing them
+func importOrder(tables []string, keys []repository.ForeignKey) ([]string, error) {
+	included := map[string]bool{}
+	for _, table := range tables {
+		included[table] = true
+	}
+
+	dependsOn := map[string]map[string]bool{}
+	for _, key := range keys {
+		if key.Table == key.RefTable || !included[key.Table] || !included[key.RefTable] {
+			continue
+		}
+		if dependsOn[key.Table] == nil {
+			dependsOn[key.Table] = map[string]bool{}
+		}
+		dependsOn[key.Table][key.RefTable] = true
+	}
+
+	sorted := append([]string(nil), tables...)
+	sort.Strings(sorted)
+
+	var order []string
+	placed := map[string]bool{}
+	for len(order) < len(sorted) {
+		progressed := false
+		for _, table := range sorted {
+			if placed[table] {
+				continue
+			}
+			ready := true
+			for parent := range dependsOn[table] {
+				if !placed[parent] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				order = append(order, table)
+				placed[table] = true
+				progressed = true
+			}
+		}
+		if !progressed {
+			return nil, fmt.Errorf("foreign keys between tenant tables form a cycle")
+		}
+	}
+
+	return order, nil
+}
+
+// buildImportTables encodes rows in insert order, listing only columns present in the data.
+// Generated columns are never listed: the database rejects values for them.
+func buildImportTables(data *importData, order []string, target []repository.ExportTable) ([]repository.ImportTable, error) {
+	targetColumns := map[string][]string{}
+	for _, table := range target {
+		targetColumns[table.Name] = table.Columns
+	}
+
+	tables := make([]repository.ImportTable, 0, len(order))
+	for _, name := range order {
+		rows := data.tables[name]
+
+		seen := map[string]bool{}
+		for _, row := range rows {
+			for column := range row {
+				seen[column] = true
+			}
+		}
+
+		table := repository.ImportTable{Name: name}
+		for _, column := range targetColumns[name] {
+			if seen[column] {
+				table.Columns = append(table.Columns, column)
+			}
+		}
+
+		for _, row := range rows {
+			encoded, err := json.Marshal(row)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode %s row: %w", name, err)
+			}
+			table.Rows = append(table.Rows, encoded)
+		}
+
+		tables = append(tables, table)
+	}
+
+	return tables, nil
+}
+
+func readZipFile(file *zip.File) ([]byte, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+func hashZipFile(file *zip.File) (string, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, rc); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func checksumMatches(content []byte, expected string) bool {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]) == expected
+}
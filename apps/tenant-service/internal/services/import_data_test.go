@@ -0,0 +1,218 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/comply360/shared/models"
+	"github.com/comply360/shared/settings"
+	testhelpers "github.com/comply360/shared/testing"
+	"github.com/comply360/tenant-service/internal/repository"
+	"github.com/google/uuid"
+)
+
+var testForeignKeys = []repository.ForeignKey{
+	{Table: "registrations", Column: "client_id", RefTable: "clients", RefColumn: "id"},
+	{Table: "clients", Column: "user_id", RefTable: "users", RefColumn: "id"},
+	{Table: "documents", Column: "registration_id", RefTable: "registrations", RefColumn: "id"},
+}
+
+// buildTestArchive writes an export archive with the given JSONL tables
+func buildTestArchive(t *testing.T, tenantID uuid.UUID, tables map[string]string, tamper string) *zip.Reader {
+	var buf bytes.Buffer
+	archive := newExportArchive(&buf)
+
+	manifest := &models.TenantExportManifest{TenantID: tenantID, Format: models.ExportFormatJSONL}
+	for _, name := range []string{"users", "clients", "registrations"} {
+		content, ok := tables[name]
+		if !ok {
+			continue
+		}
+		entry, err := archive.addFile(exportTablePath(name, models.ExportFormatJSONL), name, func(w io.Writer) (int, error) {
+			_, err := io.WriteString(w, content)
+			return strings.Count(content, "\n"), err
+		})
+		testhelpers.AssertNoError(t, err)
+		if name == tamper {
+			entry.SHA256 = strings.Repeat("0", 64)
+		}
+		manifest.Tables = append(manifest.Tables, entry)
+	}
+
+	testhelpers.AssertNoError(t, archive.addManifest(manifest))
+	testhelpers.AssertNoError(t, archive.close())
+
+	reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	testhelpers.AssertNoError(t, err)
+	return reader
+}
+
+const (
+	testUserID   = "11111111-1111-4111-8111-111111111111"
+	testClientID = "22222222-2222-4222-8222-222222222222"
+	testRegID    = "33333333-3333-4333-8333-333333333333"
+)
+
+func testTables(tenantID uuid.UUID) map[string]string {
+	tenant := tenantID.String()
+	return map[string]string{
+		"users":         `{"id":"` + testUserID + `","tenant_id":"` + tenant + `","email":"a@b.co.za"}` + "\n",
+		"clients":       `{"id":"` + testClientID + `","tenant_id":"` + tenant + `","user_id":"` + testUserID + `","email":"a@b.co.za"}` + "\n",
+		"registrations": `{"id":"` + testRegID + `","tenant_id":"` + tenant + `","client_id":"` + testClientID + `","form_data":{"x":1.50}}` + "\n",
+	}
+}
+
+func TestReadImportArchive(t *testing.T) {
+	tenantID := uuid.New()
+	data, problems, err := readImportArchive(buildTestArchive(t, tenantID, testTables(tenantID), ""))
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 0, len(problems))
+	testhelpers.AssertEqual(t, tenantID, data.manifest.TenantID)
+	testhelpers.AssertEqual(t, 1, len(data.tables["clients"]))
+	testhelpers.AssertEqual(t, testUserID, data.tables["clients"][0]["user_id"])
+}
+
+func TestReadImportArchive_DetectsChecksumMismatch(t *testing.T) {
+	tenantID := uuid.New()
+	_, problems, err := readImportArchive(buildTestArchive(t, tenantID, testTables(tenantID), "clients"))
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 1, len(problems))
+	testhelpers.AssertEqual(t, "clients", problems[0].Table)
+}
+
+func TestRemapAndRewrite_PreserveReferences(t *testing.T) {
+	sourceID, targetID := uuid.New(), uuid.New()
+	data, _, err := readImportArchive(buildTestArchive(t, sourceID, testTables(sourceID), ""))
+	testhelpers.AssertNoError(t, err)
+
+	remapImportIDs(data)
+	rewriteImportTenant(data, sourceID, targetID)
+
+	client := data.tables["clients"][0]
+	registration := data.tables["registrations"][0]
+	testhelpers.AssertNotEqual(t, testClientID, client["id"], "IDs should be remapped")
+	testhelpers.AssertEqual(t, client["id"], registration["client_id"], "References should follow remapped IDs")
+	testhelpers.AssertEqual(t, data.tables["users"][0]["id"], client["user_id"])
+	testhelpers.AssertEqual(t, targetID.String(), registration["tenant_id"])
+	testhelpers.AssertEqual(t, 0, len(validateImportReferences(data, testForeignKeys)))
+}
+
+func TestValidateImportReferences_ReportsDanglingKeys(t *testing.T) {
+	tenantID := uuid.New()
+	tables := testTables(tenantID)
+	delete(tables, "users")
+
+	data, _, err := readImportArchive(buildTestArchive(t, tenantID, tables, ""))
+	testhelpers.AssertNoError(t, err)
+
+	problems := validateImportReferences(data, testForeignKeys)
+	testhelpers.AssertEqual(t, 1, len(problems))
+	testhelpers.AssertEqual(t, "clients", problems[0].Table)
+	testhelpers.AssertEqual(t, "user_id", problems[0].Column)
+	testhelpers.AssertEqual(t, testUserID, problems[0].Value)
+}
+
+func TestValidateImportColumns(t *testing.T) {
+	tenantID := uuid.New()
+	data, _, err := readImportArchive(buildTestArchive(t, tenantID, testTables(tenantID), ""))
+	testhelpers.AssertNoError(t, err)
+
+	target := []repository.ExportTable{
+		{Name: "users", Columns: []string{"id", "tenant_id", "email"}},
+		{Name: "clients", Columns: []string{"id", "tenant_id", "user_id"}},
+	}
+
+	problems := validateImportColumns(data, target)
+	testhelpers.AssertEqual(t, 2, len(problems))
+	testhelpers.AssertEqual(t, "email", problems[0].Column)
+	testhelpers.AssertEqual(t, "registrations", problems[1].Table)
+}
+
+func TestImportOrder(t *testing.T) {
+	order, err := importOrder([]string{"documents", "registrations", "clients", "users", "audit_log"}, testForeignKeys)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "audit_log,users,clients,registrations,documents", strings.Join(order, ","))
+
+	cyclic := append(testForeignKeys, repository.ForeignKey{Table: "users", Column: "client_id", RefTable: "clients", RefColumn: "id"})
+	_, err = importOrder([]string{"users", "clients"}, cyclic)
+	testhelpers.AssertError(t, err, "Cyclic foreign keys should be rejected")
+}
+
+func TestBuildImportTables_KeepsNumbersExact(t *testing.T) {
+	tenantID := uuid.New()
+	data, _, err := readImportArchive(buildTestArchive(t, tenantID, testTables(tenantID), ""))
+	testhelpers.AssertNoError(t, err)
+
+	target := []repository.ExportTable{{Name: "registrations", Columns: []string{"id", "tenant_id", "client_id", "status", "form_data"}}}
+	tables, err := buildImportTables(data, []string{"registrations"}, target)
+	testhelpers.AssertNoError(t, err)
+
+	testhelpers.AssertEqual(t, "id,tenant_id,client_id,form_data", strings.Join(tables[0].Columns, ","), "Columns absent from the data should use their defaults")
+	testhelpers.AssertTrue(t, strings.Contains(string(tables[0].Rows[0]), `"x":1.50`), "Numbers should not be reformatted")
+}
+
+func TestBuildImportTables_SkipsGeneratedColumns(t *testing.T) {
+	tenantID := uuid.New()
+	tables := testTables(tenantID)
+	tables["clients"] = `{"id":"` + testClientID + `","tenant_id":"` + tenantID.String() + `","user_id":"` + testUserID + `","company_name":"Acme","company_name_key":"acme","search_vector":"'acme':1"}` + "\n"
+	tables["registrations"] = `{"id":"` + testRegID + `","tenant_id":"` + tenantID.String() + `","client_id":"` + testClientID + `","search_vector":"'acme':1"}` + "\n"
+	data, _, err := readImportArchive(buildTestArchive(t, tenantID, tables, ""))
+	testhelpers.AssertNoError(t, err)
+
+	target := []repository.ExportTable{
+		{Name: "clients", Columns: []string{"id", "tenant_id", "user_id", "company_name"}, Generated: []string{"search_vector", "company_name_key"}},
+		{Name: "registrations", Columns: []string{"id", "tenant_id", "client_id"}, Generated: []string{"search_vector"}},
+	}
+	order := []string{"clients", "registrations"}
+	problems := validateImportColumns(&importData{tables: data.tables, names: order}, target)
+	testhelpers.AssertEqual(t, 0, len(problems), "Archives holding generated columns should still import")
+
+	built, err := buildImportTables(data, order, target)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "id,tenant_id,user_id,company_name", strings.Join(built[0].Columns, ","), "Generated client columns should not be inserted")
+	testhelpers.AssertEqual(t, "id,tenant_id,client_id", strings.Join(built[1].Columns, ","), "Generated registration columns should not be inserted")
+}
+
+func TestRewriteStoragePath(t *testing.T) {
+	sourceID, targetID := uuid.New(), uuid.New()
+	path := "tenants/" + sourceID.String() + "/id_document/abc.pdf"
+	testhelpers.AssertEqual(t, "tenants/"+targetID.String()+"/id_document/abc.pdf", rewriteStoragePath(path, sourceID, targetID))
+	testhelpers.AssertEqual(t, "elsewhere/abc.pdf", rewriteStoragePath("elsewhere/abc.pdf", sourceID, targetID))
+}
+
+func TestResealImportSettings(t *testing.T) {
+	keyring, err := settings.NewKeyring("k1", map[string][]byte{"k1": bytes.Repeat([]byte{7}, 32)})
+	testhelpers.AssertNoError(t, err)
+	sourceID, targetID := uuid.New(), uuid.New()
+
+	sealed, err := keyring.Seal(sourceID, "smtp_password", "s3cret")
+	testhelpers.AssertNoError(t, err)
+	foreign, err := keyring.Seal(uuid.New(), "odoo_api_key", "elsewhere")
+	testhelpers.AssertNoError(t, err)
+
+	rows := func() *importData {
+		return &importData{names: []string{"tenant_settings"}, tables: map[string][]importRow{"tenant_settings": {
+			{"key": "smtp_password", "value": sealed, "is_encrypted": true},
+			{"key": "odoo_api_key", "value": foreign, "is_encrypted": true},
+			{"key": "timezone", "value": "Africa/Johannesburg", "is_encrypted": false},
+		}}}
+	}
+
+	data := rows()
+	skipped, err := resealImportSettings(data, keyring, sourceID, targetID, false)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "odoo_api_key", strings.Join(skipped, ","), "Values that do not open should be reported")
+	testhelpers.AssertEqual(t, 2, len(data.tables["tenant_settings"]))
+	plaintext, err := keyring.Open(targetID, "smtp_password", data.tables["tenant_settings"][0]["value"].(string))
+	testhelpers.AssertNoError(t, err, "Secrets should open for the target tenant")
+	testhelpers.AssertEqual(t, "s3cret", plaintext)
+
+	data = rows()
+	skipped, err = resealImportSettings(data, keyring, sourceID, targetID, true)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "odoo_api_key,smtp_password", strings.Join(skipped, ","), "Anonymised copies should leave every secret out")
+	testhelpers.AssertEqual(t, "timezone", data.tables["tenant_settings"][0]["key"])
+}
@@ -0,0 +1,31 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/comply360/shared/models"
+	testhelpers "github.com/comply360/shared/testing"
+)
+
+func TestCloneTenantRequest_AnonymisesSourceDetails(t *testing.T) {
+	company, email, phone, country := "Acme Agency (Pty) Ltd", "owner@acme.co.za", "+27821234567", "ZA"
+	source := &models.Tenant{CompanyName: &company, ContactEmail: &email, ContactPhone: &phone, Country: &country}
+	req := &models.CloneTenantRequest{Name: "Acme staging", Subdomain: "acme-staging"}
+
+	create, err := cloneTenantRequest(source, req, true)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertNotEqual(t, company, create.CompanyName)
+	testhelpers.AssertTrue(t, strings.HasSuffix(create.ContactEmail, "@example.com"), "The clone's admin should not be the real contact")
+	testhelpers.AssertNotEqual(t, phone, create.ContactPhone)
+	testhelpers.AssertEqual(t, "ZA", create.Country)
+
+	req.ContactEmail = "qa@agency.test"
+	create, err = cloneTenantRequest(source, req, true)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "qa@agency.test", create.ContactEmail, "Details given for the clone should be used")
+
+	create, err = cloneTenantRequest(source, &models.CloneTenantRequest{Name: "Copy", Subdomain: "copy"}, false)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, email, create.ContactEmail, "Copies that are not anonymised keep the source's details")
+}
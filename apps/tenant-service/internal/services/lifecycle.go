@@ -0,0 +1,212 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/comply360/shared/models"
+	"github.com/comply360/shared/tenancy"
+	"github.com/comply360/tenant-service/internal/repository"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// purgeTimeout bounds the external cleanup of a single tenant
+const purgeTimeout = 5 * time.Minute
+
+// LifecycleService suspends, reactivates, schedules deletion of and purges tenants
+type LifecycleService struct {
+	repo        *repository.TenantRepository
+	redis       *redis.Client
+	objects     ObjectStore
+	events      *EventPublisher
+	signingKey  []byte
+	gracePeriod time.Duration
+}
+
+func NewLifecycleService(repo *repository.TenantRepository, redisClient *redis.Client, objects ObjectStore, events *EventPublisher, signingKey []byte, gracePeriod time.Duration) *LifecycleService {
+	return &LifecycleService{
+		repo:        repo,
+		redis:       redisClient,
+		objects:     objects,
+		events:      events,
+		signingKey:  signingKey,
+		gracePeriod: gracePeriod,
+	}
+}
+
+// SuspendTenant blocks logins and API access for a tenant
+func (s *LifecycleService) SuspendTenant(id uuid.UUID, reason string) (*models.Tenant, error) {
+	if err := s.repo.Suspend(id, reason); err != nil {
+		return nil, err
+	}
+
+	s.revokeSessions(id)
+	s.events.Publish(tenancy.EventTenantSuspended, id)
+
+	return s.repo.GetByID(id)
+}
+
+// ReactivateTenant restores access to a suspended tenant, cancelling any scheduled deletion
+func (s *LifecycleService) ReactivateTenant(id uuid.UUID) (*models.Tenant, error) {
+	if err := s.repo.Reactivate(id); err != nil {
+		return nil, err
+	}
+
+	s.events.Publish(tenancy.EventTenantReactivated, id)
+
+	return s.repo.GetByID(id)
+}
+
+// ScheduleDeletion suspends a tenant and schedules its data for purge after the grace period
+func (s *LifecycleService) ScheduleDeletion(id uuid.UUID, graceDays *int) (*models.Tenant, error) {
+	grace := s.gracePeriod
+	if graceDays != nil {
+		grace = time.Duration(*graceDays) * 24 * time.Hour
+	}
+
+	if err := s.repo.ScheduleDeletion(id, time.Now().Add(grace)); err != nil {
+		return nil, err
+	}
+
+	s.revokeSessions(id)
+	s.events.Publish(tenancy.EventTenantDeletionScheduled, id)
+
+	return s.repo.GetByID(id)
+}
+
+// PurgeDueTenants purges every tenant whose deletion grace period has ended
+func (s *LifecycleService) PurgeDueTenants() (int, error) {
+	ids, err := s.repo.ListDueForPurge()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list tenants due for purge: %w", err)
+	}
+
+	purged := 0
+	for _, id := range ids {
+		if _, err := s.PurgeTenant(id); err != nil {
+			log.Printf("Warning: Failed to purge tenant %s: %v", id, err)
+			continue
+		}
+		purged++
+	}
+
+	return purged, nil
+}
+
+// PurgeTenant irreversibly removes a tenant's data and records a signed deletion certificate.
+// Each step is idempotent, so a failed purge is retried from the start on the next run.
+func (s *LifecycleService) PurgeTenant(id uuid.UUID) (*models.TenantDeletionCertificate, error) {
+	candidate, err := s.repo.GetPurgeCandidate(id)
+	if err != nil {
+		return nil, err
+	}
+	tenant := candidate.Tenant
+
+	ctx, cancel := context.WithTimeout(context.Background(), purgeTimeout)
+	defer cancel()
+
+	sessionsRevoked := 0
+	if s.redis != nil {
+		if sessionsRevoked, err = tenancy.RevokeTenantSessions(ctx, s.redis, id); err != nil {
+			return nil, err
+		}
+	}
+
+	objectsDeleted := 0
+	if s.objects != nil {
+		if objectsDeleted, err = s.objects.RemovePrefix(ctx, tenantObjectPrefix(id)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.DropTenantSchema(id, tenant.TenantSchema()); err != nil {
+		return nil, fmt.Errorf("failed to drop tenant schema: %w", err)
+	}
+
+	certificate := &models.TenantDeletionCertificate{
+		TenantID:            tenant.ID,
+		TenantName:          tenant.Name,
+		Subdomain:           tenant.Subdomain,
+		SchemaName:          tenant.TenantSchema(),
+		DeletionRequestedAt: candidate.DeletionRequestedAt,
+		SchemaDropped:       true,
+		ObjectsDeleted:      objectsDeleted,
+		SessionsRevoked:     sessionsRevoked,
+		PurgedAt:            time.Now().UTC().Truncate(time.Second),
+	}
+	if certificate.Signature, err = signCertificate(s.signingKey, certificate); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CompletePurge(certificate); err != nil {
+		return nil, fmt.Errorf("failed to record purge: %w", err)
+	}
+
+	s.events.Publish(tenancy.EventTenantDeleted, id)
+
+	return certificate, nil
+}
+
+// GetDeletionCertificate returns a purged tenant's deletion certificate after checking its signature
+func (s *LifecycleService) GetDeletionCertificate(id uuid.UUID) (*models.TenantDeletionCertificate, error) {
+	certificate, err := s.repo.GetDeletionCertificate(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !verifyCertificate(s.signingKey, certificate) {
+		return nil, fmt.Errorf("deletion certificate signature mismatch")
+	}
+
+	return certificate, nil
+}
+
+// revokeSessions revokes a tenant's refresh tokens, logging rather than failing on error
+func (s *LifecycleService) revokeSessions(id uuid.UUID) {
+	if s.redis == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := tenancy.RevokeTenantSessions(ctx, s.redis, id); err != nil {
+		log.Printf("Warning: Failed to revoke sessions for tenant %s: %v", id, err)
+	}
+}
+
+// tenantObjectPrefix is the object storage prefix holding a tenant's documents
+func tenantObjectPrefix(id uuid.UUID) string {
+	return fmt.Sprintf("tenants/%s/", id)
+}
+
+// signCertificate returns the hex HMAC-SHA256 of a certificate with its signature cleared
+func signCertificate(key []byte, certificate *models.TenantDeletionCertificate) (string, error) {
+	unsigned := *certificate
+	unsigned.Signature = ""
+
+	payload, err := json.Marshal(unsigned)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal certificate: %w", err)
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// verifyCertificate checks a certificate's signature
+func verifyCertificate(key []byte, certificate *models.TenantDeletionCertificate) bool {
+	expected, err := signCertificate(key, certificate)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal([]byte(expected), []byte(certificate.Signature))
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// ObjectStore removes a tenant's stored files. MinIOObjectStore satisfies it; tests inject a fake.
+type ObjectStore interface {
+	RemovePrefix(ctx context.Context, prefix string) (int, error)
+}
+
+// ExportStore reads tenant documents and stores export archives. MinIOObjectStore satisfies it.
+type ExportStore interface {
+	GetObject(ctx context.Context, key string) (io.ReadCloser, error)
+	PutObject(ctx context.Context, key string, reader io.Reader, size int64, contentType string) error
+	RemoveObject(ctx context.Context, key string) error
+	PresignedGetURL(ctx context.Context, key string, expiry time.Duration, fileName string) (string, error)
+}
+
+// MinIOObjectStore removes objects from the documents bucket
+type MinIOObjectStore struct {
+	client *minio.Client
+	bucket string
+}
+
+func NewMinIOObjectStore(client *minio.Client, bucket string) *MinIOObjectStore {
+	return &MinIOObjectStore{
+		client: client,
+		bucket: bucket,
+	}
+}
+
+// RemovePrefix deletes every object under prefix and returns how many were removed
+func (s *MinIOObjectStore) RemovePrefix(ctx context.Context, prefix string) (int, error) {
+	objects := s.client.ListObjects(ctx, s.bucket, minio.ListObjectsOptions{
+		Prefix:    prefix,
+		Recursive: true,
+	})
+
+	removed := 0
+	for object := range objects {
+		if object.Err != nil {
+			return removed, fmt.Errorf("failed to list objects: %w", object.Err)
+		}
+		if err := s.client.RemoveObject(ctx, s.bucket, object.Key, minio.RemoveObjectOptions{}); err != nil {
+			return removed, fmt.Errorf("failed to remove object %s: %w", object.Key, err)
+		}
+		removed++
+	}
+
+	return removed, nil
+}
+
+// GetObject opens an object for reading
+func (s *MinIOObjectStore) GetObject(ctx context.Context, key string) (io.ReadCloser, error) {
+	object, err := s.client.GetObject(ctx, s.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	// GetObject is lazy; Stat surfaces a missing object before the caller starts copying
+	if _, err := object.Stat(); err != nil {
+		object.Close()
+		return nil, err
+	}
+
+	return object, nil
+}
+
+// PutObject uploads an object of known size
+func (s *MinIOObjectStore) PutObject(ctx context.Context, key string, reader io.Reader, size int64, contentType string) error {
+	_, err := s.client.PutObject(ctx, s.bucket, key, reader, size, minio.PutObjectOptions{
+		ContentType: contentType,
+	})
+	return err
+}
+
+// RemoveObject deletes a single object
+func (s *MinIOObjectStore) RemoveObject(ctx context.Context, key string) error {
+	return s.client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{})
+}
+
+// PresignedGetURL returns a time-limited download URL for an object
+func (s *MinIOObjectStore) PresignedGetURL(ctx context.Context, key string, expiry time.Duration, fileName string) (string, error) {
+	params := url.Values{}
+	params.Set("response-content-disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+
+	presigned, err := s.client.PresignedGetObject(ctx, s.bucket, key, expiry, params)
+	if err != nil {
+		return "", err
+	}
+	return presigned.String(), nil
+}
@@ -0,0 +1,445 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/comply360/shared/jurisdictions"
+	"github.com/comply360/shared/models"
+	"github.com/comply360/shared/sqlscript"
+	"github.com/google/uuid"
+)
+
+// TenantMigration is a migration applied inside every tenant schema
+type TenantMigration struct {
+	Name          string
+	Version       int
+	NoTransaction bool
+	SQL           string
+}
+
+// Header markers understood by the migrator
+const (
+	tenantScopeHeader   = "-- Scope: tenant"
+	noTransactionHeader = "-- Transaction: none"
+)
+
+// tenantSetting is a default setting seeded into a new tenant
+type tenantSetting struct {
+	key       string
+	value     string
+	valueType string
+}
+
+// defaultTenantSettings returns the settings seeded into every new tenant, in
+// the currency and timezone of its country
+func defaultTenantSettings(tenant *models.Tenant) []tenantSetting {
+	var j jurisdictions.Jurisdiction = jurisdictions.SouthAfrica{}
+	if tenant.Country != nil {
+		if country, ok := jurisdictions.Get(*tenant.Country); ok {
+			j = country
+		}
+	}
+
+	return []tenantSetting{
+		{"enabled_roles", `["tenant_admin","tenant_manager","agent","agent_assistant","client"]`, "json"},
+		{"default_user_role", models.RoleClient, "string"},
+		{"timezone", j.Timezone(), "string"},
+		{"currency", j.Currency(), "string"},
+		{"mfa_required", "false", "boolean"},
+		{"max_users", strconv.Itoa(tenant.MaxUsers), "number"},
+	}
+}
+
+// LoadTenantMigrations reads the tenant-scoped migrations from the migrations directory.
+// Loading happens once at startup so a bad path fails fast instead of mid-provisioning.
+func LoadTenantMigrations(dir string) ([]TenantMigration, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations directory: %w", err)
+	}
+
+	var migrations []TenantMigration
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		content, err := os.ReadFile(filepath.Join(dir, entry.Name(), "up.sql"))
+		if err != nil {
+			continue
+		}
+		if !hasHeader(string(content), tenantScopeHeader) {
+			continue
+		}
+
+		version, err := strconv.Atoi(strings.SplitN(entry.Name(), "_", 2)[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration name %s: %w", entry.Name(), err)
+		}
+
+		migrations = append(migrations, TenantMigration{
+			Name:          entry.Name(),
+			Version:       version,
+			NoTransaction: hasHeader(string(content), noTransactionHeader),
+			SQL:           string(content),
+		})
+	}
+
+	if len(migrations) == 0 {
+		return nil, fmt.Errorf("no tenant migrations found in %s", dir)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// hasHeader checks the leading comment block of a migration for a marker line
+func hasHeader(content, marker string) bool {
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "--") {
+			return false
+		}
+		if strings.EqualFold(line, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// ProvisionTenant runs (or resumes) the provisioning saga for a tenant.
+// Completed steps are skipped, so a failed run can simply be retried.
+func (s *TenantService) ProvisionTenant(tenantID uuid.UUID) error {
+	ctx := context.Background()
+
+	// Get tenant
+	tenant, err := s.repo.GetByID(tenantID)
+	if err != nil {
+		return err
+	}
+
+	// Use a dedicated connection so session state never leaks into the pool
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Close()
+
+	// Only one provisioning run per tenant at a time
+	lockKey := "tenant_provisioning:" + tenantID.String()
+	var locked bool
+	if err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock(hashtext($1))`, lockKey).Scan(&locked); err != nil {
+		return fmt.Errorf("failed to acquire provisioning lock: %w", err)
+	}
+	if !locked {
+		return fmt.Errorf("provisioning already in progress")
+	}
+	defer conn.ExecContext(ctx, `SELECT pg_advisory_unlock(hashtext($1))`, lockKey)
+
+	if err := s.repo.InitProvisioningSteps(tenantID, models.ProvisioningSteps); err != nil {
+		return fmt.Errorf("failed to initialise provisioning steps: %w", err)
+	}
+
+	steps, err := s.repo.GetProvisioningSteps(tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to get provisioning steps: %w", err)
+	}
+
+	for _, step := range steps {
+		if step.Status == models.ProvisioningStatusCompleted {
+			continue
+		}
+
+		if err := s.repo.StartProvisioningStep(tenantID, step.Step); err != nil {
+			return fmt.Errorf("failed to start step %s: %w", step.Step, err)
+		}
+
+		if err := s.runProvisioningStep(ctx, conn, tenant, step.Step); err != nil {
+			s.repo.FailProvisioningStep(tenantID, step.Step, err.Error())
+			return fmt.Errorf("provisioning step %s failed: %w", step.Step, err)
+		}
+
+		if err := s.repo.CompleteProvisioningStep(tenantID, step.Step); err != nil {
+			return fmt.Errorf("failed to complete step %s: %w", step.Step, err)
+		}
+	}
+
+	// TODO: Send welcome email
+	// TODO: Configure DNS/subdomain
+
+	return nil
+}
+
+// GetProvisioningStatus returns the provisioning progress of a tenant
+func (s *TenantService) GetProvisioningStatus(tenantID uuid.UUID) (*models.TenantProvisioningStatus, error) {
+	if _, err := s.repo.GetByID(tenantID); err != nil {
+		return nil, err
+	}
+
+	steps, err := s.repo.GetProvisioningSteps(tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get provisioning steps: %w", err)
+	}
+
+	provisionedAt, err := s.repo.GetProvisionedAt(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &models.TenantProvisioningStatus{
+		TenantID:      tenantID,
+		Status:        models.ProvisioningStatusPending,
+		Steps:         steps,
+		ProvisionedAt: provisionedAt,
+	}
+
+	completed := 0
+	for i := range steps {
+		switch steps[i].Status {
+		case models.ProvisioningStatusCompleted:
+			completed++
+			continue
+		case models.ProvisioningStatusRunning, models.ProvisioningStatusFailed:
+			status.Status = steps[i].Status
+		}
+		if status.CurrentStep == nil {
+			status.CurrentStep = &steps[i].Step
+		}
+	}
+
+	if len(steps) > 0 && completed == len(steps) {
+		status.Status = models.ProvisioningStatusCompleted
+	} else if completed > 0 && status.Status == models.ProvisioningStatusPending {
+		status.Status = models.ProvisioningStatusRunning
+	}
+
+	return status, nil
+}
+
+// runProvisioningStep executes a single idempotent provisioning step
+func (s *TenantService) runProvisioningStep(ctx context.Context, conn *sql.Conn, tenant *models.Tenant, step string) error {
+	switch step {
+	case models.ProvisioningStepCreateSchema:
+		return s.createTenantSchema(ctx, conn, tenant)
+	case models.ProvisioningStepApplyMigrations:
+		return s.applyTenantMigrations(ctx, conn, tenant)
+	case models.ProvisioningStepSeedDefaults:
+		return s.seedTenantDefaults(ctx, conn, tenant)
+	case models.ProvisioningStepCreateAdmin:
+		return s.createTenantAdmin(ctx, conn, tenant)
+	case models.ProvisioningStepMarkReady:
+		return s.repo.MarkProvisioned(tenant.ID, tenant.TenantSchema())
+	default:
+		return fmt.Errorf("unknown provisioning step: %s", step)
+	}
+}
+
+// createTenantSchema creates the tenant schema and registers it
+func (s *TenantService) createTenantSchema(ctx context.Context, conn *sql.Conn, tenant *models.Tenant) error {
+	schemaName := tenant.TenantSchema()
+
+	if _, err := conn.ExecContext(ctx, fmt.Sprintf(`CREATE SCHEMA IF NOT EXISTS %s`, schemaName)); err != nil {
+		return fmt.Errorf("failed to create schema: %w", err)
+	}
+
+	return s.repo.EnsureSchemaRecord(tenant.ID, schemaName)
+}
+
+// applyTenantMigrations applies pending tenant migrations. Consecutive
+// transactional migrations share one transaction with the migration_version
+// update, so a retry sees either all of them applied or none.
+func (s *TenantService) applyTenantMigrations(ctx context.Context, conn *sql.Conn, tenant *models.Tenant) error {
+	var batch []TenantMigration
+	for _, migration := range s.migrations {
+		if !migration.NoTransaction {
+			batch = append(batch, migration)
+			continue
+		}
+
+		if err := s.applyTenantMigrationBatch(ctx, conn, tenant, batch); err != nil {
+			return err
+		}
+		batch = nil
+
+		if err := s.applyTenantMigrationNoTx(ctx, conn, tenant, migration); err != nil {
+			return err
+		}
+	}
+
+	return s.applyTenantMigrationBatch(ctx, conn, tenant, batch)
+}
+
+// applyTenantMigrationBatch applies transactional migrations in a single transaction
+func (s *TenantService) applyTenantMigrationBatch(ctx context.Context, conn *sql.Conn, tenant *models.Tenant, batch []TenantMigration) error {
+	if len(batch) == 0 {
+		return nil
+	}
+
+	schemaName := tenant.TenantSchema()
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`SET LOCAL search_path TO %s, public`, schemaName)); err != nil {
+		return err
+	}
+
+	var version int
+	err = tx.QueryRowContext(ctx, `
+		SELECT migration_version FROM public.tenant_schemas
+		WHERE schema_name = $1
+		FOR UPDATE
+	`, schemaName).Scan(&version)
+	if err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	for _, migration := range batch {
+		if migration.Version <= version {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, migration.SQL); err != nil {
+			return fmt.Errorf("failed to execute migration %s: %w", migration.Name, err)
+		}
+		version = migration.Version
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE public.tenant_schemas
+		SET migration_version = $2, updated_at = NOW()
+		WHERE schema_name = $1
+	`, schemaName, version); err != nil {
+		return fmt.Errorf("failed to record schema version: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// applyTenantMigrationNoTx applies a "-- Transaction: none" migration outside a
+// transaction, statement by statement, as the migrator does
+func (s *TenantService) applyTenantMigrationNoTx(ctx context.Context, conn *sql.Conn, tenant *models.Tenant, migration TenantMigration) error {
+	schemaName := tenant.TenantSchema()
+
+	var version int
+	err := conn.QueryRowContext(ctx, `
+		SELECT migration_version FROM public.tenant_schemas WHERE schema_name = $1
+	`, schemaName).Scan(&version)
+	if err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+	if migration.Version <= version {
+		return nil
+	}
+
+	// Session-level search_path on the dedicated connection, reset before it is released
+	return sqlscript.WithSearchPath(ctx, conn, schemaName, func() error {
+		if err := sqlscript.Exec(ctx, conn, migration.SQL); err != nil {
+			return fmt.Errorf("failed to execute migration %s: %w", migration.Name, err)
+		}
+
+		if _, err := conn.ExecContext(ctx, `
+			UPDATE public.tenant_schemas
+			SET migration_version = $2, updated_at = NOW()
+			WHERE schema_name = $1 AND migration_version < $2
+		`, schemaName, migration.Version); err != nil {
+			return fmt.Errorf("failed to record schema version: %w", err)
+		}
+		return nil
+	})
+}
+
+// seedTenantDefaults inserts the default tenant settings, keeping existing values
+func (s *TenantService) seedTenantDefaults(ctx context.Context, conn *sql.Conn, tenant *models.Tenant) error {
+	tx, err := s.beginTenantTx(ctx, conn, tenant)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, setting := range defaultTenantSettings(tenant) {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO tenant_settings (tenant_id, key, value, value_type)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (tenant_id, key) DO NOTHING
+		`, tenant.ID, setting.key, setting.value, setting.valueType); err != nil {
+			return fmt.Errorf("failed to seed setting %s: %w", setting.key, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// createTenantAdmin creates the initial tenant_admin user from the tenant contact email
+func (s *TenantService) createTenantAdmin(ctx context.Context, conn *sql.Conn, tenant *models.Tenant) error {
+	if tenant.ContactEmail == nil || *tenant.ContactEmail == "" {
+		return fmt.Errorf("tenant has no contact email for the admin user")
+	}
+
+	tx, err := s.beginTenantTx(ctx, conn, tenant)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// The admin has no password yet; it is set through the password reset flow
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO users (tenant_id, email, status)
+		VALUES ($1, $2, 'active')
+		ON CONFLICT (tenant_id, email) DO NOTHING
+	`, tenant.ID, *tenant.ContactEmail); err != nil {
+		return fmt.Errorf("failed to create admin user: %w", err)
+	}
+
+	var userID uuid.UUID
+	if err := tx.QueryRowContext(ctx, `
+		SELECT id FROM users WHERE tenant_id = $1 AND email = $2
+	`, tenant.ID, *tenant.ContactEmail).Scan(&userID); err != nil {
+		return fmt.Errorf("failed to get admin user: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO user_roles (user_id, role)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id, role) DO NOTHING
+	`, userID, models.RoleTenantAdmin); err != nil {
+		return fmt.Errorf("failed to assign admin role: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// beginTenantTx starts a transaction scoped to the tenant schema with RLS context set
+func (s *TenantService) beginTenantTx(ctx context.Context, conn *sql.Conn, tenant *models.Tenant) (*sql.Tx, error) {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`SET LOCAL search_path TO %s, public`, tenant.TenantSchema())); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `SELECT set_tenant_context($1)`, tenant.ID); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	return tx, nil
+}
@@ -0,0 +1,31 @@
+package services
+
+import (
+	"testing"
+
+	testhelpers "github.com/comply360/shared/testing"
+)
+
+func TestLoadTenantMigrations(t *testing.T) {
+	migrations, err := LoadTenantMigrations("../../../../database/migrations")
+	testhelpers.AssertNoError(t, err, "Failed to load tenant migrations")
+	testhelpers.AssertTrue(t, len(migrations) >= 2, "Should load the tenant template and RLS migrations")
+	testhelpers.AssertEqual(t, "002_tenant_template", migrations[0].Name)
+
+	for i := 1; i < len(migrations); i++ {
+		testhelpers.AssertTrue(t, migrations[i].Version > migrations[i-1].Version, "Migrations should be ordered by version")
+	}
+	testhelpers.AssertTrue(t, migrations[0].SQL != "", "Migration SQL should be loaded")
+}
+
+func TestLoadTenantMigrations_MissingPath(t *testing.T) {
+	_, err := LoadTenantMigrations("does-not-exist")
+	testhelpers.AssertError(t, err, "Should fail when migrations path is missing")
+}
+
+func TestHasHeader(t *testing.T) {
+	testhelpers.AssertTrue(t, hasHeader("-- Migration: 010_x\n-- Scope: tenant\n\nCREATE TABLE x (id INT);", tenantScopeHeader))
+	testhelpers.AssertFalse(t, hasHeader("-- Migration: 010_x\n\nCREATE TABLE x (id INT);", tenantScopeHeader))
+	testhelpers.AssertFalse(t, hasHeader("CREATE TABLE x (id INT);\n-- Scope: tenant", tenantScopeHeader))
+	testhelpers.AssertTrue(t, hasHeader("-- Scope: tenant\n-- Transaction: none\nCREATE INDEX CONCURRENTLY i ON x(id);", noTransactionHeader))
+}
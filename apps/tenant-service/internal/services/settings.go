@@ -0,0 +1,341 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/comply360/shared/models"
+	"github.com/comply360/shared/settings"
+	"github.com/comply360/shared/validator"
+	"github.com/comply360/tenant-service/internal/repository"
+	"github.com/google/uuid"
+)
+
+// SettingsValidationError lists the settings an update rejected. Nothing is stored
+// when any setting is invalid.
+type SettingsValidationError struct {
+	Fields []validator.ValidationError
+}
+
+func (e *SettingsValidationError) Error() string {
+	return "invalid settings"
+}
+
+// SettingsService manages typed tenant settings. Known settings, their types and
+// defaults come from the shared settings registry; sensitive values are sealed
+// with the settings keyring before they reach the database. A sub-agent tenant
+// inherits every non-local setting it has not set from its nearest ancestor.
+type SettingsService struct {
+	repo    *repository.TenantRepository
+	keyring *settings.Keyring
+	events  *EventPublisher
+}
+
+func NewSettingsService(repo *repository.TenantRepository, keyring *settings.Keyring, events *EventPublisher) *SettingsService {
+	return &SettingsService{
+		repo:    repo,
+		keyring: keyring,
+		events:  events,
+	}
+}
+
+// ListSettings returns every known setting of a tenant with inherited values and
+// defaults applied
+func (s *SettingsService) ListSettings(tenantID uuid.UUID) ([]models.TenantSetting, error) {
+	schemaName, err := s.tenantSchema(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	layers, err := s.settingsLayers(tenantID, schemaName)
+	if err != nil {
+		return nil, err
+	}
+
+	defs := settings.Definitions()
+	result := make([]models.TenantSetting, 0, len(defs))
+	for _, def := range defs {
+		result = append(result, s.toSetting(tenantID, def, layers))
+	}
+
+	return result, nil
+}
+
+// GetSetting returns a single known setting
+func (s *SettingsService) GetSetting(tenantID uuid.UUID, key string) (*models.TenantSetting, error) {
+	def, ok := settings.Lookup(key)
+	if !ok {
+		return nil, fmt.Errorf("unknown setting")
+	}
+
+	schemaName, err := s.tenantSchema(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	layers, err := s.settingsLayers(tenantID, schemaName)
+	if err != nil {
+		return nil, err
+	}
+
+	setting := s.toSetting(tenantID, def, layers)
+	return &setting, nil
+}
+
+// UpdateSettings validates and stores several settings at once. Either every
+// setting is stored or, when any is invalid, none is.
+func (s *SettingsService) UpdateSettings(tenantID uuid.UUID, values map[string]interface{}) ([]models.TenantSetting, error) {
+	return s.update(tenantID, values, false)
+}
+
+// update stores settings; read-only settings are only accepted from tenant-service itself
+func (s *SettingsService) update(tenantID uuid.UUID, values map[string]interface{}, internal bool) ([]models.TenantSetting, error) {
+	if len(values) == 0 {
+		return nil, &SettingsValidationError{Fields: []validator.ValidationError{{
+			Field:   "settings",
+			Message: "at least one setting is required",
+			Tag:     "required",
+		}}}
+	}
+
+	schemaName, err := s.tenantSchema(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var invalid []validator.ValidationError
+	var updates []repository.StoredSetting
+	for _, key := range keys {
+		def, ok := settings.Lookup(key)
+		if !ok {
+			invalid = append(invalid, validator.ValidationError{Field: key, Message: "unknown setting", Tag: "setting"})
+			continue
+		}
+		if def.ReadOnly && !internal {
+			invalid = append(invalid, validator.ValidationError{Field: key, Message: "setting is read-only", Tag: "setting"})
+			continue
+		}
+
+		update, err := s.prepare(tenantID, def, values[key])
+		if err != nil {
+			invalid = append(invalid, validator.ValidationError{Field: key, Message: err.Error(), Tag: "setting"})
+			continue
+		}
+		updates = append(updates, *update)
+	}
+	if len(invalid) > 0 {
+		return nil, &SettingsValidationError{Fields: invalid}
+	}
+
+	if err := s.repo.UpsertSettings(tenantID, schemaName, updates); err != nil {
+		return nil, err
+	}
+
+	s.events.PublishSettingsChanged(tenantID, keys)
+
+	return s.ListSettings(tenantID)
+}
+
+// UpdateSetting validates and stores a single setting
+func (s *SettingsService) UpdateSetting(tenantID uuid.UUID, key string, value interface{}) (*models.TenantSetting, error) {
+	if _, ok := settings.Lookup(key); !ok {
+		return nil, fmt.Errorf("unknown setting")
+	}
+
+	if _, err := s.UpdateSettings(tenantID, map[string]interface{}{key: value}); err != nil {
+		return nil, err
+	}
+
+	return s.GetSetting(tenantID, key)
+}
+
+// ResetSetting removes a stored setting so the inherited value or registry default
+// applies again
+func (s *SettingsService) ResetSetting(tenantID uuid.UUID, key string) (*models.TenantSetting, error) {
+	return s.reset(tenantID, key, false)
+}
+
+// reset removes a stored setting; read-only settings are only reset by tenant-service itself
+func (s *SettingsService) reset(tenantID uuid.UUID, key string, internal bool) (*models.TenantSetting, error) {
+	def, ok := settings.Lookup(key)
+	if !ok {
+		return nil, fmt.Errorf("unknown setting")
+	}
+	if def.ReadOnly && !internal {
+		return nil, fmt.Errorf("setting is read-only")
+	}
+
+	schemaName, err := s.tenantSchema(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.DeleteSetting(tenantID, schemaName, key); err != nil {
+		return nil, err
+	}
+
+	s.events.PublishSettingsChanged(tenantID, []string{key})
+
+	return s.GetSetting(tenantID, key)
+}
+
+// prepare normalizes a value and encodes it for storage, sealing sensitive values
+func (s *SettingsService) prepare(tenantID uuid.UUID, def settings.Definition, value interface{}) (*repository.StoredSetting, error) {
+	normalized, err := def.Normalize(value)
+	if err != nil {
+		return nil, err
+	}
+
+	text, err := def.Encode(normalized)
+	if err != nil {
+		return nil, err
+	}
+
+	if def.Sensitive {
+		sealed, err := s.keyring.Seal(tenantID, def.Key, text)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt value: %w", err)
+		}
+		text = sealed
+	}
+
+	return &repository.StoredSetting{
+		Key:         def.Key,
+		Value:       text,
+		ValueType:   def.Type,
+		IsEncrypted: def.Sensitive,
+	}, nil
+}
+
+// toSetting builds the API view of a setting. Sensitive values are reported as
+// set or unset only.
+func (s *SettingsService) toSetting(tenantID uuid.UUID, def settings.Definition, layers []settingsLayer) models.TenantSetting {
+	setting := models.TenantSetting{
+		Key:         def.Key,
+		Type:        def.Type,
+		Default:     def.Default,
+		Sensitive:   def.Sensitive,
+		ReadOnly:    def.ReadOnly,
+		Local:       def.Local,
+		Description: def.Description,
+	}
+
+	stored, owner := effectiveSetting(def, layers)
+	if stored == nil {
+		if !def.Sensitive {
+			setting.Value = def.Default
+		}
+		return setting
+	}
+
+	if owner == tenantID {
+		setting.IsSet = true
+	} else {
+		setting.InheritedFrom = &owner
+	}
+	setting.UpdatedAt = &stored.UpdatedAt
+	if def.Sensitive || stored.IsEncrypted {
+		return setting
+	}
+
+	value, err := def.Decode(stored.Value)
+	if err != nil {
+		log.Printf("Warning: Setting %s of tenant %s does not match its type: %v", def.Key, owner, err)
+		value = stored.Value
+	}
+	setting.Value = value
+
+	return setting
+}
+
+// settingsLayer is the stored settings of one tenant in a hierarchy
+type settingsLayer struct {
+	tenantID uuid.UUID
+	stored   map[string]*repository.StoredSetting
+}
+
+// settingsLayers returns a tenant's stored settings followed by those of its
+// ancestors, nearest first. An ancestor that cannot be read is skipped so a child
+// never loses access to its own settings.
+func (s *SettingsService) settingsLayers(tenantID uuid.UUID, schemaName string) ([]settingsLayer, error) {
+	own, err := s.storedSettings(tenantID, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	layers := []settingsLayer{{tenantID: tenantID, stored: own}}
+
+	ancestors, err := s.repo.ListAncestorIDs(tenantID)
+	if err != nil {
+		log.Printf("Warning: Failed to load ancestors of tenant %s: %v", tenantID, err)
+		return layers, nil
+	}
+
+	for _, ancestorID := range ancestors {
+		ancestorSchema, err := s.tenantSchema(ancestorID)
+		if err != nil {
+			continue
+		}
+		stored, err := s.storedSettings(ancestorID, ancestorSchema)
+		if err != nil {
+			log.Printf("Warning: Failed to load settings of ancestor tenant %s: %v", ancestorID, err)
+			continue
+		}
+		layers = append(layers, settingsLayer{tenantID: ancestorID, stored: stored})
+	}
+
+	return layers, nil
+}
+
+// effectiveSetting finds the stored value that applies to a setting and the tenant
+// it belongs to: the tenant's own, else the nearest ancestor's unless the setting
+// is local
+func effectiveSetting(def settings.Definition, layers []settingsLayer) (*repository.StoredSetting, uuid.UUID) {
+	for i, layer := range layers {
+		if i > 0 && def.Local {
+			break
+		}
+		if stored, ok := layer.stored[def.Key]; ok {
+			return stored, layer.tenantID
+		}
+	}
+	return nil, uuid.Nil
+}
+
+// storedSettings indexes a tenant's stored settings by key
+func (s *SettingsService) storedSettings(tenantID uuid.UUID, schemaName string) (map[string]*repository.StoredSetting, error) {
+	rows, err := s.repo.ListSettings(tenantID, schemaName)
+	if err != nil {
+		return nil, err
+	}
+
+	stored := make(map[string]*repository.StoredSetting, len(rows))
+	for i := range rows {
+		stored[rows[i].Key] = &rows[i]
+	}
+	return stored, nil
+}
+
+// tenantSchema returns the schema of a provisioned tenant
+func (s *SettingsService) tenantSchema(tenantID uuid.UUID) (string, error) {
+	tenant, err := s.repo.GetByID(tenantID)
+	if err != nil {
+		return "", err
+	}
+
+	provisionedAt, err := s.repo.GetProvisionedAt(tenantID)
+	if err != nil {
+		return "", err
+	}
+	if provisionedAt == nil {
+		return "", fmt.Errorf("tenant is not provisioned")
+	}
+
+	return tenant.TenantSchema(), nil
+}
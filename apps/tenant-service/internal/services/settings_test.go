@@ -0,0 +1,126 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/comply360/shared/settings"
+	testhelpers "github.com/comply360/shared/testing"
+	"github.com/comply360/tenant-service/internal/repository"
+	"github.com/google/uuid"
+)
+
+func newTestSettingsService(t *testing.T) *SettingsService {
+	t.Helper()
+	keyring, err := settings.ParseKeyring("test:" + strings.Repeat("A", 43) + "=")
+	testhelpers.AssertNoError(t, err)
+	return NewSettingsService(nil, keyring, nil)
+}
+
+func lookupSetting(t *testing.T, key string) settings.Definition {
+	t.Helper()
+	def, ok := settings.Lookup(key)
+	testhelpers.AssertTrue(t, ok, "Setting "+key+" should be registered")
+	return def
+}
+
+// layersOf builds settings layers for a tenant followed by its ancestors
+func layersOf(tenantIDs []uuid.UUID, stored ...map[string]*repository.StoredSetting) []settingsLayer {
+	layers := make([]settingsLayer, len(tenantIDs))
+	for i, id := range tenantIDs {
+		layers[i] = settingsLayer{tenantID: id, stored: stored[i]}
+	}
+	return layers
+}
+
+func TestSettingsService_PrepareSealsSensitiveValues(t *testing.T) {
+	service := newTestSettingsService(t)
+	tenantID := uuid.New()
+
+	stored, err := service.prepare(tenantID, lookupSetting(t, settings.KeySMTPPassword), "hunter2")
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertTrue(t, stored.IsEncrypted, "Sensitive setting should be stored encrypted")
+	testhelpers.AssertFalse(t, strings.Contains(stored.Value, "hunter2"), "Stored value should not contain the plaintext")
+
+	plaintext, err := service.keyring.Open(tenantID, settings.KeySMTPPassword, stored.Value)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "hunter2", plaintext)
+
+	stored, err = service.prepare(tenantID, lookupSetting(t, settings.KeySMTPPort), 2525.0)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertFalse(t, stored.IsEncrypted, "Plain setting should not be encrypted")
+	testhelpers.AssertEqual(t, "2525", stored.Value)
+	testhelpers.AssertEqual(t, settings.TypeNumber, stored.ValueType)
+}
+
+func TestSettingsService_ToSettingMasksSecrets(t *testing.T) {
+	service := newTestSettingsService(t)
+	tenantID := uuid.New()
+
+	def := lookupSetting(t, settings.KeyOdooPassword)
+	stored, err := service.prepare(tenantID, def, "odoo-secret")
+	testhelpers.AssertNoError(t, err)
+	stored.UpdatedAt = time.Now()
+
+	setting := service.toSetting(tenantID, def, layersOf([]uuid.UUID{tenantID}, map[string]*repository.StoredSetting{def.Key: stored}))
+	testhelpers.AssertTrue(t, setting.IsSet, "Stored secret should be reported as set")
+	testhelpers.AssertNil(t, setting.Value)
+
+	setting = service.toSetting(tenantID, def, layersOf([]uuid.UUID{tenantID}, nil))
+	testhelpers.AssertFalse(t, setting.IsSet, "Missing secret should be reported as unset")
+	testhelpers.AssertNil(t, setting.Value)
+}
+
+func TestSettingsService_ToSettingAppliesDefaults(t *testing.T) {
+	service := newTestSettingsService(t)
+	tenantID := uuid.New()
+
+	def := lookupSetting(t, settings.KeyMFARequired)
+	setting := service.toSetting(tenantID, def, layersOf([]uuid.UUID{tenantID}, nil))
+	testhelpers.AssertFalse(t, setting.IsSet, "Default should not be reported as set")
+	testhelpers.AssertEqual(t, false, setting.Value)
+
+	setting = service.toSetting(tenantID, def, layersOf([]uuid.UUID{tenantID}, map[string]*repository.StoredSetting{
+		settings.KeyMFARequired: {
+			Key:       settings.KeyMFARequired,
+			Value:     "true",
+			ValueType: settings.TypeBoolean,
+		},
+	}))
+	testhelpers.AssertTrue(t, setting.IsSet, "Stored value should be reported as set")
+	testhelpers.AssertEqual(t, true, setting.Value)
+}
+
+func TestSettingsService_ToSettingInheritsFromNearestAncestor(t *testing.T) {
+	service := newTestSettingsService(t)
+	child, parent, grandparent := uuid.New(), uuid.New(), uuid.New()
+
+	stored := func(key, value string) map[string]*repository.StoredSetting {
+		return map[string]*repository.StoredSetting{key: {Key: key, Value: value, ValueType: settings.TypeString}}
+	}
+	def := lookupSetting(t, settings.KeyBrandingDisplayName)
+
+	layers := layersOf([]uuid.UUID{child, parent, grandparent},
+		nil,
+		stored(settings.KeyBrandingDisplayName, "Regional Agents"),
+		stored(settings.KeyBrandingDisplayName, "National Agents"),
+	)
+	setting := service.toSetting(child, def, layers)
+	testhelpers.AssertFalse(t, setting.IsSet, "An inherited value is not the child's own")
+	testhelpers.AssertNotNil(t, setting.InheritedFrom)
+	testhelpers.AssertEqual(t, parent, *setting.InheritedFrom, "The nearest ancestor should win")
+	testhelpers.AssertEqual(t, "Regional Agents", setting.Value)
+
+	layers[0].stored = stored(settings.KeyBrandingDisplayName, "Local Agent")
+	setting = service.toSetting(child, def, layers)
+	testhelpers.AssertTrue(t, setting.IsSet, "The child's own value should override")
+	testhelpers.AssertNil(t, setting.InheritedFrom)
+	testhelpers.AssertEqual(t, "Local Agent", setting.Value)
+
+	// Local settings are never inherited
+	local := lookupSetting(t, settings.KeySMTPHost)
+	setting = service.toSetting(child, local, layersOf([]uuid.UUID{child, parent}, nil, stored(settings.KeySMTPHost, "smtp.parent.example.com")))
+	testhelpers.AssertNil(t, setting.InheritedFrom)
+	testhelpers.AssertNil(t, setting.Value)
+}
@@ -2,6 +2,7 @@
 -- Description: Create tenant schema template with all tenant-specific tables
 -- Author: Comply360 Development Team
 -- Date: 2025-12-27
+-- Scope: tenant
 -- Note: This template is used to provision new tenant schemas
 
 -- ============================================================================
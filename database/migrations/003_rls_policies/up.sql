@@ -2,6 +2,7 @@
 -- Description: Create Row-Level Security (RLS) policies for tenant isolation
 -- Author: Comply360 Development Team
 -- Date: 2025-12-27
+-- Scope: tenant
 
 -- ============================================================================
 -- ROW-LEVEL SECURITY POLICIES
@@ -0,0 +1,9 @@
+-- Migration: 004_tenant_provisioning (ROLLBACK)
+-- Description: Rollback tenant provisioning step tracking
+-- Author: Comply360 Development Team
+-- Date: 2026-10-18
+
+ALTER TABLE public.tenants DROP COLUMN IF EXISTS provisioned_at;
+
+DROP TRIGGER IF EXISTS update_tenant_provisioning_steps_updated_at ON public.tenant_provisioning_steps;
+DROP TABLE IF EXISTS public.tenant_provisioning_steps;
@@ -0,0 +1,43 @@
+-- Migration: 004_tenant_provisioning
+-- Description: Track tenant provisioning as a persisted, resumable step machine
+-- Author: Comply360 Development Team
+-- Date: 2026-10-18
+
+-- ============================================================================
+-- TENANT PROVISIONING STEPS
+-- One row per (tenant, step). Steps are retried until they reach 'completed'.
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS public.tenant_provisioning_steps (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES public.tenants(id) ON DELETE CASCADE,
+    step VARCHAR(50) NOT NULL,
+    position INT NOT NULL,
+    status VARCHAR(50) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    started_at TIMESTAMP,
+    completed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    UNIQUE(tenant_id, step),
+    CONSTRAINT valid_provisioning_step_status CHECK (status IN ('pending', 'running', 'completed', 'failed'))
+);
+
+CREATE INDEX idx_tenant_provisioning_steps_tenant_id ON public.tenant_provisioning_steps(tenant_id);
+CREATE INDEX idx_tenant_provisioning_steps_status ON public.tenant_provisioning_steps(status);
+
+CREATE TRIGGER update_tenant_provisioning_steps_updated_at
+    BEFORE UPDATE ON public.tenant_provisioning_steps
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- ============================================================================
+-- TENANT READINESS
+-- ============================================================================
+
+ALTER TABLE public.tenants ADD COLUMN IF NOT EXISTS provisioned_at TIMESTAMP;
+
+COMMENT ON TABLE public.tenant_provisioning_steps IS 'Progress of the tenant provisioning saga';
+COMMENT ON COLUMN public.tenants.provisioned_at IS 'Set once every provisioning step has completed';
@@ -0,0 +1,8 @@
+-- Migration: 005_tenant_context_functions (ROLLBACK)
+-- Description: Drop the public RLS context functions
+-- Author: Comply360 Development Team
+-- Date: 2026-10-18
+
+DROP FUNCTION IF EXISTS public.get_current_tenant_id();
+DROP FUNCTION IF EXISTS public.clear_tenant_context();
+DROP FUNCTION IF EXISTS public.set_tenant_context(UUID, UUID, TEXT, BOOLEAN);
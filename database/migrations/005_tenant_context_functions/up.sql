@@ -0,0 +1,51 @@
+-- Migration: 005_tenant_context_functions
+-- Description: Create the RLS context functions in the public schema
+-- Author: Comply360 Development Team
+-- Date: 2026-10-18
+-- Note: 002 and 003 are tenant-scoped and only run inside tenant schemas, so the
+--       context helpers used by shared services must also exist in public
+
+-- ============================================================================
+-- FUNCTIONS FOR RLS
+-- ============================================================================
+
+CREATE OR REPLACE FUNCTION public.set_tenant_context(p_tenant_id UUID, p_user_id UUID DEFAULT NULL, p_user_roles TEXT DEFAULT NULL, p_is_global_admin BOOLEAN DEFAULT false)
+RETURNS void AS $$
+BEGIN
+    PERFORM set_config('app.current_tenant_id', p_tenant_id::TEXT, true);
+
+    IF p_user_id IS NOT NULL THEN
+        PERFORM set_config('app.current_user_id', p_user_id::TEXT, true);
+    END IF;
+
+    IF p_user_roles IS NOT NULL THEN
+        PERFORM set_config('app.current_user_roles', p_user_roles, true);
+    END IF;
+
+    PERFORM set_config('app.is_global_admin', p_is_global_admin::TEXT, true);
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION public.clear_tenant_context()
+RETURNS void AS $$
+BEGIN
+    PERFORM set_config('app.current_tenant_id', NULL, true);
+    PERFORM set_config('app.current_user_id', NULL, true);
+    PERFORM set_config('app.current_user_roles', NULL, true);
+    PERFORM set_config('app.is_global_admin', 'false', true);
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION public.get_current_tenant_id()
+RETURNS UUID AS $$
+BEGIN
+    RETURN current_setting('app.current_tenant_id', true)::UUID;
+EXCEPTION
+    WHEN OTHERS THEN
+        RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+COMMENT ON FUNCTION public.set_tenant_context IS 'Sets the current tenant context for RLS policies';
+COMMENT ON FUNCTION public.clear_tenant_context IS 'Clears the current tenant context';
+COMMENT ON FUNCTION public.get_current_tenant_id IS 'Gets the current tenant ID from context';
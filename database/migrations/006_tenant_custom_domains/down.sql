@@ -0,0 +1,16 @@
+-- Migration: 006_tenant_custom_domains (ROLLBACK)
+-- Description: Rollback custom domain verification columns
+-- Author: Comply360 Development Team
+-- Date: 2026-10-18
+
+DROP INDEX IF EXISTS public.idx_tenants_domain_status;
+DROP INDEX IF EXISTS public.idx_tenants_domain;
+
+ALTER TABLE public.tenants DROP CONSTRAINT IF EXISTS valid_domain_status;
+
+ALTER TABLE public.tenants
+    DROP COLUMN IF EXISTS domain_last_error,
+    DROP COLUMN IF EXISTS domain_last_checked_at,
+    DROP COLUMN IF EXISTS domain_verified_at,
+    DROP COLUMN IF EXISTS domain_verification_token,
+    DROP COLUMN IF EXISTS domain_status;
@@ -0,0 +1,28 @@
+-- Migration: 006_tenant_custom_domains
+-- Description: Track custom domain ownership verification for tenants
+-- Author: Comply360 Development Team
+-- Date: 2026-10-18
+
+-- ============================================================================
+-- CUSTOM DOMAIN VERIFICATION
+-- A tenant proves ownership of public.tenants.domain by publishing
+-- "comply360-verification=<token>" as a TXT record on
+-- _comply360-verification.<domain>
+-- ============================================================================
+
+ALTER TABLE public.tenants
+    ADD COLUMN IF NOT EXISTS domain_status VARCHAR(50),
+    ADD COLUMN IF NOT EXISTS domain_verification_token VARCHAR(64),
+    ADD COLUMN IF NOT EXISTS domain_verified_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS domain_last_checked_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS domain_last_error TEXT;
+
+ALTER TABLE public.tenants
+    ADD CONSTRAINT valid_domain_status CHECK (domain_status IS NULL OR domain_status IN ('pending', 'verified', 'failed'));
+
+-- A custom domain can only belong to one live tenant
+CREATE UNIQUE INDEX idx_tenants_domain ON public.tenants(LOWER(domain)) WHERE domain IS NOT NULL AND deleted_at IS NULL;
+CREATE INDEX idx_tenants_domain_status ON public.tenants(domain_status) WHERE domain IS NOT NULL;
+
+COMMENT ON COLUMN public.tenants.domain_status IS 'Custom domain verification status (pending, verified, failed)';
+COMMENT ON COLUMN public.tenants.domain_verification_token IS 'Token expected in the _comply360-verification TXT record';
@@ -0,0 +1,20 @@
+-- Migration: 007_tenant_lifecycle (ROLLBACK)
+-- Description: Rollback tenant lifecycle columns
+-- Author: Comply360 Development Team
+-- Date: 2026-10-18
+
+DROP INDEX IF EXISTS public.idx_tenants_purge_after;
+
+-- Tenants awaiting deletion fall back to suspended
+UPDATE public.tenants SET status = 'suspended' WHERE status = 'pending_deletion';
+
+ALTER TABLE public.tenants DROP CONSTRAINT IF EXISTS valid_status;
+ALTER TABLE public.tenants
+    ADD CONSTRAINT valid_status CHECK (status IN ('active', 'suspended', 'deleted'));
+
+ALTER TABLE public.tenants
+    DROP COLUMN IF EXISTS purged_at,
+    DROP COLUMN IF EXISTS purge_after,
+    DROP COLUMN IF EXISTS deletion_requested_at,
+    DROP COLUMN IF EXISTS suspension_reason,
+    DROP COLUMN IF EXISTS suspended_at;
@@ -0,0 +1,28 @@
+-- Migration: 007_tenant_lifecycle
+-- Description: Track tenant suspension, scheduled deletion and data purge
+-- Author: Comply360 Development Team
+-- Date: 2026-10-18
+
+-- ============================================================================
+-- TENANT LIFECYCLE
+-- active -> suspended -> active
+-- active | suspended -> pending_deletion -> (grace period) -> deleted (purged)
+-- ============================================================================
+
+ALTER TABLE public.tenants
+    ADD COLUMN IF NOT EXISTS suspended_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS suspension_reason TEXT,
+    ADD COLUMN IF NOT EXISTS deletion_requested_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS purge_after TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS purged_at TIMESTAMP;
+
+ALTER TABLE public.tenants DROP CONSTRAINT IF EXISTS valid_status;
+ALTER TABLE public.tenants
+    ADD CONSTRAINT valid_status CHECK (status IN ('active', 'suspended', 'pending_deletion', 'deleted'));
+
+-- Purge job lookup
+CREATE INDEX idx_tenants_purge_after ON public.tenants(purge_after) WHERE status = 'pending_deletion' AND purged_at IS NULL;
+
+COMMENT ON COLUMN public.tenants.suspension_reason IS 'Reason shown to users while the tenant is suspended';
+COMMENT ON COLUMN public.tenants.purge_after IS 'End of the deletion grace period; the purge job removes all tenant data after this';
+COMMENT ON COLUMN public.tenants.purged_at IS 'When tenant data was purged; the deletion certificate is in tenant_audit_log';
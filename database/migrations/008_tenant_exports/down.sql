@@ -0,0 +1,6 @@
+-- Migration: 008_tenant_exports (ROLLBACK)
+-- Description: Rollback tenant export jobs
+-- Author: Comply360 Development Team
+-- Date: 2026-10-18
+
+DROP TABLE IF EXISTS public.tenant_exports CASCADE;
@@ -0,0 +1,50 @@
+-- Migration: 008_tenant_exports
+-- Description: Track asynchronous full tenant data exports
+-- Author: Comply360 Development Team
+-- Date: 2026-10-18
+
+-- ============================================================================
+-- TENANT EXPORTS
+-- One row per export job. The archive lives in object storage until expires_at.
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS public.tenant_exports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES public.tenants(id) ON DELETE CASCADE,
+    format VARCHAR(10) NOT NULL DEFAULT 'jsonl',
+    status VARCHAR(50) NOT NULL DEFAULT 'pending',
+    requested_by UUID,
+
+    -- Progress
+    current_step VARCHAR(100),
+    items_total INT NOT NULL DEFAULT 0,
+    items_done INT NOT NULL DEFAULT 0,
+
+    -- Result
+    object_key TEXT,
+    checksum VARCHAR(64),
+    size_bytes BIGINT,
+    manifest JSONB,
+    last_error TEXT,
+
+    started_at TIMESTAMP,
+    completed_at TIMESTAMP,
+    expires_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT valid_export_format CHECK (format IN ('jsonl', 'csv')),
+    CONSTRAINT valid_export_status CHECK (status IN ('pending', 'running', 'completed', 'failed', 'expired'))
+);
+
+CREATE INDEX idx_tenant_exports_tenant_id ON public.tenant_exports(tenant_id, created_at DESC);
+CREATE INDEX idx_tenant_exports_expires_at ON public.tenant_exports(expires_at) WHERE status = 'completed';
+
+CREATE TRIGGER update_tenant_exports_updated_at
+    BEFORE UPDATE ON public.tenant_exports
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE public.tenant_exports IS 'Asynchronous full tenant data exports (POPIA/GDPR portability)';
+COMMENT ON COLUMN public.tenant_exports.checksum IS 'Hex SHA-256 of the export ZIP';
+COMMENT ON COLUMN public.tenant_exports.manifest IS 'Files in the archive with row counts and checksums';
@@ -0,0 +1,6 @@
+-- Migration: 009_tenant_imports (ROLLBACK)
+-- Description: Rollback tenant import jobs
+-- Author: Comply360 Development Team
+-- Date: 2026-10-18
+
+DROP TABLE IF EXISTS public.tenant_imports CASCADE;
@@ -0,0 +1,50 @@
+-- Migration: 009_tenant_imports
+-- Description: Track tenant imports from export archives and tenant clones
+-- Author: Comply360 Development Team
+-- Date: 2026-10-18
+
+-- ============================================================================
+-- TENANT IMPORTS
+-- One row per import job into a freshly provisioned tenant.
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS public.tenant_imports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES public.tenants(id) ON DELETE CASCADE,
+    source VARCHAR(20) NOT NULL,
+    source_tenant_id UUID REFERENCES public.tenants(id) ON DELETE SET NULL,
+    remap_ids BOOLEAN NOT NULL DEFAULT false,
+    anonymize BOOLEAN NOT NULL DEFAULT false,
+    status VARCHAR(50) NOT NULL DEFAULT 'pending',
+    requested_by UUID,
+
+    -- Progress
+    current_step VARCHAR(100),
+    items_total INT NOT NULL DEFAULT 0,
+    items_done INT NOT NULL DEFAULT 0,
+
+    -- Result
+    object_key TEXT,
+    stats JSONB,
+    validation_errors JSONB,
+    last_error TEXT,
+
+    started_at TIMESTAMP,
+    completed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT valid_import_source CHECK (source IN ('archive', 'clone')),
+    CONSTRAINT valid_import_status CHECK (status IN ('pending', 'running', 'completed', 'failed'))
+);
+
+CREATE INDEX idx_tenant_imports_tenant_id ON public.tenant_imports(tenant_id, created_at DESC);
+
+CREATE TRIGGER update_tenant_imports_updated_at
+    BEFORE UPDATE ON public.tenant_imports
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE public.tenant_imports IS 'Imports of export archives and tenant clones into freshly provisioned tenants';
+COMMENT ON COLUMN public.tenant_imports.object_key IS 'Uploaded archive in object storage, removed once the import finishes';
+COMMENT ON COLUMN public.tenant_imports.validation_errors IS 'Referential integrity and format errors that stopped the import';
@@ -0,0 +1,50 @@
+-- Migration: 010_tenant_hierarchy (ROLLBACK)
+-- Description: Rollback tenant hierarchy and delegated tenant context
+-- Author: Comply360 Development Team
+-- Date: 2026-10-18
+
+DROP FUNCTION IF EXISTS public.set_tenant_context(UUID, UUID, TEXT, BOOLEAN, UUID);
+
+CREATE OR REPLACE FUNCTION public.set_tenant_context(p_tenant_id UUID, p_user_id UUID DEFAULT NULL, p_user_roles TEXT DEFAULT NULL, p_is_global_admin BOOLEAN DEFAULT false)
+RETURNS void AS $$
+BEGIN
+    PERFORM set_config('app.current_tenant_id', p_tenant_id::TEXT, true);
+
+    IF p_user_id IS NOT NULL THEN
+        PERFORM set_config('app.current_user_id', p_user_id::TEXT, true);
+    END IF;
+
+    IF p_user_roles IS NOT NULL THEN
+        PERFORM set_config('app.current_user_roles', p_user_roles, true);
+    END IF;
+
+    PERFORM set_config('app.is_global_admin', p_is_global_admin::TEXT, true);
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION public.clear_tenant_context()
+RETURNS void AS $$
+BEGIN
+    PERFORM set_config('app.current_tenant_id', NULL, true);
+    PERFORM set_config('app.current_user_id', NULL, true);
+    PERFORM set_config('app.current_user_roles', NULL, true);
+    PERFORM set_config('app.is_global_admin', 'false', true);
+END;
+$$ LANGUAGE plpgsql;
+
+COMMENT ON FUNCTION public.set_tenant_context IS 'Sets the current tenant context for RLS policies';
+
+DROP TRIGGER IF EXISTS check_tenant_hierarchy ON public.tenants;
+DROP FUNCTION IF EXISTS public.check_tenant_hierarchy();
+DROP FUNCTION IF EXISTS public.tenant_descendants(UUID);
+DROP FUNCTION IF EXISTS public.tenant_ancestors(UUID);
+
+DROP INDEX IF EXISTS public.idx_tenants_parent_tenant_id;
+
+ALTER TABLE public.tenants
+    DROP CONSTRAINT IF EXISTS valid_parent_override_rate,
+    DROP CONSTRAINT IF EXISTS valid_parent_tenant;
+
+ALTER TABLE public.tenants
+    DROP COLUMN IF EXISTS parent_override_rate,
+    DROP COLUMN IF EXISTS parent_tenant_id;
@@ -0,0 +1,136 @@
+-- Migration: 010_tenant_hierarchy
+-- Description: Parent/child tenant relationships for master agents and sub-agents
+-- Author: Comply360 Development Team
+-- Date: 2026-10-18
+
+-- ============================================================================
+-- TENANT HIERARCHY
+-- A master agent tenant may have sub-agent tenants, which may have their own.
+-- Children inherit settings and branding, and the parent earns an override on
+-- every commission a child records.
+-- ============================================================================
+
+ALTER TABLE public.tenants
+    ADD COLUMN IF NOT EXISTS parent_tenant_id UUID REFERENCES public.tenants(id),
+    ADD COLUMN IF NOT EXISTS parent_override_rate DECIMAL(5,2);
+
+ALTER TABLE public.tenants
+    ADD CONSTRAINT valid_parent_tenant CHECK (parent_tenant_id IS NULL OR parent_tenant_id <> id),
+    ADD CONSTRAINT valid_parent_override_rate CHECK (
+        parent_override_rate IS NULL
+        OR (parent_tenant_id IS NOT NULL AND parent_override_rate >= 0 AND parent_override_rate <= 100)
+    );
+
+CREATE INDEX idx_tenants_parent_tenant_id ON public.tenants(parent_tenant_id) WHERE parent_tenant_id IS NOT NULL;
+
+COMMENT ON COLUMN public.tenants.parent_tenant_id IS 'Master agent tenant this tenant is a sub-agent of';
+COMMENT ON COLUMN public.tenants.parent_override_rate IS 'Percentage of each registration fee paid to the parent on commissions recorded by this tenant';
+
+-- ============================================================================
+-- HIERARCHY FUNCTIONS
+-- ============================================================================
+
+-- Ancestors of a tenant, nearest first
+CREATE OR REPLACE FUNCTION public.tenant_ancestors(p_tenant_id UUID)
+RETURNS TABLE (tenant_id UUID, depth INT) AS $$
+    WITH RECURSIVE ancestors AS (
+        SELECT t.parent_tenant_id AS tenant_id, 1 AS depth
+        FROM public.tenants t
+        WHERE t.id = p_tenant_id AND t.parent_tenant_id IS NOT NULL
+        UNION ALL
+        SELECT t.parent_tenant_id, a.depth + 1
+        FROM public.tenants t
+        JOIN ancestors a ON t.id = a.tenant_id
+        WHERE t.parent_tenant_id IS NOT NULL AND a.depth < 32
+    )
+    SELECT tenant_id, depth FROM ancestors;
+$$ LANGUAGE sql STABLE;
+
+-- Live descendants of a tenant, nearest first
+CREATE OR REPLACE FUNCTION public.tenant_descendants(p_tenant_id UUID)
+RETURNS TABLE (tenant_id UUID, depth INT) AS $$
+    WITH RECURSIVE descendants AS (
+        SELECT t.id AS tenant_id, 1 AS depth
+        FROM public.tenants t
+        WHERE t.parent_tenant_id = p_tenant_id AND t.deleted_at IS NULL
+        UNION ALL
+        SELECT t.id, d.depth + 1
+        FROM public.tenants t
+        JOIN descendants d ON t.parent_tenant_id = d.tenant_id
+        WHERE t.deleted_at IS NULL AND d.depth < 32
+    )
+    SELECT tenant_id, depth FROM descendants;
+$$ LANGUAGE sql STABLE;
+
+-- Reject parents that would make a tenant its own ancestor
+CREATE OR REPLACE FUNCTION public.check_tenant_hierarchy()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.parent_tenant_id IS NULL THEN
+        RETURN NEW;
+    END IF;
+
+    IF NEW.parent_tenant_id = NEW.id
+       OR EXISTS (SELECT 1 FROM public.tenant_ancestors(NEW.parent_tenant_id) a WHERE a.tenant_id = NEW.id) THEN
+        RAISE EXCEPTION 'tenant % cannot be a descendant of itself', NEW.id
+            USING ERRCODE = 'check_violation';
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER check_tenant_hierarchy
+    BEFORE INSERT OR UPDATE OF parent_tenant_id ON public.tenants
+    FOR EACH ROW EXECUTE FUNCTION public.check_tenant_hierarchy();
+
+-- ============================================================================
+-- DELEGATED TENANT CONTEXT
+-- A user of a parent tenant acting on a child runs with the child's tenant
+-- context, so the tenant isolation policies are unchanged. The acting tenant is
+-- recorded and checked here: it must be an ancestor of the target tenant unless
+-- the user is a global admin.
+-- ============================================================================
+
+DROP FUNCTION IF EXISTS public.set_tenant_context(UUID, UUID, TEXT, BOOLEAN);
+
+CREATE OR REPLACE FUNCTION public.set_tenant_context(p_tenant_id UUID, p_user_id UUID DEFAULT NULL, p_user_roles TEXT DEFAULT NULL, p_is_global_admin BOOLEAN DEFAULT false, p_acting_tenant_id UUID DEFAULT NULL)
+RETURNS void AS $$
+BEGIN
+    IF p_acting_tenant_id IS NOT NULL
+       AND p_acting_tenant_id <> p_tenant_id
+       AND NOT p_is_global_admin
+       AND NOT EXISTS (SELECT 1 FROM public.tenant_ancestors(p_tenant_id) a WHERE a.tenant_id = p_acting_tenant_id) THEN
+        RAISE EXCEPTION 'tenant % may not act on tenant %', p_acting_tenant_id, p_tenant_id
+            USING ERRCODE = 'insufficient_privilege';
+    END IF;
+
+    PERFORM set_config('app.current_tenant_id', p_tenant_id::TEXT, true);
+
+    IF p_user_id IS NOT NULL THEN
+        PERFORM set_config('app.current_user_id', p_user_id::TEXT, true);
+    END IF;
+
+    IF p_user_roles IS NOT NULL THEN
+        PERFORM set_config('app.current_user_roles', p_user_roles, true);
+    END IF;
+
+    PERFORM set_config('app.is_global_admin', p_is_global_admin::TEXT, true);
+    PERFORM set_config('app.acting_tenant_id', COALESCE(p_acting_tenant_id::TEXT, ''), true);
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION public.clear_tenant_context()
+RETURNS void AS $$
+BEGIN
+    PERFORM set_config('app.current_tenant_id', NULL, true);
+    PERFORM set_config('app.current_user_id', NULL, true);
+    PERFORM set_config('app.current_user_roles', NULL, true);
+    PERFORM set_config('app.is_global_admin', 'false', true);
+    PERFORM set_config('app.acting_tenant_id', '', true);
+END;
+$$ LANGUAGE plpgsql;
+
+COMMENT ON FUNCTION public.set_tenant_context IS 'Sets the current tenant context for RLS policies; a parent tenant may act on its descendants';
+COMMENT ON FUNCTION public.tenant_ancestors IS 'Ancestors of a tenant, nearest first';
+COMMENT ON FUNCTION public.tenant_descendants IS 'Live descendants of a tenant, nearest first';
@@ -0,0 +1,9 @@
+-- Migration: 011_commission_overrides (ROLLBACK)
+-- Description: Rollback commission overrides
+-- Author: Comply360 Development Team
+-- Date: 2026-10-18
+-- Note: This will be executed in the context of a specific tenant schema
+
+DROP POLICY IF EXISTS tenant_isolation_policy_commission_overrides ON commission_overrides;
+DROP TRIGGER IF EXISTS update_commission_overrides_updated_at ON commission_overrides;
+DROP TABLE IF EXISTS commission_overrides;
@@ -0,0 +1,56 @@
+-- Migration: 011_commission_overrides
+-- Description: Override commissions earned by a parent tenant on its sub-agents' commissions
+-- Author: Comply360 Development Team
+-- Date: 2026-10-18
+-- Scope: tenant
+
+-- ============================================================================
+-- COMMISSION OVERRIDES (Per Tenant)
+-- Recorded in the parent's schema whenever a child tenant records a commission.
+-- The source registration and commission live in the child's schema, so they
+-- are referenced by ID only.
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS commission_overrides (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL,
+
+    -- Source commission in the child tenant
+    source_tenant_id UUID NOT NULL,
+    source_commission_id UUID NOT NULL,
+    source_registration_id UUID NOT NULL,
+
+    -- Override details
+    registration_fee DECIMAL(10,2) NOT NULL,
+    override_rate DECIMAL(5,2) NOT NULL, -- percentage
+    override_amount DECIMAL(10,2) NOT NULL,
+    currency VARCHAR(3) NOT NULL DEFAULT 'ZAR',
+
+    -- Status follows the source commission
+    status VARCHAR(50) NOT NULL DEFAULT 'pending',
+
+    -- Timestamps
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    UNIQUE(source_tenant_id, source_commission_id),
+    CONSTRAINT valid_commission_override_status CHECK (status IN ('pending', 'approved', 'paid', 'cancelled'))
+);
+
+CREATE INDEX idx_commission_overrides_tenant_id ON commission_overrides(tenant_id);
+CREATE INDEX idx_commission_overrides_source_tenant_id ON commission_overrides(source_tenant_id);
+CREATE INDEX idx_commission_overrides_status ON commission_overrides(status);
+
+CREATE TRIGGER update_commission_overrides_updated_at
+    BEFORE UPDATE ON commission_overrides
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+ALTER TABLE commission_overrides ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_policy_commission_overrides ON commission_overrides
+    FOR ALL
+    USING (tenant_id = current_setting('app.current_tenant_id', true)::UUID)
+    WITH CHECK (tenant_id = current_setting('app.current_tenant_id', true)::UUID);
+
+COMMENT ON TABLE commission_overrides IS 'Override commissions earned on sub-agent tenant commissions';
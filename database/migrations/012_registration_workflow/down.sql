@@ -0,0 +1,18 @@
+-- Migration: 012_registration_workflow (ROLLBACK)
+-- Description: Rollback registration workflows and status history
+-- Author: Comply360 Development Team
+-- Date: 2026-10-18
+-- Note: This will be executed in the context of a specific tenant schema
+
+DROP POLICY IF EXISTS tenant_isolation_policy_registration_status_history ON registration_status_history;
+DROP TABLE IF EXISTS registration_status_history;
+
+UPDATE registrations SET status = 'approved' WHERE status = 'completed';
+ALTER TABLE registrations DROP CONSTRAINT IF EXISTS valid_registration_status;
+ALTER TABLE registrations ADD CONSTRAINT valid_registration_status
+    CHECK (status IN ('draft', 'submitted', 'in_review', 'approved', 'rejected', 'cancelled'));
+
+ALTER TABLE registrations
+    DROP COLUMN IF EXISTS invoice_paid_at,
+    DROP COLUMN IF EXISTS name_reserved_at,
+    DROP COLUMN IF EXISTS workflow_version;
@@ -0,0 +1,55 @@
+-- Migration: 012_registration_workflow
+-- Description: Versioned registration workflows, guard facts and status history
+-- Author: Comply360 Development Team
+-- Date: 2026-10-18
+-- Scope: tenant
+
+-- ============================================================================
+-- REGISTRATIONS (Per Tenant)
+-- Registrations keep the workflow version they were created with. The guard
+-- timestamps are set by name reservation and billing.
+-- ============================================================================
+
+ALTER TABLE registrations
+    ADD COLUMN IF NOT EXISTS workflow_version INT NOT NULL DEFAULT 1,
+    ADD COLUMN IF NOT EXISTS name_reserved_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS invoice_paid_at TIMESTAMP;
+
+ALTER TABLE registrations DROP CONSTRAINT IF EXISTS valid_registration_status;
+ALTER TABLE registrations ADD CONSTRAINT valid_registration_status
+    CHECK (status IN ('draft', 'submitted', 'in_review', 'approved', 'rejected', 'cancelled', 'completed'));
+
+-- ============================================================================
+-- REGISTRATION STATUS HISTORY (Per Tenant)
+-- One row per status change, including the initial status on creation.
+-- changed_by is not a foreign key because platform operators act on tenants
+-- without a user row in the tenant schema.
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS registration_status_history (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL,
+    registration_id UUID NOT NULL REFERENCES registrations(id) ON DELETE CASCADE,
+
+    -- Transition
+    from_status VARCHAR(50),
+    to_status VARCHAR(50) NOT NULL,
+    workflow_version INT NOT NULL,
+    changed_by UUID,
+    reason TEXT,
+
+    -- Timestamps
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_registration_status_history_registration_id ON registration_status_history(registration_id, created_at);
+CREATE INDEX idx_registration_status_history_tenant_id ON registration_status_history(tenant_id);
+
+ALTER TABLE registration_status_history ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_policy_registration_status_history ON registration_status_history
+    FOR ALL
+    USING (tenant_id = current_setting('app.current_tenant_id', true)::UUID)
+    WITH CHECK (tenant_id = current_setting('app.current_tenant_id', true)::UUID);
+
+COMMENT ON TABLE registration_status_history IS 'Status transitions of registrations';
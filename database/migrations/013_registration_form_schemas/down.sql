@@ -0,0 +1,7 @@
+-- Migration: 013_registration_form_schemas (ROLLBACK)
+-- Description: Rollback registration form schema versions
+-- Author: Comply360 Development Team
+-- Date: 2026-10-18
+-- Note: This will be executed in the context of a specific tenant schema
+
+ALTER TABLE registrations DROP COLUMN IF EXISTS form_schema_version;
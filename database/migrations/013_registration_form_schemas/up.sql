@@ -0,0 +1,15 @@
+-- Migration: 013_registration_form_schemas
+-- Description: Record the form schema version registration form_data was validated against
+-- Author: Comply360 Development Team
+-- Date: 2026-10-18
+-- Scope: tenant
+
+-- ============================================================================
+-- REGISTRATIONS (Per Tenant)
+-- NULL for registrations created before form schemas; their form_data is not
+-- validated.
+-- ============================================================================
+
+ALTER TABLE registrations ADD COLUMN IF NOT EXISTS form_schema_version INT;
+
+COMMENT ON COLUMN registrations.form_schema_version IS 'Version of the form schema for the registration type and jurisdiction';
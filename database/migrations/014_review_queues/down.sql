@@ -0,0 +1,28 @@
+-- Migration: 014_review_queues (ROLLBACK)
+-- Description: Rollback reviewer work queues, assignment audit trail and SLA targets
+-- Author: Comply360 Development Team
+-- Date: 2026-10-18
+-- Note: This will be executed in the context of a specific tenant schema
+
+DROP POLICY IF EXISTS tenant_isolation_policy_sla_targets ON sla_targets;
+DROP TRIGGER IF EXISTS update_sla_targets_updated_at ON sla_targets;
+DROP TABLE IF EXISTS sla_targets;
+
+DROP POLICY IF EXISTS tenant_isolation_policy_registration_assignments ON registration_assignments;
+DROP TABLE IF EXISTS registration_assignments;
+
+DROP INDEX IF EXISTS idx_registrations_sla_due_at;
+DROP INDEX IF EXISTS idx_registrations_queue_id;
+ALTER TABLE registrations
+    DROP COLUMN IF EXISTS sla_breached_at,
+    DROP COLUMN IF EXISTS sla_due_at,
+    DROP COLUMN IF EXISTS status_changed_at,
+    DROP COLUMN IF EXISTS assigned_at,
+    DROP COLUMN IF EXISTS queue_id;
+
+DROP POLICY IF EXISTS tenant_isolation_policy_review_queue_members ON review_queue_members;
+DROP TABLE IF EXISTS review_queue_members;
+
+DROP POLICY IF EXISTS tenant_isolation_policy_review_queues ON review_queues;
+DROP TRIGGER IF EXISTS update_review_queues_updated_at ON review_queues;
+DROP TABLE IF EXISTS review_queues;
@@ -0,0 +1,177 @@
+-- Migration: 014_review_queues
+-- Description: Reviewer work queues, assignment audit trail and SLA targets
+-- Author: Comply360 Development Team
+-- Date: 2026-10-18
+-- Scope: tenant
+
+-- ============================================================================
+-- REVIEW QUEUES (Per Tenant)
+-- Submitted registrations are routed to the active queue that lists their
+-- registration type, or else to a queue with no types listed, and assigned to
+-- one of its members by the queue's strategy.
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS review_queues (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL,
+
+    -- Queue details
+    name VARCHAR(255) NOT NULL,
+    strategy VARCHAR(50) NOT NULL DEFAULT 'round_robin',
+    registration_types TEXT[] NOT NULL DEFAULT '{}',
+    is_active BOOLEAN NOT NULL DEFAULT true,
+
+    -- Round-robin position
+    last_assigned_to UUID,
+
+    -- Timestamps
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT unique_review_queue_name UNIQUE (tenant_id, name),
+    CONSTRAINT valid_review_queue_strategy CHECK (strategy IN ('round_robin', 'least_loaded', 'skill'))
+);
+
+CREATE INDEX idx_review_queues_tenant_id ON review_queues(tenant_id);
+
+CREATE TRIGGER update_review_queues_updated_at BEFORE UPDATE ON review_queues
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+ALTER TABLE review_queues ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_policy_review_queues ON review_queues
+    FOR ALL
+    USING (tenant_id = current_setting('app.current_tenant_id', true)::UUID)
+    WITH CHECK (tenant_id = current_setting('app.current_tenant_id', true)::UUID);
+
+COMMENT ON TABLE review_queues IS 'Reviewer work queues and their assignment strategy';
+
+-- ============================================================================
+-- REVIEW QUEUE MEMBERS (Per Tenant)
+-- skills lists the registration types a reviewer handles under the skill
+-- strategy. A member at max_load open registrations is skipped.
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS review_queue_members (
+    queue_id UUID NOT NULL REFERENCES review_queues(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    tenant_id UUID NOT NULL,
+
+    -- Membership
+    skills TEXT[] NOT NULL DEFAULT '{}',
+    max_load INT,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+
+    -- Timestamps
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (queue_id, user_id),
+    CONSTRAINT valid_review_queue_max_load CHECK (max_load IS NULL OR max_load > 0)
+);
+
+CREATE INDEX idx_review_queue_members_user_id ON review_queue_members(user_id);
+CREATE INDEX idx_review_queue_members_tenant_id ON review_queue_members(tenant_id);
+
+ALTER TABLE review_queue_members ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_policy_review_queue_members ON review_queue_members
+    FOR ALL
+    USING (tenant_id = current_setting('app.current_tenant_id', true)::UUID)
+    WITH CHECK (tenant_id = current_setting('app.current_tenant_id', true)::UUID);
+
+COMMENT ON TABLE review_queue_members IS 'Reviewers of a work queue';
+
+-- ============================================================================
+-- REGISTRATIONS (Per Tenant)
+-- status_changed_at starts the SLA clock of the current status; sla_due_at is
+-- when the status's SLA target runs out, and sla_breached_at when the breach
+-- was detected.
+-- ============================================================================
+
+ALTER TABLE registrations
+    ADD COLUMN IF NOT EXISTS queue_id UUID REFERENCES review_queues(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS assigned_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS status_changed_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS sla_due_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS sla_breached_at TIMESTAMP;
+
+UPDATE registrations SET status_changed_at = updated_at WHERE status_changed_at IS NULL;
+
+CREATE INDEX idx_registrations_queue_id ON registrations(queue_id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_registrations_sla_due_at ON registrations(sla_due_at)
+    WHERE deleted_at IS NULL AND sla_due_at IS NOT NULL;
+
+-- ============================================================================
+-- REGISTRATION ASSIGNMENTS (Per Tenant)
+-- Audit trail of every assignment, automatic or manual. assigned_by is not a
+-- foreign key because platform operators act without a tenant user row.
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS registration_assignments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL,
+    registration_id UUID NOT NULL REFERENCES registrations(id) ON DELETE CASCADE,
+    queue_id UUID REFERENCES review_queues(id) ON DELETE SET NULL,
+
+    -- Assignment
+    from_user UUID,
+    to_user UUID,
+    method VARCHAR(20) NOT NULL,
+    strategy VARCHAR(50),
+    assigned_by UUID,
+    reason TEXT,
+
+    -- Timestamps
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT valid_assignment_method CHECK (method IN ('auto', 'manual'))
+);
+
+CREATE INDEX idx_registration_assignments_registration_id ON registration_assignments(registration_id, created_at);
+CREATE INDEX idx_registration_assignments_tenant_id ON registration_assignments(tenant_id);
+
+ALTER TABLE registration_assignments ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_policy_registration_assignments ON registration_assignments
+    FOR ALL
+    USING (tenant_id = current_setting('app.current_tenant_id', true)::UUID)
+    WITH CHECK (tenant_id = current_setting('app.current_tenant_id', true)::UUID);
+
+COMMENT ON TABLE registration_assignments IS 'Reviewer assignments of registrations';
+
+-- ============================================================================
+-- SLA TARGETS (Per Tenant)
+-- How long a registration may stay in a status. A target for a registration
+-- type overrides the target for every type (registration_type NULL).
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS sla_targets (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL,
+
+    -- Target
+    status VARCHAR(50) NOT NULL,
+    registration_type VARCHAR(100),
+    target_hours INT NOT NULL,
+    business_hours BOOLEAN NOT NULL DEFAULT true,
+
+    -- Timestamps
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT valid_sla_target_hours CHECK (target_hours > 0)
+);
+
+CREATE UNIQUE INDEX idx_sla_targets_status_type ON sla_targets(tenant_id, status, COALESCE(registration_type, ''));
+
+CREATE TRIGGER update_sla_targets_updated_at BEFORE UPDATE ON sla_targets
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+ALTER TABLE sla_targets ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_policy_sla_targets ON sla_targets
+    FOR ALL
+    USING (tenant_id = current_setting('app.current_tenant_id', true)::UUID)
+    WITH CHECK (tenant_id = current_setting('app.current_tenant_id', true)::UUID);
+
+COMMENT ON TABLE sla_targets IS 'Time allowed in each registration status';
@@ -0,0 +1,20 @@
+-- Migration: 015_search (ROLLBACK)
+-- Description: Rollback full-text and trigram search over registrations and clients
+-- Author: Comply360 Development Team
+-- Date: 2026-10-18
+-- Note: This will be executed in the context of a specific tenant schema
+-- Note: pg_trgm is left installed; other tenant schemas may still use it
+
+DROP INDEX IF EXISTS idx_clients_id_number_trgm;
+DROP INDEX IF EXISTS idx_clients_email_trgm;
+DROP INDEX IF EXISTS idx_clients_company_name_trgm;
+DROP INDEX IF EXISTS idx_clients_full_name_trgm;
+DROP INDEX IF EXISTS idx_clients_search_vector;
+ALTER TABLE clients DROP COLUMN IF EXISTS search_vector;
+
+DROP INDEX IF EXISTS idx_registrations_dcip_reference_trgm;
+DROP INDEX IF EXISTS idx_registrations_cipc_reference_trgm;
+DROP INDEX IF EXISTS idx_registrations_registration_number_trgm;
+DROP INDEX IF EXISTS idx_registrations_company_name_trgm;
+DROP INDEX IF EXISTS idx_registrations_search_vector;
+ALTER TABLE registrations DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1,54 @@
+-- Migration: 015_search
+-- Description: Full-text and trigram search over registrations and clients
+-- Author: Comply360 Development Team
+-- Date: 2026-10-18
+-- Scope: tenant
+
+-- Trigram matching for misspelt names and partial reference numbers. The
+-- extension is database-wide, so it lives in public for every tenant schema.
+CREATE EXTENSION IF NOT EXISTS pg_trgm WITH SCHEMA public;
+
+-- ============================================================================
+-- REGISTRATIONS (Per Tenant)
+-- The company name ranks above reference numbers. The simple configuration
+-- keeps names and numbers as typed rather than stemming them as English.
+-- ============================================================================
+
+ALTER TABLE registrations ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', coalesce(company_name, '')), 'A') ||
+        setweight(to_tsvector('simple',
+            coalesce(registration_number, '') || ' ' ||
+            coalesce(cipc_reference, '') || ' ' ||
+            coalesce(dcip_reference, '')), 'B')
+    ) STORED;
+
+CREATE INDEX idx_registrations_search_vector ON registrations USING GIN (search_vector);
+CREATE INDEX idx_registrations_company_name_trgm ON registrations USING GIN (company_name gin_trgm_ops);
+CREATE INDEX idx_registrations_registration_number_trgm ON registrations USING GIN (registration_number gin_trgm_ops);
+CREATE INDEX idx_registrations_cipc_reference_trgm ON registrations USING GIN (cipc_reference gin_trgm_ops);
+CREATE INDEX idx_registrations_dcip_reference_trgm ON registrations USING GIN (dcip_reference gin_trgm_ops);
+
+-- ============================================================================
+-- CLIENTS (Per Tenant)
+-- Names rank above email addresses and identity numbers.
+-- ============================================================================
+
+ALTER TABLE clients ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', coalesce(full_name, '') || ' ' || coalesce(company_name, '')), 'A') ||
+        setweight(to_tsvector('simple',
+            coalesce(email, '') || ' ' ||
+            coalesce(id_number, '') || ' ' ||
+            coalesce(passport_number, '') || ' ' ||
+            coalesce(tax_number, '')), 'B')
+    ) STORED;
+
+CREATE INDEX idx_clients_search_vector ON clients USING GIN (search_vector);
+CREATE INDEX idx_clients_full_name_trgm ON clients USING GIN (full_name gin_trgm_ops);
+CREATE INDEX idx_clients_company_name_trgm ON clients USING GIN (company_name gin_trgm_ops);
+CREATE INDEX idx_clients_email_trgm ON clients USING GIN (email gin_trgm_ops);
+CREATE INDEX idx_clients_id_number_trgm ON clients USING GIN (id_number gin_trgm_ops);
+
+COMMENT ON COLUMN registrations.search_vector IS 'Full-text search document of the company name and reference numbers';
+COMMENT ON COLUMN clients.search_vector IS 'Full-text search document of the client names, email and identity numbers';
@@ -0,0 +1,9 @@
+-- Migration: 016_list_pagination (ROLLBACK)
+-- Description: Rollback indexes for keyset pagination of registration, commission and document lists
+-- Author: Comply360 Development Team
+-- Date: 2026-10-18
+-- Note: This will be executed in the context of a specific tenant schema
+
+DROP INDEX IF EXISTS idx_documents_tenant_created_at_id;
+DROP INDEX IF EXISTS idx_commissions_tenant_created_at_id;
+DROP INDEX IF EXISTS idx_registrations_tenant_created_at_id;
@@ -0,0 +1,11 @@
+-- Migration: 016_list_pagination
+-- Description: Indexes for keyset pagination of registration, commission and document lists
+-- Author: Comply360 Development Team
+-- Date: 2026-10-18
+-- Scope: tenant
+
+-- Lists are read newest first, continuing after the (created_at, id) of the
+-- last row of the previous page
+CREATE INDEX idx_registrations_tenant_created_at_id ON registrations(tenant_id, created_at DESC, id DESC) WHERE deleted_at IS NULL;
+CREATE INDEX idx_commissions_tenant_created_at_id ON commissions(tenant_id, created_at DESC, id DESC);
+CREATE INDEX idx_documents_tenant_created_at_id ON documents(tenant_id, created_at DESC, id DESC) WHERE deleted_at IS NULL;
@@ -0,0 +1,13 @@
+-- Migration: 017_registration_imports (ROLLBACK)
+-- Description: Rollback bulk registration imports from CSV/XLSX files and saved column mappings
+-- Author: Comply360 Development Team
+-- Date: 2026-10-18
+-- Note: This will be executed in the context of a specific tenant schema
+
+DROP POLICY IF EXISTS tenant_isolation_policy_registration_imports ON registration_imports;
+DROP TRIGGER IF EXISTS update_registration_imports_updated_at ON registration_imports;
+DROP TABLE IF EXISTS registration_imports;
+
+DROP POLICY IF EXISTS tenant_isolation_policy_registration_import_mappings ON registration_import_mappings;
+DROP TRIGGER IF EXISTS update_registration_import_mappings_updated_at ON registration_import_mappings;
+DROP TABLE IF EXISTS registration_import_mappings;
@@ -0,0 +1,97 @@
+-- Migration: 017_registration_imports
+-- Description: Bulk registration imports from CSV/XLSX files and saved column mappings
+-- Author: Comply360 Development Team
+-- Date: 2026-10-18
+-- Scope: tenant
+
+-- ============================================================================
+-- REGISTRATION IMPORT MAPPINGS (Per Tenant)
+-- columns maps spreadsheet column headers to import fields such as
+-- "client.email" or "registration.form_data.registered_address.city";
+-- defaults gives fields a value for rows that leave them empty.
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS registration_import_mappings (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL,
+
+    -- Mapping
+    name VARCHAR(255) NOT NULL,
+    columns JSONB NOT NULL DEFAULT '{}'::jsonb,
+    defaults JSONB NOT NULL DEFAULT '{}'::jsonb,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+
+    -- Timestamps
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT unique_registration_import_mapping_name UNIQUE (tenant_id, name)
+);
+
+CREATE INDEX idx_registration_import_mappings_tenant_id ON registration_import_mappings(tenant_id);
+
+CREATE TRIGGER update_registration_import_mappings_updated_at BEFORE UPDATE ON registration_import_mappings
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+ALTER TABLE registration_import_mappings ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_policy_registration_import_mappings ON registration_import_mappings
+    FOR ALL
+    USING (tenant_id = current_setting('app.current_tenant_id', true)::UUID)
+    WITH CHECK (tenant_id = current_setting('app.current_tenant_id', true)::UUID);
+
+COMMENT ON TABLE registration_import_mappings IS 'Saved spreadsheet column mappings for registration imports';
+
+-- ============================================================================
+-- REGISTRATION IMPORTS (Per Tenant)
+-- One row per import job. Rows that fail validation are skipped and listed in
+-- errors; a dry run validates every row without creating anything.
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS registration_imports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL,
+    mapping_id UUID REFERENCES registration_import_mappings(id) ON DELETE SET NULL,
+
+    -- Source file
+    file_name VARCHAR(255) NOT NULL,
+    format VARCHAR(10) NOT NULL,
+    dry_run BOOLEAN NOT NULL DEFAULT false,
+    status VARCHAR(50) NOT NULL DEFAULT 'pending',
+    requested_by UUID REFERENCES users(id) ON DELETE SET NULL,
+
+    -- Progress
+    rows_total INT NOT NULL DEFAULT 0,
+    rows_done INT NOT NULL DEFAULT 0,
+
+    -- Result
+    stats JSONB,
+    errors JSONB NOT NULL DEFAULT '[]'::jsonb,
+    error_count INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+
+    -- Timestamps
+    started_at TIMESTAMP,
+    completed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT valid_registration_import_format CHECK (format IN ('csv', 'xlsx')),
+    CONSTRAINT valid_registration_import_status CHECK (status IN ('pending', 'running', 'completed', 'failed'))
+);
+
+CREATE INDEX idx_registration_imports_tenant_id ON registration_imports(tenant_id, created_at DESC);
+CREATE INDEX idx_registration_imports_status ON registration_imports(status) WHERE status IN ('pending', 'running');
+
+CREATE TRIGGER update_registration_imports_updated_at BEFORE UPDATE ON registration_imports
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+ALTER TABLE registration_imports ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_policy_registration_imports ON registration_imports
+    FOR ALL
+    USING (tenant_id = current_setting('app.current_tenant_id', true)::UUID)
+    WITH CHECK (tenant_id = current_setting('app.current_tenant_id', true)::UUID);
+
+COMMENT ON TABLE registration_imports IS 'Bulk imports of clients and registrations from CSV/XLSX files';
+COMMENT ON COLUMN registration_imports.errors IS 'Row-level validation and creation errors, served as the error report';
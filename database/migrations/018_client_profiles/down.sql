@@ -0,0 +1,17 @@
+-- Migration: 018_client_profiles (ROLLBACK)
+-- Description: Rollback client VAT numbers, user links, duplicate matching and merges
+-- Author: Comply360 Development Team
+-- Date: 2026-10-18
+-- Note: This will be executed in the context of a specific tenant schema
+
+DROP POLICY IF EXISTS tenant_isolation_policy_client_merges ON client_merges;
+DROP TABLE IF EXISTS client_merges;
+
+DROP INDEX IF EXISTS idx_clients_user_id_unique;
+DROP INDEX IF EXISTS idx_clients_company_name_key;
+DROP INDEX IF EXISTS idx_clients_email_match;
+DROP INDEX IF EXISTS idx_clients_id_number_match;
+
+ALTER TABLE clients DROP COLUMN IF EXISTS company_name_key;
+ALTER TABLE clients DROP COLUMN IF EXISTS merged_into;
+ALTER TABLE clients DROP COLUMN IF EXISTS vat_number;
@@ -0,0 +1,74 @@
+-- Migration: 018_client_profiles
+-- Description: Client VAT numbers, user links, duplicate matching and merges
+-- Author: Comply360 Development Team
+-- Date: 2026-10-18
+-- Scope: tenant
+
+-- ============================================================================
+-- CLIENTS (Per Tenant)
+-- company_name_key is the company name without case, punctuation or legal
+-- suffixes such as "(Pty) Ltd", so "Nkosi Holdings (Pty) Ltd" and
+-- "NKOSI HOLDINGS" match as duplicates. The registration service computes the
+-- same key when looking for duplicates.
+-- ============================================================================
+
+ALTER TABLE clients ADD COLUMN IF NOT EXISTS vat_number VARCHAR(20);
+ALTER TABLE clients ADD COLUMN IF NOT EXISTS merged_into UUID REFERENCES clients(id);
+
+ALTER TABLE clients ADD COLUMN IF NOT EXISTS company_name_key VARCHAR(255)
+    GENERATED ALWAYS AS (
+        regexp_replace(
+            btrim(regexp_replace(lower(coalesce(company_name, '')), '[^a-z0-9]+', ' ', 'g')),
+            '( (proprietary|pty|limited|ltd|cc|inc|npc|rf|soc))+$', '')
+    ) STORED;
+
+-- Imports stored VAT numbers in metadata before the column existed
+UPDATE clients
+SET vat_number = metadata->>'vat_number'
+WHERE vat_number IS NULL AND metadata ? 'vat_number';
+
+-- Duplicate matching
+CREATE INDEX idx_clients_id_number_match ON clients(tenant_id, (upper(regexp_replace(id_number, '[^0-9A-Za-z]', '', 'g'))))
+    WHERE deleted_at IS NULL AND id_number IS NOT NULL;
+CREATE INDEX idx_clients_email_match ON clients(tenant_id, (lower(email))) WHERE deleted_at IS NULL;
+CREATE INDEX idx_clients_company_name_key ON clients(tenant_id, company_name_key)
+    WHERE deleted_at IS NULL AND company_name_key <> '';
+
+-- A user account signs in as at most one client
+CREATE UNIQUE INDEX idx_clients_user_id_unique ON clients(user_id) WHERE deleted_at IS NULL AND user_id IS NOT NULL;
+
+COMMENT ON COLUMN clients.company_name_key IS 'Company name without case, punctuation or legal suffixes, for duplicate matching';
+COMMENT ON COLUMN clients.merged_into IS 'Client this client was merged into';
+
+-- ============================================================================
+-- CLIENT MERGES (Per Tenant)
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS client_merges (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL,
+    source_client_id UUID NOT NULL REFERENCES clients(id),
+    target_client_id UUID NOT NULL REFERENCES clients(id),
+
+    -- What moved
+    registrations_moved INT NOT NULL DEFAULT 0,
+    documents_moved INT NOT NULL DEFAULT 0,
+
+    merged_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT client_merge_distinct CHECK (source_client_id <> target_client_id)
+);
+
+CREATE INDEX idx_client_merges_tenant_id ON client_merges(tenant_id);
+CREATE INDEX idx_client_merges_target ON client_merges(target_client_id);
+CREATE INDEX idx_client_merges_source ON client_merges(source_client_id);
+
+ALTER TABLE client_merges ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_policy_client_merges ON client_merges
+    FOR ALL
+    USING (tenant_id = current_setting('app.current_tenant_id', true)::UUID)
+    WITH CHECK (tenant_id = current_setting('app.current_tenant_id', true)::UUID);
+
+COMMENT ON TABLE client_merges IS 'Audit trail of duplicate clients merged into another client';
@@ -0,0 +1,9 @@
+-- Migration: 019_name_reservations (ROLLBACK)
+-- Description: Rollback company name reservations lodged with CIPC before registration
+-- Author: Comply360 Development Team
+-- Date: 2026-10-18
+-- Note: This will be executed in the context of a specific tenant schema
+
+DROP POLICY IF EXISTS tenant_isolation_policy_name_reservations ON name_reservations;
+DROP TRIGGER IF EXISTS update_name_reservations_updated_at ON name_reservations;
+DROP TABLE IF EXISTS name_reservations;
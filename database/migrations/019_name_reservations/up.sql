@@ -0,0 +1,67 @@
+-- Migration: 019_name_reservations
+-- Description: Company name reservations lodged with CIPC before registration
+-- Author: Comply360 Development Team
+-- Date: 2026-10-18
+-- Scope: tenant
+
+-- ============================================================================
+-- NAME RESERVATIONS (Per Tenant)
+-- names holds up to four proposed names in order of preference, each with the
+-- issues local screening found and CIPC's decision on it. A registration has
+-- at most one open reservation; a rejected or expired one is kept as history
+-- and a new one started. When CIPC approves a name the registration takes it
+-- as its company name and registrations.name_reserved_at is set.
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS name_reservations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL,
+    registration_id UUID NOT NULL REFERENCES registrations(id) ON DELETE CASCADE,
+
+    -- Proposed names
+    status VARCHAR(50) NOT NULL DEFAULT 'draft',
+    names JSONB NOT NULL DEFAULT '[]'::jsonb,
+    requested_by UUID REFERENCES users(id) ON DELETE SET NULL,
+
+    -- CIPC
+    cipc_reference VARCHAR(100),
+    reserved_name VARCHAR(255),
+    last_error TEXT,
+
+    -- Timestamps
+    submitted_at TIMESTAMP,
+    last_checked_at TIMESTAMP,
+    decided_at TIMESTAMP,
+    expires_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT valid_name_reservation_status CHECK (status IN ('draft', 'submitted', 'approved', 'rejected', 'expired')),
+    CONSTRAINT valid_name_reservation_names CHECK (jsonb_array_length(names) BETWEEN 1 AND 4)
+);
+
+CREATE INDEX idx_name_reservations_tenant_id ON name_reservations(tenant_id);
+CREATE INDEX idx_name_reservations_registration ON name_reservations(registration_id, created_at DESC);
+CREATE UNIQUE INDEX idx_name_reservations_open ON name_reservations(registration_id)
+    WHERE status IN ('draft', 'submitted', 'approved');
+CREATE UNIQUE INDEX idx_name_reservations_cipc_reference ON name_reservations(cipc_reference)
+    WHERE cipc_reference IS NOT NULL;
+
+-- Submitted reservations are polled for CIPC's decision and approved ones
+-- expired
+CREATE INDEX idx_name_reservations_pending ON name_reservations(status, expires_at)
+    WHERE status IN ('submitted', 'approved');
+
+CREATE TRIGGER update_name_reservations_updated_at BEFORE UPDATE ON name_reservations
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+ALTER TABLE name_reservations ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_policy_name_reservations ON name_reservations
+    FOR ALL
+    USING (tenant_id = current_setting('app.current_tenant_id', true)::UUID)
+    WITH CHECK (tenant_id = current_setting('app.current_tenant_id', true)::UUID);
+
+COMMENT ON TABLE name_reservations IS 'Company names proposed for a registration and reserved with CIPC';
+COMMENT ON COLUMN name_reservations.names IS 'Proposed names in order of preference, with screening issues and CIPC decisions';
+COMMENT ON COLUMN name_reservations.expires_at IS 'When CIPC releases the reserved name if the company is not registered';
@@ -0,0 +1,9 @@
+-- Migration: 020_compliance_obligations (ROLLBACK)
+-- Description: Rollback recurring filings owed by registered companies, with due dates and reminders
+-- Author: Comply360 Development Team
+-- Date: 2026-10-18
+-- Note: This will be executed in the context of a specific tenant schema
+
+DROP POLICY IF EXISTS tenant_isolation_policy_compliance_obligations ON compliance_obligations;
+DROP TRIGGER IF EXISTS update_compliance_obligations_updated_at ON compliance_obligations;
+DROP TABLE IF EXISTS compliance_obligations;
@@ -0,0 +1,71 @@
+-- Migration: 020_compliance_obligations
+-- Description: Recurring filings owed by registered companies, with due dates and reminders
+-- Author: Comply360 Development Team
+-- Date: 2026-10-18
+-- Scope: tenant
+
+-- ============================================================================
+-- COMPLIANCE OBLIGATIONS (Per Tenant)
+-- Scheduled for a completed registration from its type, registration date
+-- and jurisdiction: annual returns, beneficial ownership filings, VAT returns
+-- and tax clearance renewals. Obligations are scheduled a year ahead and the
+-- schedule rolls forward daily. The client and company name are read from
+-- the registration so they follow client merges.
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS compliance_obligations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL,
+    registration_id UUID NOT NULL REFERENCES registrations(id) ON DELETE CASCADE,
+
+    -- Obligation
+    obligation_type VARCHAR(50) NOT NULL,
+    jurisdiction VARCHAR(10) NOT NULL,
+    authority VARCHAR(100) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    period_start DATE NOT NULL,
+    period_end DATE NOT NULL,
+    due_date DATE NOT NULL,
+
+    -- Status
+    status VARCHAR(50) NOT NULL DEFAULT 'upcoming',
+    completed_at TIMESTAMP,
+    completed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    filing_reference VARCHAR(100),
+    notes TEXT,
+
+    -- Reminders
+    last_reminder_days INTEGER,
+    reminded_at TIMESTAMP,
+
+    -- Timestamps
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT valid_obligation_type CHECK (obligation_type IN ('annual_return', 'beneficial_ownership', 'vat_return', 'tax_clearance')),
+    CONSTRAINT valid_obligation_status CHECK (status IN ('upcoming', 'overdue', 'completed', 'waived')),
+    CONSTRAINT valid_obligation_period CHECK (period_end >= period_start)
+);
+
+CREATE INDEX idx_compliance_obligations_tenant_id ON compliance_obligations(tenant_id);
+CREATE INDEX idx_compliance_obligations_registration ON compliance_obligations(registration_id, due_date);
+CREATE UNIQUE INDEX idx_compliance_obligations_period ON compliance_obligations(registration_id, obligation_type, period_start);
+
+-- Open obligations are checked daily for reminders and to mark overdue
+CREATE INDEX idx_compliance_obligations_open ON compliance_obligations(status, due_date)
+    WHERE status IN ('upcoming', 'overdue');
+CREATE INDEX idx_compliance_obligations_due_date ON compliance_obligations(due_date, id);
+
+CREATE TRIGGER update_compliance_obligations_updated_at BEFORE UPDATE ON compliance_obligations
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+ALTER TABLE compliance_obligations ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_policy_compliance_obligations ON compliance_obligations
+    FOR ALL
+    USING (tenant_id = current_setting('app.current_tenant_id', true)::UUID)
+    WITH CHECK (tenant_id = current_setting('app.current_tenant_id', true)::UUID);
+
+COMMENT ON TABLE compliance_obligations IS 'Recurring filings owed by companies registered through the platform';
+COMMENT ON COLUMN compliance_obligations.period_start IS 'First day of the year or tax period the filing covers';
+COMMENT ON COLUMN compliance_obligations.last_reminder_days IS 'Days before the due date of the last reminder sent, so none is repeated; -1 once the overdue notice is sent';
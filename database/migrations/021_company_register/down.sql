@@ -0,0 +1,8 @@
+-- Migration: 021_company_register (ROLLBACK)
+-- Description: Rollback versioned register of directors, shareholders and beneficial owners per company
+-- Author: Comply360 Development Team
+-- Date: 2026-10-18
+-- Note: This will be executed in the context of a specific tenant schema
+
+DROP POLICY IF EXISTS tenant_isolation_policy_company_register_entries ON company_register_entries;
+DROP TABLE IF EXISTS company_register_entries;
@@ -0,0 +1,77 @@
+-- Migration: 021_company_register
+-- Description: Versioned register of directors, shareholders and beneficial owners per company
+-- Author: Comply360 Development Team
+-- Date: 2026-10-18
+-- Scope: tenant
+
+-- ============================================================================
+-- COMPANY REGISTER ENTRIES (Per Tenant)
+-- The directors, shareholders and beneficial owners of a registration's
+-- company. Rows are versions and are never updated: each change inserts the
+-- entry's next version taking effect from a date, so the register can be
+-- read as at any date. entry_id identifies an entry across its versions.
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS company_register_entries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    entry_id UUID NOT NULL,
+    tenant_id UUID NOT NULL,
+    registration_id UUID NOT NULL REFERENCES registrations(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    effective_from DATE NOT NULL,
+
+    -- Person or entity
+    role VARCHAR(50) NOT NULL,
+    person_type VARCHAR(50) NOT NULL,
+    first_names VARCHAR(200),
+    last_name VARCHAR(100),
+    id_number VARCHAR(50),
+    passport_number VARCHAR(50),
+    nationality VARCHAR(2),
+    date_of_birth DATE,
+    entity_name VARCHAR(255),
+    registration_number VARCHAR(50),
+    email VARCHAR(255),
+    residential_address TEXT,
+
+    -- Interest
+    share_percent NUMERIC(7,4),
+    control_type VARCHAR(50),
+
+    -- Term of office
+    appointed_on DATE NOT NULL,
+    resigned_on DATE,
+
+    document_ids UUID[] NOT NULL DEFAULT '{}',
+    removed BOOLEAN NOT NULL DEFAULT false,
+    note TEXT,
+
+    -- Audit
+    recorded_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    recorded_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT valid_register_role CHECK (role IN ('director', 'shareholder', 'beneficial_owner')),
+    CONSTRAINT valid_register_person_type CHECK (person_type IN ('natural_person', 'legal_entity')),
+    CONSTRAINT valid_register_control_type CHECK (control_type IS NULL OR control_type IN (
+        'direct_shareholding', 'indirect_shareholding', 'voting_rights', 'appointment_of_directors', 'other_control')),
+    CONSTRAINT valid_register_share_percent CHECK (share_percent IS NULL OR (share_percent > 0 AND share_percent <= 100)),
+    CONSTRAINT valid_register_term CHECK (resigned_on IS NULL OR resigned_on >= appointed_on)
+);
+
+CREATE INDEX idx_company_register_entries_tenant_id ON company_register_entries(tenant_id);
+CREATE INDEX idx_company_register_entries_registration ON company_register_entries(registration_id, recorded_at);
+CREATE UNIQUE INDEX idx_company_register_entries_version ON company_register_entries(entry_id, version);
+
+ALTER TABLE company_register_entries ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_policy_company_register_entries ON company_register_entries
+    FOR ALL
+    USING (tenant_id = current_setting('app.current_tenant_id', true)::UUID)
+    WITH CHECK (tenant_id = current_setting('app.current_tenant_id', true)::UUID);
+
+COMMENT ON TABLE company_register_entries IS 'Versions of the entries in a company''s register of directors, shareholders and beneficial owners';
+COMMENT ON COLUMN company_register_entries.entry_id IS 'The register entry this row is a version of';
+COMMENT ON COLUMN company_register_entries.effective_from IS 'Date from which this version describes the entry';
+COMMENT ON COLUMN company_register_entries.share_percent IS 'Shareholding of a shareholder, or beneficial interest of a beneficial owner';
+COMMENT ON COLUMN company_register_entries.document_ids IS 'Supporting documents, such as share certificates or identity documents';
+COMMENT ON COLUMN company_register_entries.removed IS 'Set on the version striking an entry recorded in error from the register';
@@ -0,0 +1,10 @@
+-- Migration: 022_jurisdiction_registries (ROLLBACK)
+-- Description: Rollback lodging name reservations with the registry of the registration's jurisdiction
+-- Author: Comply360 Development Team
+-- Date: 2026-10-18
+-- Note: This will be executed in the context of a specific tenant schema
+
+DROP INDEX IF EXISTS idx_name_reservations_dcip_reference;
+ALTER TABLE name_reservations
+    DROP COLUMN IF EXISTS dcip_reference,
+    DROP COLUMN IF EXISTS jurisdiction;
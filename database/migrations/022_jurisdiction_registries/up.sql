@@ -0,0 +1,23 @@
+-- Migration: 022_jurisdiction_registries
+-- Description: Lodge name reservations with the registry of the registration's jurisdiction
+-- Author: Comply360 Development Team
+-- Date: 2026-10-18
+-- Scope: tenant
+
+-- ============================================================================
+-- NAME RESERVATION REGISTRIES (Per Tenant)
+-- A name reservation is lodged with the registry of its registration's
+-- jurisdiction: CIPC in South Africa or DCIP in Zimbabwe. Each registry's
+-- reference has its own column, as on registrations. Reservations made before
+-- Zimbabwe was supported were all lodged with CIPC.
+-- ============================================================================
+
+ALTER TABLE name_reservations
+    ADD COLUMN IF NOT EXISTS jurisdiction VARCHAR(2) NOT NULL DEFAULT 'ZA',
+    ADD COLUMN IF NOT EXISTS dcip_reference VARCHAR(100);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_name_reservations_dcip_reference ON name_reservations(dcip_reference)
+    WHERE dcip_reference IS NOT NULL;
+
+COMMENT ON COLUMN name_reservations.jurisdiction IS 'Jurisdiction whose registry the names are lodged with';
+COMMENT ON COLUMN name_reservations.dcip_reference IS 'DCIP name search reference of a Zimbabwean reservation';
@@ -0,0 +1,13 @@
+-- Migration: 023_fee_schedules (ROLLBACK)
+-- Description: Rollback fee schedules per jurisdiction and registration type, and registration quotes locked at submission
+-- Author: Comply360 Development Team
+-- Date: 2026-10-18
+-- Note: This will be executed in the context of a specific tenant schema
+
+DROP POLICY IF EXISTS tenant_isolation_policy_registration_quotes ON registration_quotes;
+DROP TRIGGER IF EXISTS update_registration_quotes_updated_at ON registration_quotes;
+DROP TABLE IF EXISTS registration_quotes;
+
+DROP POLICY IF EXISTS tenant_isolation_policy_fee_schedules ON fee_schedules;
+DROP TRIGGER IF EXISTS update_fee_schedules_updated_at ON fee_schedules;
+DROP TABLE IF EXISTS fee_schedules;
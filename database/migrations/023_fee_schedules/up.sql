@@ -0,0 +1,95 @@
+-- Migration: 023_fee_schedules
+-- Description: Fee schedules per jurisdiction and registration type, and registration quotes locked at submission
+-- Author: Comply360 Development Team
+-- Date: 2026-10-18
+-- Scope: tenant
+
+-- ============================================================================
+-- FEE SCHEDULES (Per Tenant)
+-- What a tenant charges for a registration type in a jurisdiction. A schedule
+-- is in effect from effective_from until the next schedule for the same
+-- jurisdiction and registration type takes effect. items is a JSON array of
+-- {code, description, kind, amount}, kind being government, service or addon.
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS fee_schedules (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL,
+    jurisdiction VARCHAR(2) NOT NULL,
+    registration_type VARCHAR(50) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    vat_rate NUMERIC(5,2) NOT NULL,
+    effective_from DATE NOT NULL,
+    items JSONB NOT NULL DEFAULT '[]',
+
+    -- Audit
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT valid_fee_schedule_vat_rate CHECK (vat_rate >= 0 AND vat_rate <= 100)
+);
+
+CREATE INDEX idx_fee_schedules_tenant_id ON fee_schedules(tenant_id);
+CREATE UNIQUE INDEX idx_fee_schedules_effective ON fee_schedules(tenant_id, jurisdiction, registration_type, effective_from);
+
+CREATE TRIGGER update_fee_schedules_updated_at BEFORE UPDATE ON fee_schedules
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+ALTER TABLE fee_schedules ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_policy_fee_schedules ON fee_schedules
+    FOR ALL
+    USING (tenant_id = current_setting('app.current_tenant_id', true)::UUID)
+    WITH CHECK (tenant_id = current_setting('app.current_tenant_id', true)::UUID);
+
+COMMENT ON TABLE fee_schedules IS 'Government, service and add-on fees a tenant charges per jurisdiction and registration type';
+COMMENT ON COLUMN fee_schedules.vat_rate IS 'VAT rate in percent charged on service and add-on fees; government fees carry no VAT';
+COMMENT ON COLUMN fee_schedules.effective_from IS 'Date from which the schedule prices new quotes';
+
+-- ============================================================================
+-- REGISTRATION QUOTES (Per Tenant)
+-- The price of a registration. A registration has at most one draft quote,
+-- replaced when it is requoted, and at most one locked quote: the one locked
+-- when it was submitted, which invoicing and commissions use.
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS registration_quotes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL,
+    registration_id UUID NOT NULL REFERENCES registrations(id) ON DELETE CASCADE,
+    fee_schedule_id UUID REFERENCES fee_schedules(id) ON DELETE SET NULL,
+    currency VARCHAR(3) NOT NULL,
+    vat_rate NUMERIC(5,2) NOT NULL,
+    add_ons TEXT[] NOT NULL DEFAULT '{}',
+    lines JSONB NOT NULL DEFAULT '[]',
+    subtotal NUMERIC(12,2) NOT NULL,
+    vat NUMERIC(12,2) NOT NULL,
+    total NUMERIC(12,2) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'draft',
+    locked_at TIMESTAMP,
+    locked_by UUID REFERENCES users(id) ON DELETE SET NULL,
+
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT valid_quote_status CHECK (status IN ('draft', 'locked')),
+    CONSTRAINT valid_quote_lock CHECK ((status = 'locked') = (locked_at IS NOT NULL))
+);
+
+CREATE INDEX idx_registration_quotes_tenant_id ON registration_quotes(tenant_id);
+CREATE UNIQUE INDEX idx_registration_quotes_status ON registration_quotes(registration_id, status);
+
+CREATE TRIGGER update_registration_quotes_updated_at BEFORE UPDATE ON registration_quotes
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+ALTER TABLE registration_quotes ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_policy_registration_quotes ON registration_quotes
+    FOR ALL
+    USING (tenant_id = current_setting('app.current_tenant_id', true)::UUID)
+    WITH CHECK (tenant_id = current_setting('app.current_tenant_id', true)::UUID);
+
+COMMENT ON TABLE registration_quotes IS 'Quotes for registrations; the locked quote is invoiced and earns commission';
+COMMENT ON COLUMN registration_quotes.lines IS 'JSON array of {code, description, kind, amount, vat_rate, vat}, amounts excluding VAT';
+COMMENT ON COLUMN registration_quotes.locked_at IS 'When the registration was submitted and the quote locked';
@@ -0,0 +1,8 @@
+-- Migration: 024_backfill_tenant_migration_version (ROLLBACK)
+-- Description: Nothing to roll back
+-- Author: Comply360 Development Team
+-- Date: 2026-10-18
+
+-- The backfilled versions describe what the schemas already hold, so they are
+-- kept. Resetting them to 0 would make the next tenant migration run fail.
+SELECT 1;
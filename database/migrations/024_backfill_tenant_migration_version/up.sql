@@ -0,0 +1,20 @@
+-- Migration: 024_backfill_tenant_migration_version
+-- Description: Record 002 and 003 as applied to tenant schemas provisioned before the tenant migration runner
+-- Author: Comply360 Development Team
+-- Date: 2026-10-18
+
+-- ============================================================================
+-- TENANT MIGRATION VERSION BACKFILL
+-- Schemas provisioned before 002_tenant_template and 003_rls_policies were
+-- scoped to tenants already hold their tables and policies but were left at
+-- migration_version 0, so the first tenant migration run would apply them
+-- again and fail. Only schemas that exist and hold the template's users
+-- table are backfilled; schemas still being created are left to the
+-- provisioning service.
+-- ============================================================================
+
+UPDATE public.tenant_schemas
+SET migration_version = 3, updated_at = NOW()
+WHERE migration_version = 0
+  AND status <> 'creating'
+  AND to_regclass(quote_ident(schema_name) || '.users') IS NOT NULL;
@@ -0,0 +1,8 @@
+-- Migration: 025_queue_override_pagination (ROLLBACK)
+-- Description: Rollback indexes for keyset pagination of review queue and commission override lists
+-- Author: Comply360 Development Team
+-- Date: 2026-10-18
+-- Note: This will be executed in the context of a specific tenant schema
+
+DROP INDEX IF EXISTS idx_commission_overrides_tenant_created_at_id;
+DROP INDEX IF EXISTS idx_registrations_tenant_sla_due_at_id;
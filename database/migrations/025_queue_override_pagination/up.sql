@@ -0,0 +1,12 @@
+-- Migration: 025_queue_override_pagination
+-- Description: Indexes for keyset pagination of review queue and commission override lists
+-- Author: Comply360 Development Team
+-- Date: 2026-10-18
+-- Scope: tenant
+
+-- Registrations in review are read soonest due first, those without an SLA
+-- last, continuing after the (due time, id) of the last row of the previous page
+CREATE INDEX idx_registrations_tenant_sla_due_at_id ON registrations(tenant_id, COALESCE(sla_due_at, 'infinity'), id) WHERE deleted_at IS NULL;
+
+-- Overrides are read newest first, like commissions
+CREATE INDEX idx_commission_overrides_tenant_created_at_id ON commission_overrides(tenant_id, created_at DESC, id DESC);
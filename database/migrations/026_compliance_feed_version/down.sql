@@ -0,0 +1,7 @@
+-- Migration: 026_compliance_feed_version (ROLLBACK)
+-- Description: Rollback rotatable version of a tenant's compliance calendar feed tokens
+-- Author: Comply360 Development Team
+-- Date: 2026-10-18
+
+ALTER TABLE public.tenants
+    DROP COLUMN IF EXISTS compliance_feed_version;
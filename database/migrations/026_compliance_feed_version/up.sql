@@ -0,0 +1,11 @@
+-- Migration: 026_compliance_feed_version
+-- Description: Rotatable version of a tenant's compliance calendar feed tokens
+-- Author: Comply360 Development Team
+-- Date: 2026-10-18
+
+-- Feed tokens carry the version they were signed under and stop working once
+-- the tenant rotates to a new version
+ALTER TABLE public.tenants
+    ADD COLUMN IF NOT EXISTS compliance_feed_version INTEGER NOT NULL DEFAULT 1;
+
+COMMENT ON COLUMN public.tenants.compliance_feed_version IS 'Version of the compliance calendar feed tokens; incrementing it revokes every feed URL issued before';
@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"os"
+	"sort"
 
 	"github.com/comply360/migrator/internal/db"
 	"github.com/comply360/migrator/internal/migrations"
@@ -12,6 +13,16 @@ import (
 var (
 	dbURL          string
 	migrationsPath string
+
+	dryRun bool
+	downTo string
+
+	createScope string
+
+	tenantFilter      []string
+	tenantConcurrency int
+	tenantReportPath  string
+	tenantResumePath  string
 )
 
 var rootCmd = &cobra.Command{
@@ -22,16 +33,22 @@ var rootCmd = &cobra.Command{
 
 var upCmd = &cobra.Command{
 	Use:   "up",
-	Short: "Run all pending migrations",
+	Short: "Run all pending global migrations",
 	RunE:  runUp,
 }
 
 var downCmd = &cobra.Command{
 	Use:   "down",
-	Short: "Rollback the last migration",
+	Short: "Rollback the last migration, or every migration after --to",
 	RunE:  runDown,
 }
 
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Verify checksums and pending migrations (exits non-zero on problems)",
+	RunE:  runVerify,
+}
+
 var statusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Show migration status",
@@ -45,16 +62,51 @@ var createCmd = &cobra.Command{
 	RunE:  runCreate,
 }
 
+var tenantsCmd = &cobra.Command{
+	Use:   "tenants",
+	Short: "Manage tenant schema migrations",
+}
+
+var tenantsUpCmd = &cobra.Command{
+	Use:   "up",
+	Short: "Apply pending tenant migrations to every tenant schema",
+	RunE:  runTenantsUp,
+}
+
+var tenantsStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show migration status of tenant schemas",
+	RunE:  runTenantsStatus,
+}
+
 func init() {
 	// Global flags
 	rootCmd.PersistentFlags().StringVar(&dbURL, "db-url", getEnv("DATABASE_URL", ""), "Database connection URL")
 	rootCmd.PersistentFlags().StringVar(&migrationsPath, "path", "./migrations", "Path to migrations directory")
 
+	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "Print the planned SQL without executing it")
+
+	// Down flags
+	downCmd.Flags().StringVar(&downTo, "to", "", "Roll back every migration applied after this one")
+
+	// Create flags
+	createCmd.Flags().StringVar(&createScope, "scope", migrations.ScopeGlobal, "Migration scope (global or tenant)")
+
+	// Tenant flags
+	tenantsCmd.PersistentFlags().StringSliceVar(&tenantFilter, "tenant", nil, "Limit to these tenant IDs or schema names")
+	tenantsUpCmd.Flags().IntVar(&tenantConcurrency, "concurrency", 4, "Number of tenant schemas migrated in parallel")
+	tenantsUpCmd.Flags().StringVar(&tenantReportPath, "report", "tenant-migrations-report.json", "Where to write the run report")
+	tenantsUpCmd.Flags().StringVar(&tenantResumePath, "resume", "", "Retry only the schemas that failed in this report")
+
 	// Add commands
 	rootCmd.AddCommand(upCmd)
 	rootCmd.AddCommand(downCmd)
 	rootCmd.AddCommand(statusCmd)
 	rootCmd.AddCommand(createCmd)
+	rootCmd.AddCommand(verifyCmd)
+	rootCmd.AddCommand(tenantsCmd)
+	tenantsCmd.AddCommand(tenantsUpCmd)
+	tenantsCmd.AddCommand(tenantsStatusCmd)
 }
 
 func main() {
@@ -77,6 +129,15 @@ func runUp(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create migrations table: %w", err)
 	}
 
+	if dryRun {
+		plan, err := migrator.PlanUp()
+		if err != nil {
+			return fmt.Errorf("failed to plan migrations: %w", err)
+		}
+		printPlan(plan)
+		return nil
+	}
+
 	applied, err := migrator.Up()
 	if err != nil {
 		return fmt.Errorf("migration failed: %w", err)
@@ -103,6 +164,32 @@ func runDown(cmd *cobra.Command, args []string) error {
 
 	migrator := migrations.NewMigrator(database, migrationsPath)
 
+	if dryRun {
+		plan, err := migrator.PlanDown(downTo)
+		if err != nil {
+			return fmt.Errorf("failed to plan rollback: %w", err)
+		}
+		printPlan(plan)
+		return nil
+	}
+
+	if downTo != "" {
+		rolledBack, err := migrator.DownTo(downTo)
+		if err != nil {
+			return fmt.Errorf("rollback failed: %w", err)
+		}
+
+		if len(rolledBack) == 0 {
+			fmt.Printf("Already at %s\n", downTo)
+		} else {
+			fmt.Printf("Successfully rolled back %d migration(s):\n", len(rolledBack))
+			for _, name := range rolledBack {
+				fmt.Printf("  ✓ %s\n", name)
+			}
+		}
+		return nil
+	}
+
 	name, err := migrator.Down()
 	if err != nil {
 		return fmt.Errorf("rollback failed: %w", err)
@@ -117,6 +204,39 @@ func runDown(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runVerify(cmd *cobra.Command, args []string) error {
+	database, err := db.Connect(dbURL)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer database.Close()
+
+	migrator := migrations.NewMigrator(database, migrationsPath)
+
+	if err := migrator.CreateMigrationsTable(); err != nil {
+		return fmt.Errorf("failed to create migrations table: %w", err)
+	}
+
+	result, err := migrator.Verify()
+	if err != nil {
+		return fmt.Errorf("verification failed: %w", err)
+	}
+
+	printIssues("Checksum drift", result.Drifted)
+	printIssues("Applied but missing on disk", result.Missing)
+	printIssues("Pending migrations", result.Pending)
+	printIssues("Missing down.sql", result.NoRollback)
+	printIssues("Tenant schemas behind", result.TenantsBehind)
+	printIssues("Warning: no checksum recorded", result.NoChecksum)
+
+	if !result.OK() {
+		return fmt.Errorf("migration verification failed")
+	}
+
+	fmt.Println("✓ Migrations verified")
+	return nil
+}
+
 func runStatus(cmd *cobra.Command, args []string) error {
 	database, err := db.Connect(dbURL)
 	if err != nil {
@@ -153,7 +273,7 @@ func runCreate(cmd *cobra.Command, args []string) error {
 
 	migrator := migrations.NewMigrator(nil, migrationsPath)
 
-	created, err := migrator.Create(name)
+	created, err := migrator.Create(name, createScope)
 	if err != nil {
 		return fmt.Errorf("failed to create migration: %w", err)
 	}
@@ -165,6 +285,143 @@ func runCreate(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runTenantsUp(cmd *cobra.Command, args []string) error {
+	tenants := tenantFilter
+
+	// Resume from a previous report: only retry what failed
+	if tenantResumePath != "" {
+		previous, err := migrations.LoadTenantMigrationReport(tenantResumePath)
+		if err != nil {
+			return err
+		}
+		tenants = previous.FailedSchemas()
+		if len(tenants) == 0 {
+			fmt.Println("No failed tenant schemas to resume")
+			return nil
+		}
+	}
+
+	database, err := db.Connect(dbURL)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer database.Close()
+
+	migrator := migrations.NewMigrator(database, migrationsPath)
+
+	if dryRun {
+		plan, err := migrator.PlanTenants(tenants)
+		if err != nil {
+			return fmt.Errorf("failed to plan tenant migrations: %w", err)
+		}
+		if len(plan) == 0 {
+			fmt.Println("No pending tenant migrations")
+		}
+		schemas := make([]string, 0, len(plan))
+		for schema := range plan {
+			schemas = append(schemas, schema)
+		}
+		sort.Strings(schemas)
+		for _, schema := range schemas {
+			fmt.Printf("== %s ==\n", schema)
+			printPlan(plan[schema])
+		}
+		return nil
+	}
+
+	report, err := migrator.UpTenants(migrations.TenantUpOptions{
+		Concurrency: tenantConcurrency,
+		Tenants:     tenants,
+	})
+	if err != nil {
+		return fmt.Errorf("tenant migration failed: %w", err)
+	}
+
+	if len(report.Results) == 0 {
+		fmt.Println("No tenant schemas found")
+		return nil
+	}
+
+	fmt.Printf("Tenant migrations (target version %d):\n", report.TargetVersion)
+	for _, result := range report.Results {
+		switch result.Status {
+		case migrations.TenantResultSucceeded:
+			fmt.Printf("  ✓ %s: %d -> %d (%d applied)\n", result.SchemaName, result.FromVersion, result.ToVersion, len(result.Applied))
+		case migrations.TenantResultUpToDate:
+			fmt.Printf("  ✓ %s: up to date (%d)\n", result.SchemaName, result.ToVersion)
+		default:
+			fmt.Printf("  ✗ %s: stopped at %d: %s\n", result.SchemaName, result.ToVersion, result.Error)
+		}
+	}
+
+	if err := report.Save(tenantReportPath); err != nil {
+		return err
+	}
+	fmt.Printf("Report written to %s\n", tenantReportPath)
+
+	if failed := report.Failed(); len(failed) > 0 {
+		return fmt.Errorf("%d tenant schema(s) failed; rerun with --resume %s", len(failed), tenantReportPath)
+	}
+
+	return nil
+}
+
+func runTenantsStatus(cmd *cobra.Command, args []string) error {
+	database, err := db.Connect(dbURL)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer database.Close()
+
+	migrator := migrations.NewMigrator(database, migrationsPath)
+
+	status, err := migrator.TenantStatus(tenantFilter)
+	if err != nil {
+		return fmt.Errorf("failed to get tenant migration status: %w", err)
+	}
+
+	fmt.Println("Tenant Migration Status:")
+	fmt.Println("=======================")
+	for _, s := range status {
+		statusIcon := "✓"
+		if len(s.Pending) > 0 || s.Status == "error" {
+			statusIcon = "✗"
+		}
+		fmt.Printf("%s %s (version %d, %s, %d pending)\n", statusIcon, s.SchemaName, s.MigrationVersion, s.Status, len(s.Pending))
+	}
+
+	return nil
+}
+
+// printPlan prints the SQL a run would execute
+func printPlan(plan []migrations.PlannedMigration) {
+	if len(plan) == 0 {
+		fmt.Println("Nothing to do")
+		return
+	}
+
+	for _, migration := range plan {
+		mode := "transaction"
+		if migration.NoTransaction {
+			mode = "no transaction"
+		}
+		fmt.Printf("-- [dry-run] %s %s (%s)\n", migration.Direction, migration.Name, mode)
+		fmt.Println(migration.SQL)
+	}
+}
+
+// printIssues prints a labelled list of migrations, if any
+func printIssues(label string, names []string) {
+	if len(names) == 0 {
+		return
+	}
+
+	fmt.Printf("%s:\n", label)
+	for _, name := range names {
+		fmt.Printf("  ✗ %s\n", name)
+	}
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
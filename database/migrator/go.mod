@@ -3,6 +3,7 @@ module github.com/comply360/migrator
 go 1.21
 
 require (
+	github.com/comply360/shared v0.0.0
 	github.com/lib/pq v1.10.9
 	github.com/spf13/cobra v1.8.0
 )
@@ -11,3 +12,5 @@ require (
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
 	github.com/spf13/pflag v1.0.5 // indirect
 )
+
+replace github.com/comply360/shared => ../../packages/shared
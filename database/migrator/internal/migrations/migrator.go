@@ -1,13 +1,30 @@
 package migrations
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/comply360/shared/sqlscript"
+)
+
+// Migration scopes. Global migrations run once against the public schema;
+// tenant migrations run inside every tenant schema.
+const (
+	ScopeGlobal = "global"
+	ScopeTenant = "tenant"
+)
+
+// Migration directions
+const (
+	DirectionUp   = "up"
+	DirectionDown = "down"
 )
 
 // Migrator handles database migrations
@@ -19,9 +36,47 @@ type Migrator struct {
 // MigrationStatus represents the status of a migration
 type MigrationStatus struct {
 	Name    string
+	Scope   string
 	Applied bool
 }
 
+// PlannedMigration is a migration a run would apply or roll back
+type PlannedMigration struct {
+	Name          string
+	Direction     string
+	NoTransaction bool
+	SQL           string
+}
+
+// VerifyResult reports the consistency of the database with the migration files
+type VerifyResult struct {
+	// Pending global migrations that have not been applied
+	Pending []string
+	// Drifted migrations whose up.sql changed after being applied
+	Drifted []string
+	// Missing migrations recorded as applied but no longer on disk
+	Missing []string
+	// NoRollback migrations without a down.sql
+	NoRollback []string
+	// NoChecksum migrations applied before checksums were recorded
+	NoChecksum []string
+	// TenantsBehind tenant schemas with pending tenant migrations
+	TenantsBehind []string
+}
+
+// OK reports whether verification passed. Legacy migrations without a
+// checksum are reported but do not fail verification.
+func (r *VerifyResult) OK() bool {
+	return len(r.Pending) == 0 && len(r.Drifted) == 0 && len(r.Missing) == 0 &&
+		len(r.NoRollback) == 0 && len(r.TenantsBehind) == 0
+}
+
+// appliedMigration is a row of public.schema_migrations
+type appliedMigration struct {
+	name     string
+	checksum sql.NullString
+}
+
 // NewMigrator creates a new migrator instance
 func NewMigrator(db *sql.DB, migrationsPath string) *Migrator {
 	return &Migrator{
@@ -36,8 +91,10 @@ func (m *Migrator) CreateMigrationsTable() error {
 		CREATE TABLE IF NOT EXISTS public.schema_migrations (
 			id SERIAL PRIMARY KEY,
 			migration_name VARCHAR(255) NOT NULL UNIQUE,
+			checksum VARCHAR(64),
 			applied_at TIMESTAMP NOT NULL DEFAULT NOW()
-		)
+		);
+		ALTER TABLE public.schema_migrations ADD COLUMN IF NOT EXISTS checksum VARCHAR(64);
 	`
 
 	_, err := m.db.Exec(query)
@@ -48,10 +105,39 @@ func (m *Migrator) CreateMigrationsTable() error {
 	return nil
 }
 
-// Up runs all pending migrations
+// Up runs all pending global migrations. It refuses to run if an applied
+// migration has drifted from its file on disk.
 func (m *Migrator) Up() ([]string, error) {
-	// Get list of all migrations
-	allMigrations, err := m.listMigrations()
+	applied := []string{}
+
+	err := m.withLock(func() error {
+		if err := m.checkDrift(); err != nil {
+			return err
+		}
+
+		plan, err := m.PlanUp()
+		if err != nil {
+			return err
+		}
+
+		// Apply each pending migration
+		for _, migration := range plan {
+			if err := m.applyMigration(migration); err != nil {
+				return fmt.Errorf("failed to apply migration %s: %w", migration.Name, err)
+			}
+			applied = append(applied, migration.Name)
+		}
+
+		return nil
+	})
+
+	return applied, err
+}
+
+// PlanUp returns the pending global migrations with the SQL that Up would run
+func (m *Migrator) PlanUp() ([]PlannedMigration, error) {
+	// Get list of all global migrations
+	allMigrations, err := m.listMigrations(ScopeGlobal)
 	if err != nil {
 		return nil, err
 	}
@@ -65,53 +151,172 @@ func (m *Migrator) Up() ([]string, error) {
 	// Find pending migrations
 	pendingMigrations := m.findPendingMigrations(allMigrations, appliedMigrations)
 
-	if len(pendingMigrations) == 0 {
-		return []string{}, nil
-	}
-
-	// Apply each pending migration
-	applied := []string{}
-	for _, migration := range pendingMigrations {
-		if err := m.applyMigration(migration); err != nil {
-			return applied, fmt.Errorf("failed to apply migration %s: %w", migration, err)
+	plan := make([]PlannedMigration, 0, len(pendingMigrations))
+	for _, name := range pendingMigrations {
+		migration, err := m.loadPlannedMigration(name, DirectionUp)
+		if err != nil {
+			return nil, err
 		}
-		applied = append(applied, migration)
+		plan = append(plan, migration)
 	}
 
-	return applied, nil
+	return plan, nil
 }
 
 // Down rolls back the last applied migration
 func (m *Migrator) Down() (string, error) {
-	// Get the last applied migration
 	var migrationName string
-	query := `
-		SELECT migration_name
-		FROM public.schema_migrations
-		ORDER BY applied_at DESC
-		LIMIT 1
-	`
 
-	err := m.db.QueryRow(query).Scan(&migrationName)
-	if err == sql.ErrNoRows {
-		return "", nil
+	err := m.withLock(func() error {
+		plan, err := m.PlanDown("")
+		if err != nil {
+			return err
+		}
+		if len(plan) == 0 {
+			return nil
+		}
+
+		// Rollback the migration
+		if err := m.rollbackMigration(plan[0]); err != nil {
+			return fmt.Errorf("failed to rollback migration %s: %w", plan[0].Name, err)
+		}
+		migrationName = plan[0].Name
+
+		return nil
+	})
+
+	return migrationName, err
+}
+
+// DownTo rolls back every migration applied after target, leaving target applied
+func (m *Migrator) DownTo(target string) ([]string, error) {
+	rolledBack := []string{}
+
+	err := m.withLock(func() error {
+		plan, err := m.PlanDown(target)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range plan {
+			if err := m.rollbackMigration(migration); err != nil {
+				return fmt.Errorf("failed to rollback migration %s: %w", migration.Name, err)
+			}
+			rolledBack = append(rolledBack, migration.Name)
+		}
+
+		return nil
+	})
+
+	return rolledBack, err
+}
+
+// PlanDown returns the rollbacks Down would run. With an empty target it plans
+// the last applied migration; otherwise everything applied after target.
+func (m *Migrator) PlanDown(target string) ([]PlannedMigration, error) {
+	appliedMigrations, err := m.getAppliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	if target == "" {
+		if len(appliedMigrations) > 0 {
+			names = []string{appliedMigrations[len(appliedMigrations)-1]}
+		}
+	} else {
+		found := false
+		for i := len(appliedMigrations) - 1; i >= 0; i-- {
+			if appliedMigrations[i] == target {
+				found = true
+				break
+			}
+			names = append(names, appliedMigrations[i])
+		}
+		if !found {
+			return nil, fmt.Errorf("migration %s has not been applied", target)
+		}
 	}
+
+	plan := make([]PlannedMigration, 0, len(names))
+	for _, name := range names {
+		migration, err := m.loadPlannedMigration(name, DirectionDown)
+		if err != nil {
+			return nil, err
+		}
+		plan = append(plan, migration)
+	}
+
+	return plan, nil
+}
+
+// Verify checks the database against the migration files without changing anything
+func (m *Migrator) Verify() (*VerifyResult, error) {
+	result := &VerifyResult{}
+
+	allMigrations, err := m.listMigrations(ScopeGlobal)
 	if err != nil {
-		return "", fmt.Errorf("failed to get last migration: %w", err)
+		return nil, err
 	}
 
-	// Rollback the migration
-	if err := m.rollbackMigration(migrationName); err != nil {
-		return "", fmt.Errorf("failed to rollback migration %s: %w", migrationName, err)
+	applied, err := m.getAppliedChecksums()
+	if err != nil {
+		return nil, err
 	}
 
-	return migrationName, nil
+	appliedMap := make(map[string]bool)
+	for _, migration := range applied {
+		appliedMap[migration.name] = true
+
+		content, err := os.ReadFile(filepath.Join(m.migrationsPath, migration.name, "up.sql"))
+		if os.IsNotExist(err) {
+			result.Missing = append(result.Missing, migration.name)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration file: %w", err)
+		}
+
+		if !migration.checksum.Valid {
+			result.NoChecksum = append(result.NoChecksum, migration.name)
+		} else if migration.checksum.String != Checksum(content) {
+			result.Drifted = append(result.Drifted, migration.name)
+		}
+	}
+
+	all, err := m.listMigrations("")
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range all {
+		if _, err := os.Stat(filepath.Join(m.migrationsPath, name, "down.sql")); err != nil {
+			result.NoRollback = append(result.NoRollback, name)
+		}
+	}
+
+	for _, name := range allMigrations {
+		if !appliedMap[name] {
+			result.Pending = append(result.Pending, name)
+		}
+	}
+
+	tenants, err := m.TenantStatus(nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, tenant := range tenants {
+		if len(tenant.Pending) > 0 {
+			result.TenantsBehind = append(result.TenantsBehind, tenant.SchemaName)
+		}
+	}
+
+	return result, nil
 }
 
-// Status returns the status of all migrations
+// Status returns the status of all global migrations
 func (m *Migrator) Status() ([]MigrationStatus, error) {
-	// Get all migrations
-	allMigrations, err := m.listMigrations()
+	// Get all global migrations
+	allMigrations, err := m.listMigrations(ScopeGlobal)
 	if err != nil {
 		return nil, err
 	}
@@ -132,6 +337,7 @@ func (m *Migrator) Status() ([]MigrationStatus, error) {
 	for i, name := range allMigrations {
 		status[i] = MigrationStatus{
 			Name:    name,
+			Scope:   ScopeGlobal,
 			Applied: appliedMap[name],
 		}
 	}
@@ -139,11 +345,27 @@ func (m *Migrator) Status() ([]MigrationStatus, error) {
 	return status, nil
 }
 
-// Create creates a new migration with the given name
-func (m *Migrator) Create(name string) (string, error) {
-	// Generate migration name with timestamp
+// Create creates a new migration with the given name and scope
+func (m *Migrator) Create(name string, scope string) (string, error) {
+	if scope != ScopeGlobal && scope != ScopeTenant {
+		return "", fmt.Errorf("invalid migration scope: %s", scope)
+	}
+
+	// Number the migration after the highest existing version
+	existing, err := m.listMigrations("")
+	if err != nil {
+		return "", err
+	}
+
+	next := 1
+	for _, migration := range existing {
+		if version, err := MigrationVersion(migration); err == nil && version >= next {
+			next = version + 1
+		}
+	}
+
 	timestamp := time.Now().Format("2006-01-02")
-	migrationName := fmt.Sprintf("%s-%s", timestamp, strings.ToLower(strings.ReplaceAll(name, " ", "-")))
+	migrationName := fmt.Sprintf("%03d_%s", next, strings.ToLower(strings.ReplaceAll(name, " ", "_")))
 
 	// Create migration directory
 	migrationDir := filepath.Join(m.migrationsPath, migrationName)
@@ -153,15 +375,15 @@ func (m *Migrator) Create(name string) (string, error) {
 
 	// Create up.sql
 	upFile := filepath.Join(migrationDir, "up.sql")
-	upContent := fmt.Sprintf("-- Migration: %s (UP)\n-- Description: %s\n-- Date: %s\n\n-- Add your migration SQL here\n",
-		migrationName, name, timestamp)
+	upContent := fmt.Sprintf("-- Migration: %s\n-- Description: %s\n-- Author: Comply360 Development Team\n-- Date: %s\n%s %s\n\n-- Add your migration SQL here\n",
+		migrationName, name, timestamp, scopeHeader, scope)
 	if err := os.WriteFile(upFile, []byte(upContent), 0644); err != nil {
 		return "", fmt.Errorf("failed to create up.sql: %w", err)
 	}
 
 	// Create down.sql
 	downFile := filepath.Join(migrationDir, "down.sql")
-	downContent := fmt.Sprintf("-- Migration: %s (DOWN)\n-- Description: Rollback %s\n-- Date: %s\n\n-- Add your rollback SQL here\n",
+	downContent := fmt.Sprintf("-- Migration: %s (ROLLBACK)\n-- Description: Rollback %s\n-- Author: Comply360 Development Team\n-- Date: %s\n\n-- Add your rollback SQL here\n",
 		migrationName, name, timestamp)
 	if err := os.WriteFile(downFile, []byte(downContent), 0644); err != nil {
 		return "", fmt.Errorf("failed to create down.sql: %w", err)
@@ -170,8 +392,18 @@ func (m *Migrator) Create(name string) (string, error) {
 	return migrationName, nil
 }
 
-// listMigrations lists all migration directories
-func (m *Migrator) listMigrations() ([]string, error) {
+// MigrationVersion returns the numeric version prefix of a migration name (e.g. 002_tenant_template -> 2)
+func MigrationVersion(name string) (int, error) {
+	prefix := strings.SplitN(name, "_", 2)[0]
+	version, err := strconv.Atoi(prefix)
+	if err != nil {
+		return 0, fmt.Errorf("migration %s has no numeric version prefix", name)
+	}
+	return version, nil
+}
+
+// listMigrations lists migration directories of the given scope ("" for all)
+func (m *Migrator) listMigrations(scope string) ([]string, error) {
 	entries, err := os.ReadDir(m.migrationsPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read migrations directory: %w", err)
@@ -182,13 +414,25 @@ func (m *Migrator) listMigrations() ([]string, error) {
 		if entry.IsDir() {
 			// Check if up.sql exists
 			upFile := filepath.Join(m.migrationsPath, entry.Name(), "up.sql")
-			if _, err := os.Stat(upFile); err == nil {
-				migrations = append(migrations, entry.Name())
+			if _, err := os.Stat(upFile); err != nil {
+				continue
+			}
+
+			if scope != "" {
+				header, err := readMigrationHeader(upFile)
+				if err != nil {
+					return nil, err
+				}
+				if header.Scope != scope {
+					continue
+				}
 			}
+
+			migrations = append(migrations, entry.Name())
 		}
 	}
 
-	// Sort migrations alphabetically (which sorts by date due to naming convention)
+	// Sort migrations alphabetically (which sorts by version due to naming convention)
 	sort.Strings(migrations)
 
 	return migrations, nil
@@ -199,7 +443,7 @@ func (m *Migrator) getAppliedMigrations() ([]string, error) {
 	query := `
 		SELECT migration_name
 		FROM public.schema_migrations
-		ORDER BY applied_at ASC
+		ORDER BY applied_at ASC, id ASC
 	`
 
 	rows, err := m.db.Query(query)
@@ -237,13 +481,107 @@ func (m *Migrator) findPendingMigrations(all []string, applied []string) []strin
 	return pending
 }
 
-// applyMigration applies a single migration
-func (m *Migrator) applyMigration(name string) error {
-	// Read migration file
-	upFile := filepath.Join(m.migrationsPath, name, "up.sql")
-	content, err := os.ReadFile(upFile)
+// getAppliedChecksums gets applied migrations with their recorded checksums
+func (m *Migrator) getAppliedChecksums() ([]appliedMigration, error) {
+	query := `
+		SELECT migration_name, checksum
+		FROM public.schema_migrations
+		ORDER BY applied_at ASC, id ASC
+	`
+
+	rows, err := m.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query migrations: %w", err)
+	}
+	defer rows.Close()
+
+	var migrations []appliedMigration
+	for rows.Next() {
+		var migration appliedMigration
+		if err := rows.Scan(&migration.name, &migration.checksum); err != nil {
+			return nil, fmt.Errorf("failed to scan migration: %w", err)
+		}
+		migrations = append(migrations, migration)
+	}
+
+	return migrations, rows.Err()
+}
+
+// checkDrift fails if an applied migration's up.sql no longer matches its checksum.
+// Migrations applied before checksums existed are backfilled with their current checksum.
+func (m *Migrator) checkDrift() error {
+	applied, err := m.getAppliedChecksums()
 	if err != nil {
-		return fmt.Errorf("failed to read migration file: %w", err)
+		return err
+	}
+
+	var drifted []string
+	for _, migration := range applied {
+		content, err := os.ReadFile(filepath.Join(m.migrationsPath, migration.name, "up.sql"))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read migration file: %w", err)
+		}
+
+		checksum := Checksum(content)
+		if !migration.checksum.Valid {
+			query := `UPDATE public.schema_migrations SET checksum = $2 WHERE migration_name = $1`
+			if _, err := m.db.Exec(query, migration.name, checksum); err != nil {
+				return fmt.Errorf("failed to backfill checksum: %w", err)
+			}
+			continue
+		}
+		if migration.checksum.String != checksum {
+			drifted = append(drifted, migration.name)
+		}
+	}
+
+	if len(drifted) > 0 {
+		return fmt.Errorf("checksum drift detected in applied migrations: %s", strings.Join(drifted, ", "))
+	}
+
+	return nil
+}
+
+// loadPlannedMigration reads the up or down SQL of a migration and its header options
+func (m *Migrator) loadPlannedMigration(name, direction string) (PlannedMigration, error) {
+	file := filepath.Join(m.migrationsPath, name, direction+".sql")
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return PlannedMigration{}, fmt.Errorf("failed to read migration file: %w", err)
+	}
+
+	header, err := readMigrationHeader(file)
+	if err != nil {
+		return PlannedMigration{}, err
+	}
+
+	return PlannedMigration{
+		Name:          name,
+		Direction:     direction,
+		NoTransaction: header.NoTransaction,
+		SQL:           string(content),
+	}, nil
+}
+
+// applyMigration applies a single migration and records its checksum
+func (m *Migrator) applyMigration(migration PlannedMigration) error {
+	ctx := context.Background()
+	checksum := Checksum([]byte(migration.SQL))
+	record := `INSERT INTO public.schema_migrations (migration_name, checksum) VALUES ($1, $2)`
+
+	// Non-transactional migrations run statement by statement, then get recorded
+	if migration.NoTransaction {
+		if err := sqlscript.Exec(ctx, m.db, migration.SQL); err != nil {
+			return err
+		}
+		if _, err := m.db.Exec(record, migration.Name, checksum); err != nil {
+			return fmt.Errorf("failed to record migration: %w", err)
+		}
+		return nil
 	}
 
 	// Begin transaction
@@ -254,13 +592,12 @@ func (m *Migrator) applyMigration(name string) error {
 	defer tx.Rollback()
 
 	// Execute migration
-	if _, err := tx.Exec(string(content)); err != nil {
+	if _, err := tx.Exec(migration.SQL); err != nil {
 		return fmt.Errorf("failed to execute migration SQL: %w", err)
 	}
 
 	// Record migration
-	query := `INSERT INTO public.schema_migrations (migration_name) VALUES ($1)`
-	if _, err := tx.Exec(query, name); err != nil {
+	if _, err := tx.Exec(record, migration.Name, checksum); err != nil {
 		return fmt.Errorf("failed to record migration: %w", err)
 	}
 
@@ -273,12 +610,18 @@ func (m *Migrator) applyMigration(name string) error {
 }
 
 // rollbackMigration rolls back a single migration
-func (m *Migrator) rollbackMigration(name string) error {
-	// Read rollback file
-	downFile := filepath.Join(m.migrationsPath, name, "down.sql")
-	content, err := os.ReadFile(downFile)
-	if err != nil {
-		return fmt.Errorf("failed to read rollback file: %w", err)
+func (m *Migrator) rollbackMigration(migration PlannedMigration) error {
+	ctx := context.Background()
+	remove := `DELETE FROM public.schema_migrations WHERE migration_name = $1`
+
+	if migration.NoTransaction {
+		if err := sqlscript.Exec(ctx, m.db, migration.SQL); err != nil {
+			return err
+		}
+		if _, err := m.db.Exec(remove, migration.Name); err != nil {
+			return fmt.Errorf("failed to remove migration record: %w", err)
+		}
+		return nil
 	}
 
 	// Begin transaction
@@ -289,13 +632,12 @@ func (m *Migrator) rollbackMigration(name string) error {
 	defer tx.Rollback()
 
 	// Execute rollback
-	if _, err := tx.Exec(string(content)); err != nil {
+	if _, err := tx.Exec(migration.SQL); err != nil {
 		return fmt.Errorf("failed to execute rollback SQL: %w", err)
 	}
 
 	// Remove migration record
-	query := `DELETE FROM public.schema_migrations WHERE migration_name = $1`
-	if _, err := tx.Exec(query, name); err != nil {
+	if _, err := tx.Exec(remove, migration.Name); err != nil {
 		return fmt.Errorf("failed to remove migration record: %w", err)
 	}
 
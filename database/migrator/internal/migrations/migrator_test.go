@@ -0,0 +1,85 @@
+package migrations
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeMigration(t *testing.T, dir, name, up string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(dir, name), 0755); err != nil {
+		t.Fatalf("failed to create migration dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name, "up.sql"), []byte(up), 0644); err != nil {
+		t.Fatalf("failed to write up.sql: %v", err)
+	}
+}
+
+func TestMigrator_ListMigrationsByScope(t *testing.T) {
+	dir := t.TempDir()
+	writeMigration(t, dir, "001_initial", "-- Migration: 001_initial\n\nCREATE TABLE a (id INT);\n")
+	writeMigration(t, dir, "002_template", "-- Migration: 002_template\n-- Scope: tenant\n\nCREATE TABLE b (id INT);\n")
+	writeMigration(t, dir, "003_more", "-- Migration: 003_more\n-- Scope: global\n\nCREATE TABLE c (id INT);\n")
+
+	m := NewMigrator(nil, dir)
+
+	global, err := m.listMigrations(ScopeGlobal)
+	if err != nil {
+		t.Fatalf("listMigrations(global) failed: %v", err)
+	}
+	if len(global) != 2 || global[0] != "001_initial" || global[1] != "003_more" {
+		t.Errorf("unexpected global migrations: %v", global)
+	}
+
+	tenant, err := m.listMigrations(ScopeTenant)
+	if err != nil {
+		t.Fatalf("listMigrations(tenant) failed: %v", err)
+	}
+	if len(tenant) != 1 || tenant[0] != "002_template" {
+		t.Errorf("unexpected tenant migrations: %v", tenant)
+	}
+}
+
+func TestMigrator_InvalidScope(t *testing.T) {
+	dir := t.TempDir()
+	writeMigration(t, dir, "001_bad", "-- Scope: everywhere\nSELECT 1;\n")
+
+	m := NewMigrator(nil, dir)
+	if _, err := m.listMigrations(ScopeGlobal); err == nil {
+		t.Error("expected an error for an invalid scope")
+	}
+}
+
+func TestMigrator_CreateNumbersSequentially(t *testing.T) {
+	dir := t.TempDir()
+	writeMigration(t, dir, "004_existing", "SELECT 1;\n")
+
+	m := NewMigrator(nil, dir)
+	name, err := m.Create("Add Widgets", ScopeTenant)
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if name != "005_add_widgets" {
+		t.Errorf("expected 005_add_widgets, got %s", name)
+	}
+
+	header, err := readMigrationHeader(filepath.Join(dir, name, "up.sql"))
+	if err != nil {
+		t.Fatalf("readMigrationHeader failed: %v", err)
+	}
+	if header.Scope != ScopeTenant {
+		t.Errorf("expected tenant scope, got %s", header.Scope)
+	}
+}
+
+func TestMigrationVersion(t *testing.T) {
+	version, err := MigrationVersion("002_tenant_template")
+	if err != nil || version != 2 {
+		t.Errorf("expected version 2, got %d (%v)", version, err)
+	}
+
+	if _, err := MigrationVersion("2025-01-01-legacy"); err == nil {
+		t.Error("expected an error for a non-numeric prefix")
+	}
+}
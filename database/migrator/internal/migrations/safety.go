@@ -0,0 +1,97 @@
+package migrations
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Header markers read from the leading comment block of an up.sql file
+const (
+	scopeHeader       = "-- Scope:"
+	transactionHeader = "-- Transaction:"
+)
+
+// migrationLockKey is the pg_advisory_lock key held for the duration of a migration run
+const migrationLockKey int64 = 3603600001
+
+// migrationHeader holds the options declared in a migration's header comments
+type migrationHeader struct {
+	Scope string
+	// NoTransaction is set by "-- Transaction: none" for statements such as
+	// CREATE INDEX CONCURRENTLY that cannot run inside a transaction block
+	NoTransaction bool
+}
+
+// readMigrationHeader reads the header comments of an up.sql file.
+// Migrations without a scope header are global and transactional.
+func readMigrationHeader(upFile string) (migrationHeader, error) {
+	header := migrationHeader{Scope: ScopeGlobal}
+
+	file, err := os.Open(upFile)
+	if err != nil {
+		return header, fmt.Errorf("failed to read migration file: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "--") {
+			break
+		}
+
+		switch {
+		case strings.HasPrefix(line, scopeHeader):
+			scope := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(line, scopeHeader)))
+			if scope != ScopeGlobal && scope != ScopeTenant {
+				return header, fmt.Errorf("invalid scope %q in %s", scope, upFile)
+			}
+			header.Scope = scope
+		case strings.HasPrefix(line, transactionHeader):
+			mode := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(line, transactionHeader)))
+			if mode != "none" {
+				return header, fmt.Errorf("invalid transaction mode %q in %s", mode, upFile)
+			}
+			header.NoTransaction = true
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return header, fmt.Errorf("failed to read migration file: %w", err)
+	}
+
+	return header, nil
+}
+
+// Checksum returns the hex SHA-256 of a migration file's contents
+func Checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// withLock runs fn while holding the migration advisory lock on a dedicated connection,
+// so two migrator processes can never apply migrations at the same time
+func (m *Migrator) withLock(fn func() error) error {
+	ctx := context.Background()
+
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrationLockKey); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, migrationLockKey)
+
+	return fn()
+}
@@ -0,0 +1,56 @@
+package migrations
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadMigrationHeader_NoTransaction(t *testing.T) {
+	dir := t.TempDir()
+	writeMigration(t, dir, "006_index", "-- Migration: 006_index\n-- Scope: tenant\n-- Transaction: none\n\nCREATE INDEX CONCURRENTLY idx_a ON a(id);\n")
+
+	header, err := readMigrationHeader(filepath.Join(dir, "006_index", "up.sql"))
+	if err != nil {
+		t.Fatalf("readMigrationHeader failed: %v", err)
+	}
+	if header.Scope != ScopeTenant || !header.NoTransaction {
+		t.Errorf("unexpected header: %+v", header)
+	}
+}
+
+func TestReadMigrationHeader_Defaults(t *testing.T) {
+	dir := t.TempDir()
+	writeMigration(t, dir, "001_plain", "CREATE TABLE a (id INT);\n-- Transaction: none\n")
+
+	header, err := readMigrationHeader(filepath.Join(dir, "001_plain", "up.sql"))
+	if err != nil {
+		t.Fatalf("readMigrationHeader failed: %v", err)
+	}
+	if header.Scope != ScopeGlobal || header.NoTransaction {
+		t.Errorf("markers after the header must be ignored: %+v", header)
+	}
+}
+
+func TestChecksum(t *testing.T) {
+	a := Checksum([]byte("CREATE TABLE a (id INT);"))
+	b := Checksum([]byte("CREATE TABLE a (id INT);"))
+	c := Checksum([]byte("CREATE TABLE a (id BIGINT);"))
+
+	if len(a) != 64 {
+		t.Errorf("expected a hex SHA-256, got %q", a)
+	}
+	if a != b {
+		t.Error("checksum should be stable")
+	}
+	if a == c {
+		t.Error("checksum should change with content")
+	}
+}
+
+func TestMigrator_ListMigrationsMissingPath(t *testing.T) {
+	m := NewMigrator(nil, filepath.Join(os.TempDir(), "does-not-exist"))
+	if _, err := m.listMigrations(ScopeGlobal); err == nil {
+		t.Error("expected an error for a missing migrations directory")
+	}
+}
@@ -0,0 +1,438 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/comply360/shared/sqlscript"
+	"github.com/lib/pq"
+)
+
+// Tenant migration result statuses
+const (
+	TenantResultSucceeded = "succeeded"
+	TenantResultFailed    = "failed"
+	TenantResultUpToDate  = "up_to_date"
+)
+
+// TenantSchema is a tenant schema registered in public.tenant_schemas
+type TenantSchema struct {
+	TenantID         string
+	SchemaName       string
+	MigrationVersion int
+	Status           string
+}
+
+// TenantSchemaStatus represents the migration status of a tenant schema
+type TenantSchemaStatus struct {
+	TenantSchema
+	Pending []string
+}
+
+// TenantUpOptions configures a tenant migration run
+type TenantUpOptions struct {
+	// Concurrency is the number of schemas migrated in parallel
+	Concurrency int
+	// Tenants limits the run to these tenant IDs or schema names (empty means all)
+	Tenants []string
+}
+
+// TenantMigrationResult is the outcome of migrating a single tenant schema
+type TenantMigrationResult struct {
+	TenantID    string   `json:"tenant_id"`
+	SchemaName  string   `json:"schema_name"`
+	Status      string   `json:"status"`
+	FromVersion int      `json:"from_version"`
+	ToVersion   int      `json:"to_version"`
+	Applied     []string `json:"applied,omitempty"`
+	Error       string   `json:"error,omitempty"`
+}
+
+// TenantMigrationReport summarises a tenant migration run. It is written to
+// disk so a partially failed run can be resumed with only the failed schemas.
+type TenantMigrationReport struct {
+	StartedAt     time.Time               `json:"started_at"`
+	FinishedAt    time.Time               `json:"finished_at"`
+	TargetVersion int                     `json:"target_version"`
+	Results       []TenantMigrationResult `json:"results"`
+}
+
+// tenantMigration is a tenant-scoped migration loaded from disk
+type tenantMigration struct {
+	name          string
+	version       int
+	noTransaction bool
+	sql           string
+}
+
+// Failed returns the results of schemas that failed to migrate
+func (r *TenantMigrationReport) Failed() []TenantMigrationResult {
+	var failed []TenantMigrationResult
+	for _, result := range r.Results {
+		if result.Status == TenantResultFailed {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+// FailedSchemas returns the schema names that failed, for resuming a run
+func (r *TenantMigrationReport) FailedSchemas() []string {
+	failed := r.Failed()
+	schemas := make([]string, len(failed))
+	for i, result := range failed {
+		schemas[i] = result.SchemaName
+	}
+	return schemas
+}
+
+// Save writes the report as JSON
+func (r *TenantMigrationReport) Save(path string) error {
+	content, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode report: %w", err)
+	}
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
+
+// LoadTenantMigrationReport reads a report written by a previous run
+func LoadTenantMigrationReport(path string) (*TenantMigrationReport, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report: %w", err)
+	}
+
+	var report TenantMigrationReport
+	if err := json.Unmarshal(content, &report); err != nil {
+		return nil, fmt.Errorf("failed to decode report: %w", err)
+	}
+
+	return &report, nil
+}
+
+// UpTenants applies pending tenant migrations to every matching tenant schema
+func (m *Migrator) UpTenants(opts TenantUpOptions) (*TenantMigrationReport, error) {
+	migrations, err := m.loadTenantMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	schemas, err := m.ListTenantSchemas(opts.Tenants)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &TenantMigrationReport{
+		StartedAt: time.Now(),
+		Results:   make([]TenantMigrationResult, len(schemas)),
+	}
+	if len(migrations) > 0 {
+		report.TargetVersion = migrations[len(migrations)-1].version
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	err = m.withLock(func() error {
+		// Fan out across schemas; results keep the schema order
+		jobs := make(chan int)
+		var wg sync.WaitGroup
+		for w := 0; w < concurrency; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range jobs {
+					report.Results[i] = m.upTenantSchema(schemas[i], migrations)
+				}
+			}()
+		}
+
+		for i := range schemas {
+			jobs <- i
+		}
+		close(jobs)
+		wg.Wait()
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report.FinishedAt = time.Now()
+
+	return report, nil
+}
+
+// PlanTenants returns the pending tenant migrations per schema without applying them
+func (m *Migrator) PlanTenants(tenants []string) (map[string][]PlannedMigration, error) {
+	migrations, err := m.loadTenantMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	schemas, err := m.ListTenantSchemas(tenants)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := make(map[string][]PlannedMigration)
+	for _, schema := range schemas {
+		for _, migration := range migrations {
+			if migration.version > schema.MigrationVersion {
+				plan[schema.SchemaName] = append(plan[schema.SchemaName], PlannedMigration{
+					Name:          migration.name,
+					Direction:     DirectionUp,
+					NoTransaction: migration.noTransaction,
+					SQL:           migration.sql,
+				})
+			}
+		}
+	}
+
+	return plan, nil
+}
+
+// TenantStatus returns the migration status of every matching tenant schema
+func (m *Migrator) TenantStatus(tenants []string) ([]TenantSchemaStatus, error) {
+	migrations, err := m.loadTenantMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	schemas, err := m.ListTenantSchemas(tenants)
+	if err != nil {
+		return nil, err
+	}
+
+	status := make([]TenantSchemaStatus, len(schemas))
+	for i, schema := range schemas {
+		status[i] = TenantSchemaStatus{TenantSchema: schema}
+		for _, migration := range migrations {
+			if migration.version > schema.MigrationVersion {
+				status[i].Pending = append(status[i].Pending, migration.name)
+			}
+		}
+	}
+
+	return status, nil
+}
+
+// ListTenantSchemas lists registered tenant schemas, optionally filtered by tenant ID or schema name.
+// Schemas still being created are left to the provisioning service.
+func (m *Migrator) ListTenantSchemas(tenants []string) ([]TenantSchema, error) {
+	query := `
+		SELECT tenant_id, schema_name, migration_version, status
+		FROM public.tenant_schemas
+		WHERE status <> 'creating'
+		  AND (cardinality($1::text[]) = 0 OR tenant_id::text = ANY($1) OR schema_name = ANY($1))
+		ORDER BY schema_name
+	`
+
+	rows, err := m.db.Query(query, pq.Array(tenants))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tenant schemas: %w", err)
+	}
+	defer rows.Close()
+
+	var schemas []TenantSchema
+	for rows.Next() {
+		var schema TenantSchema
+		if err := rows.Scan(&schema.TenantID, &schema.SchemaName, &schema.MigrationVersion, &schema.Status); err != nil {
+			return nil, fmt.Errorf("failed to scan tenant schema: %w", err)
+		}
+		schemas = append(schemas, schema)
+	}
+
+	return schemas, rows.Err()
+}
+
+// loadTenantMigrations reads all tenant-scoped migrations in version order
+func (m *Migrator) loadTenantMigrations() ([]tenantMigration, error) {
+	names, err := m.listMigrations(ScopeTenant)
+	if err != nil {
+		return nil, err
+	}
+
+	migrations := make([]tenantMigration, 0, len(names))
+	for _, name := range names {
+		version, err := MigrationVersion(name)
+		if err != nil {
+			return nil, err
+		}
+
+		planned, err := m.loadPlannedMigration(name, DirectionUp)
+		if err != nil {
+			return nil, err
+		}
+
+		migrations = append(migrations, tenantMigration{
+			name:          name,
+			version:       version,
+			noTransaction: planned.NoTransaction,
+			sql:           planned.SQL,
+		})
+	}
+
+	return migrations, nil
+}
+
+// upTenantSchema applies pending migrations to one tenant schema, one transaction per migration
+func (m *Migrator) upTenantSchema(schema TenantSchema, migrations []tenantMigration) TenantMigrationResult {
+	ctx := context.Background()
+
+	result := TenantMigrationResult{
+		TenantID:    schema.TenantID,
+		SchemaName:  schema.SchemaName,
+		Status:      TenantResultUpToDate,
+		FromVersion: schema.MigrationVersion,
+		ToVersion:   schema.MigrationVersion,
+	}
+
+	var pending []tenantMigration
+	for _, migration := range migrations {
+		if migration.version > schema.MigrationVersion {
+			pending = append(pending, migration)
+		}
+	}
+	if len(pending) == 0 {
+		return result
+	}
+
+	// A dedicated connection keeps SET LOCAL search_path off the shared pool
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return m.failTenantSchema(result, fmt.Errorf("failed to acquire connection: %w", err))
+	}
+	defer conn.Close()
+
+	m.setTenantSchemaStatus(schema.SchemaName, "migrating")
+
+	for _, migration := range pending {
+		apply := applyTenantMigration
+		if migration.noTransaction {
+			apply = applyTenantMigrationNoTx
+		}
+
+		applied, err := apply(ctx, conn, schema.SchemaName, migration)
+		if err != nil {
+			return m.failTenantSchema(result, fmt.Errorf("failed to apply migration %s: %w", migration.name, err))
+		}
+		if applied {
+			result.Applied = append(result.Applied, migration.name)
+		}
+		result.ToVersion = migration.version
+	}
+
+	m.setTenantSchemaStatus(schema.SchemaName, "active")
+	result.Status = TenantResultSucceeded
+
+	return result
+}
+
+// applyTenantMigration applies a migration inside the tenant schema and bumps its version
+// in the same transaction. Returns false if another run already applied it.
+func applyTenantMigration(ctx context.Context, conn *sql.Conn, schemaName string, migration tenantMigration) (bool, error) {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock the schema row so concurrent runners cannot apply the same migration twice
+	var version int
+	err = tx.QueryRowContext(ctx, `
+		SELECT migration_version FROM public.tenant_schemas
+		WHERE schema_name = $1
+		FOR UPDATE
+	`, schemaName).Scan(&version)
+	if err != nil {
+		return false, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	if version >= migration.version {
+		return false, nil
+	}
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`SET LOCAL search_path TO %s, public`, pq.QuoteIdentifier(schemaName))); err != nil {
+		return false, fmt.Errorf("failed to set search path: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, migration.sql); err != nil {
+		return false, fmt.Errorf("failed to execute migration SQL: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE public.tenant_schemas
+		SET migration_version = $2, updated_at = NOW()
+		WHERE schema_name = $1
+	`, schemaName, migration.version); err != nil {
+		return false, fmt.Errorf("failed to record schema version: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return true, nil
+}
+
+// applyTenantMigrationNoTx applies a non-transactional migration statement by statement.
+// The search_path is set for the session and reset before the connection is released.
+func applyTenantMigrationNoTx(ctx context.Context, conn *sql.Conn, schemaName string, migration tenantMigration) (bool, error) {
+	var version int
+	err := conn.QueryRowContext(ctx, `
+		SELECT migration_version FROM public.tenant_schemas WHERE schema_name = $1
+	`, schemaName).Scan(&version)
+	if err != nil {
+		return false, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	if version >= migration.version {
+		return false, nil
+	}
+
+	err = sqlscript.WithSearchPath(ctx, conn, schemaName, func() error {
+		if err := sqlscript.Exec(ctx, conn, migration.sql); err != nil {
+			return err
+		}
+
+		if _, err := conn.ExecContext(ctx, `
+			UPDATE public.tenant_schemas
+			SET migration_version = $2, updated_at = NOW()
+			WHERE schema_name = $1 AND migration_version < $2
+		`, schemaName, migration.version); err != nil {
+			return fmt.Errorf("failed to record schema version: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// failTenantSchema records a failed schema in the result and marks it as errored
+func (m *Migrator) failTenantSchema(result TenantMigrationResult, err error) TenantMigrationResult {
+	m.setTenantSchemaStatus(result.SchemaName, "error")
+	result.Status = TenantResultFailed
+	result.Error = err.Error()
+	return result
+}
+
+// setTenantSchemaStatus updates the status of a tenant schema (best effort)
+func (m *Migrator) setTenantSchemaStatus(schemaName, status string) {
+	query := `UPDATE public.tenant_schemas SET status = $2, updated_at = NOW() WHERE schema_name = $1`
+	if _, err := m.db.Exec(query, schemaName, status); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Failed to set status of %s to %s: %v\n", schemaName, status, err)
+	}
+}
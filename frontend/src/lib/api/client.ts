@@ -426,9 +426,9 @@ class APIClient {
 	async createCommission(data: {
 		registration_id: string;
 		agent_id: string;
-		registration_fee: number;
+		registration_fee?: number;
 		commission_rate: number;
-		currency: string;
+		currency?: string;
 	}): Promise<Commission> {
 		const response = await this.client.post<Commission>('/commissions', data);
 		return response.data;
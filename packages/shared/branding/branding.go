@@ -0,0 +1,130 @@
+package branding
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/comply360/shared/settings"
+	"github.com/google/uuid"
+)
+
+// Branding is the white-label profile applied to everything a tenant's clients see:
+// email, authenticator apps and generated documents
+type Branding struct {
+	DisplayName    string  `json:"display_name"`
+	LogoURL        *string `json:"logo_url,omitempty"`
+	PrimaryColor   string  `json:"primary_color"`
+	SecondaryColor string  `json:"secondary_color"`
+	SupportEmail   string  `json:"support_email"`
+	SenderName     string  `json:"sender_name"`
+	ReplyTo        *string `json:"reply_to,omitempty"`
+	LegalFooter    string  `json:"legal_footer,omitempty"`
+	// Custom is false when every field comes from the platform defaults
+	Custom bool `json:"custom"`
+}
+
+// Defaults returns the platform branding used when a tenant has not configured its own
+func Defaults() Branding {
+	return Branding{
+		DisplayName:    "Comply360",
+		PrimaryColor:   "#0F4C81",
+		SecondaryColor: "#00A88F",
+		SupportEmail:   "support@comply360.africa",
+		SenderName:     "Comply360",
+	}
+}
+
+// Signature is the sign-off used at the end of notification email
+func (b Branding) Signature() string {
+	return b.DisplayName + " Team"
+}
+
+// LogoPath is the gateway path serving a tenant's logo. It sits outside /api so
+// mail clients can fetch it without tenant context.
+func LogoPath(tenantID uuid.UUID) string {
+	return fmt.Sprintf("/public/tenants/%s/logo", tenantID)
+}
+
+// FromSettings builds a tenant's branding from its settings. Anything the tenant has
+// not set falls back to defaults; a custom display name also becomes the sender name
+// unless one is set explicitly. publicURL prefixes the logo URL when a logo is uploaded.
+func FromSettings(tenantID uuid.UUID, values map[string]interface{}, defaults Branding, publicURL string) Branding {
+	b := defaults
+
+	str := func(key string) (string, bool) {
+		s, ok := values[key].(string)
+		if !ok || strings.TrimSpace(s) == "" {
+			return "", false
+		}
+		b.Custom = true
+		return s, true
+	}
+
+	if name, ok := str(settings.KeyBrandingDisplayName); ok {
+		b.DisplayName = name
+		b.SenderName = name
+	}
+	if sender, ok := str(settings.KeyBrandingSenderName); ok {
+		b.SenderName = sender
+	}
+	if color, ok := str(settings.KeyBrandingPrimaryColor); ok {
+		b.PrimaryColor = color
+	}
+	if color, ok := str(settings.KeyBrandingSecondaryColor); ok {
+		b.SecondaryColor = color
+	}
+	if email, ok := str(settings.KeyBrandingSupportEmail); ok {
+		b.SupportEmail = email
+	}
+	if replyTo, ok := str(settings.KeyBrandingReplyTo); ok {
+		b.ReplyTo = &replyTo
+	}
+	if footer, ok := str(settings.KeyBrandingLegalFooter); ok {
+		b.LegalFooter = footer
+	}
+	if _, ok := str(settings.KeyBrandingLogoType); ok {
+		logoURL := strings.TrimRight(publicURL, "/") + LogoPath(tenantID)
+		b.LogoURL = &logoURL
+	}
+
+	return b
+}
+
+// Resolver looks up tenant branding through the settings client
+type Resolver struct {
+	settings  *settings.Client
+	defaults  Branding
+	publicURL string
+}
+
+// NewResolver creates a branding resolver. publicURL is the externally reachable
+// API base URL used for logo links.
+func NewResolver(client *settings.Client, defaults Branding, publicURL string) *Resolver {
+	return &Resolver{
+		settings:  client,
+		defaults:  defaults,
+		publicURL: publicURL,
+	}
+}
+
+// For returns a tenant's branding. It never fails: a nil resolver, a missing
+// tenant or a settings lookup error all yield the platform defaults, because a
+// notification should still go out with the wrong logo rather than not at all.
+func (r *Resolver) For(ctx context.Context, tenantID uuid.UUID) Branding {
+	if r == nil {
+		return Defaults()
+	}
+	if tenantID == uuid.Nil || r.settings == nil {
+		return r.defaults
+	}
+
+	values, err := r.settings.All(ctx, tenantID)
+	if err != nil {
+		log.Printf("Warning: Failed to load branding for tenant %s, using defaults: %v", tenantID, err)
+		return r.defaults
+	}
+
+	return FromSettings(tenantID, values, r.defaults, r.publicURL)
+}
@@ -0,0 +1,64 @@
+package branding
+
+import (
+	"context"
+	"testing"
+
+	"github.com/comply360/shared/settings"
+	testhelpers "github.com/comply360/shared/testing"
+	"github.com/google/uuid"
+)
+
+func TestFromSettings_FallsBackToDefaults(t *testing.T) {
+	b := FromSettings(uuid.New(), map[string]interface{}{
+		settings.KeyTimezone: "Africa/Johannesburg",
+	}, Defaults(), "https://api.comply360.africa")
+
+	testhelpers.AssertEqual(t, "Comply360", b.DisplayName)
+	testhelpers.AssertEqual(t, "Comply360 Team", b.Signature())
+	testhelpers.AssertNil(t, b.LogoURL)
+	testhelpers.AssertNil(t, b.ReplyTo)
+	testhelpers.AssertFalse(t, b.Custom, "Branding without branding settings should not be custom")
+}
+
+func TestFromSettings_AppliesTenantValues(t *testing.T) {
+	tenantID := uuid.New()
+	b := FromSettings(tenantID, map[string]interface{}{
+		settings.KeyBrandingDisplayName:  "Acme Agents",
+		settings.KeyBrandingPrimaryColor: "#112233",
+		settings.KeyBrandingReplyTo:      "help@acme.co.za",
+		settings.KeyBrandingLegalFooter:  "Acme Agents (Pty) Ltd",
+		settings.KeyBrandingLogoType:     "image/png",
+	}, Defaults(), "https://api.acme.co.za/")
+
+	testhelpers.AssertTrue(t, b.Custom, "Branding with tenant values should be custom")
+	testhelpers.AssertEqual(t, "Acme Agents", b.DisplayName)
+	testhelpers.AssertEqual(t, "Acme Agents", b.SenderName)
+	testhelpers.AssertEqual(t, "Acme Agents Team", b.Signature())
+	testhelpers.AssertEqual(t, "#112233", b.PrimaryColor)
+	testhelpers.AssertEqual(t, Defaults().SecondaryColor, b.SecondaryColor)
+	testhelpers.AssertEqual(t, Defaults().SupportEmail, b.SupportEmail)
+	testhelpers.AssertEqual(t, "help@acme.co.za", *b.ReplyTo)
+	testhelpers.AssertEqual(t, "Acme Agents (Pty) Ltd", b.LegalFooter)
+	testhelpers.AssertEqual(t, "https://api.acme.co.za/public/tenants/"+tenantID.String()+"/logo", *b.LogoURL)
+}
+
+func TestFromSettings_ExplicitSenderNameWins(t *testing.T) {
+	b := FromSettings(uuid.New(), map[string]interface{}{
+		settings.KeyBrandingDisplayName:  "Acme Agents",
+		settings.KeyBrandingSenderName:   "Acme Registrations",
+		settings.KeyBrandingSupportEmail: "   ",
+	}, Defaults(), "")
+
+	testhelpers.AssertEqual(t, "Acme Registrations", b.SenderName)
+	testhelpers.AssertEqual(t, Defaults().SupportEmail, b.SupportEmail)
+}
+
+func TestResolver_NilUsesDefaults(t *testing.T) {
+	var resolver *Resolver
+	b := resolver.For(context.Background(), uuid.New())
+	testhelpers.AssertEqual(t, Defaults().DisplayName, b.DisplayName)
+
+	b = NewResolver(nil, Defaults(), "").For(context.Background(), uuid.Nil)
+	testhelpers.AssertEqual(t, Defaults().DisplayName, b.DisplayName)
+}
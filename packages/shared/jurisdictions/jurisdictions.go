@@ -0,0 +1,123 @@
+// Package jurisdictions describes the countries companies can be registered
+// in. Each jurisdiction is a plugin saying which registration types it offers,
+// how its identity, company, tax and VAT numbers are checked, which documents a
+// registration needs and what its registry charges. Talking to the registry
+// itself is left to the integration service's registry adapters.
+package jurisdictions
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Jurisdiction is a country companies are registered in
+type Jurisdiction interface {
+	// Code is the ISO 3166-1 alpha-2 country code, such as ZA
+	Code() string
+	Name() string
+	// Currency is the currency fees are charged in
+	Currency() string
+	// VATRate is the standard VAT rate, in percent
+	VATRate() float64
+	// Timezone is the IANA timezone of the country's capital
+	Timezone() string
+	// Registry is the short name of the companies registry, such as CIPC
+	Registry() string
+	// TaxAuthority is the short name of the revenue authority, such as SARS
+	TaxAuthority() string
+
+	// RegistrationTypes are the registration types offered, in display order
+	RegistrationTypes() []string
+	// RequiresNameReservation reports whether the registry must reserve the
+	// name of a registration type before it is registered
+	RequiresNameReservation(registrationType string) bool
+	// RequiredDocuments are the documents a registration type needs
+	RequiredDocuments(registrationType string) []Document
+	// Fees are what the registry or revenue authority charges for a
+	// registration type, in Currency
+	Fees(registrationType string) []Fee
+
+	ValidateIDNumber(number string) error
+	ValidateCompanyNumber(number string) error
+	ValidateTaxNumber(number string) error
+	ValidateVATNumber(number string) error
+}
+
+// Document is a document a registration needs. With PerEntryOf set, one is
+// needed per entry of that form_data array, such as an ID document for each
+// director.
+type Document struct {
+	Type       string `json:"type"`
+	Label      string `json:"label"`
+	PerEntryOf string `json:"per_entry_of,omitempty"`
+}
+
+// Fee is an amount a registry or revenue authority charges
+type Fee struct {
+	Code        string  `json:"code"`
+	Description string  `json:"description"`
+	Amount      float64 `json:"amount"`
+}
+
+var registered = map[string]Jurisdiction{}
+
+func init() {
+	Register(SouthAfrica{})
+	Register(Zimbabwe{})
+}
+
+// Register adds a jurisdiction. It panics on a duplicate code, since
+// jurisdictions are compiled in.
+func Register(j Jurisdiction) {
+	code := strings.ToUpper(j.Code())
+	if _, ok := registered[code]; ok {
+		panic(fmt.Sprintf("jurisdiction %s registered twice", code))
+	}
+	registered[code] = j
+}
+
+// Get returns the jurisdiction with a country code, in either case
+func Get(code string) (Jurisdiction, bool) {
+	j, ok := registered[strings.ToUpper(code)]
+	return j, ok
+}
+
+// Lookup returns the jurisdiction with a country code, or an error naming it
+func Lookup(code string) (Jurisdiction, error) {
+	j, ok := Get(code)
+	if !ok {
+		return nil, fmt.Errorf("unsupported jurisdiction %q", code)
+	}
+	return j, nil
+}
+
+// All returns every jurisdiction, ordered by code
+func All() []Jurisdiction {
+	all := make([]Jurisdiction, 0, len(registered))
+	for _, j := range registered {
+		all = append(all, j)
+	}
+	sort.Slice(all, func(a, b int) bool { return all[a].Code() < all[b].Code() })
+	return all
+}
+
+// Offers reports whether a jurisdiction offers a registration type
+func Offers(j Jurisdiction, registrationType string) bool {
+	for _, t := range j.RegistrationTypes() {
+		if t == registrationType {
+			return true
+		}
+	}
+	return false
+}
+
+// digits reports whether s is all ASCII digits
+func digits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,84 @@
+package jurisdictions
+
+import (
+	"testing"
+
+	"github.com/comply360/shared/models"
+	testhelpers "github.com/comply360/shared/testing"
+)
+
+func TestGet(t *testing.T) {
+	za, ok := Get("za")
+	testhelpers.AssertTrue(t, ok, "Codes are matched in either case")
+	testhelpers.AssertEqual(t, "CIPC", za.Registry())
+
+	zw, err := Lookup(models.JurisdictionZimbabwe)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "DCIP", zw.Registry())
+	testhelpers.AssertEqual(t, models.CurrencyUSD, zw.Currency())
+
+	_, err = Lookup("BW")
+	testhelpers.AssertError(t, err)
+
+	all := All()
+	testhelpers.AssertEqual(t, 2, len(all))
+	testhelpers.AssertEqual(t, "ZA", all[0].Code())
+	testhelpers.AssertEqual(t, "ZW", all[1].Code())
+}
+
+func TestRegistrationTypes(t *testing.T) {
+	za, zw := SouthAfrica{}, Zimbabwe{}
+	testhelpers.AssertTrue(t, Offers(za, models.RegistrationTypeCloseCorporation))
+	testhelpers.AssertFalse(t, Offers(zw, models.RegistrationTypeCloseCorporation), "Zimbabwe has no close corporations")
+
+	for _, j := range All() {
+		for _, registrationType := range j.RegistrationTypes() {
+			testhelpers.AssertTrue(t, len(j.RequiredDocuments(registrationType)) > 0, j.Code()+"/"+registrationType+" needs documents")
+		}
+		testhelpers.AssertEqual(t, 0, len(j.RequiredDocuments("partnership")))
+	}
+
+	testhelpers.AssertTrue(t, za.RequiresNameReservation(models.RegistrationTypeCloseCorporation))
+	testhelpers.AssertTrue(t, zw.RequiresNameReservation(models.RegistrationTypePtyLtd))
+	testhelpers.AssertFalse(t, zw.RequiresNameReservation(models.RegistrationTypeBusinessName))
+}
+
+func TestFees(t *testing.T) {
+	fees := Zimbabwe{}.Fees(models.RegistrationTypePtyLtd)
+	testhelpers.AssertEqual(t, 2, len(fees))
+	testhelpers.AssertEqual(t, "name_reservation", fees[0].Code)
+	testhelpers.AssertEqual(t, 0, len(SouthAfrica{}.Fees(models.RegistrationTypeVATRegistration)), "SARS charges nothing")
+	testhelpers.AssertEqual(t, 15.0, SouthAfrica{}.VATRate())
+}
+
+func TestSouthAfrica_Numbers(t *testing.T) {
+	za := SouthAfrica{}
+	testhelpers.AssertNoError(t, za.ValidateIDNumber("8001015009087"))
+	testhelpers.AssertError(t, za.ValidateIDNumber("8001015009088"), "Wrong check digit")
+	testhelpers.AssertError(t, za.ValidateIDNumber("800101500908"))
+
+	testhelpers.AssertNoError(t, za.ValidateCompanyNumber("2020/123456/07"))
+	testhelpers.AssertError(t, za.ValidateCompanyNumber("2020123456"))
+
+	testhelpers.AssertNoError(t, za.ValidateTaxNumber("0123456789"))
+	testhelpers.AssertError(t, za.ValidateTaxNumber("5123456789"))
+
+	testhelpers.AssertNoError(t, za.ValidateVATNumber("4123456789"))
+	testhelpers.AssertError(t, za.ValidateVATNumber("5123456789"))
+}
+
+func TestZimbabwe_Numbers(t *testing.T) {
+	zw := Zimbabwe{}
+	testhelpers.AssertNoError(t, zw.ValidateIDNumber("63-123456-A-42"))
+	testhelpers.AssertNoError(t, zw.ValidateIDNumber("631234567A42"))
+	testhelpers.AssertError(t, zw.ValidateIDNumber("63-123456-42"))
+
+	testhelpers.AssertNoError(t, zw.ValidateCompanyNumber("1234/2019"))
+	testhelpers.AssertError(t, zw.ValidateCompanyNumber("2020/123456/07"), "CIPC numbers are not DCIP numbers")
+
+	testhelpers.AssertNoError(t, zw.ValidateTaxNumber("200123456"))
+	testhelpers.AssertError(t, zw.ValidateTaxNumber("20012345"))
+
+	testhelpers.AssertNoError(t, zw.ValidateVATNumber("10012345"))
+	testhelpers.AssertError(t, zw.ValidateVATNumber("4123456789"))
+}
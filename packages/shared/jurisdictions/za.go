@@ -0,0 +1,143 @@
+package jurisdictions
+
+import (
+	"fmt"
+
+	"github.com/comply360/shared/models"
+)
+
+// SouthAfrica registers companies with CIPC and VAT vendors with SARS
+type SouthAfrica struct{}
+
+func (SouthAfrica) Code() string         { return models.JurisdictionSouthAfrica }
+func (SouthAfrica) Name() string         { return "South Africa" }
+func (SouthAfrica) Currency() string     { return models.CurrencyZAR }
+func (SouthAfrica) VATRate() float64     { return 15 }
+func (SouthAfrica) Timezone() string     { return "Africa/Johannesburg" }
+func (SouthAfrica) Registry() string     { return "CIPC" }
+func (SouthAfrica) TaxAuthority() string { return "SARS" }
+
+func (SouthAfrica) RegistrationTypes() []string {
+	return []string{
+		models.RegistrationTypePtyLtd,
+		models.RegistrationTypeCloseCorporation,
+		models.RegistrationTypeBusinessName,
+		models.RegistrationTypeVATRegistration,
+	}
+}
+
+func (SouthAfrica) RequiresNameReservation(registrationType string) bool {
+	return registrationType == models.RegistrationTypePtyLtd ||
+		registrationType == models.RegistrationTypeCloseCorporation
+}
+
+func (SouthAfrica) RequiredDocuments(registrationType string) []Document {
+	switch registrationType {
+	case models.RegistrationTypePtyLtd:
+		return []Document{
+			{Type: models.DocumentTypeID, Label: "ID document for each director", PerEntryOf: "directors"},
+			{Type: models.DocumentTypeProofOfAddress, Label: "Proof of registered address"},
+		}
+	case models.RegistrationTypeCloseCorporation:
+		return []Document{
+			{Type: models.DocumentTypeID, Label: "ID document for each member", PerEntryOf: "members"},
+			{Type: models.DocumentTypeProofOfAddress, Label: "Proof of registered address"},
+		}
+	case models.RegistrationTypeBusinessName:
+		return []Document{
+			{Type: models.DocumentTypeID, Label: "Owner ID document"},
+			{Type: models.DocumentTypeProofOfAddress, Label: "Proof of business address"},
+		}
+	case models.RegistrationTypeVATRegistration:
+		return []Document{
+			{Type: models.DocumentTypeCIPCDocuments, Label: "CIPC registration documents"},
+			{Type: models.DocumentTypeBankStatement, Label: "Bank statement"},
+			{Type: models.DocumentTypeID, Label: "Public officer ID document"},
+			{Type: models.DocumentTypeProofOfAddress, Label: "Proof of business address"},
+		}
+	}
+	return nil
+}
+
+// Fees are CIPC's e-services fees. SARS charges nothing to register a VAT
+// vendor.
+func (SouthAfrica) Fees(registrationType string) []Fee {
+	switch registrationType {
+	case models.RegistrationTypePtyLtd, models.RegistrationTypeCloseCorporation:
+		return []Fee{
+			{Code: "name_reservation", Description: "CIPC name reservation", Amount: 50},
+			{Code: "incorporation", Description: "CIPC incorporation", Amount: 175},
+		}
+	case models.RegistrationTypeBusinessName:
+		return []Fee{
+			{Code: "business_name", Description: "CIPC business name registration", Amount: 50},
+		}
+	}
+	return nil
+}
+
+// ValidateIDNumber checks a 13-digit identity number and its Luhn check digit
+func (SouthAfrica) ValidateIDNumber(number string) error {
+	if len(number) != 13 || !digits(number) {
+		return fmt.Errorf("South African ID numbers have 13 digits")
+	}
+
+	sum := 0
+	for i := 0; i < 12; i++ {
+		digit := int(number[i] - '0')
+		if i%2 == 0 {
+			sum += digit
+		} else {
+			doubled := digit * 2
+			if doubled > 9 {
+				doubled -= 9
+			}
+			sum += doubled
+		}
+	}
+	if checkDigit := (10 - (sum % 10)) % 10; checkDigit != int(number[12]-'0') {
+		return fmt.Errorf("ID number check digit is wrong")
+	}
+	return nil
+}
+
+// ValidateCompanyNumber checks the shape of a CIPC registration number,
+// YYYY/NNNNNN/NN such as 2020/123456/07
+func (SouthAfrica) ValidateCompanyNumber(number string) error {
+	if len(number) < 10 || len(number) > 15 {
+		return fmt.Errorf("CIPC registration numbers look like 2020/123456/07")
+	}
+
+	hasSlash, hasNumber := false, false
+	for _, char := range number {
+		if char == '/' {
+			hasSlash = true
+		} else if char >= '0' && char <= '9' {
+			hasNumber = true
+		}
+	}
+	if !hasSlash || !hasNumber {
+		return fmt.Errorf("CIPC registration numbers look like 2020/123456/07")
+	}
+	return nil
+}
+
+// ValidateTaxNumber checks a 10-digit SARS income tax reference number
+func (SouthAfrica) ValidateTaxNumber(number string) error {
+	if len(number) != 10 || !digits(number) {
+		return fmt.Errorf("SARS tax reference numbers have 10 digits")
+	}
+	switch number[0] {
+	case '0', '1', '2', '3', '9':
+		return nil
+	}
+	return fmt.Errorf("SARS tax reference numbers start with 0, 1, 2, 3 or 9")
+}
+
+// ValidateVATNumber checks a 10-digit SARS VAT number starting with 4
+func (SouthAfrica) ValidateVATNumber(number string) error {
+	if len(number) != 10 || !digits(number) || number[0] != '4' {
+		return fmt.Errorf("SARS VAT numbers have 10 digits and start with 4")
+	}
+	return nil
+}
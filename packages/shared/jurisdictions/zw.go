@@ -0,0 +1,114 @@
+package jurisdictions
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/comply360/shared/models"
+)
+
+var (
+	zwIDNumber      = regexp.MustCompile(`^[0-9]{2}-?[0-9]{6,7}-?[A-Z]-?[0-9]{2}$`)
+	zwCompanyNumber = regexp.MustCompile(`^[0-9]{1,6}/[0-9]{4}$`)
+	zwBPNumber      = regexp.MustCompile(`^[0-9]{9,10}$`)
+	zwVATNumber     = regexp.MustCompile(`^[0-9]{8}$`)
+)
+
+// Zimbabwe registers companies with the Deeds, Companies and Intellectual
+// Property office (DCIP) and VAT operators with ZIMRA. Fees are charged in US
+// dollars, which the registry quotes alongside the local currency.
+type Zimbabwe struct{}
+
+func (Zimbabwe) Code() string         { return models.JurisdictionZimbabwe }
+func (Zimbabwe) Name() string         { return "Zimbabwe" }
+func (Zimbabwe) Currency() string     { return models.CurrencyUSD }
+func (Zimbabwe) VATRate() float64     { return 15 }
+func (Zimbabwe) Timezone() string     { return "Africa/Harare" }
+func (Zimbabwe) Registry() string     { return "DCIP" }
+func (Zimbabwe) TaxAuthority() string { return "ZIMRA" }
+
+func (Zimbabwe) RegistrationTypes() []string {
+	return []string{
+		models.RegistrationTypePtyLtd,
+		models.RegistrationTypeBusinessName,
+		models.RegistrationTypeVATRegistration,
+	}
+}
+
+// RequiresNameReservation reports whether DCIP must approve a name search
+// first, as it must for companies
+func (Zimbabwe) RequiresNameReservation(registrationType string) bool {
+	return registrationType == models.RegistrationTypePtyLtd
+}
+
+func (Zimbabwe) RequiredDocuments(registrationType string) []Document {
+	switch registrationType {
+	case models.RegistrationTypePtyLtd:
+		return []Document{
+			{Type: models.DocumentTypeID, Label: "ID document for each director", PerEntryOf: "directors"},
+			{Type: models.DocumentTypeProofOfAddress, Label: "Proof of registered address"},
+		}
+	case models.RegistrationTypeBusinessName:
+		return []Document{
+			{Type: models.DocumentTypeID, Label: "Owner ID document"},
+			{Type: models.DocumentTypeProofOfAddress, Label: "Proof of business address"},
+		}
+	case models.RegistrationTypeVATRegistration:
+		return []Document{
+			{Type: models.DocumentTypeCompanyDocs, Label: "Company registration documents"},
+			{Type: models.DocumentTypeTaxClearance, Label: "Tax clearance certificate"},
+			{Type: models.DocumentTypeBankStatement, Label: "Bank statement"},
+		}
+	}
+	return nil
+}
+
+// Fees are DCIP's fees. ZIMRA charges nothing to register a VAT operator.
+func (Zimbabwe) Fees(registrationType string) []Fee {
+	switch registrationType {
+	case models.RegistrationTypePtyLtd:
+		return []Fee{
+			{Code: "name_reservation", Description: "DCIP name search", Amount: 5},
+			{Code: "incorporation", Description: "DCIP incorporation", Amount: 60},
+		}
+	case models.RegistrationTypeBusinessName:
+		return []Fee{
+			{Code: "business_name", Description: "DCIP business name registration", Amount: 10},
+		}
+	}
+	return nil
+}
+
+// ValidateIDNumber checks the shape of a national registration number, such as
+// 63-123456-A-42, with or without the dashes
+func (Zimbabwe) ValidateIDNumber(number string) error {
+	if !zwIDNumber.MatchString(number) {
+		return fmt.Errorf("Zimbabwean ID numbers look like 63-123456-A-42")
+	}
+	return nil
+}
+
+// ValidateCompanyNumber checks a DCIP registration number, the company's
+// number and year of registration such as 1234/2019
+func (Zimbabwe) ValidateCompanyNumber(number string) error {
+	if !zwCompanyNumber.MatchString(number) {
+		return fmt.Errorf("DCIP registration numbers look like 1234/2019")
+	}
+	return nil
+}
+
+// ValidateTaxNumber checks a ZIMRA business partner number
+func (Zimbabwe) ValidateTaxNumber(number string) error {
+	if !zwBPNumber.MatchString(number) {
+		return fmt.Errorf("ZIMRA business partner numbers have 9 or 10 digits")
+	}
+	return nil
+}
+
+// ValidateVATNumber checks an 8-digit ZIMRA VAT number
+func (Zimbabwe) ValidateVATNumber(number string) error {
+	if !zwVATNumber.MatchString(number) {
+		return fmt.Errorf("ZIMRA VAT numbers have 8 digits")
+	}
+	return nil
+}
@@ -16,6 +16,9 @@ const (
 	UserIDKey    = "user_id"
 	UserEmailKey = "user_email"
 	UserRolesKey = "user_roles"
+	// UserTenantIDKey is the tenant the token was issued for, kept even when
+	// TenantAccessMiddleware switches the request to a descendant tenant
+	UserTenantIDKey = "user_tenant_id"
 )
 
 // AuthMiddleware validates JWT tokens and sets user context
@@ -88,6 +91,7 @@ func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
 			tenantID, err := uuid.Parse(tenantIDStr)
 			if err == nil {
 				c.Set(TenantIDKey, tenantID)
+				c.Set(UserTenantIDKey, tenantID)
 			}
 		}
 
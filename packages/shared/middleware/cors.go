@@ -8,7 +8,10 @@ import (
 
 // CORSConfig holds CORS configuration
 type CORSConfig struct {
-	AllowedOrigins   []string
+	AllowedOrigins []string
+	// AllowOriginFunc is consulted for origins not in AllowedOrigins,
+	// e.g. VerifiedDomains.AllowOrigin for tenant custom domains
+	AllowOriginFunc  func(origin string) bool
 	AllowCredentials bool
 	MaxAge           time.Duration
 }
@@ -32,7 +35,8 @@ func DefaultCORSConfig() CORSConfig {
 // CORS returns a CORS middleware configured for the application
 func CORS(config CORSConfig) gin.HandlerFunc {
 	corsConfig := cors.Config{
-		AllowOrigins: config.AllowedOrigins,
+		AllowOrigins:    config.AllowedOrigins,
+		AllowOriginFunc: config.AllowOriginFunc,
 		AllowMethods: []string{
 			"GET",
 			"POST",
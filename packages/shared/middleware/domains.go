@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"database/sql"
+	"log"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// VerifiedDomains is a periodically refreshed set of verified tenant custom domains,
+// used to allow those domains as CORS origins
+type VerifiedDomains struct {
+	db  *sql.DB
+	ttl time.Duration
+
+	mu        sync.RWMutex
+	domains   map[string]struct{}
+	refreshed time.Time
+}
+
+// NewVerifiedDomains creates a verified domain set that reloads from the database every ttl
+func NewVerifiedDomains(db *sql.DB, ttl time.Duration) *VerifiedDomains {
+	return &VerifiedDomains{
+		db:      db,
+		ttl:     ttl,
+		domains: make(map[string]struct{}),
+	}
+}
+
+// AllowOrigin reports whether an Origin header belongs to a verified custom domain
+func (v *VerifiedDomains) AllowOrigin(origin string) bool {
+	host := originHost(origin)
+	if host == "" {
+		return false
+	}
+
+	v.refreshIfStale()
+
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	_, ok := v.domains[host]
+	return ok
+}
+
+// refreshIfStale reloads the domain set once the ttl has elapsed.
+// On failure the previous set is kept until the next attempt.
+func (v *VerifiedDomains) refreshIfStale() {
+	v.mu.RLock()
+	fresh := time.Since(v.refreshed) < v.ttl
+	v.mu.RUnlock()
+	if fresh {
+		return
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if time.Since(v.refreshed) < v.ttl {
+		return
+	}
+	v.refreshed = time.Now()
+
+	domains, err := loadVerifiedDomains(v.db)
+	if err != nil {
+		log.Printf("Warning: Failed to load verified domains: %v", err)
+		return
+	}
+	v.domains = domains
+}
+
+// loadVerifiedDomains reads every verified custom domain of a live tenant
+func loadVerifiedDomains(db *sql.DB) (map[string]struct{}, error) {
+	query := `
+		SELECT LOWER(domain) FROM public.tenants
+		WHERE domain IS NOT NULL AND domain_status = 'verified' AND deleted_at IS NULL
+	`
+
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	domains := make(map[string]struct{})
+	for rows.Next() {
+		var domain string
+		if err := rows.Scan(&domain); err != nil {
+			return nil, err
+		}
+		domains[domain] = struct{}{}
+	}
+
+	return domains, rows.Err()
+}
+
+// originHost extracts the lowercased host, without port, from an Origin header
+func originHost(origin string) string {
+	u, err := url.Parse(origin)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/comply360/shared/errors"
+	"github.com/comply360/shared/models"
+	"github.com/comply360/shared/tenancy"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ActingTenantIDKey holds the user's own tenant while it acts on a descendant tenant
+const ActingTenantIDKey = "acting_tenant_id"
+
+// TenantAccessMiddleware checks that the authenticated user may act on the tenant
+// resolved for the request. It runs after TenantResolverMiddleware and AuthMiddleware.
+//
+// Users act on their own tenant as before. A global_admin may act on any tenant, and
+// a tenant_admin may act on descendants of their own tenant; the request then runs
+// with the descendant's tenant context and ActingTenantIDKey set, so RLS still
+// isolates on the target tenant. Everyone else is refused rather than silently
+// getting an empty result.
+func TenantAccessMiddleware(resolver *tenancy.Resolver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tenant, err := GetTenant(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errors.NewAPIError(
+				errors.ErrTenantNotFound,
+				"Tenant context not found",
+			))
+			c.Abort()
+			return
+		}
+
+		userTenantID, hasUserTenant := c.Get(UserTenantIDKey)
+		roles, _ := GetUserRoles(c)
+
+		if hasUserTenant && userTenantID.(uuid.UUID) == tenant.ID {
+			c.Next()
+			return
+		}
+
+		switch {
+		case hasRole(roles, models.RoleGlobalAdmin):
+			// Global admins need no tenant of their own
+		case hasUserTenant && hasRole(roles, models.RoleTenantAdmin):
+			ancestor, err := resolver.IsAncestor(c.Request.Context(), userTenantID.(uuid.UUID), tenant.ID)
+			if err != nil || !ancestor {
+				forbidTenant(c)
+				return
+			}
+		default:
+			forbidTenant(c)
+			return
+		}
+
+		c.Set(TenantIDKey, tenant.ID)
+		if hasUserTenant {
+			c.Set(ActingTenantIDKey, userTenantID.(uuid.UUID))
+		}
+
+		c.Next()
+	}
+}
+
+// TenancyContext builds the RLS context for the request's user and tenant
+func TenancyContext(c *gin.Context) (tenancy.Context, error) {
+	tenantID, err := GetTenantID(c)
+	if err != nil {
+		return tenancy.Context{}, err
+	}
+
+	tc := tenancy.Context{TenantID: tenantID}
+	if userID, err := GetUserID(c); err == nil {
+		tc.UserID = &userID
+	}
+	if roles, err := GetUserRoles(c); err == nil {
+		tc.Roles = roles
+		tc.IsGlobalAdmin = hasRole(roles, models.RoleGlobalAdmin)
+	}
+	if acting, ok := c.Get(ActingTenantIDKey); ok {
+		actingID, ok := acting.(uuid.UUID)
+		if !ok {
+			return tenancy.Context{}, fmt.Errorf("invalid acting tenant ID type in context")
+		}
+		tc.ActingTenantID = &actingID
+	}
+
+	return tc, nil
+}
+
+// GetActingTenantID returns the user's own tenant when it acts on a descendant tenant
+func GetActingTenantID(c *gin.Context) (uuid.UUID, bool) {
+	acting, ok := c.Get(ActingTenantIDKey)
+	if !ok {
+		return uuid.Nil, false
+	}
+	id, ok := acting.(uuid.UUID)
+	return id, ok
+}
+
+func hasRole(roles []string, role string) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+func forbidTenant(c *gin.Context) {
+	c.JSON(http.StatusForbidden, errors.Forbidden("You do not have access to this tenant"))
+	c.Abort()
+}
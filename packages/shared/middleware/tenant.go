@@ -8,30 +8,47 @@ import (
 
 	"github.com/comply360/shared/errors"
 	"github.com/comply360/shared/models"
+	"github.com/comply360/shared/tenancy"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
 const (
 	// Context keys
-	TenantIDKey   = "tenant_id"
-	TenantKey     = "tenant"
-	SubdomainKey  = "subdomain"
+	TenantIDKey  = "tenant_id"
+	TenantKey    = "tenant"
+	SubdomainKey = "subdomain"
 )
 
-// TenantMiddleware extracts tenant context from subdomain or header
+// TenantMiddleware extracts tenant context from subdomain or header, without caching
 func TenantMiddleware(db *sql.DB) gin.HandlerFunc {
+	return TenantResolverMiddleware(tenancy.NewResolver(db, nil, tenancy.ResolverOptions{}))
+}
+
+// TenantResolverMiddleware extracts tenant context using a (cached) tenant resolver.
+// It does not touch the database session: repositories apply the RLS context per
+// transaction with tenancy.WithTenant.
+func TenantResolverMiddleware(resolver *tenancy.Resolver) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var tenantID uuid.UUID
 		var tenant *models.Tenant
+		ctx := c.Request.Context()
 
-		// Method 1: Extract from subdomain
+		// Method 1: Verified custom domain
 		host := c.Request.Host
-		subdomain := extractSubdomain(host)
+		subdomain := ""
+		if t, err := resolver.ByDomain(ctx, stripPort(host)); err == nil {
+			tenant = t
+			tenantID = t.ID
+			subdomain = t.Subdomain
+		} else {
+			subdomain = extractSubdomain(host)
+		}
 
-		if subdomain != "" && subdomain != "www" && subdomain != "api" {
+		// Method 2: Extract from subdomain
+		if tenantID == uuid.Nil && subdomain != "" && subdomain != "www" && subdomain != "api" {
 			// Lookup tenant by subdomain
-			t, err := getTenantBySubdomain(db, subdomain)
+			t, err := resolver.BySubdomain(ctx, subdomain)
 			if err != nil {
 				c.JSON(http.StatusNotFound, errors.NewAPIError(
 					errors.ErrTenantNotFound,
@@ -44,7 +61,7 @@ func TenantMiddleware(db *sql.DB) gin.HandlerFunc {
 			tenantID = t.ID
 		}
 
-		// Method 2: Extract from X-Tenant-ID header (for API calls, admin operations)
+		// Method 3: Extract from X-Tenant-ID header (for API calls, admin operations)
 		if tenantID == uuid.Nil {
 			tenantIDHeader := c.GetHeader("X-Tenant-ID")
 			if tenantIDHeader != "" {
@@ -59,7 +76,7 @@ func TenantMiddleware(db *sql.DB) gin.HandlerFunc {
 				}
 
 				// Lookup tenant by ID
-				t, err := getTenantByID(db, id)
+				t, err := resolver.ByID(ctx, id)
 				if err != nil {
 					c.JSON(http.StatusNotFound, errors.NewAPIError(
 						errors.ErrTenantNotFound,
@@ -73,7 +90,7 @@ func TenantMiddleware(db *sql.DB) gin.HandlerFunc {
 			}
 		}
 
-		// Method 3: Extract from JWT token (will be set by AuthMiddleware)
+		// Method 4: Extract from JWT token (will be set by AuthMiddleware)
 		// This is handled after AuthMiddleware runs
 
 		if tenantID == uuid.Nil {
@@ -85,6 +102,24 @@ func TenantMiddleware(db *sql.DB) gin.HandlerFunc {
 			return
 		}
 
+		// Suspended tenants, and tenants awaiting deletion, are blocked with the reason
+		if tenant.IsSuspended() {
+			details := gin.H{"status": tenant.Status}
+			if tenant.SuspensionReason != nil {
+				details["reason"] = *tenant.SuspensionReason
+			}
+			if tenant.PurgeAfter != nil {
+				details["purge_after"] = tenant.PurgeAfter
+			}
+			c.JSON(http.StatusForbidden, errors.NewAPIErrorWithDetails(
+				errors.ErrTenantSuspended,
+				"Tenant account is suspended",
+				details,
+			))
+			c.Abort()
+			return
+		}
+
 		// Check if tenant is active
 		if !tenant.IsActive() {
 			c.JSON(http.StatusForbidden, errors.NewAPIError(
@@ -101,27 +136,22 @@ func TenantMiddleware(db *sql.DB) gin.HandlerFunc {
 		c.Set(SubdomainKey, subdomain)
 		c.Set("tenant_schema", tenant.TenantSchema())
 
-		// Set PostgreSQL session variable for RLS
-		if err := setTenantContext(db, tenantID); err != nil {
-			c.JSON(http.StatusInternalServerError, errors.NewAPIError(
-				errors.ErrInternal,
-				"Failed to set tenant context",
-			))
-			c.Abort()
-			return
-		}
-
 		c.Next()
 	}
 }
 
+// stripPort removes the port from a host, if present
+func stripPort(host string) string {
+	if idx := strings.Index(host, ":"); idx != -1 {
+		return host[:idx]
+	}
+	return host
+}
+
 // extractSubdomain extracts subdomain from hostname
 // Example: agentname.comply360.com -> agentname
 func extractSubdomain(host string) string {
-	// Remove port if present
-	if idx := strings.Index(host, ":"); idx != -1 {
-		host = host[:idx]
-	}
+	host = stripPort(host)
 
 	parts := strings.Split(host, ".")
 	if len(parts) >= 3 {
@@ -136,91 +166,6 @@ func extractSubdomain(host string) string {
 	return ""
 }
 
-// getTenantBySubdomain fetches tenant from database by subdomain
-func getTenantBySubdomain(db *sql.DB, subdomain string) (*models.Tenant, error) {
-	var tenant models.Tenant
-
-	query := `
-		SELECT id, name, subdomain, domain, status, subscription_tier,
-		       company_name, contact_email, contact_phone, country,
-		       max_users, created_at, updated_at, deleted_at
-		FROM public.tenants
-		WHERE subdomain = $1 AND deleted_at IS NULL
-	`
-
-	err := db.QueryRow(query, subdomain).Scan(
-		&tenant.ID,
-		&tenant.Name,
-		&tenant.Subdomain,
-		&tenant.Domain,
-		&tenant.Status,
-		&tenant.SubscriptionTier,
-		&tenant.CompanyName,
-		&tenant.ContactEmail,
-		&tenant.ContactPhone,
-		&tenant.Country,
-		&tenant.MaxUsers,
-		&tenant.CreatedAt,
-		&tenant.UpdatedAt,
-		&tenant.DeletedAt,
-	)
-
-	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("tenant not found")
-	}
-	if err != nil {
-		return nil, err
-	}
-
-	return &tenant, nil
-}
-
-// getTenantByID fetches tenant from database by ID
-func getTenantByID(db *sql.DB, tenantID uuid.UUID) (*models.Tenant, error) {
-	var tenant models.Tenant
-
-	query := `
-		SELECT id, name, subdomain, domain, status, subscription_tier,
-		       company_name, contact_email, contact_phone, country,
-		       max_users, created_at, updated_at, deleted_at
-		FROM public.tenants
-		WHERE id = $1 AND deleted_at IS NULL
-	`
-
-	err := db.QueryRow(query, tenantID).Scan(
-		&tenant.ID,
-		&tenant.Name,
-		&tenant.Subdomain,
-		&tenant.Domain,
-		&tenant.Status,
-		&tenant.SubscriptionTier,
-		&tenant.CompanyName,
-		&tenant.ContactEmail,
-		&tenant.ContactPhone,
-		&tenant.Country,
-		&tenant.MaxUsers,
-		&tenant.CreatedAt,
-		&tenant.UpdatedAt,
-		&tenant.DeletedAt,
-	)
-
-	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("tenant not found")
-	}
-	if err != nil {
-		return nil, err
-	}
-
-	return &tenant, nil
-}
-
-// setTenantContext sets the PostgreSQL session variable for RLS
-func setTenantContext(db *sql.DB, tenantID uuid.UUID) error {
-	query := `SELECT set_tenant_context($1)`
-	_, err := db.Exec(query, tenantID)
-	return err
-}
-
 // GetTenantID retrieves tenant ID from Gin context
 func GetTenantID(c *gin.Context) (uuid.UUID, error) {
 	tenantID, exists := c.Get(TenantIDKey)
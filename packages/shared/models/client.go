@@ -0,0 +1,105 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Client is an individual or company an agent registers businesses for
+type Client struct {
+	ID             uuid.UUID  `json:"id" db:"id"`
+	TenantID       uuid.UUID  `json:"tenant_id" db:"tenant_id"`
+	ClientType     string     `json:"client_type" db:"client_type"`
+	FullName       *string    `json:"full_name,omitempty" db:"full_name"`
+	CompanyName    *string    `json:"company_name,omitempty" db:"company_name"`
+	IDNumber       *string    `json:"id_number,omitempty" db:"id_number"`
+	PassportNumber *string    `json:"passport_number,omitempty" db:"passport_number"`
+	TaxNumber      *string    `json:"tax_number,omitempty" db:"tax_number"`
+	VATNumber      *string    `json:"vat_number,omitempty" db:"vat_number"`
+	Email          string     `json:"email" db:"email"`
+	Phone          *string    `json:"phone,omitempty" db:"phone"`
+	Mobile         *string    `json:"mobile,omitempty" db:"mobile"`
+	StreetAddress  *string    `json:"street_address,omitempty" db:"street_address"`
+	City           *string    `json:"city,omitempty" db:"city"`
+	StateProvince  *string    `json:"state_province,omitempty" db:"state_province"`
+	PostalCode     *string    `json:"postal_code,omitempty" db:"postal_code"`
+	Country        *string    `json:"country,omitempty" db:"country"`
+	Status         string     `json:"status" db:"status"`
+	UserID         *uuid.UUID `json:"user_id,omitempty" db:"user_id"`
+	MergedInto     *uuid.UUID `json:"merged_into,omitempty" db:"merged_into"`
+	KYC            *ClientKYC `json:"kyc,omitempty" db:"-"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at" db:"updated_at"`
+	DeletedAt      *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+}
+
+// ClientType constants
+const (
+	ClientTypeIndividual = "individual"
+	ClientTypeCompany    = "company"
+)
+
+// ClientStatus constants
+const (
+	ClientStatusActive    = "active"
+	ClientStatusInactive  = "inactive"
+	ClientStatusSuspended = "suspended"
+)
+
+// ClientKYC status constants. A client is verified once every document KYC
+// requires of its type is verified.
+const (
+	KYCStatusNotStarted = "not_started"
+	KYCStatusPending    = "pending"
+	KYCStatusVerified   = "verified"
+	KYCStatusRejected   = "rejected"
+	KYCStatusExpired    = "expired"
+)
+
+// KYCRequirementMissing is the status of a required document not yet uploaded
+const KYCRequirementMissing = "missing"
+
+// ClientKYC is a client's KYC profile, aggregated from the documents uploaded
+// for the client and its registrations
+type ClientKYC struct {
+	Status       string                 `json:"status"`
+	Requirements []ClientKYCRequirement `json:"requirements"`
+	VerifiedAt   *time.Time             `json:"verified_at,omitempty"`
+}
+
+// ClientKYCRequirement is a document KYC requires and the best document
+// uploaded for it
+type ClientKYCRequirement struct {
+	DocumentType string     `json:"document_type"`
+	Status       string     `json:"status"`
+	DocumentID   *uuid.UUID `json:"document_id,omitempty"`
+	VerifiedAt   *time.Time `json:"verified_at,omitempty"`
+}
+
+// Client duplicate reasons name the fields two clients share
+const (
+	DuplicateReasonIDNumber    = "id_number"
+	DuplicateReasonEmail       = "email"
+	DuplicateReasonCompanyName = "company_name"
+)
+
+// ClientDuplicate is an existing client that may be the same person or company
+// as another
+type ClientDuplicate struct {
+	Client  *Client  `json:"client"`
+	Reasons []string `json:"reasons"`
+}
+
+// ClientMerge records a client merged into another. The source client's
+// registrations and documents are moved to the target and the source is deleted.
+type ClientMerge struct {
+	ID                 uuid.UUID  `json:"id" db:"id"`
+	TenantID           uuid.UUID  `json:"tenant_id" db:"tenant_id"`
+	SourceClientID     uuid.UUID  `json:"source_client_id" db:"source_client_id"`
+	TargetClientID     uuid.UUID  `json:"target_client_id" db:"target_client_id"`
+	RegistrationsMoved int        `json:"registrations_moved" db:"registrations_moved"`
+	DocumentsMoved     int        `json:"documents_moved" db:"documents_moved"`
+	MergedBy           *uuid.UUID `json:"merged_by,omitempty" db:"merged_by"`
+	CreatedAt          time.Time  `json:"created_at" db:"created_at"`
+}
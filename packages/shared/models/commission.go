@@ -27,6 +27,23 @@ type Commission struct {
 	Metadata          map[string]interface{} `json:"metadata,omitempty" db:"metadata"`
 }
 
+// CommissionOverride is what a parent tenant earns on a commission recorded by one
+// of its sub-agent tenants. Its status follows the source commission.
+type CommissionOverride struct {
+	ID                   uuid.UUID `json:"id" db:"id"`
+	TenantID             uuid.UUID `json:"tenant_id" db:"tenant_id"`
+	SourceTenantID       uuid.UUID `json:"source_tenant_id" db:"source_tenant_id"`
+	SourceCommissionID   uuid.UUID `json:"source_commission_id" db:"source_commission_id"`
+	SourceRegistrationID uuid.UUID `json:"source_registration_id" db:"source_registration_id"`
+	RegistrationFee      float64   `json:"registration_fee" db:"registration_fee"`
+	OverrideRate         float64   `json:"override_rate" db:"override_rate"`
+	OverrideAmount       float64   `json:"override_amount" db:"override_amount"`
+	Currency             string    `json:"currency" db:"currency"`
+	Status               string    `json:"status" db:"status"`
+	CreatedAt            time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt            time.Time `json:"updated_at" db:"updated_at"`
+}
+
 // CommissionStatus constants
 const (
 	CommissionStatusPending   = "pending"
@@ -57,14 +74,6 @@ func (c *Commission) IsPaid() bool {
 	return c.Status == CommissionStatusPaid
 }
 
-// CommissionListResponse represents a paginated list of commissions
-type CommissionListResponse struct {
-	Data   []*Commission `json:"data"`
-	Total  int           `json:"total"`
-	Offset int           `json:"offset"`
-	Limit  int           `json:"limit"`
-}
-
 // CommissionSummary represents commission summary statistics
 type CommissionSummary struct {
 	TotalCommissions  int     `json:"total_commissions"`
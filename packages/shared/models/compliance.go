@@ -0,0 +1,95 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ComplianceObligation is a recurring filing a registered company owes, such as
+// an annual return for one year or a VAT return for one tax period. Dates are
+// calendar dates, held at midnight UTC. ClientID and CompanyName are the
+// registration's, read with the obligation, so they follow client merges.
+type ComplianceObligation struct {
+	ID               uuid.UUID  `json:"id" db:"id"`
+	TenantID         uuid.UUID  `json:"tenant_id" db:"tenant_id"`
+	RegistrationID   uuid.UUID  `json:"registration_id" db:"registration_id"`
+	ClientID         uuid.UUID  `json:"client_id" db:"-"`
+	CompanyName      string     `json:"company_name" db:"-"`
+	ObligationType   string     `json:"obligation_type" db:"obligation_type"`
+	Jurisdiction     string     `json:"jurisdiction" db:"jurisdiction"`
+	Authority        string     `json:"authority" db:"authority"`
+	Title            string     `json:"title" db:"title"`
+	PeriodStart      time.Time  `json:"period_start" db:"period_start"`
+	PeriodEnd        time.Time  `json:"period_end" db:"period_end"`
+	DueDate          time.Time  `json:"due_date" db:"due_date"`
+	Status           string     `json:"status" db:"status"`
+	CompletedAt      *time.Time `json:"completed_at,omitempty" db:"completed_at"`
+	CompletedBy      *uuid.UUID `json:"completed_by,omitempty" db:"completed_by"`
+	FilingReference  *string    `json:"filing_reference,omitempty" db:"filing_reference"`
+	Notes            *string    `json:"notes,omitempty" db:"notes"`
+	LastReminderDays *int       `json:"last_reminder_days,omitempty" db:"last_reminder_days"`
+	RemindedAt       *time.Time `json:"reminded_at,omitempty" db:"reminded_at"`
+	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// Compliance obligation types
+const (
+	ObligationAnnualReturn        = "annual_return"
+	ObligationBeneficialOwnership = "beneficial_ownership"
+	ObligationVATReturn           = "vat_return"
+	ObligationTaxClearance        = "tax_clearance"
+)
+
+// Compliance obligation statuses. An upcoming obligation becomes overdue once
+// its due date passes; completed and waived obligations are closed.
+const (
+	ObligationStatusUpcoming  = "upcoming"
+	ObligationStatusOverdue   = "overdue"
+	ObligationStatusCompleted = "completed"
+	ObligationStatusWaived    = "waived"
+)
+
+// IsOpen reports whether an obligation still has to be met
+func (o *ComplianceObligation) IsOpen() bool {
+	return o.Status == ObligationStatusUpcoming || o.Status == ObligationStatusOverdue
+}
+
+// CompleteObligationRequest records an obligation as filed. CompletedAt
+// defaults to now.
+type CompleteObligationRequest struct {
+	FilingReference *string    `json:"filing_reference,omitempty" validate:"omitempty,max=100"`
+	CompletedAt     *time.Time `json:"completed_at,omitempty"`
+	Notes           *string    `json:"notes,omitempty" validate:"omitempty,max=2000"`
+}
+
+// WaiveObligationRequest records why an obligation does not have to be met
+type WaiveObligationRequest struct {
+	Reason string `json:"reason" validate:"required,max=2000"`
+}
+
+// ComplianceReminder is published to remind a client of an obligation falling
+// due, or that it is overdue. DaysRemaining is negative once overdue.
+type ComplianceReminder struct {
+	ObligationID   uuid.UUID `json:"obligation_id"`
+	TenantID       uuid.UUID `json:"tenant_id"`
+	RegistrationID uuid.UUID `json:"registration_id"`
+	ClientID       uuid.UUID `json:"client_id"`
+	ClientName     string    `json:"client_name"`
+	ClientEmail    string    `json:"client_email"`
+	CompanyName    string    `json:"company_name"`
+	ObligationType string    `json:"obligation_type"`
+	Authority      string    `json:"authority"`
+	Title          string    `json:"title"`
+	DueDate        time.Time `json:"due_date"`
+	DaysRemaining  int       `json:"days_remaining"`
+	Overdue        bool      `json:"overdue"`
+}
+
+// ComplianceFeed is a calendar feed URL that needs no sign-in, for calendar
+// apps to subscribe to
+type ComplianceFeed struct {
+	URL      string     `json:"url"`
+	ClientID *uuid.UUID `json:"client_id,omitempty"`
+}
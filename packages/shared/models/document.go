@@ -83,14 +83,6 @@ type UpdateDocumentRequest struct {
 	AIVerificationNotes *string  `json:"ai_verification_notes,omitempty"`
 }
 
-// DocumentListResponse represents a paginated list of documents
-type DocumentListResponse struct {
-	Data   []*Document `json:"data"`
-	Total  int         `json:"total"`
-	Offset int         `json:"offset"`
-	Limit  int         `json:"limit"`
-}
-
 // DocumentUploadResponse represents the response after uploading a document
 type DocumentUploadResponse struct {
 	Document   *Document `json:"document"`
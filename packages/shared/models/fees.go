@@ -0,0 +1,117 @@
+package models
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FeeSchedule is what a tenant charges for one registration type in one
+// jurisdiction, from EffectiveFrom until the next schedule for them takes
+// effect. VATRate is in percent; a tenant that is not registered for VAT sets
+// it to 0.
+type FeeSchedule struct {
+	ID               uuid.UUID  `json:"id" db:"id"`
+	TenantID         uuid.UUID  `json:"tenant_id" db:"tenant_id"`
+	Jurisdiction     string     `json:"jurisdiction" db:"jurisdiction"`
+	RegistrationType string     `json:"registration_type" db:"registration_type"`
+	Currency         string     `json:"currency" db:"currency"`
+	VATRate          float64    `json:"vat_rate" db:"vat_rate"`
+	EffectiveFrom    time.Time  `json:"effective_from" db:"effective_from"`
+	Items            []FeeItem  `json:"items" db:"items"`
+	CreatedBy        *uuid.UUID `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// FeeItem is one fee in a schedule. Government fees are the registry's or
+// revenue authority's, passed on at cost and without VAT; service fees are the
+// tenant's own; add-ons are service fees charged only when a quote asks for
+// them.
+type FeeItem struct {
+	Code        string  `json:"code" validate:"required,max=50"`
+	Description string  `json:"description" validate:"required,max=255"`
+	Kind        string  `json:"kind" validate:"required,oneof=government service addon"`
+	Amount      float64 `json:"amount" validate:"gte=0"`
+}
+
+// Fee item kinds
+const (
+	FeeKindGovernment = "government"
+	FeeKindService    = "service"
+	FeeKindAddOn      = "addon"
+)
+
+// Quote is the price of a registration. A draft quote follows the fee
+// schedule and can be requoted; submitting the registration locks its quote,
+// and the locked amounts are what the client is invoiced and commission is
+// earned on. Subtotal excludes VAT and Total includes it.
+type Quote struct {
+	ID             uuid.UUID   `json:"id" db:"id"`
+	TenantID       uuid.UUID   `json:"tenant_id" db:"tenant_id"`
+	RegistrationID uuid.UUID   `json:"registration_id" db:"registration_id"`
+	FeeScheduleID  *uuid.UUID  `json:"fee_schedule_id,omitempty" db:"fee_schedule_id"`
+	Currency       string      `json:"currency" db:"currency"`
+	VATRate        float64     `json:"vat_rate" db:"vat_rate"`
+	AddOns         []string    `json:"add_ons" db:"add_ons"`
+	Lines          []QuoteLine `json:"lines" db:"lines"`
+	Subtotal       float64     `json:"subtotal" db:"subtotal"`
+	VAT            float64     `json:"vat" db:"vat"`
+	Total          float64     `json:"total" db:"total"`
+	Status         string      `json:"status" db:"status"`
+	LockedAt       *time.Time  `json:"locked_at,omitempty" db:"locked_at"`
+	LockedBy       *uuid.UUID  `json:"locked_by,omitempty" db:"locked_by"`
+	CreatedAt      time.Time   `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time   `json:"updated_at" db:"updated_at"`
+}
+
+// QuoteLine is one fee of a quote. Amount excludes VAT.
+type QuoteLine struct {
+	Code        string  `json:"code"`
+	Description string  `json:"description"`
+	Kind        string  `json:"kind"`
+	Amount      float64 `json:"amount"`
+	VATRate     float64 `json:"vat_rate"`
+	VAT         float64 `json:"vat"`
+}
+
+// Quote statuses
+const (
+	QuoteStatusDraft  = "draft"
+	QuoteStatusLocked = "locked"
+)
+
+// IsLocked reports whether the quote was locked at submission
+func (q *Quote) IsLocked() bool {
+	return q.Status == QuoteStatusLocked
+}
+
+// ServiceAmount is what the tenant charges for its own services, excluding
+// VAT and government fees. Commission is earned on it.
+func (q *Quote) ServiceAmount() float64 {
+	amount := 0.0
+	for _, line := range q.Lines {
+		if line.Kind != FeeKindGovernment {
+			amount += line.Amount
+		}
+	}
+	return math.Round(amount*100) / 100
+}
+
+// CreateFeeScheduleRequest adds a fee schedule taking effect on a date
+// (YYYY-MM-DD). Currency and VATRate default to the jurisdiction's.
+type CreateFeeScheduleRequest struct {
+	Jurisdiction     string    `json:"jurisdiction" validate:"required,jurisdiction"`
+	RegistrationType string    `json:"registration_type" validate:"required,registration_type"`
+	Currency         *string   `json:"currency,omitempty" validate:"omitempty,currency"`
+	VATRate          *float64  `json:"vat_rate,omitempty" validate:"omitempty,gte=0,lte=100"`
+	EffectiveFrom    string    `json:"effective_from" validate:"required,datetime=2006-01-02"`
+	Items            []FeeItem `json:"items" validate:"required,min=1,max=50,dive"`
+}
+
+// QuoteRegistrationRequest quotes a draft registration with the add-ons, by
+// code, the client wants
+type QuoteRegistrationRequest struct {
+	AddOns []string `json:"add_ons,omitempty" validate:"omitempty,max=20,dive,required,max=50"`
+}
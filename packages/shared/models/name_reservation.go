@@ -0,0 +1,105 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxProposedNames is how many names a registry examines in one reservation
+const MaxProposedNames = 4
+
+// NameReservation is a set of company names proposed for a registration and
+// lodged with the registry of its jurisdiction, CIPC or DCIP. Names are in order
+// of preference; the registry approves at most one, which is then reserved for
+// the registration until ExpiresAt.
+type NameReservation struct {
+	ID             uuid.UUID      `json:"id" db:"id"`
+	TenantID       uuid.UUID      `json:"tenant_id" db:"tenant_id"`
+	RegistrationID uuid.UUID      `json:"registration_id" db:"registration_id"`
+	Status         string         `json:"status" db:"status"`
+	Names          []ProposedName `json:"names" db:"names"`
+	RequestedBy    *uuid.UUID     `json:"requested_by,omitempty" db:"requested_by"`
+	Jurisdiction   string         `json:"jurisdiction" db:"jurisdiction"`
+	CIPCReference  *string        `json:"cipc_reference,omitempty" db:"cipc_reference"`
+	DCIPReference  *string        `json:"dcip_reference,omitempty" db:"dcip_reference"`
+	ReservedName   *string        `json:"reserved_name,omitempty" db:"reserved_name"`
+	LastError      *string        `json:"last_error,omitempty" db:"last_error"`
+	SubmittedAt    *time.Time     `json:"submitted_at,omitempty" db:"submitted_at"`
+	LastCheckedAt  *time.Time     `json:"last_checked_at,omitempty" db:"last_checked_at"`
+	DecidedAt      *time.Time     `json:"decided_at,omitempty" db:"decided_at"`
+	ExpiresAt      *time.Time     `json:"expires_at,omitempty" db:"expires_at"`
+	CreatedAt      time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+// Reference returns the registry's reference for the reservation, nil until it
+// is submitted
+func (r *NameReservation) Reference() *string {
+	if r.Jurisdiction == JurisdictionZimbabwe {
+		return r.DCIPReference
+	}
+	return r.CIPCReference
+}
+
+// SetReference records the reference the registry gave the reservation
+func (r *NameReservation) SetReference(reference string) {
+	if r.Jurisdiction == JurisdictionZimbabwe {
+		r.DCIPReference = &reference
+	} else {
+		r.CIPCReference = &reference
+	}
+}
+
+// Name reservation status constants
+const (
+	NameReservationStatusDraft     = "draft"
+	NameReservationStatusSubmitted = "submitted"
+	NameReservationStatusApproved  = "approved"
+	NameReservationStatusRejected  = "rejected"
+	NameReservationStatusExpired   = "expired"
+)
+
+// ProposedName is one proposed company name with the issues local screening
+// found and the registry's decision on it
+type ProposedName struct {
+	Name   string      `json:"name"`
+	Status string      `json:"status"`
+	Reason string      `json:"reason,omitempty"`
+	Issues []NameIssue `json:"issues"`
+}
+
+// Proposed name status constants
+const (
+	ProposedNameStatusProposed = "proposed"
+	ProposedNameStatusApproved = "approved"
+	ProposedNameStatusRejected = "rejected"
+)
+
+// NameIssue is a problem local screening found with a proposed name. Errors
+// stop the reservation being submitted; warnings are for the preparer to weigh.
+type NameIssue struct {
+	Code           string     `json:"code"`
+	Severity       string     `json:"severity"`
+	Message        string     `json:"message"`
+	Word           string     `json:"word,omitempty"`
+	RegistrationID *uuid.UUID `json:"registration_id,omitempty"`
+	SimilarName    string     `json:"similar_name,omitempty"`
+	Similarity     float64    `json:"similarity,omitempty"`
+}
+
+// Name issue codes
+const (
+	NameIssueBannedWord    = "banned_word"
+	NameIssueRepeated      = "repeated_name"
+	NameIssueIdentical     = "identical_name"
+	NameIssueSimilar       = "similar_name"
+	NameIssueSeverityError = "error"
+	NameIssueSeverityWarn  = "warning"
+)
+
+// ProposeNamesRequest sets the names proposed for a registration, in order of
+// preference
+type ProposeNamesRequest struct {
+	Names []string `json:"names" validate:"required,min=1,max=4,dive,required,max=200"`
+}
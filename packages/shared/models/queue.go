@@ -0,0 +1,127 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReviewQueue is a tenant's work queue of registrations awaiting review.
+// Submitted registrations of the listed types are assigned to its members by
+// Strategy; a queue listing no types takes every type no other queue takes.
+type ReviewQueue struct {
+	ID                uuid.UUID            `json:"id" db:"id"`
+	TenantID          uuid.UUID            `json:"tenant_id" db:"tenant_id"`
+	Name              string               `json:"name" db:"name"`
+	Strategy          string               `json:"strategy" db:"strategy"`
+	RegistrationTypes []string             `json:"registration_types" db:"registration_types"`
+	IsActive          bool                 `json:"is_active" db:"is_active"`
+	LastAssignedTo    *uuid.UUID           `json:"last_assigned_to,omitempty" db:"last_assigned_to"`
+	Members           []*ReviewQueueMember `json:"members,omitempty"`
+	CreatedAt         time.Time            `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time            `json:"updated_at" db:"updated_at"`
+}
+
+// ReviewQueueMember is a reviewer of a queue. Skills are the registration types
+// the reviewer handles under the skill strategy; Load is the number of open
+// registrations assigned to them.
+type ReviewQueueMember struct {
+	QueueID   uuid.UUID `json:"queue_id" db:"queue_id"`
+	UserID    uuid.UUID `json:"user_id" db:"user_id"`
+	Skills    []string  `json:"skills" db:"skills"`
+	MaxLoad   *int      `json:"max_load,omitempty" db:"max_load"`
+	IsActive  bool      `json:"is_active" db:"is_active"`
+	Load      int       `json:"load"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// RegistrationAssignment records a registration being assigned to a reviewer
+type RegistrationAssignment struct {
+	ID             uuid.UUID  `json:"id" db:"id"`
+	RegistrationID uuid.UUID  `json:"registration_id" db:"registration_id"`
+	QueueID        *uuid.UUID `json:"queue_id,omitempty" db:"queue_id"`
+	FromUser       *uuid.UUID `json:"from_user,omitempty" db:"from_user"`
+	ToUser         *uuid.UUID `json:"to_user,omitempty" db:"to_user"`
+	Method         string     `json:"method" db:"method"`
+	Strategy       *string    `json:"strategy,omitempty" db:"strategy"`
+	AssignedBy     *uuid.UUID `json:"assigned_by,omitempty" db:"assigned_by"`
+	Reason         *string    `json:"reason,omitempty" db:"reason"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+}
+
+// SLATarget is how long a registration may stay in a status. A target without a
+// registration type applies to every type without a target of its own.
+type SLATarget struct {
+	ID               uuid.UUID `json:"id" db:"id"`
+	TenantID         uuid.UUID `json:"tenant_id" db:"tenant_id"`
+	Status           string    `json:"status" db:"status"`
+	RegistrationType *string   `json:"registration_type,omitempty" db:"registration_type"`
+	TargetHours      int       `json:"target_hours" db:"target_hours"`
+	BusinessHours    bool      `json:"business_hours" db:"business_hours"`
+	CreatedAt        time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// SLABreach is published when a registration outstays its status's SLA target
+type SLABreach struct {
+	RegistrationID   uuid.UUID  `json:"registration_id"`
+	TenantID         uuid.UUID  `json:"tenant_id"`
+	RegistrationType string     `json:"registration_type"`
+	CompanyName      string     `json:"company_name"`
+	Status           string     `json:"status"`
+	AssignedTo       *uuid.UUID `json:"assigned_to,omitempty"`
+	QueueID          *uuid.UUID `json:"queue_id,omitempty"`
+	DueAt            time.Time  `json:"due_at"`
+	BreachedAt       time.Time  `json:"breached_at"`
+}
+
+// ReviewQueue strategy constants
+const (
+	QueueStrategyRoundRobin  = "round_robin"
+	QueueStrategyLeastLoaded = "least_loaded"
+	QueueStrategySkill       = "skill"
+)
+
+// Assignment method constants
+const (
+	AssignmentMethodAuto   = "auto"
+	AssignmentMethodManual = "manual"
+)
+
+// CreateReviewQueueRequest represents a request to create a review queue
+type CreateReviewQueueRequest struct {
+	Name              string   `json:"name" binding:"required,min=1,max=255"`
+	Strategy          string   `json:"strategy,omitempty" binding:"omitempty,oneof=round_robin least_loaded skill"`
+	RegistrationTypes []string `json:"registration_types,omitempty" binding:"omitempty,dive,oneof=pty_ltd close_corporation business_name vat_registration"`
+}
+
+// UpdateReviewQueueRequest represents a request to update a review queue
+type UpdateReviewQueueRequest struct {
+	Name              *string  `json:"name,omitempty" binding:"omitempty,min=1,max=255"`
+	Strategy          *string  `json:"strategy,omitempty" binding:"omitempty,oneof=round_robin least_loaded skill"`
+	RegistrationTypes []string `json:"registration_types,omitempty" binding:"omitempty,dive,oneof=pty_ltd close_corporation business_name vat_registration"`
+	IsActive          *bool    `json:"is_active,omitempty"`
+}
+
+// SetQueueMemberRequest adds a reviewer to a queue or updates their membership
+type SetQueueMemberRequest struct {
+	Skills   []string `json:"skills,omitempty" binding:"omitempty,dive,oneof=pty_ltd close_corporation business_name vat_registration"`
+	MaxLoad  *int     `json:"max_load,omitempty" binding:"omitempty,min=1"`
+	IsActive *bool    `json:"is_active,omitempty"`
+}
+
+// AssignRegistrationRequest reassigns a registration to a reviewer
+type AssignRegistrationRequest struct {
+	UserID  uuid.UUID  `json:"user_id" binding:"required"`
+	QueueID *uuid.UUID `json:"queue_id,omitempty"`
+	Reason  *string    `json:"reason,omitempty" binding:"omitempty,max=2000"`
+}
+
+// SetSLATargetRequest sets the SLA target of a status, optionally for one
+// registration type
+type SetSLATargetRequest struct {
+	Status           string  `json:"status" binding:"required,oneof=draft submitted in_review approved rejected"`
+	RegistrationType *string `json:"registration_type,omitempty" binding:"omitempty,oneof=pty_ltd close_corporation business_name vat_registration"`
+	TargetHours      int     `json:"target_hours" binding:"required,min=1,max=8760"`
+	BusinessHours    *bool   `json:"business_hours,omitempty"`
+}
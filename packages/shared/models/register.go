@@ -0,0 +1,157 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RegisterEntry is a version of one entry in a company's register of
+// directors, shareholders and beneficial owners. Entries are never changed in
+// place: each change records a new version taking effect from a date, so the
+// register can be read as it stood on any day. ID identifies the entry across
+// its versions. Dates are calendar dates, held at midnight UTC.
+type RegisterEntry struct {
+	ID             uuid.UUID `json:"id" db:"entry_id"`
+	VersionID      uuid.UUID `json:"version_id" db:"id"`
+	TenantID       uuid.UUID `json:"tenant_id" db:"tenant_id"`
+	RegistrationID uuid.UUID `json:"registration_id" db:"registration_id"`
+	Version        int       `json:"version" db:"version"`
+	EffectiveFrom  time.Time `json:"effective_from" db:"effective_from"`
+
+	Role       string `json:"role" db:"role"`
+	PersonType string `json:"person_type" db:"person_type"`
+
+	// Natural persons
+	FirstNames     *string    `json:"first_names,omitempty" db:"first_names"`
+	LastName       *string    `json:"last_name,omitempty" db:"last_name"`
+	IDNumber       *string    `json:"id_number,omitempty" db:"id_number"`
+	PassportNumber *string    `json:"passport_number,omitempty" db:"passport_number"`
+	Nationality    *string    `json:"nationality,omitempty" db:"nationality"`
+	DateOfBirth    *time.Time `json:"date_of_birth,omitempty" db:"date_of_birth"`
+
+	// Legal entities holding shares
+	EntityName         *string `json:"entity_name,omitempty" db:"entity_name"`
+	RegistrationNumber *string `json:"registration_number,omitempty" db:"registration_number"`
+
+	Email              *string `json:"email,omitempty" db:"email"`
+	ResidentialAddress *string `json:"residential_address,omitempty" db:"residential_address"`
+
+	// SharePercent is a shareholder's shareholding, or a beneficial owner's
+	// beneficial interest
+	SharePercent *float64 `json:"share_percent,omitempty" db:"share_percent"`
+	// ControlType is how a beneficial owner holds their interest
+	ControlType *string `json:"control_type,omitempty" db:"control_type"`
+
+	AppointedOn time.Time   `json:"appointed_on" db:"appointed_on"`
+	ResignedOn  *time.Time  `json:"resigned_on,omitempty" db:"resigned_on"`
+	DocumentIDs []uuid.UUID `json:"document_ids" db:"document_ids"`
+
+	// Removed strikes an entry recorded in error from the register altogether
+	Removed    bool       `json:"removed,omitempty" db:"removed"`
+	Note       *string    `json:"note,omitempty" db:"note"`
+	RecordedBy *uuid.UUID `json:"recorded_by,omitempty" db:"recorded_by"`
+	RecordedAt time.Time  `json:"recorded_at" db:"recorded_at"`
+}
+
+// Register roles
+const (
+	RegisterRoleDirector        = "director"
+	RegisterRoleShareholder     = "shareholder"
+	RegisterRoleBeneficialOwner = "beneficial_owner"
+)
+
+// Register person types
+const (
+	RegisterPersonNatural = "natural_person"
+	RegisterPersonEntity  = "legal_entity"
+)
+
+// Ways a beneficial owner holds their interest
+const (
+	ControlDirectShareholding   = "direct_shareholding"
+	ControlIndirectShareholding = "indirect_shareholding"
+	ControlVotingRights         = "voting_rights"
+	ControlAppointDirectors     = "appointment_of_directors"
+	ControlOther                = "other_control"
+)
+
+// Name returns the person's full name, or the entity's name
+func (e *RegisterEntry) Name() string {
+	if e.PersonType == RegisterPersonEntity {
+		if e.EntityName == nil {
+			return ""
+		}
+		return *e.EntityName
+	}
+	var parts []string
+	for _, part := range []*string{e.FirstNames, e.LastName} {
+		if part != nil && *part != "" {
+			parts = append(parts, *part)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// HoldsOfficeOn reports whether the entry is on the register on a date:
+// appointed by then and not yet resigned
+func (e *RegisterEntry) HoldsOfficeOn(date time.Time) bool {
+	return !e.AppointedOn.After(date) && (e.ResignedOn == nil || date.Before(*e.ResignedOn))
+}
+
+// CompanyRegister is a company's register as it stood on a date.
+// ShareholdingTotal adds up the shareholders' shareholdings, which must come
+// to 100%; Issues says what keeps the register from being complete.
+type CompanyRegister struct {
+	RegistrationID    uuid.UUID        `json:"registration_id"`
+	AsAt              time.Time        `json:"as_at"`
+	Directors         []*RegisterEntry `json:"directors"`
+	Shareholders      []*RegisterEntry `json:"shareholders"`
+	BeneficialOwners  []*RegisterEntry `json:"beneficial_owners"`
+	ShareholdingTotal float64          `json:"shareholding_total"`
+	Issues            []string         `json:"issues"`
+}
+
+// RegisterEntryDetails are the details of a register entry that can change
+// over time. Dates are YYYY-MM-DD.
+type RegisterEntryDetails struct {
+	PersonType         string      `json:"person_type" validate:"required,oneof=natural_person legal_entity"`
+	FirstNames         *string     `json:"first_names,omitempty" validate:"omitempty,max=200"`
+	LastName           *string     `json:"last_name,omitempty" validate:"omitempty,max=100"`
+	IDNumber           *string     `json:"id_number,omitempty" validate:"omitempty,sa_id_number"`
+	PassportNumber     *string     `json:"passport_number,omitempty" validate:"omitempty,max=50"`
+	Nationality        *string     `json:"nationality,omitempty" validate:"omitempty,country_code,len=2"`
+	DateOfBirth        *string     `json:"date_of_birth,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	EntityName         *string     `json:"entity_name,omitempty" validate:"omitempty,max=255"`
+	RegistrationNumber *string     `json:"registration_number,omitempty" validate:"omitempty,max=50"`
+	Email              *string     `json:"email,omitempty" validate:"omitempty,email"`
+	ResidentialAddress *string     `json:"residential_address,omitempty" validate:"omitempty,max=500"`
+	SharePercent       *float64    `json:"share_percent,omitempty" validate:"omitempty,gt=0,lte=100"`
+	ControlType        *string     `json:"control_type,omitempty" validate:"omitempty,oneof=direct_shareholding indirect_shareholding voting_rights appointment_of_directors other_control"`
+	AppointedOn        string      `json:"appointed_on" validate:"required,datetime=2006-01-02"`
+	DocumentIDs        []uuid.UUID `json:"document_ids,omitempty" validate:"omitempty,max=20"`
+	Note               *string     `json:"note,omitempty" validate:"omitempty,max=1000"`
+}
+
+// CreateRegisterEntryRequest adds a director, shareholder or beneficial owner
+// to a company's register from the day they were appointed
+type CreateRegisterEntryRequest struct {
+	Role string `json:"role" validate:"required,oneof=director shareholder beneficial_owner"`
+	RegisterEntryDetails
+}
+
+// UpdateRegisterEntryRequest records a change to an entry taking effect from a
+// date, such as a changed shareholding. A new appointment date corrects the
+// one recorded.
+type UpdateRegisterEntryRequest struct {
+	EffectiveFrom string `json:"effective_from" validate:"required,datetime=2006-01-02"`
+	RegisterEntryDetails
+}
+
+// ResignRegisterEntryRequest records the day an entry left the register, such
+// as a director's resignation or a shareholder selling all their shares
+type ResignRegisterEntryRequest struct {
+	ResignedOn string  `json:"resigned_on" validate:"required,datetime=2006-01-02"`
+	Note       *string `json:"note,omitempty" validate:"omitempty,max=1000"`
+}
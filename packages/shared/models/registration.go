@@ -20,7 +20,12 @@ type Registration struct {
 	ApprovedAt         *time.Time             `json:"approved_at,omitempty" db:"approved_at"`
 	RejectedAt         *time.Time             `json:"rejected_at,omitempty" db:"rejected_at"`
 	RejectionReason    *string                `json:"rejection_reason,omitempty" db:"rejection_reason"`
-	AssignedTo         *string                `json:"assigned_to,omitempty" db:"assigned_to"`
+	AssignedTo         *uuid.UUID             `json:"assigned_to,omitempty" db:"assigned_to"`
+	QueueID            *uuid.UUID             `json:"queue_id,omitempty" db:"queue_id"`
+	AssignedAt         *time.Time             `json:"assigned_at,omitempty" db:"assigned_at"`
+	StatusChangedAt    *time.Time             `json:"status_changed_at,omitempty" db:"status_changed_at"`
+	SLADueAt           *time.Time             `json:"sla_due_at,omitempty" db:"sla_due_at"`
+	SLABreachedAt      *time.Time             `json:"sla_breached_at,omitempty" db:"sla_breached_at"`
 	CIPCReference      *string                `json:"cipc_reference,omitempty" db:"cipc_reference"`
 	DCIPReference      *string                `json:"dcip_reference,omitempty" db:"dcip_reference"`
 	OdooLeadID         *int                   `json:"odoo_lead_id,omitempty" db:"odoo_lead_id"`
@@ -31,6 +36,11 @@ type Registration struct {
 	DeletedAt          *time.Time             `json:"deleted_at,omitempty" db:"deleted_at"`
 	FormData           map[string]interface{} `json:"form_data,omitempty" db:"form_data"`
 	Metadata           map[string]interface{} `json:"metadata,omitempty" db:"metadata"`
+	WorkflowVersion    int                    `json:"workflow_version" db:"workflow_version"`
+	FormSchemaVersion  *int                   `json:"form_schema_version,omitempty" db:"form_schema_version"`
+	// Quote is the quote locked at submission. It is only set on the
+	// registration returned, and published, by the submission.
+	Quote *Quote `json:"quote,omitempty" db:"-"`
 }
 
 // RegistrationType constants
@@ -76,10 +86,65 @@ func (r *Registration) IsCompleted() bool {
 	       r.Status == RegistrationStatusCompleted
 }
 
-// RegistrationListResponse represents a paginated list of registrations
-type RegistrationListResponse struct {
-	Data   []*Registration `json:"data"`
-	Total  int             `json:"total"`
-	Offset int             `json:"offset"`
-	Limit  int             `json:"limit"`
+// RegistrationStatusChange is one entry of a registration's status history.
+// FromStatus is nil for the status the registration was created with.
+type RegistrationStatusChange struct {
+	ID              uuid.UUID  `json:"id" db:"id"`
+	RegistrationID  uuid.UUID  `json:"registration_id" db:"registration_id"`
+	FromStatus      *string    `json:"from_status,omitempty" db:"from_status"`
+	ToStatus        string     `json:"to_status" db:"to_status"`
+	WorkflowVersion int        `json:"workflow_version" db:"workflow_version"`
+	ChangedBy       *uuid.UUID `json:"changed_by,omitempty" db:"changed_by"`
+	Reason          *string    `json:"reason,omitempty" db:"reason"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+}
+
+// RegistrationHistoryResponse is a registration's status history with the
+// transitions currently available from its status
+type RegistrationHistoryResponse struct {
+	RegistrationID   uuid.UUID                   `json:"registration_id"`
+	RegistrationType string                      `json:"registration_type"`
+	WorkflowVersion  int                         `json:"workflow_version"`
+	Status           string                      `json:"status"`
+	NextStatuses     []string                    `json:"next_statuses"`
+	Data             []*RegistrationStatusChange `json:"data"`
+}
+
+// Checklist item kinds
+const (
+	ChecklistItemDocument     = "document"
+	ChecklistItemField        = "field"
+	ChecklistItemVerification = "verification"
+)
+
+// Checklist item statuses. A pending document is uploaded but not yet verified;
+// a rejected one was rejected or has expired and must be uploaded again.
+const (
+	ChecklistStatusComplete = "complete"
+	ChecklistStatusPending  = "pending"
+	ChecklistStatusRejected = "rejected"
+	ChecklistStatusMissing  = "missing"
+)
+
+// ChecklistItem is one requirement of a registration and how far along it is
+type ChecklistItem struct {
+	Key         string `json:"key"`
+	Kind        string `json:"kind"`
+	Label       string `json:"label"`
+	RequiredFor string `json:"required_for"`
+	Status      string `json:"status"`
+	Detail      string `json:"detail,omitempty"`
+}
+
+// RegistrationChecklist lists what a registration needs for its jurisdiction and
+// type. Completeness is a percentage; ReadyToSubmit reports whether every item
+// required for submission is met.
+type RegistrationChecklist struct {
+	RegistrationID   uuid.UUID        `json:"registration_id"`
+	RegistrationType string           `json:"registration_type"`
+	Jurisdiction     string           `json:"jurisdiction"`
+	Status           string           `json:"status"`
+	Completeness     int              `json:"completeness"`
+	ReadyToSubmit    bool             `json:"ready_to_submit"`
+	Items            []*ChecklistItem `json:"items"`
 }
@@ -0,0 +1,85 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RegistrationImportMapping is a saved mapping of spreadsheet columns to import
+// fields. Columns maps a column header to a field such as "client.email",
+// "registration.company_name" or "registration.form_data.registered_address.city";
+// Defaults gives fields a value for rows that leave them empty.
+type RegistrationImportMapping struct {
+	ID        uuid.UUID         `json:"id" db:"id"`
+	TenantID  uuid.UUID         `json:"tenant_id" db:"tenant_id"`
+	Name      string            `json:"name" db:"name"`
+	Columns   map[string]string `json:"columns" db:"columns"`
+	Defaults  map[string]string `json:"defaults,omitempty" db:"defaults"`
+	CreatedBy *uuid.UUID        `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at" db:"updated_at"`
+}
+
+// RegistrationImport is a bulk import of clients and registrations from a CSV
+// or XLSX file. Rows that fail validation are skipped and listed in the error
+// report; a dry run validates every row without creating anything.
+type RegistrationImport struct {
+	ID          uuid.UUID                `json:"id" db:"id"`
+	TenantID    uuid.UUID                `json:"tenant_id" db:"tenant_id"`
+	MappingID   *uuid.UUID               `json:"mapping_id,omitempty" db:"mapping_id"`
+	FileName    string                   `json:"file_name" db:"file_name"`
+	Format      string                   `json:"format" db:"format"`
+	DryRun      bool                     `json:"dry_run" db:"dry_run"`
+	Status      string                   `json:"status" db:"status"`
+	RequestedBy *uuid.UUID               `json:"requested_by,omitempty" db:"requested_by"`
+	RowsTotal   int                      `json:"rows_total" db:"rows_total"`
+	RowsDone    int                      `json:"rows_done" db:"rows_done"`
+	Progress    int                      `json:"progress"`
+	Stats       *RegistrationImportStats `json:"stats,omitempty" db:"stats"`
+	ErrorCount  int                      `json:"error_count" db:"error_count"`
+	LastError   *string                  `json:"last_error,omitempty" db:"last_error"`
+	StartedAt   *time.Time               `json:"started_at,omitempty" db:"started_at"`
+	CompletedAt *time.Time               `json:"completed_at,omitempty" db:"completed_at"`
+	CreatedAt   time.Time                `json:"created_at" db:"created_at"`
+}
+
+// Registration import format constants
+const (
+	ImportFormatCSV  = "csv"
+	ImportFormatXLSX = "xlsx"
+)
+
+// RegistrationImportStats counts the rows of an import. For a dry run the
+// created counts are what the import would create.
+type RegistrationImportStats struct {
+	ValidRows            int `json:"valid_rows"`
+	InvalidRows          int `json:"invalid_rows"`
+	ClientsCreated       int `json:"clients_created"`
+	ClientsMatched       int `json:"clients_matched"`
+	RegistrationsCreated int `json:"registrations_created"`
+}
+
+// RegistrationImportError is a problem with one row of an import. Row is the
+// spreadsheet row number, counting the header as row 1.
+type RegistrationImportError struct {
+	Row     int    `json:"row"`
+	Column  string `json:"column,omitempty"`
+	Field   string `json:"field,omitempty"`
+	Value   string `json:"value,omitempty"`
+	Message string `json:"message"`
+}
+
+// CreateImportMappingRequest saves a column mapping for registration imports
+type CreateImportMappingRequest struct {
+	Name     string            `json:"name" binding:"required,min=1,max=255"`
+	Columns  map[string]string `json:"columns" binding:"required,min=1"`
+	Defaults map[string]string `json:"defaults,omitempty"`
+}
+
+// UpdateImportMappingRequest changes a saved column mapping
+type UpdateImportMappingRequest struct {
+	Name     *string           `json:"name,omitempty" binding:"omitempty,min=1,max=255"`
+	Columns  map[string]string `json:"columns,omitempty"`
+	Defaults map[string]string `json:"defaults,omitempty"`
+}
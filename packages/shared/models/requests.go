@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 // Auth Requests
 type LoginRequest struct {
 	Email    string `json:"email" validate:"required,email"`
@@ -34,6 +36,17 @@ type UpdateRegistrationRequest struct {
 	FormData           map[string]interface{} `json:"form_data,omitempty"`
 }
 
+// TransitionRegistrationRequest carries the optional reason for a workflow action
+type TransitionRegistrationRequest struct {
+	Reason *string `json:"reason,omitempty" validate:"omitempty,max=2000"`
+}
+
+// RecordInvoicePaymentRequest records that a registration's invoice was paid,
+// now unless PaidAt is given
+type RecordInvoicePaymentRequest struct {
+	PaidAt *time.Time `json:"paid_at,omitempty"`
+}
+
 // Document Requests
 type DocumentUploadRequest struct {
 	DocumentType   string `form:"document_type" validate:"required,document_type"`
@@ -42,12 +55,16 @@ type DocumentUploadRequest struct {
 }
 
 // Commission Requests
+
+// CreateCommissionRequest records an agent's commission on a registration.
+// RegistrationFee and Currency come from the quote locked when the
+// registration was submitted, and are only needed without one.
 type CreateCommissionRequest struct {
-	RegistrationID  string  `json:"registration_id" validate:"required,uuid"`
-	AgentID         string  `json:"agent_id" validate:"required,uuid"`
-	RegistrationFee float64 `json:"registration_fee" validate:"required,gt=0"`
-	CommissionRate  float64 `json:"commission_rate" validate:"required,commission_rate"`
-	Currency        string  `json:"currency" validate:"required,currency"`
+	RegistrationID  string   `json:"registration_id" validate:"required,uuid"`
+	AgentID         string   `json:"agent_id" validate:"required,uuid"`
+	RegistrationFee *float64 `json:"registration_fee,omitempty" validate:"omitempty,gt=0"`
+	CommissionRate  float64  `json:"commission_rate" validate:"required,commission_rate"`
+	Currency        string   `json:"currency,omitempty" validate:"omitempty,currency"`
 }
 
 type PayCommissionRequest struct {
@@ -56,25 +73,49 @@ type PayCommissionRequest struct {
 
 // Client Requests
 type CreateClientRequest struct {
-	ClientType   string  `json:"client_type" validate:"required,oneof=individual company"`
-	FullName     *string `json:"full_name,omitempty" validate:"required_if=ClientType individual,omitempty,min=2,max=255"`
-	CompanyName  *string `json:"company_name,omitempty" validate:"required_if=ClientType company,omitempty,min=2,max=255"`
-	IDNumber     *string `json:"id_number,omitempty" validate:"omitempty,sa_id_number"`
-	TaxNumber    *string `json:"tax_number,omitempty"`
-	VATNumber    *string `json:"vat_number,omitempty" validate:"omitempty,vat_number"`
-	Email        string  `json:"email" validate:"required,email"`
-	Phone        *string `json:"phone,omitempty" validate:"omitempty,phone"`
-	Mobile       *string `json:"mobile,omitempty" validate:"omitempty,phone"`
-	CountryCode  *string `json:"country_code,omitempty" validate:"omitempty,country_code,len=2"`
+	ClientType     string  `json:"client_type" validate:"required,oneof=individual company"`
+	FullName       *string `json:"full_name,omitempty" validate:"required_if=ClientType individual,omitempty,min=2,max=255"`
+	CompanyName    *string `json:"company_name,omitempty" validate:"required_if=ClientType company,omitempty,min=2,max=255"`
+	IDNumber       *string `json:"id_number,omitempty" validate:"omitempty,sa_id_number"`
+	PassportNumber *string `json:"passport_number,omitempty" validate:"omitempty,max=50"`
+	TaxNumber      *string `json:"tax_number,omitempty"`
+	VATNumber      *string `json:"vat_number,omitempty" validate:"omitempty,vat_number"`
+	Email          string  `json:"email" validate:"required,email"`
+	Phone          *string `json:"phone,omitempty" validate:"omitempty,phone"`
+	Mobile         *string `json:"mobile,omitempty" validate:"omitempty,phone"`
+	StreetAddress  *string `json:"street_address,omitempty" validate:"omitempty,max=500"`
+	City           *string `json:"city,omitempty" validate:"omitempty,max=100"`
+	StateProvince  *string `json:"state_province,omitempty" validate:"omitempty,max=100"`
+	PostalCode     *string `json:"postal_code,omitempty" validate:"omitempty,max=20"`
+	CountryCode    *string `json:"country_code,omitempty" validate:"omitempty,country_code,len=2"`
 }
 
 type UpdateClientRequest struct {
-	FullName    *string `json:"full_name,omitempty" validate:"omitempty,min=2,max=255"`
-	CompanyName *string `json:"company_name,omitempty" validate:"omitempty,min=2,max=255"`
-	Email       *string `json:"email,omitempty" validate:"omitempty,email"`
-	Phone       *string `json:"phone,omitempty" validate:"omitempty,phone"`
-	Mobile      *string `json:"mobile,omitempty" validate:"omitempty,phone"`
-	Status      *string `json:"status,omitempty" validate:"omitempty,oneof=active inactive suspended"`
+	FullName       *string `json:"full_name,omitempty" validate:"omitempty,min=2,max=255"`
+	CompanyName    *string `json:"company_name,omitempty" validate:"omitempty,min=2,max=255"`
+	IDNumber       *string `json:"id_number,omitempty" validate:"omitempty,sa_id_number"`
+	PassportNumber *string `json:"passport_number,omitempty" validate:"omitempty,max=50"`
+	TaxNumber      *string `json:"tax_number,omitempty"`
+	VATNumber      *string `json:"vat_number,omitempty" validate:"omitempty,vat_number"`
+	Email          *string `json:"email,omitempty" validate:"omitempty,email"`
+	Phone          *string `json:"phone,omitempty" validate:"omitempty,phone"`
+	Mobile         *string `json:"mobile,omitempty" validate:"omitempty,phone"`
+	StreetAddress  *string `json:"street_address,omitempty" validate:"omitempty,max=500"`
+	City           *string `json:"city,omitempty" validate:"omitempty,max=100"`
+	StateProvince  *string `json:"state_province,omitempty" validate:"omitempty,max=100"`
+	PostalCode     *string `json:"postal_code,omitempty" validate:"omitempty,max=20"`
+	CountryCode    *string `json:"country_code,omitempty" validate:"omitempty,country_code,len=2"`
+	Status         *string `json:"status,omitempty" validate:"omitempty,oneof=active inactive suspended"`
+}
+
+// LinkClientUserRequest links a client to the user account it signs in with
+type LinkClientUserRequest struct {
+	UserID string `json:"user_id" validate:"required,uuid"`
+}
+
+// MergeClientRequest merges a duplicate client into another
+type MergeClientRequest struct {
+	SourceClientID string `json:"source_client_id" validate:"required,uuid"`
 }
 
 // Tenant Requests
@@ -96,6 +137,65 @@ type UpdateTenantRequest struct {
 	SubscriptionTier *string `json:"subscription_tier,omitempty" validate:"omitempty,oneof=starter professional enterprise"`
 }
 
+type SuspendTenantRequest struct {
+	Reason string `json:"reason" validate:"required,min=3,max=500"`
+}
+
+type ScheduleTenantDeletionRequest struct {
+	// GraceDays overrides the default grace period before tenant data is purged
+	GraceDays *int `json:"grace_days,omitempty" validate:"omitempty,min=0,max=365"`
+}
+
+type CreateTenantExportRequest struct {
+	Format string `json:"format" validate:"omitempty,oneof=jsonl csv"`
+}
+
+type CloneTenantRequest struct {
+	Name      string `json:"name" validate:"required,min=2,max=255"`
+	Subdomain string `json:"subdomain" validate:"required,subdomain"`
+	// Anonymize scrambles personal data in the copy; defaults to true
+	Anonymize *bool `json:"anonymize,omitempty"`
+	// CompanyName, ContactEmail and ContactPhone are the copy's details. They
+	// default to the source tenant's, anonymised unless Anonymize is false; the
+	// contact email becomes the copy's admin user.
+	CompanyName  string `json:"company_name,omitempty" validate:"omitempty,min=2,max=255"`
+	ContactEmail string `json:"contact_email,omitempty" validate:"omitempty,email"`
+	ContactPhone string `json:"contact_phone,omitempty" validate:"omitempty,phone"`
+}
+
+// SetTenantParentRequest makes a tenant a sub-agent of another tenant
+type SetTenantParentRequest struct {
+	ParentTenantID string `json:"parent_tenant_id" validate:"required,uuid"`
+	// OverrideRate is the percentage of each registration fee paid to the parent
+	OverrideRate *float64 `json:"override_rate,omitempty" validate:"omitempty,min=0,max=100"`
+}
+
+type SetCustomDomainRequest struct {
+	Domain string `json:"domain" validate:"required,fqdn,max=255"`
+}
+
+// UpdateTenantSettingsRequest sets several tenant settings at once
+type UpdateTenantSettingsRequest struct {
+	Settings map[string]interface{} `json:"settings" validate:"required"`
+}
+
+// UpdateTenantSettingRequest sets a single tenant setting
+type UpdateTenantSettingRequest struct {
+	Value interface{} `json:"value"`
+}
+
+// UpdateTenantBrandingRequest changes a tenant's branding. Omitted fields are left
+// as they are; an empty string clears a field back to the platform default.
+type UpdateTenantBrandingRequest struct {
+	DisplayName    *string `json:"display_name,omitempty" validate:"omitempty,max=100"`
+	PrimaryColor   *string `json:"primary_color,omitempty" validate:"omitempty,hexcolor"`
+	SecondaryColor *string `json:"secondary_color,omitempty" validate:"omitempty,hexcolor"`
+	SupportEmail   *string `json:"support_email,omitempty" validate:"omitempty,email"`
+	SenderName     *string `json:"sender_name,omitempty" validate:"omitempty,max=100"`
+	ReplyTo        *string `json:"reply_to,omitempty" validate:"omitempty,email"`
+	LegalFooter    *string `json:"legal_footer,omitempty" validate:"omitempty,max=2000"`
+}
+
 // Pagination Request
 type PaginationRequest struct {
 	Page  int `form:"page" validate:"omitempty,min=1"`
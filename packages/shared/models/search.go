@@ -0,0 +1,24 @@
+package models
+
+// FacetCount is how many search results have a value of a facet. Each facet
+// is counted with every filter applied except its own, so it shows what
+// choosing another value would return.
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// RegistrationSearchHit is a registration matching a search. Highlights holds
+// the matched fields with the matching words wrapped in <mark> tags.
+type RegistrationSearchHit struct {
+	*Registration
+	Score      float64           `json:"score"`
+	Highlights map[string]string `json:"highlights,omitempty"`
+}
+
+// ClientSearchHit is a client matching a search
+type ClientSearchHit struct {
+	*Client
+	Score      float64           `json:"score"`
+	Highlights map[string]string `json:"highlights,omitempty"`
+}
@@ -13,23 +13,33 @@ type Tenant struct {
 	Name             string     `json:"name" db:"name"`
 	Subdomain        string     `json:"subdomain" db:"subdomain"`
 	Domain           *string    `json:"domain,omitempty" db:"domain"`
+	DomainStatus     *string    `json:"domain_status,omitempty" db:"domain_status"`
+	DomainVerifiedAt *time.Time `json:"domain_verified_at,omitempty" db:"domain_verified_at"`
+	DomainCheckedAt  *time.Time `json:"domain_last_checked_at,omitempty" db:"domain_last_checked_at"`
 	Status           string     `json:"status" db:"status"`
-	SubscriptionTier string     `json:"subscription_tier" db:"subscription_tier"`
-	CompanyName      *string    `json:"company_name,omitempty" db:"company_name"`
-	ContactEmail     *string    `json:"contact_email,omitempty" db:"contact_email"`
-	ContactPhone     *string    `json:"contact_phone,omitempty" db:"contact_phone"`
-	Country          *string    `json:"country,omitempty" db:"country"`
-	MaxUsers         int        `json:"max_users" db:"max_users"`
-	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
-	UpdatedAt        time.Time  `json:"updated_at" db:"updated_at"`
-	DeletedAt        *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+	SuspendedAt      *time.Time `json:"suspended_at,omitempty" db:"suspended_at"`
+	SuspensionReason *string    `json:"suspension_reason,omitempty" db:"suspension_reason"`
+	PurgeAfter       *time.Time `json:"purge_after,omitempty" db:"purge_after"`
+	ParentTenantID   *uuid.UUID `json:"parent_tenant_id,omitempty" db:"parent_tenant_id"`
+	// ParentOverrideRate is the percentage of each registration fee paid to the parent
+	ParentOverrideRate *float64   `json:"parent_override_rate,omitempty" db:"parent_override_rate"`
+	SubscriptionTier   string     `json:"subscription_tier" db:"subscription_tier"`
+	CompanyName        *string    `json:"company_name,omitempty" db:"company_name"`
+	ContactEmail       *string    `json:"contact_email,omitempty" db:"contact_email"`
+	ContactPhone       *string    `json:"contact_phone,omitempty" db:"contact_phone"`
+	Country            *string    `json:"country,omitempty" db:"country"`
+	MaxUsers           int        `json:"max_users" db:"max_users"`
+	CreatedAt          time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at" db:"updated_at"`
+	DeletedAt          *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
 }
 
 // TenantStatus constants
 const (
-	TenantStatusActive    = "active"
-	TenantStatusSuspended = "suspended"
-	TenantStatusDeleted   = "deleted"
+	TenantStatusActive          = "active"
+	TenantStatusSuspended       = "suspended"
+	TenantStatusPendingDeletion = "pending_deletion"
+	TenantStatusDeleted         = "deleted"
 )
 
 // SubscriptionTier constants
@@ -39,6 +49,13 @@ const (
 	SubscriptionTierEnterprise   = "enterprise"
 )
 
+// DomainStatus constants
+const (
+	DomainStatusPending  = "pending"
+	DomainStatusVerified = "verified"
+	DomainStatusFailed   = "failed"
+)
+
 // IsActive checks if tenant is active
 func (t *Tenant) IsActive() bool {
 	return t.Status == TenantStatusActive && t.DeletedAt == nil
@@ -50,11 +67,286 @@ func (t *Tenant) TenantSchema() string {
 	return "tenant_" + strings.ReplaceAll(t.ID.String(), "-", "")
 }
 
-// TenantListResponse represents a paginated list of tenants
-type TenantListResponse struct {
-	Tenants    []Tenant `json:"tenants"`
-	Total      int      `json:"total"`
-	Page       int      `json:"page"`
-	PerPage    int      `json:"per_page"`
-	TotalPages int      `json:"total_pages"`
+// IsSuspended checks if tenant access is blocked by suspension or pending deletion
+func (t *Tenant) IsSuspended() bool {
+	return t.Status == TenantStatusSuspended || t.Status == TenantStatusPendingDeletion
+}
+
+// HasVerifiedDomain checks if the tenant's custom domain passed DNS verification
+func (t *Tenant) HasVerifiedDomain() bool {
+	return t.Domain != nil && t.DomainStatus != nil && *t.DomainStatus == DomainStatusVerified
+}
+
+// HasParent checks if the tenant is a sub-agent of another tenant
+func (t *Tenant) HasParent() bool {
+	return t.ParentTenantID != nil
+}
+
+// TenantDomainVerification describes a custom domain and the DNS TXT record that proves ownership
+type TenantDomainVerification struct {
+	TenantID       uuid.UUID  `json:"tenant_id"`
+	Domain         string     `json:"domain"`
+	Status         string     `json:"status"`
+	TXTRecordName  string     `json:"txt_record_name"`
+	TXTRecordValue string     `json:"txt_record_value"`
+	VerifiedAt     *time.Time `json:"verified_at,omitempty"`
+	LastCheckedAt  *time.Time `json:"last_checked_at,omitempty"`
+	LastError      *string    `json:"last_error,omitempty"`
+}
+
+// TenantDeletionCertificate records what the purge job removed for a deleted tenant.
+// Signature is an HMAC-SHA256 over the certificate with Signature empty.
+type TenantDeletionCertificate struct {
+	TenantID            uuid.UUID  `json:"tenant_id"`
+	TenantName          string     `json:"tenant_name"`
+	Subdomain           string     `json:"subdomain"`
+	SchemaName          string     `json:"schema_name"`
+	DeletionRequestedAt *time.Time `json:"deletion_requested_at,omitempty"`
+	SchemaDropped       bool       `json:"schema_dropped"`
+	ObjectsDeleted      int        `json:"objects_deleted"`
+	SessionsRevoked     int        `json:"sessions_revoked"`
+	PurgedAt            time.Time  `json:"purged_at"`
+	Signature           string     `json:"signature,omitempty"`
+}
+
+// TenantHierarchyRollup aggregates the registrations and commissions of a tenant's
+// descendants. Amounts are totalled per currency.
+type TenantHierarchyRollup struct {
+	TenantID      uuid.UUID                `json:"tenant_id"`
+	From          *time.Time               `json:"from,omitempty"`
+	To            *time.Time               `json:"to,omitempty"`
+	Children      []TenantRollupEntry      `json:"children"`
+	Registrations map[string]int           `json:"registrations"`
+	Commissions   map[string]RollupAmounts `json:"commissions"`
+	Overrides     map[string]RollupAmounts `json:"overrides"`
+	GeneratedAt   time.Time                `json:"generated_at"`
+}
+
+// TenantRollupEntry is a single descendant in a hierarchy roll-up
+type TenantRollupEntry struct {
+	TenantID       uuid.UUID                `json:"tenant_id"`
+	Name           string                   `json:"name"`
+	ParentTenantID uuid.UUID                `json:"parent_tenant_id"`
+	Depth          int                      `json:"depth"`
+	Registrations  map[string]int           `json:"registrations"`
+	Commissions    map[string]RollupAmounts `json:"commissions"`
+	// Unavailable is set when the descendant is not provisioned or could not be read
+	Unavailable *string `json:"unavailable,omitempty"`
+}
+
+// RollupAmounts totals commission amounts by status for one currency
+type RollupAmounts struct {
+	Count     int     `json:"count"`
+	Total     float64 `json:"total"`
+	Pending   float64 `json:"pending"`
+	Approved  float64 `json:"approved"`
+	Paid      float64 `json:"paid"`
+	Cancelled float64 `json:"cancelled"`
+}
+
+// Add records count commissions in a status totalling amount
+func (a *RollupAmounts) Add(status string, count int, amount float64) {
+	a.Count += count
+	switch status {
+	case "pending":
+		a.Pending += amount
+	case "approved":
+		a.Approved += amount
+	case "paid":
+		a.Paid += amount
+	case "cancelled":
+		a.Cancelled += amount
+		return
+	}
+	a.Total += amount
+}
+
+// Merge adds another set of amounts to a
+func (a *RollupAmounts) Merge(other RollupAmounts) {
+	a.Count += other.Count
+	a.Total += other.Total
+	a.Pending += other.Pending
+	a.Approved += other.Approved
+	a.Paid += other.Paid
+	a.Cancelled += other.Cancelled
+}
+
+// TenantProvisioningStep represents the progress of a single provisioning step
+type TenantProvisioningStep struct {
+	Step        string     `json:"step" db:"step"`
+	Position    int        `json:"position" db:"position"`
+	Status      string     `json:"status" db:"status"`
+	Attempts    int        `json:"attempts" db:"attempts"`
+	LastError   *string    `json:"last_error,omitempty" db:"last_error"`
+	StartedAt   *time.Time `json:"started_at,omitempty" db:"started_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty" db:"completed_at"`
+}
+
+// Provisioning step constants, in execution order
+const (
+	ProvisioningStepCreateSchema    = "create_schema"
+	ProvisioningStepApplyMigrations = "apply_migrations"
+	ProvisioningStepSeedDefaults    = "seed_defaults"
+	ProvisioningStepCreateAdmin     = "create_admin_user"
+	ProvisioningStepMarkReady       = "mark_ready"
+)
+
+// ProvisioningSteps lists every provisioning step in execution order
+var ProvisioningSteps = []string{
+	ProvisioningStepCreateSchema,
+	ProvisioningStepApplyMigrations,
+	ProvisioningStepSeedDefaults,
+	ProvisioningStepCreateAdmin,
+	ProvisioningStepMarkReady,
+}
+
+// Provisioning status constants (used for both steps and the overall run)
+const (
+	ProvisioningStatusPending   = "pending"
+	ProvisioningStatusRunning   = "running"
+	ProvisioningStatusCompleted = "completed"
+	ProvisioningStatusFailed    = "failed"
+)
+
+// TenantProvisioningStatus summarises provisioning progress for a tenant
+type TenantProvisioningStatus struct {
+	TenantID      uuid.UUID                `json:"tenant_id"`
+	Status        string                   `json:"status"`
+	CurrentStep   *string                  `json:"current_step,omitempty"`
+	Steps         []TenantProvisioningStep `json:"steps"`
+	ProvisionedAt *time.Time               `json:"provisioned_at,omitempty"`
+}
+
+// TenantExport represents an asynchronous full data export of a tenant
+type TenantExport struct {
+	ID          uuid.UUID             `json:"id" db:"id"`
+	TenantID    uuid.UUID             `json:"tenant_id" db:"tenant_id"`
+	Format      string                `json:"format" db:"format"`
+	Status      string                `json:"status" db:"status"`
+	RequestedBy *uuid.UUID            `json:"requested_by,omitempty" db:"requested_by"`
+	CurrentStep *string               `json:"current_step,omitempty" db:"current_step"`
+	ItemsTotal  int                   `json:"items_total" db:"items_total"`
+	ItemsDone   int                   `json:"items_done" db:"items_done"`
+	Progress    int                   `json:"progress"`
+	Checksum    *string               `json:"checksum,omitempty" db:"checksum"`
+	SizeBytes   *int64                `json:"size_bytes,omitempty" db:"size_bytes"`
+	Manifest    *TenantExportManifest `json:"manifest,omitempty" db:"manifest"`
+	LastError   *string               `json:"last_error,omitempty" db:"last_error"`
+	DownloadURL *string               `json:"download_url,omitempty"`
+	StartedAt   *time.Time            `json:"started_at,omitempty" db:"started_at"`
+	CompletedAt *time.Time            `json:"completed_at,omitempty" db:"completed_at"`
+	ExpiresAt   *time.Time            `json:"expires_at,omitempty" db:"expires_at"`
+	CreatedAt   time.Time             `json:"created_at" db:"created_at"`
+}
+
+// Export format constants
+const (
+	ExportFormatJSONL = "jsonl"
+	ExportFormatCSV   = "csv"
+)
+
+// Export status constants
+const (
+	ExportStatusPending   = "pending"
+	ExportStatusRunning   = "running"
+	ExportStatusCompleted = "completed"
+	ExportStatusFailed    = "failed"
+	ExportStatusExpired   = "expired"
+)
+
+// TenantExportManifest lists the contents of an export archive
+type TenantExportManifest struct {
+	TenantID    uuid.UUID          `json:"tenant_id"`
+	TenantName  string             `json:"tenant_name"`
+	SchemaName  string             `json:"schema_name"`
+	Format      string             `json:"format"`
+	GeneratedAt time.Time          `json:"generated_at"`
+	Tables      []TenantExportFile `json:"tables"`
+	Documents   []TenantExportFile `json:"documents"`
+	// OmittedTables and OmittedColumns hold secrets deliberately left out of the export
+	OmittedTables    []string            `json:"omitted_tables,omitempty"`
+	OmittedColumns   map[string][]string `json:"omitted_columns,omitempty"`
+	MissingDocuments []string            `json:"missing_documents,omitempty"`
+}
+
+// TenantExportFile is a single file in an export archive
+type TenantExportFile struct {
+	Path   string `json:"path"`
+	Source string `json:"source"`
+	Rows   int    `json:"rows,omitempty"`
+	Bytes  int64  `json:"bytes"`
+	SHA256 string `json:"sha256"`
+}
+
+// TenantImport represents an import of an export archive or a clone into a freshly provisioned tenant
+type TenantImport struct {
+	ID               uuid.UUID           `json:"id" db:"id"`
+	TenantID         uuid.UUID           `json:"tenant_id" db:"tenant_id"`
+	Source           string              `json:"source" db:"source"`
+	SourceTenantID   *uuid.UUID          `json:"source_tenant_id,omitempty" db:"source_tenant_id"`
+	RemapIDs         bool                `json:"remap_ids" db:"remap_ids"`
+	Anonymize        bool                `json:"anonymize" db:"anonymize"`
+	Status           string              `json:"status" db:"status"`
+	RequestedBy      *uuid.UUID          `json:"requested_by,omitempty" db:"requested_by"`
+	CurrentStep      *string             `json:"current_step,omitempty" db:"current_step"`
+	ItemsTotal       int                 `json:"items_total" db:"items_total"`
+	ItemsDone        int                 `json:"items_done" db:"items_done"`
+	Progress         int                 `json:"progress"`
+	Stats            *TenantImportStats  `json:"stats,omitempty" db:"stats"`
+	ValidationErrors []TenantImportError `json:"validation_errors,omitempty" db:"validation_errors"`
+	LastError        *string             `json:"last_error,omitempty" db:"last_error"`
+	StartedAt        *time.Time          `json:"started_at,omitempty" db:"started_at"`
+	CompletedAt      *time.Time          `json:"completed_at,omitempty" db:"completed_at"`
+	CreatedAt        time.Time           `json:"created_at" db:"created_at"`
+}
+
+// Import source constants
+const (
+	ImportSourceArchive = "archive"
+	ImportSourceClone   = "clone"
+)
+
+// Import status constants
+const (
+	ImportStatusPending   = "pending"
+	ImportStatusRunning   = "running"
+	ImportStatusCompleted = "completed"
+	ImportStatusFailed    = "failed"
+)
+
+// TenantImportStats summarises what an import restored
+type TenantImportStats struct {
+	Rows              map[string]int `json:"rows"`
+	DocumentsImported int            `json:"documents_imported"`
+	DocumentsSkipped  int            `json:"documents_skipped"`
+	// SettingsSkipped lists encrypted settings left out: always for anonymised
+	// copies, and otherwise those that could not be decrypted
+	SettingsSkipped []string `json:"settings_skipped,omitempty"`
+}
+
+// TenantImportError is a single problem found while validating an import
+type TenantImportError struct {
+	Table   string `json:"table,omitempty"`
+	Row     int    `json:"row,omitempty"`
+	Column  string `json:"column,omitempty"`
+	Value   string `json:"value,omitempty"`
+	Message string `json:"message"`
+}
+
+// TenantSetting is a tenant setting as returned by the settings API. Sensitive
+// values are never returned; IsSet tells whether the tenant stored its own value.
+type TenantSetting struct {
+	Key         string      `json:"key"`
+	Type        string      `json:"type"`
+	Value       interface{} `json:"value"`
+	Default     interface{} `json:"default,omitempty"`
+	IsSet       bool        `json:"is_set"`
+	Sensitive   bool        `json:"sensitive"`
+	ReadOnly    bool        `json:"read_only"`
+	Description string      `json:"description,omitempty"`
+	// Local settings are never inherited from a parent tenant
+	Local bool `json:"local"`
+	// InheritedFrom is the ancestor whose value applies when the tenant has not set its own
+	InheritedFrom *uuid.UUID `json:"inherited_from,omitempty"`
+	UpdatedAt     *time.Time `json:"updated_at,omitempty"`
 }
@@ -48,6 +48,9 @@ const (
 	RoleAgent           = "agent"
 	RoleAgentAssistant  = "agent_assistant"
 	RoleClient          = "client"
+
+	// RoleGlobalAdmin is a platform operator who may act on any tenant
+	RoleGlobalAdmin = "global_admin"
 )
 
 // MFAMethod constants
@@ -0,0 +1,380 @@
+// Package pagination implements keyset pagination for list endpoints: opaque
+// cursors, whitelisted sort fields, optional total counts and sparse fieldsets,
+// returned in one list envelope.
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	apierrors "github.com/comply360/shared/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultLimit is the page size when none is given
+	DefaultLimit = 20
+	// MaxLimit is the largest page size a client may ask for
+	MaxLimit = 100
+)
+
+// Page is a list request, read from ?limit=&cursor=&sort=&include_total=&fields=
+type Page struct {
+	Limit  int
+	Cursor string
+	// Sort is a field, descending when prefixed with "-"
+	Sort string
+	// IncludeTotal asks for the number of matching rows, which costs a count
+	IncludeTotal bool
+	// Fields limits each item to the named JSON fields
+	Fields []string
+}
+
+// Error is a list request the client got wrong
+type Error struct {
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// IsRequestError reports whether err is a list request the client got wrong
+func IsRequestError(err error) bool {
+	var requestErr *Error
+	return errors.As(err, &requestErr)
+}
+
+// FromQuery reads a list request from the query string. Page sizes outside
+// 1..MaxLimit are clamped.
+func FromQuery(c *gin.Context) (Page, error) {
+	page := Page{
+		Limit:  DefaultLimit,
+		Cursor: c.Query("cursor"),
+		Sort:   strings.TrimSpace(c.Query("sort")),
+	}
+
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			return page, &Error{Message: "limit must be a number"}
+		}
+		page.Limit = limit
+	}
+	if page.Limit <= 0 {
+		page.Limit = DefaultLimit
+	}
+	if page.Limit > MaxLimit {
+		page.Limit = MaxLimit
+	}
+
+	if v := c.Query("include_total"); v != "" {
+		includeTotal, err := strconv.ParseBool(v)
+		if err != nil {
+			return page, &Error{Message: "include_total must be true or false"}
+		}
+		page.IncludeTotal = includeTotal
+	}
+
+	for _, field := range strings.Split(c.Query("fields"), ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			page.Fields = append(page.Fields, field)
+		}
+	}
+
+	return page, nil
+}
+
+// Column is a field a list can be sorted by. The column must be NOT NULL.
+type Column[T any] struct {
+	// SQL is the column or expression to order by
+	SQL string
+	// Value returns an item's value of the column, stored in the next cursor
+	Value func(item T) interface{}
+}
+
+// Keys describes how a list of T is ordered: the sortable fields and the unique
+// id column that breaks ties between equal values
+type Keys[T any] struct {
+	ID      func(item T) uuid.UUID
+	Columns map[string]Column[T]
+	// Default is the sort when none is given, such as "-created_at"
+	Default string
+}
+
+// cursor is the position after the last item of a page
+type cursor struct {
+	Sort  string      `json:"s"`
+	Value interface{} `json:"v"`
+	ID    uuid.UUID   `json:"id"`
+}
+
+// Query is a validated list request for a list of T
+type Query[T any] struct {
+	keys   *Keys[T]
+	sort   string
+	desc   bool
+	column Column[T]
+	after  *cursor
+	limit  int
+	total  bool
+}
+
+// Query validates a list request against the sortable fields
+func (k *Keys[T]) Query(page Page) (*Query[T], error) {
+	sort := page.Sort
+	if sort == "" {
+		sort = k.Default
+	}
+	field := strings.TrimPrefix(sort, "-")
+	column, ok := k.Columns[field]
+	if !ok {
+		return nil, &Error{Message: fmt.Sprintf("unsupported sort field: %s", field)}
+	}
+
+	q := &Query[T]{
+		keys:   k,
+		sort:   sort,
+		desc:   strings.HasPrefix(sort, "-"),
+		column: column,
+		limit:  page.Limit,
+		total:  page.IncludeTotal,
+	}
+	if q.limit <= 0 {
+		q.limit = DefaultLimit
+	}
+
+	if page.Cursor != "" {
+		after, err := decodeCursor(page.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		if after.Sort != sort {
+			return nil, &Error{Message: "cursor does not match sort"}
+		}
+		q.after = after
+	}
+
+	return q, nil
+}
+
+// Sort returns the sort the list is ordered by
+func (q *Query[T]) Sort() string {
+	return q.sort
+}
+
+// IncludeTotal reports whether the total number of matching rows was asked for
+func (q *Query[T]) IncludeTotal() bool {
+	return q.total
+}
+
+// Keyset returns the condition selecting rows after the cursor, with its
+// arguments numbered from next, or "" on the first page
+func (q *Query[T]) Keyset(next int) (string, []interface{}) {
+	if q.after == nil {
+		return "", nil
+	}
+	op := ">"
+	if q.desc {
+		op = "<"
+	}
+	return fmt.Sprintf("(%s, id) %s ($%d, $%d)", q.column.SQL, op, next, next+1), []interface{}{q.after.Value, q.after.ID}
+}
+
+// OrderBy returns the ORDER BY expression of the sort
+func (q *Query[T]) OrderBy() string {
+	if q.desc {
+		return fmt.Sprintf("%s DESC, id DESC", q.column.SQL)
+	}
+	return fmt.Sprintf("%s ASC, id ASC", q.column.SQL)
+}
+
+// Limit returns how many rows to fetch: one more than the page size, to tell
+// whether another page follows
+func (q *Query[T]) Limit() int {
+	return q.limit + 1
+}
+
+// List returns the page of items fetched with Limit. total is nil unless the
+// total was asked for.
+func (q *Query[T]) List(items []T, total *int) *List[T] {
+	if items == nil {
+		items = []T{}
+	}
+
+	list := &List[T]{
+		Data:  items,
+		Limit: q.limit,
+		Sort:  q.sort,
+		Total: total,
+	}
+	if len(items) > q.limit {
+		list.Data = items[:q.limit]
+		list.HasMore = true
+
+		last := list.Data[q.limit-1]
+		list.NextCursor = encodeCursor(&cursor{
+			Sort:  q.sort,
+			Value: q.column.Value(last),
+			ID:    q.keys.ID(last),
+		})
+	}
+	return list
+}
+
+// List is the envelope every list endpoint responds with
+type List[T any] struct {
+	Data []T `json:"data"`
+	// NextCursor fetches the next page; it is empty on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+	Limit      int    `json:"limit"`
+	Sort       string `json:"sort"`
+	Total      *int   `json:"total,omitempty"`
+}
+
+func encodeCursor(c *cursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(value string) (*cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, &Error{Message: "invalid cursor"}
+	}
+	var c cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.ID == uuid.Nil || c.Value == nil {
+		return nil, &Error{Message: "invalid cursor"}
+	}
+	return &c, nil
+}
+
+// Select returns the list with each item limited to the named JSON fields. The
+// id is always included. Every field must be one of the item's JSON fields.
+func Select[T any](list *List[T], fields []string) (interface{}, error) {
+	if len(fields) == 0 {
+		return list, nil
+	}
+
+	known := jsonFields(reflect.TypeOf((*T)(nil)).Elem())
+	keep := map[string]bool{"id": true}
+	for _, field := range fields {
+		if !known[field] {
+			return nil, &Error{Message: fmt.Sprintf("unknown field: %s", field)}
+		}
+		keep[field] = true
+	}
+
+	data := make([]map[string]json.RawMessage, len(list.Data))
+	for i, item := range list.Data {
+		encoded, err := json.Marshal(item)
+		if err != nil {
+			return nil, err
+		}
+		var all map[string]json.RawMessage
+		if err := json.Unmarshal(encoded, &all); err != nil {
+			return nil, err
+		}
+
+		data[i] = map[string]json.RawMessage{}
+		for name, value := range all {
+			if keep[name] {
+				data[i][name] = value
+			}
+		}
+	}
+
+	return &List[map[string]json.RawMessage]{
+		Data:       data,
+		NextCursor: list.NextCursor,
+		HasMore:    list.HasMore,
+		Limit:      list.Limit,
+		Sort:       list.Sort,
+		Total:      list.Total,
+	}, nil
+}
+
+// jsonFields returns the JSON field names of a struct type, including those of
+// embedded structs
+func jsonFields(t reflect.Type) map[string]bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	fields := map[string]bool{}
+	if t.Kind() != reflect.Struct {
+		return fields
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" || !field.IsExported() {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if field.Anonymous && name == "" {
+			for embedded := range jsonFields(field.Type) {
+				fields[embedded] = true
+			}
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = true
+	}
+	return fields
+}
+
+// Respond writes a list, limited to the requested fields
+func Respond[T any](c *gin.Context, list *List[T], page Page) {
+	RespondWith(c, list, page, nil)
+}
+
+// RespondWith writes a list like Respond, with extra fields beside the
+// envelope's, such as a search's facet counts
+func RespondWith[T any](c *gin.Context, list *List[T], page Page, extra gin.H) {
+	body, err := Select(list, page.Fields)
+	if err != nil {
+		if IsRequestError(err) {
+			c.JSON(http.StatusBadRequest, apierrors.NewAPIError(apierrors.ErrInvalidInput, err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, apierrors.NewAPIError(apierrors.ErrInternalServer, "Failed to encode list"))
+		return
+	}
+	if len(extra) > 0 {
+		body, err = withFields(body, extra)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, apierrors.NewAPIError(apierrors.ErrInternalServer, "Failed to encode list"))
+			return
+		}
+	}
+	c.JSON(http.StatusOK, body)
+}
+
+// withFields returns an encoded object with extra fields added to it
+func withFields(body interface{}, extra gin.H) (interface{}, error) {
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		return nil, err
+	}
+	for name, value := range extra {
+		if fields[name], err = json.Marshal(value); err != nil {
+			return nil, err
+		}
+	}
+	return fields, nil
+}
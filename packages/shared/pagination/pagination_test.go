@@ -0,0 +1,138 @@
+package pagination
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	testhelpers "github.com/comply360/shared/testing"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type item struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	Secret    string    `json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+var keys = &Keys[*item]{
+	ID: func(i *item) uuid.UUID { return i.ID },
+	Columns: map[string]Column[*item]{
+		"created_at": {SQL: "created_at", Value: func(i *item) interface{} { return i.CreatedAt }},
+		"name":       {SQL: "lower(name)", Value: func(i *item) interface{} { return i.Name }},
+	},
+	Default: "-created_at",
+}
+
+func items(n int) []*item {
+	list := make([]*item, n)
+	for i := range list {
+		list[i] = &item{ID: uuid.New(), Name: "item", CreatedAt: time.Date(2026, 10, 18, 12, 0, i, 0, time.UTC)}
+	}
+	return list
+}
+
+func TestQuery_CursorContinuesAfterLastItem(t *testing.T) {
+	q, err := keys.Query(Page{Limit: 2})
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 3, q.Limit(), "One extra row tells whether another page follows")
+	testhelpers.AssertEqual(t, "created_at DESC, id DESC", q.OrderBy())
+
+	condition, _ := q.Keyset(3)
+	testhelpers.AssertEqual(t, "", condition, "The first page has no keyset condition")
+
+	fetched := items(3)
+	list := q.List(fetched, nil)
+	testhelpers.AssertEqual(t, 2, len(list.Data))
+	testhelpers.AssertTrue(t, list.HasMore)
+	testhelpers.AssertTrue(t, list.Total == nil)
+
+	next, err := keys.Query(Page{Limit: 2, Cursor: list.NextCursor})
+	testhelpers.AssertNoError(t, err)
+	condition, args := next.Keyset(3)
+	testhelpers.AssertEqual(t, "(created_at, id) < ($3, $4)", condition)
+	testhelpers.AssertEqual(t, 2, len(args))
+	testhelpers.AssertEqual(t, fetched[1].ID, args[1].(uuid.UUID))
+
+	last := next.List(items(1), nil)
+	testhelpers.AssertFalse(t, last.HasMore)
+	testhelpers.AssertEqual(t, "", last.NextCursor)
+}
+
+func TestQuery_RejectsBadSortAndCursor(t *testing.T) {
+	_, err := keys.Query(Page{Sort: "secret"})
+	testhelpers.AssertTrue(t, IsRequestError(err))
+
+	_, err = keys.Query(Page{Cursor: "not-a-cursor"})
+	testhelpers.AssertTrue(t, IsRequestError(err))
+
+	q, _ := keys.Query(Page{Limit: 1})
+	list := q.List(items(2), nil)
+	_, err = keys.Query(Page{Sort: "name", Cursor: list.NextCursor})
+	testhelpers.AssertTrue(t, IsRequestError(err), "A cursor only continues the sort it was issued for")
+
+	q, err = keys.Query(Page{Sort: "name"})
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "lower(name) ASC, id ASC", q.OrderBy())
+}
+
+func TestSelect_KeepsRequestedFields(t *testing.T) {
+	q, _ := keys.Query(Page{})
+	list := q.List(items(1), nil)
+
+	selected, err := Select(list, []string{"name"})
+	testhelpers.AssertNoError(t, err)
+
+	encoded, _ := json.Marshal(selected)
+	var body struct {
+		Data []map[string]interface{} `json:"data"`
+	}
+	testhelpers.AssertNoError(t, json.Unmarshal(encoded, &body))
+	testhelpers.AssertEqual(t, 2, len(body.Data[0]), "The id is always kept")
+	testhelpers.AssertEqual(t, "item", body.Data[0]["name"])
+
+	_, err = Select(list, []string{"secret"})
+	testhelpers.AssertTrue(t, IsRequestError(err))
+}
+
+func TestRespondWith_AddsFieldsBesideEnvelope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+
+	q, _ := keys.Query(Page{Limit: 1})
+	RespondWith(c, q.List(items(2), nil), Page{Fields: []string{"name"}}, gin.H{"facets": []string{"status"}})
+
+	testhelpers.AssertEqual(t, 200, recorder.Code)
+	var body struct {
+		Data    []map[string]interface{} `json:"data"`
+		HasMore bool                     `json:"has_more"`
+		Facets  []string                 `json:"facets"`
+	}
+	testhelpers.AssertNoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	testhelpers.AssertEqual(t, 1, len(body.Data))
+	testhelpers.AssertEqual(t, 2, len(body.Data[0]), "Fields are still selected")
+	testhelpers.AssertTrue(t, body.HasMore)
+	testhelpers.AssertEqual(t, 1, len(body.Facets))
+}
+
+func TestFromQuery_ClampsLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+	c.Request = httptest.NewRequest("GET", "/?limit=500&include_total=true&fields=id,%20name&sort=-name", nil)
+	page, err := FromQuery(c)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, MaxLimit, page.Limit)
+	testhelpers.AssertTrue(t, page.IncludeTotal)
+	testhelpers.AssertEqual(t, 2, len(page.Fields))
+	testhelpers.AssertEqual(t, "-name", page.Sort)
+
+	c, _ = gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", "/?limit=ten", nil)
+	_, err = FromQuery(c)
+	testhelpers.AssertTrue(t, IsRequestError(err))
+}
@@ -0,0 +1,291 @@
+package settings
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/comply360/shared/models"
+	"github.com/comply360/shared/tenancy"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// ErrNotSet is returned for a setting with neither a stored value nor a default.
+// Callers typically fall back to their service-wide configuration.
+var ErrNotSet = errors.New("setting not set")
+
+// Client reads typed tenant settings straight from the tenant schema. A sub-agent
+// tenant inherits every setting it has not set itself from its parent, except
+// local ones. Settings are cached per tenant for a TTL and dropped early when
+// tenant-service announces a change to the tenant or one of its ancestors.
+type Client struct {
+	db      *sql.DB
+	keyring *Keyring
+	ttl     time.Duration
+
+	mu       sync.Mutex
+	cache    map[uuid.UUID]cachedSettings
+	handlers []func(tenantID uuid.UUID, keys []string)
+}
+
+// cachedSettings is a tenant's decoded values, inherited ones included
+type cachedSettings struct {
+	values map[string]interface{}
+	// ancestors are the tenants the values were inherited through
+	ancestors []uuid.UUID
+	expires   time.Time
+}
+
+// NewClient creates a settings client. keyring may be nil for services that never
+// read sensitive settings; reading one then fails.
+func NewClient(db *sql.DB, keyring *Keyring, ttl time.Duration) *Client {
+	return &Client{
+		db:      db,
+		keyring: keyring,
+		ttl:     ttl,
+		cache:   map[uuid.UUID]cachedSettings{},
+	}
+}
+
+// Get returns a setting's typed value, falling back to the registry default
+func (c *Client) Get(ctx context.Context, tenantID uuid.UUID, key string) (interface{}, error) {
+	values, err := c.load(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if value, ok := values[key]; ok {
+		return value, nil
+	}
+	if def, ok := Lookup(key); ok && def.Default != nil {
+		return def.Default, nil
+	}
+	return nil, ErrNotSet
+}
+
+// String returns a string setting
+func (c *Client) String(ctx context.Context, tenantID uuid.UUID, key string) (string, error) {
+	value, err := c.Get(ctx, tenantID, key)
+	if err != nil {
+		return "", err
+	}
+	s, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("setting %s is not a string", key)
+	}
+	return s, nil
+}
+
+// Int returns a number setting as an int
+func (c *Client) Int(ctx context.Context, tenantID uuid.UUID, key string) (int, error) {
+	value, err := c.Get(ctx, tenantID, key)
+	if err != nil {
+		return 0, err
+	}
+	n, ok := value.(float64)
+	if !ok {
+		return 0, fmt.Errorf("setting %s is not a number", key)
+	}
+	return int(n), nil
+}
+
+// Bool returns a boolean setting
+func (c *Client) Bool(ctx context.Context, tenantID uuid.UUID, key string) (bool, error) {
+	value, err := c.Get(ctx, tenantID, key)
+	if err != nil {
+		return false, err
+	}
+	b, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf("setting %s is not a boolean", key)
+	}
+	return b, nil
+}
+
+// JSON decodes a setting into out
+func (c *Client) JSON(ctx context.Context, tenantID uuid.UUID, key string, out interface{}) error {
+	value, err := c.Get(ctx, tenantID, key)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+// All returns every known setting of a tenant with defaults applied, plus any
+// stored keys the registry does not know
+func (c *Client) All(ctx context.Context, tenantID uuid.UUID) (map[string]interface{}, error) {
+	values, err := c.load(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	all := make(map[string]interface{}, len(registry))
+	for _, def := range registry {
+		if def.Default != nil {
+			all[def.Key] = def.Default
+		}
+	}
+	for key, value := range values {
+		all[key] = value
+	}
+	return all, nil
+}
+
+// Invalidate drops a tenant's cached settings, and those of its descendants that
+// inherited from it
+func (c *Client) Invalidate(tenantID uuid.UUID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.cache, tenantID)
+	for id, cached := range c.cache {
+		for _, ancestor := range cached.ancestors {
+			if ancestor == tenantID {
+				delete(c.cache, id)
+				break
+			}
+		}
+	}
+}
+
+// OnChange registers fn to run after a tenant's settings change. keys lists the
+// changed settings. Handlers run on the event goroutine and should not block.
+func (c *Client) OnChange(fn func(tenantID uuid.UUID, keys []string)) {
+	c.mu.Lock()
+	c.handlers = append(c.handlers, fn)
+	c.mu.Unlock()
+}
+
+// ListenForChanges subscribes to settings change events from tenant-service.
+// Without it, changes are picked up when cached settings expire.
+func (c *Client) ListenForChanges(conn *amqp.Connection) error {
+	return tenancy.Subscribe(conn, tenancy.EventTenantSettingsChanged, func(event tenancy.Event) {
+		c.Invalidate(event.TenantID)
+
+		c.mu.Lock()
+		handlers := append([]func(uuid.UUID, []string){}, c.handlers...)
+		c.mu.Unlock()
+
+		for _, fn := range handlers {
+			fn(event.TenantID, event.Keys)
+		}
+	})
+}
+
+// load returns a tenant's values from cache or the tenant schema
+func (c *Client) load(ctx context.Context, tenantID uuid.UUID) (map[string]interface{}, error) {
+	cached, err := c.loadCached(ctx, tenantID, 0)
+	if err != nil {
+		return nil, err
+	}
+	return cached.values, nil
+}
+
+// loadCached returns a tenant's values with those inherited from its ancestors
+func (c *Client) loadCached(ctx context.Context, tenantID uuid.UUID, depth int) (cachedSettings, error) {
+	c.mu.Lock()
+	cached, ok := c.cache[tenantID]
+	c.mu.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached, nil
+	}
+
+	values, parentID, err := c.query(ctx, tenantID)
+	if err != nil {
+		return cachedSettings{}, err
+	}
+
+	cached = cachedSettings{values: values}
+	if parentID != nil && depth < tenancy.MaxHierarchyDepth {
+		parent, err := c.loadCached(ctx, *parentID, depth+1)
+		if err != nil {
+			// A child keeps working on its own values if its parent cannot be read
+			log.Printf("Warning: Failed to load inherited settings for tenant %s: %v", tenantID, err)
+		} else {
+			cached.values = Inherit(values, parent.values)
+			cached.ancestors = append([]uuid.UUID{*parentID}, parent.ancestors...)
+		}
+	}
+	cached.expires = time.Now().Add(c.ttl)
+
+	c.mu.Lock()
+	c.cache[tenantID] = cached
+	c.mu.Unlock()
+
+	return cached, nil
+}
+
+// query reads and decodes a tenant's stored settings, and returns its parent tenant
+func (c *Client) query(ctx context.Context, tenantID uuid.UUID) (map[string]interface{}, *uuid.UUID, error) {
+	tenant := models.Tenant{ID: tenantID}
+	query := fmt.Sprintf(`SELECT key, value, value_type, is_encrypted FROM %s.tenant_settings`, pq.QuoteIdentifier(tenant.TenantSchema()))
+
+	values := map[string]interface{}{}
+	var parentID *uuid.UUID
+	err := tenancy.Run(ctx, c.db, tenancy.Context{TenantID: tenantID}, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, `SELECT parent_tenant_id FROM public.tenants WHERE id = $1 AND deleted_at IS NULL`, tenantID).Scan(&parentID)
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+
+		rows, err := tx.QueryContext(ctx, query)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var key, valueType string
+			var raw sql.NullString
+			var encrypted bool
+			if err := rows.Scan(&key, &raw, &valueType, &encrypted); err != nil {
+				return err
+			}
+			if !raw.Valid {
+				continue
+			}
+
+			value, err := c.decode(tenantID, key, raw.String, valueType, encrypted)
+			if err != nil {
+				// One unreadable value should not take every other setting down with it
+				log.Printf("Warning: Failed to read setting %s for tenant %s: %v", key, tenantID, err)
+				continue
+			}
+			values[key] = value
+		}
+
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load tenant settings: %w", err)
+	}
+
+	return values, parentID, nil
+}
+
+// decode decrypts a stored value if needed and parses it
+func (c *Client) decode(tenantID uuid.UUID, key, raw, valueType string, encrypted bool) (interface{}, error) {
+	if encrypted {
+		if c.keyring == nil {
+			return nil, fmt.Errorf("no keyring configured for encrypted settings")
+		}
+		plaintext, err := c.keyring.Open(tenantID, key, raw)
+		if err != nil {
+			return nil, err
+		}
+		raw = plaintext
+	}
+
+	return decodeValue(valueType, raw)
+}
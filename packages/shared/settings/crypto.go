@@ -0,0 +1,199 @@
+package settings
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// sealedPrefix marks the envelope format version of an encrypted value
+const sealedPrefix = "v1"
+
+// keySize is the size of master and data keys (AES-256)
+const keySize = 32
+
+// Keyring holds the master keys used for envelope encryption of sensitive settings.
+// Every value is encrypted with its own random data key, and the data key is wrapped
+// with a master key. New values use the primary key; the other keys are kept so
+// values sealed before a rotation can still be opened.
+type Keyring struct {
+	primary string
+	keys    map[string][]byte
+}
+
+// NewKeyring creates a keyring from master keys indexed by key ID
+func NewKeyring(primary string, keys map[string][]byte) (*Keyring, error) {
+	if _, ok := keys[primary]; !ok {
+		return nil, fmt.Errorf("primary key %q not in keyring", primary)
+	}
+	for id, key := range keys {
+		if id == "" || strings.Contains(id, ":") {
+			return nil, fmt.Errorf("invalid key ID %q", id)
+		}
+		if len(key) != keySize {
+			return nil, fmt.Errorf("key %q must be %d bytes", id, keySize)
+		}
+	}
+
+	return &Keyring{primary: primary, keys: keys}, nil
+}
+
+// ParseKeyring parses a comma-separated list of id:base64key pairs, as held in the
+// SETTINGS_MASTER_KEYS environment variable. The first key is the primary.
+func ParseKeyring(spec string) (*Keyring, error) {
+	keys := map[string][]byte{}
+	primary := ""
+
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		id, encoded, ok := strings.Cut(entry, ":")
+		if !ok {
+			return nil, fmt.Errorf("key entry must be id:base64key")
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("key %q is not valid base64: %w", id, err)
+		}
+		if _, exists := keys[id]; exists {
+			return nil, fmt.Errorf("key %q listed twice", id)
+		}
+
+		keys[id] = key
+		if primary == "" {
+			primary = id
+		}
+	}
+
+	if primary == "" {
+		return nil, fmt.Errorf("no master keys configured")
+	}
+
+	return NewKeyring(primary, keys)
+}
+
+// PrimaryKeyID returns the ID of the key new values are sealed with
+func (k *Keyring) PrimaryKeyID() string {
+	return k.primary
+}
+
+// Seal encrypts a setting value. The tenant ID and setting key are bound as
+// additional data, so a sealed value copied to another tenant or key will not open.
+func (k *Keyring) Seal(tenantID uuid.UUID, key, plaintext string) (string, error) {
+	aad := associatedData(tenantID, key)
+
+	dataKey := make([]byte, keySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return "", fmt.Errorf("failed to generate data key: %w", err)
+	}
+
+	ciphertext, err := seal(dataKey, []byte(plaintext), aad)
+	if err != nil {
+		return "", err
+	}
+	wrappedKey, err := seal(k.keys[k.primary], dataKey, aad)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.Join([]string{
+		sealedPrefix,
+		k.primary,
+		base64.StdEncoding.EncodeToString(wrappedKey),
+		base64.StdEncoding.EncodeToString(ciphertext),
+	}, ":"), nil
+}
+
+// Open decrypts a value produced by Seal for the same tenant and setting key
+func (k *Keyring) Open(tenantID uuid.UUID, key, sealed string) (string, error) {
+	parts := strings.Split(sealed, ":")
+	if len(parts) != 4 || parts[0] != sealedPrefix {
+		return "", fmt.Errorf("unrecognised encrypted value format")
+	}
+
+	masterKey, ok := k.keys[parts[1]]
+	if !ok {
+		return "", fmt.Errorf("master key %q not in keyring", parts[1])
+	}
+
+	wrappedKey, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", fmt.Errorf("invalid wrapped key: %w", err)
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(parts[3])
+	if err != nil {
+		return "", fmt.Errorf("invalid ciphertext: %w", err)
+	}
+
+	aad := associatedData(tenantID, key)
+	dataKey, err := open(masterKey, wrappedKey, aad)
+	if err != nil {
+		return "", fmt.Errorf("failed to unwrap data key: %w", err)
+	}
+	plaintext, err := open(dataKey, ciphertext, aad)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt value: %w", err)
+	}
+
+	return string(plaintext), nil
+}
+
+// SealedKeyID returns the master key ID a sealed value was written with
+func SealedKeyID(sealed string) string {
+	parts := strings.Split(sealed, ":")
+	if len(parts) != 4 || parts[0] != sealedPrefix {
+		return ""
+	}
+	return parts[1]
+}
+
+// associatedData binds a sealed value to its tenant and setting key
+func associatedData(tenantID uuid.UUID, key string) []byte {
+	return []byte(tenantID.String() + "/" + key)
+}
+
+// seal encrypts with AES-GCM, prefixing the random nonce to the ciphertext
+func seal(key, plaintext, aad []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+// open reverses seal
+func open(key, data, aad []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+
+	nonce, ciphertext := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, aad)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
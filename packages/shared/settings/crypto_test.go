@@ -0,0 +1,114 @@
+package settings
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	testhelpers "github.com/comply360/shared/testing"
+	"github.com/google/uuid"
+)
+
+func testKeyring(t *testing.T, spec string) *Keyring {
+	t.Helper()
+	keyring, err := ParseKeyring(spec)
+	testhelpers.AssertNoError(t, err)
+	return keyring
+}
+
+func keySpec(id string, fill byte) string {
+	return id + ":" + base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{fill}, keySize))
+}
+
+func TestKeyring_SealOpenRoundTrip(t *testing.T) {
+	keyring := testKeyring(t, keySpec("k1", 1))
+	tenantID := uuid.New()
+
+	sealed, err := keyring.Seal(tenantID, KeySMTPPassword, "s3cret")
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertFalse(t, strings.Contains(sealed, "s3cret"), "Sealed value should not contain the plaintext")
+	testhelpers.AssertEqual(t, "k1", SealedKeyID(sealed))
+
+	plaintext, err := keyring.Open(tenantID, KeySMTPPassword, sealed)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "s3cret", plaintext)
+}
+
+func TestKeyring_SealUsesFreshDataKeys(t *testing.T) {
+	keyring := testKeyring(t, keySpec("k1", 1))
+	tenantID := uuid.New()
+
+	first, err := keyring.Seal(tenantID, KeySMTPPassword, "same")
+	testhelpers.AssertNoError(t, err)
+	second, err := keyring.Seal(tenantID, KeySMTPPassword, "same")
+	testhelpers.AssertNoError(t, err)
+
+	testhelpers.AssertNotEqual(t, first, second)
+}
+
+func TestKeyring_OpenRejectsOtherTenantOrKey(t *testing.T) {
+	keyring := testKeyring(t, keySpec("k1", 1))
+	tenantID := uuid.New()
+
+	sealed, err := keyring.Seal(tenantID, KeySMTPPassword, "s3cret")
+	testhelpers.AssertNoError(t, err)
+
+	_, err = keyring.Open(uuid.New(), KeySMTPPassword, sealed)
+	testhelpers.AssertError(t, err)
+
+	_, err = keyring.Open(tenantID, KeyOdooPassword, sealed)
+	testhelpers.AssertError(t, err)
+}
+
+func TestKeyring_OpenRejectsTamperedValue(t *testing.T) {
+	keyring := testKeyring(t, keySpec("k1", 1))
+	tenantID := uuid.New()
+
+	sealed, err := keyring.Seal(tenantID, KeySMSAPISecret, "token")
+	testhelpers.AssertNoError(t, err)
+
+	parts := strings.Split(sealed, ":")
+	ciphertext, _ := base64.StdEncoding.DecodeString(parts[3])
+	ciphertext[len(ciphertext)-1] ^= 0xff
+	parts[3] = base64.StdEncoding.EncodeToString(ciphertext)
+
+	_, err = keyring.Open(tenantID, KeySMSAPISecret, strings.Join(parts, ":"))
+	testhelpers.AssertError(t, err)
+}
+
+func TestKeyring_RotationKeepsOldKeysReadable(t *testing.T) {
+	tenantID := uuid.New()
+
+	old := testKeyring(t, keySpec("k1", 1))
+	sealed, err := old.Seal(tenantID, KeyOdooPassword, "odoo")
+	testhelpers.AssertNoError(t, err)
+
+	rotated := testKeyring(t, keySpec("k2", 2)+","+keySpec("k1", 1))
+	testhelpers.AssertEqual(t, "k2", rotated.PrimaryKeyID())
+
+	plaintext, err := rotated.Open(tenantID, KeyOdooPassword, sealed)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "odoo", plaintext)
+
+	resealed, err := rotated.Seal(tenantID, KeyOdooPassword, plaintext)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, "k2", SealedKeyID(resealed))
+
+	_, err = old.Open(tenantID, KeyOdooPassword, resealed)
+	testhelpers.AssertError(t, err)
+}
+
+func TestParseKeyring_RejectsBadKeys(t *testing.T) {
+	_, err := ParseKeyring("")
+	testhelpers.AssertError(t, err)
+
+	_, err = ParseKeyring("k1:" + base64.StdEncoding.EncodeToString([]byte("short")))
+	testhelpers.AssertError(t, err)
+
+	_, err = ParseKeyring("k1:not-base64!")
+	testhelpers.AssertError(t, err)
+
+	_, err = ParseKeyring(keySpec("k1", 1) + "," + keySpec("k1", 2))
+	testhelpers.AssertError(t, err)
+}
@@ -0,0 +1,349 @@
+package settings
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/comply360/shared/models"
+	"github.com/comply360/shared/validator"
+)
+
+// Value types, matching the value_type column of tenant_settings
+const (
+	TypeString  = "string"
+	TypeNumber  = "number"
+	TypeBoolean = "boolean"
+	TypeJSON    = "json"
+)
+
+// Known setting keys
+const (
+	KeyEnabledRoles    = "enabled_roles"
+	KeyDefaultUserRole = "default_user_role"
+	KeyTimezone        = "timezone"
+	KeyCurrency        = "currency"
+	KeyMFARequired     = "mfa_required"
+	KeyMaxUsers        = "max_users"
+
+	KeySMTPHost     = "smtp_host"
+	KeySMTPPort     = "smtp_port"
+	KeySMTPUsername = "smtp_username"
+	KeySMTPPassword = "smtp_password"
+	KeySMTPFrom     = "smtp_from"
+
+	KeySMSProvider   = "sms_provider"
+	KeySMSAPIKey     = "sms_api_key"
+	KeySMSAPISecret  = "sms_api_secret"
+	KeySMSFromNumber = "sms_from_number"
+
+	KeyOdooURL      = "odoo_url"
+	KeyOdooDatabase = "odoo_database"
+	KeyOdooUsername = "odoo_username"
+	KeyOdooPassword = "odoo_password"
+
+	KeyBrandingDisplayName    = "branding_display_name"
+	KeyBrandingPrimaryColor   = "branding_primary_color"
+	KeyBrandingSecondaryColor = "branding_secondary_color"
+	KeyBrandingSupportEmail   = "branding_support_email"
+	KeyBrandingSenderName     = "branding_sender_name"
+	KeyBrandingReplyTo        = "branding_reply_to"
+	KeyBrandingLegalFooter    = "branding_legal_footer"
+	KeyBrandingLogoType       = "branding_logo_type"
+
+	KeyComplianceReminderDays = "compliance_reminder_days"
+)
+
+// Definition describes a known tenant setting
+type Definition struct {
+	Key         string
+	Type        string
+	Default     interface{} // typed default; nil means the setting is unset until configured
+	Sensitive   bool        // stored encrypted and never returned in plain text
+	ReadOnly    bool        // maintained by tenant-service, not writable through the settings API
+	Local       bool        // applies to the tenant only; child tenants do not inherit it
+	Description string
+	// Tag is a validator tag applied to string and number values
+	Tag string
+	// Check validates values the tag cannot express, such as JSON documents
+	Check func(value interface{}) error
+}
+
+// registry holds every setting a tenant may configure
+var registry = map[string]Definition{}
+
+var validate = validator.New()
+
+func init() {
+	for _, def := range []Definition{
+		{Key: KeyEnabledRoles, Type: TypeJSON, Description: "Roles available to users of the tenant",
+			Default: []interface{}{models.RoleTenantAdmin, models.RoleTenantManager, models.RoleAgent, models.RoleAgentAssistant, models.RoleClient},
+			Check:   checkRoles},
+		{Key: KeyDefaultUserRole, Type: TypeString, Default: models.RoleClient, Tag: "user_role",
+			Description: "Role given to newly registered users"},
+		{Key: KeyTimezone, Type: TypeString, Default: "Africa/Johannesburg", Check: checkTimezone,
+			Description: "IANA timezone used for dates shown to the tenant"},
+		{Key: KeyCurrency, Type: TypeString, Default: models.CurrencyZAR, Tag: "currency",
+			Description: "Default billing currency"},
+		{Key: KeyMFARequired, Type: TypeBoolean, Default: false,
+			Description: "Require multi-factor authentication for every user"},
+		{Key: KeyMaxUsers, Type: TypeNumber, Local: true, Default: float64(10), Tag: "min=1", Check: checkInteger,
+			Description: "Maximum number of active users"},
+
+		{Key: KeySMTPHost, Type: TypeString, Local: true, Tag: "hostname|ip",
+			Description: "SMTP server used for tenant email"},
+		{Key: KeySMTPPort, Type: TypeNumber, Local: true, Default: float64(587), Tag: "min=1,max=65535", Check: checkInteger,
+			Description: "SMTP server port"},
+		{Key: KeySMTPUsername, Type: TypeString, Local: true, Tag: "max=255",
+			Description: "SMTP username"},
+		{Key: KeySMTPPassword, Type: TypeString, Local: true, Sensitive: true, Tag: "max=255",
+			Description: "SMTP password"},
+		{Key: KeySMTPFrom, Type: TypeString, Local: true, Tag: "email",
+			Description: "Sender address for tenant email"},
+
+		{Key: KeySMSProvider, Type: TypeString, Local: true, Tag: "oneof=twilio africastalking",
+			Description: "SMS gateway: twilio or africastalking"},
+		{Key: KeySMSAPIKey, Type: TypeString, Local: true, Sensitive: true, Tag: "max=255",
+			Description: "SMS gateway API key or account SID"},
+		{Key: KeySMSAPISecret, Type: TypeString, Local: true, Sensitive: true, Tag: "max=255",
+			Description: "SMS gateway API secret or auth token"},
+		{Key: KeySMSFromNumber, Type: TypeString, Local: true, Tag: "phone",
+			Description: "Sender number for tenant SMS"},
+
+		{Key: KeyOdooURL, Type: TypeString, Local: true, Tag: "url",
+			Description: "Odoo instance URL"},
+		{Key: KeyOdooDatabase, Type: TypeString, Local: true, Tag: "max=255",
+			Description: "Odoo database name"},
+		{Key: KeyOdooUsername, Type: TypeString, Local: true, Tag: "max=255",
+			Description: "Odoo API username"},
+		{Key: KeyOdooPassword, Type: TypeString, Local: true, Sensitive: true, Tag: "max=255",
+			Description: "Odoo API password"},
+
+		{Key: KeyBrandingDisplayName, Type: TypeString, Tag: "min=1,max=100", Check: checkSingleLine,
+			Description: "Brand name shown to clients in place of Comply360"},
+		{Key: KeyBrandingPrimaryColor, Type: TypeString, Tag: "hexcolor",
+			Description: "Primary brand colour as a hex code"},
+		{Key: KeyBrandingSecondaryColor, Type: TypeString, Tag: "hexcolor",
+			Description: "Secondary brand colour as a hex code"},
+		{Key: KeyBrandingSupportEmail, Type: TypeString, Tag: "email",
+			Description: "Support address shown to clients"},
+		{Key: KeyBrandingSenderName, Type: TypeString, Tag: "min=1,max=100", Check: checkSingleLine,
+			Description: "Sender name on tenant email"},
+		{Key: KeyBrandingReplyTo, Type: TypeString, Tag: "email",
+			Description: "Reply-To address on tenant email"},
+		{Key: KeyBrandingLegalFooter, Type: TypeString, Tag: "max=2000",
+			Description: "Legal footer added to email and generated documents"},
+		{Key: KeyBrandingLogoType, Type: TypeString, ReadOnly: true,
+			Description: "Content type of the uploaded logo; set by the logo upload"},
+
+		{Key: KeyComplianceReminderDays, Type: TypeJSON, Default: []interface{}{float64(30), float64(7), float64(1)},
+			Check: checkReminderDays, Description: "Days before a compliance obligation falls due that clients are reminded"},
+	} {
+		Register(def)
+	}
+}
+
+// Inherit returns a tenant's own values over those it inherits from its parent.
+// Local settings and keys the registry does not know are never inherited.
+func Inherit(own, parent map[string]interface{}) map[string]interface{} {
+	values := make(map[string]interface{}, len(own)+len(parent))
+	for key, value := range parent {
+		if def, ok := registry[key]; ok && !def.Local {
+			values[key] = value
+		}
+	}
+	for key, value := range own {
+		values[key] = value
+	}
+	return values
+}
+
+// Register adds a setting to the registry. It is meant to be called from init
+// functions; registering the same key twice panics.
+func Register(def Definition) {
+	if _, exists := registry[def.Key]; exists {
+		panic(fmt.Sprintf("settings: %s registered twice", def.Key))
+	}
+	registry[def.Key] = def
+}
+
+// Lookup returns the definition of a known setting
+func Lookup(key string) (Definition, bool) {
+	def, ok := registry[key]
+	return def, ok
+}
+
+// Definitions returns every known setting ordered by key
+func Definitions() []Definition {
+	defs := make([]Definition, 0, len(registry))
+	for _, def := range registry {
+		defs = append(defs, def)
+	}
+	sort.Slice(defs, func(i, j int) bool {
+		return defs[i].Key < defs[j].Key
+	})
+	return defs
+}
+
+// Normalize checks a value decoded from a JSON request against the definition
+// and returns it in its canonical typed form
+func (d Definition) Normalize(value interface{}) (interface{}, error) {
+	switch d.Type {
+	case TypeString:
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("must be a string")
+		}
+		value = s
+	case TypeNumber:
+		switch n := value.(type) {
+		case float64:
+			value = n
+		case json.Number:
+			f, err := n.Float64()
+			if err != nil {
+				return nil, fmt.Errorf("must be a number")
+			}
+			value = f
+		default:
+			return nil, fmt.Errorf("must be a number")
+		}
+	case TypeBoolean:
+		if _, ok := value.(bool); !ok {
+			return nil, fmt.Errorf("must be true or false")
+		}
+	case TypeJSON:
+		if value == nil {
+			return nil, fmt.Errorf("must not be null")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported setting type %s", d.Type)
+	}
+
+	if d.Tag != "" && (d.Type == TypeString || d.Type == TypeNumber) {
+		if err := validateTag(value, d.Tag); err != nil {
+			return nil, err
+		}
+	}
+	if d.Check != nil {
+		if err := d.Check(value); err != nil {
+			return nil, err
+		}
+	}
+
+	return value, nil
+}
+
+// Encode converts a normalized value to the text stored in tenant_settings
+func (d Definition) Encode(value interface{}) (string, error) {
+	switch d.Type {
+	case TypeString:
+		return value.(string), nil
+	case TypeNumber:
+		return strconv.FormatFloat(value.(float64), 'f', -1, 64), nil
+	case TypeBoolean:
+		return strconv.FormatBool(value.(bool)), nil
+	case TypeJSON:
+		data, err := json.Marshal(value)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	}
+	return "", fmt.Errorf("unsupported setting type %s", d.Type)
+}
+
+// Decode parses the text stored in tenant_settings into a typed value
+func (d Definition) Decode(raw string) (interface{}, error) {
+	return decodeValue(d.Type, raw)
+}
+
+// decodeValue parses stored text of the given value type
+func decodeValue(valueType, raw string) (interface{}, error) {
+	switch valueType {
+	case TypeString:
+		return raw, nil
+	case TypeNumber:
+		return strconv.ParseFloat(raw, 64)
+	case TypeBoolean:
+		return strconv.ParseBool(raw)
+	case TypeJSON:
+		var value interface{}
+		if err := json.Unmarshal([]byte(raw), &value); err != nil {
+			return nil, err
+		}
+		return value, nil
+	}
+	return nil, fmt.Errorf("unsupported setting type %s", valueType)
+}
+
+// validateTag applies a validator tag and turns a failure into a readable message
+func validateTag(value interface{}, tag string) error {
+	if err := validate.ValidateVar(value, tag); err != nil {
+		return fmt.Errorf("failed %s validation", tag)
+	}
+	return nil
+}
+
+// checkInteger requires a whole number
+func checkInteger(value interface{}) error {
+	if n := value.(float64); n != math.Trunc(n) {
+		return fmt.Errorf("must be a whole number")
+	}
+	return nil
+}
+
+// checkSingleLine rejects line breaks, which would let a value spill into email headers
+func checkSingleLine(value interface{}) error {
+	if strings.ContainsAny(value.(string), "\r\n") {
+		return fmt.Errorf("must be a single line")
+	}
+	return nil
+}
+
+// checkTimezone requires a timezone the runtime can load
+func checkTimezone(value interface{}) error {
+	name := value.(string)
+	if name == "" || strings.EqualFold(name, "local") {
+		return fmt.Errorf("must be an IANA timezone")
+	}
+	if _, err := time.LoadLocation(name); err != nil {
+		return fmt.Errorf("unknown timezone %s", name)
+	}
+	return nil
+}
+
+// checkReminderDays requires a list of up to ten whole numbers of days, none
+// more than a year
+func checkReminderDays(value interface{}) error {
+	days, ok := value.([]interface{})
+	if !ok || len(days) > 10 {
+		return fmt.Errorf("must be a list of at most 10 day counts")
+	}
+	for _, day := range days {
+		n, ok := day.(float64)
+		if !ok || n != math.Trunc(n) || n < 0 || n > 365 {
+			return fmt.Errorf("invalid day count %v; must be a whole number from 0 to 365", day)
+		}
+	}
+	return nil
+}
+
+// checkRoles requires a non-empty list of known user roles
+func checkRoles(value interface{}) error {
+	roles, ok := value.([]interface{})
+	if !ok || len(roles) == 0 {
+		return fmt.Errorf("must be a non-empty list of roles")
+	}
+	for _, role := range roles {
+		s, ok := role.(string)
+		if !ok || validate.ValidateVar(s, "user_role") != nil {
+			return fmt.Errorf("unknown role %v", role)
+		}
+	}
+	return nil
+}
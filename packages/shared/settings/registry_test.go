@@ -0,0 +1,134 @@
+package settings
+
+import (
+	"testing"
+
+	testhelpers "github.com/comply360/shared/testing"
+)
+
+func mustLookup(t *testing.T, key string) Definition {
+	t.Helper()
+	def, ok := Lookup(key)
+	testhelpers.AssertTrue(t, ok, "Setting "+key+" should be registered")
+	return def
+}
+
+func TestDefinition_NormalizeChecksType(t *testing.T) {
+	_, err := mustLookup(t, KeySMTPPort).Normalize("587")
+	testhelpers.AssertError(t, err)
+
+	_, err = mustLookup(t, KeyMFARequired).Normalize("true")
+	testhelpers.AssertError(t, err)
+
+	_, err = mustLookup(t, KeySMTPHost).Normalize(25.0)
+	testhelpers.AssertError(t, err)
+
+	value, err := mustLookup(t, KeyMFARequired).Normalize(true)
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, true, value)
+}
+
+func TestDefinition_NormalizeValidates(t *testing.T) {
+	cases := []struct {
+		key   string
+		value interface{}
+		valid bool
+	}{
+		{KeySMTPPort, 587.0, true},
+		{KeySMTPPort, 70000.0, false},
+		{KeySMTPPort, 25.5, false},
+		{KeySMTPFrom, "noreply@example.co.za", true},
+		{KeySMTPFrom, "not-an-email", false},
+		{KeySMSProvider, "twilio", true},
+		{KeySMSProvider, "pigeon", false},
+		{KeyOdooURL, "https://odoo.example.com", true},
+		{KeyOdooURL, "odoo", false},
+		{KeyCurrency, "USD", true},
+		{KeyCurrency, "XXX", false},
+		{KeyTimezone, "Africa/Harare", true},
+		{KeyTimezone, "Mars/Olympus", false},
+		{KeyDefaultUserRole, "agent", true},
+		{KeyDefaultUserRole, "superuser", false},
+		{KeyEnabledRoles, []interface{}{"tenant_admin", "client"}, true},
+		{KeyEnabledRoles, []interface{}{}, false},
+		{KeyEnabledRoles, []interface{}{"client", "superuser"}, false},
+		{KeyBrandingPrimaryColor, "#0F4C81", true},
+		{KeyBrandingPrimaryColor, "blue", false},
+		{KeyBrandingSenderName, "Acme Agents", true},
+		{KeyBrandingSenderName, "Acme\r\nBcc: victim@example.com", false},
+		{KeyComplianceReminderDays, []interface{}{30.0, 7.0, 0.0}, true},
+		{KeyComplianceReminderDays, []interface{}{}, true},
+		{KeyComplianceReminderDays, []interface{}{7.5}, false},
+		{KeyComplianceReminderDays, []interface{}{400.0}, false},
+		{KeyComplianceReminderDays, []interface{}{"week"}, false},
+	}
+
+	for _, tc := range cases {
+		_, err := mustLookup(t, tc.key).Normalize(tc.value)
+		if tc.valid && err != nil {
+			t.Errorf("%s=%v should be valid: %v", tc.key, tc.value, err)
+		}
+		if !tc.valid && err == nil {
+			t.Errorf("%s=%v should be rejected", tc.key, tc.value)
+		}
+	}
+}
+
+func TestDefinition_EncodeDecodeRoundTrip(t *testing.T) {
+	for _, tc := range []struct {
+		key   string
+		value interface{}
+		text  string
+	}{
+		{KeySMTPHost, "smtp.example.com", "smtp.example.com"},
+		{KeyMaxUsers, 25.0, "25"},
+		{KeyMFARequired, true, "true"},
+		{KeyEnabledRoles, []interface{}{"client"}, `["client"]`},
+	} {
+		def := mustLookup(t, tc.key)
+
+		text, err := def.Encode(tc.value)
+		testhelpers.AssertNoError(t, err)
+		testhelpers.AssertEqual(t, tc.text, text)
+
+		decoded, err := def.Decode(text)
+		testhelpers.AssertNoError(t, err)
+		reencoded, err := def.Encode(decoded)
+		testhelpers.AssertNoError(t, err)
+		testhelpers.AssertEqual(t, tc.text, reencoded)
+	}
+}
+
+func TestRegistry_SecretsAreSensitive(t *testing.T) {
+	for _, key := range []string{KeySMTPPassword, KeySMSAPIKey, KeySMSAPISecret, KeyOdooPassword} {
+		testhelpers.AssertTrue(t, mustLookup(t, key).Sensitive, key+" should be sensitive")
+	}
+	testhelpers.AssertFalse(t, mustLookup(t, KeySMTPHost).Sensitive, "smtp_host should not be sensitive")
+}
+
+func TestInherit_ChildOverridesParent(t *testing.T) {
+	parent := map[string]interface{}{
+		KeyBrandingDisplayName:  "Master Agents",
+		KeyBrandingPrimaryColor: "#112233",
+		KeyTimezone:             "Africa/Harare",
+		KeySMTPHost:             "smtp.master.example.com",
+		KeySMTPPassword:         "secret",
+		"legacy_key":            "kept by the parent only",
+	}
+	own := map[string]interface{}{
+		KeyBrandingPrimaryColor: "#445566",
+	}
+
+	values := Inherit(own, parent)
+
+	testhelpers.AssertEqual(t, "Master Agents", values[KeyBrandingDisplayName], "Unset settings should be inherited")
+	testhelpers.AssertEqual(t, "#445566", values[KeyBrandingPrimaryColor], "The child's own value should win")
+	testhelpers.AssertEqual(t, "Africa/Harare", values[KeyTimezone])
+
+	_, inherited := values[KeySMTPHost]
+	testhelpers.AssertFalse(t, inherited, "Local settings must not be inherited")
+	_, inherited = values[KeySMTPPassword]
+	testhelpers.AssertFalse(t, inherited, "Parent credentials must not be inherited")
+	_, inherited = values["legacy_key"]
+	testhelpers.AssertFalse(t, inherited, "Unknown keys must not be inherited")
+}
@@ -0,0 +1,156 @@
+// Package sqlscript runs SQL scripts outside a transaction block, statement by
+// statement, and scopes a dedicated connection to a tenant schema while they
+// run. The migrator and the provisioning service share it.
+package sqlscript
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// WithSearchPath runs fn with the connection's search_path set to the schema,
+// then resets it. If the reset fails the connection is discarded rather than
+// handed back to the pool with a tenant search_path.
+func WithSearchPath(ctx context.Context, conn *sql.Conn, schemaName string, fn func() error) error {
+	if _, err := conn.ExecContext(ctx, fmt.Sprintf(`SET search_path TO %s, public`, pq.QuoteIdentifier(schemaName))); err != nil {
+		return fmt.Errorf("failed to set search path: %w", err)
+	}
+	defer func() {
+		if _, err := conn.ExecContext(ctx, `RESET search_path`); err != nil {
+			conn.Raw(func(interface{}) error { return driver.ErrBadConn })
+		}
+	}()
+
+	return fn()
+}
+
+// Execer is satisfied by *sql.DB, *sql.Conn and *sql.Tx
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// Exec runs a script one statement at a time. Postgres wraps a
+// multi-statement query in an implicit transaction, which non-transactional
+// migrations must avoid.
+func Exec(ctx context.Context, db Execer, script string) error {
+	for _, statement := range Split(script) {
+		if _, err := db.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("failed to execute statement %q: %w", firstLine(statement), err)
+		}
+	}
+	return nil
+}
+
+// Split splits a SQL script on semicolons, ignoring those inside
+// quotes, dollar-quoted bodies and comments
+func Split(script string) []string {
+	var statements []string
+	var current strings.Builder
+
+	inSingle, inDouble, inLineComment, inBlockComment := false, false, false, false
+	dollarTag := ""
+
+	for i := 0; i < len(script); i++ {
+		c := script[i]
+		next := byte(0)
+		if i+1 < len(script) {
+			next = script[i+1]
+		}
+
+		switch {
+		case inLineComment:
+			if c == '\n' {
+				inLineComment = false
+			}
+		case inBlockComment:
+			if c == '*' && next == '/' {
+				inBlockComment = false
+				current.WriteByte(c)
+				i++
+				c = next
+			}
+		case dollarTag != "":
+			if strings.HasPrefix(script[i:], dollarTag) {
+				current.WriteString(dollarTag)
+				i += len(dollarTag) - 1
+				dollarTag = ""
+				continue
+			}
+		case inSingle:
+			if c == '\'' {
+				inSingle = false
+			}
+		case inDouble:
+			if c == '"' {
+				inDouble = false
+			}
+		case c == '-' && next == '-':
+			inLineComment = true
+		case c == '/' && next == '*':
+			inBlockComment = true
+		case c == '\'':
+			inSingle = true
+		case c == '"':
+			inDouble = true
+		case c == '$':
+			if end := strings.IndexByte(script[i+1:], '$'); end >= 0 && isDollarTag(script[i+1:i+1+end]) {
+				dollarTag = script[i : i+end+2]
+				current.WriteString(dollarTag)
+				i += end + 1
+				continue
+			}
+		case c == ';':
+			if statement := strings.TrimSpace(current.String()); !isOnlyComments(statement) {
+				statements = append(statements, statement)
+			}
+			current.Reset()
+			continue
+		}
+
+		current.WriteByte(c)
+	}
+
+	if statement := strings.TrimSpace(current.String()); !isOnlyComments(statement) {
+		statements = append(statements, statement)
+	}
+
+	return statements
+}
+
+// isDollarTag reports whether s is a valid dollar-quote tag body ("" or an identifier)
+func isDollarTag(s string) bool {
+	for i, r := range s {
+		if r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (i > 0 && r >= '0' && r <= '9') {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+// isOnlyComments reports whether a statement has no SQL besides comments and whitespace
+func isOnlyComments(statement string) bool {
+	for _, line := range strings.Split(statement, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "--") {
+			return false
+		}
+	}
+	return true
+}
+
+// firstLine returns the first non-comment line of a statement, for error messages
+func firstLine(statement string) string {
+	for _, line := range strings.Split(statement, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "--") {
+			return line
+		}
+	}
+	return statement
+}
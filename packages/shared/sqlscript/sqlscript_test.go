@@ -0,0 +1,32 @@
+package sqlscript
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	script := `-- Migration: 006_index
+-- Transaction: none
+
+CREATE INDEX CONCURRENTLY idx_a ON a(id); -- trailing; comment
+INSERT INTO a (name) VALUES ('x;y');
+/* block; comment */
+CREATE FUNCTION f() RETURNS void AS $$
+BEGIN
+    PERFORM 1;
+END;
+$$ LANGUAGE plpgsql;
+`
+
+	statements := Split(script)
+	if len(statements) != 3 {
+		t.Fatalf("expected 3 statements, got %d: %q", len(statements), statements)
+	}
+	if firstLine(statements[0]) != "CREATE INDEX CONCURRENTLY idx_a ON a(id)" {
+		t.Errorf("unexpected first statement: %q", statements[0])
+	}
+	if !strings.Contains(statements[2], "PERFORM 1;\nEND;\n$$ LANGUAGE plpgsql") {
+		t.Errorf("function body should be kept whole: %q", statements[2])
+	}
+}
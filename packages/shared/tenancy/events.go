@@ -0,0 +1,130 @@
+package tenancy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// EventsExchange is the topic exchange tenant-service publishes tenant changes to
+const EventsExchange = "comply360.tenants"
+
+// Tenant event routing keys
+const (
+	EventTenantUpdated           = "tenant.updated"
+	EventTenantDeleted           = "tenant.deleted"
+	EventTenantDomainChanged     = "tenant.domain.changed"
+	EventTenantSuspended         = "tenant.suspended"
+	EventTenantReactivated       = "tenant.reactivated"
+	EventTenantDeletionScheduled = "tenant.deletion.scheduled"
+	EventTenantSettingsChanged   = "tenant.settings.changed"
+	EventTenantHierarchyChanged  = "tenant.hierarchy.changed"
+)
+
+// Event is the payload of a tenant event
+type Event struct {
+	TenantID   uuid.UUID `json:"tenant_id"`
+	Type       string    `json:"type"`
+	OccurredAt time.Time `json:"occurred_at"`
+	// Keys lists the changed setting keys of a settings event
+	Keys []string `json:"keys,omitempty"`
+	// Domain is the custom domain of a domain event
+	Domain string `json:"domain,omitempty"`
+}
+
+// DeclareExchange declares the tenant events exchange
+func DeclareExchange(ch *amqp.Channel) error {
+	return ch.ExchangeDeclare(
+		EventsExchange, // name
+		"topic",        // type
+		true,           // durable
+		false,          // auto-deleted
+		false,          // internal
+		false,          // no-wait
+		nil,            // arguments
+	)
+}
+
+// ListenForInvalidation drops cached tenants whenever tenant-service reports a change.
+// Each listener binds its own exclusive queue, so every process clears its local cache.
+func (r *Resolver) ListenForInvalidation(conn *amqp.Connection) error {
+	return Subscribe(conn, "tenant.#", func(event Event) {
+		// Settings live in the tenant schema and are not part of the cached tenant
+		if event.Type == EventTenantSettingsChanged {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := r.Invalidate(ctx, event.TenantID); err != nil {
+			log.Printf("Warning: Failed to invalidate tenant %s: %v", event.TenantID, err)
+		}
+		if event.Domain != "" {
+			if err := r.ForgetDomain(ctx, event.Domain); err != nil {
+				log.Printf("Warning: Failed to forget missing domain %s: %v", event.Domain, err)
+			}
+		}
+	})
+}
+
+// Subscribe delivers tenant events matching a routing key pattern to fn. The
+// subscription uses an exclusive server-named queue, so every process receives
+// its own copy of each event.
+func Subscribe(conn *amqp.Connection, bindingKey string, fn func(Event)) error {
+	ch, err := conn.Channel()
+	if err != nil {
+		return fmt.Errorf("failed to open channel: %w", err)
+	}
+
+	if err := DeclareExchange(ch); err != nil {
+		return fmt.Errorf("failed to declare exchange: %w", err)
+	}
+
+	queue, err := ch.QueueDeclare(
+		"",    // name (server generated)
+		false, // durable
+		true,  // delete when unused
+		true,  // exclusive
+		false, // no-wait
+		nil,   // arguments
+	)
+	if err != nil {
+		return fmt.Errorf("failed to declare queue: %w", err)
+	}
+
+	if err := ch.QueueBind(queue.Name, bindingKey, EventsExchange, false, nil); err != nil {
+		return fmt.Errorf("failed to bind queue: %w", err)
+	}
+
+	msgs, err := ch.Consume(
+		queue.Name, // queue
+		"",         // consumer
+		true,       // auto-ack
+		true,       // exclusive
+		false,      // no-local
+		false,      // no-wait
+		nil,        // args
+	)
+	if err != nil {
+		return fmt.Errorf("failed to consume tenant events: %w", err)
+	}
+
+	go func() {
+		for msg := range msgs {
+			var event Event
+			if err := json.Unmarshal(msg.Body, &event); err != nil {
+				log.Printf("Warning: Failed to decode tenant event: %v", err)
+				continue
+			}
+			fn(event)
+		}
+	}()
+
+	return nil
+}
@@ -0,0 +1,5 @@
+package tenancy
+
+// MaxHierarchyDepth bounds how far parent chains are followed. It matches the
+// recursion limit of public.tenant_ancestors and public.tenant_descendants.
+const MaxHierarchyDepth = 32
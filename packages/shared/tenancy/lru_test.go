@@ -0,0 +1,57 @@
+package tenancy
+
+import (
+	"testing"
+	"time"
+
+	testhelpers "github.com/comply360/shared/testing"
+)
+
+func TestLRU_EvictsLeastRecentlyUsed(t *testing.T) {
+	cache := newLRU[string, int](2, time.Minute)
+
+	cache.Add("a", 1)
+	cache.Add("b", 2)
+	_, _ = cache.Get("a")
+	cache.Add("c", 3)
+
+	_, ok := cache.Get("b")
+	testhelpers.AssertFalse(t, ok, "Least recently used entry should be evicted")
+
+	value, ok := cache.Get("a")
+	testhelpers.AssertTrue(t, ok, "Recently used entry should be kept")
+	testhelpers.AssertEqual(t, 1, value)
+	testhelpers.AssertEqual(t, 2, cache.Len())
+}
+
+func TestLRU_ExpiresEntries(t *testing.T) {
+	now := time.Now()
+	cache := newLRU[string, int](10, time.Second)
+	cache.now = func() time.Time { return now }
+
+	cache.Add("a", 1)
+	now = now.Add(2 * time.Second)
+
+	_, ok := cache.Get("a")
+	testhelpers.AssertFalse(t, ok, "Expired entry should not be returned")
+	testhelpers.AssertEqual(t, 0, cache.Len())
+}
+
+func TestLRU_Remove(t *testing.T) {
+	cache := newLRU[string, int](10, time.Minute)
+
+	cache.Add("a", 1)
+	cache.Remove("a")
+
+	_, ok := cache.Get("a")
+	testhelpers.AssertFalse(t, ok, "Removed entry should not be returned")
+}
+
+func TestLRU_ZeroSizeDisablesCache(t *testing.T) {
+	cache := newLRU[string, int](0, time.Minute)
+
+	cache.Add("a", 1)
+
+	_, ok := cache.Get("a")
+	testhelpers.AssertFalse(t, ok, "Zero-size cache should not store entries")
+}
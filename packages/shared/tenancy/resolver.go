@@ -0,0 +1,373 @@
+package tenancy
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/comply360/shared/models"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis key prefixes for cached tenants, their subdomain/domain aliases and
+// custom domains known not to belong to any tenant
+const (
+	redisTenantPrefix  = "tenancy:tenant:"
+	redisAliasPrefix   = "tenancy:alias:"
+	redisMissingPrefix = "tenancy:missing:"
+)
+
+// ResolverOptions configures the caches in front of the tenants table
+type ResolverOptions struct {
+	// LocalSize is the number of tenants kept in process; 0 disables the local cache
+	LocalSize int
+	LocalTTL  time.Duration
+	// RedisTTL bounds how long a tenant stays in Redis without an invalidation event
+	RedisTTL time.Duration
+	// MissTTL is how long a host that is no tenant's custom domain is remembered,
+	// locally and in Redis; 0 disables it. Verifying a domain forgets it at once.
+	MissTTL time.Duration
+}
+
+// DefaultResolverOptions returns the cache settings used by the API gateway
+func DefaultResolverOptions() ResolverOptions {
+	return ResolverOptions{
+		LocalSize: 1024,
+		LocalTTL:  30 * time.Second,
+		RedisTTL:  5 * time.Minute,
+		MissTTL:   30 * time.Second,
+	}
+}
+
+// Resolver looks tenants up by ID, subdomain or verified custom domain through an
+// in-process LRU, then Redis, then Postgres. Subdomain and domain lookups are cached
+// as aliases of the tenant ID and re-checked against the tenant on every hit, so
+// invalidating the ID is enough when either changes. Hosts that are no tenant's
+// custom domain, which is most of them, are remembered for a short while so
+// subdomain and header lookups do not query for a domain first.
+type Resolver struct {
+	db       *sql.DB
+	redis    *redis.Client
+	redisTTL time.Duration
+	missTTL  time.Duration
+	tenants  *lru[uuid.UUID, models.Tenant]
+	aliases  *lru[string, uuid.UUID]
+	misses   *lru[string, struct{}]
+}
+
+// NewResolver creates a tenant resolver. redisClient may be nil.
+func NewResolver(db *sql.DB, redisClient *redis.Client, opts ResolverOptions) *Resolver {
+	missSize := opts.LocalSize
+	if opts.MissTTL <= 0 {
+		missSize = 0
+	}
+
+	return &Resolver{
+		db:       db,
+		redis:    redisClient,
+		redisTTL: opts.RedisTTL,
+		missTTL:  opts.MissTTL,
+		tenants:  newLRU[uuid.UUID, models.Tenant](opts.LocalSize, opts.LocalTTL),
+		aliases:  newLRU[string, uuid.UUID](opts.LocalSize, opts.LocalTTL),
+		misses:   newLRU[string, struct{}](missSize, opts.MissTTL),
+	}
+}
+
+// ByID resolves a tenant by ID
+func (r *Resolver) ByID(ctx context.Context, id uuid.UUID) (*models.Tenant, error) {
+	if tenant, ok := r.tenants.Get(id); ok {
+		return &tenant, nil
+	}
+
+	if tenant, ok := r.redisTenant(ctx, id); ok {
+		r.tenants.Add(id, *tenant)
+		return tenant, nil
+	}
+
+	tenant, err := queryTenant(ctx, r.db, `id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+
+	r.storeTenant(ctx, tenant)
+	return tenant, nil
+}
+
+// BySubdomain resolves a tenant by subdomain
+func (r *Resolver) BySubdomain(ctx context.Context, subdomain string) (*models.Tenant, error) {
+	matches := func(t *models.Tenant) bool {
+		return t.Subdomain == subdomain
+	}
+
+	return r.byAlias(ctx, "subdomain:"+subdomain, matches, `subdomain = $1`, subdomain)
+}
+
+// ByDomain resolves a tenant by verified custom domain
+func (r *Resolver) ByDomain(ctx context.Context, domain string) (*models.Tenant, error) {
+	domain = strings.ToLower(domain)
+	alias := "domain:" + domain
+	if r.isMissing(ctx, alias) {
+		return nil, fmt.Errorf("tenant not found")
+	}
+
+	matches := func(t *models.Tenant) bool {
+		return t.HasVerifiedDomain() && strings.EqualFold(*t.Domain, domain)
+	}
+
+	tenant, err := r.byAlias(ctx, alias, matches, `LOWER(domain) = $1 AND domain_status = 'verified'`, domain)
+	if err != nil && err.Error() == "tenant not found" {
+		r.storeMissing(ctx, alias)
+	}
+	return tenant, err
+}
+
+// IsAncestor reports whether ancestorID is a parent, grandparent, etc. of tenantID.
+// The chain is walked through the cache, so repeated checks cost no queries.
+func (r *Resolver) IsAncestor(ctx context.Context, ancestorID, tenantID uuid.UUID) (bool, error) {
+	tenant, err := r.ByID(ctx, tenantID)
+	if err != nil {
+		return false, err
+	}
+
+	for depth := 0; tenant.ParentTenantID != nil && depth < MaxHierarchyDepth; depth++ {
+		if *tenant.ParentTenantID == ancestorID {
+			return true, nil
+		}
+		if tenant, err = r.ByID(ctx, *tenant.ParentTenantID); err != nil {
+			// A deleted ancestor ends the chain
+			return false, nil
+		}
+	}
+
+	return false, nil
+}
+
+// Invalidate drops a tenant from the local cache and Redis
+func (r *Resolver) Invalidate(ctx context.Context, id uuid.UUID) error {
+	r.tenants.Remove(id)
+
+	if r.redis == nil {
+		return nil
+	}
+	return r.redis.Del(ctx, redisTenantPrefix+id.String()).Err()
+}
+
+// ForgetDomain drops a remembered miss for a custom domain, so the next lookup
+// sees it as soon as it is verified
+func (r *Resolver) ForgetDomain(ctx context.Context, domain string) error {
+	alias := "domain:" + strings.ToLower(domain)
+	r.misses.Remove(alias)
+
+	if r.redis == nil {
+		return nil
+	}
+	return r.redis.Del(ctx, redisMissingPrefix+alias).Err()
+}
+
+// byAlias resolves a tenant through a cached alias, falling back to the database
+func (r *Resolver) byAlias(ctx context.Context, alias string, matches func(*models.Tenant) bool, where string, arg interface{}) (*models.Tenant, error) {
+	if id, ok := r.lookupAlias(ctx, alias); ok {
+		if tenant, err := r.ByID(ctx, id); err == nil && matches(tenant) {
+			return tenant, nil
+		}
+		r.dropAlias(ctx, alias)
+	}
+
+	tenant, err := queryTenant(ctx, r.db, where, arg)
+	if err != nil {
+		return nil, err
+	}
+
+	r.storeTenant(ctx, tenant)
+	r.storeAlias(ctx, alias, tenant.ID)
+	return tenant, nil
+}
+
+// redisTenant reads a tenant from Redis. Cache errors are logged and treated as misses.
+func (r *Resolver) redisTenant(ctx context.Context, id uuid.UUID) (*models.Tenant, bool) {
+	if r.redis == nil {
+		return nil, false
+	}
+
+	data, err := r.redis.Get(ctx, redisTenantPrefix+id.String()).Bytes()
+	if err != nil {
+		if err != redis.Nil {
+			log.Printf("Warning: Failed to read tenant %s from cache: %v", id, err)
+		}
+		return nil, false
+	}
+
+	var tenant models.Tenant
+	if err := json.Unmarshal(data, &tenant); err != nil {
+		log.Printf("Warning: Failed to decode cached tenant %s: %v", id, err)
+		return nil, false
+	}
+
+	return &tenant, true
+}
+
+// storeTenant caches a tenant locally and in Redis
+func (r *Resolver) storeTenant(ctx context.Context, tenant *models.Tenant) {
+	r.tenants.Add(tenant.ID, *tenant)
+
+	if r.redis == nil {
+		return
+	}
+
+	data, err := json.Marshal(tenant)
+	if err != nil {
+		log.Printf("Warning: Failed to encode tenant %s for cache: %v", tenant.ID, err)
+		return
+	}
+	if err := r.redis.Set(ctx, redisTenantPrefix+tenant.ID.String(), data, r.redisTTL).Err(); err != nil {
+		log.Printf("Warning: Failed to cache tenant %s: %v", tenant.ID, err)
+	}
+}
+
+// lookupAlias finds the tenant ID cached for an alias
+func (r *Resolver) lookupAlias(ctx context.Context, alias string) (uuid.UUID, bool) {
+	if id, ok := r.aliases.Get(alias); ok {
+		return id, true
+	}
+
+	if r.redis == nil {
+		return uuid.Nil, false
+	}
+
+	value, err := r.redis.Get(ctx, redisAliasPrefix+alias).Result()
+	if err != nil {
+		if err != redis.Nil {
+			log.Printf("Warning: Failed to read tenant alias %s from cache: %v", alias, err)
+		}
+		return uuid.Nil, false
+	}
+
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, false
+	}
+
+	r.aliases.Add(alias, id)
+	return id, true
+}
+
+// storeAlias caches the tenant ID for an alias locally and in Redis
+func (r *Resolver) storeAlias(ctx context.Context, alias string, id uuid.UUID) {
+	r.aliases.Add(alias, id)
+
+	if r.redis == nil {
+		return
+	}
+	if err := r.redis.Set(ctx, redisAliasPrefix+alias, id.String(), r.redisTTL).Err(); err != nil {
+		log.Printf("Warning: Failed to cache tenant alias %s: %v", alias, err)
+	}
+}
+
+// dropAlias removes a stale alias
+func (r *Resolver) dropAlias(ctx context.Context, alias string) {
+	r.aliases.Remove(alias)
+
+	if r.redis == nil {
+		return
+	}
+	if err := r.redis.Del(ctx, redisAliasPrefix+alias).Err(); err != nil {
+		log.Printf("Warning: Failed to drop tenant alias %s: %v", alias, err)
+	}
+}
+
+// isMissing reports whether an alias was recently found to match no tenant
+func (r *Resolver) isMissing(ctx context.Context, alias string) bool {
+	if r.missTTL <= 0 {
+		return false
+	}
+	if _, ok := r.misses.Get(alias); ok {
+		return true
+	}
+
+	if r.redis == nil {
+		return false
+	}
+
+	n, err := r.redis.Exists(ctx, redisMissingPrefix+alias).Result()
+	if err != nil {
+		log.Printf("Warning: Failed to read tenant alias %s from cache: %v", alias, err)
+		return false
+	}
+	if n == 0 {
+		return false
+	}
+
+	r.misses.Add(alias, struct{}{})
+	return true
+}
+
+// storeMissing remembers that an alias matches no tenant
+func (r *Resolver) storeMissing(ctx context.Context, alias string) {
+	if r.missTTL <= 0 {
+		return
+	}
+	r.misses.Add(alias, struct{}{})
+
+	if r.redis == nil {
+		return
+	}
+	if err := r.redis.Set(ctx, redisMissingPrefix+alias, "1", r.missTTL).Err(); err != nil {
+		log.Printf("Warning: Failed to cache missing tenant alias %s: %v", alias, err)
+	}
+}
+
+// tenantColumns is the column list scanned by queryTenant
+const tenantColumns = `
+	id, name, subdomain, domain, domain_status, domain_verified_at, domain_last_checked_at,
+	status, suspended_at, suspension_reason, purge_after, parent_tenant_id, parent_override_rate,
+	subscription_tier, company_name, contact_email, contact_phone, country,
+	max_users, created_at, updated_at, deleted_at
+`
+
+// queryTenant fetches a live tenant matching a WHERE condition
+func queryTenant(ctx context.Context, db *sql.DB, where string, arg interface{}) (*models.Tenant, error) {
+	query := `SELECT ` + tenantColumns + `
+		FROM public.tenants
+		WHERE ` + where + ` AND deleted_at IS NULL
+	`
+
+	var tenant models.Tenant
+	err := db.QueryRowContext(ctx, query, arg).Scan(
+		&tenant.ID,
+		&tenant.Name,
+		&tenant.Subdomain,
+		&tenant.Domain,
+		&tenant.DomainStatus,
+		&tenant.DomainVerifiedAt,
+		&tenant.DomainCheckedAt,
+		&tenant.Status,
+		&tenant.SuspendedAt,
+		&tenant.SuspensionReason,
+		&tenant.PurgeAfter,
+		&tenant.ParentTenantID,
+		&tenant.ParentOverrideRate,
+		&tenant.SubscriptionTier,
+		&tenant.CompanyName,
+		&tenant.ContactEmail,
+		&tenant.ContactPhone,
+		&tenant.Country,
+		&tenant.MaxUsers,
+		&tenant.CreatedAt,
+		&tenant.UpdatedAt,
+		&tenant.DeletedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("tenant not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &tenant, nil
+}
@@ -0,0 +1,73 @@
+package tenancy
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Context identifies who a database transaction acts for. It is applied with
+// set_tenant_context, whose settings are transaction-local (SET LOCAL), so the
+// RLS variables can never leak onto another request's pooled connection.
+type Context struct {
+	TenantID      uuid.UUID
+	UserID        *uuid.UUID
+	Roles         []string
+	IsGlobalAdmin bool
+	// ActingTenantID is the user's own tenant when it acts on a descendant tenant.
+	// set_tenant_context rejects it unless it is an ancestor of TenantID or the
+	// user is a global admin; RLS itself still isolates on TenantID.
+	ActingTenantID *uuid.UUID
+}
+
+// WithTenant runs fn in a transaction scoped to a tenant's RLS context
+func WithTenant(db *sql.DB, tenantID uuid.UUID, fn func(tx *sql.Tx) error) error {
+	return Run(context.Background(), db, Context{TenantID: tenantID}, fn)
+}
+
+// Run runs fn in a transaction with the RLS context applied. The transaction is
+// committed when fn returns nil and rolled back otherwise.
+func Run(ctx context.Context, db *sql.DB, tc Context, fn func(tx *sql.Tx) error) error {
+	if tc.TenantID == uuid.Nil {
+		return fmt.Errorf("tenant context required")
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := SetLocal(ctx, tx, tc); err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// SetLocal applies the RLS context to an open transaction
+func SetLocal(ctx context.Context, tx *sql.Tx, tc Context) error {
+	var roles *string
+	if len(tc.Roles) > 0 {
+		joined := strings.Join(tc.Roles, ",")
+		roles = &joined
+	}
+
+	query := `SELECT public.set_tenant_context($1, $2, $3, $4, $5)`
+	if _, err := tx.ExecContext(ctx, query, tc.TenantID, tc.UserID, roles, tc.IsGlobalAdmin, tc.ActingTenantID); err != nil {
+		return fmt.Errorf("failed to set tenant context: %w", err)
+	}
+
+	return nil
+}
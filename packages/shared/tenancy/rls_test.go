@@ -0,0 +1,180 @@
+package tenancy
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+
+	testhelpers "github.com/comply360/shared/testing"
+	"github.com/google/uuid"
+)
+
+// setupRLSTable creates a table with the same tenant isolation policy as the tenant
+// schemas. FORCE makes the policy apply to the table owner the tests connect as.
+func setupRLSTable(t *testing.T, tdb *testhelpers.TestDB) string {
+	var exists bool
+	err := tdb.DB.QueryRow(`SELECT to_regprocedure('public.set_tenant_context(uuid, uuid, text, boolean, uuid)') IS NOT NULL`).Scan(&exists)
+	testhelpers.AssertNoError(t, err)
+	if !exists {
+		t.Skip("public.set_tenant_context not installed; run the global migrations first")
+	}
+
+	table := tdb.Schema + ".rls_records"
+	statements := []string{
+		fmt.Sprintf(`CREATE TABLE %s (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			tenant_id UUID NOT NULL,
+			name VARCHAR(255) NOT NULL
+		)`, table),
+		fmt.Sprintf(`ALTER TABLE %s ENABLE ROW LEVEL SECURITY`, table),
+		fmt.Sprintf(`ALTER TABLE %s FORCE ROW LEVEL SECURITY`, table),
+		fmt.Sprintf(`CREATE POLICY tenant_isolation ON %s
+			FOR ALL
+			USING (tenant_id = current_setting('app.current_tenant_id', true)::UUID)
+			WITH CHECK (tenant_id = current_setting('app.current_tenant_id', true)::UUID)`, table),
+	}
+	for _, statement := range statements {
+		_, err := tdb.DB.Exec(statement)
+		testhelpers.AssertNoError(t, err)
+	}
+
+	return table
+}
+
+func insertRecord(t *testing.T, db *sql.DB, table string, tenantID uuid.UUID, name string) uuid.UUID {
+	var id uuid.UUID
+	err := WithTenant(db, tenantID, func(tx *sql.Tx) error {
+		return tx.QueryRow(fmt.Sprintf(`INSERT INTO %s (tenant_id, name) VALUES ($1, $2) RETURNING id`, table), tenantID, name).Scan(&id)
+	})
+	testhelpers.AssertNoError(t, err, "Tenant should be able to insert its own rows")
+	return id
+}
+
+func listRecords(t *testing.T, db *sql.DB, table string, tenantID uuid.UUID) []string {
+	var names []string
+	err := WithTenant(db, tenantID, func(tx *sql.Tx) error {
+		rows, err := tx.Query(fmt.Sprintf(`SELECT name FROM %s ORDER BY name`, table))
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var name string
+			if err := rows.Scan(&name); err != nil {
+				return err
+			}
+			names = append(names, name)
+		}
+		return rows.Err()
+	})
+	testhelpers.AssertNoError(t, err)
+	return names
+}
+
+func TestWithTenant_TenantCannotReadOtherTenantsRows(t *testing.T) {
+	tdb := testhelpers.SetupTestDB(t)
+	defer tdb.Cleanup(t)
+	table := setupRLSTable(t, tdb)
+
+	tenantA, tenantB := uuid.New(), uuid.New()
+	insertRecord(t, tdb.DB, table, tenantA, "a-record")
+	recordB := insertRecord(t, tdb.DB, table, tenantB, "b-record")
+
+	testhelpers.AssertEqual(t, []string{"a-record"}, listRecords(t, tdb.DB, table, tenantA))
+	testhelpers.AssertEqual(t, []string{"b-record"}, listRecords(t, tdb.DB, table, tenantB))
+
+	// Tenant A cannot fetch tenant B's row even by ID
+	err := WithTenant(tdb.DB, tenantA, func(tx *sql.Tx) error {
+		var name string
+		return tx.QueryRow(fmt.Sprintf(`SELECT name FROM %s WHERE id = $1`, table), recordB).Scan(&name)
+	})
+	testhelpers.AssertEqual(t, sql.ErrNoRows, err, "Tenant A must not see tenant B's row")
+
+	// Tenant A cannot update tenant B's row
+	err = WithTenant(tdb.DB, tenantA, func(tx *sql.Tx) error {
+		result, err := tx.Exec(fmt.Sprintf(`UPDATE %s SET name = 'hijacked' WHERE id = $1`, table), recordB)
+		if err != nil {
+			return err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		testhelpers.AssertEqual(t, int64(0), affected, "Tenant A must not update tenant B's row")
+		return nil
+	})
+	testhelpers.AssertNoError(t, err)
+
+	// Tenant A cannot write rows on behalf of tenant B
+	err = WithTenant(tdb.DB, tenantA, func(tx *sql.Tx) error {
+		_, err := tx.Exec(fmt.Sprintf(`INSERT INTO %s (tenant_id, name) VALUES ($1, 'forged')`, table), tenantB)
+		return err
+	})
+	testhelpers.AssertError(t, err, "Tenant A must not insert rows for tenant B")
+
+	testhelpers.AssertEqual(t, []string{"b-record"}, listRecords(t, tdb.DB, table, tenantB))
+}
+
+func TestWithTenant_ContextDoesNotLeakToPool(t *testing.T) {
+	tdb := testhelpers.SetupTestDB(t)
+	defer tdb.Cleanup(t)
+	table := setupRLSTable(t, tdb)
+
+	tenantA := uuid.New()
+	insertRecord(t, tdb.DB, table, tenantA, "a-record")
+
+	// Reuse a single connection so a leaked session setting would be visible
+	tdb.DB.SetMaxOpenConns(1)
+	listRecords(t, tdb.DB, table, tenantA)
+
+	// Once a session has seen the setting, current_setting returns '' rather than NULL,
+	// which the policy's UUID cast rejects; either way no rows may be returned
+	var count int
+	err := tdb.DB.QueryRow(fmt.Sprintf(`SELECT COUNT(*) FROM %s`, table)).Scan(&count)
+	if err == nil {
+		testhelpers.AssertEqual(t, 0, count, "Queries outside WithTenant must not inherit a tenant context")
+	}
+}
+
+func TestRun_ActingTenantMustBeAncestor(t *testing.T) {
+	tdb := testhelpers.SetupTestDB(t)
+	defer tdb.Cleanup(t)
+	table := setupRLSTable(t, tdb)
+
+	tenantA, outsider := uuid.New(), uuid.New()
+	insertRecord(t, tdb.DB, table, tenantA, "a-record")
+
+	// A tenant that is not an ancestor cannot act on tenant A
+	err := Run(context.Background(), tdb.DB, Context{TenantID: tenantA, ActingTenantID: &outsider}, func(tx *sql.Tx) error {
+		return nil
+	})
+	testhelpers.AssertError(t, err, "An unrelated acting tenant must be rejected")
+
+	// A global admin may act on any tenant, and still only sees that tenant's rows
+	var names []string
+	tc := Context{TenantID: tenantA, ActingTenantID: &outsider, IsGlobalAdmin: true}
+	err = Run(context.Background(), tdb.DB, tc, func(tx *sql.Tx) error {
+		rows, err := tx.Query(fmt.Sprintf(`SELECT name FROM %s`, table))
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var name string
+			if err := rows.Scan(&name); err != nil {
+				return err
+			}
+			names = append(names, name)
+		}
+		return rows.Err()
+	})
+	testhelpers.AssertNoError(t, err)
+	testhelpers.AssertEqual(t, 1, len(names))
+}
+
+func TestWithTenant_RequiresTenant(t *testing.T) {
+	err := WithTenant(nil, uuid.Nil, func(tx *sql.Tx) error { return nil })
+	testhelpers.AssertError(t, err, "A nil tenant ID should be rejected")
+}
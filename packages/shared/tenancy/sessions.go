@@ -0,0 +1,46 @@
+package tenancy
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// RefreshTokenKey is the Redis key mapping a refresh token to its user ID
+func RefreshTokenKey(token string) string {
+	return "refresh_token:" + token
+}
+
+// TenantSessionsKey is the Redis set of refresh tokens issued to a tenant's users,
+// kept so the tenant's sessions can be revoked together
+func TenantSessionsKey(tenantID uuid.UUID) string {
+	return "tenant_sessions:" + tenantID.String()
+}
+
+// RevokeTenantSessions deletes every refresh token issued to a tenant's users
+// and returns how many were still live
+func RevokeTenantSessions(ctx context.Context, rdb *redis.Client, tenantID uuid.UUID) (int, error) {
+	setKey := TenantSessionsKey(tenantID)
+
+	tokens, err := rdb.SMembers(ctx, setKey).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list tenant sessions: %w", err)
+	}
+
+	revoked := 0
+	for _, token := range tokens {
+		n, err := rdb.Del(ctx, RefreshTokenKey(token)).Result()
+		if err != nil {
+			return revoked, fmt.Errorf("failed to revoke session: %w", err)
+		}
+		revoked += int(n)
+	}
+
+	if err := rdb.Del(ctx, setKey).Err(); err != nil {
+		return revoked, fmt.Errorf("failed to clear tenant sessions: %w", err)
+	}
+
+	return revoked, nil
+}
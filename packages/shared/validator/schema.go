@@ -0,0 +1,337 @@
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/comply360/shared/jurisdictions"
+)
+
+// Schema is the subset of JSON Schema used to describe dynamic forms: types,
+// properties, required fields, enums, string and number bounds, patterns, arrays
+// and formats. Formats are checked with this package's validators, so
+// "sa_id_number" or "vat_number" behave exactly like the struct tags of the same name.
+// Keywords outside the subset are ignored when validating.
+type Schema struct {
+	ID                   string             `json:"$id,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+
+	pattern *regexp.Regexp
+}
+
+// schemaFormats maps schema formats to the validator tags that check them
+var schemaFormats = map[string]string{
+	"email":                       "email",
+	"uri":                         "url",
+	"date":                        "datetime=2006-01-02",
+	"phone":                       "phone",
+	"country_code":                "country_code",
+	"currency":                    "currency",
+	"sa_id_number":                "sa_id_number",
+	"vat_number":                  "vat_number",
+	"company_registration_number": "company_registration_number",
+}
+
+// Each jurisdiction's number formats, such as zw_id_number, check with the tags
+// of the same name
+func init() {
+	for _, j := range jurisdictions.All() {
+		for tag := range jurisdictionTags(j) {
+			schemaFormats[tag] = tag
+		}
+	}
+}
+
+// schemaTypes are the JSON Schema types a Schema may declare
+var schemaTypes = map[string]bool{
+	"":        true,
+	"object":  true,
+	"array":   true,
+	"string":  true,
+	"number":  true,
+	"integer": true,
+	"boolean": true,
+}
+
+// ParseSchema decodes a schema and checks that its types, formats and patterns
+// are usable
+func ParseSchema(data []byte) (*Schema, error) {
+	var schema Schema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	if err := schema.compile("$"); err != nil {
+		return nil, err
+	}
+	return &schema, nil
+}
+
+// compile checks a schema and its subschemas and compiles patterns
+func (s *Schema) compile(path string) error {
+	if !schemaTypes[s.Type] {
+		return fmt.Errorf("invalid schema: %s has unknown type %q", path, s.Type)
+	}
+	if s.Format != "" {
+		if _, ok := schemaFormats[s.Format]; !ok {
+			return fmt.Errorf("invalid schema: %s has unknown format %q", path, s.Format)
+		}
+	}
+	if s.Pattern != "" {
+		pattern, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid schema: %s has an invalid pattern: %w", path, err)
+		}
+		s.pattern = pattern
+	}
+
+	for name, property := range s.Properties {
+		if property == nil {
+			return fmt.Errorf("invalid schema: %s.%s is empty", path, name)
+		}
+		if err := property.compile(path + "." + name); err != nil {
+			return err
+		}
+	}
+	if s.Items != nil {
+		if err := s.Items.compile(path + "[]"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateSchema validates decoded JSON against a schema and returns an error for
+// every failing field. Field names are paths such as "directors[0].id_number".
+func (v *Validator) ValidateSchema(schema *Schema, value interface{}) []ValidationError {
+	var errs []ValidationError
+	v.validateSchema(schema, value, "", true, &errs)
+	return errs
+}
+
+// ValidatePartialSchema validates like ValidateSchema but does not require any
+// field, so a form can be saved before it is complete
+func (v *Validator) ValidatePartialSchema(schema *Schema, value interface{}) []ValidationError {
+	var errs []ValidationError
+	v.validateSchema(schema, value, "", false, &errs)
+	return errs
+}
+
+func (v *Validator) validateSchema(schema *Schema, value interface{}, path string, complete bool, errs *[]ValidationError) {
+	field := path
+	if field == "" {
+		field = "value"
+	}
+	fail := func(tag, message string) {
+		*errs = append(*errs, ValidationError{Field: field, Message: message, Tag: tag, Value: value})
+	}
+
+	if !hasSchemaType(schema.Type, value) {
+		fail("type", fmt.Sprintf("%s must be of type %s", field, schema.Type))
+		return
+	}
+
+	if len(schema.Enum) > 0 && !inEnum(schema.Enum, value) {
+		fail("enum", fmt.Sprintf("%s must be one of: %s", field, enumList(schema.Enum)))
+		return
+	}
+
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		v.validateObject(schema, typed, path, complete, errs)
+	case []interface{}:
+		if complete && schema.MinItems != nil && len(typed) < *schema.MinItems {
+			fail("minItems", fmt.Sprintf("%s must have at least %d items", field, *schema.MinItems))
+		}
+		if schema.MaxItems != nil && len(typed) > *schema.MaxItems {
+			fail("maxItems", fmt.Sprintf("%s must have at most %d items", field, *schema.MaxItems))
+		}
+		if schema.Items != nil {
+			for i, item := range typed {
+				v.validateSchema(schema.Items, item, fmt.Sprintf("%s[%d]", path, i), complete, errs)
+			}
+		}
+	case string:
+		length := utf8.RuneCountInString(typed)
+		if schema.MinLength != nil && length < *schema.MinLength {
+			fail("minLength", tagMessage(field, "min", strconv.Itoa(*schema.MinLength)))
+			return
+		}
+		if schema.MaxLength != nil && length > *schema.MaxLength {
+			fail("maxLength", tagMessage(field, "max", strconv.Itoa(*schema.MaxLength)))
+			return
+		}
+		if schema.pattern != nil && !schema.pattern.MatchString(typed) {
+			fail("pattern", fmt.Sprintf("%s has an invalid format", field))
+			return
+		}
+		if schema.Format != "" {
+			if err := v.validate.Var(typed, schemaFormats[schema.Format]); err != nil {
+				fail(schema.Format, formatMessage(field, schema.Format))
+			}
+		}
+	default:
+		if number, ok := toFloat(value); ok {
+			if schema.Minimum != nil && number < *schema.Minimum {
+				fail("minimum", tagMessage(field, "gte", formatFloat(*schema.Minimum)))
+			}
+			if schema.Maximum != nil && number > *schema.Maximum {
+				fail("maximum", tagMessage(field, "lte", formatFloat(*schema.Maximum)))
+			}
+		}
+	}
+}
+
+// validateObject checks required fields, known properties and each property
+func (v *Validator) validateObject(schema *Schema, object map[string]interface{}, path string, complete bool, errs *[]ValidationError) {
+	join := func(name string) string {
+		if path == "" {
+			return name
+		}
+		return path + "." + name
+	}
+
+	if complete {
+		for _, name := range schema.Required {
+			if value, ok := object[name]; !ok || value == nil || value == "" {
+				field := join(name)
+				*errs = append(*errs, ValidationError{Field: field, Message: tagMessage(field, "required", ""), Tag: "required"})
+			}
+		}
+	}
+
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value := object[name]
+		property, known := schema.Properties[name]
+		if !known {
+			if schema.AdditionalProperties != nil && !*schema.AdditionalProperties {
+				field := join(name)
+				*errs = append(*errs, ValidationError{Field: field, Message: fmt.Sprintf("%s is not a known field", field), Tag: "additionalProperties"})
+			}
+			continue
+		}
+		// Missing values are reported by required; optional fields may be left empty
+		if value == nil || value == "" {
+			continue
+		}
+		v.validateSchema(property, value, join(name), complete, errs)
+	}
+}
+
+// formatMessage describes a value that does not match a schema format
+func formatMessage(field, format string) string {
+	switch format {
+	case "date":
+		return fmt.Sprintf("%s must be a date (YYYY-MM-DD)", field)
+	case "uri":
+		return tagMessage(field, "url", "")
+	default:
+		return tagMessage(field, format, "")
+	}
+}
+
+// hasSchemaType reports whether a decoded JSON value has a schema type
+func hasSchemaType(schemaType string, value interface{}) bool {
+	switch schemaType {
+	case "":
+		return true
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "number":
+		_, ok := toFloat(value)
+		return ok
+	case "integer":
+		number, ok := toFloat(value)
+		return ok && number == math.Trunc(number)
+	default:
+		return false
+	}
+}
+
+// toFloat reads a number decoded from JSON or built in Go
+func toFloat(value interface{}) (float64, bool) {
+	switch n := value.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// inEnum reports whether a value is one of the enum values
+func inEnum(enum []interface{}, value interface{}) bool {
+	number, isNumber := toFloat(value)
+	for _, allowed := range enum {
+		if isNumber {
+			if n, ok := toFloat(allowed); ok && n == number {
+				return true
+			}
+			continue
+		}
+		if reflect.DeepEqual(allowed, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// enumList formats enum values for messages
+func enumList(enum []interface{}) string {
+	list := ""
+	for i, value := range enum {
+		if i > 0 {
+			list += " "
+		}
+		list += fmt.Sprint(value)
+	}
+	return list
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
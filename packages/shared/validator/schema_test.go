@@ -0,0 +1,102 @@
+package validator
+
+import (
+	"testing"
+
+	testhelpers "github.com/comply360/shared/testing"
+)
+
+const directorsSchema = `{
+	"type": "object",
+	"required": ["company_name", "directors"],
+	"additionalProperties": false,
+	"properties": {
+		"company_name": {"type": "string", "minLength": 2},
+		"vat_number": {"type": "string", "format": "vat_number"},
+		"financial_year_end": {"type": "integer", "minimum": 1, "maximum": 12},
+		"directors": {
+			"type": "array",
+			"minItems": 1,
+			"items": {
+				"type": "object",
+				"required": ["full_name", "id_number"],
+				"properties": {
+					"full_name": {"type": "string"},
+					"id_number": {"type": "string", "format": "sa_id_number"},
+					"role": {"type": "string", "enum": ["director", "secretary"]}
+				}
+			}
+		}
+	}
+}`
+
+func fieldErrors(errs []ValidationError) map[string]string {
+	fields := map[string]string{}
+	for _, err := range errs {
+		fields[err.Field] = err.Tag
+	}
+	return fields
+}
+
+func TestValidateSchema_AcceptsValidData(t *testing.T) {
+	schema, err := ParseSchema([]byte(directorsSchema))
+	testhelpers.AssertNoError(t, err)
+
+	errs := New().ValidateSchema(schema, map[string]interface{}{
+		"company_name":       "Acme Trading",
+		"vat_number":         "4123456789",
+		"financial_year_end": float64(2),
+		"directors": []interface{}{
+			map[string]interface{}{"full_name": "Thandi Nkosi", "id_number": "8001015009087", "role": "director"},
+		},
+	})
+	testhelpers.AssertEqual(t, 0, len(errs), "Valid form data should pass")
+}
+
+func TestValidateSchema_ReportsFieldErrors(t *testing.T) {
+	schema, err := ParseSchema([]byte(directorsSchema))
+	testhelpers.AssertNoError(t, err)
+
+	errs := New().ValidateSchema(schema, map[string]interface{}{
+		"vat_number":         "5123456789",
+		"financial_year_end": 2.5,
+		"nickname":           "acme",
+		"directors": []interface{}{
+			map[string]interface{}{"full_name": "Thandi Nkosi", "id_number": "8001015009088", "role": "ceo"},
+		},
+	})
+
+	expected := map[string]string{
+		"company_name":           "required",
+		"vat_number":             "vat_number",
+		"financial_year_end":     "type",
+		"nickname":               "additionalProperties",
+		"directors[0].id_number": "sa_id_number",
+		"directors[0].role":      "enum",
+	}
+	fields := fieldErrors(errs)
+	testhelpers.AssertEqual(t, len(expected), len(fields))
+	for field, tag := range expected {
+		testhelpers.AssertEqual(t, tag, fields[field], field)
+	}
+}
+
+func TestParseSchema_RejectsUnknownFormat(t *testing.T) {
+	_, err := ParseSchema([]byte(`{"type": "string", "format": "passport"}`))
+	testhelpers.AssertError(t, err, "Unknown formats should be rejected")
+
+	_, err = ParseSchema([]byte(`{"type": "string", "pattern": "("}`))
+	testhelpers.AssertError(t, err, "Invalid patterns should be rejected")
+}
+
+func TestValidatePartialSchema_SkipsRequiredFields(t *testing.T) {
+	schema, err := ParseSchema([]byte(directorsSchema))
+	testhelpers.AssertNoError(t, err)
+
+	errs := New().ValidatePartialSchema(schema, map[string]interface{}{
+		"directors": []interface{}{map[string]interface{}{"id_number": "8001015009088"}},
+	})
+	fields := fieldErrors(errs)
+	testhelpers.AssertEqual(t, 1, len(fields), "Only the invalid ID number should be reported")
+	testhelpers.AssertEqual(t, "sa_id_number", fields["directors[0].id_number"])
+}
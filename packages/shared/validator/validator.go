@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/comply360/shared/errors"
+	"github.com/comply360/shared/jurisdictions"
 	"github.com/go-playground/validator/v10"
 )
 
@@ -51,6 +52,11 @@ func New() *Validator {
 	v.RegisterValidation("sa_id_number", validateSAIDNumber)
 	v.RegisterValidation("company_registration_number", validateCompanyRegistrationNumber)
 	v.RegisterValidation("vat_number", validateVATNumber)
+	for _, j := range jurisdictions.All() {
+		for tag, check := range jurisdictionTags(j) {
+			v.RegisterValidation(tag, check)
+		}
+	}
 	v.RegisterValidation("strong_password", validateStrongPassword)
 
 	return &Validator{validate: v}
@@ -83,70 +89,102 @@ func (v *Validator) ValidateVar(field interface{}, tag string) error {
 
 // getErrorMessage returns a human-readable error message
 func getErrorMessage(fe validator.FieldError) string {
-	switch fe.Tag() {
+	return tagMessage(fe.Field(), fe.Tag(), fe.Param())
+}
+
+// tagMessage describes a failed validation tag for a field
+func tagMessage(field, tag, param string) string {
+	switch tag {
 	case "required":
-		return fmt.Sprintf("%s is required", fe.Field())
+		return fmt.Sprintf("%s is required", field)
 	case "email":
-		return fmt.Sprintf("%s must be a valid email address", fe.Field())
+		return fmt.Sprintf("%s must be a valid email address", field)
 	case "min":
-		return fmt.Sprintf("%s must be at least %s characters", fe.Field(), fe.Param())
+		return fmt.Sprintf("%s must be at least %s characters", field, param)
 	case "max":
-		return fmt.Sprintf("%s must be at most %s characters", fe.Field(), fe.Param())
+		return fmt.Sprintf("%s must be at most %s characters", field, param)
 	case "len":
-		return fmt.Sprintf("%s must be exactly %s characters", fe.Field(), fe.Param())
+		return fmt.Sprintf("%s must be exactly %s characters", field, param)
 	case "uuid":
-		return fmt.Sprintf("%s must be a valid UUID", fe.Field())
+		return fmt.Sprintf("%s must be a valid UUID", field)
 	case "url":
-		return fmt.Sprintf("%s must be a valid URL", fe.Field())
+		return fmt.Sprintf("%s must be a valid URL", field)
 	case "phone":
-		return fmt.Sprintf("%s must be a valid phone number", fe.Field())
+		return fmt.Sprintf("%s must be a valid phone number", field)
 	case "subdomain":
-		return fmt.Sprintf("%s must be a valid subdomain (lowercase alphanumeric and hyphens only)", fe.Field())
+		return fmt.Sprintf("%s must be a valid subdomain (lowercase alphanumeric and hyphens only)", field)
 	case "currency":
-		return fmt.Sprintf("%s must be a valid currency code (ZAR, USD, or ZWL)", fe.Field())
+		return fmt.Sprintf("%s must be a valid currency code (ZAR, USD, or ZWL)", field)
 	case "country_code":
-		return fmt.Sprintf("%s must be a valid ISO 3166-1 alpha-2 country code", fe.Field())
+		return fmt.Sprintf("%s must be a valid ISO 3166-1 alpha-2 country code", field)
 	case "jurisdiction":
-		return fmt.Sprintf("%s must be a valid jurisdiction (ZA or ZW)", fe.Field())
+		codes := []string{}
+		for _, j := range jurisdictions.All() {
+			codes = append(codes, j.Code())
+		}
+		return fmt.Sprintf("%s must be a valid jurisdiction (%s)", field, strings.Join(codes, " or "))
 	case "registration_type":
-		return fmt.Sprintf("%s must be a valid registration type", fe.Field())
+		return fmt.Sprintf("%s must be a valid registration type", field)
 	case "user_role":
-		return fmt.Sprintf("%s must be a valid user role", fe.Field())
+		return fmt.Sprintf("%s must be a valid user role", field)
 	case "user_status":
-		return fmt.Sprintf("%s must be a valid user status", fe.Field())
+		return fmt.Sprintf("%s must be a valid user status", field)
 	case "commission_rate":
-		return fmt.Sprintf("%s must be between 0 and 100", fe.Field())
+		return fmt.Sprintf("%s must be between 0 and 100", field)
 	case "document_type":
-		return fmt.Sprintf("%s must be a valid document type", fe.Field())
+		return fmt.Sprintf("%s must be a valid document type", field)
 	case "registration_status":
-		return fmt.Sprintf("%s must be a valid registration status", fe.Field())
+		return fmt.Sprintf("%s must be a valid registration status", field)
 	case "commission_status":
-		return fmt.Sprintf("%s must be a valid commission status", fe.Field())
+		return fmt.Sprintf("%s must be a valid commission status", field)
 	case "sa_id_number":
-		return fmt.Sprintf("%s must be a valid South African ID number (13 digits)", fe.Field())
+		return fmt.Sprintf("%s must be a valid South African ID number (13 digits)", field)
 	case "company_registration_number":
-		return fmt.Sprintf("%s must be a valid company registration number", fe.Field())
+		return fmt.Sprintf("%s must be a valid company registration number", field)
 	case "vat_number":
-		return fmt.Sprintf("%s must be a valid VAT number (10 digits starting with 4)", fe.Field())
+		return fmt.Sprintf("%s must be a valid VAT number (10 digits starting with 4)", field)
 	case "strong_password":
-		return fmt.Sprintf("%s must be at least 8 characters with uppercase, lowercase, numbers, and symbols", fe.Field())
+		return fmt.Sprintf("%s must be at least 8 characters with uppercase, lowercase, numbers, and symbols", field)
 	case "oneof":
-		return fmt.Sprintf("%s must be one of: %s", fe.Field(), fe.Param())
+		return fmt.Sprintf("%s must be one of: %s", field, param)
 	case "gte":
-		return fmt.Sprintf("%s must be greater than or equal to %s", fe.Field(), fe.Param())
+		return fmt.Sprintf("%s must be greater than or equal to %s", field, param)
 	case "lte":
-		return fmt.Sprintf("%s must be less than or equal to %s", fe.Field(), fe.Param())
+		return fmt.Sprintf("%s must be less than or equal to %s", field, param)
 	case "gt":
-		return fmt.Sprintf("%s must be greater than %s", fe.Field(), fe.Param())
+		return fmt.Sprintf("%s must be greater than %s", field, param)
 	case "lt":
-		return fmt.Sprintf("%s must be less than %s", fe.Field(), fe.Param())
+		return fmt.Sprintf("%s must be less than %s", field, param)
 	case "alphanum":
-		return fmt.Sprintf("%s must contain only letters and numbers", fe.Field())
+		return fmt.Sprintf("%s must contain only letters and numbers", field)
 	default:
-		return fmt.Sprintf("%s is invalid", fe.Field())
+		if j, number, ok := jurisdictionTag(tag); ok {
+			return fmt.Sprintf("%s must be a valid %s %s", field, j.Name(), number)
+		}
+		return fmt.Sprintf("%s is invalid", field)
 	}
 }
 
+// jurisdictionNumbers names the numbers of the tags jurisdictionTags returns
+var jurisdictionNumbers = map[string]string{
+	"id_number":                   "ID number",
+	"company_registration_number": "company registration number",
+	"tax_number":                  "tax number",
+	"vat_number":                  "VAT number",
+}
+
+// jurisdictionTag reads a tag such as zw_id_number as a jurisdiction and the
+// number it checks
+func jurisdictionTag(tag string) (jurisdictions.Jurisdiction, string, bool) {
+	parts := strings.SplitN(tag, "_", 2)
+	if len(parts) != 2 {
+		return nil, "", false
+	}
+	j, ok := jurisdictions.Get(parts[0])
+	number, known := jurisdictionNumbers[parts[1]]
+	return j, number, ok && known
+}
+
 // Custom validation functions
 
 func validatePhone(fl validator.FieldLevel) bool {
@@ -197,21 +235,17 @@ func validateCountryCode(fl validator.FieldLevel) bool {
 }
 
 func validateJurisdiction(fl validator.FieldLevel) bool {
-	jurisdiction := fl.Field().String()
-	validJurisdictions := []string{"ZA", "ZW"}
-	for _, valid := range validJurisdictions {
-		if jurisdiction == valid {
-			return true
-		}
-	}
-	return false
+	code := fl.Field().String()
+	_, ok := jurisdictions.Get(code)
+	return ok && code == strings.ToUpper(code)
 }
 
+// validateRegistrationType accepts a registration type offered in any
+// jurisdiction
 func validateRegistrationType(fl validator.FieldLevel) bool {
 	regType := fl.Field().String()
-	validTypes := []string{"pty_ltd", "close_corporation", "business_name", "vat_registration"}
-	for _, valid := range validTypes {
-		if regType == valid {
+	for _, j := range jurisdictions.All() {
+		if jurisdictions.Offers(j, regType) {
 			return true
 		}
 	}
@@ -262,7 +296,7 @@ func validateDocumentType(fl validator.FieldLevel) bool {
 
 func validateRegistrationStatus(fl validator.FieldLevel) bool {
 	status := fl.Field().String()
-	validStatuses := []string{"draft", "submitted", "in_review", "approved", "rejected", "cancelled"}
+	validStatuses := []string{"draft", "submitted", "in_review", "approved", "rejected", "cancelled", "completed"}
 	for _, valid := range validStatuses {
 		if status == valid {
 			return true
@@ -284,84 +318,34 @@ func validateCommissionStatus(fl validator.FieldLevel) bool {
 
 // validateSAIDNumber validates South African ID numbers using the Luhn algorithm
 func validateSAIDNumber(fl validator.FieldLevel) bool {
-	idNumber := fl.Field().String()
-
-	// SA ID must be exactly 13 digits
-	if len(idNumber) != 13 {
-		return false
-	}
-
-	// Check if all characters are digits
-	for _, char := range idNumber {
-		if char < '0' || char > '9' {
-			return false
-		}
-	}
-
-	// Validate using Luhn algorithm
-	sum := 0
-	for i := 0; i < 12; i++ {
-		digit := int(idNumber[i] - '0')
-		if i%2 == 0 {
-			sum += digit
-		} else {
-			doubled := digit * 2
-			if doubled > 9 {
-				doubled -= 9
-			}
-			sum += doubled
-		}
-	}
-
-	checkDigit := (10 - (sum % 10)) % 10
-	return checkDigit == int(idNumber[12]-'0')
+	return jurisdictions.SouthAfrica{}.ValidateIDNumber(fl.Field().String()) == nil
 }
 
 // validateCompanyRegistrationNumber validates South African company registration numbers
 func validateCompanyRegistrationNumber(fl validator.FieldLevel) bool {
-	regNumber := fl.Field().String()
-
-	// Format: YYYY/NNNNNN/NN (e.g., 2020/123456/07)
-	if len(regNumber) < 10 || len(regNumber) > 15 {
-		return false
-	}
-
-	// Basic format check - should contain numbers and slashes
-	hasSlash := false
-	hasNumber := false
-	for _, char := range regNumber {
-		if char == '/' {
-			hasSlash = true
-		} else if char >= '0' && char <= '9' {
-			hasNumber = true
-		}
-	}
-
-	return hasSlash && hasNumber
+	return jurisdictions.SouthAfrica{}.ValidateCompanyNumber(fl.Field().String()) == nil
 }
 
 // validateVATNumber validates South African VAT numbers
 func validateVATNumber(fl validator.FieldLevel) bool {
-	vatNumber := fl.Field().String()
-
-	// SA VAT numbers are 10 digits starting with 4
-	if len(vatNumber) != 10 {
-		return false
-	}
-
-	// Must start with 4
-	if vatNumber[0] != '4' {
-		return false
-	}
+	return jurisdictions.SouthAfrica{}.ValidateVATNumber(fl.Field().String()) == nil
+}
 
-	// All characters must be digits
-	for _, char := range vatNumber {
-		if char < '0' || char > '9' {
-			return false
+// jurisdictionTags returns a jurisdiction's number validators as tags named
+// for its country code, such as zw_id_number and zw_vat_number
+func jurisdictionTags(j jurisdictions.Jurisdiction) map[string]validator.Func {
+	check := func(validate func(string) error) validator.Func {
+		return func(fl validator.FieldLevel) bool {
+			return validate(fl.Field().String()) == nil
 		}
 	}
-
-	return true
+	prefix := strings.ToLower(j.Code()) + "_"
+	return map[string]validator.Func{
+		prefix + "id_number":                   check(j.ValidateIDNumber),
+		prefix + "company_registration_number": check(j.ValidateCompanyNumber),
+		prefix + "tax_number":                  check(j.ValidateTaxNumber),
+		prefix + "vat_number":                  check(j.ValidateVATNumber),
+	}
 }
 
 // validateStrongPassword validates password strength